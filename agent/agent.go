@@ -0,0 +1,299 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package agent implements a runtime measurement agent. The agent records
+// events in a CEL file and extends them into measurement registers, so the
+// file can later be replayed against the registers by a verifier.
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/google/go-eventlog/cel"
+)
+
+// Ordering determines whether a measurement register is extended before or
+// after the event is appended to the CEL file.
+type Ordering int
+
+// Orderings. They differ in what is left behind if the agent crashes between
+// the two steps.
+const (
+	// ExtendThenAppend extends the measurement register before appending the
+	// record to the CEL file. A crash between the two steps leaves a
+	// measurement that is missing from the log, so replay fails from then
+	// on. No event is ever logged without being measured.
+	ExtendThenAppend Ordering = iota
+	// AppendThenExtend durably appends the record to the CEL file before
+	// extending the register. A crash between the two steps leaves a record
+	// at the end of the log that was never measured, which identifies the
+	// interrupted event. If no register could be extended, the record is
+	// removed again.
+	AppendThenExtend
+)
+
+// Opts gives options for creating an Agent.
+type Opts struct {
+	// Path is the CEL file. It is created if it does not exist. Records
+	// already in the file are kept, and a partially written record at its end
+	// is discarded.
+	Path string
+	// MRType is the type of measurement register recorded in the CEL, either
+	// cel.PCRType or cel.CCMRType.
+	MRType cel.MRType
+	// Banks are the hash algorithms each event is measured with.
+	Banks []crypto.Hash
+	// Extender extends the measurement registers.
+	Extender cel.MRExtender
+	// Ordering determines whether registers are extended before or after the
+	// event is logged. Defaults to ExtendThenAppend.
+	Ordering Ordering
+}
+
+// Agent measures events. Events are measured one at a time, so the order of
+// records in the CEL file always matches the order of the extensions.
+type Agent struct {
+	opts Opts
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	recNum uint64
+}
+
+// Event is a request to measure Content into the register at MRIndex.
+type Event struct {
+	Content cel.Content
+	MRIndex int
+	// Done, if set, receives the result of measuring the event.
+	Done chan<- error
+}
+
+// New returns an Agent appending to the CEL file at opts.Path.
+func New(opts Opts) (*Agent, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no CEL file path given")
+	}
+	if opts.MRType != cel.PCRType && opts.MRType != cel.CCMRType {
+		return nil, fmt.Errorf("unsupported measurement register type: %d", opts.MRType)
+	}
+	if len(opts.Banks) == 0 {
+		return nil, errors.New("no hash algorithms given")
+	}
+	if opts.Extender == nil {
+		return nil, errors.New("no measurement register extender given")
+	}
+	if opts.Ordering != ExtendThenAppend && opts.Ordering != AppendThenExtend {
+		return nil, fmt.Errorf("unknown ordering: %d", opts.Ordering)
+	}
+
+	file, err := os.OpenFile(opts.Path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	a := &Agent{opts: opts, file: file}
+	if err := a.recover(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read CEL file %v: %v", opts.Path, err)
+	}
+	return a, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// recover reads the existing records in the CEL file, and truncates a
+// partially written record at its end.
+func (a *Agent) recover() error {
+	cr := &countingReader{r: a.file}
+	r := bufio.NewReader(cr)
+	var end int64
+	for {
+		rec, err := cel.DecodeRecord(r, cel.DecodeOpts{})
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			if err := a.file.Truncate(end); err != nil {
+				return fmt.Errorf("failed to discard partial record %d: %v", a.recNum, err)
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("bad record %d: %v", a.recNum, err)
+		}
+		if rec.IndexType != a.opts.MRType {
+			return fmt.Errorf("bad record %d: got MR type %v, expected %v", rec.RecNum, rec.IndexType, a.opts.MRType)
+		}
+		a.recNum++
+		end = cr.n - int64(r.Buffered())
+	}
+	a.size = end
+	return nil
+}
+
+// Measure measures content into the register at mrIndex and records it in
+// the CEL file.
+func (a *Agent) Measure(content cel.Content, mrIndex int) error {
+	if mrIndex < 0 || mrIndex > math.MaxUint8 {
+		return fmt.Errorf("invalid measurement register index %d", mrIndex)
+	}
+	digests := make(map[crypto.Hash][]byte)
+	for _, bank := range a.opts.Banks {
+		digest, err := content.GenerateDigest(bank)
+		if err != nil {
+			return fmt.Errorf("failed to compute %v digest: %v", bank, err)
+		}
+		digests[bank] = digest
+	}
+	contentTLV, err := content.TLV()
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return errors.New("agent is closed")
+	}
+
+	rec := cel.Record{
+		RecNum:    a.recNum,
+		Index:     uint8(mrIndex),
+		IndexType: a.opts.MRType,
+		Digests:   digests,
+		Content:   contentTLV,
+	}
+	var buf bytes.Buffer
+	if err := rec.EncodeCELR(&buf); err != nil {
+		return err
+	}
+
+	switch a.opts.Ordering {
+	case ExtendThenAppend:
+		if _, err := a.extend(mrIndex, digests); err != nil {
+			return err
+		}
+		if err := a.append(buf.Bytes()); err != nil {
+			return fmt.Errorf("extended MR%d but failed to log record %d: %v", mrIndex, rec.RecNum, err)
+		}
+	case AppendThenExtend:
+		if err := a.append(buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to log record %d: %v", rec.RecNum, err)
+		}
+		extended, err := a.extend(mrIndex, digests)
+		if err != nil && extended == 0 {
+			if rollbackErr := a.truncate(a.size - int64(buf.Len())); rollbackErr != nil {
+				return fmt.Errorf("logged record %d but failed to extend MR%d (%v) or remove the record (%v)", rec.RecNum, mrIndex, err, rollbackErr)
+			}
+			return err
+		}
+		if err != nil {
+			a.recNum++
+			return fmt.Errorf("logged record %d but failed to extend MR%d: %v", rec.RecNum, mrIndex, err)
+		}
+	}
+	a.recNum++
+	return nil
+}
+
+// extend extends the digests in the order of opts.Banks, and returns the
+// number of banks extended.
+func (a *Agent) extend(mrIndex int, digests map[crypto.Hash][]byte) (int, error) {
+	for i, bank := range a.opts.Banks {
+		if err := a.opts.Extender(bank, mrIndex, digests[bank]); err != nil {
+			return i, fmt.Errorf("failed to extend event to MR%d on bank %v: %v", mrIndex, bank, err)
+		}
+	}
+	return len(a.opts.Banks), nil
+}
+
+// append durably writes an encoded record to the end of the CEL file. On
+// failure, a partially written record is removed.
+func (a *Agent) append(record []byte) error {
+	if _, err := a.file.Write(record); err != nil {
+		a.truncate(a.size)
+		return err
+	}
+	if err := a.file.Sync(); err != nil {
+		a.truncate(a.size)
+		return err
+	}
+	a.size += int64(len(record))
+	return nil
+}
+
+func (a *Agent) truncate(size int64) error {
+	if err := a.file.Truncate(size); err != nil {
+		return err
+	}
+	if err := a.file.Sync(); err != nil {
+		return err
+	}
+	a.size = size
+	return nil
+}
+
+// Run measures the events received on events until the channel is closed or
+// ctx is done. If an event has no Done channel and fails to be measured, Run
+// stops and returns the error.
+func (a *Agent) Run(ctx context.Context, events <-chan Event) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			err := a.Measure(event.Content, event.MRIndex)
+			if event.Done != nil {
+				event.Done <- err
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Close closes the CEL file. Events can no longer be measured afterwards.
+func (a *Agent) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Close()
+	a.file = nil
+	return err
+}
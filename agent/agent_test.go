@@ -0,0 +1,203 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package agent
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-eventlog/cel"
+	"github.com/google/go-eventlog/register"
+)
+
+var measuredHashes = []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA512}
+
+func fakeExtender(rot register.FakeROT) cel.MRExtender {
+	return func(hash crypto.Hash, mrIndex int, digest []byte) error {
+		return rot.ExtendMR(register.FakeMR{Index: mrIndex, Digest: digest, DigestAlg: hash})
+	}
+}
+
+func newAgent(t *testing.T, path string, rot register.FakeROT, ordering Ordering) *Agent {
+	t.Helper()
+	a, err := New(Opts{
+		Path:     path,
+		MRType:   cel.PCRType,
+		Banks:    measuredHashes,
+		Extender: fakeExtender(rot),
+		Ordering: ordering,
+	})
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	t.Cleanup(func() { a.Close() })
+	return a
+}
+
+// replayFile decodes the CEL file and replays it against the fake RoT.
+func replayFile(t *testing.T, path string, rot register.FakeROT, mrs []int) cel.CEL {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	c, err := cel.DecodeFrom(f, cel.DecodeOpts{})
+	if err != nil {
+		t.Fatalf("DecodeFrom(): %v", err)
+	}
+	for _, hash := range measuredHashes {
+		bank, err := rot.ReadMRs(hash, mrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Replay(bank); err != nil {
+			t.Errorf("Replay(%v): %v", hash, err)
+		}
+	}
+	return c
+}
+
+func TestMeasureAndReopen(t *testing.T) {
+	for _, ordering := range []Ordering{ExtendThenAppend, AppendThenExtend} {
+		rot, err := register.CreateFakeRot(measuredHashes, 24)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "cel")
+		a := newAgent(t, path, rot, ordering)
+		if err := a.Measure(cel.FakeTlv{EventType: cel.FakeEvent1, EventContent: []byte("first")}, 15); err != nil {
+			t.Fatalf("Measure(): %v", err)
+		}
+		a.Close()
+
+		// Simulate a crash in the middle of writing a record.
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte{0, 0, 0, 0, 8, 0})
+		f.Close()
+
+		a = newAgent(t, path, rot, ordering)
+		if err := a.Measure(cel.FakeTlv{EventType: cel.FakeEvent2, EventContent: []byte("second")}, 16); err != nil {
+			t.Fatalf("Measure(): %v", err)
+		}
+		c := replayFile(t, path, rot, []int{15, 16})
+		for i, rec := range c.Records() {
+			if rec.RecNum != uint64(i) {
+				t.Errorf("ordering %v: got record number %d at position %d", ordering, rec.RecNum, i)
+			}
+		}
+		if len(c.Records()) != 2 {
+			t.Errorf("ordering %v: got %d records, want 2", ordering, len(c.Records()))
+		}
+	}
+}
+
+func TestAppendThenExtendRollsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cel")
+	a, err := New(Opts{
+		Path:     path,
+		MRType:   cel.CCMRType,
+		Banks:    []crypto.Hash{crypto.SHA384},
+		Extender: func(crypto.Hash, int, []byte) error { return errors.New("extend failed") },
+		Ordering: AppendThenExtend,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	if err := a.Measure(cel.FakeTlv{EventType: cel.FakeEvent1, EventContent: []byte("event")}, 2); err == nil {
+		t.Fatal("Measure(): got nil, want error")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("got CEL file of %d bytes, want the unmeasured record removed", info.Size())
+	}
+}
+
+func TestNewFailsOnMismatchedMRType(t *testing.T) {
+	rot, err := register.CreateFakeRot(measuredHashes, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cel")
+	a := newAgent(t, path, rot, ExtendThenAppend)
+	if err := a.Measure(cel.FakeTlv{EventType: cel.FakeEvent1, EventContent: []byte("event")}, 1); err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+	if _, err := New(Opts{Path: path, MRType: cel.CCMRType, Banks: measuredHashes, Extender: fakeExtender(rot)}); err == nil {
+		t.Error("New(PCR CEL file, CCMRType): got nil, want error")
+	}
+}
+
+func TestRunAndServe(t *testing.T) {
+	rot, err := register.CreateFakeRot(measuredHashes, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cel")
+	a := newAgent(t, path, rot, ExtendThenAppend)
+
+	events := make(chan Event)
+	runErr := make(chan error)
+	go func() { runErr <- a.Run(context.Background(), events) }()
+	done := make(chan error)
+	events <- Event{Content: cel.FakeTlv{EventType: cel.FakeEvent1, EventContent: []byte("channel")}, MRIndex: 14, Done: done}
+	if err := <-done; err != nil {
+		t.Errorf("Run(): event failed: %v", err)
+	}
+	close(events)
+	if err := <-runErr; err != nil {
+		t.Errorf("Run(): %v", err)
+	}
+
+	l, err := net.Listen("unix", filepath.Join(t.TempDir(), "agent.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	serveErr := make(chan error)
+	go func() { serveErr <- a.Serve(l) }()
+	conn, err := net.Dial("unix", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SendEvent(conn, cel.FakeTlv{EventType: cel.FakeEvent2, EventContent: []byte("socket")}, 14); err != nil {
+		t.Errorf("SendEvent(): %v", err)
+	}
+	if err := SendEvent(conn, cel.FakeTlv{EventType: cel.FakeEvent2, EventContent: []byte("bad index")}, 200); err == nil {
+		t.Error("SendEvent(index not in RoT): got nil, want error")
+	}
+	conn.Close()
+	l.Close()
+	if err := <-serveErr; err != nil {
+		t.Errorf("Serve(): %v", err)
+	}
+
+	c := replayFile(t, path, rot, []int{14})
+	if len(c.Records()) != 2 {
+		t.Errorf("got %d records, want 2", len(c.Records()))
+	}
+}
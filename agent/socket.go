@@ -0,0 +1,121 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package agent
+
+import (
+	"bufio"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/google/go-eventlog/cel"
+)
+
+// The wire protocol used by Serve and SendEvent. A request is a single byte
+// holding the measurement register index, followed by the content TLV. The
+// response is a TLV of type responseOK, or of type responseError with the
+// error message as its value.
+const (
+	responseOK    uint8 = 0
+	responseError uint8 = 1
+)
+
+// tlvContent is event content received as a raw TLV. Its digest is the hash of
+// the marshaled TLV, as for cel.FakeTlv.
+type tlvContent cel.TLV
+
+func (c tlvContent) TLV() (cel.TLV, error) {
+	return cel.TLV(c), nil
+}
+
+func (c tlvContent) GenerateDigest(hash crypto.Hash) ([]byte, error) {
+	b, err := cel.TLV(c).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	hasher := hash.New()
+	hasher.Write(b)
+	return hasher.Sum(nil), nil
+}
+
+// Serve accepts connections on l, typically a unix socket, and measures the
+// events sent with SendEvent. Events received over a connection are measured
+// in order. The content digest is the hash of the marshaled content TLV. Serve
+// returns nil once l is closed.
+func (a *Agent) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		go a.serveConn(conn)
+	}
+}
+
+func (a *Agent) serveConn(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		mrIndex, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+		content, err := cel.ReadTLV(r, cel.DecodeOpts{})
+		if err != nil {
+			return
+		}
+		resp := cel.TLV{Type: responseOK}
+		if err := a.Measure(tlvContent(content), int(mrIndex)); err != nil {
+			resp = cel.TLV{Type: responseError, Value: []byte(err.Error())}
+		}
+		b, _ := resp.MarshalBinary()
+		if _, err := conn.Write(b); err != nil {
+			return
+		}
+	}
+}
+
+// SendEvent asks the agent serving on conn to measure content into the
+// register at mrIndex, and waits for the result.
+func SendEvent(conn io.ReadWriter, content cel.Content, mrIndex uint8) error {
+	contentTLV, err := content.TLV()
+	if err != nil {
+		return err
+	}
+	b, err := contentTLV.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(append([]byte{mrIndex}, b...)); err != nil {
+		return fmt.Errorf("failed to send event: %v", err)
+	}
+	resp, err := cel.ReadTLV(conn, cel.DecodeOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+	switch resp.Type {
+	case responseOK:
+		return nil
+	case responseError:
+		return fmt.Errorf("agent failed to measure event: %s", resp.Value)
+	default:
+		return fmt.Errorf("unexpected response type %d", resp.Type)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package archive retains the event logs of successive boots of a machine in a
+// LogArchive, and compares them across boots for audit trails.
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxArchiveLen is the maximum accepted byte length of a serialized
+	// LogArchive. This value should be larger than any reasonable value.
+	maxArchiveLen = 256 << 20
+	// maxDiffEvents is the maximum number of events of each event log
+	// compared by Diff.
+	maxDiffEvents = 1 << 14
+	// maxDiffCells is the maximum size of the table Diff uses to compare the
+	// differing events of a register, after skipping their common prefix and
+	// suffix.
+	maxDiffCells = 1 << 22
+)
+
+// Save writes the serialized LogArchive to w.
+func Save(w io.Writer, archive *pb.LogArchive) error {
+	out, err := proto.Marshal(archive)
+	if err != nil {
+		return fmt.Errorf("failed to marshal LogArchive: %v", err)
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Load reads a serialized LogArchive from r.
+func Load(r io.Reader) (*pb.LogArchive, error) {
+	in, err := io.ReadAll(io.LimitReader(r, maxArchiveLen+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(in) > maxArchiveLen {
+		return nil, fmt.Errorf("LogArchive too long: > %d bytes", maxArchiveLen)
+	}
+	archive := &pb.LogArchive{}
+	if err := proto.Unmarshal(in, archive); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal LogArchive: %v", err)
+	}
+	return archive, nil
+}
+
+// Append adds a boot to the archive. The boot counter must be greater than
+// that of the last archived boot, and the boot must have an event log.
+func Append(archive *pb.LogArchive, boot *pb.ArchivedBoot) error {
+	if len(boot.GetBundle().GetRawEventLog()) == 0 {
+		return fmt.Errorf("boot %d has no event log", boot.GetBootCounter())
+	}
+	if n := len(archive.GetBoots()); n > 0 {
+		last := archive.GetBoots()[n-1]
+		if boot.GetBootCounter() <= last.GetBootCounter() {
+			return fmt.Errorf("boot counter %d does not follow the last archived boot %d", boot.GetBootCounter(), last.GetBootCounter())
+		}
+	}
+	archive.Boots = append(archive.Boots, boot)
+	return nil
+}
+
+// Iterate calls fn with each archived boot and the boot before it, in order.
+// prev is nil for the first boot. It stops at the first error from fn, and
+// returns it.
+func Iterate(archive *pb.LogArchive, fn func(prev, boot *pb.ArchivedBoot) error) error {
+	var prev *pb.ArchivedBoot
+	for _, boot := range archive.GetBoots() {
+		if err := fn(prev, boot); err != nil {
+			return err
+		}
+		prev = boot
+	}
+	return nil
+}
+
+// ChangeKind describes how an event differs between two boots.
+type ChangeKind int
+
+// Supported change kinds.
+const (
+	// Added events are only in the later boot.
+	Added ChangeKind = iota
+	// Removed events are only in the earlier boot.
+	Removed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// EventChange is an event that differs between two boots.
+type EventChange struct {
+	Kind ChangeKind
+	// Event is the added event from the later boot, or the removed event from
+	// the earlier boot.
+	Event tcg.Event
+}
+
+// Diff returns the events that differ between the event logs of two boots,
+// ordered by register index, then by log order. Events are compared by type
+// and digest, using the strongest hash algorithm both logs have digests for.
+// A changed event is reported as a removed event and an added event.
+//
+// The event logs are not replayed, so boots should be verified, e.g., with
+// bundle.Verify, before they are archived. As archives may be untrusted, Diff
+// fails for event logs of more than 16384 events, or registers whose events
+// differ too much to compare.
+func Diff(older, newer *pb.ArchivedBoot) ([]EventChange, error) {
+	olderLog, err := parseLog(older)
+	if err != nil {
+		return nil, fmt.Errorf("boot %d: %v", older.GetBootCounter(), err)
+	}
+	newerLog, err := parseLog(newer)
+	if err != nil {
+		return nil, fmt.Errorf("boot %d: %v", newer.GetBootCounter(), err)
+	}
+	alg, err := strongestCommonAlg(olderLog.Algs, newerLog.Algs)
+	if err != nil {
+		return nil, err
+	}
+
+	olderEvents := byIndex(olderLog.Events(alg))
+	newerEvents := byIndex(newerLog.Events(alg))
+	indexes := make(map[int]bool)
+	for idx := range olderEvents {
+		indexes[idx] = true
+	}
+	for idx := range newerEvents {
+		indexes[idx] = true
+	}
+	sorted := make([]int, 0, len(indexes))
+	for idx := range indexes {
+		sorted = append(sorted, idx)
+	}
+	sort.Ints(sorted)
+
+	var changes []EventChange
+	for _, idx := range sorted {
+		regChanges, err := diffEvents(olderEvents[idx], newerEvents[idx])
+		if err != nil {
+			return nil, fmt.Errorf("register %d: %v", idx, err)
+		}
+		changes = append(changes, regChanges...)
+	}
+	return changes, nil
+}
+
+func parseLog(boot *pb.ArchivedBoot) (*tcg.EventLog, error) {
+	bundle := boot.GetBundle()
+	// CCELs have trailing padding at the end of the event log.
+	opts := tcg.ParseOpts{
+		AllowPadding: bundle.GetLogType() == pb.LogType_LOG_TYPE_CC,
+		MaxEvents:    maxDiffEvents,
+	}
+	return tcg.ParseEventLog(bundle.GetRawEventLog(), opts)
+}
+
+func strongestCommonAlg(a, b []register.HashAlg) (register.HashAlg, error) {
+	var common []register.HashAlg
+	for _, alg := range a {
+		for _, other := range b {
+			if alg == other {
+				common = append(common, alg)
+			}
+		}
+	}
+	if len(common) == 0 {
+		return 0, fmt.Errorf("event logs have no hash algorithm in common: %v and %v", a, b)
+	}
+	sort.SliceStable(common, func(i, j int) bool {
+		return pb.HashAlgo(common[i]).Strength() > pb.HashAlgo(common[j]).Strength()
+	})
+	return common[0], nil
+}
+
+func byIndex(events []tcg.Event) map[int][]tcg.Event {
+	out := make(map[int][]tcg.Event)
+	for _, event := range events {
+		out[event.Index] = append(out[event.Index], event)
+	}
+	return out
+}
+
+func sameEvent(a, b tcg.Event) bool {
+	return a.Type == b.Type && bytes.Equal(a.Digest, b.Digest)
+}
+
+// diffEvents returns the changes between the events of a single register,
+// from their longest common subsequence.
+func diffEvents(older, newer []tcg.Event) ([]EventChange, error) {
+	// Boots mostly differ in a few events, so only the events between the
+	// common prefix and suffix need the quadratic comparison.
+	for len(older) > 0 && len(newer) > 0 && sameEvent(older[0], newer[0]) {
+		older, newer = older[1:], newer[1:]
+	}
+	for len(older) > 0 && len(newer) > 0 && sameEvent(older[len(older)-1], newer[len(newer)-1]) {
+		older, newer = older[:len(older)-1], newer[:len(newer)-1]
+	}
+	if cells := (len(older) + 1) * (len(newer) + 1); cells > maxDiffCells {
+		return nil, fmt.Errorf("%d and %d differing events are too many to compare", len(older), len(newer))
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of older[i:]
+	// and newer[j:].
+	lcs := make([][]int, len(older)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newer)+1)
+	}
+	for i := len(older) - 1; i >= 0; i-- {
+		for j := len(newer) - 1; j >= 0; j-- {
+			if sameEvent(older[i], newer[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var changes []EventChange
+	i, j := 0, 0
+	for i < len(older) && j < len(newer) {
+		switch {
+		case sameEvent(older[i], newer[j]):
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			changes = append(changes, EventChange{Kind: Removed, Event: older[i]})
+			i++
+		default:
+			changes = append(changes, EventChange{Kind: Added, Event: newer[j]})
+			j++
+		}
+	}
+	for ; i < len(older); i++ {
+		changes = append(changes, EventChange{Kind: Removed, Event: older[i]})
+	}
+	for ; j < len(newer); j++ {
+		changes = append(changes, EventChange{Kind: Added, Event: newer[j]})
+	}
+	return changes, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/testdata"
+	"google.golang.org/protobuf/proto"
+)
+
+func boot(counter uint64, rawEventLog []byte) *pb.ArchivedBoot {
+	return &pb.ArchivedBoot{
+		BootCounter: counter,
+		ArchiveTime: int64(1700000000 + counter),
+		Bundle:      &pb.AttestationBundle{LogType: pb.LogType_LOG_TYPE_TCG2, RawEventLog: rawEventLog},
+	}
+}
+
+func TestAppendAndIterate(t *testing.T) {
+	archive := &pb.LogArchive{}
+	for _, counter := range []uint64{1, 2, 5} {
+		if err := Append(archive, boot(counter, testdata.Ubuntu2404AmdSevSnpEventLog)); err != nil {
+			t.Fatalf("Append(boot %d): %v", counter, err)
+		}
+	}
+	if err := Append(archive, boot(5, testdata.Ubuntu2404AmdSevSnpEventLog)); err == nil {
+		t.Errorf("Append(repeated boot counter): got nil, want error")
+	}
+	if err := Append(archive, boot(6, nil)); err == nil {
+		t.Errorf("Append(no event log): got nil, want error")
+	}
+
+	var buf bytes.Buffer
+	if err := Save(&buf, archive); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("Load(): %v", err)
+	}
+	if !proto.Equal(loaded, archive) {
+		t.Errorf("Load(Save()) = %v, want %v", loaded, archive)
+	}
+
+	var pairs [][2]uint64
+	err = Iterate(loaded, func(prev, boot *pb.ArchivedBoot) error {
+		pairs = append(pairs, [2]uint64{prev.GetBootCounter(), boot.GetBootCounter()})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate(): %v", err)
+	}
+	if want := [][2]uint64{{0, 1}, {1, 2}, {2, 5}}; len(pairs) != len(want) || pairs[0] != want[0] || pairs[1] != want[1] || pairs[2] != want[2] {
+		t.Errorf("Iterate() visited %v, want %v", pairs, want)
+	}
+	errStop := errors.New("stop")
+	if err := Iterate(loaded, func(_, _ *pb.ArchivedBoot) error { return errStop }); err != errStop {
+		t.Errorf("Iterate() = %v, want %v", err, errStop)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	full := testdata.Ubuntu2404AmdSevSnpEventLog
+	el, err := tcg.ParseEventLog(full, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pcr9 int
+	indexes := make(map[int]bool)
+	for _, event := range el.Events(register.HashSHA256) {
+		indexes[event.Index] = true
+		if event.Index == 9 {
+			pcr9++
+		}
+	}
+	var withoutPCR9 []int
+	for idx := range indexes {
+		if idx != 9 {
+			withoutPCR9 = append(withoutPCR9, idx)
+		}
+	}
+	filtered, err := tcg.FilterEventLog(full, withoutPCR9)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := Diff(boot(1, full), boot(2, full))
+	if err != nil {
+		t.Fatalf("Diff(same log): %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Diff(same log) = %v, want no changes", changes)
+	}
+
+	changes, err = Diff(boot(1, full), boot(2, filtered))
+	if err != nil {
+		t.Fatalf("Diff(): %v", err)
+	}
+	if len(changes) != pcr9 {
+		t.Errorf("Diff() = %d changes, want %d", len(changes), pcr9)
+	}
+	for _, change := range changes {
+		if change.Kind != Removed || change.Event.Index != 9 {
+			t.Errorf("Diff() = got %v change for PCR%d, want removed PCR9 events", change.Kind, change.Event.Index)
+		}
+	}
+
+	changes, err = Diff(boot(2, filtered), boot(3, full))
+	if err != nil {
+		t.Fatalf("Diff(): %v", err)
+	}
+	for _, change := range changes {
+		if change.Kind != Added || change.Event.Index != 9 {
+			t.Errorf("Diff() = got %v change for PCR%d, want added PCR9 events", change.Kind, change.Event.Index)
+		}
+	}
+}
+
+func TestDiffEvents(t *testing.T) {
+	a := tcg.Event{Index: 8, Type: tcg.Ipl, Digest: []byte{1}}
+	b := tcg.Event{Index: 8, Type: tcg.Ipl, Digest: []byte{2}}
+	c := tcg.Event{Index: 8, Type: tcg.Ipl, Digest: []byte{3}}
+	changes, err := diffEvents([]tcg.Event{a, b, c}, []tcg.Event{a, c, b})
+	if err != nil {
+		t.Fatalf("diffEvents() failed: %v", err)
+	}
+	if len(changes) != 2 || changes[0].Kind != Removed || changes[1].Kind != Added {
+		t.Errorf("diffEvents() = %v, want one removed and one added event", changes)
+	}
+}
+
+func TestDiffEventsLimit(t *testing.T) {
+	events := func(n int, tag byte) []tcg.Event {
+		out := make([]tcg.Event, n)
+		for i := range out {
+			out[i] = tcg.Event{Index: 8, Type: tcg.Ipl, Digest: []byte{tag, byte(i >> 8), byte(i)}}
+		}
+		return out
+	}
+	// A long common prefix and suffix are not compared pairwise.
+	common := events(maxDiffEvents, 0)
+	older := append(append(append([]tcg.Event{}, common[:maxDiffEvents/2]...), events(1, 1)...), common[maxDiffEvents/2:]...)
+	changes, err := diffEvents(older, common)
+	if err != nil {
+		t.Fatalf("diffEvents() with a common prefix and suffix failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Kind != Removed {
+		t.Errorf("diffEvents() = %v, want one removed event", changes)
+	}
+
+	if _, err := diffEvents(events(4096, 1), events(4096, 2)); err == nil {
+		t.Error("diffEvents() of 4096 differing events succeeded, want error")
+	}
+}
+
+func TestLoadTooLong(t *testing.T) {
+	r := io.LimitReader(zeroReader{}, maxArchiveLen+1)
+	if _, err := Load(r); err == nil {
+		t.Errorf("Load() of %d bytes succeeded, want error", maxArchiveLen+1)
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
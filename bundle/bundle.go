@@ -0,0 +1,454 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package bundle loads, saves, and verifies AttestationBundles, which package
+// a raw event log with the register values, quotes, and certificates needed to
+// verify it.
+package bundle
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/google/go-eventlog/ccel"
+	"github.com/google/go-eventlog/extract"
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/tpmeventlog"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"google.golang.org/protobuf/proto"
+)
+
+// VerifyOpts gives options for verifying an AttestationBundle.
+type VerifyOpts struct {
+	// Nonce is the expected extra data of the TPM quotes. When the quotes are
+	// verified, it is recorded in the FirmwareLogState provenance.
+	// If nil, the quote extra data is not checked.
+	Nonce []byte
+	// AKRoots are the trusted roots for the attestation key certificates.
+	// If nil, the certificate chain is not verified, and the caller is
+	// responsible for establishing trust in the attestation key: the quote
+	// signatures are still checked, but the provenance does not record the
+	// quotes as verified.
+	AKRoots *x509.CertPool
+	// Hash selects the register bank to replay the event log against.
+	// If unset, the strongest verified bank is used.
+	Hash pb.HashAlgo
+	// TrustUnquotedRegisters skips quote verification and trusts the register
+	// values as given. This is required for LOG_TYPE_CC bundles, whose
+	// register values must be verified against a TDX quote by the caller.
+	TrustUnquotedRegisters bool
+	// AllowRedactedMRs accepts bundles redacted with Redact, by parsing the
+	// event log with tcg.ParseOpts.AllowRedactedMRs. The redacted registers
+	// are recorded in FirmwareLogState.redacted_mrs, with a finding, and
+	// fail verification with Extract.Strict.
+	AllowRedactedMRs bool
+	// Extract gives options for extracting the FirmwareLogState.
+	Extract extract.Opts
+	// Now returns the time to verify the attestation key certificates at.
+	// The same time is recorded as the provenance VerifyTime, so verifying an
+	// old bundle as of its capture time gives the certificates' validity then.
+	// If nil, time.Now is used.
+	Now func() time.Time
+}
+
+func (o VerifyOpts) now() time.Time {
+	if o.Now == nil {
+		return time.Now()
+	}
+	return o.Now()
+}
+
+// extractOpts returns the extraction options, which parse the event log with
+// defaults, or Extract.ParseOpts if set, and allow redacted register events
+// if AllowRedactedMRs is set.
+func (o VerifyOpts) extractOpts(defaults tcg.ParseOpts) extract.Opts {
+	opts := o.Extract
+	if !o.AllowRedactedMRs {
+		return opts
+	}
+	parseOpts := defaults
+	if opts.ParseOpts != nil {
+		parseOpts = *opts.ParseOpts
+	}
+	parseOpts.AllowRedactedMRs = true
+	opts.ParseOpts = &parseOpts
+	return opts
+}
+
+// Save writes the serialized AttestationBundle to w.
+func Save(w io.Writer, bundle *pb.AttestationBundle) error {
+	out, err := proto.Marshal(bundle)
+	if err != nil {
+		return fmt.Errorf("failed to marshal AttestationBundle: %v", err)
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Load reads a serialized AttestationBundle from r.
+func Load(r io.Reader) (*pb.AttestationBundle, error) {
+	in, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &pb.AttestationBundle{}
+	if err := proto.Unmarshal(in, bundle); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal AttestationBundle: %v", err)
+	}
+	return bundle, nil
+}
+
+// Redact returns a copy of the bundle for selective disclosure, where the
+// events measured into each of the given registers are replaced by a single
+// event with the register value. See tcg.RedactEventLog. The register values,
+// quotes, and certificates are unchanged, so the redacted bundle still
+// verifies, but only the state measured into the other registers can be
+// extracted from it. Verifiers must set VerifyOpts.AllowRedactedMRs to
+// accept it. The event log keeps its compression format.
+func Redact(bundle *pb.AttestationBundle, mrIndexes []int) (*pb.AttestationBundle, error) {
+	var opts tcg.ParseOpts
+	switch bundle.GetLogType() {
+	case pb.LogType_LOG_TYPE_TCG2:
+	case pb.LogType_LOG_TYPE_CC:
+		// CCELs have trailing padding at the end of the event log.
+		opts.AllowPadding = true
+	default:
+		return nil, fmt.Errorf("unsupported log type %v", bundle.GetLogType())
+	}
+	rawEventLog := bundle.GetRawEventLog()
+	redacted, err := tcg.RedactEventLog(rawEventLog, mrIndexes, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to redact event log: %v", err)
+	}
+	redacted, err = tcg.Compress(redacted, tcg.DetectCompression(rawEventLog))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress redacted event log: %v", err)
+	}
+	out := proto.Clone(bundle).(*pb.AttestationBundle)
+	out.RawEventLog = redacted
+	return out, nil
+}
+
+// Verify runs the full verification pipeline on an AttestationBundle: it
+// verifies the attestation key and quotes, replays the event log against the
+// quoted register values, and extracts the FirmwareLogState.
+//
+// As with tpmeventlog.ReplayAndExtract and ccel.ReplayAndExtract, the returned
+// FirmwareLogState may be a partial FirmwareLogState, in which case err will
+// be non-nil. The FirmwareLogState provenance records whether the quotes were
+// verified by an attestation key certified by opts.AKRoots, and the nonce they
+// were bound to. Only the PCRs selected by the quotes are trusted, so Verify
+// fails if the event log measures into a PCR the quotes do not cover.
+func Verify(bundle *pb.AttestationBundle, opts VerifyOpts) (*pb.FirmwareLogState, error) {
+	now := opts.now()
+	state, err := verify(bundle, opts, now)
+	if state != nil {
+		state.Provenance = provenance(opts, now)
+	}
+	return state, err
+}
+
+func verify(bundle *pb.AttestationBundle, opts VerifyOpts, now time.Time) (*pb.FirmwareLogState, error) {
+	switch bundle.GetLogType() {
+	case pb.LogType_LOG_TYPE_TCG2:
+		bank, err := verifiedPCRBank(bundle, opts, now)
+		if err != nil {
+			return nil, err
+		}
+		extractOpts := opts.extractOpts(tcg.ParseOpts{})
+		if err := checkMeasuredPCRs(bundle.GetRawEventLog(), bank, extractOpts); err != nil {
+			return nil, err
+		}
+		return tpmeventlog.ReplayAndExtract(bundle.GetRawEventLog(), bank, extractOpts)
+	case pb.LogType_LOG_TYPE_CC:
+		if !opts.TrustUnquotedRegisters {
+			return nil, errors.New("verifying CC register values is unsupported: set TrustUnquotedRegisters after verifying them with a TDX quote")
+		}
+		bank, err := rtmrBank(bundle)
+		if err != nil {
+			return nil, err
+		}
+		// As in ccel.ReplayAndExtract, CCELs allow trailing padding.
+		return ccel.ReplayAndExtract(bundle.GetCcelAcpiTable(), bundle.GetRawEventLog(), bank, opts.extractOpts(tcg.ParseOpts{AllowPadding: true}))
+	default:
+		return nil, fmt.Errorf("unsupported log type %v", bundle.GetLogType())
+	}
+}
+
+// provenance describes how and when the register values were verified. The
+// quotes, and the nonce they were checked against, only count as verified if
+// the attestation key certificate chain was verified.
+func provenance(opts VerifyOpts, now time.Time) *pb.Provenance {
+	if opts.TrustUnquotedRegisters || opts.AKRoots == nil {
+		return &pb.Provenance{VerifyTime: now.Unix()}
+	}
+	return &pb.Provenance{QuotesVerified: true, Nonce: opts.Nonce, VerifyTime: now.Unix()}
+}
+
+// verifiedPCRBank returns the PCR bank selected by opts, after verifying it
+// against the bundle's quotes. The returned bank only has the PCRs selected by
+// the quotes of its hash, unless the registers are trusted unquoted.
+func verifiedPCRBank(bundle *pb.AttestationBundle, opts VerifyOpts, now time.Time) (register.PCRBank, error) {
+	banks := make(map[pb.HashAlgo]register.PCRBank)
+	for _, bank := range bundle.GetBanks() {
+		if _, ok := banks[bank.GetHash()]; ok {
+			return register.PCRBank{}, fmt.Errorf("duplicate %v register bank", bank.GetHash())
+		}
+		pcrBank, err := register.PCRBankFromProto(bank)
+		if err != nil {
+			return register.PCRBank{}, err
+		}
+		banks[bank.GetHash()] = pcrBank
+	}
+
+	// quoted holds the PCRs of each bank selected by a verified quote, or nil
+	// if the whole bank is trusted.
+	quoted := make(map[pb.HashAlgo]map[int]bool)
+	if opts.TrustUnquotedRegisters {
+		for hash := range banks {
+			quoted[hash] = nil
+		}
+	} else {
+		akPub, err := akPublicKey(bundle, opts.AKRoots, now)
+		if err != nil {
+			return register.PCRBank{}, err
+		}
+		for i, quote := range bundle.GetQuotes() {
+			hash, pcrs, err := verifyQuote(akPub, quote, opts.Nonce, banks)
+			if err != nil {
+				return register.PCRBank{}, fmt.Errorf("invalid quote #%d: %v", i, err)
+			}
+			if quoted[hash] == nil {
+				quoted[hash] = make(map[int]bool)
+			}
+			for _, idx := range pcrs {
+				quoted[hash][idx] = true
+			}
+		}
+	}
+
+	hash := opts.Hash
+	if hash == pb.HashAlgo_HASH_INVALID {
+		for verifiedHash := range quoted {
+			if hash == pb.HashAlgo_HASH_INVALID || verifiedHash.Strength() > hash.Strength() {
+				hash = verifiedHash
+			}
+		}
+	}
+	pcrs, ok := quoted[hash]
+	if !ok {
+		return register.PCRBank{}, fmt.Errorf("no verified %v register bank", hash)
+	}
+	bank := banks[hash]
+	if pcrs == nil {
+		return bank, nil
+	}
+	verified := register.PCRBank{TCGHashAlgo: bank.TCGHashAlgo}
+	for _, pcr := range bank.PCRs {
+		if pcrs[pcr.Index] {
+			verified.PCRs = append(verified.PCRs, pcr)
+		}
+	}
+	return verified, nil
+}
+
+// checkMeasuredPCRs returns an error if the event log measures into a PCR that
+// is not in the verified bank. Replay skips the events of such PCRs, so the
+// state measured into them could not be extracted or trusted.
+func checkMeasuredPCRs(rawEventLog []byte, bank register.PCRBank, opts extract.Opts) error {
+	parseOpts, err := opts.EventLogParseOpts(tcg.ParseOpts{})
+	if err != nil {
+		return err
+	}
+	eventLog, err := tcg.ParseEventLog(rawEventLog, parseOpts)
+	if err != nil {
+		return fmt.Errorf("failed to parse event log: %v", err)
+	}
+	verified := make(map[int]bool, len(bank.PCRs))
+	for _, pcr := range bank.PCRs {
+		verified[pcr.Index] = true
+	}
+	unverified := make(map[int]bool)
+	for _, event := range eventLog.Events(register.HashAlg(bank.TCGHashAlgo)) {
+		if !verified[event.Index] {
+			unverified[event.Index] = true
+		}
+	}
+	if len(unverified) == 0 {
+		return nil
+	}
+	pcrs := make([]int, 0, len(unverified))
+	for idx := range unverified {
+		pcrs = append(pcrs, idx)
+	}
+	sort.Ints(pcrs)
+	return fmt.Errorf("event log measures into PCRs %v, which are not covered by a verified quote", pcrs)
+}
+
+func rtmrBank(bundle *pb.AttestationBundle) (register.RTMRBank, error) {
+	for _, bank := range bundle.GetBanks() {
+		if bank.GetHash() == pb.HashAlgo_SHA384 {
+			return register.RTMRBankFromProto(bank)
+		}
+	}
+	return register.RTMRBank{}, errors.New("no SHA384 register bank for RTMRs")
+}
+
+// akPublicKey returns the attestation key public key, verifying the
+// certificate chain against roots at the given time if roots is set.
+func akPublicKey(bundle *pb.AttestationBundle, roots *x509.CertPool, now time.Time) (crypto.PublicKey, error) {
+	var certs []*x509.Certificate
+	for i, der := range bundle.GetCertificates() {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate #%d: %v", i, err)
+		}
+		certs = append(certs, cert)
+	}
+	if roots != nil {
+		if len(certs) == 0 {
+			return nil, errors.New("no attestation key certificates to verify")
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   now,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to verify attestation key certificate: %v", err)
+		}
+	}
+
+	if len(bundle.GetAkPublic()) == 0 {
+		if len(certs) == 0 {
+			return nil, errors.New("no attestation key public key or certificate")
+		}
+		return certs[0].PublicKey, nil
+	}
+	akPub, err := x509.ParsePKIXPublicKey(bundle.GetAkPublic())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse attestation key public key: %v", err)
+	}
+	if len(certs) != 0 {
+		if pub, ok := akPub.(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(certs[0].PublicKey) {
+			return nil, errors.New("attestation key public key does not match the certificate")
+		}
+	}
+	return akPub, nil
+}
+
+// verifyQuote verifies the quote signature, extra data, and PCR digest
+// against the banks. It returns the hash algorithm of the quoted bank, and
+// the quoted PCRs.
+func verifyQuote(akPub crypto.PublicKey, quote *pb.TpmQuote, nonce []byte, banks map[pb.HashAlgo]register.PCRBank) (pb.HashAlgo, []int, error) {
+	att, err := tpm2.DecodeAttestationData(quote.GetQuote())
+	if err != nil {
+		return pb.HashAlgo_HASH_INVALID, nil, fmt.Errorf("failed to decode attestation data: %v", err)
+	}
+	if att.Type != tpm2.TagAttestQuote || att.AttestedQuoteInfo == nil {
+		return pb.HashAlgo_HASH_INVALID, nil, fmt.Errorf("attestation data is not a quote: got type %#x", att.Type)
+	}
+	sig, err := tpm2.DecodeSignature(bytes.NewBuffer(quote.GetRawSig()))
+	if err != nil {
+		return pb.HashAlgo_HASH_INVALID, nil, fmt.Errorf("failed to decode signature: %v", err)
+	}
+	if err := verifySignature(akPub, quote.GetQuote(), sig); err != nil {
+		return pb.HashAlgo_HASH_INVALID, nil, err
+	}
+	if nonce != nil && subtle.ConstantTimeCompare(att.ExtraData, nonce) != 1 {
+		return pb.HashAlgo_HASH_INVALID, nil, errors.New("quote extra data does not match the nonce")
+	}
+
+	selection := att.AttestedQuoteInfo.PCRSelection
+	hash := pb.HashAlgo(selection.Hash)
+	bank, ok := banks[hash]
+	if !ok {
+		return pb.HashAlgo_HASH_INVALID, nil, fmt.Errorf("no register bank for quoted hash %v", hash)
+	}
+	cryptoHash, err := selection.Hash.Hash()
+	if err != nil {
+		return pb.HashAlgo_HASH_INVALID, nil, err
+	}
+	if !cryptoHash.Available() {
+		return pb.HashAlgo_HASH_INVALID, nil, fmt.Errorf("hash algorithm %v is not available", cryptoHash)
+	}
+	values := make(map[int][]byte, len(bank.PCRs))
+	for _, pcr := range bank.PCRs {
+		values[pcr.Index] = pcr.Digest
+	}
+	pcrs := append([]int(nil), selection.PCRs...)
+	sort.Ints(pcrs)
+	hasher := cryptoHash.New()
+	for _, idx := range pcrs {
+		value, ok := values[idx]
+		if !ok {
+			return pb.HashAlgo_HASH_INVALID, nil, fmt.Errorf("quoted PCR %d missing from %v register bank", idx, hash)
+		}
+		hasher.Write(value)
+	}
+	if subtle.ConstantTimeCompare(hasher.Sum(nil), att.AttestedQuoteInfo.PCRDigest) != 1 {
+		return pb.HashAlgo_HASH_INVALID, nil, fmt.Errorf("quoted PCR digest does not match the %v register bank", hash)
+	}
+	return hash, pcrs, nil
+}
+
+func verifySignature(akPub crypto.PublicKey, data []byte, sig *tpm2.Signature) error {
+	switch pub := akPub.(type) {
+	case *rsa.PublicKey:
+		if sig.RSA == nil {
+			return fmt.Errorf("got signature algorithm %v for an RSA key", sig.Alg)
+		}
+		hash, err := sig.RSA.HashAlg.Hash()
+		if err != nil {
+			return err
+		}
+		hasher := hash.New()
+		hasher.Write(data)
+		if sig.Alg == tpm2.AlgRSAPSS {
+			return rsa.VerifyPSS(pub, hash, hasher.Sum(nil), sig.RSA.Signature, nil)
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, hasher.Sum(nil), sig.RSA.Signature)
+	case *ecdsa.PublicKey:
+		if sig.ECC == nil {
+			return fmt.Errorf("got signature algorithm %v for an ECDSA key", sig.Alg)
+		}
+		hash, err := sig.ECC.HashAlg.Hash()
+		if err != nil {
+			return err
+		}
+		hasher := hash.New()
+		hasher.Write(data)
+		if !ecdsa.Verify(pub, hasher.Sum(nil), sig.ECC.R, sig.ECC.S) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported attestation key type %T", akPub)
+	}
+}
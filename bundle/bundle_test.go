@@ -0,0 +1,355 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package bundle
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-eventlog/eventlogtest"
+	"github.com/google/go-eventlog/extract"
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/testdata"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"google.golang.org/protobuf/proto"
+)
+
+// replayPCRs computes the SHA256 PCR values measured by the event log.
+func replayPCRs(t *testing.T, rawEventLog []byte) map[uint32][]byte {
+	t.Helper()
+	el, err := tcg.ParseEventLog(rawEventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcrs := make(map[uint32][]byte)
+	for _, event := range el.Events(register.HashSHA256) {
+		value, ok := pcrs[event.MRIndex()]
+		if !ok {
+			value = make([]byte, crypto.SHA256.Size())
+		}
+		hasher := crypto.SHA256.New()
+		hasher.Write(value)
+		hasher.Write(event.ReplayedDigest())
+		pcrs[event.MRIndex()] = hasher.Sum(nil)
+	}
+	return pcrs
+}
+
+func makeQuote(t *testing.T, ak *ecdsa.PrivateKey, nonce []byte, pcrs map[uint32][]byte) *pb.TpmQuote {
+	t.Helper()
+	var selected []int
+	hasher := crypto.SHA256.New()
+	for idx := 0; idx < 24; idx++ {
+		if value, ok := pcrs[uint32(idx)]; ok {
+			selected = append(selected, idx)
+			hasher.Write(value)
+		}
+	}
+	att := tpm2.AttestationData{
+		Magic:           0xff544347,
+		Type:            tpm2.TagAttestQuote,
+		QualifiedSigner: tpm2.Name{Digest: &tpm2.HashValue{Alg: tpm2.AlgSHA256, Value: make([]byte, 32)}},
+		ExtraData:       nonce,
+		AttestedQuoteInfo: &tpm2.QuoteInfo{
+			PCRSelection: tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: selected},
+			PCRDigest:    hasher.Sum(nil),
+		},
+	}
+	quote, err := att.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := crypto.SHA256.New()
+	digest.Write(quote)
+	r, s, err := ecdsa.Sign(rand.Reader, ak, digest.Sum(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := tpm2.Signature{Alg: tpm2.AlgECDSA, ECC: &tpm2.SignatureECC{HashAlg: tpm2.AlgSHA256, R: r, S: s}}.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pb.TpmQuote{Quote: quote, RawSig: sig}
+}
+
+func makeBundle(t *testing.T, nonce []byte) (*pb.AttestationBundle, map[uint32][]byte) {
+	t.Helper()
+	ak, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	akPub, err := x509.MarshalPKIXPublicKey(&ak.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcrs := replayPCRs(t, testdata.Ubuntu2404AmdSevSnpEventLog)
+	return &pb.AttestationBundle{
+		LogType:     pb.LogType_LOG_TYPE_TCG2,
+		RawEventLog: testdata.Ubuntu2404AmdSevSnpEventLog,
+		Banks:       []*pb.RegisterBank{{Hash: pb.HashAlgo_SHA256, Values: pcrs}},
+		Quotes:      []*pb.TpmQuote{makeQuote(t, ak, nonce, pcrs)},
+		AkPublic:    akPub,
+	}, pcrs
+}
+
+// certifyAK adds a certificate for the attestation key of the bundle, and
+// returns the root it chains to.
+func certifyAK(t *testing.T, bundle *pb.AttestationBundle) *x509.CertPool {
+	t.Helper()
+	akPub, err := x509.ParsePKIXPublicKey(bundle.GetAkPublic())
+	if err != nil {
+		t.Fatal(err)
+	}
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "AK root"},
+		NotBefore:             time.Unix(1600000000, 0),
+		NotAfter:              time.Unix(1800000000, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	akTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "AK"},
+		NotBefore:    time.Unix(1600000000, 0),
+		NotAfter:     time.Unix(1800000000, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	akDER, err := x509.CreateCertificate(rand.Reader, akTemplate, ca, akPub, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle.Certificates = [][]byte{akDER}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	return roots
+}
+
+func TestSaveLoadVerify(t *testing.T) {
+	nonce := []byte("super secret nonce")
+	bundle, _ := makeBundle(t, nonce)
+	roots := certifyAK(t, bundle)
+
+	var buf bytes.Buffer
+	if err := Save(&buf, bundle); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("Load(): %v", err)
+	}
+	if !proto.Equal(loaded, bundle) {
+		t.Fatalf("Load(Save()) differs from the original bundle")
+	}
+
+	clock := eventlogtest.NewFakeClock(time.Unix(1700000000, 0))
+	state, err := Verify(loaded, VerifyOpts{Nonce: nonce, AKRoots: roots, Extract: extract.Opts{Loader: extract.GRUB}, Now: clock.Now})
+	if err != nil {
+		t.Fatalf("Verify(): %v", err)
+	}
+	if state.GetHash() != pb.HashAlgo_SHA256 || state.GetLinuxKernel().GetCommandLine() == "" {
+		t.Errorf("Verify(): got hash %v and kernel %v, want a SHA256 state with a kernel command line", state.GetHash(), state.GetLinuxKernel())
+	}
+	if want := (&pb.Provenance{QuotesVerified: true, Nonce: nonce, VerifyTime: 1700000000}); !proto.Equal(state.GetProvenance(), want) {
+		t.Errorf("Verify(): got provenance %v, want %v", state.GetProvenance(), want)
+	}
+
+	// Without AK roots, the quotes are checked but do not count as verified.
+	state, err = Verify(loaded, VerifyOpts{Nonce: nonce, Extract: extract.Opts{Loader: extract.GRUB}, Now: clock.Now})
+	if err != nil {
+		t.Fatalf("Verify(no AK roots): %v", err)
+	}
+	if want := (&pb.Provenance{VerifyTime: 1700000000}); !proto.Equal(state.GetProvenance(), want) {
+		t.Errorf("Verify(no AK roots): got provenance %v, want %v", state.GetProvenance(), want)
+	}
+}
+
+func TestVerifyTrustUnquotedRegisters(t *testing.T) {
+	nonce := []byte("super secret nonce")
+	bundle, _ := makeBundle(t, nonce)
+	bundle.Quotes = nil
+
+	clock := eventlogtest.NewFakeClock(time.Unix(1700000000, 0))
+	state, err := Verify(bundle, VerifyOpts{Nonce: nonce, TrustUnquotedRegisters: true, Extract: extract.Opts{Loader: extract.GRUB}, Now: clock.Now})
+	if err != nil {
+		t.Fatalf("Verify(): %v", err)
+	}
+	// The nonce was not checked against a quote, so it must not be recorded.
+	if want := (&pb.Provenance{VerifyTime: 1700000000}); !proto.Equal(state.GetProvenance(), want) {
+		t.Errorf("Verify(): got provenance %v, want %v", state.GetProvenance(), want)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	nonce := []byte("super secret nonce")
+	bundle, _ := makeBundle(t, nonce)
+	compressed, err := tcg.Compress(bundle.GetRawEventLog(), tcg.Gzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle.RawEventLog = compressed
+
+	redacted, err := Redact(bundle, []int{1})
+	if err != nil {
+		t.Fatalf("Redact(): %v", err)
+	}
+	if !bytes.Equal(bundle.GetRawEventLog(), compressed) {
+		t.Errorf("Redact(): modified the original bundle")
+	}
+	if got := tcg.DetectCompression(redacted.GetRawEventLog()); got != tcg.Gzip {
+		t.Errorf("Redact(): got %v event log, want %v", got, tcg.Gzip)
+	}
+	if _, err := Verify(redacted, VerifyOpts{Nonce: nonce, Extract: extract.Opts{Loader: extract.GRUB}}); err == nil {
+		t.Errorf("Verify(redacted bundle without AllowRedactedMRs): got nil, want error")
+	}
+	state, err := Verify(redacted, VerifyOpts{Nonce: nonce, AllowRedactedMRs: true, Extract: extract.Opts{Loader: extract.GRUB}})
+	if err != nil {
+		t.Fatalf("Verify(redacted bundle): %v", err)
+	}
+	if state.GetLinuxKernel().GetCommandLine() == "" {
+		t.Errorf("Verify(redacted bundle): got no kernel command line")
+	}
+	if got := state.GetRedactedMrs(); len(got) != 1 || got[0] != 1 {
+		t.Errorf("Verify(redacted bundle): got redacted MRs %v, want [1]", got)
+	}
+	var found bool
+	for _, finding := range state.GetFindings() {
+		found = found || finding.GetType() == pb.FindingType_FINDING_TYPE_REDACTED_MRS
+	}
+	if !found {
+		t.Errorf("Verify(redacted bundle): got findings %v, want a redacted registers finding", state.GetFindings())
+	}
+	var redactedErr extract.RedactedMRsError
+	if _, err := Verify(redacted, VerifyOpts{Nonce: nonce, AllowRedactedMRs: true, Extract: extract.Opts{Loader: extract.GRUB, Strict: true}}); !errors.As(err, &redactedErr) {
+		t.Errorf("Verify(redacted bundle, Strict): got %v, want a RedactedMRsError", err)
+	}
+
+	rawEventLog, err := tcg.Decompress(redacted.GetRawEventLog())
+	if err != nil {
+		t.Fatal(err)
+	}
+	el, err := tcg.ParseEventLog(rawEventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the PCR1 value is disclosed, not the digests of its events.
+	var pcr1Events []tcg.Event
+	for _, event := range el.Events(register.HashSHA256) {
+		if event.Index == 1 {
+			pcr1Events = append(pcr1Events, event)
+		}
+	}
+	if len(pcr1Events) != 1 || pcr1Events[0].Type != tcg.NoAction || !bytes.Equal(pcr1Events[0].Digest, bundle.GetBanks()[0].GetValues()[1]) {
+		t.Errorf("Redact(): got PCR1 events %v, want a single event with the PCR1 value", pcr1Events)
+	}
+}
+
+func TestVerifyFails(t *testing.T) {
+	nonce := []byte("super secret nonce")
+	tests := []struct {
+		name   string
+		mutate func(*pb.AttestationBundle)
+		opts   VerifyOpts
+	}{
+		{"wrong nonce", func(*pb.AttestationBundle) {}, VerifyOpts{Nonce: []byte("stale nonce")}},
+		{"tampered PCR", func(b *pb.AttestationBundle) {
+			b.Banks[0].Values[0] = make([]byte, crypto.SHA256.Size())
+		}, VerifyOpts{Nonce: nonce}},
+		{"tampered signature", func(b *pb.AttestationBundle) {
+			b.Quotes[0].Quote[len(b.Quotes[0].Quote)-1] ^= 0xff
+		}, VerifyOpts{Nonce: nonce}},
+		{"no quotes", func(b *pb.AttestationBundle) { b.Quotes = nil }, VerifyOpts{Nonce: nonce}},
+		{"no AK", func(b *pb.AttestationBundle) { b.AkPublic = nil }, VerifyOpts{Nonce: nonce}},
+		{"uncertified AK", func(*pb.AttestationBundle) {}, VerifyOpts{Nonce: nonce, AKRoots: x509.NewCertPool()}},
+		{"unquoted bank requested", func(*pb.AttestationBundle) {}, VerifyOpts{Nonce: nonce, Hash: pb.HashAlgo_SHA1}},
+		{"CC without trusted registers", func(b *pb.AttestationBundle) { b.LogType = pb.LogType_LOG_TYPE_CC }, VerifyOpts{}},
+		{"undefined log type", func(b *pb.AttestationBundle) { b.LogType = pb.LogType_LOG_TYPE_UNDEFINED }, VerifyOpts{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle, _ := makeBundle(t, nonce)
+			tc.mutate(bundle)
+			if _, err := Verify(bundle, tc.opts); err == nil {
+				t.Errorf("Verify(): got nil, want error")
+			}
+		})
+	}
+}
+
+func TestVerifyPartialQuote(t *testing.T) {
+	nonce := []byte("super secret nonce")
+	bundle, pcrs := makeBundle(t, nonce)
+	ak, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	akPub, err := x509.MarshalPKIXPublicKey(&ak.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle.AkPublic = akPub
+
+	// An unquoted register value is dropped from the verified bank.
+	bundle.Quotes = []*pb.TpmQuote{makeQuote(t, ak, nonce, pcrs)}
+	bundle.Banks[0].Values[23] = make([]byte, crypto.SHA256.Size())
+	bank, err := verifiedPCRBank(bundle, VerifyOpts{Nonce: nonce}, time.Now())
+	if err != nil {
+		t.Fatalf("verifiedPCRBank(): %v", err)
+	}
+	for _, pcr := range bank.PCRs {
+		if pcr.Index == 23 {
+			t.Errorf("verifiedPCRBank(): got unquoted PCR 23")
+		}
+	}
+	if _, err := Verify(bundle, VerifyOpts{Nonce: nonce, Extract: extract.Opts{Loader: extract.GRUB}}); err != nil {
+		t.Errorf("Verify(): %v", err)
+	}
+
+	// A register the event log measures into must be quoted.
+	partial := make(map[uint32][]byte)
+	for idx, value := range pcrs {
+		if idx != 7 && idx != 23 {
+			partial[idx] = value
+		}
+	}
+	bundle.Quotes = []*pb.TpmQuote{makeQuote(t, ak, nonce, partial)}
+	if _, err := Verify(bundle, VerifyOpts{Nonce: nonce, Extract: extract.Opts{Loader: extract.GRUB}}); err == nil {
+		t.Errorf("Verify(): got nil, want error for an unquoted PCR 7")
+	}
+}
@@ -61,6 +61,12 @@ type CCACPITable struct {
 	CCType
 }
 
+// ParseACPITable parses the CCEL ACPI table, e.g., read from
+// /sys/firmware/acpi/tables/CCEL.
+func ParseACPITable(acpiTableFile []byte) (CCACPITable, error) {
+	return parseCCELACPITable(acpiTableFile)
+}
+
 func parseCCELACPITable(acpiTableFile []byte) (CCACPITable, error) {
 	if len(acpiTableFile) < CCELACPITableMinSize {
 		return CCACPITable{}, fmt.Errorf("received a smaller CCEL ACPI Table size (%v) than expected (%v)", len(acpiTableFile), CCELACPITableMinSize)
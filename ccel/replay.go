@@ -15,6 +15,7 @@
 package ccel
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/google/go-eventlog/extract"
@@ -24,36 +25,72 @@ import (
 )
 
 // ReplayAndExtract parses a Confidential Computing event log and
-// replays the parsed event log against the RTMR bank specified by hash.
+// replays the parsed event log against the CC measurement register bank.
+// For TDX, the bank must be a register.RTMRBank. For SEV-SNP, it must be a
+// register.CCMRBank indexed by CC MR index.
 //
 // It then extracts event info from the verified log into a FirmwareLogState.
-// It returns an error on failing to replay the events against the RTMR bank or
+// It returns an error on failing to replay the events against the bank or
 // on failing to parse malformed events.
 //
 // The returned FirmwareLogState may be a partial FirmwareLogState.
 // In the case of a partially filled state, err will be non-nil.
 // Callers can look for individual errors using `errors.Is`.
 //
-// It is the caller's responsibility to ensure that the passed RTMR values can be
-// trusted. Users can establish trust in RTMR values by either calling
+// It is the caller's responsibility to ensure that the passed register values
+// can be trusted. Users can establish trust in RTMR values by either calling
 // client.ReadRTMRs() themselves or by verifying the values via a RTMR quote.
-func ReplayAndExtract(acpiTableFile []byte, rawEventLog []byte, rtmrBank register.RTMRBank, opts extract.Opts) (*pb.FirmwareLogState, error) {
+func ReplayAndExtract(acpiTableFile []byte, rawEventLog []byte, bank register.MRBank, opts extract.Opts) (*pb.FirmwareLogState, error) {
 	table, err := parseCCELACPITable(acpiTableFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse CCEL ACPI Table file: %v", err)
 	}
-	if table.CCType != TDX {
-		return nil, fmt.Errorf("only TDX Confidential Computing event logs are supported: received %v", table.CCType)
+	var registerCfg extract.RegisterConfig
+	switch table.CCType {
+	case TDX:
+		if _, ok := bank.(register.RTMRBank); !ok {
+			return nil, fmt.Errorf("got a %T for a TDX event log, want a register.RTMRBank", bank)
+		}
+		registerCfg = extract.RTMRRegisterConfig
+	case SEV:
+		if _, ok := bank.(register.CCMRBank); !ok {
+			return nil, fmt.Errorf("got a %T for an SEV event log, want a register.CCMRBank", bank)
+		}
+		registerCfg = extract.SEVSNPRegisterConfig
+	default:
+		return nil, fmt.Errorf("only TDX and SEV Confidential Computing event logs are supported: received %v", table.CCType)
 	}
 
-	cryptoHash, err := rtmrBank.CryptoHash()
+	cryptoHash, err := bank.CryptoHash()
 	if err != nil {
 		return &pb.FirmwareLogState{}, err
 	}
-	// CCELs have trailing padding at the end of the event log.
-	events, err := tcg.ParseAndReplay(rawEventLog, rtmrBank.MRs(), tcg.ParseOpts{AllowPadding: true})
+	// CCELs have trailing padding at the end of the event log, so allow it
+	// unless the caller set their own parse options.
+	parseOpts, err := opts.EventLogParseOpts(tcg.ParseOpts{AllowPadding: true})
 	if err != nil {
 		return nil, err
 	}
-	return extract.FirmwareLogState(events, cryptoHash, extract.RTMRRegisterConfig, opts)
+	eventLog, err := tcg.ParseEventLog(rawEventLog, parseOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event log: %v", err)
+	}
+	events, err := eventLog.Verify(bank.MRs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay event log: %v", err)
+	}
+	// Report the findings once the padding and parse quirk findings are added.
+	extractOpts := opts
+	extractOpts.FindingSink = nil
+	state, err := extract.FirmwareLogState(events, cryptoHash, registerCfg, extractOpts)
+	state = opts.OwnedState(state)
+	if state != nil {
+		state.Findings = append(state.Findings, extract.PaddingFindings(eventLog.Padding)...)
+	}
+	extract.ReportQuirks(state, eventLog.AppliedQuirks)
+	if redactedErr := opts.ReportRedactedMRs(state, eventLog.RedactedMRs); redactedErr != nil {
+		err = errors.Join(err, redactedErr)
+	}
+	opts.ReportFindings(state)
+	return state, err
 }
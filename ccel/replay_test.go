@@ -15,13 +15,16 @@
 package ccel
 
 import (
+	"crypto"
 	"os"
 	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/google/go-eventlog/extract"
+	pb "github.com/google/go-eventlog/proto/state"
 	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
 )
 
 func TestReplayAndExtract(t *testing.T) {
@@ -63,6 +66,60 @@ func TestReplayAndExtract(t *testing.T) {
 	}
 }
 
+func TestReplayAndExtractSEVSNP(t *testing.T) {
+	tdxTable, err := os.ReadFile("../testdata/eventlogs/ccel/cos-113-intel-tdx.table.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	elBytes, err := os.ReadFile(COS113TDX.fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SEVSNPRegisterConfig assumes SEV-SNP firmware maps PCRs to CC MR
+	// indexes as TDX firmware does, so the TDX event log stands in for an
+	// SEV-SNP one.
+	sevTable := append([]byte(nil), tdxTable...)
+	sevTable[36] = SEV
+	bank := register.CCMRBank{Hash: crypto.SHA384}
+	for _, rtmr := range COS113TDX.rtmrs {
+		bank.CCMRs = append(bank.CCMRs, register.CCMR{Index: rtmr.Index + 1, Digest: rtmr.Digest})
+	}
+
+	state, err := ReplayAndExtract(sevTable, elBytes, bank, extract.Opts{Loader: extract.GRUB})
+	if err != nil {
+		t.Fatalf("ReplayAndExtract(): %v", err)
+	}
+	if got := state.GetPlatform().GetTechnology(); got != pb.GCEConfidentialTechnology_AMD_SEV_SNP {
+		t.Errorf("ReplayAndExtract(): got technology %v, want AMD_SEV_SNP", got)
+	}
+	if state.GetLogType() != pb.LogType_LOG_TYPE_CC || state.GetLinuxKernel().GetCommandLine() == "" {
+		t.Errorf("ReplayAndExtract(): got log type %v and kernel state %v, want a CC log with a kernel command line", state.GetLogType(), state.GetLinuxKernel())
+	}
+
+	// CC MR indexes are not RTMR indexes.
+	rtmrIndexed := register.CCMRBank{Hash: crypto.SHA384}
+	for _, rtmr := range COS113TDX.rtmrs {
+		rtmrIndexed.CCMRs = append(rtmrIndexed.CCMRs, register.CCMR{Index: rtmr.Index, Digest: rtmr.Digest})
+	}
+	if _, err := ReplayAndExtract(sevTable, elBytes, rtmrIndexed, extract.Opts{Loader: extract.GRUB}); err == nil {
+		t.Errorf("ReplayAndExtract(RTMR-indexed bank): got nil, want error")
+	}
+
+	// The bank type must match the CC type.
+	if _, err := ReplayAndExtract(sevTable, elBytes, register.RTMRBank{RTMRs: COS113TDX.rtmrs}, extract.Opts{Loader: extract.GRUB}); err == nil {
+		t.Errorf("ReplayAndExtract(SEV event log, RTMR bank): got nil, want error")
+	}
+	if _, err := ReplayAndExtract(tdxTable, elBytes, bank, extract.Opts{Loader: extract.GRUB}); err == nil {
+		t.Errorf("ReplayAndExtract(TDX event log, CC MR bank): got nil, want error")
+	}
+
+	reserved := append([]byte(nil), tdxTable...)
+	reserved[36] = Reserved
+	if _, err := ReplayAndExtract(reserved, elBytes, bank, extract.Opts{Loader: extract.GRUB}); err == nil {
+		t.Errorf("ReplayAndExtract(reserved CC type): got nil, want error")
+	}
+}
+
 func TestReplayAndExtractFailDuplicateSeparator(t *testing.T) {
 	badELWithUEFIBug, err := os.ReadFile("../testdata/eventlogs/ccel/cos-113-intel-tdx-dupe-separator.bin")
 	if err != nil {
@@ -87,3 +144,84 @@ func TestReplayAndExtractFailDuplicateSeparator(t *testing.T) {
 		t.Errorf("ReplayAndExtract(badELWithUEFIBug): got %v, expected error with duplicate separator message", err)
 	}
 }
+
+func TestReplayAndExtractParseOpts(t *testing.T) {
+	tableBytes, err := os.ReadFile("../testdata/eventlogs/ccel/cos-113-intel-tdx.table.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	elBytes, err := os.ReadFile(COS113TDX.fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank := register.RTMRBank{RTMRs: COS113TDX.rtmrs}
+	for _, tc := range []struct {
+		name      string
+		parseOpts *tcg.ParseOpts
+		wantErr   bool
+	}{
+		{"default", nil, false},
+		{"allow padding", &tcg.ParseOpts{AllowPadding: true}, false},
+		{"no padding", &tcg.ParseOpts{}, true},
+		{"padding quirk", &tcg.ParseOpts{Quirks: tcg.QuirkPadding}, false},
+		{"too many events", &tcg.ParseOpts{AllowPadding: true, MaxEvents: 10}, true},
+		{"event data too large", &tcg.ParseOpts{AllowPadding: true, MaxEventDataSize: 16}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := extract.Opts{Loader: extract.GRUB, ParseOpts: tc.parseOpts}
+			if _, err := ReplayAndExtract(tableBytes, elBytes, bank, opts); (err != nil) != tc.wantErr {
+				t.Errorf("ReplayAndExtract(): got %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestReplayAndExtractPaddingGarbage(t *testing.T) {
+	tableBytes, err := os.ReadFile("../testdata/eventlogs/ccel/cos-113-intel-tdx.table.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	elBytes, err := os.ReadFile(COS113TDX.fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank := register.RTMRBank{RTMRs: COS113TDX.rtmrs}
+	opts := extract.Opts{Loader: extract.GRUB}
+
+	parsed, err := tcg.ParseEventLog(elBytes, tcg.ParseOpts{AllowPadding: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Padding == nil || !parsed.Padding.Clean() {
+		t.Fatalf("ParseEventLog(): got padding %+v, want clean padding", parsed.Padding)
+	}
+	state, err := ReplayAndExtract(tableBytes, elBytes, bank, opts)
+	if err != nil {
+		t.Fatalf("ReplayAndExtract(): %v", err)
+	}
+	for _, finding := range state.GetFindings() {
+		if finding.GetType() == pb.FindingType_FINDING_TYPE_PADDING_GARBAGE {
+			t.Errorf("ReplayAndExtract(): got unexpected finding %v", finding)
+		}
+	}
+
+	garbageOffset := len(elBytes) - 16
+	hidden := append([]byte(nil), elBytes...)
+	copy(hidden[garbageOffset:], "hidden data")
+	state, err = ReplayAndExtract(tableBytes, hidden, bank, opts)
+	if err != nil {
+		t.Fatalf("ReplayAndExtract(hidden data in padding): %v", err)
+	}
+	var found bool
+	for _, finding := range state.GetFindings() {
+		if finding.GetType() == pb.FindingType_FINDING_TYPE_PADDING_GARBAGE {
+			found = true
+			if !strings.Contains(finding.GetDescription(), "offset "+strconv.Itoa(garbageOffset)) {
+				t.Errorf("ReplayAndExtract(hidden data in padding): got finding %q, want offset %d", finding.GetDescription(), garbageOffset)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("ReplayAndExtract(hidden data in padding): got no padding garbage finding")
+	}
+}
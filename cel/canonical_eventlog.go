@@ -5,12 +5,14 @@ package cel
 import (
 	"bytes"
 	"crypto"
+	"crypto/subtle"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/google/go-eventlog/register"
-	"github.com/google/go-tpm/legacy/tpm2"
 )
 
 // TopLevelEventType represents the CEL spec's known CELR data types for TPMS_CEL_EVENT.
@@ -38,8 +40,33 @@ const (
 
 	recnumValueLength   uint32 = 8 // support up to 2^64 records
 	regIndexValueLength uint32 = 1 // support up to 256 registers
+	// wideIndexValueLength is the longest register index accepted with
+	// QuirkWideIndex.
+	wideIndexValueLength = 4
+
+	// DefaultMaxTLVValueLength is the default maximum length of a TLV value
+	// accepted when decoding a CEL.
+	DefaultMaxTLVValueLength uint32 = 16 << 20
 )
 
+// DecodeOpts gives options for decoding a CEL.
+type DecodeOpts struct {
+	// MaxTLVValueLength bounds the length of any TLV value, and thus the
+	// allocation size for a single record field.
+	// If zero, DefaultMaxTLVValueLength is used.
+	MaxTLVValueLength uint32
+	// Quirks tolerates the encodings of other CEL-TLV producers, e.g.,
+	// TPM2ToolsQuirks.
+	Quirks Quirks
+}
+
+func (o DecodeOpts) maxTLVValueLength() uint32 {
+	if o.MaxTLVValueLength == 0 {
+		return DefaultMaxTLVValueLength
+	}
+	return o.MaxTLVValueLength
+}
+
 // MRExtender extends an implementation-specific measurement register at the
 // specified bank and index with the supplied digest.
 type MRExtender func(crypto.Hash, int, []byte) error
@@ -65,6 +92,9 @@ func (t TLV) MarshalBinary() (data []byte, err error) {
 
 // UnmarshalBinary unmarshal a byte slice to a TLV.
 func (t *TLV) UnmarshalBinary(data []byte) error {
+	if len(data) < tlvTypeFieldLength+tlvLengthFieldLength {
+		return fmt.Errorf("TLV too short: got %d bytes, want at least %d", len(data), tlvTypeFieldLength+tlvLengthFieldLength)
+	}
 	valueLength := binary.BigEndian.Uint32(data[tlvTypeFieldLength : tlvTypeFieldLength+tlvLengthFieldLength])
 
 	if valueLength != uint32(len(data[tlvTypeFieldLength+tlvLengthFieldLength:])) {
@@ -76,37 +106,32 @@ func (t *TLV) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
-// unmarshalFirstTLV reads and parse the first TLV from the bytes buffer. The function will
-// return io.EOF if the buf ends unexpectedly or cannot fill the TLV.
-func unmarshalFirstTLV(buf *bytes.Buffer) (tlv TLV, err error) {
-	typeByte, err := buf.ReadByte()
-	if err != nil {
-		return tlv, err
-	}
-	var data []byte
-	data = append(data, typeByte)
-
-	// get the length
-	lengthBytes := make([]byte, tlvLengthFieldLength)
-	bytesRead, err := buf.Read(lengthBytes)
-	if err != nil {
+// unmarshalFirstTLV reads and parses the first TLV from the reader. The
+// function returns io.EOF if the reader ends before the TLV starts, and
+// io.ErrUnexpectedEOF if it ends before the TLV is complete.
+// The value length is checked against maxValueLength, and the remaining
+// length if the reader reports it (e.g., *bytes.Buffer), before allocating.
+func unmarshalFirstTLV(r io.Reader, maxValueLength uint32) (tlv TLV, err error) {
+	header := make([]byte, tlvTypeFieldLength+tlvLengthFieldLength)
+	if _, err := io.ReadFull(r, header); err != nil {
 		return TLV{}, err
 	}
-	if bytesRead != tlvLengthFieldLength {
-		return TLV{}, io.EOF
+	valueLength := binary.BigEndian.Uint32(header[tlvTypeFieldLength:])
+	if valueLength > maxValueLength {
+		return TLV{}, fmt.Errorf("TLV value length %d exceeds the maximum %d", valueLength, maxValueLength)
+	}
+	if lr, ok := r.(interface{ Len() int }); ok && uint64(valueLength) > uint64(lr.Len()) {
+		return TLV{}, io.ErrUnexpectedEOF
 	}
-	valueLength := binary.BigEndian.Uint32(lengthBytes)
-	data = append(data, lengthBytes...)
 
-	valueBytes := make([]byte, valueLength)
-	bytesRead, err = buf.Read(valueBytes)
-	if err != nil {
+	data := make([]byte, len(header)+int(valueLength))
+	copy(data, header)
+	if _, err := io.ReadFull(r, data[len(header):]); err != nil {
+		if err == io.EOF {
+			return TLV{}, io.ErrUnexpectedEOF
+		}
 		return TLV{}, err
 	}
-	if uint32(bytesRead) != valueLength {
-		return TLV{}, io.EOF
-	}
-	data = append(data, valueBytes...)
 
 	if err = (&tlv).UnmarshalBinary(data); err != nil {
 		return TLV{}, err
@@ -227,11 +252,14 @@ func createRecNumField(recNum uint64) TLV {
 
 // UnmarshalRecNum takes in a TLV with its type equals to the recnum type value (0), and
 // return its record number.
-func unmarshalRecNum(tlv TLV) (uint64, error) {
+func unmarshalRecNum(tlv TLV, quirks Quirks) (uint64, error) {
 	if tlv.Type != uint8(recnumTypeValue) {
 		return 0, fmt.Errorf("type of the TLV [%d] indicates it is not a recnum field [%d]",
 			tlv.Type, recnumTypeValue)
 	}
+	if quirks.Has(QuirkVariableRecNum) && len(tlv.Value) > 0 && uint32(len(tlv.Value)) <= recnumValueLength {
+		return uintBigEndian(tlv.Value), nil
+	}
 	if uint32(len(tlv.Value)) != recnumValueLength {
 		return 0, fmt.Errorf(
 			"length of the value of the TLV [%d] doesn't match the defined length [%d] of value for recnum",
@@ -240,13 +268,22 @@ func unmarshalRecNum(tlv TLV) (uint64, error) {
 	return binary.BigEndian.Uint64(tlv.Value), nil
 }
 
+// uintBigEndian decodes a big-endian unsigned integer of at most 8 bytes.
+func uintBigEndian(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
 func createIndexField(indexType uint8, indexNum uint8) TLV {
 	return TLV{indexType, []byte{indexNum}}
 }
 
 // unmarshalIndex takes in a TLV with its type equals to the PCR or CCMR type value, and
 // return its index number.
-func unmarshalIndex(tlv TLV) (indexType MRType, index uint8, err error) {
+func unmarshalIndex(tlv TLV, quirks Quirks) (indexType MRType, index uint8, err error) {
 	switch tlv.Type {
 	case uint8(PCRType):
 		indexType = PCRType
@@ -256,6 +293,13 @@ func unmarshalIndex(tlv TLV) (indexType MRType, index uint8, err error) {
 		return 0, 0, fmt.Errorf("type of the TLV [%d] indicates it is not a PCR [%d] or a CCMR [%d] field ",
 			tlv.Type, uint8(PCRType), uint8(CCMRType))
 	}
+	if quirks.Has(QuirkWideIndex) && len(tlv.Value) > 0 && len(tlv.Value) <= wideIndexValueLength {
+		wide := uintBigEndian(tlv.Value)
+		if wide > 0xff {
+			return 0, 0, fmt.Errorf("register index %d exceeds the maximum 255", wide)
+		}
+		return indexType, uint8(wide), nil
+	}
 	if uint32(len(tlv.Value)) != regIndexValueLength {
 		return 0, 0, fmt.Errorf(
 			"length of the value of the TLV [%d] doesn't match the defined length [%d] of value for a register index field",
@@ -265,14 +309,27 @@ func unmarshalIndex(tlv TLV) (indexType MRType, index uint8, err error) {
 	return indexType, tlv.Value[0], nil
 }
 
+// createDigestField encodes the digests in increasing order of TPM algorithm
+// ID, so the encoding of a record is deterministic.
 func createDigestField(digestMap map[crypto.Hash][]byte) (TLV, error) {
+	hashAlgos := make([]crypto.Hash, 0, len(digestMap))
+	for hashAlgo := range digestMap {
+		hashAlgos = append(hashAlgos, hashAlgo)
+	}
+	sort.Slice(hashAlgos, func(i, j int) bool {
+		// Hashes without a TPM algorithm ID fail below.
+		a, _ := register.HashTPMAlg(hashAlgos[i])
+		b, _ := register.HashTPMAlg(hashAlgos[j])
+		return a < b
+	})
 	var buf bytes.Buffer
-	for hashAlgo, hash := range digestMap {
+	for _, hashAlgo := range hashAlgos {
+		hash := digestMap[hashAlgo]
 		if len(hash) != hashAlgo.Size() {
 			return TLV{}, fmt.Errorf("digest length [%d] doesn't match the expected length [%d] for the hash algorithm",
 				len(hash), hashAlgo.Size())
 		}
-		tpmHashAlg, err := tpm2.HashToAlgorithm(hashAlgo)
+		tpmHashAlg, err := register.HashTPMAlg(hashAlgo)
 		if err != nil {
 			return TLV{}, err
 		}
@@ -291,7 +348,8 @@ func createDigestField(digestMap map[crypto.Hash][]byte) (TLV, error) {
 
 // UnmarshalDigests takes in a TLV with its type equals to the digests type value (3), and
 // return its digests content in a map, the key is its TPM hash algorithm.
-func unmarshalDigests(tlv TLV) (digestsMap map[crypto.Hash][]byte, err error) {
+// The digests may be in any order.
+func unmarshalDigests(tlv TLV, quirks Quirks) (digestsMap map[crypto.Hash][]byte, err error) {
 	if tlv.Type != uint8(digestsTypeValue) {
 		return nil, fmt.Errorf("type of the TLV indicates it doesn't contain digests")
 	}
@@ -300,14 +358,17 @@ func unmarshalDigests(tlv TLV) (digestsMap map[crypto.Hash][]byte, err error) {
 	digestsMap = make(map[crypto.Hash][]byte)
 
 	for buf.Len() > 0 {
-		digestTLV, err := unmarshalFirstTLV(buf)
-		if err == io.EOF {
+		digestTLV, err := unmarshalFirstTLV(buf, uint32(buf.Len()))
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
 			return nil, fmt.Errorf("buffer ends unexpectedly")
 		} else if err != nil {
 			return nil, err
 		}
-		hashAlg, err := tpm2.Algorithm(digestTLV.Type).Hash()
+		hashAlg, err := register.TPMAlgHash(uint16(digestTLV.Type))
 		if err != nil {
+			if quirks.Has(QuirkUnknownDigests) {
+				continue
+			}
 			return nil, err
 		}
 		digestsMap[hashAlg] = digestTLV.Value
@@ -372,10 +433,27 @@ func (c *eventLog) EncodeCEL(buf *bytes.Buffer) error {
 // DecodeToCEL will read the buf for CEL, will return err if the buffer
 // is not complete.
 func DecodeToCEL(buf *bytes.Buffer) (CEL, error) {
+	return DecodeToCELWithOpts(buf, DecodeOpts{})
+}
+
+// DecodeToCELWithOpts is like DecodeToCEL, but allows configuring the bounds
+// applied to untrusted input.
+func DecodeToCELWithOpts(buf *bytes.Buffer, opts DecodeOpts) (CEL, error) {
+	return DecodeFrom(buf, opts)
+}
+
+// DecodeFrom reads a CEL from r until r returns io.EOF between records.
+// Short reads are handled, so r may deliver its input in chunks (e.g., from a
+// network connection). It returns an error if r ends in the middle of a
+// record.
+func DecodeFrom(r io.Reader, opts DecodeOpts) (CEL, error) {
 	var cel eventLog
-	for buf.Len() > 0 {
-		celr, err := decodeToCELR(buf)
+	for {
+		celr, err := decodeToCELR(r, opts)
 		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
 			return &eventLog{}, fmt.Errorf("buffer ends unexpectedly")
 		}
 		if err != nil {
@@ -399,62 +477,154 @@ func DecodeToCEL(buf *bytes.Buffer) (CEL, error) {
 	return &cel, nil
 }
 
-// decodeToCELR will read the buf for the next CELR, will return err if
-// failed to unmarshal a correct CELR TLV from the buffer.
-func decodeToCELR(buf *bytes.Buffer) (r Record, err error) {
-	recnum, err := unmarshalFirstTLV(buf)
+// ReadTLV reads a single TLV from r. It returns io.EOF if r ends before the
+// TLV starts, and io.ErrUnexpectedEOF if it ends before the TLV is complete.
+func ReadTLV(r io.Reader, opts DecodeOpts) (TLV, error) {
+	return unmarshalFirstTLV(r, opts.maxTLVValueLength())
+}
+
+// DecodeRecord reads a single CEL record from r, allowing a CEL to be
+// consumed incrementally. It returns io.EOF if r ends before the record
+// starts, and io.ErrUnexpectedEOF if it ends in the middle of the record.
+func DecodeRecord(r io.Reader, opts DecodeOpts) (Record, error) {
+	return decodeToCELR(r, opts)
+}
+
+// decodeToCELR will read the reader for the next CELR, will return err if
+// failed to unmarshal a correct CELR TLV from the reader.
+// It returns io.EOF only if the reader ends before the CELR starts.
+func decodeToCELR(buf io.Reader, opts DecodeOpts) (r Record, err error) {
+	maxValueLength := opts.maxTLVValueLength()
+	recnum, err := unmarshalFirstTLV(buf, maxValueLength)
 	if err != nil {
 		return Record{}, err
 	}
-	r.RecNum, err = unmarshalRecNum(recnum)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	r.RecNum, err = unmarshalRecNum(recnum, opts.Quirks)
 	if err != nil {
 		return Record{}, err
 	}
 
-	regIndex, err := unmarshalFirstTLV(buf)
+	regIndex, err := unmarshalFirstTLV(buf, maxValueLength)
 	if err != nil {
 		return Record{}, err
 	}
-	r.IndexType, r.Index, err = unmarshalIndex(regIndex)
+	r.IndexType, r.Index, err = unmarshalIndex(regIndex, opts.Quirks)
 	if err != nil {
 		return Record{}, err
 	}
 
-	digests, err := unmarshalFirstTLV(buf)
+	digests, err := unmarshalFirstTLV(buf, maxValueLength)
 	if err != nil {
 		return Record{}, err
 	}
-	r.Digests, err = unmarshalDigests(digests)
+	r.Digests, err = unmarshalDigests(digests, opts.Quirks)
 	if err != nil {
 		return Record{}, err
 	}
 
-	r.Content, err = unmarshalFirstTLV(buf)
+	r.Content, err = unmarshalFirstTLV(buf, maxValueLength)
 	if err != nil {
 		return Record{}, err
 	}
 	return r, nil
 }
 
+// DigestPolicy controls how replay handles records without a digest for the
+// hash of the register bank, e.g., the SHA-1 only records of CELs written by
+// tools that are not crypto agile.
+type DigestPolicy int
+
+// Supported digest policies.
+const (
+	// StrictDigests fails replay for records without a digest for the bank
+	// hash.
+	StrictDigests DigestPolicy = iota
+	// PaddedDigests extends a record without a digest for the bank hash with
+	// its digest for another supported hash, zero-padded to the size of the
+	// bank hash, as the Linux IMA does for banks without a matching digest.
+	// The digest with the lowest TPM algorithm ID no longer than the bank
+	// hash is used. Such records are only as collision resistant as the
+	// substituted hash, e.g., SHA-1.
+	PaddedDigests
+)
+
+// ReplayOpts gives options for replaying a CEL.
+type ReplayOpts struct {
+	// DigestPolicy controls how records without a digest for the bank hash
+	// are handled. The default is StrictDigests.
+	DigestPolicy DigestPolicy
+}
+
 // Replay takes the digests from a Canonical Event Log and carries out the
 // extend sequence for each register (PCR, RTMR) in the log. It then compares
 // the final digests against a bank of register values to see if they match.
 // make sure CEL has only one indexType event
 func (c *eventLog) Replay(regs register.MRBank) error {
+	return replayRecords(c.Recs, regs, ReplayOpts{})
+}
+
+// ReplayWithOpts is like CEL.Replay, but allows tolerating records without a
+// digest for the bank hash. See DigestPolicy.
+func ReplayWithOpts(c CEL, regs register.MRBank, opts ReplayOpts) error {
+	if c == nil {
+		return errors.New("nil CEL")
+	}
+	return replayRecords(c.Records(), regs, opts)
+}
+
+// recordDigest returns the digest of the record to extend into a bank with
+// the given hash, according to the policy.
+func recordDigest(record Record, cryptoHash crypto.Hash, policy DigestPolicy) ([]byte, error) {
+	if digest, ok := record.Digests[cryptoHash]; ok {
+		return digest, nil
+	}
+	switch policy {
+	case StrictDigests:
+		return nil, fmt.Errorf("the CEL record did not contain a %v digest", cryptoHash)
+	case PaddedDigests:
+	default:
+		return nil, fmt.Errorf("unknown digest policy %d", policy)
+	}
+	var (
+		substitute []byte
+		substAlg   uint16
+	)
+	for hash, digest := range record.Digests {
+		alg, err := register.HashTPMAlg(hash)
+		if err != nil || len(digest) > cryptoHash.Size() {
+			continue
+		}
+		if substitute == nil || alg < substAlg {
+			substitute, substAlg = digest, alg
+		}
+	}
+	if substitute == nil {
+		return nil, fmt.Errorf("the CEL record did not contain a %v digest or a shorter digest to pad", cryptoHash)
+	}
+	padded := make([]byte, cryptoHash.Size())
+	copy(padded, substitute)
+	return padded, nil
+}
+
+func replayRecords(recs []Record, regs register.MRBank, opts ReplayOpts) error {
 	cryptoHash, err := regs.CryptoHash()
 	if err != nil {
 		return err
 	}
 	replayed := make(map[uint8][]byte)
-	for _, record := range c.Recs {
+	for _, record := range recs {
 		if _, ok := replayed[record.Index]; !ok {
 			replayed[record.Index] = make([]byte, cryptoHash.Size())
 		}
 		hasher := cryptoHash.New()
-		digestsMap := record.Digests
-		digest, ok := digestsMap[cryptoHash]
-		if !ok {
-			return fmt.Errorf("the CEL record did not contain a %v digest", cryptoHash)
+		digest, err := recordDigest(record, cryptoHash, opts.DigestPolicy)
+		if err != nil {
+			return err
 		}
 		hasher.Write(replayed[record.Index])
 		hasher.Write(digest)
@@ -473,7 +643,7 @@ func (c *eventLog) Replay(regs register.MRBank) error {
 		if !ok {
 			return fmt.Errorf("the CEL contains record(s) for register %d without a matching register in the given bank to verify", replayReg)
 		}
-		if !bytes.Equal(bankDigest, replayDigest) {
+		if subtle.ConstantTimeCompare(bankDigest, replayDigest) != 1 {
 			failedReplayRegs = append(failedReplayRegs, replayReg)
 		}
 	}
@@ -501,9 +671,38 @@ func VerifyDigests(c Content, digestMap map[crypto.Hash][]byte) error {
 		if err != nil {
 			return err
 		}
-		if !bytes.Equal(generatedDigest, digest) {
+		if subtle.ConstantTimeCompare(generatedDigest, digest) != 1 {
 			return fmt.Errorf("CEL record content digest verification failed for %s", hash)
 		}
 	}
 	return nil
 }
+
+// DuplicateRecord reports a record whose content exactly repeats the record
+// immediately before it.
+type DuplicateRecord struct {
+	// RecNum is the record number of the repeated record.
+	RecNum uint64
+	// PreviousRecNum is the record number of the original record.
+	PreviousRecNum uint64
+}
+
+// ConsecutiveDuplicates returns the records that repeat the register index and
+// content of the record immediately before them, which typically indicates a
+// producer retrying an AppendEvent that had already succeeded.
+//
+// Duplicate records were still extended into the measurement registers, so
+// they must be kept for Replay. Verifiers can use the report to decide whether
+// to reject the log or to ignore the repeated content.
+func ConsecutiveDuplicates(c CEL) []DuplicateRecord {
+	var dups []DuplicateRecord
+	recs := c.Records()
+	for i := 1; i < len(recs); i++ {
+		prev, cur := recs[i-1], recs[i]
+		if cur.IndexType == prev.IndexType && cur.Index == prev.Index &&
+			cur.Content.Type == prev.Content.Type && bytes.Equal(cur.Content.Value, prev.Content.Value) {
+			dups = append(dups, DuplicateRecord{RecNum: cur.RecNum, PreviousRecNum: prev.RecNum})
+		}
+	}
+	return dups
+}
@@ -5,8 +5,10 @@ import (
 	"crypto"
 	"crypto/rand"
 	"fmt"
+	"io"
 	"reflect"
 	"testing"
+	"testing/iotest"
 
 	"github.com/google/go-eventlog/register"
 )
@@ -153,6 +155,62 @@ func TestCELReplayFailTamperedDigest(t *testing.T) {
 		[]int{2, 3}, false /*shouldSucceed*/)
 }
 
+func TestCELReplaySHA1Only(t *testing.T) {
+	rot, err := register.CreateFakeRot(measuredHashes, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Extend the SHA-256 bank with the zero-padded SHA-1 digest, as the Linux
+	// IMA does.
+	paddingExtender := func(bank crypto.Hash, mrIdx int, digest []byte) error {
+		for _, hash := range measuredHashes {
+			padded := make([]byte, hash.Size())
+			copy(padded, digest)
+			if err := rot.ExtendMR(register.FakeMR{Index: mrIdx, Digest: padded, DigestAlg: hash}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	cel := NewPCR()
+	for _, content := range []string{"a", "b", "c"} {
+		if err := cel.AppendEvent(FakeTlv{FakeEvent1, []byte(content)}, []crypto.Hash{crypto.SHA1}, 16, paddingExtender); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sha1Bank, err := rot.ReadMRs(crypto.SHA1, []int{16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cel.Replay(sha1Bank); err != nil {
+		t.Errorf("Replay(SHA-1 bank): %v", err)
+	}
+	sha256Bank, err := rot.ReadMRs(crypto.SHA256, []int{16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cel.Replay(sha256Bank); err == nil {
+		t.Errorf("Replay(SHA-256 bank): got nil, want error")
+	}
+	if err := ReplayWithOpts(cel, sha256Bank, ReplayOpts{DigestPolicy: StrictDigests}); err == nil {
+		t.Errorf("ReplayWithOpts(SHA-256 bank, StrictDigests): got nil, want error")
+	}
+	if err := ReplayWithOpts(cel, sha256Bank, ReplayOpts{DigestPolicy: PaddedDigests}); err != nil {
+		t.Errorf("ReplayWithOpts(SHA-256 bank, PaddedDigests): %v", err)
+	}
+
+	// Longer digests are not truncated.
+	sha512Only := &eventLog{Type: PCRType, Recs: []Record{{
+		IndexType: PCRType,
+		Index:     16,
+		Digests:   map[crypto.Hash][]byte{crypto.SHA512: make([]byte, crypto.SHA512.Size())},
+	}}}
+	if err := ReplayWithOpts(sha512Only, sha256Bank, ReplayOpts{DigestPolicy: PaddedDigests}); err == nil {
+		t.Errorf("ReplayWithOpts(SHA-512 only record, PaddedDigests): got nil, want error")
+	}
+}
+
 func TestCELReplayEmpty(t *testing.T) {
 	rot, err := register.CreateFakeRot(measuredHashes, 24)
 	if err != nil {
@@ -255,3 +313,147 @@ func fakeRotExtender(rot register.FakeROT) MRExtender {
 		})
 	}
 }
+
+func TestCELDigestsSHA3(t *testing.T) {
+	digests := map[crypto.Hash][]byte{
+		crypto.SHA256:   bytes.Repeat([]byte{0x01}, crypto.SHA256.Size()),
+		crypto.SHA3_384: bytes.Repeat([]byte{0x02}, crypto.SHA3_384.Size()),
+		crypto.SHA3_512: bytes.Repeat([]byte{0x03}, crypto.SHA3_512.Size()),
+	}
+	tlv, err := createDigestField(digests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := unmarshalDigests(tlv, 0)
+	if !crypto.SHA3_384.Available() {
+		// Builds that do not link SHA-3, e.g., with the eventlog_minimal
+		// tag, reject SHA-3 digests.
+		if err == nil {
+			t.Errorf("unmarshalDigests() without SHA-3: got nil, want error")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, digests) {
+		t.Errorf("unmarshalDigests(createDigestField()): got %v, want %v", got, digests)
+	}
+}
+
+func TestDecodeCELAdversarialLengths(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		opts DecodeOpts
+	}{
+		{"max uint32 length", []byte{0, 0xff, 0xff, 0xff, 0xff, 0x01}, DecodeOpts{}},
+		{"length beyond buffer", []byte{0, 0x00, 0x00, 0x01, 0x00, 0x01, 0x02}, DecodeOpts{}},
+		{"length above configured max", append([]byte{0, 0x00, 0x00, 0x00, 0x08}, make([]byte, 8)...), DecodeOpts{MaxTLVValueLength: 4}},
+		{"truncated length", []byte{0, 0x00, 0x00}, DecodeOpts{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DecodeToCELWithOpts(bytes.NewBuffer(tc.data), tc.opts); err == nil {
+				t.Errorf("DecodeToCELWithOpts(%x): got nil, want error", tc.data)
+			}
+		})
+	}
+
+	var tlv TLV
+	if err := tlv.UnmarshalBinary([]byte{1, 0}); err == nil {
+		t.Errorf("UnmarshalBinary(short): got nil, want error")
+	}
+}
+
+func TestDecodeFromChunkedReader(t *testing.T) {
+	rot, err := register.CreateFakeRot(measuredHashes, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cel := NewPCR()
+	appendFakeMREventOrFatal(t, cel, rot, 16, measuredHashes, FakeTlv{FakeEvent1, []byte("docker.io/bazel/experimental/test:latest")})
+	appendFakeMREventOrFatal(t, cel, rot, 23, measuredHashes, FakeTlv{FakeEvent2, []byte("sha256:781d8dfdd92118436bd914442c8339e653b83f6bf3c1a7a98efcfb7c4fed7483")})
+	var buf bytes.Buffer
+	if err := cel.EncodeCEL(&buf); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	readers := map[string]func() io.Reader{
+		"OneByteReader": func() io.Reader { return iotest.OneByteReader(bytes.NewReader(encoded)) },
+		"HalfReader":    func() io.Reader { return iotest.HalfReader(bytes.NewReader(encoded)) },
+		"DataErrReader": func() io.Reader { return iotest.DataErrReader(bytes.NewReader(encoded)) },
+	}
+	for name, newReader := range readers {
+		t.Run(name, func(t *testing.T) {
+			decoded, err := DecodeFrom(newReader(), DecodeOpts{})
+			if err != nil {
+				t.Fatalf("DecodeFrom(): %v", err)
+			}
+			if !reflect.DeepEqual(decoded.Records(), cel.Records()) {
+				t.Errorf("DecodeFrom(): decoded CEL doesn't equal to the original one")
+			}
+		})
+	}
+
+	truncated := iotest.OneByteReader(bytes.NewReader(encoded[:len(encoded)-1]))
+	if _, err := DecodeFrom(truncated, DecodeOpts{}); err == nil {
+		t.Errorf("DecodeFrom(truncated): got nil, want error")
+	}
+}
+
+func TestConsecutiveDuplicates(t *testing.T) {
+	rot, err := register.CreateFakeRot(measuredHashes, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cel := NewPCR()
+	event := FakeTlv{FakeEvent1, []byte("docker.io/bazel/experimental/test:latest")}
+	appendFakeMREventOrFatal(t, cel, rot, 16, measuredHashes, event)
+	appendFakeMREventOrFatal(t, cel, rot, 16, measuredHashes, event)
+	// Same content in a different register is not a duplicate.
+	appendFakeMREventOrFatal(t, cel, rot, 17, measuredHashes, event)
+	appendFakeMREventOrFatal(t, cel, rot, 17, measuredHashes, FakeTlv{FakeEvent2, []byte("other")})
+	// Non-consecutive repeats are not duplicates.
+	appendFakeMREventOrFatal(t, cel, rot, 17, measuredHashes, event)
+
+	got := ConsecutiveDuplicates(cel)
+	want := []DuplicateRecord{{RecNum: 1, PreviousRecNum: 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ConsecutiveDuplicates() = %v, want %v", got, want)
+	}
+	// Duplicates are still replayable.
+	replay(t, cel, rot, measuredHashes, []int{16, 17}, true /*shouldSucceed*/)
+}
+
+func TestRecordDescribe(t *testing.T) {
+	fake := FakeTlv{FakeEvent2, []byte("sha256:781d8dfdd92118436bd914442c8339e653b83f6bf3c1a7a98efcfb7c4fed7483")}
+	fakeTLV, err := fake.TLV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsers := map[uint8]ContentParser{
+		FakeEventType: func(tlv TLV) (Content, error) { return tlv.ParseToFakeTlv() },
+	}
+	tests := []struct {
+		name    string
+		content TLV
+		parsers map[uint8]ContentParser
+		want    string
+	}{
+		{"parsed", fakeTLV, parsers, "fake event 1 (71 bytes)"},
+		{"no parser", fakeTLV, nil, "TLV type 222 (76 bytes)"},
+		{"invalid content", TLV{FakeEventType, []byte{1}}, parsers, "TLV type 222 (1 bytes): invalid content: TLV too short: got 1 bytes, want at least 5"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := (Record{Content: tc.content}).Describe(tc.parsers); got != tc.want {
+				t.Errorf("Describe() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+	if got, want := DescribeContent(fake), "fake event 1 (71 bytes)"; got != want {
+		t.Errorf("DescribeContent() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cel
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-eventlog/register"
+)
+
+// CBOR major types used by CEL-CBOR (RFC 8949, section 3.1).
+const (
+	cborUint  = 0
+	cborBytes = 2
+	cborArray = 4
+	cborMap   = 5
+)
+
+// nvIndexTypeValue is the CEL-TLV type of NV index records, which are
+// unsupported.
+const nvIndexTypeValue = 2
+
+// EncodeCELCBOR encodes the CEL in the CEL-CBOR format of the CEL spec, as a
+// CBOR array of records. Each record is a map keyed by the CEL-TLV type of
+// its fields, so the recnum is under 0, the register index under 1 (PCR) or
+// 108 (CCMR), the digests under 3, and the content under its content type.
+// The digests are a map from TPM_ALG_ID to digest. The content of
+// pcclient_std records is a map of its event type (0) and event data (1), and
+// any other content is its TLV value as a byte string.
+//
+// The encoding is deterministic, following the core deterministic encoding
+// requirements of RFC 8949, section 4.2.1.
+func EncodeCELCBOR(c CEL) ([]byte, error) {
+	out := appendCBORHead(nil, cborArray, uint64(len(c.Records())))
+	for _, r := range c.Records() {
+		var err error
+		if out, err = r.appendCBOR(out); err != nil {
+			return nil, fmt.Errorf("record %d: %v", r.RecNum, err)
+		}
+	}
+	return out, nil
+}
+
+// DecodeCELCBOR decodes a CEL from the CEL-CBOR format. See EncodeCELCBOR.
+// Only definite-length items are accepted. As with DecodeFrom, all records
+// must use the same measurement register type.
+func DecodeCELCBOR(data []byte) (CEL, error) {
+	d := cborDecoder{data: data}
+	n, err := d.readLen(cborArray)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CEL-CBOR: %v", err)
+	}
+	cel := &eventLog{}
+	for i := uint64(0); i < n; i++ {
+		r, err := d.readRecord()
+		if err != nil {
+			return nil, fmt.Errorf("bad record %d: %v", i, err)
+		}
+		if i == 0 {
+			cel.Type = r.IndexType
+		} else if r.IndexType != cel.Type {
+			return nil, fmt.Errorf("bad record %v: found differing MR types in the CEL: got %v, expected %v", r.RecNum, r.IndexType, cel.Type)
+		}
+		cel.Recs = append(cel.Recs, r)
+	}
+	if len(d.data) != 0 {
+		return nil, fmt.Errorf("failed to parse CEL-CBOR: %d trailing bytes", len(d.data))
+	}
+	return cel, nil
+}
+
+func (r Record) appendCBOR(out []byte) ([]byte, error) {
+	switch r.IndexType {
+	case PCRType, CCMRType:
+	default:
+		return nil, fmt.Errorf("received unknown type of measurement register: %d", r.IndexType)
+	}
+	if isCBORRecordKey(uint64(r.Content.Type)) {
+		return nil, fmt.Errorf("content type %d collides with a record field", r.Content.Type)
+	}
+
+	digests := appendCBORHead(nil, cborMap, uint64(len(r.Digests)))
+	for _, hash := range sortedHashes(r.Digests) {
+		if len(r.Digests[hash]) != hash.Size() {
+			return nil, fmt.Errorf("digest length [%d] doesn't match the expected length [%d] for the hash algorithm", len(r.Digests[hash]), hash.Size())
+		}
+		alg, err := register.HashTPMAlg(hash)
+		if err != nil {
+			return nil, err
+		}
+		digests = appendCBORHead(digests, cborUint, uint64(alg))
+		digests = appendCBORBytes(digests, r.Digests[hash])
+	}
+
+	content := appendCBORBytes(nil, r.Content.Value)
+	if r.Content.Type == pcClientStdContentType {
+		pcClient, err := parsePCClientContent(r.Content.Value)
+		if err != nil {
+			return nil, err
+		}
+		content = appendCBORHead(nil, cborMap, 2)
+		content = appendCBORHead(content, cborUint, uint64(pcClientEventType))
+		content = appendCBORHead(content, cborUint, uint64(pcClient.EventType))
+		content = appendCBORHead(content, cborUint, uint64(pcClientEventData))
+		content = appendCBORBytes(content, pcClient.EventData)
+	}
+
+	fields := []struct {
+		key   uint64
+		value []byte
+	}{
+		{uint64(recnumTypeValue), appendCBORHead(nil, cborUint, r.RecNum)},
+		{uint64(r.IndexType), appendCBORHead(nil, cborUint, uint64(r.Index))},
+		{uint64(digestsTypeValue), digests},
+		{uint64(r.Content.Type), content},
+	}
+	// Keys of the same major type sort numerically in the deterministic
+	// encoding.
+	sort.Slice(fields, func(i, j int) bool { return fields[i].key < fields[j].key })
+	out = appendCBORHead(out, cborMap, uint64(len(fields)))
+	for _, f := range fields {
+		out = appendCBORHead(out, cborUint, f.key)
+		out = append(out, f.value...)
+	}
+	return out, nil
+}
+
+// isCBORRecordKey reports whether the key is that of a record field other
+// than the content.
+func isCBORRecordKey(key uint64) bool {
+	switch key {
+	case uint64(recnumTypeValue), uint64(PCRType), nvIndexTypeValue, uint64(digestsTypeValue), uint64(CCMRType):
+		return true
+	}
+	return false
+}
+
+// appendCBORHead appends the initial bytes of a CBOR data item with the
+// shortest encoding of the argument.
+func appendCBORHead(out []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return append(out, major<<5|byte(arg))
+	case arg <= 0xff:
+		return append(out, major<<5|24, byte(arg))
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(out, major<<5|25), uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(out, major<<5|26), uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64(append(out, major<<5|27), arg)
+}
+
+func appendCBORBytes(out []byte, b []byte) []byte {
+	return append(appendCBORHead(out, cborBytes, uint64(len(b))), b...)
+}
+
+// cborDecoder reads the CBOR data items of CEL-CBOR from the front of data.
+type cborDecoder struct {
+	data []byte
+}
+
+var errCBOREnd = errors.New("CBOR ends unexpectedly")
+
+// readHead reads the initial bytes of a data item and returns its major type
+// and argument. Indefinite lengths, and the simple and float types, are not
+// used by CEL-CBOR and are rejected.
+func (d *cborDecoder) readHead() (byte, uint64, error) {
+	if len(d.data) == 0 {
+		return 0, 0, errCBOREnd
+	}
+	major, info := d.data[0]>>5, d.data[0]&0x1f
+	d.data = d.data[1:]
+	if info < 24 {
+		return major, uint64(info), nil
+	}
+	if info > 27 {
+		return 0, 0, fmt.Errorf("unsupported CBOR additional information %d", info)
+	}
+	size := 1 << (info - 24)
+	if len(d.data) < size {
+		return 0, 0, errCBOREnd
+	}
+	var arg uint64
+	for _, b := range d.data[:size] {
+		arg = arg<<8 | uint64(b)
+	}
+	d.data = d.data[size:]
+	return major, arg, nil
+}
+
+func (d *cborDecoder) readUint() (uint64, error) {
+	major, arg, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if major != cborUint {
+		return 0, fmt.Errorf("got CBOR major type %d, want an unsigned integer", major)
+	}
+	return arg, nil
+}
+
+func (d *cborDecoder) readBytes() ([]byte, error) {
+	major, arg, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+	if major != cborBytes {
+		return nil, fmt.Errorf("got CBOR major type %d, want a byte string", major)
+	}
+	if arg > uint64(len(d.data)) {
+		return nil, errCBOREnd
+	}
+	b := d.data[:arg:arg]
+	d.data = d.data[arg:]
+	return b, nil
+}
+
+// readLen reads the head of an array or map and returns its number of
+// elements or pairs. The count is checked against the remaining data, as
+// each item takes at least one byte.
+func (d *cborDecoder) readLen(major byte) (uint64, error) {
+	got, arg, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if got != major {
+		return 0, fmt.Errorf("got CBOR major type %d, want %d", got, major)
+	}
+	if arg > uint64(len(d.data)) {
+		return 0, errCBOREnd
+	}
+	return arg, nil
+}
+
+func (d *cborDecoder) readRecord() (Record, error) {
+	n, err := d.readLen(cborMap)
+	if err != nil {
+		return Record{}, err
+	}
+	var (
+		r                                           Record
+		hasRecNum, hasIndex, hasDigests, hasContent bool
+	)
+	seen := make(map[uint64]bool)
+	for i := uint64(0); i < n; i++ {
+		key, err := d.readUint()
+		if err != nil {
+			return Record{}, err
+		}
+		if seen[key] {
+			return Record{}, fmt.Errorf("duplicate record field %d", key)
+		}
+		seen[key] = true
+		switch key {
+		case uint64(recnumTypeValue):
+			r.RecNum, err = d.readUint()
+			hasRecNum = true
+		case uint64(PCRType), uint64(CCMRType):
+			if hasIndex {
+				return Record{}, errors.New("record must have exactly one of pcr or ccmr")
+			}
+			var index uint64
+			if index, err = d.readUint(); err == nil && index > 0xff {
+				err = fmt.Errorf("register index %d out of range", index)
+			}
+			r.IndexType, r.Index = MRType(key), uint8(index)
+			hasIndex = true
+		case uint64(digestsTypeValue):
+			r.Digests, err = d.readDigests()
+			hasDigests = true
+		case nvIndexTypeValue:
+			return Record{}, errors.New("NV index records are unsupported")
+		default:
+			if hasContent {
+				return Record{}, errors.New("record has more than one content")
+			}
+			if key > 0xff {
+				return Record{}, fmt.Errorf("content type %d out of range", key)
+			}
+			r.Content.Type = uint8(key)
+			r.Content.Value, err = d.readContent(r.Content.Type)
+			hasContent = true
+		}
+		if err != nil {
+			return Record{}, err
+		}
+	}
+	if !hasRecNum || !hasIndex || !hasDigests || !hasContent {
+		return Record{}, errors.New("record must have a recnum, a register index, digests, and content")
+	}
+	return r, nil
+}
+
+func (d *cborDecoder) readDigests() (map[crypto.Hash][]byte, error) {
+	n, err := d.readLen(cborMap)
+	if err != nil {
+		return nil, err
+	}
+	digests := make(map[crypto.Hash][]byte)
+	for i := uint64(0); i < n; i++ {
+		alg, err := d.readUint()
+		if err != nil {
+			return nil, err
+		}
+		if alg > 0xffff {
+			return nil, fmt.Errorf("hash algorithm %d out of range", alg)
+		}
+		hash, err := register.TPMAlgHash(uint16(alg))
+		if err != nil {
+			return nil, err
+		}
+		digest, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		if len(digest) != hash.Size() {
+			return nil, fmt.Errorf("digest length [%d] doesn't match the expected length [%d] for the hash algorithm", len(digest), hash.Size())
+		}
+		if _, ok := digests[hash]; ok {
+			return nil, fmt.Errorf("found more than one digest for hash algorithm %d", alg)
+		}
+		digests[hash] = digest
+	}
+	return digests, nil
+}
+
+func (d *cborDecoder) readContent(contentType uint8) ([]byte, error) {
+	if contentType != pcClientStdContentType {
+		return d.readBytes()
+	}
+	n, err := d.readLen(cborMap)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		content          pcClientContent
+		hasType, hasData bool
+	)
+	for i := uint64(0); i < n; i++ {
+		key, err := d.readUint()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case key == uint64(pcClientEventType) && !hasType:
+			eventType, err := d.readUint()
+			if err != nil {
+				return nil, err
+			}
+			if eventType > 0xffffffff {
+				return nil, fmt.Errorf("pcclient_std event type %d out of range", eventType)
+			}
+			content.EventType = uint32(eventType)
+			hasType = true
+		case key == uint64(pcClientEventData) && !hasData:
+			if content.EventData, err = d.readBytes(); err != nil {
+				return nil, err
+			}
+			hasData = true
+		default:
+			return nil, fmt.Errorf("unexpected pcclient_std field %d", key)
+		}
+	}
+	if !hasType || !hasData {
+		return nil, errors.New("pcclient_std content must have an event type and event data")
+	}
+	return content.tlvValue()
+}
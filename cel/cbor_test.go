@@ -0,0 +1,169 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cel
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-eventlog/register"
+)
+
+func TestCELCBORRoundTrip(t *testing.T) {
+	for _, tc := range []MRType{PCRType, CCMRType} {
+		t.Run(fmt.Sprintf("MRType %v", tc), func(t *testing.T) {
+			rot, err := register.CreateFakeRot(measuredHashes, 24)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cel := &eventLog{Type: tc}
+			appendFakeMREventOrFatal(t, cel, rot, 16, measuredHashes, FakeTlv{FakeEvent1, []byte("docker.io/bazel/experimental/test:latest")})
+			appendFakeMREventOrFatal(t, cel, rot, 23, measuredHashes, FakeTlv{FakeEvent2, []byte("sha256:781d8dfdd92118436bd914442c8339e653b83f6bf3c1a7a98efcfb7c4fed7483")})
+
+			// Go from TLV to CBOR and back to TLV.
+			var buf bytes.Buffer
+			if err := cel.EncodeCEL(&buf); err != nil {
+				t.Fatal(err)
+			}
+			tlvDecoded, err := DecodeToCEL(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := EncodeCELCBOR(tlvDecoded)
+			if err != nil {
+				t.Fatalf("EncodeCELCBOR(): %v", err)
+			}
+			decoded, err := DecodeCELCBOR(data)
+			if err != nil {
+				t.Fatalf("DecodeCELCBOR(): %v", err)
+			}
+			if decoded.MRType() != tc {
+				t.Errorf("DecodeCELCBOR(): got MR type %v, want %v", decoded.MRType(), tc)
+			}
+			if !reflect.DeepEqual(decoded.Records(), cel.Records()) {
+				t.Errorf("DecodeCELCBOR(): decoded CEL doesn't equal the original one")
+			}
+			buf.Reset()
+			if err := decoded.EncodeCEL(&buf); err != nil {
+				t.Fatal(err)
+			}
+			retlv, err := DecodeToCEL(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(retlv.Records(), cel.Records()) {
+				t.Errorf("DecodeToCEL(): records differ after the CBOR round trip")
+			}
+			replay(t, decoded, rot, measuredHashes, []int{16, 23}, true /*shouldSucceed*/)
+
+			// The encoding is deterministic.
+			again, err := EncodeCELCBOR(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(again, data) {
+				t.Errorf("EncodeCELCBOR() = %x, want %x", again, data)
+			}
+		})
+	}
+}
+
+func TestEncodeCELCBOR(t *testing.T) {
+	digest := bytes.Repeat([]byte{0xaa}, crypto.SHA256.Size())
+	cel := &eventLog{Type: PCRType, Recs: []Record{{
+		RecNum:    0,
+		Index:     7,
+		IndexType: PCRType,
+		Digests:   map[crypto.Hash][]byte{crypto.SHA256: digest},
+		Content:   TLV{FakeEventType, []byte("ab")},
+	}}}
+	data, err := EncodeCELCBOR(cel)
+	if err != nil {
+		t.Fatalf("EncodeCELCBOR(): %v", err)
+	}
+	// [{0: 0, 1: 7, 3: {11: h'aa...'}, 222: h'6162'}]
+	want := "81a4" + "0000" + "0107" + "03a10b5820" + hex.EncodeToString(digest) + "18de426162"
+	if got := hex.EncodeToString(data); got != want {
+		t.Errorf("EncodeCELCBOR() = %s, want %s", got, want)
+	}
+}
+
+func TestCELCBORPCClient(t *testing.T) {
+	decoded, err := DecodeCELJSON([]byte(pcClientJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := EncodeCELCBOR(decoded)
+	if err != nil {
+		t.Fatalf("EncodeCELCBOR(): %v", err)
+	}
+	// The content is {0: 8, 1: h'0000'}.
+	if want := "05a2000801420000"; !strings.HasSuffix(hex.EncodeToString(data), want) {
+		t.Errorf("EncodeCELCBOR() = %x, want pcclient_std content %s", data, want)
+	}
+	cborDecoded, err := DecodeCELCBOR(data)
+	if err != nil {
+		t.Fatalf("DecodeCELCBOR(): %v", err)
+	}
+	if !reflect.DeepEqual(cborDecoded.Records(), decoded.Records()) {
+		t.Errorf("DecodeCELCBOR(): records differ from the CEL-JSON records")
+	}
+}
+
+func TestDecodeCELCBORFails(t *testing.T) {
+	sha1Digest := "a1" + "04" + "54" + strings.Repeat("00", 20)
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"not an array", "a0"},
+		{"indefinite length", "9fff"},
+		{"array count exceeds data", "9a7fffffff"},
+		{"trailing bytes", "8000"},
+		{"no register", "81a3" + "0000" + "03a0" + "18de40"},
+		{"two registers", "81a5" + "0000" + "0101" + "03a0" + "186c01" + "18de40"},
+		{"register index out of range", "81a4" + "0000" + "01190100" + "03a0" + "18de40"},
+		{"NV index", "81a4" + "0000" + "0201" + "03a0" + "18de40"},
+		{"duplicate field", "81a5" + "0000" + "0000" + "0101" + "03a0" + "18de40"},
+		{"two contents", "81a5" + "0000" + "0101" + "03a0" + "18de40" + "18df40"},
+		{"unknown hash", "81a4" + "0000" + "0101" + "03a1" + "0141" + "00" + "18de40"},
+		{"short digest", "81a4" + "0000" + "0101" + "03a1" + "0441" + "00" + "18de40"},
+		{"truncated digest", "81a4" + "0000" + "0101" + "03a1" + "0454" + "00"},
+		{"content not bytes", "81a4" + "0000" + "0101" + "03a0" + "18de00"},
+		{"bad pcclient_std content", "81a4" + "0000" + "0101" + "03a0" + "05a10008"},
+		{"differing MR types", "82" + "a4" + "0000" + "0101" + "03a0" + "18de40" + "a4" + "0001" + "186c01" + "03a0" + "18de40"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := DecodeCELCBOR(data); err == nil {
+				t.Errorf("DecodeCELCBOR(): got nil, want error")
+			}
+		})
+	}
+	// A well-formed record with the same fields decodes.
+	ok, _ := hex.DecodeString("81a4" + "0000" + "0101" + "03" + sha1Digest + "18de40")
+	if _, err := DecodeCELCBOR(ok); err != nil {
+		t.Errorf("DecodeCELCBOR(): %v", err)
+	}
+}
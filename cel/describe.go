@@ -0,0 +1,61 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cel
+
+import "fmt"
+
+// Describer is an optional interface for Content with a human-readable
+// description, so rendering tools can display record summaries without
+// knowing the content type.
+type Describer interface {
+	// Describe returns a short, single-line description of the content.
+	Describe() string
+}
+
+// ContentParser parses the content TLV of a record into Content.
+type ContentParser func(TLV) (Content, error)
+
+// DescribeContent returns the description of the content if it implements
+// Describer, or its TLV type and length otherwise.
+func DescribeContent(content Content) string {
+	if d, ok := content.(Describer); ok {
+		return d.Describe()
+	}
+	tlv, err := content.TLV()
+	if err != nil {
+		return fmt.Sprintf("invalid content: %v", err)
+	}
+	return describeTLV(tlv)
+}
+
+// Describe returns a description of the record content. The parser for the
+// content TLV type in parsers, if any, parses the content, whose description
+// is used if it implements Describer. Otherwise, the description gives the
+// TLV type and length.
+func (r Record) Describe(parsers map[uint8]ContentParser) string {
+	parse, ok := parsers[r.Content.Type]
+	if !ok {
+		return describeTLV(r.Content)
+	}
+	content, err := parse(r.Content)
+	if err != nil {
+		return fmt.Sprintf("%v: invalid content: %v", describeTLV(r.Content), err)
+	}
+	return DescribeContent(content)
+}
+
+func describeTLV(tlv TLV) string {
+	return fmt.Sprintf("TLV type %d (%d bytes)", tlv.Type, len(tlv.Value))
+}
@@ -40,6 +40,11 @@ func (f FakeTlv) TLV() (TLV, error) {
 	}, nil
 }
 
+// Describe returns the fake event type and content length.
+func (f FakeTlv) Describe() string {
+	return fmt.Sprintf("fake event %d (%d bytes)", f.EventType, len(f.EventContent))
+}
+
 // GenerateDigest generates the digest for the given fake TLV. The whole TLV struct will
 // be marshaled to bytes and feed into the hash algo.
 func (f FakeTlv) GenerateDigest(hashAlgo crypto.Hash) ([]byte, error) {
@@ -0,0 +1,302 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cel
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Content types defined by the CEL spec, used to name the content of records
+// in CEL-JSON.
+const (
+	celMgtContentType      uint8 = 4
+	pcClientStdContentType uint8 = 5
+	imaTemplateContentType uint8 = 7
+	imaTLVContentType      uint8 = 8
+
+	// Nested TLV types of pcclient_std content.
+	pcClientEventType uint8 = 0
+	pcClientEventData uint8 = 1
+)
+
+var contentTypeNames = map[uint8]string{
+	celMgtContentType:      "cel",
+	pcClientStdContentType: "pcclient_std",
+	imaTemplateContentType: "ima_template",
+	imaTLVContentType:      "ima_tlv",
+}
+
+// hashAlgNames are the CEL-JSON names of the TCG hash algorithms.
+var hashAlgNames = map[crypto.Hash]string{
+	crypto.SHA1:     "sha1",
+	crypto.SHA256:   "sha256",
+	crypto.SHA384:   "sha384",
+	crypto.SHA512:   "sha512",
+	crypto.SHA3_256: "sha3_256",
+	crypto.SHA3_384: "sha3_384",
+	crypto.SHA3_512: "sha3_512",
+}
+
+// jsonRecord is the CEL-JSON form of a Record. Records of confidential
+// computing measurement registers, which the CEL spec does not define, use
+// the "ccmr" key in place of "pcr".
+type jsonRecord struct {
+	RecNum      uint64          `json:"recnum"`
+	PCR         *uint8          `json:"pcr,omitempty"`
+	CCMR        *uint8          `json:"ccmr,omitempty"`
+	Digests     []jsonDigest    `json:"digests"`
+	ContentType jsonContentType `json:"content_type"`
+	Content     json.RawMessage `json:"content"`
+}
+
+type jsonDigest struct {
+	HashAlg string `json:"hashAlg"`
+	// Digest is hex encoded.
+	Digest string `json:"digest"`
+}
+
+// pcClientContent is the parsed form of pcclient_std content.
+type pcClientContent struct {
+	EventType uint32 `json:"event_type"`
+	EventData []byte `json:"event_data"`
+}
+
+// jsonContentType is the content TLV type of a record. It is encoded as the
+// CEL spec name of the type if it has one, and as a number otherwise.
+type jsonContentType uint8
+
+func (t jsonContentType) MarshalJSON() ([]byte, error) {
+	if name, ok := contentTypeNames[uint8(t)]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(uint8(t))
+}
+
+func (t *jsonContentType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var num uint8
+		if err := json.Unmarshal(data, &num); err != nil {
+			return fmt.Errorf("content_type must be a name or a number up to 255: %s", data)
+		}
+		*t = jsonContentType(num)
+		return nil
+	}
+	for typ, n := range contentTypeNames {
+		if n == name {
+			*t = jsonContentType(typ)
+			return nil
+		}
+	}
+	// Accept numbers quoted as strings.
+	num, err := strconv.ParseUint(name, 10, 8)
+	if err != nil {
+		return fmt.Errorf("unknown content_type %q", name)
+	}
+	*t = jsonContentType(num)
+	return nil
+}
+
+// EncodeCELJSON encodes the CEL in the CEL-JSON format of the CEL spec, as a
+// JSON array of records. Digests are hex encoded. The content of pcclient_std
+// records is encoded as its event type and base64 event data, and any other
+// content as its base64 TLV value.
+func EncodeCELJSON(c CEL) ([]byte, error) {
+	records := make([]jsonRecord, 0, len(c.Records()))
+	for _, r := range c.Records() {
+		jr, err := r.jsonRecord()
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %v", r.RecNum, err)
+		}
+		records = append(records, jr)
+	}
+	return json.Marshal(records)
+}
+
+// DecodeCELJSON decodes a CEL from the CEL-JSON format. See EncodeCELJSON.
+// As with DecodeFrom, all records must use the same measurement register
+// type.
+func DecodeCELJSON(data []byte) (CEL, error) {
+	var records []jsonRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse CEL-JSON: %v", err)
+	}
+	cel := &eventLog{}
+	for i, jr := range records {
+		r, err := jr.record()
+		if err != nil {
+			return nil, fmt.Errorf("bad record %d: %v", i, err)
+		}
+		if i == 0 {
+			cel.Type = r.IndexType
+		} else if r.IndexType != cel.Type {
+			return nil, fmt.Errorf("bad record %v: found differing MR types in the CEL: got %v, expected %v", r.RecNum, r.IndexType, cel.Type)
+		}
+		cel.Recs = append(cel.Recs, r)
+	}
+	return cel, nil
+}
+
+func (r Record) jsonRecord() (jsonRecord, error) {
+	jr := jsonRecord{RecNum: r.RecNum, ContentType: jsonContentType(r.Content.Type)}
+	index := r.Index
+	switch r.IndexType {
+	case PCRType:
+		jr.PCR = &index
+	case CCMRType:
+		jr.CCMR = &index
+	default:
+		return jsonRecord{}, fmt.Errorf("received unknown type of measurement register: %d", r.IndexType)
+	}
+	for _, hash := range sortedHashes(r.Digests) {
+		name, ok := hashAlgNames[hash]
+		if !ok {
+			return jsonRecord{}, fmt.Errorf("hash algorithm %v has no CEL-JSON name", hash)
+		}
+		jr.Digests = append(jr.Digests, jsonDigest{HashAlg: name, Digest: hex.EncodeToString(r.Digests[hash])})
+	}
+
+	var content any = r.Content.Value
+	if r.Content.Type == pcClientStdContentType {
+		pcClient, err := parsePCClientContent(r.Content.Value)
+		if err != nil {
+			return jsonRecord{}, err
+		}
+		content = pcClient
+	}
+	var err error
+	jr.Content, err = json.Marshal(content)
+	return jr, err
+}
+
+func (jr jsonRecord) record() (Record, error) {
+	r := Record{RecNum: jr.RecNum, Digests: make(map[crypto.Hash][]byte)}
+	switch {
+	case jr.PCR != nil && jr.CCMR == nil:
+		r.IndexType, r.Index = PCRType, *jr.PCR
+	case jr.CCMR != nil && jr.PCR == nil:
+		r.IndexType, r.Index = CCMRType, *jr.CCMR
+	default:
+		return Record{}, fmt.Errorf("record must have exactly one of pcr or ccmr")
+	}
+	for _, d := range jr.Digests {
+		hash, err := parseHashAlgName(d.HashAlg)
+		if err != nil {
+			return Record{}, err
+		}
+		digest, err := hex.DecodeString(d.Digest)
+		if err != nil {
+			return Record{}, fmt.Errorf("bad %s digest: %v", d.HashAlg, err)
+		}
+		if len(digest) != hash.Size() {
+			return Record{}, fmt.Errorf("digest length [%d] doesn't match the expected length [%d] for %s", len(digest), hash.Size(), d.HashAlg)
+		}
+		if _, ok := r.Digests[hash]; ok {
+			return Record{}, fmt.Errorf("found more than one %s digest", d.HashAlg)
+		}
+		r.Digests[hash] = digest
+	}
+
+	r.Content.Type = uint8(jr.ContentType)
+	if r.Content.Type == pcClientStdContentType {
+		var pcClient pcClientContent
+		if err := json.Unmarshal(jr.Content, &pcClient); err != nil {
+			return Record{}, fmt.Errorf("bad pcclient_std content: %v", err)
+		}
+		value, err := pcClient.tlvValue()
+		if err != nil {
+			return Record{}, err
+		}
+		r.Content.Value = value
+		return r, nil
+	}
+	if err := json.Unmarshal(jr.Content, &r.Content.Value); err != nil {
+		return Record{}, fmt.Errorf("bad content: %v", err)
+	}
+	return r, nil
+}
+
+// parsePCClientContent parses the nested event type and event data TLVs of
+// pcclient_std content.
+func parsePCClientContent(value []byte) (pcClientContent, error) {
+	var (
+		content          pcClientContent
+		hasType, hasData bool
+	)
+	buf := bytes.NewBuffer(value)
+	for buf.Len() > 0 {
+		field, err := unmarshalFirstTLV(buf, uint32(buf.Len()))
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return pcClientContent{}, fmt.Errorf("pcclient_std content ends unexpectedly")
+		} else if err != nil {
+			return pcClientContent{}, err
+		}
+		switch {
+		case field.Type == pcClientEventType && !hasType && len(field.Value) == 4:
+			content.EventType = binary.BigEndian.Uint32(field.Value)
+			hasType = true
+		case field.Type == pcClientEventData && !hasData:
+			content.EventData = field.Value
+			hasData = true
+		default:
+			return pcClientContent{}, fmt.Errorf("unexpected pcclient_std field of type %d (%d bytes)", field.Type, len(field.Value))
+		}
+	}
+	if !hasType || !hasData {
+		return pcClientContent{}, fmt.Errorf("pcclient_std content must have an event type and event data")
+	}
+	return content, nil
+}
+
+// tlvValue encodes the pcclient_std content as nested TLVs.
+func (c pcClientContent) tlvValue() ([]byte, error) {
+	eventType, err := TLV{pcClientEventType, binary.BigEndian.AppendUint32(nil, c.EventType)}.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	eventData, err := TLV{pcClientEventData, c.EventData}.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(eventType, eventData...), nil
+}
+
+func parseHashAlgName(name string) (crypto.Hash, error) {
+	for hash, n := range hashAlgNames {
+		if n == name {
+			return hash, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown hashAlg %q", name)
+}
+
+// sortedHashes returns the hash algorithms of the digests in increasing
+// order, so encodings are deterministic.
+func sortedHashes(digests map[crypto.Hash][]byte) []crypto.Hash {
+	hashes := make([]crypto.Hash, 0, len(digests))
+	for hash := range digests {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+	return hashes
+}
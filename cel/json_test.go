@@ -0,0 +1,156 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cel
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-eventlog/register"
+)
+
+func TestCELJSONRoundTrip(t *testing.T) {
+	for _, tc := range []MRType{PCRType, CCMRType} {
+		t.Run(fmt.Sprintf("MRType %v", tc), func(t *testing.T) {
+			rot, err := register.CreateFakeRot(measuredHashes, 24)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cel := &eventLog{Type: tc}
+			appendFakeMREventOrFatal(t, cel, rot, 16, measuredHashes, FakeTlv{FakeEvent1, []byte("docker.io/bazel/experimental/test:latest")})
+			appendFakeMREventOrFatal(t, cel, rot, 23, measuredHashes, FakeTlv{FakeEvent2, []byte("sha256:781d8dfdd92118436bd914442c8339e653b83f6bf3c1a7a98efcfb7c4fed7483")})
+
+			data, err := EncodeCELJSON(cel)
+			if err != nil {
+				t.Fatalf("EncodeCELJSON(): %v", err)
+			}
+			decoded, err := DecodeCELJSON(data)
+			if err != nil {
+				t.Fatalf("DecodeCELJSON(): %v", err)
+			}
+			if decoded.MRType() != tc {
+				t.Errorf("DecodeCELJSON(): got MR type %v, want %v", decoded.MRType(), tc)
+			}
+			if !reflect.DeepEqual(decoded.Records(), cel.Records()) {
+				t.Errorf("DecodeCELJSON(): decoded CEL doesn't equal the original one")
+			}
+			replay(t, decoded, rot, measuredHashes, []int{16, 23}, true /*shouldSucceed*/)
+		})
+	}
+}
+
+// pcClientJSON is a CEL-JSON log in the form produced by other tooling, with
+// a pcclient_std record measuring the EV_S_CRTM_VERSION event.
+const pcClientJSON = `[{
+  "recnum": 0,
+  "pcr": 0,
+  "digests": [
+    {"hashAlg": "sha256", "digest": "4cd5f1d3a7d7f4e2b7a2ea1b2b9ddbd6b91ee77ea0bdb58e7bdb0b0a0ec4c1a0"},
+    {"hashAlg": "sha1", "digest": "c42fedad268200cb1d15f97841c344e79dae3320"}
+  ],
+  "content_type": "pcclient_std",
+  "content": {"event_type": 8, "event_data": "AAA="}
+}]`
+
+func TestDecodeCELJSONPCClient(t *testing.T) {
+	decoded, err := DecodeCELJSON([]byte(pcClientJSON))
+	if err != nil {
+		t.Fatalf("DecodeCELJSON(): %v", err)
+	}
+	if len(decoded.Records()) != 1 {
+		t.Fatalf("DecodeCELJSON(): got %d records, want 1", len(decoded.Records()))
+	}
+	rec := decoded.Records()[0]
+	if decoded.MRType() != PCRType || rec.Index != 0 || len(rec.Digests) != 2 {
+		t.Errorf("DecodeCELJSON(): got record %+v in MR type %v", rec, decoded.MRType())
+	}
+	wantContent := TLV{pcClientStdContentType, []byte{
+		pcClientEventType, 0, 0, 0, 4, 0, 0, 0, 8,
+		pcClientEventData, 0, 0, 0, 2, 0, 0,
+	}}
+	if !reflect.DeepEqual(rec.Content, wantContent) {
+		t.Errorf("DecodeCELJSON(): got content %+v, want %+v", rec.Content, wantContent)
+	}
+
+	// Encoding gives back the same log.
+	data, err := EncodeCELJSON(decoded)
+	if err != nil {
+		t.Fatalf("EncodeCELJSON(): %v", err)
+	}
+	var got, want any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(pcClientJSON), &want); err != nil {
+		t.Fatal(err)
+	}
+	// Digests are encoded in a fixed order.
+	wantDigests := want.([]any)[0].(map[string]any)["digests"].([]any)
+	wantDigests[0], wantDigests[1] = wantDigests[1], wantDigests[0]
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeCELJSON() = %s, want %s", data, pcClientJSON)
+	}
+
+	// The TLV encoding of the decoded log is decoded to the same records.
+	var buf bytes.Buffer
+	if err := decoded.EncodeCEL(&buf); err != nil {
+		t.Fatal(err)
+	}
+	tlvDecoded, err := DecodeToCEL(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tlvDecoded.Records(), decoded.Records()) {
+		t.Errorf("DecodeToCEL(): records differ from the CEL-JSON records")
+	}
+}
+
+func TestDecodeCELJSONFails(t *testing.T) {
+	sha1Digest := `{"hashAlg": "sha1", "digest": "c42fedad268200cb1d15f97841c344e79dae3320"}`
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{"not an array", `{}`},
+		{"no register", `[{"recnum": 0, "digests": [], "content_type": 222, "content": ""}]`},
+		{"two registers", `[{"recnum": 0, "pcr": 1, "ccmr": 1, "digests": [], "content_type": 222, "content": ""}]`},
+		{"unknown hash", `[{"recnum": 0, "pcr": 1, "digests": [{"hashAlg": "md5", "digest": "00"}], "content_type": 222, "content": ""}]`},
+		{"short digest", `[{"recnum": 0, "pcr": 1, "digests": [{"hashAlg": "sha1", "digest": "c42f"}], "content_type": 222, "content": ""}]`},
+		{"duplicate digest", `[{"recnum": 0, "pcr": 1, "digests": [` + sha1Digest + `,` + sha1Digest + `], "content_type": 222, "content": ""}]`},
+		{"unknown content type", `[{"recnum": 0, "pcr": 1, "digests": [], "content_type": "systemd", "content": ""}]`},
+		{"bad pcclient_std content", `[{"recnum": 0, "pcr": 1, "digests": [], "content_type": "pcclient_std", "content": "AAA="}]`},
+		{"differing MR types", `[{"recnum": 0, "pcr": 1, "digests": [], "content_type": 222, "content": ""}, {"recnum": 1, "ccmr": 1, "digests": [], "content_type": 222, "content": ""}]`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DecodeCELJSON([]byte(tc.data)); err == nil {
+				t.Errorf("DecodeCELJSON(): got nil, want error")
+			}
+		})
+	}
+}
+
+func TestEncodeCELJSONUnknownHash(t *testing.T) {
+	cel := &eventLog{Type: PCRType, Recs: []Record{{
+		IndexType: PCRType,
+		Digests:   map[crypto.Hash][]byte{crypto.MD5: make([]byte, crypto.MD5.Size())},
+	}}}
+	if _, err := EncodeCELJSON(cel); err == nil {
+		t.Errorf("EncodeCELJSON(): got nil, want error for a hash without a CEL-JSON name")
+	}
+}
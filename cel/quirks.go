@@ -0,0 +1,77 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cel
+
+import "github.com/google/go-eventlog/internal/quirkset"
+
+// Quirks is a set of decoding tolerances for CEL-TLV logs written by other
+// implementations, e.g., tpm2-tools and TSS-based producers, which encode
+// some record fields differently from this package. Quirks only relax
+// decoding: Replay still verifies every digest that was decoded.
+type Quirks uint32
+
+// Supported quirks.
+const (
+	// QuirkVariableRecNum accepts recnum fields of 1 to 8 bytes, holding the
+	// record number in big-endian with leading zero bytes omitted, rather
+	// than only 8 bytes.
+	QuirkVariableRecNum Quirks = 1 << iota
+	// QuirkWideIndex accepts register index fields of up to 4 bytes, e.g., a
+	// big-endian uint32 PCR index, rather than only 1 byte. The index must
+	// still be less than 256.
+	QuirkWideIndex
+	// QuirkUnknownDigests skips digests with a TPM algorithm ID this package
+	// does not support, e.g., SM3-256, rather than failing to decode the
+	// record. Replay fails for banks of the skipped algorithms.
+	QuirkUnknownDigests
+)
+
+// TPM2ToolsQuirks are the quirks enabled for CEL-TLV logs written by
+// tpm2-tools and TSS-based producers.
+const TPM2ToolsQuirks = QuirkVariableRecNum | QuirkWideIndex | QuirkUnknownDigests
+
+var quirkNames = []quirkset.Name[Quirks]{
+	{Quirk: QuirkVariableRecNum, Name: "variable-recnum"},
+	{Quirk: QuirkWideIndex, Name: "wide-index"},
+	{Quirk: QuirkUnknownDigests, Name: "unknown-digests"},
+}
+
+var quirkSets = map[string]Quirks{
+	"tpm2-tools": TPM2ToolsQuirks,
+}
+
+// QuirkSet returns the named quirk set for a CEL producer, e.g.,
+// "tpm2-tools", for configuring quirks by name.
+func QuirkSet(name string) (Quirks, error) {
+	return quirkset.Lookup(quirkSets, "CEL quirk set", name)
+}
+
+// Has reports whether all of the quirks in q are in the set.
+func (s Quirks) Has(q Quirks) bool {
+	return s&q == q
+}
+
+// Names returns the names of the quirks in the set, e.g.,
+// ["variable-recnum", "wide-index"]. Unknown quirks are named by their hex
+// value.
+func (s Quirks) Names() []string {
+	return quirkset.Names(s, quirkNames)
+}
+
+// String returns the names of the quirks in the set, e.g.,
+// "variable-recnum|wide-index".
+func (s Quirks) String() string {
+	return quirkset.String(s, quirkNames)
+}
@@ -0,0 +1,205 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cel
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/testdata"
+)
+
+// algSM3_256 is the TPM algorithm ID of SM3-256, which this package does not
+// support.
+const algSM3_256 = 0x0012
+
+// encodeTPM2ToolsCEL encodes the records in the style of tpm2-tools and
+// TSS-based producers: minimal-width recnums, 4-byte register indexes, and
+// digests in decreasing order of algorithm ID, with an extra SM3-256 digest.
+func encodeTPM2ToolsCEL(t *testing.T, recs []Record) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writeTLV := func(tlv TLV) {
+		data, err := tlv.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(data)
+	}
+	for _, rec := range recs {
+		recnum := binary.BigEndian.AppendUint64(nil, rec.RecNum)
+		for len(recnum) > 1 && recnum[0] == 0 {
+			recnum = recnum[1:]
+		}
+		writeTLV(TLV{uint8(recnumTypeValue), recnum})
+		writeTLV(TLV{uint8(rec.IndexType), binary.BigEndian.AppendUint32(nil, uint32(rec.Index))})
+
+		var digests bytes.Buffer
+		sm3, err := TLV{algSM3_256, make([]byte, 32)}.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digests.Write(sm3)
+		for _, hash := range []crypto.Hash{crypto.SHA256, crypto.SHA1} {
+			alg, err := register.HashTPMAlg(hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, err := TLV{uint8(alg), rec.Digests[hash]}.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			digests.Write(d)
+		}
+		writeTLV(TLV{uint8(digestsTypeValue), digests.Bytes()})
+		writeTLV(rec.Content)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeTPM2ToolsCEL(t *testing.T) {
+	rot, err := register.CreateFakeRot(measuredHashes, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cel := NewPCR()
+	for i := 0; i < 300; i++ {
+		appendFakeMREventOrFatal(t, cel, rot, 16+i%2, measuredHashes, FakeTlv{FakeEvent1, []byte{byte(i)}})
+	}
+	data := encodeTPM2ToolsCEL(t, cel.Records())
+
+	if _, err := DecodeToCEL(bytes.NewBuffer(data)); err == nil {
+		t.Errorf("DecodeToCEL(tpm2-tools CEL): got nil, want error")
+	}
+	quirks, err := QuirkSet("tpm2-tools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeFrom(bytes.NewReader(data), DecodeOpts{Quirks: quirks})
+	if err != nil {
+		t.Fatalf("DecodeFrom(tpm2-tools CEL, %v): %v", quirks, err)
+	}
+	if !reflect.DeepEqual(decoded.Records(), cel.Records()) {
+		t.Errorf("DecodeFrom(tpm2-tools CEL): decoded records differ from the original ones")
+	}
+	replay(t, decoded, rot, measuredHashes, []int{16, 17}, true /*shouldSucceed*/)
+
+	// Each quirk is needed.
+	for _, q := range []Quirks{QuirkVariableRecNum, QuirkWideIndex, QuirkUnknownDigests} {
+		if _, err := DecodeFrom(bytes.NewReader(data), DecodeOpts{Quirks: quirks &^ q}); err == nil {
+			t.Errorf("DecodeFrom(tpm2-tools CEL, %v): got nil, want error", quirks&^q)
+		}
+	}
+}
+
+func TestDecodeTPM2ToolsFixture(t *testing.T) {
+	el, err := tcg.ParseEventLog(testdata.Ubuntu2404AmdSevSnpEventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecodeFrom(bytes.NewReader(testdata.Ubuntu2404AmdSevSnpTPM2ToolsCEL), DecodeOpts{}); err == nil {
+		t.Errorf("DecodeFrom(tpm2-tools CEL): got nil, want error")
+	}
+	decoded, err := DecodeFrom(bytes.NewReader(testdata.Ubuntu2404AmdSevSnpTPM2ToolsCEL), DecodeOpts{Quirks: TPM2ToolsQuirks})
+	if err != nil {
+		t.Fatalf("DecodeFrom(tpm2-tools CEL, %v): %v", TPM2ToolsQuirks, err)
+	}
+
+	for _, alg := range el.Algs {
+		hash := alg.CryptoHash()
+		events := el.Events(alg)
+		if len(decoded.Records()) != len(events) {
+			t.Fatalf("DecodeFrom(tpm2-tools CEL): got %d records, want %d", len(decoded.Records()), len(events))
+		}
+		// Replay the registers from the TCG event log the CEL was converted
+		// from, as the PCR values of the boot are not recorded.
+		pcrs := make(map[int][]byte)
+		for i, event := range events {
+			rec := decoded.Records()[i]
+			if int(rec.Index) != event.Index || !bytes.Equal(rec.Digests[hash], event.Digest) {
+				t.Errorf("record %d: got PCR%d %v digest %x, want PCR%d digest %x", rec.RecNum, rec.Index, hash, rec.Digests[hash], event.Index, event.Digest)
+			}
+			value, ok := pcrs[event.Index]
+			if !ok {
+				value = make([]byte, hash.Size())
+			}
+			h := hash.New()
+			h.Write(value)
+			h.Write(event.Digest)
+			pcrs[event.Index] = h.Sum(nil)
+		}
+		bank := register.PCRBank{TCGHashAlgo: register.HashAlgo(alg)}
+		for idx, value := range pcrs {
+			bank.PCRs = append(bank.PCRs, register.PCR{Index: idx, Digest: value, DigestAlg: hash})
+		}
+		if err := decoded.Replay(bank); err != nil {
+			t.Errorf("Replay(%v bank): %v", hash, err)
+		}
+	}
+}
+
+func TestDecodeWideIndexOutOfRange(t *testing.T) {
+	rec := []byte{
+		0, 0, 0, 0, 1, 0, // recnum 0
+		1, 0, 0, 0, 4, 0, 0, 1, 0, // PCR 256
+		3, 0, 0, 0, 0, // no digests
+		222, 0, 0, 0, 0, // empty content
+	}
+	if _, err := DecodeFrom(bytes.NewReader(rec), DecodeOpts{Quirks: TPM2ToolsQuirks}); err == nil {
+		t.Errorf("DecodeFrom(index 256): got nil, want error")
+	}
+}
+
+func TestCreateDigestFieldOrder(t *testing.T) {
+	digests := map[crypto.Hash][]byte{
+		crypto.SHA512: bytes.Repeat([]byte{3}, crypto.SHA512.Size()),
+		crypto.SHA1:   bytes.Repeat([]byte{1}, crypto.SHA1.Size()),
+		crypto.SHA256: bytes.Repeat([]byte{2}, crypto.SHA256.Size()),
+	}
+	want, err := createDigestField(digests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := want.Value[0]; got != 0x04 {
+		t.Errorf("createDigestField(): got first algorithm %#x, want SHA-1 (0x04)", got)
+	}
+	for i := 0; i < 10; i++ {
+		got, err := createDigestField(digests)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Value, want.Value) {
+			t.Fatalf("createDigestField(): encoding is not deterministic")
+		}
+	}
+}
+
+func TestQuirkSet(t *testing.T) {
+	quirks, err := QuirkSet("tpm2-tools")
+	if err != nil {
+		t.Fatalf("QuirkSet(tpm2-tools): %v", err)
+	}
+	if want := "variable-recnum|wide-index|unknown-digests"; quirks.String() != want {
+		t.Errorf("QuirkSet(tpm2-tools) = %v, want %v", quirks, want)
+	}
+	if _, err := QuirkSet("tss"); err == nil {
+		t.Errorf("QuirkSet(tss): got nil, want error")
+	}
+}
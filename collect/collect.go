@@ -0,0 +1,46 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package collect reads event logs from the local machine, for attesters that
+// send them to a verifier with the register values.
+//
+// The TPM event log is read from securityfs on Linux, and from the TPM Base
+// Services (TBS) on Windows. The Confidential Computing event log (CCEL) is
+// only read on Linux.
+package collect
+
+import (
+	"errors"
+)
+
+// ErrUnsupported is returned when the event log cannot be read on the
+// platform.
+var ErrUnsupported = errors.New("reading the event log is not supported on this platform")
+
+// TPMEventLog reads the TCG PC Client event log of the machine's TPM. The log
+// is unverified; parse it with tcg.ParseEventLog and replay it against the
+// PCRs, e.g., with tpmeventlog.ReplayAndExtract.
+//
+// On Windows, the log is the Windows Boot Configuration Log (WBCL) returned by
+// Tbsi_Get_TCG_Log, the same log as the Get-TpmEventLog cmdlet. Reading it
+// may require administrator privileges.
+func TPMEventLog() ([]byte, error) {
+	return tpmEventLog()
+}
+
+// CCEL reads the Confidential Computing event log and its ACPI table, e.g.,
+// for ccel.ReplayAndExtract.
+func CCEL() (acpiTable []byte, eventLog []byte, err error) {
+	return ccel()
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build linux
+
+package collect
+
+import (
+	"fmt"
+	"os"
+)
+
+// Paths of the event logs on Linux.
+var (
+	tpmEventLogPath = "/sys/kernel/security/tpm0/binary_bios_measurements"
+	ccelTablePath   = "/sys/firmware/acpi/tables/CCEL"
+	ccelPath        = "/sys/firmware/acpi/tables/data/CCEL"
+)
+
+func tpmEventLog() ([]byte, error) {
+	log, err := os.ReadFile(tpmEventLogPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TPM event log: %v", err)
+	}
+	return log, nil
+}
+
+func ccel() ([]byte, []byte, error) {
+	table, err := os.ReadFile(ccelTablePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CCEL ACPI table: %v", err)
+	}
+	log, err := os.ReadFile(ccelPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CCEL: %v", err)
+	}
+	return table, log, nil
+}
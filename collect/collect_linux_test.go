@@ -0,0 +1,74 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build linux
+
+package collect
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/testdata"
+)
+
+func TestTPMEventLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "binary_bios_measurements")
+	if err := os.WriteFile(path, testdata.Ubuntu2404AmdSevSnpEventLog, 0444); err != nil {
+		t.Fatal(err)
+	}
+	defer func(old string) { tpmEventLogPath = old }(tpmEventLogPath)
+	tpmEventLogPath = path
+
+	log, err := TPMEventLog()
+	if err != nil {
+		t.Fatalf("TPMEventLog(): %v", err)
+	}
+	if !bytes.Equal(log, testdata.Ubuntu2404AmdSevSnpEventLog) {
+		t.Errorf("TPMEventLog(): got a different log than the one at %s", path)
+	}
+	if _, err := tcg.ParseEventLog(log, tcg.ParseOpts{}); err != nil {
+		t.Errorf("ParseEventLog(TPMEventLog()): %v", err)
+	}
+
+	tpmEventLogPath = filepath.Join(t.TempDir(), "missing")
+	if _, err := TPMEventLog(); err == nil {
+		t.Errorf("TPMEventLog(): got nil, want error for a missing log")
+	}
+}
+
+func TestCCEL(t *testing.T) {
+	dir := t.TempDir()
+	defer func(table, log string) { ccelTablePath, ccelPath = table, log }(ccelTablePath, ccelPath)
+	ccelTablePath, ccelPath = filepath.Join(dir, "table"), filepath.Join(dir, "data")
+	if err := os.WriteFile(ccelTablePath, []byte("table"), 0444); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CCEL(); err == nil {
+		t.Errorf("CCEL(): got nil, want error for a missing log")
+	}
+	if err := os.WriteFile(ccelPath, []byte("log"), 0444); err != nil {
+		t.Fatal(err)
+	}
+	table, log, err := CCEL()
+	if err != nil {
+		t.Fatalf("CCEL(): %v", err)
+	}
+	if string(table) != "table" || string(log) != "log" {
+		t.Errorf("CCEL() = %q, %q, want %q, %q", table, log, "table", "log")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build windows
+
+package collect
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-tpm/tpmutil/tbs"
+)
+
+// maxLogReads bounds the attempts to read the TCG log, which may grow between
+// reading its size and its contents.
+const maxLogReads = 3
+
+func tpmEventLog() ([]byte, error) {
+	ctx, err := tbs.CreateContext(tbs.TPMVersion20, tbs.IncludeTPM12|tbs.IncludeTPM20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TBS context: %v", err)
+	}
+	defer ctx.Close()
+
+	for i := 0; i < maxLogReads; i++ {
+		size, err := ctx.GetTCGLog(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get TCG log size: %v", err)
+		}
+		log := make([]byte, size)
+		n, err := ctx.GetTCGLog(log)
+		if errors.Is(err, tbs.ErrInsufficientBuffer) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TCG log: %v", err)
+		}
+		return log[:n], nil
+	}
+	return nil, fmt.Errorf("failed to read TCG log: log kept growing after %d reads", maxLogReads)
+}
+
+func ccel() ([]byte, []byte, error) {
+	return nil, nil, ErrUnsupported
+}
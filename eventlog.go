@@ -0,0 +1,382 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package eventlog is the stable entry point to go-eventlog. It covers the
+// main user journeys: parsing a PC Client (TPM) event log, a Confidential
+// Computing event log (CCEL), or a Canonical Event Log (CEL), verifying it
+// against measurement register values, and extracting a FirmwareLogState.
+//
+// The types in this package are kept stable across reorganizations of the
+// tcg, extract, ccel, cel, and tpmeventlog packages, which remain available
+// for advanced use.
+package eventlog
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-eventlog/ccel"
+	"github.com/google/go-eventlog/cel"
+	"github.com/google/go-eventlog/extract"
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/tpmeventlog"
+)
+
+// FirmwareLogState is the verified state of a booted machine.
+type FirmwareLogState = pb.FirmwareLogState
+
+// CEL is a Canonical Event Log.
+type CEL = cel.CEL
+
+// Loader selects the second-stage bootloader whose events are extracted.
+type Loader int
+
+// Supported loaders.
+const (
+	// NoLoader skips bootloader extraction.
+	NoLoader Loader = iota
+	// AutoDetectLoader determines the bootloader from the event log.
+	AutoDetectLoader
+	// GRUB is GNU GRUB.
+	GRUB
+	// SystemdBoot is systemd-boot.
+	SystemdBoot
+	// WindowsBootManager is the Windows Boot Manager.
+	WindowsBootManager
+)
+
+func (l Loader) bootloader() (extract.Bootloader, error) {
+	switch l {
+	case NoLoader:
+		return extract.UnsupportedLoader, nil
+	case AutoDetectLoader:
+		return extract.AutoDetect, nil
+	case GRUB:
+		return extract.GRUB, nil
+	case SystemdBoot:
+		return extract.SystemdBoot, nil
+	case WindowsBootManager:
+		return extract.WindowsBootManager, nil
+	}
+	return extract.UnsupportedLoader, fmt.Errorf("unknown loader %d", l)
+}
+
+// VariablePolicy controls how missing or empty Secure Boot variables are
+// handled. See extract.VariablePolicy.
+type VariablePolicy = extract.VariablePolicy
+
+// VariablePresence controls whether a Secure Boot variable may be missing or
+// empty.
+type VariablePresence = extract.VariablePresence
+
+// Supported variable presences. See extract.VariablePresence.
+const (
+	DefaultVariable  = extract.DefaultVariable
+	OptionalVariable = extract.OptionalVariable
+	RequiredVariable = extract.RequiredVariable
+)
+
+// MissingSectionError is returned with ExtractOpts.Strict when a
+// FirmwareLogState section is omitted. See extract.MissingSectionError.
+type MissingSectionError = extract.MissingSectionError
+
+// TrustAnchor is a certificate trusted to authorize boot components. See
+// extract.TrustAnchor.
+type TrustAnchor = extract.TrustAnchor
+
+// SchemaVersion is the current version of the extraction output. See
+// extract.SchemaVersion.
+const SchemaVersion = extract.SchemaVersion
+
+// ExtractOpts gives options for extracting a FirmwareLogState.
+type ExtractOpts struct {
+	// CompatLevel pins the extraction output to a SchemaVersion. If 0, the
+	// current SchemaVersion is used.
+	CompatLevel uint32
+	// Loader is the second-stage bootloader whose events are extracted.
+	Loader Loader
+	// AllowEmptySBVar allows the SecureBoot variable to be empty.
+	//
+	// Deprecated: Set VariablePolicy.SecureBoot to OptionalVariable instead.
+	AllowEmptySBVar bool
+	// AllowDebugMode reports a UEFI debugger enabled during boot in the
+	// Secure Boot state, rather than failing extraction.
+	AllowDebugMode bool
+	// VariablePolicy controls how missing or empty Secure Boot variables are
+	// handled.
+	VariablePolicy VariablePolicy
+	// CollectPostEBSEvents collects the untrusted events measured after
+	// ExitBootServices.
+	CollectPostEBSEvents bool
+	// CertMetadata includes parsed certificate fields, such as the subject
+	// and validity period, in the Secure Boot databases.
+	CertMetadata bool
+	// EventSummaries includes a human-readable summary of each raw event.
+	EventSummaries bool
+	// QuirkProfile enables the workarounds for known deviations of a
+	// platform's event logs, e.g., "ovmf-edk2-2022". See tcg.QuirkSet.
+	QuirkProfile string
+	// Strict fails extraction with a MissingSectionError for each state that
+	// would otherwise be silently omitted.
+	Strict bool
+	// TrustAnchors are certificates the caller trusts, e.g., an enterprise
+	// UEFI CA. The db and authority certificates are annotated as trusted or
+	// untrusted relative to them.
+	TrustAnchors []TrustAnchor
+}
+
+func (o ExtractOpts) extractOpts() (extract.Opts, error) {
+	loader, err := o.Loader.bootloader()
+	if err != nil {
+		return extract.Opts{}, err
+	}
+	return extract.Opts{
+		CompatLevel:          o.CompatLevel,
+		Loader:               loader,
+		AllowEmptySBVar:      o.AllowEmptySBVar,
+		AllowDebugMode:       o.AllowDebugMode,
+		VariablePolicy:       o.VariablePolicy,
+		CollectPostEBSEvents: o.CollectPostEBSEvents,
+		CertMetadata:         o.CertMetadata,
+		EventSummaries:       o.EventSummaries,
+		QuirkProfile:         o.QuirkProfile,
+		Strict:               o.Strict,
+		TrustAnchors:         o.TrustAnchors,
+	}, nil
+}
+
+// Bank holds measurement register values for a single hash algorithm.
+type Bank struct {
+	Hash crypto.Hash
+	// Values maps register indexes to register values. For PC Client logs the
+	// index is the PCR index. For CCELs it is the RTMR index, e.g., 1 for
+	// RTMR[1].
+	Values map[int][]byte
+}
+
+func (b Bank) indexes() []int {
+	indexes := make([]int, 0, len(b.Values))
+	for idx := range b.Values {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+func (b Bank) pcrBank() (register.PCRBank, error) {
+	alg, err := pb.HashAlgoFromCryptoHash(b.Hash)
+	if err != nil {
+		return register.PCRBank{}, err
+	}
+	bank := register.PCRBank{TCGHashAlgo: alg}
+	for _, idx := range b.indexes() {
+		bank.PCRs = append(bank.PCRs, register.PCR{Index: idx, Digest: b.Values[idx], DigestAlg: b.Hash})
+	}
+	return bank, nil
+}
+
+func (b Bank) rtmrBank() (register.RTMRBank, error) {
+	if b.Hash != crypto.SHA384 {
+		return register.RTMRBank{}, fmt.Errorf("RTMRs only support SHA384, got %v", b.Hash)
+	}
+	var bank register.RTMRBank
+	for _, idx := range b.indexes() {
+		bank.RTMRs = append(bank.RTMRs, register.RTMR{Index: idx, Digest: b.Values[idx]})
+	}
+	return bank, nil
+}
+
+// Event is an event from a firmware event log.
+type Event struct {
+	// Num is the position of the event in the event log.
+	Num uint32
+	// Index is the register index encoded in the event log. For CCELs this is
+	// the CC measurement register index, which is the RTMR index plus one.
+	Index uint32
+	// Type is the event type. It is only verified together with Data, so
+	// it must be treated as a hint.
+	Type uint32
+	// Data is the event data.
+	Data []byte
+	// Digest is the event digest for the requested hash algorithm.
+	Digest []byte
+}
+
+func convertEvents(events []tcg.Event) []Event {
+	out := make([]Event, 0, len(events))
+	for _, event := range events {
+		out = append(out, Event{
+			Num:    event.Num(),
+			Index:  event.MRIndex(),
+			Type:   uint32(event.Type),
+			Data:   event.Data,
+			Digest: event.Digest,
+		})
+	}
+	return out
+}
+
+type logKind int
+
+const (
+	pcClientLog logKind = iota
+	ccelLog
+)
+
+// Log is a parsed firmware event log. Its events are untrusted until the log
+// is verified with Verify or Extract.
+type Log struct {
+	kind      logKind
+	raw       []byte
+	acpiTable []byte
+	parsed    *tcg.EventLog
+}
+
+// ParsePCClient parses a TCG PC Client (TPM) event log, e.g., read from
+// /sys/kernel/security/tpm0/binary_bios_measurements. Compressed logs are
+// supported.
+func ParsePCClient(rawEventLog []byte) (*Log, error) {
+	parsed, err := tcg.ParseEventLog(rawEventLog, tcg.ParseOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return &Log{kind: pcClientLog, raw: rawEventLog, parsed: parsed}, nil
+}
+
+// ParseCCEL parses a Confidential Computing event log and its ACPI table,
+// e.g., read from /sys/firmware/acpi/tables/data/CCEL and
+// /sys/firmware/acpi/tables/CCEL. Only TDX logs are supported.
+func ParseCCEL(acpiTable, rawEventLog []byte) (*Log, error) {
+	table, err := ccel.ParseACPITable(acpiTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CCEL ACPI Table file: %v", err)
+	}
+	if table.CCType != ccel.TDX {
+		return nil, fmt.Errorf("only TDX Confidential Computing event logs are supported: received %v", table.CCType)
+	}
+	// CCELs have trailing padding at the end of the event log.
+	parsed, err := tcg.ParseEventLog(rawEventLog, tcg.ParseOpts{AllowPadding: true})
+	if err != nil {
+		return nil, err
+	}
+	return &Log{kind: ccelLog, raw: rawEventLog, acpiTable: acpiTable, parsed: parsed}, nil
+}
+
+// ParseCEL parses a Canonical Event Log.
+func ParseCEL(rawCEL []byte) (CEL, error) {
+	return cel.DecodeFrom(bytes.NewReader(rawCEL), cel.DecodeOpts{})
+}
+
+// ParseCELJSON parses a Canonical Event Log in the CEL-JSON format.
+func ParseCELJSON(data []byte) (CEL, error) {
+	return cel.DecodeCELJSON(data)
+}
+
+// ParseCELCBOR parses a Canonical Event Log in the CEL-CBOR format.
+func ParseCELCBOR(data []byte) (CEL, error) {
+	return cel.DecodeCELCBOR(data)
+}
+
+// Events returns the unverified events of the log with digests for the given
+// hash algorithm.
+func (l *Log) Events(hash crypto.Hash) ([]Event, error) {
+	alg, err := pb.HashAlgoFromCryptoHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return convertEvents(l.parsed.Events(register.HashAlg(alg))), nil
+}
+
+func (l *Log) mrs(bank Bank) ([]register.MR, error) {
+	if l.kind == ccelLog {
+		rtmrs, err := bank.rtmrBank()
+		return rtmrs.MRs(), err
+	}
+	pcrs, err := bank.pcrBank()
+	return pcrs.MRs(), err
+}
+
+// Verify replays the log against the register values in bank, and returns
+// the verified events.
+//
+// It is the caller's responsibility to ensure the register values can be
+// trusted, e.g., by verifying a quote over them.
+func Verify(log *Log, bank Bank) ([]Event, error) {
+	if log == nil {
+		return nil, errors.New("nil Log")
+	}
+	mrs, err := log.mrs(bank)
+	if err != nil {
+		return nil, err
+	}
+	events, err := log.parsed.Verify(mrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay event log: %v", err)
+	}
+	return convertEvents(events), nil
+}
+
+// Extract verifies the log against the register values in bank, and extracts
+// a FirmwareLogState from the verified events.
+//
+// The returned FirmwareLogState may be partial, in which case err is
+// non-nil. It is the caller's responsibility to ensure the register values
+// can be trusted.
+func Extract(log *Log, bank Bank, opts ExtractOpts) (*FirmwareLogState, error) {
+	if log == nil {
+		return nil, errors.New("nil Log")
+	}
+	extractOpts, err := opts.extractOpts()
+	if err != nil {
+		return nil, err
+	}
+	if log.kind == ccelLog {
+		rtmrs, err := bank.rtmrBank()
+		if err != nil {
+			return nil, err
+		}
+		return ccel.ReplayAndExtract(log.acpiTable, log.raw, rtmrs, extractOpts)
+	}
+	pcrs, err := bank.pcrBank()
+	if err != nil {
+		return nil, err
+	}
+	return tpmeventlog.ReplayAndExtract(log.raw, pcrs, extractOpts)
+}
+
+// VerifyCEL replays a Canonical Event Log against the register values in
+// bank. For CELs measured into PCRs, bank indexes are PCR indexes. For CELs
+// measured into confidential computing registers, they are RTMR indexes.
+func VerifyCEL(c CEL, bank Bank) error {
+	if c == nil {
+		return errors.New("nil CEL")
+	}
+	if c.MRType() == cel.CCMRType {
+		rtmrs, err := bank.rtmrBank()
+		if err != nil {
+			return err
+		}
+		return c.Replay(rtmrs)
+	}
+	pcrs, err := bank.pcrBank()
+	if err != nil {
+		return err
+	}
+	return c.Replay(pcrs)
+}
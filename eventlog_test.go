@@ -0,0 +1,247 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package eventlog
+
+import (
+	"bytes"
+	"crypto"
+	"os"
+	"testing"
+
+	"github.com/google/go-eventlog/cel"
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/testdata"
+)
+
+// replayBank computes the register values measured by the events. offset is
+// subtracted from the event index, e.g., 1 to convert CC MR indexes to RTMR
+// indexes.
+func replayBank(events []Event, hash crypto.Hash, offset int) Bank {
+	bank := Bank{Hash: hash, Values: make(map[int][]byte)}
+	for _, event := range events {
+		idx := int(event.Index) - offset
+		value, ok := bank.Values[idx]
+		if !ok {
+			value = make([]byte, hash.Size())
+		}
+		hasher := hash.New()
+		hasher.Write(value)
+		hasher.Write(event.Digest)
+		bank.Values[idx] = hasher.Sum(nil)
+	}
+	return bank
+}
+
+func TestPCClient(t *testing.T) {
+	log, err := ParsePCClient(testdata.Ubuntu2404AmdSevSnpEventLog)
+	if err != nil {
+		t.Fatalf("ParsePCClient(): %v", err)
+	}
+	events, err := log.Events(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank := replayBank(events, crypto.SHA256, 0)
+
+	verified, err := Verify(log, bank)
+	if err != nil {
+		t.Fatalf("Verify(): %v", err)
+	}
+	if len(verified) == 0 {
+		t.Errorf("Verify(): got no events")
+	}
+	state, err := Extract(log, bank, ExtractOpts{Loader: AutoDetectLoader})
+	if err != nil {
+		t.Fatalf("Extract(): %v", err)
+	}
+	if state.GetLogType() != pb.LogType_LOG_TYPE_TCG2 || state.GetLinuxKernel().GetCommandLine() != testdata.Ubuntu2404AmdSevSnpCmdline {
+		t.Errorf("Extract(): got log type %v and command line %q", state.GetLogType(), state.GetLinuxKernel().GetCommandLine())
+	}
+
+	bank.Values[0] = make([]byte, crypto.SHA256.Size())
+	if _, err := Verify(log, bank); err == nil {
+		t.Errorf("Verify(tampered bank): got nil, want error")
+	}
+	if _, err := Extract(log, bank, ExtractOpts{Loader: Loader(100)}); err == nil {
+		t.Errorf("Extract(unknown loader): got nil, want error")
+	}
+}
+
+func TestVerifyBoot(t *testing.T) {
+	log, err := ParsePCClient(testdata.Ubuntu2404AmdSevSnpEventLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := log.Events(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank := replayBank(events, crypto.SHA256, 0)
+
+	state, err := Extract(log, bank, ExtractOpts{Loader: AutoDetectLoader})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kernel, grubCfg []byte
+	for _, file := range state.GetGrub().GetFiles() {
+		if bytes.Contains(file.GetUntrustedFilename(), []byte("vmlinuz")) {
+			kernel = file.GetDigest()
+		}
+		if bytes.Contains(file.GetUntrustedFilename(), []byte("grub.cfg")) {
+			grubCfg = file.GetDigest()
+		}
+	}
+	if kernel == nil || grubCfg == nil {
+		t.Fatal("no kernel or grub.cfg file in the GRUB state")
+	}
+	version := state.GetPlatform().GetGceVersion()
+	if version == 0 {
+		t.Fatal("no GCE firmware version in the platform state")
+	}
+
+	for _, tc := range []struct {
+		name         string
+		policy       BootPolicy
+		wantFailures int
+	}{
+		{"empty policy", BootPolicy{}, 0},
+		{"all checks", BootPolicy{RequireSecureBoot: state.GetSecureBoot().GetEnabled(), AllowedKernels: [][]byte{{1}, kernel}, MinFirmwareVersion: version}, 0},
+		{"unknown kernel", BootPolicy{AllowedKernels: [][]byte{make([]byte, crypto.SHA256.Size())}}, 1},
+		{"file read but not booted", BootPolicy{AllowedKernels: [][]byte{grubCfg}}, 1},
+		{"old firmware", BootPolicy{MinFirmwareVersion: version + 1}, 1},
+		{"unknown kernel and old firmware", BootPolicy{AllowedKernels: [][]byte{{1}}, MinFirmwareVersion: version + 1}, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := VerifyBoot(testdata.Ubuntu2404AmdSevSnpEventLog, bank, tc.policy)
+			if err != nil {
+				t.Fatalf("VerifyBoot(): %v", err)
+			}
+			if len(result.Failures) != tc.wantFailures || result.Passed != (tc.wantFailures == 0) {
+				t.Errorf("VerifyBoot() = passed %v, failures %q; want %d failures", result.Passed, result.Failures, tc.wantFailures)
+			}
+			if result.State.GetLinuxKernel().GetCommandLine() != testdata.Ubuntu2404AmdSevSnpCmdline {
+				t.Errorf("VerifyBoot(): got command line %q, want %q", result.State.GetLinuxKernel().GetCommandLine(), testdata.Ubuntu2404AmdSevSnpCmdline)
+			}
+		})
+	}
+
+	bank.Values[0] = make([]byte, crypto.SHA256.Size())
+	if _, err := VerifyBoot(testdata.Ubuntu2404AmdSevSnpEventLog, bank, BootPolicy{}); err == nil {
+		t.Errorf("VerifyBoot(tampered bank): got nil, want error")
+	}
+}
+
+func TestBootPolicyKernel(t *testing.T) {
+	kernel, other := []byte{1}, []byte{2}
+	for _, tc := range []struct {
+		name        string
+		kernels     [][]byte
+		wantFailure bool
+	}{
+		{"booted kernel", [][]byte{kernel}, false},
+		{"UKI .linux section", [][]byte{other, kernel}, false},
+		{"other kernel", [][]byte{other}, true},
+		{"no booted kernel", nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Only the kernels booted by the detected bootloader are
+			// checked, not the kernel digest in the state.
+			state := &FirmwareLogState{LinuxKernel: &pb.LinuxKernelState{KernelDigest: kernel}}
+			failures := BootPolicy{AllowedKernels: [][]byte{kernel}}.check(state, tc.kernels)
+			if got := len(failures) > 0; got != tc.wantFailure {
+				t.Errorf("check() = %q, want failure %v", failures, tc.wantFailure)
+			}
+		})
+	}
+}
+
+func TestBootPolicyDebugMode(t *testing.T) {
+	state := &FirmwareLogState{SecureBoot: &pb.SecureBootState{Enabled: true, DebugModeEnabled: true}}
+	if failures := (BootPolicy{}).check(state, nil); len(failures) != 1 {
+		t.Errorf("check() = %q, want one failure for a UEFI debugger", failures)
+	}
+}
+
+func TestCCEL(t *testing.T) {
+	table, err := os.ReadFile("testdata/eventlogs/ccel/cos-113-intel-tdx.table.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawLog, err := os.ReadFile("testdata/eventlogs/ccel/cos-113-intel-tdx.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := ParseCCEL(table, rawLog)
+	if err != nil {
+		t.Fatalf("ParseCCEL(): %v", err)
+	}
+	events, err := log.Events(crypto.SHA384)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank := replayBank(events, crypto.SHA384, 1)
+	// MRTD is not an RTMR.
+	delete(bank.Values, -1)
+
+	state, err := Extract(log, bank, ExtractOpts{Loader: GRUB})
+	if err != nil {
+		t.Fatalf("Extract(): %v", err)
+	}
+	if state.GetLogType() != pb.LogType_LOG_TYPE_CC {
+		t.Errorf("Extract(): got log type %v, want %v", state.GetLogType(), pb.LogType_LOG_TYPE_CC)
+	}
+	if _, err := Verify(log, Bank{Hash: crypto.SHA256, Values: bank.Values}); err == nil {
+		t.Errorf("Verify(SHA256 RTMRs): got nil, want error")
+	}
+	if _, err := ParseCCEL(table[:10], rawLog); err == nil {
+		t.Errorf("ParseCCEL(truncated table): got nil, want error")
+	}
+}
+
+func TestCEL(t *testing.T) {
+	values := make(map[int][]byte)
+	extender := func(hash crypto.Hash, idx int, digest []byte) error {
+		value, ok := values[idx]
+		if !ok {
+			value = make([]byte, hash.Size())
+		}
+		hasher := hash.New()
+		hasher.Write(value)
+		hasher.Write(digest)
+		values[idx] = hasher.Sum(nil)
+		return nil
+	}
+	c := cel.NewPCR()
+	for _, content := range []string{"first", "second"} {
+		if err := c.AppendEvent(cel.FakeTlv{EventType: cel.FakeEvent1, EventContent: []byte(content)}, []crypto.Hash{crypto.SHA256}, 16, extender); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := c.EncodeCEL(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseCEL(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseCEL(): %v", err)
+	}
+	if err := VerifyCEL(parsed, Bank{Hash: crypto.SHA256, Values: values}); err != nil {
+		t.Errorf("VerifyCEL(): %v", err)
+	}
+	if err := VerifyCEL(parsed, Bank{Hash: crypto.SHA256, Values: map[int][]byte{16: make([]byte, 32)}}); err == nil {
+		t.Errorf("VerifyCEL(wrong values): got nil, want error")
+	}
+}
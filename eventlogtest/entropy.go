@@ -0,0 +1,61 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package eventlogtest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+)
+
+// Entropy is a deterministic source of random bytes. It can be used where an
+// io.Reader of randomness is expected, so that key and certificate generation
+// is reproducible between runs. It must not be used outside of tests.
+type Entropy struct {
+	mu      sync.Mutex
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+// NewEntropy returns an Entropy whose output is determined by seed.
+func NewEntropy(seed []byte) *Entropy {
+	return &Entropy{seed: append([]byte(nil), seed...)}
+}
+
+// Read fills p with the next bytes of the stream. It never fails.
+func (e *Entropy) Read(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n := 0
+	for n < len(p) {
+		if len(e.buf) == 0 {
+			e.buf = e.block()
+		}
+		c := copy(p[n:], e.buf)
+		e.buf = e.buf[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// block returns SHA-256(seed || counter) and increments the counter.
+func (e *Entropy) block() []byte {
+	h := sha256.New()
+	h.Write(e.seed)
+	binary.Write(h, binary.BigEndian, e.counter)
+	e.counter++
+	return h.Sum(nil)
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package eventlogtest
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestEntropy(t *testing.T) {
+	read := func(e *Entropy, sizes ...int) []byte {
+		var out []byte
+		for _, size := range sizes {
+			p := make([]byte, size)
+			if _, err := io.ReadFull(e, p); err != nil {
+				t.Fatalf("Read() failed: %v", err)
+			}
+			out = append(out, p...)
+		}
+		return out
+	}
+	a := read(NewEntropy([]byte("seed")), 100)
+	b := read(NewEntropy([]byte("seed")), 1, 31, 33, 35)
+	if !bytes.Equal(a, b) {
+		t.Errorf("Entropy output depends on read sizes:\n%x\n%x", a, b)
+	}
+	if c := read(NewEntropy([]byte("other")), 100); bytes.Equal(a, c) {
+		t.Error("Entropy output does not depend on the seed")
+	}
+}
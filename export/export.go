@@ -0,0 +1,91 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package export flattens event log events into rows for data analysis, and
+// writes them as CSV for ingestion by analytics pipelines.
+//
+// Rows describe the events as logged. Export verified events (e.g., from
+// tcg.ParseAndReplay) unless the analysis is of untrusted logs.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"io"
+	"strconv"
+
+	"github.com/google/go-eventlog/tcg"
+)
+
+// Row is a flattened event.
+type Row struct {
+	// Num is the position of the event in the event log.
+	Num uint32
+	// Index is the measurement register index encoded in the event log.
+	Index uint32
+	// Type is the untrusted event type.
+	Type uint32
+	// TypeName is the readable name of Type, or its hex value if unknown.
+	TypeName string
+	// Digest is the lowercase hex event digest.
+	Digest string
+	// Summary is a short, human-readable decoding of the event data, e.g.,
+	// the UEFI variable name or the action string. See tcg.Summarize.
+	Summary string
+}
+
+// columns are the names of the exported Row fields, in order.
+var columns = []string{"num", "index", "type", "type_name", "digest", "summary"}
+
+// Rows flattens the events into rows, in the same order.
+func Rows(events []tcg.Event) []Row {
+	rows := make([]Row, 0, len(events))
+	for _, event := range events {
+		rows = append(rows, Row{
+			Num:      event.Num(),
+			Index:    event.MRIndex(),
+			Type:     uint32(event.Type),
+			TypeName: event.Type.String(),
+			Digest:   hex.EncodeToString(event.ReplayedDigest()),
+			Summary:  tcg.Summarize(event),
+		})
+	}
+	return rows
+}
+
+func (r Row) strings() []string {
+	return []string{
+		strconv.FormatUint(uint64(r.Num), 10),
+		strconv.FormatUint(uint64(r.Index), 10),
+		strconv.FormatUint(uint64(r.Type), 10),
+		r.TypeName,
+		r.Digest,
+		r.Summary,
+	}
+}
+
+// WriteCSV writes the rows as CSV with a header record.
+func WriteCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(row.strings()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-eventlog/tcg"
+)
+
+func TestRows(t *testing.T) {
+	variable := tcg.UEFIVariableData{UnicodeName: utf16.Encode([]rune("SecureBoot")), VariableData: []byte{1}}
+	variableData, err := variable.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := []tcg.Event{
+		{Index: 7, Type: tcg.EFIVariableDriverConfig, Data: variableData, Digest: []byte{0xab}},
+		{Index: 4, Type: tcg.EFIAction, Data: []byte("Calling EFI Application from Boot Option"), Digest: []byte{0xcd}},
+		{Index: 0, Type: tcg.Separator, Data: []byte{0, 0, 0, 0}},
+		{Index: 8, Type: tcg.Ipl, Data: []byte("grub_cmd: " + strings.Repeat("a", 200) + "\x00")},
+		{Index: 1, Type: tcg.EventType(0xdeadbeef), Data: []byte{0xff, 0x00, 0x01}},
+	}
+	rows := Rows(events)
+	want := []Row{
+		{Index: 7, Type: uint32(tcg.EFIVariableDriverConfig), TypeName: "EFI Variable Driver Config", Digest: "ab", Summary: "variable SecureBoot (1 bytes)"},
+		{Index: 4, Type: uint32(tcg.EFIAction), TypeName: "EFI Action", Digest: "cd", Summary: "Calling EFI Application from Boot Option"},
+		{Index: 0, Type: uint32(tcg.Separator), TypeName: "Separator", Summary: "separator 00000000"},
+		{Index: 8, Type: uint32(tcg.Ipl), TypeName: "IPL", Summary: ("grub_cmd: " + strings.Repeat("a", 200))[:tcg.MaxSummaryLen] + "..."},
+		{Index: 1, Type: 0xdeadbeef, TypeName: "EventType(0xdeadbeef)", Summary: "3 bytes"},
+	}
+	if diff := cmp.Diff(want, rows); diff != "" {
+		t.Errorf("Rows() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	rows := []Row{
+		{Num: 1, Index: 8, Type: uint32(tcg.Ipl), TypeName: "IPL", Digest: "ab", Summary: `grub_cmd: echo "a, b"`},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, rows); err != nil {
+		t.Fatalf("WriteCSV(): %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		columns,
+		{"1", "8", "13", "IPL", "ab", `grub_cmd: echo "a, b"`},
+	}
+	if diff := cmp.Diff(want, records); diff != "" {
+		t.Errorf("WriteCSV() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
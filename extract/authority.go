@@ -0,0 +1,259 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"sort"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// authorityUsages deduplicates the certificates of the authority uses, and
+// links each use to the image load it authorized. It returns the distinct
+// certificates in order of first use, and their usages.
+//
+// UEFI firmware measures the authority used to verify an image immediately
+// before the image load event, so the authorized image is the first EFI
+// application or driver load after the authority event. Shim measures the
+// image before verifying it, so from compatLevel 3, the image authorized by a
+// shim authority is the last EFI application load before the authority event.
+func authorityUsages(events []tcg.Event, uses []AuthorityUse, registerCfg RegisterConfig, compatLevel uint32) ([]x509.Certificate, []*pb.AuthorityUsage) {
+	var certs []x509.Certificate
+	var usages []*pb.AuthorityUsage
+	byDER := make(map[string]*pb.AuthorityUsage)
+	for _, use := range uses {
+		var imageNum uint32
+		var imageDigest []byte
+		if compatLevel >= 3 && shimAuthorityVariables[use.VariableName] {
+			imageNum, imageDigest = shimAuthorizedImage(events, use.EventNum, registerCfg)
+		} else {
+			imageNum, imageDigest = authorizedImage(events, use.EventNum, registerCfg)
+		}
+		for _, cert := range use.Certs {
+			usage, ok := byDER[string(cert.Raw)]
+			if !ok {
+				usage = &pb.AuthorityUsage{CertIndex: uint32(len(certs))}
+				byDER[string(cert.Raw)] = usage
+				certs = append(certs, cert)
+				usages = append(usages, usage)
+			}
+			usage.Count++
+			usage.Uses = append(usage.Uses, &pb.AuthorityUse{
+				EventNum:      use.EventNum,
+				VariableName:  use.VariableName,
+				ImageEventNum: imageNum,
+				ImageDigest:   imageDigest,
+			})
+		}
+	}
+	return certs, usages
+}
+
+// shimAuthorityVariables are the variables of authorities that only shim
+// measures: its built-in vendor certificate and the Machine Owner Keys.
+var shimAuthorityVariables = map[string]bool{
+	"Shim":      true,
+	"MokList":   true,
+	"MokListRT": true,
+}
+
+// authorizedImage returns the number and digest of the first image load event
+// after the authority event, or 0 and nil if there is none.
+func authorizedImage(events []tcg.Event, authorityNum uint32, registerCfg RegisterConfig) (uint32, []byte) {
+	for _, event := range events {
+		if event.Num() <= authorityNum {
+			continue
+		}
+		index := event.MRIndex()
+		if index != registerCfg.EFIAppIdx && index != registerCfg.FirmwareDriverIdx {
+			continue
+		}
+		switch event.Type {
+		case tcg.EFIBootServicesApplication, tcg.EFIBootServicesDriver, tcg.EFIRuntimeServicesDriver:
+			return event.Num(), event.ReplayedDigest()
+		}
+	}
+	return 0, nil
+}
+
+// shimAuthorizedImage returns the number and digest of the last EFI
+// application load event before the authority event, or 0 and nil if there is
+// none.
+func shimAuthorizedImage(events []tcg.Event, authorityNum uint32, registerCfg RegisterConfig) (uint32, []byte) {
+	var num uint32
+	var digest []byte
+	for _, event := range events {
+		if event.Num() >= authorityNum {
+			break
+		}
+		if event.MRIndex() == registerCfg.EFIAppIdx && event.Type == tcg.EFIBootServicesApplication {
+			num, digest = event.Num(), event.ReplayedDigest()
+		}
+	}
+	return num, digest
+}
+
+// AppAuthority is a Secure Boot authority that authorized the load of an EFI
+// application.
+type AppAuthority struct {
+	// Cert is the certificate from SecureBootState.authority, typically the
+	// CA in db, or the MOK, that the application's signature chains to.
+	Cert *pb.Certificate
+	// VariableName is the variable the certificate was found in, e.g., db or
+	// MokListRT.
+	VariableName string
+	// EventNum is the number of the authority event.
+	EventNum uint32
+}
+
+// AppSigner is an EFI application and the authorities that authorized it.
+type AppSigner struct {
+	// App is the application from EfiState.apps.
+	App *pb.EfiApp
+	// Authorities are the authorities linked to loads of the application, in
+	// log order. It is empty if no authority was measured for the
+	// application, e.g., because Secure Boot was disabled, or the
+	// application's digest was in db.
+	Authorities []AppAuthority
+}
+
+// AppSigners correlates the EFI applications of the state with the Secure
+// Boot authorities that authorized them, answering which CA signed each
+// application that booted. An authority authorizes the first image load after
+// its event (see AuthorityUse.image_event_num), and is linked to the
+// applications with that image's digest. It returns a signer per application,
+// in the order of EfiState.apps.
+func AppSigners(state *pb.FirmwareLogState) []AppSigner {
+	sbState := state.GetSecureBoot()
+	certs := sbState.GetAuthority().GetCerts()
+	var uses []*pb.AuthorityUse
+	certOf := make(map[*pb.AuthorityUse]*pb.Certificate)
+	for _, usage := range sbState.GetAuthorityUsages() {
+		if int(usage.GetCertIndex()) >= len(certs) {
+			continue
+		}
+		for _, use := range usage.GetUses() {
+			uses = append(uses, use)
+			certOf[use] = certs[usage.GetCertIndex()]
+		}
+	}
+	sort.SliceStable(uses, func(i, j int) bool { return uses[i].GetEventNum() < uses[j].GetEventNum() })
+
+	var signers []AppSigner
+	for _, app := range state.GetEfi().GetApps() {
+		signer := AppSigner{App: app}
+		for _, use := range uses {
+			if use.GetImageEventNum() == 0 || !bytes.Equal(use.GetImageDigest(), app.GetDigest()) {
+				continue
+			}
+			signer.Authorities = append(signer.Authorities, AppAuthority{
+				Cert:         certOf[use],
+				VariableName: use.GetVariableName(),
+				EventNum:     use.GetEventNum(),
+			})
+		}
+		signers = append(signers, signer)
+	}
+	return signers
+}
+
+// TrustAnchor is a certificate a deployment trusts to authorize boot
+// components, e.g., an enterprise UEFI CA, in addition to or instead of the
+// well-known Microsoft and GCE certificates.
+type TrustAnchor struct {
+	// Name identifies the anchor in Certificate.TrustAnchor.
+	Name string
+	Cert *x509.Certificate
+}
+
+// revocations are the certificates revoked by dbx.
+type revocations struct {
+	certs []x509.Certificate
+	// tbsHashes are the EFI_CERT_X509_SHA* entries, keyed by hash, holding
+	// the digest of a revoked certificate's TBSCertificate.
+	tbsHashes map[crypto.Hash][][]byte
+}
+
+// tbsHashTypes are the signature types of dbx entries revoking a certificate
+// by the digest of its TBSCertificate.
+var tbsHashTypes = map[string]crypto.Hash{
+	"X509_SHA256": crypto.SHA256,
+	"X509_SHA384": crypto.SHA384,
+	"X509_SHA512": crypto.SHA512,
+}
+
+func newRevocations(certs []x509.Certificate, entries []tcg.SignatureEntry) revocations {
+	r := revocations{certs: certs, tbsHashes: make(map[crypto.Hash][][]byte)}
+	for _, entry := range entries {
+		hash, ok := tbsHashTypes[entry.Type]
+		// The digest is followed by the EFI_TIME of the revocation.
+		if !ok || len(entry.Data) < hash.Size() {
+			continue
+		}
+		r.tbsHashes[hash] = append(r.tbsHashes[hash], entry.Data[:hash.Size()])
+	}
+	return r
+}
+
+// revoked returns whether cert, or the certificate that issued it, is in dbx.
+func (r revocations) revoked(cert x509.Certificate) bool {
+	for _, revoked := range r.certs {
+		if bytes.Equal(cert.Raw, revoked.Raw) {
+			return true
+		}
+		if bytes.Equal(cert.RawIssuer, revoked.RawSubject) && cert.CheckSignatureFrom(&revoked) == nil {
+			return true
+		}
+	}
+	for hash, digests := range r.tbsHashes {
+		if !hash.Available() {
+			continue
+		}
+		h := hash.New()
+		h.Write(cert.RawTBSCertificate)
+		digest := h.Sum(nil)
+		for _, d := range digests {
+			if bytes.Equal(digest, d) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// trustStatus returns whether the certificate is revoked by dbx, is one of
+// the trust anchors or was issued by one, and the name of the matching
+// anchor. Revocation is checked first, so a revoked certificate is never
+// trusted, even if it is an anchor.
+func trustStatus(cert x509.Certificate, anchors []TrustAnchor, dbx revocations) (pb.TrustStatus, string) {
+	if dbx.revoked(cert) {
+		return pb.TrustStatus_TRUST_STATUS_REVOKED, ""
+	}
+	for _, anchor := range anchors {
+		if bytes.Equal(cert.Raw, anchor.Cert.Raw) {
+			return pb.TrustStatus_TRUST_STATUS_TRUSTED, anchor.Name
+		}
+	}
+	for _, anchor := range anchors {
+		if bytes.Equal(cert.RawIssuer, anchor.Cert.RawSubject) && cert.CheckSignatureFrom(anchor.Cert) == nil {
+			return pb.TrustStatus_TRUST_STATUS_TRUSTED, anchor.Name
+		}
+	}
+	return pb.TrustStatus_TRUST_STATUS_UNTRUSTED, ""
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// BootConfigState extracts the boot configuration from the events in
+// registerCfg.BootConfigIdx, i.e., PCR 1. It returns nil if there are no boot
+// configuration events.
+//
+// The BootOrder and Boot#### variables are only extracted if their digest
+// matches the event data. EV_EFI_VARIABLE_BOOT events are accepted with the
+// digest of either the variable data, as measured by EDK2, or of the whole
+// UEFI_VARIABLE_DATA, as the specification requires. The names of variables
+// with other digests are reported in unverified_variables, and variables
+// without parsable event data are skipped. The platform
+// configuration events measure data that is not in the event log, so their
+// digests are extracted with their untrusted descriptions.
+func BootConfigState(events []tcg.Event, registerCfg RegisterConfig) (*pb.BootConfigState, error) {
+	return bootConfigState(events, registerCfg, SchemaVersion)
+}
+
+func bootConfigState(events []tcg.Event, registerCfg RegisterConfig, compatLevel uint32) (*pb.BootConfigState, error) {
+	var state pb.BootConfigState
+	found := false
+	for _, event := range events {
+		if event.MRIndex() != registerCfg.BootConfigIdx {
+			continue
+		}
+		switch typ := event.UntrustedType(); typ {
+		case tcg.EFIVariableBoot, tcg.EFIVariableBoot2:
+			v, err := tcg.ParseVariableEvent(event)
+			if err != nil {
+				// Event data that does not match the digest, e.g., is
+				// missing, is untrusted, so it does not fail extraction.
+				if DigestEquals(event, event.RawData()) != nil {
+					continue
+				}
+				return nil, fmt.Errorf("event %d: failed parsing boot variable: %v", event.Num(), err)
+			}
+			found = true
+			if DigestEquals(event, v.VariableData) != nil && DigestEquals(event, event.RawData()) != nil {
+				state.UnverifiedVariables = append(state.UnverifiedVariables, v.VarName())
+				continue
+			}
+			if err := addBootVariable(&state, v, compatLevel); err != nil {
+				return nil, fmt.Errorf("event %d: %v", event.Num(), err)
+			}
+		case tcg.PlatformConfigFlags, tcg.EFIHandoffTables, tcg.EFIHandoffTables2:
+			found = true
+			state.PlatformConfigs = append(state.PlatformConfigs, &pb.ConfigMeasurement{
+				EventNum:             event.Num(),
+				UntrustedType:        uint32(typ),
+				UntrustedDescription: configDescription(typ, event.RawData()),
+				Digest:               event.ReplayedDigest(),
+			})
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// addBootVariable adds a verified BootOrder or Boot#### variable to the state.
+// Other variables, e.g., BootNext, are ignored.
+func addBootVariable(state *pb.BootConfigState, v tcg.UEFIVariableData, compatLevel uint32) error {
+	name := v.VarName()
+	if name == "BootOrder" {
+		order, err := tcg.ParseBootOrder(v.VariableData)
+		if err != nil {
+			return err
+		}
+		state.BootOrder = nil
+		for _, number := range order {
+			state.BootOrder = append(state.BootOrder, uint32(number))
+		}
+		return nil
+	}
+	number, ok := bootOptionNumber(name)
+	if !ok {
+		return nil
+	}
+	option, err := tcg.ParseEFILoadOption(v.VariableData)
+	if err != nil {
+		return fmt.Errorf("failed parsing %s: %v", name, err)
+	}
+	state.BootOptions = append(state.BootOptions, &pb.BootOption{
+		Number:       uint32(number),
+		Attributes:   option.Attributes,
+		Active:       option.Attributes&tcg.LoadOptionActive != 0,
+		Description:  option.Description,
+		DevicePath:   devicePathString(option.FilePath, compatLevel),
+		OptionalData: option.OptionalData,
+	})
+	return nil
+}
+
+// bootOptionNumber returns the option number of a Boot#### variable name,
+// e.g., 1 for "Boot0001".
+func bootOptionNumber(name string) (uint16, bool) {
+	digits, ok := strings.CutPrefix(name, "Boot")
+	if !ok || len(digits) != 4 || strings.ToUpper(digits) != digits {
+		return 0, false
+	}
+	number, err := strconv.ParseUint(digits, 16, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(number), true
+}
+
+// configDescription returns the description of a platform configuration
+// event: the data of an EV_PLATFORM_CONFIG_FLAGS event, or the table
+// description of an EV_EFI_HANDOFF_TABLES2 event. EV_EFI_HANDOFF_TABLES
+// events have no description.
+func configDescription(typ tcg.EventType, data []byte) string {
+	switch typ {
+	case tcg.PlatformConfigFlags:
+		return printableDescription(data)
+	case tcg.EFIHandoffTables2:
+		if len(data) > 0 && 1+int(data[0]) <= len(data) {
+			return printableDescription(data[1 : 1+int(data[0])])
+		}
+	}
+	return ""
+}
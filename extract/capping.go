@@ -0,0 +1,54 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"crypto"
+	"fmt"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// CappedError is returned when the firmware capped PCRs 0-7 with error
+// separators, so the event log does not describe the boot. See
+// tcg.FindCapping.
+type CappedError struct {
+	// Capping holds the capping separators.
+	Capping *tcg.Capping
+}
+
+// Error returns a human-friendly description of the capping.
+func (e CappedError) Error() string {
+	return fmt.Sprintf("PCRs 0-7 capped with error separator %x at events %v: the firmware did not measure the boot", e.Capping.Data, e.Capping.Nums)
+}
+
+// cappedState returns the FirmwareLogState of a capped event log. Only the
+// raw events are reported, as the per-register extractions would fail on
+// the missing separators and boot events.
+func cappedState(events []tcg.Event, hash crypto.Hash, pbHash pb.HashAlgo, registerCfg RegisterConfig, opts Opts, compatLevel uint32, capping *tcg.Capping) *pb.FirmwareLogState {
+	return &pb.FirmwareLogState{
+		RawEvents: tcg.ConvertToPbEventsWithOpts(hash, events, tcg.ConvertOpts{Summaries: opts.EventSummaries}),
+		Hash:      pbHash,
+		LogType:   registerCfg.LogType,
+		Findings: []*pb.Finding{{
+			Type:        pb.FindingType_FINDING_TYPE_PCRS_CAPPED,
+			Description: CappedError{capping}.Error(),
+		}},
+		Stats:         EventLogStats(events),
+		SchemaVersion: compatLevel,
+		QuirkProfile:  opts.QuirkProfile,
+	}
+}
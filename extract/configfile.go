@@ -0,0 +1,187 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+	"gopkg.in/yaml.v3"
+)
+
+// registerConfigFile is the YAML or JSON form of a RegisterConfig. Unset
+// fields are copied from the base RegisterConfig. For example:
+//
+//	name: VMR
+//	base: tpm
+//	log_type: LOG_TYPE_CC
+//	secure_boot_idx: 1
+//	efi_app_idx: 2
+//	additional_secure_boot_events: [EV_EFI_ACTION, 0x80000002]
+//	drtm:
+//	  indexes: [17, 18]
+//	  image_idx: 17
+//	  config_idx: 18
+type registerConfigFile struct {
+	Name string `yaml:"name"`
+	// Base is "tpm" for TPMRegisterConfig, "rtmr" for RTMRRegisterConfig,
+	// "sevsnp" for SEVSNPRegisterConfig, "cca" for CCARegisterConfig, or
+	// "cove" for CoVERegisterConfig. The extracters always come from the base.
+	Base                string  `yaml:"base"`
+	LogType             string  `yaml:"log_type"`
+	FirmwareDriverIdx   *uint32 `yaml:"firmware_driver_idx"`
+	SecureBootIdx       *uint32 `yaml:"secure_boot_idx"`
+	EFIAppIdx           *uint32 `yaml:"efi_app_idx"`
+	ExitBootServicesIdx *uint32 `yaml:"exit_boot_services_idx"`
+	GRUBCmdIdx          *uint32 `yaml:"grub_cmd_idx"`
+	GRUBFileIdx         *uint32 `yaml:"grub_file_idx"`
+	BootConfigIdx       *uint32 `yaml:"boot_config_idx"`
+	// AdditionalSecureBootEvents are TCG event type names, e.g.,
+	// "EV_EFI_ACTION", or event type numbers. If set, they replace those of
+	// the base.
+	AdditionalSecureBootEvents []string `yaml:"additional_secure_boot_events"`
+	// Drtm, if set, replaces the dynamic launch registers of the base.
+	Drtm *drtmRegisterConfigFile `yaml:"drtm"`
+}
+
+type drtmRegisterConfigFile struct {
+	// Name defaults to the name of the RegisterConfig.
+	Name      string   `yaml:"name"`
+	Indexes   []uint32 `yaml:"indexes"`
+	ImageIdx  uint32   `yaml:"image_idx"`
+	ConfigIdx uint32   `yaml:"config_idx"`
+}
+
+// ParseRegisterConfig parses a RegisterConfig from a YAML or JSON definition,
+// so deployments can support platforms with a custom measurement register
+// layout without recompiling. The definition names a base RegisterConfig,
+// e.g., "tpm" or "rtmr", which provides the extracters and any unset fields.
+// Unknown fields are rejected.
+func ParseRegisterConfig(data []byte) (RegisterConfig, error) {
+	var file registerConfigFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return RegisterConfig{}, fmt.Errorf("failed to parse register config: %v", err)
+	}
+	return file.registerConfig()
+}
+
+// LoadRegisterConfig reads and parses a RegisterConfig file. See
+// ParseRegisterConfig.
+func LoadRegisterConfig(path string) (RegisterConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RegisterConfig{}, err
+	}
+	cfg, err := ParseRegisterConfig(data)
+	if err != nil {
+		return RegisterConfig{}, fmt.Errorf("%v: %v", path, err)
+	}
+	return cfg, nil
+}
+
+func (f registerConfigFile) registerConfig() (RegisterConfig, error) {
+	var cfg RegisterConfig
+	switch f.Base {
+	case "tpm":
+		cfg = NewTPMRegisterConfig()
+	case "rtmr":
+		cfg = NewRTMRRegisterConfig()
+	case "sevsnp":
+		cfg = NewSEVSNPRegisterConfig()
+	case "cca":
+		cfg = NewCCARegisterConfig()
+	case "cove":
+		cfg = NewCoVERegisterConfig()
+	case "":
+		return RegisterConfig{}, errors.New("register config has no base")
+	default:
+		return RegisterConfig{}, fmt.Errorf("unknown register config base %q: want tpm, rtmr, sevsnp, cca, or cove", f.Base)
+	}
+	if f.Name == "" {
+		return RegisterConfig{}, errors.New("register config has no name")
+	}
+	cfg.Name = f.Name
+
+	if f.LogType != "" {
+		logType, ok := pb.LogType_value[f.LogType]
+		if !ok {
+			return RegisterConfig{}, fmt.Errorf("unknown log type %q", f.LogType)
+		}
+		cfg.LogType = pb.LogType(logType)
+	}
+	for _, idx := range []struct {
+		from *uint32
+		to   *uint32
+	}{
+		{f.FirmwareDriverIdx, &cfg.FirmwareDriverIdx},
+		{f.SecureBootIdx, &cfg.SecureBootIdx},
+		{f.EFIAppIdx, &cfg.EFIAppIdx},
+		{f.ExitBootServicesIdx, &cfg.ExitBootServicesIdx},
+		{f.GRUBCmdIdx, &cfg.GRUBCmdIdx},
+		{f.GRUBFileIdx, &cfg.GRUBFileIdx},
+		{f.BootConfigIdx, &cfg.BootConfigIdx},
+	} {
+		if idx.from != nil {
+			*idx.to = *idx.from
+		}
+	}
+
+	if f.AdditionalSecureBootEvents != nil {
+		cfg.AdditionalSecureBootIdxEvents = make(map[tcg.EventType]bool, len(f.AdditionalSecureBootEvents))
+		for _, name := range f.AdditionalSecureBootEvents {
+			eventType, err := parseEventTypeName(name)
+			if err != nil {
+				return RegisterConfig{}, err
+			}
+			cfg.AdditionalSecureBootIdxEvents[eventType] = true
+		}
+	}
+
+	if f.Drtm != nil {
+		drtm := DrtmRegisterConfig{
+			Name:      f.Drtm.Name,
+			Indexes:   f.Drtm.Indexes,
+			ImageIdx:  f.Drtm.ImageIdx,
+			ConfigIdx: f.Drtm.ConfigIdx,
+		}
+		if drtm.Name == "" {
+			drtm.Name = cfg.Name
+		}
+		if !drtm.contains(drtm.ImageIdx) || !drtm.contains(drtm.ConfigIdx) {
+			return RegisterConfig{}, fmt.Errorf("DRTM image and config indexes %d and %d must be in indexes %v", drtm.ImageIdx, drtm.ConfigIdx, drtm.Indexes)
+		}
+		cfg.Drtm = &drtm
+	}
+	return cfg, nil
+}
+
+// parseEventTypeName parses a TCG event type name or number.
+func parseEventTypeName(name string) (tcg.EventType, error) {
+	if eventType, err := tcg.ParseTCGString(name); err == nil {
+		return eventType, nil
+	}
+	et, err := strconv.ParseUint(name, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("unknown event type %q", name)
+	}
+	return tcg.EventType(et), nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"crypto"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+const yamlRegisterConfig = `
+name: VMR
+base: tpm
+log_type: LOG_TYPE_CC
+secure_boot_idx: 1
+efi_app_idx: 2
+additional_secure_boot_events: [EV_EFI_ACTION, 0x80000002]
+drtm:
+  indexes: [17, 18]
+  image_idx: 17
+  config_idx: 18
+`
+
+const jsonRegisterConfig = `{
+  "name": "VMR",
+  "base": "tpm",
+  "log_type": "LOG_TYPE_CC",
+  "secure_boot_idx": 1,
+  "efi_app_idx": 2,
+  "additional_secure_boot_events": ["EV_EFI_ACTION", "0x80000002"],
+  "drtm": {"indexes": [17, 18], "image_idx": 17, "config_idx": 18}
+}`
+
+func TestParseRegisterConfig(t *testing.T) {
+	wantLayout := TPMRegisterConfig.Layout()
+	wantLayout.SecureBootIdx = 1
+	wantLayout.EFIAppIdx = 2
+	wantEvents := map[tcg.EventType]bool{tcg.EFIAction: true, tcg.EFIVariableBoot: true}
+	wantDrtm := &DrtmRegisterConfig{Name: "VMR", Indexes: []uint32{17, 18}, ImageIdx: 17, ConfigIdx: 18}
+
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{"YAML", yamlRegisterConfig},
+		{"JSON", jsonRegisterConfig},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "vmr.conf")
+			if err := os.WriteFile(path, []byte(tc.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadRegisterConfig(path)
+			if err != nil {
+				t.Fatalf("LoadRegisterConfig(): %v", err)
+			}
+			if cfg.Name != "VMR" || cfg.LogType != pb.LogType_LOG_TYPE_CC {
+				t.Errorf("LoadRegisterConfig(): got name %q and log type %v, want VMR and LOG_TYPE_CC", cfg.Name, cfg.LogType)
+			}
+			if got := cfg.Layout(); got != wantLayout {
+				t.Errorf("LoadRegisterConfig(): got layout %+v, want %+v", got, wantLayout)
+			}
+			if !reflect.DeepEqual(cfg.AdditionalSecureBootIdxEvents, wantEvents) {
+				t.Errorf("LoadRegisterConfig(): got additional Secure Boot events %v, want %v", cfg.AdditionalSecureBootIdxEvents, wantEvents)
+			}
+			if !reflect.DeepEqual(cfg.Drtm, wantDrtm) {
+				t.Errorf("LoadRegisterConfig(): got DRTM config %+v, want %+v", cfg.Drtm, wantDrtm)
+			}
+			if cfg.GRUBExtracter == nil || cfg.PlatformExtracter == nil {
+				t.Errorf("LoadRegisterConfig(): extracters not copied from the base")
+			}
+		})
+	}
+	if TPMRegisterConfig.SecureBootIdx != 7 || TPMRegisterConfig.Drtm.Name != "PCR" {
+		t.Errorf("ParseRegisterConfig() modified TPMRegisterConfig")
+	}
+}
+
+func TestParseRegisterConfigBase(t *testing.T) {
+	for _, tc := range []struct {
+		base           string
+		wantTechnology pb.GCEConfidentialTechnology
+	}{
+		{"rtmr", pb.GCEConfidentialTechnology_INTEL_TDX},
+		{"sevsnp", pb.GCEConfidentialTechnology_AMD_SEV_SNP},
+		{"cca", pb.GCEConfidentialTechnology_ARM_CCA},
+		{"cove", pb.GCEConfidentialTechnology_RISCV_COVE},
+	} {
+		t.Run(tc.base, func(t *testing.T) {
+			cfg, err := ParseRegisterConfig([]byte("name: MR\nbase: " + tc.base))
+			if err != nil {
+				t.Fatalf("ParseRegisterConfig(): %v", err)
+			}
+			if got := cfg.Layout(); got != RTMRRegisterConfig.Layout() {
+				t.Errorf("ParseRegisterConfig(): got layout %+v, want the RTMR layout %+v", got, RTMRRegisterConfig.Layout())
+			}
+			platform, err := cfg.PlatformExtracter(crypto.SHA384, nil)
+			if err != nil {
+				t.Fatalf("PlatformExtracter(): %v", err)
+			}
+			if got := platform.GetTechnology(); got != tc.wantTechnology {
+				t.Errorf("PlatformExtracter(): got technology %v, want %v", got, tc.wantTechnology)
+			}
+		})
+	}
+}
+
+func TestParseRegisterConfigFails(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{"no base", "name: VMR"},
+		{"unknown base", "name: VMR\nbase: sev"},
+		{"no name", "base: tpm"},
+		{"unknown field", "name: VMR\nbase: tpm\nsecureboot_idx: 1"},
+		{"unknown log type", "name: VMR\nbase: tpm\nlog_type: LOG_TYPE_SEV"},
+		{"unknown event type", "name: VMR\nbase: tpm\nadditional_secure_boot_events: [EV_NOT_A_TYPE]"},
+		{"DRTM image index not in indexes", "name: VMR\nbase: tpm\ndrtm: {indexes: [18], image_idx: 17, config_idx: 18}"},
+		{"malformed", "{"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseRegisterConfig([]byte(tc.data)); err == nil {
+				t.Errorf("ParseRegisterConfig(): got nil, want error")
+			}
+		})
+	}
+}
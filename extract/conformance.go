@@ -0,0 +1,112 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"bytes"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// conformanceRequirement is an event the TCG PC Client Platform Firmware
+// Profile requires in a PCR.
+type conformanceRequirement struct {
+	index       uint32
+	requirement string
+	description string
+	matches     func(tcg.Event) bool
+}
+
+func eventTypeIn(types ...tcg.EventType) func(tcg.Event) bool {
+	return func(event tcg.Event) bool {
+		for _, typ := range types {
+			if event.Type == typ {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func efiAction(action string) func(tcg.Event) bool {
+	return func(event tcg.Event) bool {
+		return event.Type == tcg.EFIAction && bytes.Equal(event.Data, []byte(action))
+	}
+}
+
+func efiVariable(typ tcg.EventType, name string) func(tcg.Event) bool {
+	return func(event tcg.Event) bool {
+		if event.Type != typ {
+			return false
+		}
+		v, err := tcg.ParseVariableEvent(event)
+		return err == nil && v.VarName() == name
+	}
+}
+
+// conformanceRequirements are the checked requirements, from Section 3.3.4
+// and Section 10 of the TCG PC Client Platform Firmware Profile
+// Specification, accessible at:
+// https://trustedcomputinggroup.org/resource/pc-client-specific-platform-firmware-profile-specification/
+var conformanceRequirements = func() []conformanceRequirement {
+	reqs := []conformanceRequirement{
+		{0, "scrtm_version", "EV_S_CRTM_VERSION of the SRTM", eventTypeIn(tcg.SCRTMVersion)},
+		{0, "platform_firmware", "EV_POST_CODE or EV_EFI_PLATFORM_FIRMWARE_BLOB of the platform firmware", eventTypeIn(tcg.PostCode, tcg.EFIPlatformFirmwareBlob, tcg.EFIPlatformFirmwareBlob2)},
+		{1, "boot_order", "EV_EFI_VARIABLE_BOOT of the BootOrder variable", efiVariable(tcg.EFIVariableBoot, "BootOrder")},
+		{4, "calling_efi_application", "EV_EFI_ACTION \"" + tcg.CallingEFIApplication + "\"", efiAction(tcg.CallingEFIApplication)},
+		{5, "exit_boot_services", "EV_EFI_ACTION \"" + tcg.ExitBootServicesInvocation + "\"", efiAction(tcg.ExitBootServicesInvocation)},
+	}
+	for _, name := range []string{"SecureBoot", "PK", "KEK", "db", "dbx"} {
+		reqs = append(reqs, conformanceRequirement{7, "variable_" + name, "EV_EFI_VARIABLE_DRIVER_CONFIG of the " + name + " variable", efiVariable(tcg.EFIVariableDriverConfig, name)})
+	}
+	for index := uint32(0); index <= 7; index++ {
+		reqs = append(reqs, conformanceRequirement{index, "separator", "EV_SEPARATOR", eventTypeIn(tcg.Separator)})
+	}
+	return reqs
+}()
+
+// ConformanceReport checks the events of a PC Client event log for the events
+// the TCG PC Client Platform Firmware Profile requires in PCRs 0 to 7, e.g., a
+// separator in each PCR and the SCRTM version in PCR0. It reports the
+// requirements that are present and missing, for firmware vendors validating
+// their event logs.
+//
+// The report describes the events as logged, and does not imply the events
+// were verified.
+func ConformanceReport(events []tcg.Event) *pb.ConformanceReport {
+	report := &pb.ConformanceReport{Conformant: true}
+	for index := uint32(0); index <= 7; index++ {
+		for _, req := range conformanceRequirements {
+			if req.index != index {
+				continue
+			}
+			check := &pb.ConformanceCheck{
+				PcrIndex:    req.index,
+				Requirement: req.requirement,
+				Description: req.description,
+			}
+			for _, event := range events {
+				if event.MRIndex() == req.index && req.matches(event) {
+					check.EventNums = append(check.EventNums, event.Num())
+				}
+			}
+			check.Present = len(check.EventNums) > 0
+			report.Conformant = report.Conformant && check.Present
+			report.Checks = append(report.Checks, check)
+		}
+	}
+	return report
+}
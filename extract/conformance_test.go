@@ -0,0 +1,61 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"testing"
+
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/testdata"
+)
+
+func TestConformanceReport(t *testing.T) {
+	eventLog, err := tcg.ParseEventLog(testdata.Rhel8EventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := ConformanceReport(eventLog.Events(register.HashSHA256))
+	if report.GetConformant() {
+		t.Errorf("ConformanceReport(RHEL 8) = got conformant, want missing platform firmware")
+	}
+	separators := 0
+	for _, check := range report.GetChecks() {
+		// GCE firmware does not measure its firmware volumes into PCR0.
+		wantPresent := check.GetRequirement() != "platform_firmware"
+		if check.GetPresent() != wantPresent || (len(check.GetEventNums()) > 0) != wantPresent {
+			t.Errorf("ConformanceReport(RHEL 8) = got PCR%d %s present %v with events %v, want present %v", check.GetPcrIndex(), check.GetRequirement(), check.GetPresent(), check.GetEventNums(), wantPresent)
+		}
+		if check.GetRequirement() == "separator" {
+			separators++
+		}
+	}
+	if separators != 8 {
+		t.Errorf("ConformanceReport(RHEL 8) = got %d separator checks, want 8", separators)
+	}
+
+	report = ConformanceReport(nil)
+	if report.GetConformant() || len(report.GetChecks()) != len(conformanceRequirements) {
+		t.Errorf("ConformanceReport(no events) = got conformant %v with %d checks, want not conformant with %d checks", report.GetConformant(), len(report.GetChecks()), len(conformanceRequirements))
+	}
+	for i, check := range report.GetChecks() {
+		if check.GetPresent() {
+			t.Errorf("ConformanceReport(no events) = got PCR%d %s present, want missing", check.GetPcrIndex(), check.GetRequirement())
+		}
+		if i > 0 && check.GetPcrIndex() < report.GetChecks()[i-1].GetPcrIndex() {
+			t.Errorf("ConformanceReport(no events) = got checks out of PCR order")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"crypto"
+	"fmt"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// DrtmState extracts the dynamic launch (DRTM) state from the events in the
+// DRTM registers, as measured by Intel TXT, AMD SKINIT, tboot, and Linux
+// Secure Launch. The events may come from the firmware event log or from a
+// standalone DRTM event log.
+// It returns nil if there are no DRTM events.
+//
+// It is the caller's responsibility to ensure that the passed events have
+// been replayed against verified DRTM registers.
+func DrtmState(hash crypto.Hash, events []tcg.Event, drtmCfg DrtmRegisterConfig) (*pb.DrtmState, error) {
+	var drtmEvents []tcg.Event
+	var mleEvents []tcg.Event
+	var slaunchEvents []tcg.Event
+	var txt bool
+	state := &pb.DrtmState{}
+	for _, event := range events {
+		index := event.MRIndex()
+		if !drtmCfg.contains(index) {
+			continue
+		}
+		drtmEvents = append(drtmEvents, event)
+		if event.Type >= tcg.TXTEventBase && event.Type <= tcg.TXTCapValue {
+			txt = true
+		}
+
+		var field *[]byte
+		switch event.Type {
+		case tcg.TXTHashStart:
+			field = &state.SinitDigest
+		case tcg.TXTSINITPubKeyHash:
+			field = &state.SinitPubkeyDigest
+		case tcg.TXTMLEHash:
+			field = &state.MleDigest
+		case tcg.EventTag:
+			mleEvents = append(mleEvents, event)
+		case tcg.SLaunch:
+			if index != drtmCfg.ImageIdx && index != drtmCfg.ConfigIdx {
+				return nil, fmt.Errorf("found Secure Launch event in unexpected %s%d", drtmCfg.Name, index)
+			}
+			slaunchEvents = append(slaunchEvents, event)
+		}
+		if field == nil {
+			continue
+		}
+		if *field != nil {
+			return nil, fmt.Errorf("found more than one %v event in the DRTM %ss", event.Type, drtmCfg.Name)
+		}
+		*field = event.ReplayedDigest()
+	}
+	if len(drtmEvents) == 0 {
+		return nil, nil
+	}
+	// AMD SKINIT measures the secure loader without logging TXT events, so a
+	// Secure Launch without them is an SKINIT launch.
+	switch {
+	case txt:
+		state.Technology = pb.DrtmTechnology_DRTM_TECHNOLOGY_INTEL_TXT
+	case len(slaunchEvents) > 0:
+		state.Technology = pb.DrtmTechnology_DRTM_TECHNOLOGY_AMD_SKINIT
+	}
+	state.MleEvents = tcg.ConvertToPbEvents(hash, mleEvents)
+	state.SecureLaunchEvents = tcg.ConvertToPbEvents(hash, slaunchEvents)
+	state.Events = tcg.ConvertToPbEvents(hash, drtmEvents)
+	return state, nil
+}
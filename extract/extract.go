@@ -17,15 +17,21 @@ package extract
 
 import (
 	"bytes"
+	"context"
 	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
 	"crypto/x509"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	pb "github.com/google/go-eventlog/proto/state"
 	"github.com/google/go-eventlog/tcg"
 	"github.com/google/go-eventlog/wellknown"
-	"github.com/google/go-tpm/legacy/tpm2"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -52,14 +58,240 @@ const (
 	UnsupportedLoader Bootloader = iota
 	// GRUB (https://www.gnu.org/software/grub/).
 	GRUB
+	// SystemdBoot (https://systemd.io/BOOT/). See
+	// LinuxKernelStateFromSystemdBoot.
+	SystemdBoot
+	// WindowsBootManager is the Windows Boot Manager (bootmgfw.efi).
+	WindowsBootManager
+	// AutoDetect determines the bootloader from the event log contents using
+	// DetectBootloader.
+	AutoDetect
 )
 
+// SchemaVersion is the current version of the extraction output. It is
+// incremented whenever a change to extraction, e.g., a new heuristic, changes
+// the FirmwareLogState extracted from the same event log. Such changes only
+// apply to Opts.CompatLevel values at or above the version that introduced
+// them.
+//
+// Versions:
+//   - 1: The first versioned output.
+//   - 2: Malformed dbx entries are skipped and recorded in
+//     SecureBootState.dbx_errors, rather than failing SecureBootState
+//     extraction.
+//   - 3: Authorities measured by shim are linked to the image load before
+//     them in SecureBootState.authority_usages, rather than the one after.
+//   - 4: Events whose data size does not match their type are reported as
+//     FINDING_TYPE_EVENT_SIZE_ANOMALY findings.
+//   - 5: The load options and initrd digests measured by the Linux EFI stub
+//     are reported in LinuxKernelState.
+//   - 6: The Linux EFI stub load options are checked against the kernel
+//     command line in LinuxKernelState.load_options_match, and mismatches
+//     are reported as FINDING_TYPE_LOAD_OPTIONS_MISMATCH findings.
+//   - 7: The Windows SIPA events of Windows Boot Manager TPM event logs are
+//     extracted into FirmwareLogState.windows_sipa.
+//   - 8: The UKI sections and boot phases measured into PCR 11 of TPM event
+//     logs are extracted into FirmwareLogState.uki.
+//   - 9: The kernel command line and kernel digest of systemd-boot boots are
+//     extracted into FirmwareLogState.linux_kernel.
+//   - 10: Every entry of the PK, KEK, db, and dbx signature lists, including
+//     its signature type and owner GUID, is extracted into Database.entries.
+//   - 11: The MOK measurements in PCR 14 and the SBAT level of TPM event logs
+//     are extracted into SecureBootState.mok.
+//   - 12: The boot variables and platform configuration events of PCR 1 are
+//     extracted into FirmwareLogState.boot_config.
+//   - 13: The GUID partition table measured into PCR 5 is extracted into
+//     FirmwareLogState.gpt.
+//   - 14: TPM event logs whose PCRs 0-7 were capped with error separators
+//     are reported with a FINDING_TYPE_PCRS_CAPPED finding and a CappedError
+//     rather than failing the per-register extractions.
+//   - 15: GrubFile.hash annotates the algorithm of GRUB file digests, and the
+//     GRUB file and command digests of each verified bank are reported in
+//     GrubFile.bank_digests and GrubState.command_digests.
+//   - 16: The untrusted device path, file path, partition GUID, and image
+//     location of each EFI application are decoded from its image load event
+//     into EfiApp.
+//   - 17: Secure Boot and shim variables are only extracted by name if they
+//     have the vendor GUID of that name, e.g., EFI_IMAGE_SECURITY_DATABASE_GUID
+//     for db. A variable of another vendor fails SecureBootState extraction,
+//     and is not extracted as the SBAT level.
+const SchemaVersion = 17
+
 // Opts gives options for extracting information from an event log.
 type Opts struct {
+	// CompatLevel pins the extraction output to a SchemaVersion, so stored
+	// FirmwareLogStates (e.g., golden states) stay equal when the library is
+	// updated. If 0, the current SchemaVersion is used.
+	CompatLevel uint32
+	// Loader is the second-stage bootloader whose events are extracted.
+	// Set it to AutoDetect if the OS image's bootloader is not known in advance.
 	Loader Bootloader
+	// VariablePolicy controls how missing or empty Secure Boot variables are
+	// handled. The zero value applies the default checks.
+	VariablePolicy VariablePolicy
 	// AllowEmptySBVar allows the SecureBoot variable to be empty in addition to length 1 (0 or 1).
 	// This can be used when the SecureBoot variable is not initialized.
+	//
+	// Deprecated: Set VariablePolicy.SecureBoot to OptionalVariable instead.
 	AllowEmptySBVar bool
+	// AllowDebugMode reports a UEFI debugger that was enabled during boot in
+	// SecureBootState.DebugModeEnabled. By default, extraction of the Secure
+	// Boot state fails, as the debugger can modify the firmware at runtime.
+	AllowDebugMode bool
+	// CollectPostEBSEvents collects the EFI application and ExitBootServices
+	// register events measured after the ExitBootServices invocation into
+	// EfiState.UntrustedPostEbsEvents. These events are not interpreted.
+	CollectPostEBSEvents bool
+	// CertMetadata includes the parsed subject, issuer, validity period, and
+	// SHA-256 fingerprint of each Secure Boot database certificate in
+	// Certificate.Metadata.
+	CertMetadata bool
+	// EventSummaries includes a human-readable summary of each event in
+	// FirmwareLogState.RawEvents, so consumers can display events without
+	// parsing them.
+	EventSummaries bool
+	// DigestResolvers verify external content referenced by digests in event
+	// data. Their results are recorded in FirmwareLogState.DigestResolutions.
+	DigestResolvers []DigestResolver
+	// ResolverContext is passed to the DigestResolvers, e.g., to bound the
+	// time spent fetching content. If nil, context.Background() is used.
+	ResolverContext context.Context
+	// Quirks enables workarounds for nonstandard event logs, when the event
+	// log is parsed, e.g., by tpmeventlog.ReplayAndExtract, and during
+	// extraction. The quirks that were needed are reported in
+	// FirmwareLogState.AppliedQuirks.
+	Quirks tcg.Quirks
+	// QuirkProfile names a tcg.QuirkSet, e.g., "ovmf-edk2-2022", whose quirks
+	// are enabled in addition to Quirks. It is recorded in
+	// FirmwareLogState.QuirkProfile.
+	QuirkProfile string
+	// RejectUnknownEventTags fails extraction if the GRUB registers contain
+	// EV_EVENT_TAG events other than the Linux EFI stub measurements, which
+	// are otherwise ignored.
+	RejectUnknownEventTags bool
+	// Strict fails extraction with a MissingSectionError for each state that
+	// would otherwise be silently omitted, e.g., EfiState when the event log
+	// has no verified ExitBootServices invocation, for verifiers that must not
+	// ignore anomalies. Event logs with redacted registers, allowed with
+	// tcg.ParseOpts.AllowRedactedMRs, fail with a RedactedMRsError. The
+	// partial FirmwareLogState is still returned.
+	Strict bool
+	// TrustAnchors, if set, are the certificates the caller trusts, e.g., an
+	// enterprise UEFI CA. Each db and authority certificate is annotated with
+	// Certificate.Trust: revoked if it or its issuer is in dbx, otherwise
+	// trusted if it is an anchor or was issued by one, and untrusted
+	// otherwise.
+	TrustAnchors []TrustAnchor
+	// ParseOpts, if set, replaces the options used when the event log is
+	// parsed, e.g., by tpmeventlog.ReplayAndExtract, so deployments can
+	// tighten or relax parsing. The enabled quirks are added to its Quirks.
+	// If nil, the parser's defaults are used, e.g., CCELs allow padding.
+	ParseOpts *tcg.ParseOpts
+	// FindingSink, if set, is called with each finding of the extracted
+	// FirmwareLogState, e.g., to log or alert on findings without inspecting
+	// every returned state. The findings are still recorded in
+	// FirmwareLogState.Findings.
+	FindingSink func(*pb.Finding)
+}
+
+// ReportFindings calls FindingSink, if set, with each finding of state.
+// FirmwareLogState reports its findings itself. Callers that add findings to
+// the state it returns, e.g., with ReportQuirks, should clear FindingSink
+// when calling FirmwareLogState and report the final state instead, so each
+// finding is reported once.
+func (o Opts) ReportFindings(state *pb.FirmwareLogState) {
+	if o.FindingSink == nil {
+		return
+	}
+	for _, finding := range state.GetFindings() {
+		o.FindingSink(finding)
+	}
+}
+
+// MissingSectionError is returned with Opts.Strict when a FirmwareLogState
+// section is omitted without an extraction error.
+type MissingSectionError struct {
+	// Section is the omitted FirmwareLogState field, e.g., "efi".
+	Section string
+	// Reason describes why the section was omitted.
+	Reason string
+}
+
+// Error returns a human-friendly description of the omitted section.
+func (e MissingSectionError) Error() string {
+	return fmt.Sprintf("missing %s state: %s", e.Section, e.Reason)
+}
+
+// GRUBMeasurementsNotFoundError is returned when GRUB extraction is requested
+// (e.g., with Opts.Loader set to GRUB) but the event log contains no GRUB
+// measurements.
+type GRUBMeasurementsNotFoundError struct {
+	// SearchedMRs are the measurement register indexes searched for GRUB
+	// events, as encoded in the event log.
+	SearchedMRs []uint32
+	// EventTypeCounts counts the events of each (untrusted) type found in the
+	// searched registers.
+	EventTypeCounts map[tcg.EventType]int
+}
+
+// Error returns a human-friendly description of the missing GRUB measurements.
+func (e GRUBMeasurementsNotFoundError) Error() string {
+	return fmt.Sprintf("no GRUB measurements found: searched MRs %v, found event types %v", e.SearchedMRs, e.EventTypeCounts)
+}
+
+// DetectBootloader inspects the loader and EFI application events to determine
+// which second-stage bootloader measured into the event log.
+// GRUB is detected by its EV_IPL command measurements. systemd-boot and the
+// Windows Boot Manager are detected by the file path of the loaded EFI
+// applications.
+// It returns UnsupportedLoader if no known bootloader is found.
+//
+// The events are not verified; callers should only use the result to choose
+// Opts.Loader before extracting from a replayed event log.
+func DetectBootloader(events []tcg.Event, registerCfg RegisterConfig) Bootloader {
+	for _, event := range events {
+		if event.MRIndex() != registerCfg.GRUBCmdIdx || event.UntrustedType() != tcg.Ipl {
+			continue
+		}
+		for _, prefix := range validPrefixes {
+			if bytes.HasPrefix(event.RawData(), prefix) {
+				return GRUB
+			}
+		}
+	}
+	for _, path := range efiAppFilePaths(events, registerCfg) {
+		path = strings.ToLower(path)
+		switch {
+		case strings.HasSuffix(path, `\bootmgfw.efi`):
+			return WindowsBootManager
+		case strings.Contains(path, `\systemd-boot`):
+			return SystemdBoot
+		}
+	}
+	return UnsupportedLoader
+}
+
+// efiAppFilePaths returns the file paths of the EFI applications loaded by the
+// firmware. Events with malformed device paths are skipped.
+func efiAppFilePaths(events []tcg.Event, registerCfg RegisterConfig) []string {
+	var paths []string
+	for _, event := range events {
+		if event.MRIndex() != registerCfg.EFIAppIdx || event.UntrustedType() != tcg.EFIBootServicesApplication {
+			continue
+		}
+		image, err := tcg.ParseEFIImageLoad(bytes.NewReader(event.RawData()))
+		if err != nil {
+			continue
+		}
+		devicePath, err := image.DevicePath()
+		if err != nil {
+			continue
+		}
+		if path := tcg.FilePathString(devicePath); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
 }
 
 // FirmwareLogState extracts event info from a verified TCG PC Client event
@@ -73,50 +305,263 @@ type Opts struct {
 // It is the caller's responsibility to ensure that the passed events have
 // been replayed (e.g., using `tcg.ParseAndReplay`) against a verified measurement
 // register bank.
-func FirmwareLogState(events []tcg.Event, hash crypto.Hash, registerCfg registerConfig, opts Opts) (*pb.FirmwareLogState, error) {
+//
+// The returned FirmwareLogState shares the memory of the event data and
+// digests. If the event log was parsed with tcg.ParseBuffers, copy it, e.g.,
+// with Opts.OwnedState or proto.Clone, before releasing the buffers.
+func FirmwareLogState(events []tcg.Event, hash crypto.Hash, registerCfg RegisterConfig, opts Opts) (*pb.FirmwareLogState, error) {
 	var joined error
-	tcgHash, err := tpm2.HashToAlgorithm(hash)
+	compatLevel, err := opts.compatLevel()
+	if err != nil {
+		return nil, err
+	}
+	quirks, err := opts.EnabledQuirks()
+	if err != nil {
+		return nil, err
+	}
+	pbHash, err := pb.HashAlgoFromCryptoHash(hash)
 	if err != nil {
 		return nil, err
 	}
+	if compatLevel >= 14 && registerCfg.LogType == pb.LogType_LOG_TYPE_TCG2 {
+		capping, err := tcg.FindCapping(events, hash)
+		if err != nil {
+			return nil, err
+		}
+		if capping != nil {
+			return cappedState(events, hash, pbHash, registerCfg, opts, compatLevel, capping), CappedError{capping}
+		}
+	}
 
 	platform, err := registerCfg.PlatformExtracter(hash, events)
 	if err != nil {
 		joined = errors.Join(joined, err)
+	} else if platform == nil && opts.Strict {
+		joined = errors.Join(joined, MissingSectionError{Section: "platform", Reason: fmt.Sprintf("no platform state for %s event logs", registerCfg.Name)})
 	}
 	sbState, err := SecureBootState(events, registerCfg, opts)
 	if err != nil {
 		joined = errors.Join(joined, err)
 	}
-	efiState, err := EfiState(hash, events, registerCfg)
+	if compatLevel >= 11 && sbState != nil && registerCfg.LogType == pb.LogType_LOG_TYPE_TCG2 {
+		sbState.Mok = mokState(hash, events, registerCfg, sbState, compatLevel)
+	}
+	efiState, err := efiStateWithQuirks(hash, events, registerCfg, quirks, compatLevel)
 
 	if err != nil {
 		joined = errors.Join(joined, err)
+	} else if efiState == nil && opts.Strict {
+		joined = errors.Join(joined, MissingSectionError{Section: "efi", Reason: fmt.Sprintf("no verified ExitBootServices invocation in %s%d", registerCfg.Name, registerCfg.ExitBootServicesIdx)})
+	}
+	if opts.CollectPostEBSEvents && efiState != nil {
+		efiState.UntrustedPostEbsEvents = PostExitBootServicesEvents(hash, events, registerCfg)
 	}
 
 	var grub *pb.GrubState
 	var kernel *pb.LinuxKernelState
-	if opts.Loader == GRUB {
-		grub, err = registerCfg.GRUBExtracter(hash, events)
+	var kexec []*pb.KexecState
+	loader := opts.Loader
+	if loader == AutoDetect {
+		loader = DetectBootloader(events, registerCfg)
+		if loader == UnsupportedLoader && opts.Strict {
+			joined = errors.Join(joined, MissingSectionError{Section: "linux_kernel", Reason: "no supported bootloader detected"})
+		}
+	}
+	// Loader events after ExitBootServices belong to kexec-loaded kernels.
+	firstGeneration, _ := splitKexecGenerations(hash, events, registerCfg)
+	if loader == GRUB {
+		grub, err = registerCfg.GRUBExtracter(hash, firstGeneration)
 
 		if err != nil {
 			joined = errors.Join(joined, err)
 		}
 		kernel, err = LinuxKernelStateFromGRUB(grub)
+		if err != nil {
+			joined = errors.Join(joined, err)
+		} else if grub != nil && kernel.GetCommandLine() == "" && opts.Strict {
+			joined = errors.Join(joined, MissingSectionError{Section: "linux_kernel", Reason: "no kernel command line in the GRUB commands"})
+		}
+		kexec, err = KexecStates(hash, events, registerCfg)
+		if err != nil {
+			joined = errors.Join(joined, err)
+		}
+	}
+	if loader == SystemdBoot && compatLevel >= 9 {
+		kernel, err = LinuxKernelStateFromSystemdBoot(hash, firstGeneration, registerCfg)
+		if err != nil {
+			joined = errors.Join(joined, err)
+		}
+	}
+	if compatLevel >= 5 || opts.RejectUnknownEventTags {
+		stub, err := LinuxStubState(firstGeneration, registerCfg, opts.RejectUnknownEventTags)
+		if err != nil {
+			joined = errors.Join(joined, err)
+		}
+		if compatLevel >= 5 && stub != nil {
+			if kernel == nil {
+				kernel = &pb.LinuxKernelState{}
+			}
+			kernel.LoadOptionsDigest = stub.LoadOptionsDigest
+			kernel.InitrdDigest = stub.InitrdDigest
+		}
+	}
+	if compatLevel < 15 {
+		withoutBankDigests(grub)
+		for _, k := range kexec {
+			withoutBankDigests(k.GetGrub())
+		}
+	}
+	if compatLevel >= 6 && kernel != nil {
+		kernel.LoadOptionsMatch = LoadOptionsMatch(hash, kernel.GetCommandLine(), kernel.GetLoadOptionsDigest())
+	}
+	var findings []*pb.Finding
+	findings = append(findings, hashFindings(pbHash)...)
+	findings = append(findings, repeatedEventsFindings(events)...)
+	findings = append(findings, secureBootFindings(events, registerCfg)...)
+	findings = append(findings, legacyOptionROMFindings(events, registerCfg)...)
+	findings = append(findings, dbxFindings(sbState)...)
+	if compatLevel >= 4 {
+		findings = append(findings, SizeAnomalyFindings(tcg.CheckEventSizes(events))...)
+	}
+	if compatLevel >= 6 {
+		findings = append(findings, loadOptionsFindings(kernel)...)
+	}
+
+	var windowsSipa *pb.WindowsSipaState
+	if compatLevel >= 7 && loader == WindowsBootManager && registerCfg.LogType == pb.LogType_LOG_TYPE_TCG2 {
+		windowsSipa, err = WindowsSipaState(hash, events)
+		if err != nil {
+			joined = errors.Join(joined, err)
+		} else if windowsSipa == nil && opts.Strict {
+			joined = errors.Join(joined, MissingSectionError{Section: "windows_sipa", Reason: "no measured SIPA events"})
+		}
+	}
+
+	var uki *pb.UkiState
+	if compatLevel >= 8 && registerCfg.LogType == pb.LogType_LOG_TYPE_TCG2 {
+		uki, err = UkiState(hash, events)
 		if err != nil {
 			joined = errors.Join(joined, err)
 		}
 	}
-	return &pb.FirmwareLogState{
-		Platform:    platform,
-		SecureBoot:  sbState,
-		Efi:         efiState,
-		RawEvents:   tcg.ConvertToPbEvents(hash, events),
-		Hash:        pb.HashAlgo(tcgHash),
-		Grub:        grub,
-		LinuxKernel: kernel,
-		LogType:     registerCfg.LogType,
-	}, joined
+
+	var bootConfig *pb.BootConfigState
+	if compatLevel >= 12 {
+		bootConfig, err = bootConfigState(events, registerCfg, compatLevel)
+		if err != nil {
+			joined = errors.Join(joined, err)
+		}
+	}
+
+	var gpt *pb.GptState
+	if compatLevel >= 13 {
+		gpt, err = GptState(events, registerCfg)
+		if err != nil {
+			joined = errors.Join(joined, err)
+		}
+	}
+
+	var drtm *pb.DrtmState
+	if registerCfg.Drtm != nil {
+		drtm, err = DrtmState(hash, events, *registerCfg.Drtm)
+		if err != nil {
+			joined = errors.Join(joined, err)
+		}
+	}
+	state := &pb.FirmwareLogState{
+		Platform:          platform,
+		SecureBoot:        sbState,
+		Efi:               efiState,
+		RawEvents:         tcg.ConvertToPbEventsWithOpts(hash, events, tcg.ConvertOpts{Summaries: opts.EventSummaries}),
+		Hash:              pbHash,
+		Grub:              grub,
+		LinuxKernel:       kernel,
+		LogType:           registerCfg.LogType,
+		Kexec:             kexec,
+		Findings:          findings,
+		Stats:             EventLogStats(events),
+		Drtm:              drtm,
+		DigestResolutions: DigestResolutions(opts.resolverContext(), events, opts.DigestResolvers),
+		SchemaVersion:     compatLevel,
+		QuirkProfile:      opts.QuirkProfile,
+		WindowsSipa:       windowsSipa,
+		Uki:               uki,
+		BootConfig:        bootConfig,
+		Gpt:               gpt,
+	}
+	ReportQuirks(state, extractionQuirks(events, registerCfg, opts, quirks))
+	opts.ReportFindings(state)
+	return state, joined
+}
+
+// OwnedState returns state, or a deep copy of it if the event log was parsed
+// with ParseOpts.Buffers. The byte fields of a FirmwareLogState, e.g.,
+// RawEvents, share the memory of the events it was extracted from, so they
+// must not outlive the ParseBuffers.
+func (o Opts) OwnedState(state *pb.FirmwareLogState) *pb.FirmwareLogState {
+	if state == nil || o.ParseOpts == nil || o.ParseOpts.Buffers == nil {
+		return state
+	}
+	return proto.Clone(state).(*pb.FirmwareLogState)
+}
+
+// resolverContext returns the context for the DigestResolvers.
+func (o Opts) resolverContext() context.Context {
+	if o.ResolverContext == nil {
+		return context.Background()
+	}
+	return o.ResolverContext
+}
+
+// compatLevel returns the SchemaVersion to extract with.
+func (o Opts) compatLevel() (uint32, error) {
+	if o.CompatLevel == 0 {
+		return SchemaVersion, nil
+	}
+	if o.CompatLevel > SchemaVersion {
+		return 0, fmt.Errorf("unsupported compat level %d: the latest schema version is %d", o.CompatLevel, SchemaVersion)
+	}
+	return o.CompatLevel, nil
+}
+
+// hashFindings reports a weak bank finding if the events were replayed
+// against a bank using a weak hash algorithm. Extraction still succeeds, so
+// policy can decide whether to accept the state.
+func hashFindings(hash pb.HashAlgo) []*pb.Finding {
+	if !hash.Weak() {
+		return nil
+	}
+	return []*pb.Finding{{
+		Type:        pb.FindingType_FINDING_TYPE_WEAK_BANK,
+		Description: fmt.Sprintf("event log was verified against the %v bank, which is not collision resistant", hash),
+	}}
+}
+
+// PaddingFindings reports a padding garbage finding if the trailing padding
+// of an event log parsed with tcg.ParseOpts.AllowPadding is not all fill
+// bytes. Callers that parse the log themselves can append these to
+// FirmwareLogState.Findings.
+func PaddingFindings(padding *tcg.Padding) []*pb.Finding {
+	if padding == nil || padding.Clean() {
+		return nil
+	}
+	return []*pb.Finding{{
+		Type:        pb.FindingType_FINDING_TYPE_PADDING_GARBAGE,
+		Description: fmt.Sprintf("event log padding of %d bytes at offset %d contains non-fill data at offset %d", padding.Length, padding.Offset, padding.GarbageOffset),
+	}}
+}
+
+// SizeAnomalyFindings reports a finding for each event whose data size does
+// not match its type. See tcg.CheckEventSize.
+func SizeAnomalyFindings(anomalies []tcg.SizeAnomaly) []*pb.Finding {
+	var findings []*pb.Finding
+	for _, anomaly := range anomalies {
+		findings = append(findings, &pb.Finding{
+			Type:        pb.FindingType_FINDING_TYPE_EVENT_SIZE_ANOMALY,
+			Description: anomaly.String(),
+		})
+	}
+	return findings
 }
 
 func contains(set [][]byte, value []byte) bool {
@@ -133,20 +578,22 @@ type separatorInfo struct {
 	separatorDigests [][]byte
 }
 
+// separatorInfos memoizes the separatorInfo of each hash algorithm, as it is
+// needed by every extraction.
+var separatorInfos sync.Map
+
 // getSeparatorInfo is used to return the valid event data and their corresponding
 // digests. This is useful for events like separators, where the data is known
-// ahead of time.
+// ahead of time. The result is shared, so callers must not modify it.
 func getSeparatorInfo(hash crypto.Hash) *separatorInfo {
-	hasher := hash.New()
-	// From the PC Client Firmware Profile spec, on the separator event:
-	// The event field MUST contain the hex value 00000000h or FFFFFFFFh.
-	sepData := [][]byte{{0, 0, 0, 0}, {0xff, 0xff, 0xff, 0xff}}
-	sepDigests := make([][]byte, 0, len(sepData))
-	for _, value := range sepData {
-		hasher.Write(value)
-		sepDigests = append(sepDigests, hasher.Sum(nil))
+	if info, ok := separatorInfos.Load(hash); ok {
+		return info.(*separatorInfo)
 	}
-	return &separatorInfo{separatorData: sepData, separatorDigests: sepDigests}
+	info, _ := separatorInfos.LoadOrStore(hash, &separatorInfo{
+		separatorData:    [][]byte{wellknown.SeparatorData, wellknown.ErrorSeparatorData},
+		separatorDigests: wellknown.SeparatorDigests(hash),
+	})
+	return info.(*separatorInfo)
 }
 
 // checkIfValidSeparator returns true if both the separator event's type and
@@ -176,7 +623,10 @@ func checkIfValidSeparator(event tcg.Event, sepInfo *separatorInfo) (bool, error
 	return true, nil
 }
 
-func convertToPbDatabase(certs []x509.Certificate, hashes [][]byte) *pb.Database {
+// convertToPbDatabase converts the certificates, hashes, and signature list
+// entries of a Secure Boot database. If anchors are given, the trust status
+// of each certificate is set relative to them and to the dbx revocations.
+func convertToPbDatabase(certs []x509.Certificate, hashes [][]byte, entries []tcg.SignatureEntry, opts Opts, anchors []TrustAnchor, dbx revocations) *pb.Database {
 	protoCerts := make([]*pb.Certificate, 0, len(certs))
 	for _, cert := range certs {
 		wkEnum, err := matchWellKnown(cert)
@@ -186,33 +636,158 @@ func convertToPbDatabase(certs []x509.Certificate, hashes [][]byte) *pb.Database
 		} else {
 			pbCert.Representation = &pb.Certificate_Der{Der: cert.Raw}
 		}
+		if opts.CertMetadata {
+			pbCert.Metadata = certMetadata(cert)
+		}
+		if len(anchors) > 0 {
+			pbCert.Trust, pbCert.TrustAnchor = trustStatus(cert, anchors, dbx)
+		}
 		protoCerts = append(protoCerts, &pbCert)
 	}
 	return &pb.Database{
-		Certs:  protoCerts,
-		Hashes: hashes,
+		Certs:   protoCerts,
+		Hashes:  hashes,
+		Entries: convertToPbSignatureEntries(entries, certs, hashes),
 	}
 }
 
+// convertToPbSignatureEntries converts signature list entries. Entries
+// matching the next of the database's certificates or hashes refer to them
+// by index, which holds for all entries unless some were skipped.
+func convertToPbSignatureEntries(entries []tcg.SignatureEntry, certs []x509.Certificate, hashes [][]byte) []*pb.SignatureEntry {
+	var (
+		out     []*pb.SignatureEntry
+		certIdx int
+		hashIdx int
+	)
+	for _, entry := range entries {
+		pbEntry := &pb.SignatureEntry{
+			Type:     pb.SignatureType(pb.SignatureType_value["SIGNATURE_TYPE_"+entry.Type]),
+			TypeGuid: entry.TypeGUID,
+			Owner:    entry.Owner,
+		}
+		switch {
+		case entry.Cert != nil && certIdx < len(certs) && bytes.Equal(certs[certIdx].Raw, entry.Data):
+			pbEntry.Value = &pb.SignatureEntry_CertIndex{CertIndex: uint32(certIdx)}
+			certIdx++
+		case entry.Type == "SHA256" && hashIdx < len(hashes) && bytes.Equal(hashes[hashIdx], entry.Data):
+			pbEntry.Value = &pb.SignatureEntry_HashIndex{HashIndex: uint32(hashIdx)}
+			hashIdx++
+		default:
+			pbEntry.Value = &pb.SignatureEntry_Data{Data: entry.Data}
+		}
+		out = append(out, pbEntry)
+	}
+	return out
+}
+
+func certMetadata(cert x509.Certificate) *pb.CertificateMetadata {
+	fingerprint := sha256.Sum256(cert.Raw)
+	return &pb.CertificateMetadata{
+		Subject:           cert.Subject.String(),
+		Issuer:            cert.Issuer.String(),
+		NotBefore:         cert.NotBefore.Unix(),
+		NotAfter:          cert.NotAfter.Unix(),
+		Sha256Fingerprint: fingerprint[:],
+	}
+}
+
+// wellKnownCerts are the DER certificates of the WellKnownCertificate
+// values, used both to replace and to restore the DER.
+var wellKnownCerts = map[pb.WellKnownCertificate][]byte{
+	pb.WellKnownCertificate_MS_WINDOWS_PROD_PCA_2011:    wellknown.WindowsProductionPCA2011Cert,
+	pb.WellKnownCertificate_MS_THIRD_PARTY_UEFI_CA_2011: wellknown.MicrosoftUEFICA2011Cert,
+	pb.WellKnownCertificate_MS_THIRD_PARTY_KEK_CA_2011:  wellknown.MicrosoftKEKCA2011Cert,
+	pb.WellKnownCertificate_GCE_DEFAULT_PK:              wellknown.GceDefaultPKCert,
+}
+
 func matchWellKnown(cert x509.Certificate) (pb.WellKnownCertificate, error) {
-	if bytes.Equal(wellknown.WindowsProductionPCA2011Cert, cert.Raw) {
-		return pb.WellKnownCertificate_MS_WINDOWS_PROD_PCA_2011, nil
+	for wk, der := range wellKnownCerts {
+		if bytes.Equal(der, cert.Raw) {
+			return wk, nil
+		}
 	}
-	if bytes.Equal(wellknown.MicrosoftUEFICA2011Cert, cert.Raw) {
-		return pb.WellKnownCertificate_MS_THIRD_PARTY_UEFI_CA_2011, nil
+	return pb.WellKnownCertificate_UNKNOWN, errors.New("failed to find matching well known certificate")
+}
+
+// certDER returns the DER of an extracted certificate.
+func certDER(cert *pb.Certificate) ([]byte, error) {
+	if cert.GetWellKnown() == pb.WellKnownCertificate_UNKNOWN {
+		return cert.GetDer(), nil
 	}
-	if bytes.Equal(wellknown.MicrosoftKEKCA2011Cert, cert.Raw) {
-		return pb.WellKnownCertificate_MS_THIRD_PARTY_KEK_CA_2011, nil
+	der, ok := wellKnownCerts[cert.GetWellKnown()]
+	if !ok {
+		return nil, fmt.Errorf("unknown well known certificate %v", cert.GetWellKnown())
 	}
-	if bytes.Equal(wellknown.GceDefaultPKCert, cert.Raw) {
-		return pb.WellKnownCertificate_GCE_DEFAULT_PK, nil
+	return der, nil
+}
+
+// EncodeDatabase encodes an extracted Secure Boot database, e.g., db or dbx,
+// back into the EFI_SIGNATURE_LIST format of its UEFI variable, so it can be
+// compared byte-for-byte with an authored variable payload.
+//
+// If the database has signature list entries, each entry is encoded with its
+// own type and owner GUID, as in tcg.EncodeSignatureEntries, and owner is
+// ignored. Otherwise, e.g., for states extracted before CompatLevel 10, the
+// certificates and hashes are encoded with the given owner GUID, as in
+// tcg.EncodeEFISignatureList.
+func EncodeDatabase(db *pb.Database, owner string) ([]byte, error) {
+	if len(db.GetEntries()) > 0 {
+		entries, err := databaseSignatureEntries(db)
+		if err != nil {
+			return nil, err
+		}
+		return tcg.EncodeSignatureEntries(entries)
 	}
-	return pb.WellKnownCertificate_UNKNOWN, errors.New("failed to find matching well known certificate")
+	certs := make([]x509.Certificate, 0, len(db.GetCerts()))
+	for _, cert := range db.GetCerts() {
+		der, err := certDER(cert)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %v", err)
+		}
+		certs = append(certs, *parsed)
+	}
+	return tcg.EncodeEFISignatureList(certs, db.GetHashes(), owner)
+}
+
+// databaseSignatureEntries resolves the certificate and hash indexes of the
+// database's signature list entries.
+func databaseSignatureEntries(db *pb.Database) ([]tcg.SignatureEntry, error) {
+	entries := make([]tcg.SignatureEntry, 0, len(db.GetEntries()))
+	for i, entry := range db.GetEntries() {
+		out := tcg.SignatureEntry{TypeGUID: entry.GetTypeGuid(), Owner: entry.GetOwner()}
+		switch value := entry.GetValue().(type) {
+		case *pb.SignatureEntry_CertIndex:
+			if int(value.CertIndex) >= len(db.GetCerts()) {
+				return nil, fmt.Errorf("entry %d: certificate index %d out of range", i, value.CertIndex)
+			}
+			der, err := certDER(db.GetCerts()[value.CertIndex])
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: %v", i, err)
+			}
+			out.Data = der
+		case *pb.SignatureEntry_HashIndex:
+			if int(value.HashIndex) >= len(db.GetHashes()) {
+				return nil, fmt.Errorf("entry %d: hash index %d out of range", i, value.HashIndex)
+			}
+			out.Data = db.GetHashes()[value.HashIndex]
+		case *pb.SignatureEntry_Data:
+			out.Data = value.Data
+		default:
+			return nil, fmt.Errorf("entry %d has no signature", i)
+		}
+		entries = append(entries, out)
+	}
+	return entries, nil
 }
 
 // SecureBootState extracts Secure Boot information from a UEFI TCG2
 // firmware event log.
-func SecureBootState(replayEvents []tcg.Event, registerCfg registerConfig, opts Opts) (*pb.SecureBootState, error) {
+func SecureBootState(replayEvents []tcg.Event, registerCfg RegisterConfig, opts Opts) (*pb.SecureBootState, error) {
 	attestSbState, err := ParseSecurebootState(replayEvents, registerCfg, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse SecureBootState: %v", err)
@@ -220,24 +795,71 @@ func SecureBootState(replayEvents []tcg.Event, registerCfg registerConfig, opts
 	if len(attestSbState.PreSeparatorAuthority) != 0 {
 		return nil, fmt.Errorf("event log contained %v pre-separator authorities, which are not expected or supported", len(attestSbState.PreSeparatorAuthority))
 	}
+	compatLevel, err := opts.compatLevel()
+	if err != nil {
+		return nil, err
+	}
+	for _, anchor := range opts.TrustAnchors {
+		if anchor.Cert == nil {
+			return nil, fmt.Errorf("trust anchor %q has no certificate", anchor.Name)
+		}
+	}
+	authority, usages := authorityUsages(replayEvents, attestSbState.PostSeparatorAuthorityUses, registerCfg, compatLevel)
+	dbx := newRevocations(attestSbState.ForbiddenKeys, attestSbState.ForbiddenEntries)
+	if compatLevel < 10 {
+		attestSbState.PermittedEntries = nil
+		attestSbState.ForbiddenEntries = nil
+		attestSbState.PlatformKeyEntries = nil
+		attestSbState.ExchangeKeyEntries = nil
+	}
 	return &pb.SecureBootState{
-		Enabled:   attestSbState.Enabled,
-		Db:        convertToPbDatabase(attestSbState.PermittedKeys, attestSbState.PermittedHashes),
-		Dbx:       convertToPbDatabase(attestSbState.ForbiddenKeys, attestSbState.ForbiddenHashes),
-		Authority: convertToPbDatabase(attestSbState.PostSeparatorAuthority, nil),
-		Pk:        convertToPbDatabase(attestSbState.PlatformKeys, attestSbState.PlatformKeyHashes),
-		Kek:       convertToPbDatabase(attestSbState.ExchangeKeys, attestSbState.ExchangeKeyHashes),
+		Enabled:         attestSbState.Enabled,
+		Db:              convertToPbDatabase(attestSbState.PermittedKeys, attestSbState.PermittedHashes, attestSbState.PermittedEntries, opts, opts.TrustAnchors, dbx),
+		Dbx:             convertToPbDatabase(attestSbState.ForbiddenKeys, attestSbState.ForbiddenHashes, attestSbState.ForbiddenEntries, opts, nil, revocations{}),
+		Authority:       convertToPbDatabase(authority, nil, nil, opts, opts.TrustAnchors, dbx),
+		Pk:              convertToPbDatabase(attestSbState.PlatformKeys, attestSbState.PlatformKeyHashes, attestSbState.PlatformKeyEntries, opts, nil, revocations{}),
+		Kek:             convertToPbDatabase(attestSbState.ExchangeKeys, attestSbState.ExchangeKeyHashes, attestSbState.ExchangeKeyEntries, opts, nil, revocations{}),
+		AuthorityUsages: usages,
+
+		DebugModeEnabled: attestSbState.DebugModeEnabled,
+		DbxErrors:        convertToPbSignatureListErrors(attestSbState.ForbiddenErrors),
 	}, nil
 }
 
+func convertToPbSignatureListErrors(errs []tcg.SignatureListError) []*pb.SignatureListError {
+	var out []*pb.SignatureListError
+	for _, err := range errs {
+		out = append(out, &pb.SignatureListError{Offset: uint32(err.Offset), Error: err.Err.Error()})
+	}
+	return out
+}
+
+// dbxFindings reports a dbx parse errors finding if malformed dbx entries were
+// skipped, as revocations in them are missing from the state.
+func dbxFindings(sbState *pb.SecureBootState) []*pb.Finding {
+	errs := sbState.GetDbxErrors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return []*pb.Finding{{
+		Type:        pb.FindingType_FINDING_TYPE_DBX_PARSE_ERRORS,
+		Description: fmt.Sprintf("skipped %d malformed dbx entries, the first at offset %d: %s", len(errs), errs[0].GetOffset(), errs[0].GetError()),
+	}}
+}
+
 // EfiDriverState extracts EFI Driver information from a UEFI TCG2 firmware event log.
 // Obtained from section 3.3.4.3 PCR[2]-UEFI Drivers and UEFI Applications
 // https://trustedcomputinggroup.org/wp-content/uploads/TCG-PC-Client-Platform-Firmware-Profile-Version-1.06-Revision-52_pub-3.pdf
-func EfiDriverState(events []tcg.Event, registerCfg registerConfig) (*pb.EfiState, error) {
+func EfiDriverState(events []tcg.Event, registerCfg RegisterConfig) (*pb.EfiState, error) {
+	return efiDriverStateWithQuirks(events, registerCfg, 0, SchemaVersion)
+}
+
+func efiDriverStateWithQuirks(events []tcg.Event, registerCfg RegisterConfig, quirks tcg.Quirks, compatLevel uint32) (*pb.EfiState, error) {
 	var (
 		seenSeparator          bool
 		efiDriverStates        []*pb.EfiApp
 		efiRuntimeDriverStates []*pb.EfiApp
+		drivers                []*pb.EfiDriver
 	)
 	for _, e := range events {
 		if e.MRIndex() != registerCfg.FirmwareDriverIdx {
@@ -255,39 +877,147 @@ func EfiDriverState(events []tcg.Event, registerCfg registerConfig) (*pb.EfiStat
 				return nil, fmt.Errorf("duplicate separator at event %d", e.Num())
 			}
 			seenSeparator = true
-			if !bytes.Equal(e.RawData(), []byte{0, 0, 0, 0}) {
+			if !validSeparatorData(e.RawData(), quirks) {
 				return nil, fmt.Errorf("invalid separator data at event %d: %v", e.Num(), e.RawData())
 			}
 			if digestVerify != nil {
 				return nil, fmt.Errorf("invalid separator digest at event %d: %v", e.Num(), digestVerify)
 			}
 
-		case tcg.EFIBootServicesDriver:
-			if !seenSeparator {
-				// The EFI Boot Services Driver will use the EFI LoadImage service, so try loading it.
-				_, err := tcg.ParseEFIImageLoad(bytes.NewReader(e.RawData()))
-				if err != nil {
-					return nil, fmt.Errorf("failed parsing EFI image load at boot services driver event %d: %v", e.Num(), err)
-				}
-				efiDriverStates = append(efiDriverStates, &pb.EfiApp{Digest: e.ReplayedDigest()})
+		case tcg.EFIBootServicesDriver, tcg.EFIRuntimeServicesDriver:
+			// EFI drivers use the EFI LoadImage service, so try loading it.
+			driver, err := parseEfiDriver(e, seenSeparator, compatLevel)
+			if err != nil {
+				return nil, err
 			}
-		case tcg.EFIRuntimeServicesDriver:
-			if !seenSeparator {
-				// The EFI Runtime Services Driver will use the EFI LoadImage service, so try loading it.
-				_, err := tcg.ParseEFIImageLoad(bytes.NewReader(e.RawData()))
-				if err != nil {
-					return nil, fmt.Errorf("failed parsing EFI image load at boot services driver event %d: %v", e.Num(), err)
-				}
+			drivers = append(drivers, driver)
+			if seenSeparator {
+				continue
+			}
+			if driver.GetRuntime() {
 				efiRuntimeDriverStates = append(efiRuntimeDriverStates, &pb.EfiApp{Digest: e.ReplayedDigest()})
+			} else {
+				efiDriverStates = append(efiDriverStates, &pb.EfiApp{Digest: e.ReplayedDigest()})
 			}
 		}
 	}
 	return &pb.EfiState{
 		BootServicesDrivers:    efiDriverStates,
 		RuntimeServicesDrivers: efiRuntimeDriverStates,
+		Drivers:                drivers,
 	}, nil
 }
 
+// efiApp returns the EfiApp of an EFI application load event, with the
+// untrusted device path and image location decoded from the event data.
+// Malformed event data leaves those fields unset, as the digest is what is
+// verified.
+func efiApp(e tcg.Event) *pb.EfiApp {
+	app := &pb.EfiApp{Digest: e.ReplayedDigest()}
+	imgLoad, err := tcg.ParseEFIImageLoad(bytes.NewReader(e.RawData()))
+	if err != nil {
+		return app
+	}
+	app.UntrustedImageLocation = imgLoad.Header.LoadAddr
+	app.UntrustedImageLength = imgLoad.Header.Length
+	dp, err := imgLoad.DevicePath()
+	if err != nil {
+		return app
+	}
+	app.UntrustedDevicePath = tcg.DevicePathString(dp)
+	app.UntrustedFilePath = tcg.FilePathString(dp)
+	for _, element := range dp {
+		if guid, ok := element.PartitionGUID(); ok {
+			app.UntrustedPartitionGuid = guid
+			break
+		}
+	}
+	return app
+}
+
+// devicePathString returns the UEFI text form of a device path. Before
+// SchemaVersion 16, messaging device nodes used the generic form, and hard
+// drive nodes omitted their partition signature.
+func devicePathString(dp []tcg.EFIDevicePathElement, compatLevel uint32) string {
+	if compatLevel >= 16 {
+		return tcg.DevicePathString(dp)
+	}
+	nodes := make([]string, 0, len(dp))
+	for _, e := range dp {
+		switch {
+		case e.Type == tcg.MediaDevice && e.Subtype == 1 && len(e.Data) == 38:
+			partition := binary.LittleEndian.Uint32(e.Data[:4])
+			start := binary.LittleEndian.Uint64(e.Data[4:12])
+			size := binary.LittleEndian.Uint64(e.Data[12:20])
+			nodes = append(nodes, fmt.Sprintf("HD(%d,%#x,%#x)", partition, start, size))
+		case e.Type == tcg.MessagingDevice:
+			nodes = append(nodes, fmt.Sprintf("Path(%d,%d,%x)", e.Type, e.Subtype, e.Data))
+		default:
+			nodes = append(nodes, e.String())
+		}
+	}
+	return strings.Join(nodes, "/")
+}
+
+// parseEfiDriver parses a boot or runtime services driver load event.
+func parseEfiDriver(e tcg.Event, postSeparator bool, compatLevel uint32) (*pb.EfiDriver, error) {
+	runtime := e.Type == tcg.EFIRuntimeServicesDriver
+	kind := "boot services"
+	if runtime {
+		kind = "runtime services"
+	}
+	driver := &pb.EfiDriver{
+		Digest:        e.ReplayedDigest(),
+		EventNum:      e.Num(),
+		Runtime:       runtime,
+		PostSeparator: postSeparator,
+	}
+	imgLoad, err := tcg.ParseEFIImageLoad(bytes.NewReader(e.RawData()))
+	if err != nil {
+		// Drivers loaded after the separator were not part of the EfiState
+		// before they were recorded, so malformed event data does not fail
+		// the extraction.
+		if postSeparator {
+			driver.UntrustedImageLoadError = err.Error()
+			return driver, nil
+		}
+		return nil, fmt.Errorf("failed parsing EFI image load at %s driver event %d: %v", kind, e.Num(), err)
+	}
+	// The device path is not measured, so a malformed one does not make the
+	// driver state invalid.
+	dp, err := imgLoad.DevicePath()
+	if err != nil {
+		driver.UntrustedDevicePathError = err.Error()
+		return driver, nil
+	}
+	driver.UntrustedDevicePath = devicePathString(dp, compatLevel)
+	driver.UntrustedOptionRom = isPCIOptionROM(dp)
+	return driver, nil
+}
+
+// isPCIOptionROM reports whether the device path identifies an image read
+// from PCI memory-mapped I/O, i.e., an option ROM.
+//
+// We consider a driver to have originated from PCI-MMIO if any number
+// of elements in the device path [1] were PCI devices, and are followed by
+// an element representing a "relative offset range" read.
+// In the wild, we have typically observed 4-tuple device paths for such
+// devices: ACPI device -> PCI device -> PCI device -> relative offset.
+//
+// [1]: See section 9 of the UEFI specification v2.6 or greater.
+func isPCIOptionROM(dp []tcg.EFIDevicePathElement) bool {
+	var seenPCI bool
+	for _, e := range dp {
+		if e.IsPCI() {
+			seenPCI = true
+		}
+		if seenPCI && e.IsRelativeOffsetRange() {
+			return true
+		}
+	}
+	return false
+}
+
 // PlatformState extracts platform information from a UEFI TCG2 firmware
 // event log.
 func PlatformState(hash crypto.Hash, events []tcg.Event) (*pb.PlatformState, error) {
@@ -343,38 +1073,47 @@ func PlatformState(hash crypto.Hash, events []tcg.Event) (*pb.PlatformState, err
 
 // EfiState extracts EFI app information from a UEFI TCG2 firmware
 // event log.
-func EfiState(hash crypto.Hash, events []tcg.Event, registerCfg registerConfig) (*pb.EfiState, error) {
+func EfiState(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig) (*pb.EfiState, error) {
+	return efiStateWithQuirks(hash, events, registerCfg, 0, SchemaVersion)
+}
+
+func efiStateWithQuirks(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig, quirks tcg.Quirks, compatLevel uint32) (*pb.EfiState, error) {
 	// We pre-compute various event digests, and check if those event type have
 	// been modified. We only trust events that come before the
 	// ExitBootServices() request.
 	separatorInfo := getSeparatorInfo(hash)
 
-	hasher := hash.New()
-	hasher.Write([]byte(tcg.CallingEFIApplication))
-	callingEFIAppDigest := hasher.Sum(nil)
-
-	hasher.Reset()
-	hasher.Write([]byte(tcg.ExitBootServicesInvocation))
-	exitBootSvcDigest := hasher.Sum(nil)
+	callingEFIAppDigest := tcg.EFIActionDigest(hash, tcg.CallingEFIApplication)
+	exitBootSvcDigest := tcg.EFIActionDigest(hash, tcg.ExitBootServicesInvocation)
 
 	var efiAppStates []*pb.EfiApp
 	var seenSeparator4 bool
 	var seenSeparator5 bool
 	var seenCallingEfiApp bool
 	var seenExitBootServices bool
+	var ebsResult pb.ExitBootServicesResult
 	for _, event := range events {
 		index := event.MRIndex()
 		//  MRs corresponding to EFI apps and the Exit Boot Services event.
 		if index != registerCfg.EFIAppIdx && index != registerCfg.ExitBootServicesIdx {
 			continue
 		}
+		if seenExitBootServices {
+			// Only the untrusted ExitBootServices result is recorded after
+			// the invocation. A failed call may be retried, so the last
+			// result wins.
+			if result := exitBootServicesResult(event, registerCfg); result != pb.ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_UNSPECIFIED {
+				ebsResult = result
+			}
+			continue
+		}
 		evtType := event.UntrustedType()
 
 		// Switch statements won't work since duplicate cases will get triggered like an if, else-if, else.			// Process Calling EFI Application event.
 		// See https://github.com/golang/go/commit/2d9378c7f6dfbbe82d1bbd806093c2dfe57d7e17
 		// PCRs use different indexes, but RTMRs do not.
 		if index == registerCfg.EFIAppIdx {
-			if bytes.Equal(callingEFIAppDigest, event.ReplayedDigest()) {
+			if subtle.ConstantTimeCompare(callingEFIAppDigest, event.ReplayedDigest()) == 1 {
 				if evtType != tcg.EFIAction {
 					return nil, fmt.Errorf("%s%d contains CallingEFIApp event but non EFIAction type: %d",
 						registerCfg.Name, index, evtType)
@@ -396,7 +1135,11 @@ func EfiState(hash crypto.Hash, events []tcg.Event, registerCfg registerConfig)
 				if !seenCallingEfiApp {
 					return nil, fmt.Errorf("found EFIBootServicesApplication in %s%d before CallingEFIApp event", registerCfg.Name, index)
 				}
-				efiAppStates = append(efiAppStates, &pb.EfiApp{Digest: event.ReplayedDigest()})
+				if compatLevel >= 16 {
+					efiAppStates = append(efiAppStates, efiApp(event))
+				} else {
+					efiAppStates = append(efiAppStates, &pb.EfiApp{Digest: event.ReplayedDigest()})
+				}
 			}
 
 			isSeparator, err := checkIfValidSeparator(event, separatorInfo)
@@ -412,7 +1155,7 @@ func EfiState(hash crypto.Hash, events []tcg.Event, registerCfg registerConfig)
 		}
 		if index == registerCfg.ExitBootServicesIdx {
 			// Process ExitBootServices event.
-			if bytes.Equal(exitBootSvcDigest, event.ReplayedDigest()) {
+			if subtle.ConstantTimeCompare(exitBootSvcDigest, event.ReplayedDigest()) == 1 {
 				if evtType != tcg.EFIAction {
 					return nil, fmt.Errorf("%s%d contains ExitBootServices event but non EFIAction type: %d",
 						registerCfg.Name, index, evtType)
@@ -423,7 +1166,7 @@ func EfiState(hash crypto.Hash, events []tcg.Event, registerCfg registerConfig)
 				// Don't process any events after Boot Manager has requested
 				// ExitBootServices().
 				seenExitBootServices = true
-				break
+				continue
 			}
 
 			isSeparator, err := checkIfValidSeparator(event, separatorInfo)
@@ -442,7 +1185,7 @@ func EfiState(hash crypto.Hash, events []tcg.Event, registerCfg registerConfig)
 	// Otherwise, software further down the bootchain could extend bad
 	// PCR4/RTMR2 measurements.
 	if seenExitBootServices {
-		efiDriver, err := EfiDriverState(events, registerCfg)
+		efiDriver, err := efiDriverStateWithQuirks(events, registerCfg, quirks, compatLevel)
 		if err != nil {
 			return nil, err
 		}
@@ -450,11 +1193,70 @@ func EfiState(hash crypto.Hash, events []tcg.Event, registerCfg registerConfig)
 			Apps:                   efiAppStates,
 			BootServicesDrivers:    efiDriver.BootServicesDrivers,
 			RuntimeServicesDrivers: efiDriver.RuntimeServicesDrivers,
+			Drivers:                efiDriver.Drivers,
+
+			UntrustedExitBootServicesResult: ebsResult,
 		}, nil
 	}
 	return nil, nil
 }
 
+// exitBootServicesResult returns the result of ExitBootServices() measured by
+// the event, or EXIT_BOOT_SERVICES_RESULT_UNSPECIFIED if the event is not an
+// "Exit Boot Services Returned" event.
+func exitBootServicesResult(event tcg.Event, registerCfg RegisterConfig) pb.ExitBootServicesResult {
+	if event.MRIndex() != registerCfg.ExitBootServicesIdx || event.Type != tcg.EFIAction {
+		return pb.ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_UNSPECIFIED
+	}
+	if DigestEquals(event, event.RawData()) != nil {
+		return pb.ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_UNSPECIFIED
+	}
+	action, _ := tcg.KnownEFIAction(event.RawData())
+	switch action {
+	case tcg.ExitBootServicesSuccess:
+		return pb.ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_SUCCESS
+	case tcg.ExitBootServicesFailure:
+		return pb.ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_FAILURE
+	}
+	return pb.ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_UNSPECIFIED
+}
+
+// exitBootServicesPosition returns the position in events of the first
+// verified ExitBootServices invocation, or -1 if there is none.
+func exitBootServicesPosition(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig) int {
+	exitBootSvcDigest := tcg.EFIActionDigest(hash, tcg.ExitBootServicesInvocation)
+	for i, event := range events {
+		if event.MRIndex() == registerCfg.ExitBootServicesIdx &&
+			event.UntrustedType() == tcg.EFIAction &&
+			subtle.ConstantTimeCompare(exitBootSvcDigest, event.ReplayedDigest()) == 1 &&
+			DigestEquals(event, event.RawData()) == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// PostExitBootServicesEvents returns the events measured into the EFI
+// application and ExitBootServices registers after the ExitBootServices
+// invocation.
+// These events are recorded by the OS or runtime services once firmware has
+// relinquished control, so they are untrusted from the firmware's point of
+// view. It returns nil if the event log has no ExitBootServices invocation.
+func PostExitBootServicesEvents(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig) []*pb.Event {
+	ebsPos := exitBootServicesPosition(hash, events, registerCfg)
+	if ebsPos == -1 {
+		return nil
+	}
+	var postEBS []tcg.Event
+	for _, event := range events[ebsPos+1:] {
+		index := event.MRIndex()
+		if index == registerCfg.EFIAppIdx || index == registerCfg.ExitBootServicesIdx {
+			postEBS = append(postEBS, event)
+		}
+	}
+	return tcg.ConvertToPbEvents(hash, postEBS)
+}
+
 // LinuxKernelStateFromGRUB extracts the kernel command line from GrubState.
 func LinuxKernelStateFromGRUB(grub *pb.GrubState) (*pb.LinuxKernelState, error) {
 	var cmdline string
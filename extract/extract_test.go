@@ -17,17 +17,30 @@ package extract
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"math/big"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf16"
 
 	"github.com/google/go-eventlog/internal/testutil"
 	"github.com/google/go-eventlog/register"
 	"github.com/google/go-eventlog/tcg"
 	"github.com/google/go-eventlog/testdata"
+	"github.com/google/go-eventlog/wellknown"
 	"google.golang.org/protobuf/proto"
 
 	pb "github.com/google/go-eventlog/proto/state"
@@ -336,6 +349,79 @@ func TestExtractFirmwareLogStateTPMNilEvents(t *testing.T) {
 	}
 }
 
+func TestGRUBMeasurementsNotFoundError(t *testing.T) {
+	hash, tpmEvents := getTPMELEvents(t)
+	var nonGrubEvents []tcg.Event
+	for _, event := range tpmEvents {
+		if event.MRIndex() == 8 || event.MRIndex() == 9 {
+			continue
+		}
+		nonGrubEvents = append(nonGrubEvents, event)
+	}
+	tagEvent := tcg.Event{Index: 8, Type: tcg.EventTag, Data: []byte("tag"), Digest: []byte{}}
+	nonGrubEvents = append(nonGrubEvents, tagEvent)
+
+	_, err := GrubStateFromTPMLog(hash, nonGrubEvents)
+	var notFound GRUBMeasurementsNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("GrubStateFromTPMLog(): got %v, want GRUBMeasurementsNotFoundError", err)
+	}
+	if !reflect.DeepEqual(notFound.SearchedMRs, []uint32{8, 9}) {
+		t.Errorf("GrubStateFromTPMLog(): got SearchedMRs %v, want [8 9]", notFound.SearchedMRs)
+	}
+	if notFound.EventTypeCounts[tcg.EventTag] != 1 || len(notFound.EventTypeCounts) != 1 {
+		t.Errorf("GrubStateFromTPMLog(): got EventTypeCounts %v, want one EV_EVENT_TAG", notFound.EventTypeCounts)
+	}
+
+	// The error must also be reachable through FirmwareLogState's joined error.
+	_, err = FirmwareLogState(nil, crypto.SHA384, RTMRRegisterConfig, Opts{Loader: GRUB})
+	if !errors.As(err, &notFound) {
+		t.Fatalf("FirmwareLogState(nil): got %v, want GRUBMeasurementsNotFoundError", err)
+	}
+	if !reflect.DeepEqual(notFound.SearchedMRs, []uint32{3}) {
+		t.Errorf("FirmwareLogState(nil): got SearchedMRs %v, want [3]", notFound.SearchedMRs)
+	}
+}
+
+func TestDetectBootloader(t *testing.T) {
+	_, tpmEvents := getTPMELEvents(t)
+	var nonGrubEvents []tcg.Event
+	for _, event := range tpmEvents {
+		if event.MRIndex() != 8 {
+			nonGrubEvents = append(nonGrubEvents, event)
+		}
+	}
+	tests := []struct {
+		name        string
+		events      []tcg.Event
+		registerCfg RegisterConfig
+		want        Bootloader
+	}{
+		{"TPM GRUB", tpmEvents, TPMRegisterConfig, GRUB},
+		{"CCEL GRUB", getCCELEvents(t), RTMRRegisterConfig, GRUB},
+		{"no loader events", nonGrubEvents, TPMRegisterConfig, UnsupportedLoader},
+		{"nil events", nil, TPMRegisterConfig, UnsupportedLoader},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DetectBootloader(tc.events, tc.registerCfg); got != tc.want {
+				t.Errorf("DetectBootloader() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFirmwareLogStateAutoDetectLoader(t *testing.T) {
+	hash, tpmEvents := getTPMELEvents(t)
+	fs, err := FirmwareLogState(tpmEvents, hash, TPMRegisterConfig, Opts{Loader: AutoDetect})
+	if err != nil {
+		t.Fatalf("FirmwareLogState(AutoDetect): %v", err)
+	}
+	if fs.GetGrub() == nil || fs.GetLinuxKernel().GetCommandLine() == "" {
+		t.Errorf("FirmwareLogState(AutoDetect): got no GRUB or kernel state, want GRUB detected")
+	}
+}
+
 func TestGrubStateFromTPMLogWithModifiedNullTerminator(t *testing.T) {
 	hash, tpmEvents := getTPMELEvents(t)
 
@@ -376,11 +462,109 @@ func TestGrubStateFromRTMRLogWithModifiedNullTerminator(t *testing.T) {
 	}
 }
 
+func TestEfiDriverState(t *testing.T) {
+	raw, err := os.ReadFile("../testdata/legacydata/option_rom_eventlog")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	el, err := tcg.ParseEventLog(raw, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	events := el.Events(register.HashSHA1)
+	var driver tcg.Event
+	for _, e := range events {
+		if e.Type == tcg.EFIBootServicesDriver {
+			driver = e
+		}
+	}
+	wantDriver := &pb.EfiDriver{
+		Digest:              driver.ReplayedDigest(),
+		EventNum:            driver.Num(),
+		UntrustedDevicePath: "PciRoot(0x0)/Pci(0x2,0x0)/Pci(0x0,0x0)/Offset(0xf600,0x207ff)",
+		UntrustedOptionRom:  true,
+	}
+
+	efiState, err := EfiDriverState(events, TPMRegisterConfig)
+	if err != nil {
+		t.Fatalf("EfiDriverState(): %v", err)
+	}
+	want := &pb.EfiState{
+		BootServicesDrivers: []*pb.EfiApp{{Digest: driver.ReplayedDigest()}},
+		Drivers:             []*pb.EfiDriver{wantDriver},
+	}
+	if !proto.Equal(efiState, want) {
+		t.Errorf("EfiDriverState() = %v, want %v", efiState, want)
+	}
+
+	// Load the option ROM after the PCR2 separator.
+	var postSeparator []tcg.Event
+	for _, e := range events {
+		if e.Type == tcg.EFIBootServicesDriver {
+			continue
+		}
+		postSeparator = append(postSeparator, e)
+		if e.Index == 2 && e.Type == tcg.Separator {
+			postSeparator = append(postSeparator, driver)
+		}
+	}
+	efiState, err = EfiDriverState(postSeparator, TPMRegisterConfig)
+	if err != nil {
+		t.Fatalf("EfiDriverState(post-separator driver): %v", err)
+	}
+	wantDriver.PostSeparator = true
+	want = &pb.EfiState{Drivers: []*pb.EfiDriver{wantDriver}}
+	if !proto.Equal(efiState, want) {
+		t.Errorf("EfiDriverState(post-separator driver) = %v, want %v", efiState, want)
+	}
+
+	// A malformed device path is recorded rather than failing the state: the
+	// first node claims a length shorter than its header.
+	var malformed []tcg.Event
+	for _, e := range events {
+		if e.Type == tcg.EFIBootServicesDriver {
+			e.Data = append([]byte(nil), e.Data...)
+			binary.LittleEndian.PutUint16(e.Data[34:], 1)
+		}
+		malformed = append(malformed, e)
+	}
+	efiState, err = EfiDriverState(malformed, TPMRegisterConfig)
+	if err != nil {
+		t.Fatalf("EfiDriverState(malformed device path): %v", err)
+	}
+	if got := efiState.GetDrivers(); len(got) != 1 || got[0].GetUntrustedDevicePathError() == "" || got[0].GetUntrustedDevicePath() != "" || got[0].GetUntrustedOptionRom() {
+		t.Errorf("EfiDriverState(malformed device path) drivers = %v, want one driver with a device path error", got)
+	}
+
+	// Truncated image load data fails the state before the separator, and is
+	// recorded on the driver after it.
+	truncate := func(events []tcg.Event) []tcg.Event {
+		var out []tcg.Event
+		for _, e := range events {
+			if e.Type == tcg.EFIBootServicesDriver {
+				e.Data = e.Data[:8]
+			}
+			out = append(out, e)
+		}
+		return out
+	}
+	if _, err := EfiDriverState(truncate(events), TPMRegisterConfig); err == nil {
+		t.Errorf("EfiDriverState(truncated pre-separator driver): got nil, want error")
+	}
+	efiState, err = EfiDriverState(truncate(postSeparator), TPMRegisterConfig)
+	if err != nil {
+		t.Fatalf("EfiDriverState(truncated post-separator driver): %v", err)
+	}
+	if got := efiState.GetDrivers(); len(got) != 1 || got[0].GetUntrustedImageLoadError() == "" || !got[0].GetPostSeparator() || got[0].GetUntrustedDevicePath() != "" {
+		t.Errorf("EfiDriverState(truncated post-separator driver) drivers = %v, want one driver with an image load error", got)
+	}
+}
+
 func TestEfiState(t *testing.T) {
 	tests := []struct {
 		name            string
 		events          func() (crypto.Hash, []tcg.Event)
-		registserConfig registerConfig
+		registserConfig RegisterConfig
 		wantPass        bool
 		wantEfiState    *pb.EfiState
 	}{
@@ -394,12 +578,22 @@ func TestEfiState(t *testing.T) {
 			wantEfiState: &pb.EfiState{
 				Apps: []*pb.EfiApp{
 					{
-						Digest: []byte("rM\xe6\x84M\xd0\xfea\x8b\xa5wl{\xca\x07(\xbe8\xa6TN$\xe4N\xf2Y\xb9\x87\xb7\xab΀"),
+						Digest:                 []byte("rM\xe6\x84M\xd0\xfea\x8b\xa5wl{\xca\x07(\xbe8\xa6TN$\xe4N\xf2Y\xb9\x87\xb7\xab΀"),
+						UntrustedDevicePath:    `PciRoot(0x0)/Pci(0x4,0x0)/NVMe(0x1,00-00-00-00-00-00-00-00)/HD(15,GPT,fc24e8b6-5e8c-4396-aadd-2c6a9a43b92d,0x2800,0x35000)/\EFI\ubuntu\shimx64.efi`,
+						UntrustedFilePath:      `\EFI\ubuntu\shimx64.efi`,
+						UntrustedPartitionGuid: "fc24e8b6-5e8c-4396-aadd-2c6a9a43b92d",
+						UntrustedImageLocation: 3166142488,
+						UntrustedImageLength:   966664,
 					},
 					{
-						Digest: []byte("^\x8c\xb7Z\xcd\xf8\xe0\x9e_\xc1L\xc2\xd6\xce\x0c\"\x88\xaf \x89v\xd9s\t\x85\x1cf\x1e\x91\xec\x1e\x03"),
+						Digest:                 []byte("^\x8c\xb7Z\xcd\xf8\xe0\x9e_\xc1L\xc2\xd6\xce\x0c\"\x88\xaf \x89v\xd9s\t\x85\x1cf\x1e\x91\xec\x1e\x03"),
+						UntrustedDevicePath:    `\EFI\ubuntu\grubx64.efi`,
+						UntrustedFilePath:      `\EFI\ubuntu\grubx64.efi`,
+						UntrustedImageLocation: 3162603544,
+						UntrustedImageLength:   2656136,
 					},
 				},
+				UntrustedExitBootServicesResult: pb.ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_SUCCESS,
 			},
 		},
 		{
@@ -412,12 +606,57 @@ func TestEfiState(t *testing.T) {
 			wantEfiState: &pb.EfiState{
 				Apps: []*pb.EfiApp{
 					{
-						Digest: []byte("Z\x10\x02l\x9a\xd4\x1d\x1f\x90ܜ\xfe\x88\xbc\xab\xe1\x84,\xcf\xd8T\x95\xc8\x1b\x1a\x1a\xb9&\xa9\xef#\xb5\xd2\xe6\x0e\xef\xeb\xa0A[\xbe\\\x8c2\x8a\x89\x9a\n"),
+						Digest:                 []byte("Z\x10\x02l\x9a\xd4\x1d\x1f\x90ܜ\xfe\x88\xbc\xab\xe1\x84,\xcf\xd8T\x95\xc8\x1b\x1a\x1a\xb9&\xa9\xef#\xb5\xd2\xe6\x0e\xef\xeb\xa0A[\xbe\\\x8c2\x8a\x89\x9a\n"),
+						UntrustedDevicePath:    `PciRoot(0x0)/Pci(0x4,0x0)/NVMe(0x1,00-00-00-00-00-00-00-00)/HD(12,GPT,58ec8b8f-8e5b-ed42-83a4-0691abb8f3c7,0x3d000,0x10000)/\EFI\BOOT\BOOTX64.EFI`,
+						UntrustedFilePath:      `\EFI\BOOT\BOOTX64.EFI`,
+						UntrustedPartitionGuid: "58ec8b8f-8e5b-ed42-83a4-0691abb8f3c7",
+						UntrustedImageLocation: 3166027800,
+						UntrustedImageLength:   924688,
+					},
+					{
+						Digest:                 []byte("\xb1\xfb\x7fL\x06\x89\xf5\xa9 \xb8\x00\xb2`pu\xf4\x90o\x8c\x82\x82\xd4NV\xfc\x99\x1e\xc0\x1f\x1a\xda\xc1v\xd2\x04\n&\xf1E=\xf1\x12\xd7\xc4\xf4)?\xc9"),
+						UntrustedDevicePath:    `\EFI\BOOT\grub-lakitu.efi`,
+						UntrustedFilePath:      `\EFI\BOOT\grub-lakitu.efi`,
+						UntrustedImageLocation: 3166203928,
+						UntrustedImageLength:   743168,
+					},
+				},
+				UntrustedExitBootServicesResult: pb.ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_SUCCESS,
+			},
+		},
+		{
+			name: "untrusted ExitBootServices failure in TPM logs",
+			events: func() (crypto.Hash, []tcg.Event) {
+				hash, evts := getTPMELEvents(t)
+				for i, e := range evts {
+					if bytes.Equal(e.RawData(), []byte(tcg.ExitBootServicesSuccess)) {
+						evts[i].Data = []byte(tcg.ExitBootServicesFailure)
+						evts[i].Digest = tcg.EFIActionDigest(hash, tcg.ExitBootServicesFailure)
+					}
+				}
+				return hash, evts
+			},
+			registserConfig: TPMRegisterConfig,
+			wantPass:        true,
+			wantEfiState: &pb.EfiState{
+				Apps: []*pb.EfiApp{
+					{
+						Digest:                 []byte("rM\xe6\x84M\xd0\xfea\x8b\xa5wl{\xca\x07(\xbe8\xa6TN$\xe4N\xf2Y\xb9\x87\xb7\xab΀"),
+						UntrustedDevicePath:    `PciRoot(0x0)/Pci(0x4,0x0)/NVMe(0x1,00-00-00-00-00-00-00-00)/HD(15,GPT,fc24e8b6-5e8c-4396-aadd-2c6a9a43b92d,0x2800,0x35000)/\EFI\ubuntu\shimx64.efi`,
+						UntrustedFilePath:      `\EFI\ubuntu\shimx64.efi`,
+						UntrustedPartitionGuid: "fc24e8b6-5e8c-4396-aadd-2c6a9a43b92d",
+						UntrustedImageLocation: 3166142488,
+						UntrustedImageLength:   966664,
 					},
 					{
-						Digest: []byte("\xb1\xfb\x7fL\x06\x89\xf5\xa9 \xb8\x00\xb2`pu\xf4\x90o\x8c\x82\x82\xd4NV\xfc\x99\x1e\xc0\x1f\x1a\xda\xc1v\xd2\x04\n&\xf1E=\xf1\x12\xd7\xc4\xf4)?\xc9"),
+						Digest:                 []byte("^\x8c\xb7Z\xcd\xf8\xe0\x9e_\xc1L\xc2\xd6\xce\x0c\"\x88\xaf \x89v\xd9s\t\x85\x1cf\x1e\x91\xec\x1e\x03"),
+						UntrustedDevicePath:    `\EFI\ubuntu\grubx64.efi`,
+						UntrustedFilePath:      `\EFI\ubuntu\grubx64.efi`,
+						UntrustedImageLocation: 3162603544,
+						UntrustedImageLength:   2656136,
 					},
 				},
+				UntrustedExitBootServicesResult: pb.ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_FAILURE,
 			},
 		},
 		{
@@ -533,3 +772,1652 @@ func decodeHex(hexStr string) []byte {
 	}
 	return bytes
 }
+
+func TestFirmwareLogStateKexec(t *testing.T) {
+	hash, evts := getTPMELEvents(t)
+	fs, err := FirmwareLogState(evts, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatalf("FirmwareLogState() failed: %v", err)
+	}
+	if len(fs.GetKexec()) != 0 {
+		t.Errorf("FirmwareLogState() = got %d kexec generations, want 0", len(fs.GetKexec()))
+	}
+	wantCmdline := fs.GetLinuxKernel().GetCommandLine()
+
+	kexecCmdline := "/vmlinuz-kexec root=/dev/sda1 ro"
+	evts = append(evts,
+		makeGrubEvent(hash, 8, "grub_cmd: ", "linux /vmlinuz-kexec"),
+		makeGrubEvent(hash, 9, "", "/vmlinuz-kexec"),
+		makeGrubEvent(hash, 8, "kernel_cmdline: ", kexecCmdline),
+	)
+	fs, err = FirmwareLogState(evts, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatalf("FirmwareLogState() with kexec events failed: %v", err)
+	}
+	if got := fs.GetLinuxKernel().GetCommandLine(); got != wantCmdline {
+		t.Errorf("FirmwareLogState() = got first kernel command line %q, want %q", got, wantCmdline)
+	}
+	if len(fs.GetKexec()) != 1 {
+		t.Fatalf("FirmwareLogState() = got %d kexec generations, want 1", len(fs.GetKexec()))
+	}
+	kexec := fs.GetKexec()[0]
+	if got := kexec.GetLinuxKernel().GetCommandLine(); got != kexecCmdline {
+		t.Errorf("FirmwareLogState() = got kexec command line %q, want %q", got, kexecCmdline)
+	}
+	if len(kexec.GetGrub().GetFiles()) != 1 || len(kexec.GetGrub().GetCommands()) != 2 {
+		t.Errorf("FirmwareLogState() = got kexec GRUB state %v, want 1 file and 2 commands", kexec.GetGrub())
+	}
+}
+
+func makeGrubEvent(hash crypto.Hash, index int, prefix string, data string) tcg.Event {
+	hasher := hash.New()
+	hasher.Write([]byte(data))
+	return tcg.Event{
+		Index:  index,
+		Type:   tcg.Ipl,
+		Data:   []byte(prefix + data),
+		Digest: hasher.Sum(nil),
+	}
+}
+
+func TestFirmwareLogStateCollectPostEBSEvents(t *testing.T) {
+	hash, evts := getTPMELEvents(t)
+	fs, err := FirmwareLogState(evts, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatalf("FirmwareLogState() failed: %v", err)
+	}
+	if got := fs.GetEfi().GetUntrustedPostEbsEvents(); len(got) != 0 {
+		t.Errorf("FirmwareLogState() = got %d post-EBS events without CollectPostEBSEvents, want 0", len(got))
+	}
+
+	fs, err = FirmwareLogState(evts, hash, TPMRegisterConfig, Opts{Loader: GRUB, CollectPostEBSEvents: true})
+	if err != nil {
+		t.Fatalf("FirmwareLogState() failed: %v", err)
+	}
+	postEBS := fs.GetEfi().GetUntrustedPostEbsEvents()
+	if len(postEBS) == 0 {
+		t.Fatal("FirmwareLogState() = got no post-EBS events, want at least one")
+	}
+	for _, e := range postEBS {
+		if e.GetPcrIndex() != TPMRegisterConfig.EFIAppIdx && e.GetPcrIndex() != TPMRegisterConfig.ExitBootServicesIdx {
+			t.Errorf("FirmwareLogState() = got post-EBS event in PCR%d, want PCR4 or PCR5", e.GetPcrIndex())
+		}
+	}
+	if got := string(postEBS[0].GetData()); got != "Exit Boot Services Returned with Success" {
+		t.Errorf("FirmwareLogState() = got first post-EBS event data %q", got)
+	}
+}
+
+func TestNewCustomRegisterConfig(t *testing.T) {
+	hash, tpmEvents := getTPMELEvents(t)
+	cfg := NewCustomRegisterConfig("custom PCR", NewTPMRegisterConfig(), TPMRegisterConfig.Layout())
+	if cfg.Layout() != TPMRegisterConfig.Layout() {
+		t.Errorf("NewCustomRegisterConfig(): got layout %+v, want %+v", cfg.Layout(), TPMRegisterConfig.Layout())
+	}
+	want, err := FirmwareLogState(tpmEvents, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := FirmwareLogState(tpmEvents, hash, cfg, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("FirmwareLogState(custom config) differs from FirmwareLogState(TPMRegisterConfig)")
+	}
+
+	rtmrCfg := NewRTMRRegisterConfig()
+	rtmrCfg.AdditionalSecureBootIdxEvents[tcg.Ipl] = true
+	if RTMRRegisterConfig.AdditionalSecureBootIdxEvents[tcg.Ipl] {
+		t.Errorf("NewRTMRRegisterConfig() shares AdditionalSecureBootIdxEvents with RTMRRegisterConfig")
+	}
+	tpmCfg := NewTPMRegisterConfig()
+	tpmCfg.Drtm.Indexes[0] = 0
+	if TPMRegisterConfig.Drtm.Indexes[0] != 17 {
+		t.Errorf("NewTPMRegisterConfig() shares Drtm with TPMRegisterConfig")
+	}
+}
+
+func TestNormalizeGRUBCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"grub_cmd: set root='hd0,gpt1'", "grub_cmd: set root='hd0,gpt1'"},
+		{"grub_cmd:  linux   /vmlinuz  root=/dev/sda1 \tro\x00", "grub_cmd: linux /vmlinuz root=/dev/sda1 ro"},
+		{"kernel_cmdline: /vmlinuz ro  quiet ", "kernel_cmdline: /vmlinuz ro quiet"},
+		{`grub_cmd: search --no-floppy "" --set=root ''`, "grub_cmd: search --no-floppy --set=root"},
+		{`grub_cmd: echo "keep  inner   spacing"`, `grub_cmd: echo "keep  inner   spacing"`},
+		{`grub_cmd: echo a\ \ b`, `grub_cmd: echo a\ \ b`},
+		{"no prefix   here", "no prefix here"},
+	}
+	for _, tc := range tests {
+		if got := NormalizeGRUBCommand(tc.command); got != tc.want {
+			t.Errorf("NormalizeGRUBCommand(%q) = %q, want %q", tc.command, got, tc.want)
+		}
+	}
+
+	hash, events := getTPMELEvents(t)
+	grub, err := GrubStateFromTPMLog(hash, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grub.GetNormalizedCommands()) != len(grub.GetCommands()) {
+		t.Errorf("GrubStateFromTPMLog(): got %d normalized commands for %d commands", len(grub.GetNormalizedCommands()), len(grub.GetCommands()))
+	}
+	if len(grub.GetCommandDigests()) != len(grub.GetCommands()) {
+		t.Errorf("GrubStateFromTPMLog(): got %d command digests for %d commands", len(grub.GetCommandDigests()), len(grub.GetCommands()))
+	}
+	for _, file := range grub.GetFiles() {
+		if file.GetHash() != pb.HashAlgo_SHA256 || len(file.GetBankDigests()) != 1 {
+			t.Errorf("GrubStateFromTPMLog(): got file hash %v with %d bank digests, want SHA256 with 1", file.GetHash(), len(file.GetBankDigests()))
+		}
+	}
+}
+
+func TestGrubBankDigestsCompatLevel(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	state, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: 14})
+	if err != nil {
+		t.Fatal(err)
+	}
+	grub := state.GetGrub()
+	if len(grub.GetCommands()) == 0 {
+		t.Fatal("FirmwareLogState(): got no GRUB commands")
+	}
+	if len(grub.GetCommandDigests()) != 0 {
+		t.Errorf("FirmwareLogState(CompatLevel: 14): got %d command digests, want none", len(grub.GetCommandDigests()))
+	}
+	for _, file := range grub.GetFiles() {
+		if file.GetHash() != pb.HashAlgo_HASH_INVALID || len(file.GetBankDigests()) != 0 {
+			t.Errorf("FirmwareLogState(CompatLevel: 14): got file hash %v with %d bank digests, want none", file.GetHash(), len(file.GetBankDigests()))
+		}
+	}
+}
+
+func TestRepeatedEventRuns(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	state, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, finding := range state.GetFindings() {
+		if finding.GetType() == pb.FindingType_FINDING_TYPE_REPEATED_EVENTS {
+			t.Errorf("FirmwareLogState(): got unexpected finding %v", finding)
+		}
+	}
+
+	first := makeGrubEvent(hash, 14, "grub_cmd: ", "insmod tpm")
+	second := makeGrubEvent(hash, 14, "grub_cmd: ", "insmod gzio")
+	other := makeGrubEvent(hash, 13, "grub_cmd: ", "unrelated")
+	stuffed := []tcg.Event{first, second, other, first, second, first, second, first}
+	runs := RepeatedEventRuns(stuffed, DefaultMinRepeatedRunLength)
+	want := []RepeatedEventRun{{MRIndex: 14, Length: 2, Copies: 3}}
+	if !reflect.DeepEqual(runs, want) {
+		t.Errorf("RepeatedEventRuns() = %v, want %v", runs, want)
+	}
+	for _, minRunLength := range []int{0, 1, DefaultMinRepeatedRunLength} {
+		if runs := RepeatedEventRuns([]tcg.Event{first, first, first}, minRunLength); len(runs) != 0 {
+			t.Errorf("RepeatedEventRuns(single repeated event, %d) = %v, want none", minRunLength, runs)
+		}
+	}
+	// The first event recurs inside the run, so the run is not the distance
+	// to its next occurrence.
+	third := makeGrubEvent(hash, 14, "grub_cmd: ", "insmod part_gpt")
+	recurring := []tcg.Event{first, second, first, third, first, second, first, third}
+	want = []RepeatedEventRun{{MRIndex: 14, Length: 4, Copies: 2}}
+	if runs := RepeatedEventRuns(recurring, DefaultMinRepeatedRunLength); !reflect.DeepEqual(runs, want) {
+		t.Errorf("RepeatedEventRuns(recurring event) = %v, want %v", runs, want)
+	}
+	if findings := repeatedEventsFindings(stuffed); len(findings) != 1 {
+		t.Errorf("repeatedEventsFindings() = %v, want one finding", findings)
+	}
+}
+
+func TestEventLogStats(t *testing.T) {
+	hash := crypto.SHA256
+	events := []tcg.Event{
+		makeGrubEvent(hash, 8, "grub_cmd: ", "aaaa"),
+		makeGrubEvent(hash, 8, "grub_cmd: ", "bbbb"),
+		makeGrubEvent(hash, 9, "", "/boot/vmlinuz"),
+		{Index: 4, Type: tcg.EventType(0xdeadbeef), Data: []byte{0x00}},
+	}
+	stats := EventLogStats(events)
+	if stats.GetTotalEvents() != 4 || stats.GetUnknownTypeEvents() != 1 {
+		t.Errorf("EventLogStats(): got %d events and %d unknown, want 4 and 1", stats.GetTotalEvents(), stats.GetUnknownTypeEvents())
+	}
+	wantSize := uint64(0)
+	for _, event := range events {
+		wantSize += uint64(len(event.Data))
+	}
+	if stats.GetTotalDataSize() != wantSize {
+		t.Errorf("EventLogStats(): got data size %d, want %d", stats.GetTotalDataSize(), wantSize)
+	}
+	want := []*pb.EventCount{
+		{PcrIndex: 4, UntrustedType: 0xdeadbeef, Count: 1},
+		{PcrIndex: 8, UntrustedType: uint32(tcg.Ipl), Count: 2},
+		{PcrIndex: 9, UntrustedType: uint32(tcg.Ipl), Count: 1},
+	}
+	if len(stats.GetCounts()) != len(want) {
+		t.Fatalf("EventLogStats(): got counts %v, want %v", stats.GetCounts(), want)
+	}
+	for i := range want {
+		if !proto.Equal(stats.GetCounts()[i], want[i]) {
+			t.Errorf("EventLogStats(): got count %v, want %v", stats.GetCounts()[i], want[i])
+		}
+	}
+	if entropy := stats.GetDataEntropy(); entropy <= 0 || entropy > 8 {
+		t.Errorf("EventLogStats(): got entropy %v, want within (0, 8]", entropy)
+	}
+	if entropy := EventLogStats([]tcg.Event{{Data: []byte("aaaa")}}).GetDataEntropy(); entropy != 0 {
+		t.Errorf("EventLogStats(constant data): got entropy %v, want 0", entropy)
+	}
+}
+
+func TestDrtmState(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	drtm, err := DrtmState(hash, events, TPMDrtmRegisterConfig)
+	if err != nil || drtm != nil {
+		t.Errorf("DrtmState(SRTM-only log) = %v, %v, want nil, nil", drtm, err)
+	}
+
+	makeEvent := func(index int, typ tcg.EventType, data string) tcg.Event {
+		hasher := hash.New()
+		hasher.Write([]byte(data))
+		return tcg.Event{Index: index, Type: typ, Data: []byte(data), Digest: hasher.Sum(nil)}
+	}
+	sinit := makeEvent(17, tcg.TXTHashStart, "sinit")
+	pubkey := makeEvent(17, tcg.TXTSINITPubKeyHash, "pubkey")
+	mle := makeEvent(18, tcg.TXTMLEHash, "tboot")
+	tag := makeEvent(19, tcg.EventTag, "kernel")
+	drtmEvents := append(append([]tcg.Event{}, events...), sinit, pubkey, makeEvent(17, tcg.TXTCapValue, "cap"), mle, tag)
+
+	drtm, err = DrtmState(hash, drtmEvents, TPMDrtmRegisterConfig)
+	if err != nil {
+		t.Fatalf("DrtmState() failed: %v", err)
+	}
+	if !bytes.Equal(drtm.GetSinitDigest(), sinit.Digest) ||
+		!bytes.Equal(drtm.GetSinitPubkeyDigest(), pubkey.Digest) ||
+		!bytes.Equal(drtm.GetMleDigest(), mle.Digest) {
+		t.Errorf("DrtmState() = got %v, want SINIT, SINIT public key, and MLE digests", drtm)
+	}
+	if len(drtm.GetEvents()) != 5 || len(drtm.GetMleEvents()) != 1 {
+		t.Errorf("DrtmState() = got %d events and %d MLE events, want 5 and 1", len(drtm.GetEvents()), len(drtm.GetMleEvents()))
+	}
+	if drtm.GetTechnology() != pb.DrtmTechnology_DRTM_TECHNOLOGY_INTEL_TXT {
+		t.Errorf("DrtmState() = got technology %v, want %v", drtm.GetTechnology(), pb.DrtmTechnology_DRTM_TECHNOLOGY_INTEL_TXT)
+	}
+
+	// A standalone Linux Secure Launch log after an AMD SKINIT launch.
+	slaunch := []tcg.Event{
+		makeEvent(17, tcg.SLaunchStart, ""),
+		makeEvent(18, tcg.SLaunch, "Measured boot parameters"),
+		makeEvent(18, tcg.SLaunch, "Measured Kernel command line"),
+		makeEvent(17, tcg.SLaunch, "Measured initramfs"),
+		makeEvent(17, tcg.SLaunchEnd, ""),
+	}
+	drtm, err = DrtmState(hash, slaunch, TPMDrtmRegisterConfig)
+	if err != nil {
+		t.Fatalf("DrtmState(Secure Launch) failed: %v", err)
+	}
+	if drtm.GetTechnology() != pb.DrtmTechnology_DRTM_TECHNOLOGY_AMD_SKINIT || len(drtm.GetSecureLaunchEvents()) != 3 {
+		t.Errorf("DrtmState(Secure Launch) = got technology %v and %d Secure Launch events, want %v and 3", drtm.GetTechnology(), len(drtm.GetSecureLaunchEvents()), pb.DrtmTechnology_DRTM_TECHNOLOGY_AMD_SKINIT)
+	}
+	if _, err := DrtmState(hash, []tcg.Event{makeEvent(19, tcg.SLaunch, "Measured initramfs")}, TPMDrtmRegisterConfig); err == nil {
+		t.Errorf("DrtmState(Secure Launch event in PCR19) = nil, want error")
+	}
+
+	if _, err := DrtmState(hash, []tcg.Event{sinit, sinit}, TPMDrtmRegisterConfig); err == nil {
+		t.Errorf("DrtmState(duplicate SINIT events) = nil, want error")
+	}
+}
+
+func TestCompatLevel(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	for _, tc := range []struct {
+		compatLevel uint32
+		want        uint32
+	}{
+		{0, SchemaVersion},
+		{1, 1},
+		{SchemaVersion, SchemaVersion},
+	} {
+		fs, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: tc.compatLevel})
+		if err != nil {
+			t.Fatalf("FirmwareLogState(CompatLevel %d): %v", tc.compatLevel, err)
+		}
+		if fs.GetSchemaVersion() != tc.want {
+			t.Errorf("FirmwareLogState(CompatLevel %d) = got schema version %d, want %d", tc.compatLevel, fs.GetSchemaVersion(), tc.want)
+		}
+	}
+	if _, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: SchemaVersion + 1}); err == nil {
+		t.Errorf("FirmwareLogState(future CompatLevel): got nil, want error")
+	}
+}
+
+func TestSizeAnomalyFindings(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	sizeAnomalies := func(state *pb.FirmwareLogState) int {
+		var count int
+		for _, finding := range state.GetFindings() {
+			if finding.GetType() == pb.FindingType_FINDING_TYPE_EVENT_SIZE_ANOMALY {
+				count++
+			}
+		}
+		return count
+	}
+	state, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sizeAnomalies(state); got != 0 {
+		t.Errorf("FirmwareLogState(): got %d size anomaly findings, want 0", got)
+	}
+
+	truncatedBlob := tcg.Event{Index: 14, Type: tcg.EFIPlatformFirmwareBlob, Data: []byte{1}, Digest: make([]byte, hash.Size())}
+	malformed := append(append([]tcg.Event(nil), events...), truncatedBlob)
+	for _, tc := range []struct {
+		compatLevel uint32
+		want        int
+	}{
+		{0, 1},
+		{3, 0},
+	} {
+		state, err := FirmwareLogState(malformed, hash, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: tc.compatLevel})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sizeAnomalies(state); got != tc.want {
+			t.Errorf("FirmwareLogState(CompatLevel %d): got %d size anomaly findings, want %d", tc.compatLevel, got, tc.want)
+		}
+	}
+}
+
+func TestDbxFindings(t *testing.T) {
+	if findings := dbxFindings(&pb.SecureBootState{}); findings != nil {
+		t.Errorf("dbxFindings(no errors) = %v, want nil", findings)
+	}
+	sbState := &pb.SecureBootState{DbxErrors: []*pb.SignatureListError{{Offset: 28, Error: "x509: malformed certificate"}}}
+	findings := dbxFindings(sbState)
+	if len(findings) != 1 || findings[0].GetType() != pb.FindingType_FINDING_TYPE_DBX_PARSE_ERRORS {
+		t.Errorf("dbxFindings() = %v, want a dbx parse errors finding", findings)
+	}
+}
+
+func TestCertMetadata(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	state, err := SecureBootState(events, TPMRegisterConfig, Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cert := range state.GetDb().GetCerts() {
+		if cert.GetMetadata() != nil {
+			t.Errorf("SecureBootState() = got certificate metadata without CertMetadata")
+		}
+	}
+
+	fs, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, CertMetadata: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs := fs.GetSecureBoot().GetDb().GetCerts()
+	if len(certs) == 0 {
+		t.Fatal("FirmwareLogState() = got no db certificates")
+	}
+	for _, cert := range certs {
+		metadata := cert.GetMetadata()
+		if metadata.GetSubject() == "" || metadata.GetIssuer() == "" || metadata.GetNotBefore() >= metadata.GetNotAfter() {
+			t.Errorf("FirmwareLogState() = got certificate metadata %v, want subject, issuer, and validity period", metadata)
+		}
+		if der := cert.GetDer(); der != nil {
+			fingerprint := sha256.Sum256(der)
+			if !bytes.Equal(metadata.GetSha256Fingerprint(), fingerprint[:]) {
+				t.Errorf("FirmwareLogState() = got fingerprint %x, want %x", metadata.GetSha256Fingerprint(), fingerprint)
+			}
+		}
+	}
+}
+
+func TestTrustAnchors(t *testing.T) {
+	_, events := getTPMELEvents(t)
+	msCA, err := x509.ParseCertificate(wellknown.MicrosoftUEFICA2011Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := SecureBootState(events, TPMRegisterConfig, Opts{TrustAnchors: []TrustAnchor{{Name: "uefi-ca", Cert: msCA}}})
+	if err != nil {
+		t.Fatalf("SecureBootState(): %v", err)
+	}
+	var trusted int
+	for _, cert := range append(state.GetDb().GetCerts(), state.GetAuthority().GetCerts()...) {
+		wantTrust, wantAnchor := pb.TrustStatus_TRUST_STATUS_UNTRUSTED, ""
+		if cert.GetWellKnown() == pb.WellKnownCertificate_MS_THIRD_PARTY_UEFI_CA_2011 {
+			wantTrust, wantAnchor = pb.TrustStatus_TRUST_STATUS_TRUSTED, "uefi-ca"
+			trusted++
+		}
+		if cert.GetTrust() != wantTrust || cert.GetTrustAnchor() != wantAnchor {
+			t.Errorf("SecureBootState() = got certificate %v trust %v from %q, want %v from %q", cert.GetWellKnown(), cert.GetTrust(), cert.GetTrustAnchor(), wantTrust, wantAnchor)
+		}
+	}
+	if trusted == 0 {
+		t.Errorf("SecureBootState() = got no trusted db certificates")
+	}
+	for _, cert := range append(state.GetPk().GetCerts(), state.GetKek().GetCerts()...) {
+		if cert.GetTrust() != pb.TrustStatus_TRUST_STATUS_UNSPECIFIED {
+			t.Errorf("SecureBootState() = got PK or KEK certificate trust %v, want unspecified", cert.GetTrust())
+		}
+	}
+
+	if _, err := SecureBootState(events, TPMRegisterConfig, Opts{TrustAnchors: []TrustAnchor{{Name: "no cert"}}}); err == nil {
+		t.Errorf("SecureBootState(trust anchor without certificate): got nil, want error")
+	}
+}
+
+func TestTrustStatus(t *testing.T) {
+	newCert := func(name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Unix(1700000000, 0),
+			NotAfter:              time.Unix(1800000000, 0),
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		}
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert, key
+	}
+	ca, caKey := newCert("Enterprise UEFI CA", nil, nil)
+	signer, _ := newCert("Enterprise Boot Signer", ca, caKey)
+	other, _ := newCert("Other CA", nil, nil)
+	// Issued by a CA with the same subject as the anchor, but another key.
+	fakeCA, fakeKey := newCert("Enterprise UEFI CA", nil, nil)
+	impostor, _ := newCert("Enterprise Boot Signer", fakeCA, fakeKey)
+	anchors := []TrustAnchor{{Name: "enterprise", Cert: ca}}
+	tbsDigest := sha256.Sum256(signer.RawTBSCertificate)
+	revokedByHash := newRevocations(nil, []tcg.SignatureEntry{{
+		Type: "X509_SHA256",
+		// The digest is followed by the EFI_TIME of the revocation.
+		Data: append(tbsDigest[:], make([]byte, 16)...),
+	}})
+
+	for _, tc := range []struct {
+		name       string
+		cert       *x509.Certificate
+		dbx        revocations
+		wantTrust  pb.TrustStatus
+		wantAnchor string
+	}{
+		{"anchor", ca, revocations{}, pb.TrustStatus_TRUST_STATUS_TRUSTED, "enterprise"},
+		{"issued by anchor", signer, revocations{}, pb.TrustStatus_TRUST_STATUS_TRUSTED, "enterprise"},
+		{"unrelated", other, revocations{}, pb.TrustStatus_TRUST_STATUS_UNTRUSTED, ""},
+		{"impostor", impostor, revocations{}, pb.TrustStatus_TRUST_STATUS_UNTRUSTED, ""},
+		{"revoked anchor", ca, newRevocations([]x509.Certificate{*ca}, nil), pb.TrustStatus_TRUST_STATUS_REVOKED, ""},
+		{"issued by revoked anchor", signer, newRevocations([]x509.Certificate{*ca}, nil), pb.TrustStatus_TRUST_STATUS_REVOKED, ""},
+		{"revoked by TBS digest", signer, revokedByHash, pb.TrustStatus_TRUST_STATUS_REVOKED, ""},
+		{"other TBS digest revoked", ca, revokedByHash, pb.TrustStatus_TRUST_STATUS_TRUSTED, "enterprise"},
+		{"unrelated revoked", other, newRevocations([]x509.Certificate{*other}, nil), pb.TrustStatus_TRUST_STATUS_REVOKED, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			trust, anchor := trustStatus(*tc.cert, anchors, tc.dbx)
+			if trust != tc.wantTrust || anchor != tc.wantAnchor {
+				t.Errorf("trustStatus() = %v, %q, want %v, %q", trust, anchor, tc.wantTrust, tc.wantAnchor)
+			}
+		})
+	}
+}
+
+func TestSignatureEntries(t *testing.T) {
+	_, events := getTPMELEvents(t)
+	state, err := SecureBootState(events, TPMRegisterConfig, Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		gceOwner       = "d281fad2-8d88-47a4-9792-5baa47bb1b89"
+		microsoftOwner = "77fa9abd-0359-4d32-bd60-28f4e78f784b"
+	)
+	for _, tc := range []struct {
+		name  string
+		db    *pb.Database
+		owner string
+	}{
+		{"PK", state.GetPk(), gceOwner},
+		{"KEK", state.GetKek(), gceOwner},
+		{"db", state.GetDb(), gceOwner},
+		{"dbx", state.GetDbx(), microsoftOwner},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			entries := tc.db.GetEntries()
+			if want := len(tc.db.GetCerts()) + len(tc.db.GetHashes()); len(entries) != want {
+				t.Fatalf("got %d entries, want %d", len(entries), want)
+			}
+			var certs, hashes uint32
+			for i, entry := range entries {
+				if entry.GetOwner() != tc.owner {
+					t.Errorf("entry %d: got owner %s, want %s", i, entry.GetOwner(), tc.owner)
+				}
+				switch entry.GetValue().(type) {
+				case *pb.SignatureEntry_CertIndex:
+					if entry.GetType() != pb.SignatureType_SIGNATURE_TYPE_X509 || entry.GetCertIndex() != certs {
+						t.Errorf("entry %d: got %v certificate %d, want X509 certificate %d", i, entry.GetType(), entry.GetCertIndex(), certs)
+					}
+					certs++
+				case *pb.SignatureEntry_HashIndex:
+					if entry.GetType() != pb.SignatureType_SIGNATURE_TYPE_SHA256 || entry.GetHashIndex() != hashes {
+						t.Errorf("entry %d: got %v hash %d, want SHA256 hash %d", i, entry.GetType(), entry.GetHashIndex(), hashes)
+					}
+					hashes++
+				default:
+					t.Errorf("entry %d: got data %x, want a certificate or hash index", i, entry.GetData())
+				}
+			}
+		})
+	}
+
+	old, err := SecureBootState(events, TPMRegisterConfig, Opts{CompatLevel: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, db := range []*pb.Database{old.GetPk(), old.GetKek(), old.GetDb(), old.GetDbx()} {
+		if len(db.GetEntries()) != 0 {
+			t.Errorf("SecureBootState(CompatLevel: 9): got %d entries, want none", len(db.GetEntries()))
+		}
+	}
+}
+
+func TestConvertToPbSignatureEntries(t *testing.T) {
+	cert := x509.Certificate{Raw: []byte("cert")}
+	hash := bytes.Repeat([]byte{1}, 32)
+	skipped := bytes.Repeat([]byte{2}, 32)
+	entries := []tcg.SignatureEntry{
+		{Type: "X509", Data: cert.Raw, Cert: &cert},
+		{Type: "SHA256", Data: skipped},
+		{Type: "SHA256", Data: hash},
+		{Type: "SHA1", Data: []byte("sha1")},
+		{TypeGUID: "01020304-0506-0708-090a-0b0c0d0e0f10", Data: []byte("unknown")},
+	}
+	got := convertToPbSignatureEntries(entries, []x509.Certificate{cert}, [][]byte{hash})
+	want := []*pb.SignatureEntry{
+		{Type: pb.SignatureType_SIGNATURE_TYPE_X509, Value: &pb.SignatureEntry_CertIndex{CertIndex: 0}},
+		{Type: pb.SignatureType_SIGNATURE_TYPE_SHA256, Value: &pb.SignatureEntry_Data{Data: skipped}},
+		{Type: pb.SignatureType_SIGNATURE_TYPE_SHA256, Value: &pb.SignatureEntry_HashIndex{HashIndex: 0}},
+		{Type: pb.SignatureType_SIGNATURE_TYPE_SHA1, Value: &pb.SignatureEntry_Data{Data: []byte("sha1")}},
+		{TypeGuid: "01020304-0506-0708-090a-0b0c0d0e0f10", Value: &pb.SignatureEntry_Data{Data: []byte("unknown")}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("convertToPbSignatureEntries() = %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !proto.Equal(got[i], want[i]) {
+			t.Errorf("convertToPbSignatureEntries()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEncodeDatabase(t *testing.T) {
+	_, events := getTPMELEvents(t)
+	state, err := SecureBootState(events, TPMRegisterConfig, Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	measured := make(map[string][]byte)
+	for _, e := range events {
+		if e.Type != tcg.EFIVariableDriverConfig {
+			continue
+		}
+		v, err := tcg.ParseUEFIVariableData(bytes.NewReader(e.RawData()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		measured[v.VarName()] = v.VariableData
+	}
+
+	const (
+		gceOwner       = "d281fad2-8d88-47a4-9792-5baa47bb1b89"
+		microsoftOwner = "77fa9abd-0359-4d32-bd60-28f4e78f784b"
+	)
+	for _, tc := range []struct {
+		name  string
+		db    *pb.Database
+		owner string
+	}{
+		{"PK", state.GetPk(), gceOwner},
+		{"KEK", state.GetKek(), gceOwner},
+		{"db", state.GetDb(), gceOwner},
+		{"dbx", state.GetDbx(), microsoftOwner},
+	} {
+		if len(tc.db.GetEntries()) == 0 {
+			t.Fatalf("%s has no signature list entries", tc.name)
+		}
+		// The entries give the owners, so the owner argument is ignored.
+		got, err := EncodeDatabase(tc.db, "")
+		if err != nil {
+			t.Fatalf("EncodeDatabase(%s): %v", tc.name, err)
+		}
+		if !bytes.Equal(got, measured[tc.name]) {
+			t.Errorf("EncodeDatabase(%s) does not match the measured variable", tc.name)
+		}
+		withoutEntries := proto.Clone(tc.db).(*pb.Database)
+		withoutEntries.Entries = nil
+		got, err = EncodeDatabase(withoutEntries, tc.owner)
+		if err != nil {
+			t.Fatalf("EncodeDatabase(%s without entries): %v", tc.name, err)
+		}
+		if !bytes.Equal(got, measured[tc.name]) {
+			t.Errorf("EncodeDatabase(%s without entries) does not match the measured variable", tc.name)
+		}
+	}
+
+	// Each entry keeps its owner, even within a list.
+	hashes := [][]byte{make([]byte, 32), bytes.Repeat([]byte{1}, 32)}
+	mixed := &pb.Database{Hashes: hashes, Entries: []*pb.SignatureEntry{
+		{Type: pb.SignatureType_SIGNATURE_TYPE_SHA256, TypeGuid: "c1c41626-504c-4092-aca9-41f936934328", Owner: gceOwner, Value: &pb.SignatureEntry_HashIndex{HashIndex: 0}},
+		{Type: pb.SignatureType_SIGNATURE_TYPE_SHA256, TypeGuid: "c1c41626-504c-4092-aca9-41f936934328", Owner: microsoftOwner, Value: &pb.SignatureEntry_HashIndex{HashIndex: 1}},
+	}}
+	encoded, err := EncodeDatabase(mixed, "")
+	if err != nil {
+		t.Fatalf("EncodeDatabase(mixed owners): %v", err)
+	}
+	entries, errs := (&tcg.UEFIVariableData{VariableData: encoded}).SignatureEntries()
+	if len(errs) != 0 || len(entries) != 2 || entries[0].Owner != gceOwner || entries[1].Owner != microsoftOwner ||
+		!bytes.Equal(entries[0].Data, hashes[0]) || !bytes.Equal(entries[1].Data, hashes[1]) {
+		t.Errorf("EncodeDatabase(mixed owners) decodes to %+v, %v, want both hashes with their owners", entries, errs)
+	}
+	mixed.Entries[1].Value = &pb.SignatureEntry_HashIndex{HashIndex: 2}
+	if _, err := EncodeDatabase(mixed, ""); err == nil {
+		t.Errorf("EncodeDatabase(hash index out of range): got nil, want error")
+	}
+
+	unknown := &pb.Database{Certs: []*pb.Certificate{{Representation: &pb.Certificate_WellKnown{WellKnown: pb.WellKnownCertificate(100)}}}}
+	if _, err := EncodeDatabase(unknown, ""); err == nil {
+		t.Errorf("EncodeDatabase(unknown well known certificate): got nil, want error")
+	}
+}
+
+// numberedEvents returns the events as parsed from a SHA-1 format event log,
+// so they are numbered in order starting from 0.
+func numberedEvents(t *testing.T, events []tcg.Event) []tcg.Event {
+	t.Helper()
+	parsed, err := tcg.ParseEventLog(sha1EventLog(events), tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Events(register.HashSHA1)
+}
+
+// replayedEvents returns the events as replayed from a SHA-1 format event log
+// against the PCR values they extend to.
+func replayedEvents(t *testing.T, events []tcg.Event) []tcg.Event {
+	t.Helper()
+	pcrs := make(map[uint32][]byte)
+	for _, event := range numberedEvents(t, events) {
+		pcr, ok := pcrs[event.MRIndex()]
+		if !ok {
+			pcr = make([]byte, crypto.SHA1.Size())
+		}
+		extended := sha1.Sum(append(pcr, event.ReplayedDigest()...))
+		pcrs[event.MRIndex()] = extended[:]
+	}
+	bank := testutil.MakePCRBank(pb.HashAlgo_SHA1, pcrs)
+	replayed, err := tcg.ParseAndReplay(sha1EventLog(events), bank.MRs(), tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return replayed
+}
+
+func sha1EventLog(events []tcg.Event) []byte {
+	var log bytes.Buffer
+	for _, event := range events {
+		digest := make([]byte, crypto.SHA1.Size())
+		copy(digest, event.Digest)
+		binary.Write(&log, binary.LittleEndian, uint32(event.Index))
+		binary.Write(&log, binary.LittleEndian, uint32(event.Type))
+		log.Write(digest)
+		binary.Write(&log, binary.LittleEndian, uint32(len(event.Data)))
+		log.Write(event.Data)
+	}
+	return log.Bytes()
+}
+
+func TestAuthorityUsages(t *testing.T) {
+	certA := x509.Certificate{Raw: []byte("cert A")}
+	certB := x509.Certificate{Raw: []byte("cert B")}
+	events := numberedEvents(t, []tcg.Event{
+		{Index: 7, Type: tcg.EFIVariableAuthority},
+		{Index: 8, Type: tcg.Ipl, Data: []byte("grub_cmd: unrelated")},
+		{Index: 4, Type: tcg.EFIBootServicesApplication, Digest: []byte("shim")},
+		{Index: 7, Type: tcg.EFIVariableAuthority},
+		{Index: 4, Type: tcg.EFIBootServicesApplication, Digest: []byte("grub")},
+		{Index: 7, Type: tcg.EFIVariableAuthority},
+	})
+	shim, grub := events[2].ReplayedDigest(), events[4].ReplayedDigest()
+	uses := []AuthorityUse{
+		{EventNum: 0, VariableName: "db", Certs: []x509.Certificate{certA}},
+		{EventNum: 3, VariableName: "MokListRT", Certs: []x509.Certificate{certB, certA}},
+		{EventNum: 5, VariableName: "db", Certs: []x509.Certificate{certA}},
+	}
+
+	certs, usages := authorityUsages(events, uses, TPMRegisterConfig, 2)
+	if len(certs) != 2 || !bytes.Equal(certs[0].Raw, certA.Raw) || !bytes.Equal(certs[1].Raw, certB.Raw) {
+		t.Fatalf("authorityUsages() = got certs %v, want A and B", certs)
+	}
+	want := []*pb.AuthorityUsage{
+		{CertIndex: 0, Count: 3, Uses: []*pb.AuthorityUse{
+			{EventNum: 0, VariableName: "db", ImageEventNum: 2, ImageDigest: shim},
+			{EventNum: 3, VariableName: "MokListRT", ImageEventNum: 4, ImageDigest: grub},
+			{EventNum: 5, VariableName: "db"},
+		}},
+		{CertIndex: 1, Count: 1, Uses: []*pb.AuthorityUse{
+			{EventNum: 3, VariableName: "MokListRT", ImageEventNum: 4, ImageDigest: grub},
+		}},
+	}
+	if len(usages) != len(want) {
+		t.Fatalf("authorityUsages() = got %v, want %v", usages, want)
+	}
+	for i := range want {
+		if !proto.Equal(usages[i], want[i]) {
+			t.Errorf("authorityUsages() = got usage %v, want %v", usages[i], want[i])
+		}
+	}
+}
+
+func TestShimAuthorityUsages(t *testing.T) {
+	cert := x509.Certificate{Raw: []byte("vendor cert")}
+	// Shim measures the image it loads before the authority that verified
+	// it.
+	events := numberedEvents(t, []tcg.Event{
+		{Index: 4, Type: tcg.EFIBootServicesApplication, Digest: []byte("shim")},
+		{Index: 4, Type: tcg.EFIBootServicesApplication, Digest: []byte("grub")},
+		{Index: 7, Type: tcg.EFIVariableAuthority},
+		{Index: 4, Type: tcg.EFIBootServicesApplication, Digest: []byte("kernel")},
+	})
+	uses := []AuthorityUse{{EventNum: 2, VariableName: "Shim", Certs: []x509.Certificate{cert}}}
+	for _, tc := range []struct {
+		compatLevel uint32
+		want        tcg.Event
+	}{
+		{2, events[3]},
+		{SchemaVersion, events[1]},
+	} {
+		_, usages := authorityUsages(events, uses, TPMRegisterConfig, tc.compatLevel)
+		use := usages[0].GetUses()[0]
+		if use.GetImageEventNum() != tc.want.Num() || !bytes.Equal(use.GetImageDigest(), tc.want.ReplayedDigest()) {
+			t.Errorf("authorityUsages(compat level %d) = got image event %d, want %d", tc.compatLevel, use.GetImageEventNum(), tc.want.Num())
+		}
+	}
+}
+
+func TestAppSigners(t *testing.T) {
+	certA := &pb.Certificate{Representation: &pb.Certificate_WellKnown{WellKnown: pb.WellKnownCertificate_MS_THIRD_PARTY_UEFI_CA_2011}}
+	certB := &pb.Certificate{Representation: &pb.Certificate_Der{Der: []byte("MOK")}}
+	shim, grub, other := []byte("shim"), []byte("grub"), []byte("other")
+	state := &pb.FirmwareLogState{
+		SecureBoot: &pb.SecureBootState{
+			Authority: &pb.Database{Certs: []*pb.Certificate{certA, certB}},
+			AuthorityUsages: []*pb.AuthorityUsage{
+				{CertIndex: 0, Count: 2, Uses: []*pb.AuthorityUse{
+					{EventNum: 10, VariableName: "db", ImageEventNum: 12, ImageDigest: shim},
+					{EventNum: 30, VariableName: "db"},
+				}},
+				{CertIndex: 1, Count: 1, Uses: []*pb.AuthorityUse{
+					{EventNum: 20, VariableName: "MokListRT", ImageEventNum: 22, ImageDigest: grub},
+				}},
+			},
+		},
+		Efi: &pb.EfiState{Apps: []*pb.EfiApp{{Digest: shim}, {Digest: grub}, {Digest: other}}},
+	}
+
+	signers := AppSigners(state)
+	want := [][]AppAuthority{
+		{{Cert: certA, VariableName: "db", EventNum: 10}},
+		{{Cert: certB, VariableName: "MokListRT", EventNum: 20}},
+		nil,
+	}
+	if len(signers) != len(want) {
+		t.Fatalf("AppSigners() = got %d signers, want %d", len(signers), len(want))
+	}
+	for i, signer := range signers {
+		if signer.App != state.GetEfi().GetApps()[i] {
+			t.Errorf("AppSigners()[%d] = got app %v, want %v", i, signer.App, state.GetEfi().GetApps()[i])
+		}
+		if len(signer.Authorities) != len(want[i]) {
+			t.Errorf("AppSigners()[%d] = got authorities %v, want %v", i, signer.Authorities, want[i])
+			continue
+		}
+		for j, got := range signer.Authorities {
+			if !proto.Equal(got.Cert, want[i][j].Cert) || got.VariableName != want[i][j].VariableName || got.EventNum != want[i][j].EventNum {
+				t.Errorf("AppSigners()[%d] = got authority %v, want %v", i, got, want[i][j])
+			}
+		}
+	}
+
+	if signers := AppSigners(&pb.FirmwareLogState{}); len(signers) != 0 {
+		t.Errorf("AppSigners(empty state) = got %v, want none", signers)
+	}
+}
+
+func TestSecureBootContradictions(t *testing.T) {
+	variable := func(name string, data []byte) tcg.Event {
+		v := tcg.UEFIVariableData{UnicodeName: utf16.Encode([]rune(name)), VariableData: data}
+		encoded, err := v.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tcg.Event{Index: 7, Type: tcg.EFIVariableDriverConfig, Data: encoded}
+	}
+	pk := variable("PK", []byte("a platform key"))
+	noPK := variable("PK", nil)
+	tests := []struct {
+		name   string
+		events []tcg.Event
+		want   int
+	}{
+		{"user mode", []tcg.Event{variable("SecureBoot", []byte{1}), variable("SetupMode", []byte{0}), pk}, 0},
+		{"setup mode", []tcg.Event{variable("SecureBoot", []byte{0}), variable("SetupMode", []byte{1}), noPK}, 0},
+		{"SetupMode not measured", []tcg.Event{variable("SecureBoot", []byte{1}), pk}, 0},
+		{"enabled in setup mode", []tcg.Event{variable("SecureBoot", []byte{1}), variable("SetupMode", []byte{1}), pk}, 2},
+		{"enabled without PK", []tcg.Event{variable("SecureBoot", []byte{1}), noPK}, 1},
+		{"user mode without PK", []tcg.Event{variable("SecureBoot", []byte{0}), variable("SetupMode", []byte{0}), noPK}, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := SecureBootContradictions(tc.events, TPMRegisterConfig)
+			if len(got) != tc.want {
+				t.Errorf("SecureBootContradictions() = %q, want %d contradictions", got, tc.want)
+			}
+			findings := secureBootFindings(tc.events, TPMRegisterConfig)
+			if (len(findings) != 0) != (tc.want != 0) {
+				t.Errorf("secureBootFindings() = %v, want a finding: %v", findings, tc.want != 0)
+			}
+		})
+	}
+}
+
+func TestLegacyOptionROMEvents(t *testing.T) {
+	tagged := func(index int, id uint32) tcg.Event {
+		data := binary.LittleEndian.AppendUint32(nil, id)
+		data = binary.LittleEndian.AppendUint32(data, 1)
+		return tcg.Event{Index: index, Type: tcg.EventTag, Data: append(data, 0xff)}
+	}
+	separator := func(index int) tcg.Event {
+		return tcg.Event{Index: index, Type: tcg.Separator, Data: []byte{0, 0, 0, 0}}
+	}
+	tests := []struct {
+		name   string
+		events []tcg.Event
+		want   int
+	}{
+		{"UEFI drivers only", []tcg.Event{{Index: 2, Type: tcg.EFIBootServicesDriver}, {Index: 0, Type: tcg.PostCode, Data: []byte("ACPI DATA")}}, 0},
+		{"POST code option ROM", []tcg.Event{{Index: 2, Type: tcg.PostCode, Data: []byte("option rom")}}, 1},
+		{"tagged option ROMs", []tcg.Event{tagged(2, optionROMExecuteTag), tagged(3, optionROMConfigTag), tagged(3, optionROMMicrocodeTag), separator(2), separator(3)}, 3},
+		{"other tags", []tcg.Event{tagged(3, 0x1), tagged(12, optionROMExecuteTag), tagged(9, 0x8f3b22ed), separator(3)}, 0},
+		{"tagged option ROMs after separator", []tcg.Event{separator(2), separator(3), tagged(2, optionROMExecuteTag), tagged(3, optionROMConfigTag)}, 0},
+		{"truncated tagged event", []tcg.Event{{Index: 3, Type: tcg.EventTag, Data: []byte{optionROMConfigTag, 0, 0}}, separator(3)}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			events := numberedEvents(t, tc.events)
+			got := LegacyOptionROMEvents(events, TPMRegisterConfig)
+			if len(got) != tc.want {
+				t.Errorf("LegacyOptionROMEvents() = %v, want %d events", got, tc.want)
+			}
+			findings := legacyOptionROMFindings(events, TPMRegisterConfig)
+			if (len(findings) != 0) != (tc.want != 0) {
+				t.Errorf("legacyOptionROMFindings() = %v, want a finding: %v", findings, tc.want != 0)
+			}
+		})
+	}
+}
+
+func TestLegacyOptionROMEventsRTMR(t *testing.T) {
+	// RTMR[1] (CCMR2) holds both the PCR2 and PCR3 events, and CCMR3 is the
+	// OS register.
+	events := numberedEvents(t, []tcg.Event{
+		{Index: 2, Type: tcg.EventTag, Data: binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, optionROMConfigTag), 0)},
+		{Index: 2, Type: tcg.Separator, Data: []byte{0, 0, 0, 0}},
+		{Index: 3, Type: tcg.EventTag, Data: binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, optionROMExecuteTag), 0)},
+	})
+	got := LegacyOptionROMEvents(events, RTMRRegisterConfig)
+	if len(got) != 1 || got[0].MRIndex() != 2 {
+		t.Errorf("LegacyOptionROMEvents() = %v, want the CCMR2 event", got)
+	}
+}
+
+func TestEventSummaries(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	fs, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range fs.GetRawEvents() {
+		if event.GetSummary() != "" {
+			t.Fatalf("FirmwareLogState() = got summary %q without EventSummaries", event.GetSummary())
+		}
+	}
+
+	fs, err = FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, EventSummaries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summaries := make(map[string]bool)
+	for _, event := range fs.GetRawEvents() {
+		if event.GetSummary() == "" {
+			t.Errorf("FirmwareLogState() = got no summary for event %v", event)
+		}
+		summaries[event.GetSummary()] = true
+	}
+	for _, want := range []string{
+		"EFI Action: " + tcg.ExitBootServicesInvocation,
+		"EFI Variable Driver Config: variable SecureBoot (1 bytes)",
+		"Separator: separator 00000000",
+	} {
+		if !summaries[want] {
+			t.Errorf("FirmwareLogState() = got no event summary %q", want)
+		}
+	}
+}
+
+func TestLinuxStubState(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	var wantDigest []byte
+	for _, event := range events {
+		if event.MRIndex() == 9 && event.UntrustedType() == tcg.EventTag {
+			wantDigest = event.ReplayedDigest()
+		}
+	}
+	state, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatalf("FirmwareLogState(): %v", err)
+	}
+	if got := state.GetLinuxKernel().GetLoadOptionsDigest(); wantDigest == nil || !bytes.Equal(got, wantDigest) {
+		t.Errorf("FirmwareLogState(): got load options digest %x, want %x", got, wantDigest)
+	}
+	if got := state.GetLinuxKernel().GetInitrdDigest(); got != nil {
+		t.Errorf("FirmwareLogState(): got initrd digest %x, want none", got)
+	}
+	if !state.GetLinuxKernel().GetLoadOptionsMatch() {
+		t.Errorf("FirmwareLogState(): load options do not match command line %q", state.GetLinuxKernel().GetCommandLine())
+	}
+	old, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: 4})
+	if err != nil {
+		t.Fatalf("FirmwareLogState(CompatLevel 4): %v", err)
+	}
+	if got := old.GetLinuxKernel().GetLoadOptionsDigest(); got != nil {
+		t.Errorf("FirmwareLogState(CompatLevel 4): got load options digest %x, want none", got)
+	}
+
+	loadOptions := tcg.Event{Index: 9, Type: tcg.EventTag, Data: []byte("LOADED_IMAGE::LoadOptions\x00"), Digest: []byte{1}}
+	unknown := tcg.Event{Index: 8, Type: tcg.EventTag, Data: []byte("tboot"), Digest: []byte{2}}
+	for _, tc := range []struct {
+		name          string
+		events        []tcg.Event
+		rejectUnknown bool
+		wantDigest    bool
+		wantErr       bool
+	}{
+		{"description only", []tcg.Event{loadOptions}, false, true, false},
+		{"unknown tag ignored", []tcg.Event{unknown, loadOptions}, false, true, false},
+		{"unknown tag rejected", []tcg.Event{unknown, loadOptions}, true, false, true},
+		{"duplicate load options", []tcg.Event{loadOptions, loadOptions}, false, false, true},
+		{"no stub events", []tcg.Event{unknown}, false, false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kernel, err := LinuxStubState(numberedEvents(t, tc.events), TPMRegisterConfig, tc.rejectUnknown)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LinuxStubState(): got error %v, want error: %v", err, tc.wantErr)
+			}
+			if got := kernel.GetLoadOptionsDigest() != nil; got != tc.wantDigest {
+				t.Errorf("LinuxStubState(): got load options digest %v, want %v", got, tc.wantDigest)
+			}
+		})
+	}
+}
+
+func TestLoadOptionsMatch(t *testing.T) {
+	cmdline := "/vmlinuz root=/dev/sda1 ro\x00"
+	digest := func(options string, terminated bool) []byte {
+		encoded := encodeUTF16(options)
+		if terminated {
+			encoded = append(encoded, 0, 0)
+		}
+		sum := sha256.Sum256(encoded)
+		return sum[:]
+	}
+	utf8Digest := sha256.Sum256([]byte("BOOT_IMAGE=/vmlinuz root=/dev/sda1 ro"))
+	for _, tc := range []struct {
+		name    string
+		cmdline string
+		digest  []byte
+		want    bool
+	}{
+		{"GRUB", cmdline, digest("BOOT_IMAGE=/vmlinuz root=/dev/sda1 ro", false), true},
+		{"as is", "root=/dev/sda1 ro", digest("root=/dev/sda1 ro", true), true},
+		{"tampered", cmdline, digest("BOOT_IMAGE=/vmlinuz root=/dev/sda1 rw", false), false},
+		{"UTF-8", cmdline, utf8Digest[:], false},
+		{"no digest", cmdline, nil, false},
+		{"no command line", "", digest("BOOT_IMAGE=", false), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := LoadOptionsMatch(crypto.SHA256, tc.cmdline, tc.digest); got != tc.want {
+				t.Errorf("LoadOptionsMatch() = %v, want %v", got, tc.want)
+			}
+			kernel := &pb.LinuxKernelState{CommandLine: tc.cmdline, LoadOptionsDigest: tc.digest, LoadOptionsMatch: tc.want}
+			wantFinding := tc.cmdline != "" && tc.digest != nil && !tc.want
+			if got := len(loadOptionsFindings(kernel)) != 0; got != wantFinding {
+				t.Errorf("loadOptionsFindings(): got a finding: %v, want %v", got, wantFinding)
+			}
+		})
+	}
+}
+
+func TestWindowsSipaState(t *testing.T) {
+	data, err := os.ReadFile("../testdata/legacydata/windows_gcp_shielded_vm.json")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	var dump testutil.Dump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("parsing test data: %v", err)
+	}
+	events, err := tcg.ParseAndReplay(dump.Log.Raw, (register.PCRBank{TCGHashAlgo: pb.HashAlgo(dump.Log.PCRAlg), PCRs: dump.Log.PCRs}).MRs(), tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// This log measures EFI applications before the EV_EFI_ACTION event, so
+	// EfiState extraction fails, but the rest of the state is returned.
+	state, _ := FirmwareLogState(events, crypto.SHA1, TPMRegisterConfig, Opts{Loader: AutoDetect})
+	sipa := state.GetWindowsSipa()
+	if sipa.GetBootCount() != 4 || !sipa.GetCodeIntegrityEnabled() || sipa.GetTestSigningEnabled() || sipa.GetKernelDebugEnabled() {
+		t.Errorf("FirmwareLogState(): got Windows SIPA state %v, want boot count 4 with only code integrity enabled", sipa)
+	}
+	var winload *pb.WindowsLoadedModule
+	for _, module := range sipa.GetLoadedModules() {
+		if module.GetFilePath() == `\Windows\system32\winload.efi` {
+			winload = module
+		}
+	}
+	if winload == nil {
+		t.Fatalf("FirmwareLogState(): winload.efi not in loaded modules")
+	}
+	if !winload.GetImageValidated() || winload.GetAuthorityIssuer() != "Microsoft Windows Production PCA 2011" || len(winload.GetAuthenticodeHash()) != crypto.SHA256.Size() {
+		t.Errorf("FirmwareLogState(): got winload.efi module %v", winload)
+	}
+
+	old, _ := FirmwareLogState(events, crypto.SHA1, TPMRegisterConfig, Opts{Loader: AutoDetect, CompatLevel: 6})
+	if old.GetWindowsSipa() != nil {
+		t.Errorf("FirmwareLogState(CompatLevel 6): got Windows SIPA state, want none")
+	}
+
+	// SIPA events whose data was not measured are rejected.
+	bootCounter := []byte{0x02, 0x00, 0x02, 0x00, 8, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0}
+	tampered := numberedEvents(t, []tcg.Event{{Index: 12, Type: tcg.EventTag, Data: bootCounter, Digest: []byte{1}}})
+	if _, err := WindowsSipaState(crypto.SHA1, tampered); err == nil {
+		t.Errorf("WindowsSipaState(): got nil, want error for a digest mismatch")
+	}
+}
+
+func TestMokState(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	sbState, err := SecureBootState(events, TPMRegisterConfig, Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := MokState(hash, events, TPMRegisterConfig, sbState)
+	var got []string
+	for _, m := range state.GetMeasurements() {
+		if m.GetUntrustedType() != uint32(tcg.Ipl) {
+			t.Errorf("measurement %q: got type %d, want EV_IPL", m.GetDescription(), m.GetUntrustedType())
+		}
+		if len(m.GetDigest()) != crypto.SHA256.Size() {
+			t.Errorf("measurement %q: got %d digest bytes, want %d", m.GetDescription(), len(m.GetDigest()), crypto.SHA256.Size())
+		}
+		got = append(got, m.GetDescription())
+	}
+	if want := []string{"MokList", "MokListX", "MokListTrusted"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MokState(): got MOK measurements %q, want %q", got, want)
+	}
+	if want := decodeHex("2f196b05a0564764cca674175ecd97898e74ed3891c7c63ce6f17dc82603164a"); !bytes.Equal(state.GetMokListDigest(), want) {
+		t.Errorf("MokState(): got MokList digest %x, want %x", state.GetMokListDigest(), want)
+	}
+	if len(state.GetMokListXDigest()) == 0 {
+		t.Errorf("MokState(): got no MokListX digest")
+	}
+	if !state.GetMokListTrusted() {
+		t.Errorf("MokState(): got MokListTrusted disabled, want enabled")
+	}
+	if want := "sbat,1,2021030218\n"; state.GetSbatLevel() != want {
+		t.Errorf("MokState(): got SBAT level %q, want %q", state.GetSbatLevel(), want)
+	}
+
+	firmwareState, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(firmwareState.GetSecureBoot().GetMok(), state) {
+		t.Errorf("FirmwareLogState(): got MOK state %v, want %v", firmwareState.GetSecureBoot().GetMok(), state)
+	}
+	old, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.GetSecureBoot().GetMok() != nil {
+		t.Errorf("FirmwareLogState(CompatLevel: 10): got MOK state %v, want nil", old.GetSecureBoot().GetMok())
+	}
+
+	compact := numberedEvents(t, []tcg.Event{
+		{Index: 14, Type: tcg.CompactHash, Data: []byte("MokList\x00"), Digest: []byte{1}},
+		{Index: 14, Type: tcg.CompactHash, Data: []byte{0xff, 0x01, 0x02, 0x03}, Digest: []byte{2}},
+		{Index: 14, Type: tcg.Ipl, Data: []byte("MokListTrusted\x00"), Digest: []byte{3}},
+		{Index: 14, Type: tcg.EFIAction, Data: []byte("action"), Digest: []byte{4}},
+		{Index: 7, Type: tcg.Ipl, Data: []byte("MokListX\x00"), Digest: []byte{5}},
+	})
+	mokUsed := &pb.SecureBootState{AuthorityUsages: []*pb.AuthorityUsage{
+		{CertIndex: 0, Uses: []*pb.AuthorityUse{{VariableName: "db"}}},
+		{CertIndex: 1, Uses: []*pb.AuthorityUse{{VariableName: "MokListRT"}, {VariableName: "MokListRT"}}},
+	}}
+	want := &pb.MokState{
+		Measurements: []*pb.MokMeasurement{
+			{EventNum: compact[0].Num(), UntrustedType: uint32(tcg.CompactHash), Description: "MokList", Digest: compact[0].ReplayedDigest()},
+			{EventNum: compact[1].Num(), UntrustedType: uint32(tcg.CompactHash), Digest: compact[1].ReplayedDigest()},
+			{EventNum: compact[2].Num(), UntrustedType: uint32(tcg.Ipl), Description: "MokListTrusted", Digest: compact[2].ReplayedDigest()},
+		},
+		MokListDigest:        compact[0].ReplayedDigest(),
+		AuthorityCertIndexes: []uint32{1},
+	}
+	if got := MokState(crypto.SHA1, compact, TPMRegisterConfig, mokUsed); !proto.Equal(got, want) {
+		t.Errorf("MokState() = %v, want %v", got, want)
+	}
+	if got := MokState(crypto.SHA1, compact[3:], TPMRegisterConfig, nil); got != nil {
+		t.Errorf("MokState(no MOK measurements) = %v, want nil", got)
+	}
+}
+
+func TestBootConfigState(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	state, err := BootConfigState(events, TPMRegisterConfig)
+	if err != nil {
+		t.Fatalf("BootConfigState(): %v", err)
+	}
+	if want := []uint32{2, 1, 0}; !reflect.DeepEqual(state.GetBootOrder(), want) {
+		t.Errorf("BootConfigState(): got boot order %v, want %v", state.GetBootOrder(), want)
+	}
+	var gotNumbers []uint32
+	var gotDescriptions []string
+	for _, option := range state.GetBootOptions() {
+		if !option.GetActive() {
+			t.Errorf("BootConfigState(): got inactive boot option %d", option.GetNumber())
+		}
+		gotNumbers = append(gotNumbers, option.GetNumber())
+		gotDescriptions = append(gotDescriptions, option.GetDescription())
+	}
+	if want := []uint32{2, 1, 0}; !reflect.DeepEqual(gotNumbers, want) {
+		t.Errorf("BootConfigState(): got boot options %v, want %v", gotNumbers, want)
+	}
+	if want := []string{"Ubuntu", "UEFI nvme_card-pd", "UiApp"}; !reflect.DeepEqual(gotDescriptions, want) {
+		t.Errorf("BootConfigState(): got boot option descriptions %q, want %q", gotDescriptions, want)
+	}
+	if want := `PciRoot(0x0)/Pci(0x4,0x0)/NVMe(0x1,00-00-00-00-00-00-00-00)/HD(15,GPT,fc24e8b6-5e8c-4396-aadd-2c6a9a43b92d,0x2800,0x35000)/\EFI\ubuntu\shimx64.efi`; state.GetBootOptions()[0].GetDevicePath() != want {
+		t.Errorf("BootConfigState(): got boot option device path %q, want %q", state.GetBootOptions()[0].GetDevicePath(), want)
+	}
+	if len(state.GetPlatformConfigs()) != 3 {
+		t.Fatalf("BootConfigState(): got %d platform configs, want 3", len(state.GetPlatformConfigs()))
+	}
+	for _, config := range state.GetPlatformConfigs() {
+		if config.GetUntrustedType() != uint32(tcg.PlatformConfigFlags) || config.GetUntrustedDescription() != "ACPI DATA" {
+			t.Errorf("BootConfigState(): got platform config %v, want ACPI DATA platform config flags", config)
+		}
+	}
+	if len(state.GetUnverifiedVariables()) != 0 {
+		t.Errorf("BootConfigState(): got unverified variables %q", state.GetUnverifiedVariables())
+	}
+
+	firmwareState, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(firmwareState.GetBootConfig(), state) {
+		t.Errorf("FirmwareLogState(): got boot config %v, want %v", firmwareState.GetBootConfig(), state)
+	}
+	old, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.GetBootConfig() != nil {
+		t.Errorf("FirmwareLogState(CompatLevel: 11): got boot config %v, want nil", old.GetBootConfig())
+	}
+
+	order := tcg.UEFIVariableData{UnicodeName: utf16.Encode([]rune("BootOrder")), VariableData: []byte{1, 0}}
+	orderData, err := order.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unverified := numberedEvents(t, []tcg.Event{
+		{Index: 1, Type: tcg.EFIVariableBoot, Data: orderData, Digest: []byte{1}},
+		{Index: 1, Type: tcg.EFIVariableBoot, Data: nil, Digest: []byte{2}},
+	})
+	want := &pb.BootConfigState{UnverifiedVariables: []string{"BootOrder"}}
+	if got, err := BootConfigState(unverified, TPMRegisterConfig); err != nil || !proto.Equal(got, want) {
+		t.Errorf("BootConfigState(unverified) = %v, %v, want %v", got, err, want)
+	}
+	if got, err := BootConfigState(unverified[1:], TPMRegisterConfig); err != nil || got != nil {
+		t.Errorf("BootConfigState(no event data) = %v, %v, want nil", got, err)
+	}
+}
+
+func TestDevicePathsCompatLevel(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	state, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `PciRoot(0x0)/Pci(0x4,0x0)/Path(3,23,010000000000000000000000)/HD(15,0x2800,0x35000)/\EFI\ubuntu\shimx64.efi`; state.GetBootConfig().GetBootOptions()[0].GetDevicePath() != want {
+		t.Errorf("FirmwareLogState(CompatLevel: 15): got boot option device path %q, want %q", state.GetBootConfig().GetBootOptions()[0].GetDevicePath(), want)
+	}
+	for _, app := range state.GetEfi().GetApps() {
+		if want := (&pb.EfiApp{Digest: app.GetDigest()}); !proto.Equal(app, want) {
+			t.Errorf("FirmwareLogState(CompatLevel: 15): got EFI app %v, want %v", app, want)
+		}
+	}
+
+	state, err = FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apps := state.GetEfi().GetApps()
+	if len(apps) == 0 {
+		t.Fatal("FirmwareLogState(): got no EFI apps")
+	}
+	// The shim device path names the partition measured in the GPT.
+	var found bool
+	for _, partition := range state.GetGpt().GetPartitions() {
+		if partition.GetPartitionGuid() == apps[0].GetUntrustedPartitionGuid() {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("FirmwareLogState(): EFI app partition GUID %q not found in GPT %v", apps[0].GetUntrustedPartitionGuid(), state.GetGpt())
+	}
+}
+
+func TestGptState(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	state, err := GptState(events, TPMRegisterConfig)
+	if err != nil {
+		t.Fatalf("GptState(): %v", err)
+	}
+	if want := "9b3aa448-be40-489b-b8c3-9c16fb4fb6a2"; state.GetDiskGuid() != want {
+		t.Errorf("GptState(): got disk GUID %q, want %q", state.GetDiskGuid(), want)
+	}
+	var gotTypes []string
+	for _, partition := range state.GetPartitions() {
+		gotTypes = append(gotTypes, partition.GetTypeGuid())
+	}
+	wantTypes := []string{
+		"0fc63daf-8483-4772-8e79-3d69d8477de4",
+		"21686148-6449-6e6f-744e-656564454649",
+		"c12a7328-f81f-11d2-ba4b-00a0c93ec93b",
+		"bc13c2ff-59e6-4262-a352-b275fd6f7172",
+	}
+	if !reflect.DeepEqual(gotTypes, wantTypes) {
+		t.Errorf("GptState(): got partition types %q, want %q", gotTypes, wantTypes)
+	}
+	if esp := state.GetPartitions()[2]; esp.GetStartingLba() != 10240 || esp.GetEndingLba() != 227327 {
+		t.Errorf("GptState(): got EFI system partition LBAs %d-%d, want 10240-227327", esp.GetStartingLba(), esp.GetEndingLba())
+	}
+
+	firmwareState, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(firmwareState.GetGpt(), state) {
+		t.Errorf("FirmwareLogState(): got GPT state %v, want %v", firmwareState.GetGpt(), state)
+	}
+	old, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.GetGpt() != nil {
+		t.Errorf("FirmwareLogState(CompatLevel: 12): got GPT state %v, want nil", old.GetGpt())
+	}
+
+	var gptData []byte
+	for _, event := range events {
+		if event.Type == tcg.EFIGPTEvent {
+			gptData = event.Data
+		}
+	}
+	gptDigest := sha1.Sum(gptData)
+	gptEvent := tcg.Event{Index: 5, Type: tcg.EFIGPTEvent, Data: gptData, Digest: gptDigest[:]}
+	unmeasured := tcg.Event{Index: 5, Type: tcg.EFIGPTEvent, Data: gptData, Digest: []byte{1}}
+	otherPCR := gptEvent
+	otherPCR.Index = 4
+	for _, tc := range []struct {
+		name    string
+		events  []tcg.Event
+		wantNil bool
+		wantErr bool
+	}{
+		{"measured", []tcg.Event{gptEvent}, false, false},
+		{"other PCR ignored", []tcg.Event{otherPCR}, true, false},
+		{"unmeasured", []tcg.Event{unmeasured}, false, true},
+		{"duplicate", []tcg.Event{gptEvent, gptEvent}, false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := GptState(numberedEvents(t, tc.events), TPMRegisterConfig)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GptState(): got error %v, want error %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && (got == nil) != tc.wantNil {
+				t.Errorf("GptState() = %v, want nil %v", got, tc.wantNil)
+			}
+		})
+	}
+}
+
+func TestFirmwareLogStateCapped(t *testing.T) {
+	errorSeparator := []byte{0xff, 0xff, 0xff, 0xff}
+	errorSeparatorDigest := sha1.Sum(errorSeparator)
+	var capped []tcg.Event
+	for pcr := 0; pcr < 8; pcr++ {
+		capped = append(capped, tcg.Event{Index: pcr, Type: tcg.Separator, Data: errorSeparator, Digest: errorSeparatorDigest[:]})
+	}
+	events := replayedEvents(t, capped)
+
+	state, err := FirmwareLogState(events, crypto.SHA1, TPMRegisterConfig, Opts{Loader: GRUB})
+	var cappedErr CappedError
+	if !errors.As(err, &cappedErr) {
+		t.Fatalf("FirmwareLogState(): got error %v, want CappedError", err)
+	}
+	if want := []uint32{0, 1, 2, 3, 4, 5, 6, 7}; !reflect.DeepEqual(cappedErr.Capping.Nums, want) {
+		t.Errorf("FirmwareLogState(): got capping events %v, want %v", cappedErr.Capping.Nums, want)
+	}
+	if len(state.GetFindings()) != 1 || state.GetFindings()[0].GetType() != pb.FindingType_FINDING_TYPE_PCRS_CAPPED {
+		t.Errorf("FirmwareLogState(): got findings %v, want a PCRs capped finding", state.GetFindings())
+	}
+	if len(state.GetRawEvents()) != len(events) || state.GetSecureBoot() != nil {
+		t.Errorf("FirmwareLogState(): got %d raw events and Secure Boot state %v, want %d raw events only", len(state.GetRawEvents()), state.GetSecureBoot(), len(events))
+	}
+
+	if _, err := FirmwareLogState(events, crypto.SHA1, TPMRegisterConfig, Opts{Loader: GRUB, CompatLevel: 13}); err == nil || errors.As(err, &cappedErr) {
+		t.Errorf("FirmwareLogState(CompatLevel: 13): got error %v, want extraction errors", err)
+	}
+	afterCap := replayedEvents(t, append(capped, tcg.Event{Index: 4, Type: tcg.EFIAction, Data: []byte("after")}))
+	if _, err := FirmwareLogState(afterCap, crypto.SHA1, TPMRegisterConfig, Opts{Loader: GRUB}); err == nil || errors.As(err, &cappedErr) {
+		t.Errorf("FirmwareLogState(event after capping): got error %v, want invalid capping error", err)
+	}
+}
+
+func TestUkiState(t *testing.T) {
+	sha1Digest := func(measured string) []byte {
+		h := crypto.SHA1.New()
+		h.Write([]byte(measured))
+		return h.Sum(nil)
+	}
+	sectionEvents := func(name, contents string) []tcg.Event {
+		description := encodeUTF16(name + "\x00")
+		return []tcg.Event{
+			{Index: 11, Type: tcg.Ipl, Data: description, Digest: sha1Digest(name + "\x00")},
+			{Index: 11, Type: tcg.Ipl, Data: description, Digest: sha1Digest(contents)},
+		}
+	}
+	phase := func(name string) tcg.Event {
+		return tcg.Event{Index: 11, Type: tcg.Ipl, Data: []byte(name), Digest: sha1Digest(name)}
+	}
+	var uki []tcg.Event
+	uki = append(uki, sectionEvents(".linux", "kernel")...)
+	uki = append(uki, sectionEvents(".cmdline", "root=/dev/sda1")...)
+	uki = append(uki, phase("enter-initrd"), phase("leave-initrd"))
+
+	state, err := UkiState(crypto.SHA1, numberedEvents(t, uki))
+	if err != nil {
+		t.Fatalf("UkiState(): %v", err)
+	}
+	want := &pb.UkiState{
+		Sections: []*pb.UkiSection{
+			{Name: ".linux", Digest: sha1Digest("kernel")},
+			{Name: ".cmdline", Digest: sha1Digest("root=/dev/sda1")},
+		},
+		Phases: []string{"enter-initrd", "leave-initrd"},
+	}
+	if !proto.Equal(state, want) {
+		t.Errorf("UkiState() = %v, want %v", state, want)
+	}
+
+	unmeasured := tcg.Event{Index: 11, Type: tcg.Ipl, Data: []byte("ready"), Digest: []byte{1}}
+	otherPCR := phase("ready")
+	otherPCR.Index = 12
+	for _, tc := range []struct {
+		name    string
+		events  []tcg.Event
+		wantNil bool
+		wantErr bool
+	}{
+		{"unmeasured phase ignored", []tcg.Event{unmeasured}, true, false},
+		{"other PCR ignored", []tcg.Event{otherPCR}, true, false},
+		{"section without contents", sectionEvents(".linux", "kernel")[:1], false, true},
+		{"section contents mismatch", append(sectionEvents(".linux", "kernel")[:1], sectionEvents(".initrd", "initrd")[1]), false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			state, err := UkiState(crypto.SHA1, numberedEvents(t, tc.events))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("UkiState(): got error %v, want error: %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && (state == nil) != tc.wantNil {
+				t.Errorf("UkiState() = %v, want nil: %v", state, tc.wantNil)
+			}
+		})
+	}
+}
+
+func TestLinuxKernelStateFromSystemdBoot(t *testing.T) {
+	cmdline := func(index int, options string, measured bool) tcg.Event {
+		data := append(encodeUTF16(options), 0, 0)
+		digest := []byte{1}
+		if measured {
+			h := crypto.SHA1.New()
+			h.Write(data)
+			digest = h.Sum(nil)
+		}
+		return tcg.Event{Index: index, Type: tcg.Ipl, Data: data, Digest: digest}
+	}
+	kernelApp := tcg.Event{Index: 4, Type: tcg.EFIBootServicesApplication, Digest: []byte("kernel")}
+	for _, tc := range []struct {
+		name        string
+		events      []tcg.Event
+		wantCmdline string
+		wantErr     bool
+	}{
+		{"PCR 8", []tcg.Event{kernelApp, cmdline(8, "root=/dev/sda1", true)}, "root=/dev/sda1", false},
+		{"PCR 12", []tcg.Event{kernelApp, cmdline(12, "root=/dev/sda1", true)}, "root=/dev/sda1", false},
+		{"measured again by the stub", []tcg.Event{cmdline(8, "ro", true), kernelApp, cmdline(12, "ro", true)}, "ro", false},
+		{"unmeasured command line", []tcg.Event{kernelApp, cmdline(8, "init=/bin/sh", false)}, "", false},
+		{"different command lines", []tcg.Event{cmdline(8, "ro", true), cmdline(12, "rw", true)}, "", true},
+		{"no events", []tcg.Event{cmdline(8, "ro", false)}, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kernel, err := LinuxKernelStateFromSystemdBoot(crypto.SHA1, numberedEvents(t, tc.events), TPMRegisterConfig)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LinuxKernelStateFromSystemdBoot(): got error %v, want error: %v", err, tc.wantErr)
+			}
+			if got := kernel.GetCommandLine(); got != tc.wantCmdline {
+				t.Errorf("LinuxKernelStateFromSystemdBoot(): got command line %q, want %q", got, tc.wantCmdline)
+			}
+		})
+	}
+}
+
+func TestBootedKernelDigests(t *testing.T) {
+	hash := crypto.SHA1
+	digest := func(contents string) []byte {
+		h := hash.New()
+		h.Write([]byte(contents))
+		return h.Sum(nil)
+	}
+	kernel, initrd, other := digest("kernel"), digest("initrd"), digest("other")
+	file := func(name string, digest []byte) tcg.Event {
+		return tcg.Event{Index: 9, Type: tcg.Ipl, Data: []byte(name), Digest: digest}
+	}
+	linux := makeGrubEvent(hash, 8, "grub_cmd: ", "linux /boot/vmlinuz-2 ro")
+	linuxefi := makeGrubEvent(hash, 8, "grub_cmd ", "linuxefi /boot/vmlinuz-2 ro")
+	cmdline := makeGrubEvent(hash, 8, "kernel_cmdline: ", "/boot/vmlinuz-2 ro")
+	oldCmdline := makeGrubEvent(hash, 8, "grub_kernel_cmdline ", "/boot/vmlinuz-2 ro")
+	initrdCmd := makeGrubEvent(hash, 8, "grub_cmd: ", "initrd /boot/initrd-2")
+	unmeasured := makeGrubEvent(hash, 8, "grub_cmd: ", "linux /boot/vmlinuz-2 ro")
+	unmeasured.Digest = other
+	ebs := tcg.Event{Index: 5, Type: tcg.EFIAction, Data: []byte(tcg.ExitBootServicesInvocation), Digest: tcg.EFIActionDigest(hash, tcg.ExitBootServicesInvocation)}
+	app := func(digest []byte) tcg.Event {
+		return tcg.Event{Index: 4, Type: tcg.EFIBootServicesApplication, Digest: digest}
+	}
+	linuxSection := func(contents []byte) []tcg.Event {
+		description := encodeUTF16(".linux\x00")
+		return []tcg.Event{
+			{Index: 11, Type: tcg.Ipl, Data: description, Digest: digest(".linux\x00")},
+			{Index: 11, Type: tcg.Ipl, Data: description, Digest: contents},
+		}
+	}
+	events := func(events ...[]tcg.Event) []tcg.Event {
+		var all []tcg.Event
+		for _, e := range events {
+			all = append(all, e...)
+		}
+		return all
+	}
+
+	for _, tc := range []struct {
+		name   string
+		events []tcg.Event
+		loader Bootloader
+		want   [][]byte
+	}{
+		{"GRUB", []tcg.Event{file("/boot/vmlinuz-1", other), linux, file("/boot/vmlinuz-2", kernel), cmdline, initrdCmd, file("/boot/initrd-2", initrd), ebs}, GRUB, [][]byte{kernel}},
+		{"GRUB file names ignored", []tcg.Event{linux, file("/boot/grub/grub.cfg", kernel), cmdline, file("/boot/vmlinuz-2", other), ebs}, GRUB, [][]byte{kernel}},
+		{"GRUB linuxefi", []tcg.Event{linuxefi, file("grub_linuxefi Kernel", kernel), app(other), oldCmdline, ebs}, GRUB, [][]byte{kernel}},
+		{"GRUB more than one file", []tcg.Event{linux, file("/boot/vmlinuz-2", kernel), file("/boot/vmlinuz-2", other), cmdline, ebs}, GRUB, nil},
+		{"GRUB command line without linux", []tcg.Event{initrdCmd, file("/boot/vmlinuz-2", kernel), cmdline, ebs}, GRUB, nil},
+		{"GRUB unmeasured command", []tcg.Event{unmeasured, file("/boot/vmlinuz-2", kernel), cmdline, ebs}, GRUB, nil},
+		{"GRUB kernel after ExitBootServices", []tcg.Event{ebs, linux, file("/boot/vmlinuz-2", kernel), cmdline}, GRUB, nil},
+		{"GRUB without ExitBootServices", []tcg.Event{linux, file("/boot/vmlinuz-2", kernel), cmdline}, GRUB, nil},
+		{"GRUB ignores UKI sections", events([]tcg.Event{linux, file("/boot/vmlinuz-2", other), cmdline}, linuxSection(kernel), []tcg.Event{ebs}), GRUB, [][]byte{other}},
+		{"systemd-boot", []tcg.Event{app(other), app(kernel), ebs}, SystemdBoot, [][]byte{kernel}},
+		{"systemd-boot UKI", events([]tcg.Event{app(other), app(initrd)}, linuxSection(kernel), []tcg.Event{ebs}), SystemdBoot, [][]byte{initrd, kernel}},
+		{"UKI started by the firmware", events([]tcg.Event{app(other)}, linuxSection(kernel), []tcg.Event{ebs}), UnsupportedLoader, [][]byte{kernel}},
+		{"UKI section before the booted app", events(linuxSection(kernel), []tcg.Event{app(other), ebs}), UnsupportedLoader, nil},
+		{"UKI section after ExitBootServices", events([]tcg.Event{app(other), ebs}, linuxSection(kernel)), UnsupportedLoader, nil},
+		{"Windows Boot Manager", events([]tcg.Event{app(other)}, linuxSection(kernel), []tcg.Event{ebs}), WindowsBootManager, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := BootedKernelDigests(hash, numberedEvents(t, tc.events), TPMRegisterConfig, tc.loader)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("BootedKernelDigests() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	state, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB})
+	if err != nil {
+		t.Fatalf("FirmwareLogState(): %v", err)
+	}
+	state.Findings = append(state.Findings,
+		&pb.Finding{Type: pb.FindingType_FINDING_TYPE_REPEATED_EVENTS},
+		&pb.Finding{Type: pb.FindingType_FINDING_TYPE_WEAK_BANK},
+		&pb.Finding{Type: pb.FindingType_FINDING_TYPE_REPEATED_EVENTS})
+	state.AppliedQuirks = []string{"padding", "bad_separator"}
+
+	summary := Summarize(state)
+	if summary.GetSecureBootEnabled() != state.GetSecureBoot().GetEnabled() || !summary.GetGrub() || summary.GetUki() || summary.GetWindows() {
+		t.Errorf("Summarize() = %v, want the Secure Boot state and only a GRUB state", summary)
+	}
+	if got, want := summary.GetTotalEvents(), uint32(len(events)); got != want {
+		t.Errorf("Summarize(): got %d total events, want %d", got, want)
+	}
+	if got, want := len(summary.GetEfiAppDigests()), len(state.GetEfi().GetApps()); got != want || got == 0 {
+		t.Errorf("Summarize(): got %d EFI app digests, want %d", got, want)
+	}
+	if got, want := summary.GetDbxHashes(), uint32(len(state.GetSecureBoot().GetDbx().GetHashes())); got != want {
+		t.Errorf("Summarize(): got %d dbx hashes, want %d", got, want)
+	}
+	wantFindings := []pb.FindingType{pb.FindingType_FINDING_TYPE_WEAK_BANK, pb.FindingType_FINDING_TYPE_REPEATED_EVENTS}
+	if !reflect.DeepEqual(summary.GetFindingTypes(), wantFindings) {
+		t.Errorf("Summarize(): got finding types %v, want %v", summary.GetFindingTypes(), wantFindings)
+	}
+	if want := []string{"bad_separator", "padding"}; !reflect.DeepEqual(summary.GetAppliedQuirks(), want) {
+		t.Errorf("Summarize(): got applied quirks %v, want %v", summary.GetAppliedQuirks(), want)
+	}
+
+	marshaled, err := proto.MarshalOptions{Deterministic: true}.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := proto.MarshalOptions{Deterministic: true}.Marshal(Summarize(state))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(marshaled, again) {
+		t.Errorf("Summarize() is not deterministic")
+	}
+	cmdline := strings.TrimRight(state.GetLinuxKernel().GetCommandLine(), "\x00")
+	if cmdline == "" || bytes.Contains(marshaled, []byte(cmdline)) {
+		t.Errorf("Summarize(): got the kernel command line %q in the summary", cmdline)
+	}
+}
+
+func TestFirmwareLogStateStrict(t *testing.T) {
+	hash, events := getTPMELEvents(t)
+	if _, err := FirmwareLogState(events, hash, TPMRegisterConfig, Opts{Loader: GRUB, Strict: true}); err != nil {
+		t.Fatalf("FirmwareLogState(Strict): %v", err)
+	}
+
+	ebsPos := exitBootServicesPosition(hash, events, TPMRegisterConfig)
+	if ebsPos == -1 {
+		t.Fatal("no ExitBootServices event")
+	}
+	noEBS := events[:ebsPos]
+	var noLoader []tcg.Event
+	for _, event := range events {
+		if index := event.MRIndex(); index != 8 && index != 9 {
+			noLoader = append(noLoader, event)
+		}
+	}
+	for _, tc := range []struct {
+		name        string
+		events      []tcg.Event
+		loader      Bootloader
+		wantSection string
+	}{
+		{"no ExitBootServices", noEBS, GRUB, "efi"},
+		{"no bootloader detected", noLoader, AutoDetect, "linux_kernel"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := FirmwareLogState(tc.events, hash, TPMRegisterConfig, Opts{Loader: tc.loader}); err != nil {
+				t.Fatalf("FirmwareLogState(): %v", err)
+			}
+			state, err := FirmwareLogState(tc.events, hash, TPMRegisterConfig, Opts{Loader: tc.loader, Strict: true})
+			var missing MissingSectionError
+			if !errors.As(err, &missing) || missing.Section != tc.wantSection {
+				t.Fatalf("FirmwareLogState(Strict): got %v, want MissingSectionError for %q", err, tc.wantSection)
+			}
+			if state == nil {
+				t.Errorf("FirmwareLogState(Strict): got no partial state")
+			}
+		})
+	}
+}
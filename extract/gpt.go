@@ -0,0 +1,60 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"fmt"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// GptState extracts the partition table of the boot disk from the
+// EV_EFI_GPT_EVENT in registerCfg.ExitBootServicesIdx, i.e., PCR 5. It returns
+// nil if the partition table was not measured, e.g., when booting from a
+// network or a disk without a GUID partition table.
+//
+// The event digest must match the event data, and only one partition table
+// may be measured, as the boot disk would otherwise be ambiguous.
+func GptState(events []tcg.Event, registerCfg RegisterConfig) (*pb.GptState, error) {
+	var state *pb.GptState
+	for _, event := range events {
+		if event.MRIndex() != registerCfg.ExitBootServicesIdx || event.UntrustedType() != tcg.EFIGPTEvent {
+			continue
+		}
+		if err := DigestEquals(event, event.RawData()); err != nil {
+			return nil, fmt.Errorf("event %d: invalid GPT event digest: %v", event.Num(), err)
+		}
+		if state != nil {
+			return nil, fmt.Errorf("event %d: found duplicate GPT event in %s%d", event.Num(), registerCfg.Name, registerCfg.ExitBootServicesIdx)
+		}
+		gpt, err := tcg.ParseUEFIGPTData(event.RawData())
+		if err != nil {
+			return nil, fmt.Errorf("event %d: failed parsing GPT event: %v", event.Num(), err)
+		}
+		state = &pb.GptState{DiskGuid: gpt.DiskGUID}
+		for _, partition := range gpt.Partitions {
+			state.Partitions = append(state.Partitions, &pb.GptPartition{
+				TypeGuid:      partition.TypeGUID,
+				PartitionGuid: partition.PartitionGUID,
+				StartingLba:   partition.StartingLBA,
+				EndingLba:     partition.EndingLBA,
+				Attributes:    partition.Attributes,
+				Name:          partition.Name,
+			})
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"strings"
+	"unicode"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+// NormalizeGRUBCommand removes cosmetic differences from a measured GRUB
+// command, so equivalent commands compare equal across machines:
+//   - trailing NUL terminators are removed,
+//   - runs of unquoted whitespace, such as those left by variables that
+//     expanded to nothing, are collapsed into a single space,
+//   - empty quoted words left by such expansions, e.g., "", are dropped,
+//   - leading and trailing whitespace after the measurement prefix is removed.
+//
+// The measurement prefix (e.g., "grub_cmd: ") and quoted text are kept as is.
+// Normalized commands are for matching only; policy that depends on the exact
+// command must use the measured command.
+func NormalizeGRUBCommand(command string) string {
+	command = strings.TrimRight(command, "\x00")
+	var prefix string
+	for _, p := range validPrefixes {
+		if strings.HasPrefix(command, string(p)) {
+			prefix = string(p)
+			break
+		}
+	}
+	var words []string
+	for _, word := range grubWords(command[len(prefix):]) {
+		if word == `""` || word == `''` {
+			continue
+		}
+		words = append(words, word)
+	}
+	return prefix + strings.Join(words, " ")
+}
+
+// grubWords splits a GRUB command into words on unquoted whitespace, following
+// GRUB's quoting rules. Quotes and escapes are kept in the words.
+func grubWords(command string) []string {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range command {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		}
+		word.WriteRune(r)
+		inWord = true
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+func normalizeGRUBCommands(commands []string) []string {
+	normalized := make([]string, 0, len(commands))
+	for _, command := range commands {
+		normalized = append(normalized, NormalizeGRUBCommand(command))
+	}
+	return normalized
+}
+
+// singleBankDigests returns the digests of a measurement verified in one bank.
+func singleBankDigests(hash pb.HashAlgo, digest []byte) *pb.BankDigests {
+	return &pb.BankDigests{Digests: []*pb.BankDigest{{Hash: hash, Digest: digest}}}
+}
+
+// withoutBankDigests removes the hash annotation and bank digests added in
+// schema version 15 from a GrubState.
+func withoutBankDigests(grub *pb.GrubState) {
+	for _, file := range grub.GetFiles() {
+		file.Hash = pb.HashAlgo_HASH_INVALID
+		file.BankDigests = nil
+	}
+	if grub != nil {
+		grub.CommandDigests = nil
+	}
+}
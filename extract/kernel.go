@@ -0,0 +1,130 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"crypto"
+	"strings"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// BootedKernelDigests returns the digests of the kernel image that loader
+// booted, using only the events measured before ExitBootServices(). The OS
+// can extend the loader registers after ExitBootServices(), so the digests
+// are nil if the event log has no ExitBootServices invocation.
+//
+// For GRUB, the kernel is the one file GRUB measured between the linux (or
+// linuxefi) command and the kernel command line. File names are not
+// measured, so they are not used. For systemd-boot, the kernel is the last
+// EFI application the firmware started. For systemd-boot and for a unified
+// kernel image (UKI) started by the firmware, the digests also include the
+// .linux sections systemd-stub measured after the last EFI application
+// started. Other loaders boot no known kernel.
+//
+// The events must be verified.
+func BootedKernelDigests(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig, loader Bootloader) [][]byte {
+	ebsPos := exitBootServicesPosition(hash, events, registerCfg)
+	if ebsPos == -1 {
+		return nil
+	}
+	events = events[:ebsPos]
+	switch loader {
+	case GRUB:
+		if digest := grubKernelDigest(hash, events, registerCfg); digest != nil {
+			return [][]byte{digest}
+		}
+		return nil
+	case SystemdBoot, UnsupportedLoader:
+		lastApp := -1
+		for i, event := range events {
+			if event.MRIndex() == registerCfg.EFIAppIdx && event.UntrustedType() == tcg.EFIBootServicesApplication {
+				lastApp = i
+			}
+		}
+		if lastApp == -1 {
+			return nil
+		}
+		var digests [][]byte
+		if loader == SystemdBoot {
+			digests = append(digests, events[lastApp].ReplayedDigest())
+		}
+		if registerCfg.LogType != pb.LogType_LOG_TYPE_TCG2 {
+			return digests
+		}
+		// Only the booted UKI measures its sections between its start and
+		// ExitBootServices().
+		uki, err := UkiState(hash, events[lastApp+1:])
+		if err != nil {
+			return digests
+		}
+		for _, section := range uki.GetSections() {
+			if section.GetName() == ".linux" {
+				digests = append(digests, section.GetDigest())
+			}
+		}
+		return digests
+	}
+	return nil
+}
+
+// grubKernelDigest returns the digest of the kernel file GRUB booted, or nil
+// if GRUB did not measure exactly one kernel command line, with exactly one
+// file between it and the linux command before it.
+func grubKernelDigest(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig) []byte {
+	var (
+		kernels [][]byte
+		files   [][]byte
+		inLinux bool
+	)
+	for _, event := range events {
+		if event.UntrustedType() != tcg.Ipl {
+			continue
+		}
+		switch event.MRIndex() {
+		case registerCfg.GRUBFileIdx:
+			files = append(files, event.ReplayedDigest())
+		case registerCfg.GRUBCmdIdx:
+			if verifyGrubCommand(hash, event) != nil {
+				return nil
+			}
+			if getGrubKernelCmdlineSuffix(event.RawData()) != -1 {
+				if !inLinux || len(files) != 1 {
+					return nil
+				}
+				kernels = append(kernels, files[0])
+			}
+			inLinux = isGrubLinuxCommand(string(event.RawData()))
+			files = nil
+		}
+	}
+	if len(kernels) != 1 {
+		return nil
+	}
+	return kernels[0]
+}
+
+// isGrubLinuxCommand reports whether a measured GRUB command loads a Linux
+// kernel.
+func isGrubLinuxCommand(command string) bool {
+	for _, prefix := range []string{"grub_cmd: ", "grub_cmd "} {
+		if rest, ok := strings.CutPrefix(command, prefix); ok {
+			words := grubWords(strings.TrimRight(rest, "\x00"))
+			return len(words) > 0 && (words[0] == "linux" || words[0] == "linuxefi")
+		}
+	}
+	return false
+}
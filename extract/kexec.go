@@ -0,0 +1,90 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// splitKexecGenerations splits events into the ones belonging to the first
+// booted kernel and the loader events of each kernel generation loaded via
+// kexec.
+//
+// The firmware bootloader runs before ExitBootServices(), so any EV_IPL event
+// in the GRUB registers after ExitBootServices() was measured by a loader
+// running on top of an already-booted kernel. Each kexec generation starts at
+// the first such EV_IPL event and ends at its kernel command line.
+//
+// If no verified ExitBootServices event is found, all events are returned
+// as part of the first generation.
+func splitKexecGenerations(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig) ([]tcg.Event, [][]tcg.Event) {
+	ebsPos := exitBootServicesPosition(hash, events, registerCfg)
+	var (
+		first       []tcg.Event
+		generations [][]tcg.Event
+		current     []tcg.Event
+	)
+	for i, event := range events {
+		index := event.MRIndex()
+		isLoaderIdx := index == registerCfg.GRUBCmdIdx || index == registerCfg.GRUBFileIdx
+		if ebsPos == -1 || i <= ebsPos || !isLoaderIdx {
+			first = append(first, event)
+			continue
+		}
+		// Only an EV_IPL event can start a new generation.
+		if len(current) == 0 && event.UntrustedType() != tcg.Ipl {
+			first = append(first, event)
+			continue
+		}
+		current = append(current, event)
+		if index == registerCfg.GRUBCmdIdx && event.UntrustedType() == tcg.Ipl &&
+			getGrubKernelCmdlineSuffix(event.RawData()) != -1 {
+			generations = append(generations, current)
+			current = nil
+		}
+	}
+	if len(current) != 0 {
+		generations = append(generations, current)
+	}
+	return first, generations
+}
+
+// KexecStates extracts the loader and kernel state of every kernel generation
+// loaded via kexec after the first kernel.
+// It returns an empty slice if the event log does not contain loader
+// measurements after ExitBootServices().
+func KexecStates(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig) ([]*pb.KexecState, error) {
+	_, generations := splitKexecGenerations(hash, events, registerCfg)
+	var joined error
+	kexecStates := make([]*pb.KexecState, 0, len(generations))
+	for i, generation := range generations {
+		grub, err := registerCfg.GRUBExtracter(hash, generation)
+		if err != nil {
+			joined = errors.Join(joined, fmt.Errorf("kexec generation %d: %w", i+1, err))
+			continue
+		}
+		kernel, err := LinuxKernelStateFromGRUB(grub)
+		if err != nil {
+			joined = errors.Join(joined, fmt.Errorf("kexec generation %d: %w", i+1, err))
+		}
+		kexecStates = append(kexecStates, &pb.KexecState{Grub: grub, LinuxKernel: kernel})
+	}
+	return kexecStates, joined
+}
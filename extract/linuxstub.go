@@ -0,0 +1,128 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// LinuxStubState extracts the kernel load options and initrd digests measured
+// by the Linux EFI stub as EV_EVENT_TAG events in the GRUB file register. It
+// returns nil if the stub measured neither.
+//
+// If rejectUnknownTags is set, EV_EVENT_TAG events in the GRUB registers that
+// are not Linux EFI stub measurements are an error. Otherwise they are
+// ignored, as in GRUB extraction.
+func LinuxStubState(events []tcg.Event, registerCfg RegisterConfig, rejectUnknownTags bool) (*pb.LinuxKernelState, error) {
+	var kernel pb.LinuxKernelState
+	found := false
+	for _, event := range events {
+		index := event.MRIndex()
+		if event.UntrustedType() != tcg.EventTag || (index != registerCfg.GRUBCmdIdx && index != registerCfg.GRUBFileIdx) {
+			continue
+		}
+		id, ok := tcg.ParseLinuxEventTag(event.RawData())
+		if !ok || index != registerCfg.GRUBFileIdx {
+			if rejectUnknownTags {
+				return nil, fmt.Errorf("found unknown EV_EVENT_TAG event %d in %s%d", event.Num(), registerCfg.Name, index)
+			}
+			continue
+		}
+		var field *[]byte
+		switch id {
+		case tcg.LinuxLoadOptionsEventTag:
+			field = &kernel.LoadOptionsDigest
+		case tcg.LinuxInitrdEventTag:
+			field = &kernel.InitrdDigest
+		}
+		if *field != nil {
+			return nil, fmt.Errorf("found more than one Linux EFI stub event with tag %#x", id)
+		}
+		*field = event.ReplayedDigest()
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return &kernel, nil
+}
+
+// grubBootImagePrefix is prepended by GRUB to the command line it passes to
+// the kernel in the LoadOptions, but not to the command line it measures.
+const grubBootImagePrefix = "BOOT_IMAGE="
+
+// LoadOptionsMatch reports whether the load options digest measured by the
+// Linux EFI stub is the digest of the kernel command line measured by the
+// loader. The stub measures the UTF-16 LoadOptions it was started with, which
+// GRUB prefixes with the BOOT_IMAGE parameter, and other loaders, e.g.,
+// systemd-boot, pass as is, with or without a NUL terminator.
+func LoadOptionsMatch(hash crypto.Hash, commandLine string, loadOptionsDigest []byte) bool {
+	if commandLine == "" || len(loadOptionsDigest) == 0 {
+		return false
+	}
+	// The GRUB command line includes its NUL terminator.
+	commandLine = strings.TrimRight(commandLine, "\x00")
+	for _, options := range []string{grubBootImagePrefix + commandLine, commandLine} {
+		encoded := encodeUTF16(options)
+		for _, candidate := range [][]byte{encoded, append(encoded, 0, 0)} {
+			hasher := hash.New()
+			hasher.Write(candidate)
+			if subtle.ConstantTimeCompare(hasher.Sum(nil), loadOptionsDigest) == 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodeUTF16 decodes a NUL-terminated UTF-16LE string. A trailing odd byte
+// is ignored.
+func decodeUTF16(data []byte) string {
+	u16 := make([]uint16, len(data)/2)
+	for i := range u16 {
+		u16[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	return strings.TrimRight(string(utf16.Decode(u16)), "\x00")
+}
+
+// encodeUTF16 encodes s as UTF-16LE, without a NUL terminator.
+func encodeUTF16(s string) []byte {
+	var out []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		out = binary.LittleEndian.AppendUint16(out, u)
+	}
+	return out
+}
+
+// loadOptionsFindings reports a load options mismatch finding if the kernel
+// command line measured by the loader does not match the load options
+// measured by the Linux EFI stub.
+func loadOptionsFindings(kernel *pb.LinuxKernelState) []*pb.Finding {
+	if kernel.GetCommandLine() == "" || len(kernel.GetLoadOptionsDigest()) == 0 || kernel.GetLoadOptionsMatch() {
+		return nil
+	}
+	return []*pb.Finding{{
+		Type:        pb.FindingType_FINDING_TYPE_LOAD_OPTIONS_MISMATCH,
+		Description: fmt.Sprintf("Linux EFI stub load options digest %x does not match the kernel command line %q", kernel.GetLoadOptionsDigest(), strings.TrimRight(kernel.GetCommandLine(), "\x00")),
+	}}
+}
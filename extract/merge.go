@@ -0,0 +1,312 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"google.golang.org/protobuf/proto"
+)
+
+// BankMismatchError is returned by MergeFirmwareLogStates when the
+// FirmwareLogState extracted using one hash bank disagrees with the
+// authoritative FirmwareLogState on digest-independent contents.
+type BankMismatchError struct {
+	// Authoritative is the hash algorithm of the state returned by the merge.
+	Authoritative pb.HashAlgo
+	// Other is the hash algorithm of the disagreeing state.
+	Other pb.HashAlgo
+	// Fields are the FirmwareLogState fields that differ.
+	Fields []string
+}
+
+// Error returns the mismatched fields between the two hash banks.
+func (e BankMismatchError) Error() string {
+	return fmt.Sprintf("FirmwareLogState mismatch between %v and %v banks in fields: %s",
+		e.Authoritative, e.Other, strings.Join(e.Fields, ", "))
+}
+
+// MergeFirmwareLogStates reconciles FirmwareLogStates extracted from the same
+// event log using different verified hash banks (e.g., SHA1 and SHA256).
+//
+// The state with the strongest hash algorithm is returned as the authoritative
+// FirmwareLogState. Every other state is compared with it, ignoring digests
+// that depend on the hash algorithm. Any disagreement is reported with a
+// BankMismatchError, joined with `errors.Join`. The authoritative state is
+// returned even if there are mismatches, with the GRUB file and command
+// digests of the other banks added to GrubFile.bank_digests and
+// GrubState.command_digests.
+func MergeFirmwareLogStates(states ...*pb.FirmwareLogState) (*pb.FirmwareLogState, error) {
+	var authoritative *pb.FirmwareLogState
+	for _, state := range states {
+		if state == nil {
+			return nil, errors.New("cannot merge nil FirmwareLogState")
+		}
+		if authoritative == nil || state.GetHash().Strength() > authoritative.GetHash().Strength() {
+			authoritative = state
+		}
+	}
+	if authoritative == nil {
+		return nil, errors.New("no FirmwareLogState to merge")
+	}
+
+	var joined error
+	merged := proto.Clone(authoritative).(*pb.FirmwareLogState)
+	for _, state := range states {
+		if state == authoritative {
+			continue
+		}
+		if state.GetHash() == authoritative.GetHash() {
+			joined = errors.Join(joined, fmt.Errorf("cannot merge two FirmwareLogStates of the same hash %v", state.GetHash()))
+			continue
+		}
+		if fields := mismatchedFields(authoritative, state); len(fields) != 0 {
+			joined = errors.Join(joined, BankMismatchError{
+				Authoritative: authoritative.GetHash(),
+				Other:         state.GetHash(),
+				Fields:        fields,
+			})
+		}
+		addGrubBankDigests(merged.GetGrub(), state.GetGrub())
+		for i, kexec := range merged.GetKexec() {
+			if i < len(state.GetKexec()) {
+				addGrubBankDigests(kexec.GetGrub(), state.GetKexec()[i].GetGrub())
+			}
+		}
+	}
+	return merged, joined
+}
+
+// addGrubBankDigests adds the GRUB file and command digests of another bank
+// to dst, if the GRUB states match.
+func addGrubBankDigests(dst, other *pb.GrubState) {
+	if dst == nil || !grubStatesMatch(dst, other) || len(dst.GetCommandDigests()) != len(other.GetCommandDigests()) {
+		return
+	}
+	for i, file := range dst.GetFiles() {
+		file.BankDigests = append(file.BankDigests, other.GetFiles()[i].GetBankDigests()...)
+	}
+	for i, digests := range dst.GetCommandDigests() {
+		digests.Digests = append(digests.Digests, other.GetCommandDigests()[i].GetDigests()...)
+	}
+}
+
+// mismatchedFields returns the names of the FirmwareLogState fields whose
+// digest-independent contents differ between a and b.
+func mismatchedFields(a, b *pb.FirmwareLogState) []string {
+	var fields []string
+	if a.GetSchemaVersion() != b.GetSchemaVersion() {
+		fields = append(fields, "schema_version")
+	}
+	if a.GetLogType() != b.GetLogType() {
+		fields = append(fields, "log_type")
+	}
+	if !proto.Equal(a.GetPlatform(), b.GetPlatform()) {
+		fields = append(fields, "platform")
+	}
+	if !secureBootStatesMatch(a.GetSecureBoot(), b.GetSecureBoot()) {
+		fields = append(fields, "secure_boot")
+	}
+	if !grubStatesMatch(a.GetGrub(), b.GetGrub()) {
+		fields = append(fields, "grub")
+	}
+	if !linuxKernelStatesMatch(a.GetLinuxKernel(), b.GetLinuxKernel()) {
+		fields = append(fields, "linux_kernel")
+	}
+	if !efiStatesMatch(a.GetEfi(), b.GetEfi()) {
+		fields = append(fields, "efi")
+	}
+	if !kexecStatesMatch(a.GetKexec(), b.GetKexec()) {
+		fields = append(fields, "kexec")
+	}
+	if !eventsMatch(a.GetRawEvents(), b.GetRawEvents()) {
+		fields = append(fields, "raw_events")
+	}
+	if !proto.Equal(a.GetWindowsSipa(), b.GetWindowsSipa()) {
+		fields = append(fields, "windows_sipa")
+	}
+	if !ukiStatesMatch(a.GetUki(), b.GetUki()) {
+		fields = append(fields, "uki")
+	}
+	if !bootConfigStatesMatch(a.GetBootConfig(), b.GetBootConfig()) {
+		fields = append(fields, "boot_config")
+	}
+	if !proto.Equal(a.GetGpt(), b.GetGpt()) {
+		fields = append(fields, "gpt")
+	}
+	return fields
+}
+
+func secureBootStatesMatch(a, b *pb.SecureBootState) bool {
+	// Authority uses record the digest of the authorized image, and the MOK
+	// state records the digests of the MOK variables.
+	withoutDigests := func(state *pb.SecureBootState) *pb.SecureBootState {
+		state = proto.Clone(state).(*pb.SecureBootState)
+		for _, usage := range state.GetAuthorityUsages() {
+			for _, use := range usage.GetUses() {
+				use.ImageDigest = nil
+			}
+		}
+		if mok := state.GetMok(); mok != nil {
+			mok.MokListDigest = nil
+			mok.MokListXDigest = nil
+			for _, m := range mok.GetMeasurements() {
+				m.Digest = nil
+			}
+		}
+		return state
+	}
+	return proto.Equal(withoutDigests(a), withoutDigests(b))
+}
+
+// bootConfigStatesMatch compares the boot configurations, except for the
+// per-bank platform configuration digests.
+func bootConfigStatesMatch(a, b *pb.BootConfigState) bool {
+	withoutDigests := func(state *pb.BootConfigState) *pb.BootConfigState {
+		state = proto.Clone(state).(*pb.BootConfigState)
+		for _, config := range state.GetPlatformConfigs() {
+			config.Digest = nil
+		}
+		return state
+	}
+	return proto.Equal(withoutDigests(a), withoutDigests(b))
+}
+
+func grubStatesMatch(a, b *pb.GrubState) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	if len(a.GetFiles()) != len(b.GetFiles()) || len(a.GetCommands()) != len(b.GetCommands()) {
+		return false
+	}
+	for i, file := range a.GetFiles() {
+		if !bytes.Equal(file.GetUntrustedFilename(), b.GetFiles()[i].GetUntrustedFilename()) {
+			return false
+		}
+	}
+	for i, command := range a.GetCommands() {
+		if command != b.GetCommands()[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// linuxKernelStatesMatch compares the kernel states, except for the per-bank
+// Linux EFI stub digests, which only need to be present in both.
+func linuxKernelStatesMatch(a, b *pb.LinuxKernelState) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return a.GetCommandLine() == b.GetCommandLine() &&
+		a.GetLoadOptionsMatch() == b.GetLoadOptionsMatch() &&
+		(a.GetLoadOptionsDigest() == nil) == (b.GetLoadOptionsDigest() == nil) &&
+		(a.GetInitrdDigest() == nil) == (b.GetInitrdDigest() == nil) &&
+		(a.GetKernelDigest() == nil) == (b.GetKernelDigest() == nil)
+}
+
+func ukiStatesMatch(a, b *pb.UkiState) bool {
+	if (a == nil) != (b == nil) || len(a.GetSections()) != len(b.GetSections()) || len(a.GetPhases()) != len(b.GetPhases()) {
+		return false
+	}
+	for i, section := range a.GetSections() {
+		if section.GetName() != b.GetSections()[i].GetName() {
+			return false
+		}
+	}
+	for i, phase := range a.GetPhases() {
+		if phase != b.GetPhases()[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func efiStatesMatch(a, b *pb.EfiState) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return appsMatch(a.GetApps(), b.GetApps()) &&
+		appsMatch(a.GetBootServicesDrivers(), b.GetBootServicesDrivers()) &&
+		appsMatch(a.GetRuntimeServicesDrivers(), b.GetRuntimeServicesDrivers()) &&
+		eventsMatch(a.GetUntrustedPostEbsEvents(), b.GetUntrustedPostEbsEvents()) &&
+		a.GetUntrustedExitBootServicesResult() == b.GetUntrustedExitBootServicesResult() &&
+		driversMatch(a.GetDrivers(), b.GetDrivers())
+}
+
+// appsMatch compares the EFI apps or boot and runtime services drivers,
+// except for their per-bank digests.
+func appsMatch(a, b []*pb.EfiApp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x := proto.Clone(a[i]).(*pb.EfiApp)
+		y := proto.Clone(b[i]).(*pb.EfiApp)
+		x.Digest, y.Digest = nil, nil
+		if !proto.Equal(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// driversMatch compares the drivers, except for their per-bank digests.
+func driversMatch(a, b []*pb.EfiDriver) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x := proto.Clone(a[i]).(*pb.EfiDriver)
+		y := proto.Clone(b[i]).(*pb.EfiDriver)
+		x.Digest, y.Digest = nil, nil
+		if !proto.Equal(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+func kexecStatesMatch(a, b []*pb.KexecState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !grubStatesMatch(a[i].GetGrub(), b[i].GetGrub()) ||
+			!linuxKernelStatesMatch(a[i].GetLinuxKernel(), b[i].GetLinuxKernel()) {
+			return false
+		}
+	}
+	return true
+}
+
+func eventsMatch(a, b []*pb.Event) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].GetPcrIndex() != b[i].GetPcrIndex() ||
+			a[i].GetUntrustedType() != b[i].GetUntrustedType() ||
+			!bytes.Equal(a[i].GetData(), b[i].GetData()) ||
+			a[i].GetDigestVerified() != b[i].GetDigestVerified() {
+			return false
+		}
+	}
+	return true
+}
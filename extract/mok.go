@@ -0,0 +1,99 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"crypto"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// mokPCR is the PCR that shim measures the Machine Owner Key (MOK) variables
+// into.
+const mokPCR = 14
+
+// shimSbatLevelVar is the variable shim measures its SBAT level from.
+const shimSbatLevelVar = "SbatLevel"
+
+// MokState extracts the Machine Owner Key (MOK) state of a shim boot from a
+// TPM event log. It returns nil if there are no MOK or SBAT measurements.
+//
+// shim measures the contents of MokList, MokListX, and MokListTrusted into
+// PCR 14 as EV_IPL events described by the NUL-terminated variable name. Some
+// shims and bootloaders measure into PCR 14 with EV_COMPACT_HASH events
+// instead, whose data is also a descriptor string. The measured contents are
+// not in the event log, so their digests are extracted for comparison with
+// the expected MOK variables. Descriptors that are not printable strings are
+// extracted as empty.
+//
+// shim also measures its SbatLevel variable as an authority event into the
+// Secure Boot register, which is extracted if its digest matches. If sbState
+// is given, the MOKs it records as used to authorize images are referenced
+// by their index in sbState.authority.
+func MokState(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig, sbState *pb.SecureBootState) *pb.MokState {
+	return mokState(hash, events, registerCfg, sbState, SchemaVersion)
+}
+
+func mokState(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig, sbState *pb.SecureBootState, compatLevel uint32) *pb.MokState {
+	var state pb.MokState
+	for _, event := range events {
+		switch {
+		case event.MRIndex() == mokPCR && (event.UntrustedType() == tcg.Ipl || event.UntrustedType() == tcg.CompactHash):
+			m := &pb.MokMeasurement{
+				EventNum:      event.Num(),
+				UntrustedType: uint32(event.UntrustedType()),
+				Description:   printableDescription(event.RawData()),
+				Digest:        event.ReplayedDigest(),
+			}
+			state.Measurements = append(state.Measurements, m)
+			switch m.Description {
+			case "MokList":
+				state.MokListDigest = m.Digest
+			case "MokListX":
+				state.MokListXDigest = m.Digest
+			case "MokListTrusted":
+				// shim sets MokListTrusted to 1 to enable it.
+				state.MokListTrusted = digestMatches(hash, "\x01", m.Digest)
+			}
+		case event.MRIndex() == registerCfg.SecureBootIdx && event.UntrustedType() == tcg.EFIVariableAuthority:
+			v, err := tcg.ParseVariableEvent(event)
+			if err != nil || v.VarName() != shimSbatLevelVar || checkVariableVendor(v, compatLevel) != nil || DigestEquals(event, event.RawData()) != nil {
+				continue
+			}
+			state.SbatLevel = string(v.VariableData)
+		}
+	}
+	state.AuthorityCertIndexes = mokAuthorityCertIndexes(sbState)
+	if len(state.Measurements) == 0 && state.SbatLevel == "" {
+		return nil
+	}
+	return &state
+}
+
+// mokAuthorityCertIndexes returns the indexes of the authority certificates
+// used from MokList or MokListRT.
+func mokAuthorityCertIndexes(sbState *pb.SecureBootState) []uint32 {
+	var indexes []uint32
+	for _, usage := range sbState.GetAuthorityUsages() {
+		for _, use := range usage.GetUses() {
+			if name := use.GetVariableName(); name == "MokList" || name == "MokListRT" {
+				indexes = append(indexes, usage.GetCertIndex())
+				break
+			}
+		}
+	}
+	return indexes
+}
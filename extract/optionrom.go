@@ -0,0 +1,106 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// Tagged event IDs for option ROM measurements.
+// Taken from TCG PC Client Specific Implementation Specification for
+// Conventional BIOS 1.21, Table 11 "Tagged Event IDs".
+const (
+	optionROMExecuteTag   = 0x7
+	optionROMConfigTag    = 0x8
+	optionROMMicrocodeTag = 0xA
+)
+
+var optionROMTagNames = map[uint32]string{
+	optionROMExecuteTag:   "Option ROM Execute",
+	optionROMConfigTag:    "Option ROM Configuration",
+	optionROMMicrocodeTag: "Option ROM Microcode",
+}
+
+// LegacyOptionROMEvents returns the events measuring legacy (CSM) option
+// ROMs. UEFI option ROMs are measured as EV_EFI_BOOT_SERVICES_DRIVER events,
+// while legacy option ROMs are measured as EV_POST_CODE events in the firmware
+// driver register, or as option ROM EV_EVENT_TAG events.
+//
+// Tagged events are found by their tag ID rather than by register, since the
+// option ROM configuration register (PCR3) does not map to a fixed offset
+// from the firmware driver register on every technology. As the firmware
+// measures option ROMs before booting, only tagged events preceding a
+// separator in their register are reported.
+func LegacyOptionROMEvents(events []tcg.Event, registerCfg RegisterConfig) []tcg.Event {
+	separators := make(map[uint32]int)
+	for i, event := range events {
+		if _, ok := separators[event.MRIndex()]; !ok && event.Type == tcg.Separator {
+			separators[event.MRIndex()] = i
+		}
+	}
+
+	var out []tcg.Event
+	for i, event := range events {
+		if event.Type == tcg.EventTag {
+			separator, ok := separators[event.MRIndex()]
+			if !ok || i > separator {
+				continue
+			}
+		}
+		if _, ok := legacyOptionROMKind(event, registerCfg); ok {
+			out = append(out, event)
+		}
+	}
+	return out
+}
+
+// legacyOptionROMKind describes the kind of legacy option ROM measurement
+// the event is, if any.
+func legacyOptionROMKind(event tcg.Event, registerCfg RegisterConfig) (string, bool) {
+	switch event.Type {
+	case tcg.PostCode:
+		return "POST code", event.MRIndex() == registerCfg.FirmwareDriverIdx
+	case tcg.EventTag:
+		tagged, err := tcg.ParseTaggedEventData(event.RawData())
+		if err != nil {
+			return "", false
+		}
+		name, ok := optionROMTagNames[tagged.ID]
+		return name, ok
+	}
+	return "", false
+}
+
+// legacyOptionROMFindings reports a legacy option ROM finding if the firmware
+// measured legacy option ROMs.
+func legacyOptionROMFindings(events []tcg.Event, registerCfg RegisterConfig) []*pb.Finding {
+	optionROMs := LegacyOptionROMEvents(events, registerCfg)
+	if len(optionROMs) == 0 {
+		return nil
+	}
+	descriptions := make([]string, 0, len(optionROMs))
+	for _, event := range optionROMs {
+		kind, _ := legacyOptionROMKind(event, registerCfg)
+		descriptions = append(descriptions, fmt.Sprintf("event %d (%s)", event.Num(), kind))
+	}
+	return []*pb.Finding{{
+		Type:        pb.FindingType_FINDING_TYPE_LEGACY_OPTION_ROM,
+		Description: "firmware measured legacy option ROMs: " + strings.Join(descriptions, "; "),
+	}}
+}
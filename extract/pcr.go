@@ -17,7 +17,6 @@ package extract
 import (
 	"bytes"
 	"crypto"
-	"errors"
 	"fmt"
 
 	pb "github.com/google/go-eventlog/proto/state"
@@ -26,13 +25,20 @@ import (
 
 // GrubStateFromTPMLog extracts GRUB commands from PCR8 and GRUB files from 9.
 func GrubStateFromTPMLog(hash crypto.Hash, events []tcg.Event) (*pb.GrubState, error) {
+	pbHash, err := pb.HashAlgoFromCryptoHash(hash)
+	if err != nil {
+		return nil, err
+	}
 	var files []*pb.GrubFile
 	var commands []string
+	var commandDigests []*pb.BankDigests
+	typeCounts := make(map[tcg.EventType]int)
 	for eventNum, event := range events {
 		index := event.MRIndex()
 		if index != 8 && index != 9 {
 			continue
 		}
+		typeCounts[event.UntrustedType()]++
 
 		// Skip parsing EV_EVENT_TAG event since it likely comes from Linux.
 		if event.UntrustedType() == tcg.EventTag {
@@ -44,39 +50,49 @@ func GrubStateFromTPMLog(hash crypto.Hash, events []tcg.Event) (*pb.GrubState, e
 		}
 
 		if index == 9 {
-			files = append(files, &pb.GrubFile{Digest: event.ReplayedDigest(),
-				UntrustedFilename: event.RawData()})
+			files = append(files, &pb.GrubFile{
+				Digest:            event.ReplayedDigest(),
+				UntrustedFilename: event.RawData(),
+				Hash:              pbHash,
+				BankDigests:       []*pb.BankDigest{{Hash: pbHash, Digest: event.ReplayedDigest()}},
+			})
 		} else if index == 8 {
-			hasher := hash.New()
-			suffixAt := -1
-			rawData := event.RawData()
-			for _, prefix := range validPrefixes {
-				if bytes.HasPrefix(rawData, prefix) {
-					suffixAt = len(prefix)
-					break
-				}
-			}
-			if suffixAt == -1 {
-				return nil, fmt.Errorf("invalid prefix seen for PCR%d event: %s", index, rawData)
-			}
-
-			// Check the slice is not empty after the suffix, which ensures rawData[len(rawData)-1] is not part
-			// of the suffix.
-			if len(rawData[suffixAt:]) > 0 && rawData[len(rawData)-1] == '\x00' {
-				if err := verifyNullTerminatedDataDigest(hasher, rawData[suffixAt:], event.ReplayedDigest()); err != nil {
-					return nil, fmt.Errorf("invalid GRUB event (null-terminated) #%d: %v", eventNum, err)
-				}
-			} else {
-				if err := verifyDataDigest(hasher, rawData[suffixAt:], event.ReplayedDigest()); err != nil {
-					return nil, fmt.Errorf("invalid GRUB event #%d: %v", eventNum, err)
-				}
+			if err := verifyGrubCommand(hash, event); err != nil {
+				return nil, fmt.Errorf("invalid GRUB event #%d: %v", eventNum, err)
 			}
-			hasher.Reset()
-			commands = append(commands, string(rawData))
+			commands = append(commands, string(event.RawData()))
+			commandDigests = append(commandDigests, singleBankDigests(pbHash, event.ReplayedDigest()))
 		}
 	}
 	if len(files) == 0 && len(commands) == 0 {
-		return nil, errors.New("no GRUB measurements found")
+		return nil, GRUBMeasurementsNotFoundError{SearchedMRs: []uint32{8, 9}, EventTypeCounts: typeCounts}
+	}
+	return &pb.GrubState{Files: files, Commands: commands, NormalizedCommands: normalizeGRUBCommands(commands), CommandDigests: commandDigests}, nil
+}
+
+// verifyGrubCommand checks that the data of a GRUB command event has a valid
+// prefix, and that the digest of the event is that of the command after the
+// prefix.
+func verifyGrubCommand(hash crypto.Hash, event tcg.Event) error {
+	suffixAt := -1
+	rawData := event.RawData()
+	for _, prefix := range validPrefixes {
+		if bytes.HasPrefix(rawData, prefix) {
+			suffixAt = len(prefix)
+			break
+		}
+	}
+	if suffixAt == -1 {
+		return fmt.Errorf("invalid prefix seen for PCR%d event: %s", event.MRIndex(), rawData)
+	}
+
+	// Check the slice is not empty after the suffix, which ensures rawData[len(rawData)-1] is not part
+	// of the suffix.
+	if len(rawData[suffixAt:]) > 0 && rawData[len(rawData)-1] == '\x00' {
+		if err := verifyNullTerminatedDataDigest(hash.New(), rawData[suffixAt:], event.ReplayedDigest()); err != nil {
+			return fmt.Errorf("null-terminated: %v", err)
+		}
+		return nil
 	}
-	return &pb.GrubState{Files: files, Commands: commands}, nil
+	return verifyDataDigest(hash.New(), rawData[suffixAt:], event.ReplayedDigest())
 }
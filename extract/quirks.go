@@ -0,0 +1,132 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"bytes"
+	"fmt"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+var (
+	separatorData      = []byte{0, 0, 0, 0}
+	errorSeparatorData = []byte{0xff, 0xff, 0xff, 0xff}
+)
+
+// EnabledQuirks returns Quirks combined with the quirks of QuirkProfile. It
+// returns an error if QuirkProfile is not a known tcg.QuirkSet. Callers that
+// parse the event log themselves can pass these in tcg.ParseOpts.Quirks.
+func (o Opts) EnabledQuirks() (tcg.Quirks, error) {
+	if o.QuirkProfile == "" {
+		return o.Quirks, nil
+	}
+	profile, err := tcg.QuirkSet(o.QuirkProfile)
+	if err != nil {
+		return 0, err
+	}
+	return o.Quirks | profile, nil
+}
+
+// EventLogParseOpts returns the options for parsing the event log: ParseOpts,
+// or defaults if ParseOpts is nil, with the enabled quirks added. See
+// EnabledQuirks.
+func (o Opts) EventLogParseOpts(defaults tcg.ParseOpts) (tcg.ParseOpts, error) {
+	quirks, err := o.EnabledQuirks()
+	if err != nil {
+		return tcg.ParseOpts{}, err
+	}
+	parseOpts := defaults
+	if o.ParseOpts != nil {
+		parseOpts = *o.ParseOpts
+	}
+	parseOpts.Quirks |= quirks
+	return parseOpts, nil
+}
+
+// validSeparatorData reports whether the separator data in the Secure Boot
+// or driver register is accepted with the quirks.
+func validSeparatorData(data []byte, quirks tcg.Quirks) bool {
+	if bytes.Equal(data, separatorData) {
+		return true
+	}
+	return quirks.Has(tcg.QuirkErrorSeparators) && bytes.Equal(data, errorSeparatorData)
+}
+
+// extractionQuirks returns the enabled extraction quirks that the events
+// needed. An empty SecureBoot variable only needs a quirk if opts does not
+// already allow it.
+func extractionQuirks(events []tcg.Event, registerCfg RegisterConfig, opts Opts, quirks tcg.Quirks) tcg.Quirks {
+	checkEmptySB := quirks.Has(tcg.QuirkEmptySecureBootVar) && opts.variablePolicy(0).SecureBoot == DefaultVariable
+	var applied tcg.Quirks
+	for _, e := range events {
+		if e.MRIndex() != registerCfg.SecureBootIdx && e.MRIndex() != registerCfg.FirmwareDriverIdx {
+			continue
+		}
+		switch e.Type {
+		case tcg.Separator:
+			if quirks.Has(tcg.QuirkErrorSeparators) && bytes.Equal(e.RawData(), errorSeparatorData) {
+				applied |= tcg.QuirkErrorSeparators
+			}
+		case tcg.EFIVariableDriverConfig:
+			if !checkEmptySB || e.MRIndex() != registerCfg.SecureBootIdx {
+				continue
+			}
+			v, err := tcg.ParseVariableEvent(e)
+			if err == nil && v.VarName() == "SecureBoot" && len(v.VariableData) == 0 {
+				applied |= tcg.QuirkEmptySecureBootVar
+			}
+		}
+	}
+	return applied
+}
+
+// QuirkFindings reports a quirks applied finding if parsing the event log or
+// extracting state needed any quirks.
+func QuirkFindings(applied tcg.Quirks) []*pb.Finding {
+	if applied == 0 {
+		return nil
+	}
+	return []*pb.Finding{{
+		Type:        pb.FindingType_FINDING_TYPE_QUIRKS_APPLIED,
+		Description: fmt.Sprintf("event log needed quirk workarounds: %v", applied),
+	}}
+}
+
+// ReportQuirks records the applied quirks in state.AppliedQuirks, and adds a
+// quirks applied finding. FirmwareLogState reports the extraction quirks it
+// needed. Callers that parse the event log themselves should report
+// tcg.EventLog.AppliedQuirks after extraction.
+func ReportQuirks(state *pb.FirmwareLogState, applied tcg.Quirks) {
+	if state == nil || applied == 0 {
+		return
+	}
+	for _, name := range applied.Names() {
+		if !containsString(state.AppliedQuirks, name) {
+			state.AppliedQuirks = append(state.AppliedQuirks, name)
+		}
+	}
+	state.Findings = append(state.Findings, QuirkFindings(applied)...)
+}
+
+func containsString(set []string, value string) bool {
+	for _, item := range set {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
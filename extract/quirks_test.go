@@ -0,0 +1,130 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/testdata"
+)
+
+func rhel8Events(t *testing.T) []tcg.Event {
+	t.Helper()
+	el, err := tcg.ParseEventLog(testdata.Rhel8EventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	return el.Events(register.HashSHA256)
+}
+
+func TestQuirkProfiles(t *testing.T) {
+	errorSeparator := rhel8Events(t)
+	for i, evt := range errorSeparator {
+		if evt.MRIndex() == TPMRegisterConfig.SecureBootIdx && evt.Type == tcg.Separator {
+			errorSeparator[i].Data = errorSeparatorData
+			digest := sha256.Sum256(errorSeparatorData)
+			errorSeparator[i].Digest = digest[:]
+		}
+	}
+	emptySB := rhel8Events(t)
+	for i, evt := range emptySB {
+		if evt.Type != tcg.EFIVariableDriverConfig {
+			continue
+		}
+		v, err := tcg.ParseUEFIVariableData(bytes.NewReader(evt.RawData()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.VarName() == "SecureBoot" {
+			v.VariableData = nil
+			if emptySB[i].Data, err = v.Encode(); err != nil {
+				t.Fatal(err)
+			}
+			digest := sha256.Sum256(emptySB[i].Data)
+			emptySB[i].Digest = digest[:]
+		}
+	}
+
+	tests := []struct {
+		name        string
+		events      []tcg.Event
+		opts        Opts
+		wantErr     bool
+		wantApplied tcg.Quirks
+	}{
+		{"error separator", errorSeparator, Opts{}, true, 0},
+		{"error separator with OVMF profile", errorSeparator, Opts{QuirkProfile: "ovmf-edk2-2022"}, true, 0},
+		{"error separator with quirk", errorSeparator, Opts{Quirks: tcg.QuirkErrorSeparators}, false, tcg.QuirkErrorSeparators},
+		{"error separator with GCE profile", errorSeparator, Opts{QuirkProfile: "gce"}, true, 0},
+		{"empty SecureBoot", emptySB, Opts{}, true, 0},
+		{"empty SecureBoot with GCE profile", emptySB, Opts{QuirkProfile: "gce"}, false, tcg.QuirkEmptySecureBootVar},
+		{"empty SecureBoot with quirk", emptySB, Opts{Quirks: tcg.QuirkEmptySecureBootVar}, false, tcg.QuirkEmptySecureBootVar},
+		// The variable policy already allows the empty variable.
+		{"empty SecureBoot with policy and GCE profile", emptySB, Opts{QuirkProfile: "gce", AllowEmptySBVar: true}, false, 0},
+		{"clean log with OVMF profile", rhel8Events(t), Opts{QuirkProfile: "ovmf-edk2-2022"}, false, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSecurebootState(tc.events, TPMRegisterConfig, tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseSecurebootState() = %v, wantErr %v", err, tc.wantErr)
+			}
+			quirks, err := tc.opts.EnabledQuirks()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := extractionQuirks(tc.events, TPMRegisterConfig, tc.opts, quirks); !tc.wantErr && got != tc.wantApplied {
+				t.Errorf("extractionQuirks() = %v, want %v", got, tc.wantApplied)
+			}
+		})
+	}
+}
+
+func TestEnabledQuirks(t *testing.T) {
+	quirks, err := Opts{Quirks: tcg.QuirkPadding, QuirkProfile: "gce"}.EnabledQuirks()
+	if err != nil {
+		t.Fatalf("EnabledQuirks(): %v", err)
+	}
+	if want := tcg.QuirkPadding | tcg.GCEQuirks; quirks != want {
+		t.Errorf("EnabledQuirks() = %v, want %v", quirks, want)
+	}
+	if _, err := (Opts{QuirkProfile: "unknown"}).EnabledQuirks(); err == nil {
+		t.Errorf("EnabledQuirks(unknown profile) = nil error, want error")
+	}
+}
+
+func TestReportQuirks(t *testing.T) {
+	state := &pb.FirmwareLogState{}
+	ReportQuirks(state, 0)
+	ReportQuirks(state, tcg.QuirkEmptySecureBootVar)
+	ReportQuirks(state, tcg.QuirkPadding|tcg.QuirkEmptySecureBootVar)
+	if diff := cmp.Diff([]string{"empty-secureboot-var", "padding"}, state.GetAppliedQuirks()); diff != "" {
+		t.Errorf("ReportQuirks() returned unexpected diff (-want +got):\n%s", diff)
+	}
+	if len(state.GetFindings()) != 2 {
+		t.Errorf("ReportQuirks() = %d findings, want 2", len(state.GetFindings()))
+	}
+	for _, finding := range state.GetFindings() {
+		if finding.GetType() != pb.FindingType_FINDING_TYPE_QUIRKS_APPLIED {
+			t.Errorf("ReportQuirks() = finding %v, want %v", finding.GetType(), pb.FindingType_FINDING_TYPE_QUIRKS_APPLIED)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"fmt"
+	"sort"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+// RedactedMRsError is returned with Opts.Strict when the events of some
+// registers were replaced by redacted register events, so the state measured
+// into them is missing. See tcg.RedactEventLog.
+type RedactedMRsError struct {
+	// MRs are the redacted registers, as encoded in the event log.
+	MRs []uint32
+}
+
+// Error returns a human-friendly description of the redacted registers.
+func (e RedactedMRsError) Error() string {
+	return fmt.Sprintf("events of registers %v were redacted: their state cannot be extracted", e.MRs)
+}
+
+// ReportRedactedMRs records the redacted registers of the event log, e.g.,
+// tcg.EventLog.RedactedMRs, in state.RedactedMrs in increasing order, and
+// adds a redacted registers finding. With Strict, it returns a
+// RedactedMRsError. Callers that parse the event log with
+// tcg.ParseOpts.AllowRedactedMRs should report them after extraction.
+func (o Opts) ReportRedactedMRs(state *pb.FirmwareLogState, redacted []int) error {
+	if len(redacted) == 0 {
+		return nil
+	}
+	mrs := make([]uint32, 0, len(redacted))
+	for _, idx := range redacted {
+		mrs = append(mrs, uint32(idx))
+	}
+	sort.Slice(mrs, func(i, j int) bool { return mrs[i] < mrs[j] })
+	if state != nil {
+		state.RedactedMrs = mrs
+		state.Findings = append(state.Findings, &pb.Finding{
+			Type:        pb.FindingType_FINDING_TYPE_REDACTED_MRS,
+			Description: fmt.Sprintf("events of registers %v were redacted, and their state was not extracted", mrs),
+		})
+	}
+	if o.Strict {
+		return RedactedMRsError{MRs: mrs}
+	}
+	return nil
+}
@@ -21,27 +21,158 @@ import (
 	"github.com/google/go-eventlog/tcg"
 )
 
-// registerConfig contains the measurement register technology-specific indexes
+// RegisterConfig contains the measurement register technology-specific indexes
 // expected to contain the events corresponding to various states, like EFI
 // and Secure Boot states.
 // This uses the event log-encoded index, e.g., PCR or CC MR (not RTMR).
-type registerConfig struct {
-	Name                          string
-	FirmwareDriverIdx             uint32
-	SecureBootIdx                 uint32
-	EFIAppIdx                     uint32
-	ExitBootServicesIdx           uint32
-	GRUBCmdIdx                    uint32
-	GRUBFileIdx                   uint32
-	GRUBExtracter                 func(crypto.Hash, []tcg.Event) (*pb.GrubState, error)
-	PlatformExtracter             func(crypto.Hash, []tcg.Event) (*pb.PlatformState, error)
+//
+// Use NewTPMRegisterConfig, NewRTMRRegisterConfig, NewSEVSNPRegisterConfig,
+// NewCCARegisterConfig, NewCoVERegisterConfig, or NewCustomRegisterConfig to
+// construct a RegisterConfig.
+type RegisterConfig struct {
+	// Name is the measurement register technology name, used in error messages.
+	Name string
+	// FirmwareDriverIdx contains the firmware driver and option ROM events.
+	FirmwareDriverIdx uint32
+	// SecureBootIdx contains the Secure Boot variable and authority events.
+	SecureBootIdx uint32
+	// EFIAppIdx contains the EFI application (e.g., shim, bootloader) events.
+	EFIAppIdx uint32
+	// ExitBootServicesIdx contains the ExitBootServices() EFI action events.
+	ExitBootServicesIdx uint32
+	// GRUBCmdIdx contains the GRUB command and kernel command line events.
+	GRUBCmdIdx uint32
+	// GRUBFileIdx contains the GRUB file events.
+	GRUBFileIdx uint32
+	// BootConfigIdx contains the boot variable and platform configuration
+	// events.
+	BootConfigIdx uint32
+	// GRUBExtracter extracts the GRUB state from the GRUB registers.
+	GRUBExtracter func(crypto.Hash, []tcg.Event) (*pb.GrubState, error)
+	// PlatformExtracter extracts the platform state.
+	PlatformExtracter func(crypto.Hash, []tcg.Event) (*pb.PlatformState, error)
+	// AdditionalSecureBootIdxEvents are the event types allowed in
+	// SecureBootIdx in addition to the Secure Boot events, for technologies
+	// where SecureBootIdx is shared with other PCRs' events.
 	AdditionalSecureBootIdxEvents map[tcg.EventType]bool
-	LogType                       pb.LogType
+	// LogType is the event log type reported in FirmwareLogState.
+	LogType pb.LogType
+	// Drtm contains the dynamic launch registers, or nil if the technology
+	// does not support a dynamic launch.
+	Drtm *DrtmRegisterConfig
+}
+
+// DrtmRegisterConfig contains the measurement register indexes used by a
+// dynamic launch (DRTM), e.g., Intel TXT or AMD SKINIT with Linux Secure
+// Launch.
+type DrtmRegisterConfig struct {
+	// Name is the measurement register technology name, used in error messages.
+	Name string
+	// Indexes contains all registers reset and extended by the dynamic launch.
+	Indexes []uint32
+	// ImageIdx contains the Secure Launch image measurements, e.g., initramfs.
+	ImageIdx uint32
+	// ConfigIdx contains the Secure Launch configuration measurements, e.g.,
+	// the boot parameters and kernel command line.
+	ConfigIdx uint32
+}
+
+func (c DrtmRegisterConfig) contains(index uint32) bool {
+	for _, idx := range c.Indexes {
+		if idx == index {
+			return true
+		}
+	}
+	return false
+}
+
+// MRLayout maps the event categories of a RegisterConfig to event log-encoded
+// measurement register indexes.
+type MRLayout struct {
+	FirmwareDriverIdx   uint32
+	SecureBootIdx       uint32
+	EFIAppIdx           uint32
+	ExitBootServicesIdx uint32
+	GRUBCmdIdx          uint32
+	GRUBFileIdx         uint32
+	BootConfigIdx       uint32
+}
+
+// Layout returns the measurement register indexes of the RegisterConfig.
+func (c RegisterConfig) Layout() MRLayout {
+	return MRLayout{
+		FirmwareDriverIdx:   c.FirmwareDriverIdx,
+		SecureBootIdx:       c.SecureBootIdx,
+		EFIAppIdx:           c.EFIAppIdx,
+		ExitBootServicesIdx: c.ExitBootServicesIdx,
+		GRUBCmdIdx:          c.GRUBCmdIdx,
+		GRUBFileIdx:         c.GRUBFileIdx,
+		BootConfigIdx:       c.BootConfigIdx,
+	}
+}
+
+// NewTPMRegisterConfig returns a copy of TPMRegisterConfig.
+func NewTPMRegisterConfig() RegisterConfig {
+	return TPMRegisterConfig.clone()
+}
+
+// NewRTMRRegisterConfig returns a copy of RTMRRegisterConfig.
+func NewRTMRRegisterConfig() RegisterConfig {
+	return RTMRRegisterConfig.clone()
+}
+
+// NewSEVSNPRegisterConfig returns a copy of SEVSNPRegisterConfig.
+func NewSEVSNPRegisterConfig() RegisterConfig {
+	return SEVSNPRegisterConfig.clone()
+}
+
+// NewCCARegisterConfig returns a copy of CCARegisterConfig.
+func NewCCARegisterConfig() RegisterConfig {
+	return CCARegisterConfig.clone()
+}
+
+// NewCoVERegisterConfig returns a copy of CoVERegisterConfig.
+func NewCoVERegisterConfig() RegisterConfig {
+	return CoVERegisterConfig.clone()
+}
+
+// NewCustomRegisterConfig returns a RegisterConfig for a platform with a custom
+// measurement register layout. The extracters, additional Secure Boot events,
+// and log type are copied from base (e.g., NewTPMRegisterConfig()).
+func NewCustomRegisterConfig(name string, base RegisterConfig, layout MRLayout) RegisterConfig {
+	cfg := base.clone()
+	cfg.Name = name
+	cfg.FirmwareDriverIdx = layout.FirmwareDriverIdx
+	cfg.SecureBootIdx = layout.SecureBootIdx
+	cfg.EFIAppIdx = layout.EFIAppIdx
+	cfg.ExitBootServicesIdx = layout.ExitBootServicesIdx
+	cfg.GRUBCmdIdx = layout.GRUBCmdIdx
+	cfg.GRUBFileIdx = layout.GRUBFileIdx
+	cfg.BootConfigIdx = layout.BootConfigIdx
+	return cfg
+}
+
+// clone returns a copy of c that does not share AdditionalSecureBootIdxEvents
+// or Drtm.
+func (c RegisterConfig) clone() RegisterConfig {
+	if c.AdditionalSecureBootIdxEvents != nil {
+		events := make(map[tcg.EventType]bool, len(c.AdditionalSecureBootIdxEvents))
+		for eventType, allowed := range c.AdditionalSecureBootIdxEvents {
+			events[eventType] = allowed
+		}
+		c.AdditionalSecureBootIdxEvents = events
+	}
+	if c.Drtm != nil {
+		drtm := *c.Drtm
+		drtm.Indexes = append([]uint32(nil), c.Drtm.Indexes...)
+		c.Drtm = &drtm
+	}
+	return c
 }
 
 // TPMRegisterConfig configures the expected indexes and event types for
 // TPM-based event logs.
-var TPMRegisterConfig = registerConfig{
+var TPMRegisterConfig = RegisterConfig{
 	Name:                "PCR",
 	FirmwareDriverIdx:   2,
 	SecureBootIdx:       7,
@@ -49,17 +180,34 @@ var TPMRegisterConfig = registerConfig{
 	ExitBootServicesIdx: 5,
 	GRUBCmdIdx:          8,
 	GRUBFileIdx:         9,
+	BootConfigIdx:       1,
 	GRUBExtracter:       GrubStateFromTPMLog,
 	PlatformExtracter:   PlatformState,
 	// AdditionalSecureBootIdxEvents is empty since
 	// eventparse.ParseSecurebootState encodes all the current allowable types
 	// for PCR 7.
 	LogType: pb.LogType_LOG_TYPE_TCG2,
+	Drtm:    &TPMDrtmRegisterConfig,
+}
+
+// TPMDrtmRegisterConfig configures the expected indexes for a dynamic launch
+// into TPM PCRs. Intel TXT measures the SINIT ACM and launch control policy
+// into PCR17 and the MLE into PCR18, and tboot measures the OS into PCR19.
+// Linux Secure Launch measures its images into PCR17 and its configuration
+// into PCR18.
+//
+// It can be used with DrtmState on a standalone DRTM event log, e.g., read
+// from /sys/kernel/security/slaunch/eventlog.
+var TPMDrtmRegisterConfig = DrtmRegisterConfig{
+	Name:      "PCR",
+	Indexes:   []uint32{17, 18, 19},
+	ImageIdx:  17,
+	ConfigIdx: 18,
 }
 
 // RTMRRegisterConfig configures the expected indexes and event types for
 // RTMR-based event logs.
-var RTMRRegisterConfig = registerConfig{
+var RTMRRegisterConfig = RegisterConfig{
 	Name: "RTMR",
 	// CCMR2=RTMR[1]=PCR[2]
 	FirmwareDriverIdx: 2,
@@ -72,7 +220,9 @@ var RTMRRegisterConfig = registerConfig{
 	// CCMR3=RTMR[2]=PCR[8]
 	GRUBCmdIdx: 3,
 	// CCMR3=RTMR[2]=PCR[9]
-	GRUBFileIdx:   3,
+	GRUBFileIdx: 3,
+	// CCMR1=RTMR[0]=PCR[1]
+	BootConfigIdx: 1,
 	GRUBExtracter: GrubStateFromRTMRLog,
 	PlatformExtracter: func(_ crypto.Hash, _ []tcg.Event) (*pb.PlatformState, error) {
 		return &pb.PlatformState{Technology: pb.GCEConfidentialTechnology_INTEL_TDX}, nil
@@ -96,3 +246,40 @@ var RTMRRegisterConfig = registerConfig{
 	},
 	LogType: pb.LogType_LOG_TYPE_CC,
 }
+
+// SEVSNPRegisterConfig configures the expected indexes and event types for AMD
+// SEV-SNP Confidential Computing event logs, i.e., those with the SEV CC type
+// in the CCEL ACPI table.
+//
+// Upstream EDK2 only implements the EFI_CC_MEASUREMENT_PROTOCOL for TDX, so
+// this assumes SEV-SNP firmware maps PCRs to CC MR indexes as MapPcrToMrIndex
+// does for TDX: CC MR index 0 is the launch measurement, and CC MR indexes 1
+// to 4 are the runtime measurement registers.
+// https://github.com/tianocore/edk2/blob/master/SecurityPkg/Tcg/TdTcg2Dxe/TdTcg2Dxe.c
+var SEVSNPRegisterConfig = ccRegisterConfig("CCMR", pb.GCEConfidentialTechnology_AMD_SEV_SNP)
+
+// CCARegisterConfig configures the expected indexes and event types for Arm
+// Confidential Compute Architecture (CCA) event logs. Realm firmware maps the
+// PCRs to the Realm Extensible Measurements (REMs) as TDX firmware maps them
+// to RTMRs: CC MR index 0 is the Realm Initial Measurement, and CC MR index
+// n+1 is REM[n]. Replay CCA event logs against a register.REMBank.
+var CCARegisterConfig = ccRegisterConfig("REM", pb.GCEConfidentialTechnology_ARM_CCA)
+
+// CoVERegisterConfig configures the expected indexes and event types for
+// RISC-V Confidential VM Extension (CoVE) event logs. TVM firmware maps the
+// PCRs to the TVM measurement registers as TDX firmware maps them to RTMRs:
+// CC MR index 0 is the static TVM measurement, and CC MR index n+1 is the
+// runtime measurement register n.
+var CoVERegisterConfig = ccRegisterConfig("MSR", pb.GCEConfidentialTechnology_RISCV_COVE)
+
+// ccRegisterConfig returns a RegisterConfig with the CC MR layout of
+// RTMRRegisterConfig, for a confidential computing technology whose firmware
+// maps PCRs to CC MR indexes as the UEFI CC measurement protocol does for TDX.
+func ccRegisterConfig(name string, technology pb.GCEConfidentialTechnology) RegisterConfig {
+	cfg := RTMRRegisterConfig.clone()
+	cfg.Name = name
+	cfg.PlatformExtracter = func(_ crypto.Hash, _ []tcg.Event) (*pb.PlatformState, error) {
+		return &pb.PlatformState{Technology: technology}, nil
+	}
+	return cfg
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// DefaultMinRepeatedRunLength is the shortest run of events reported by
+// RepeatedEventRuns when extracting a FirmwareLogState. Single repeated events
+// are common in valid logs (e.g., GRUB re-running a command), so only runs of
+// two or more events are considered suspicious.
+const DefaultMinRepeatedRunLength = 2
+
+// RepeatedEventRun is a run of events in a single measurement register that
+// is immediately followed by identical copies of itself.
+type RepeatedEventRun struct {
+	// MRIndex is the measurement register index, as encoded in the event log.
+	MRIndex uint32
+	// FirstEventNum is the number of the first event of the first copy.
+	FirstEventNum uint32
+	// Length is the number of events in each copy.
+	Length int
+	// Copies is the number of consecutive identical copies, at least 2.
+	Copies int
+}
+
+// String returns a human-friendly description of the run.
+func (r RepeatedEventRun) String() string {
+	return fmt.Sprintf("MR%d: %d events starting at event %d repeated %d times", r.MRIndex, r.Length, r.FirstEventNum, r.Copies)
+}
+
+// RepeatedEventRuns reports runs of at least minRunLength events that are
+// measured into a register and then measured again, identically, immediately
+// afterwards. Events are identical if they have the same type, data, and
+// digest. minRunLength is at least 2, as single repeated events are common in
+// valid logs; smaller values are treated as 2.
+//
+// Such a log still replays, but it suggests the measurements were stuffed or
+// re-measured without a reboot, so it should be treated as suspicious rather
+// than silently accepted. This is a heuristic and does not detect every
+// replayed sequence.
+func RepeatedEventRuns(events []tcg.Event, minRunLength int) []RepeatedEventRun {
+	if minRunLength < DefaultMinRepeatedRunLength {
+		minRunLength = DefaultMinRepeatedRunLength
+	}
+	// Identical events get the same ID, so runs are compared by ID, and a
+	// copy of the run starting at an event can only start at a later
+	// occurrence of the same ID.
+	type eventKey struct {
+		mrIndex uint32
+		typ     tcg.EventType
+		digest  string
+		data    string
+	}
+	type mrEvents struct {
+		ids  []int
+		nums []uint32
+	}
+	idOf := make(map[eventKey]int)
+	var order []uint32
+	byMR := make(map[uint32]*mrEvents)
+	for _, event := range events {
+		key := eventKey{event.MRIndex(), event.Type, string(event.ReplayedDigest()), string(event.RawData())}
+		id, ok := idOf[key]
+		if !ok {
+			id = len(idOf)
+			idOf[key] = id
+		}
+		mr, ok := byMR[key.mrIndex]
+		if !ok {
+			mr = &mrEvents{}
+			byMR[key.mrIndex] = mr
+			order = append(order, key.mrIndex)
+		}
+		mr.ids = append(mr.ids, id)
+		mr.nums = append(mr.nums, event.Num())
+	}
+
+	var runs []RepeatedEventRun
+	for _, mrIndex := range order {
+		ids := byMR[mrIndex].ids
+		occurrences := make(map[int][]int)
+		for i, id := range ids {
+			occurrences[id] = append(occurrences[id], i)
+		}
+		for start := 0; start < len(ids); start++ {
+			later := occurrences[ids[start]]
+			later = later[sort.SearchInts(later, start+1):]
+			for _, next := range later {
+				length := next - start
+				if start+2*length > len(ids) {
+					break
+				}
+				if length < minRunLength {
+					continue
+				}
+				copies := 1
+				for start+(copies+1)*length <= len(ids) &&
+					sameIDs(ids[start:start+length], ids[start+copies*length:start+(copies+1)*length]) {
+					copies++
+				}
+				if copies < 2 {
+					continue
+				}
+				runs = append(runs, RepeatedEventRun{
+					MRIndex:       mrIndex,
+					FirstEventNum: byMR[mrIndex].nums[start],
+					Length:        length,
+					Copies:        copies,
+				})
+				start += copies*length - 1
+				break
+			}
+		}
+	}
+	return runs
+}
+
+func sameIDs(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// repeatedEventsFindings reports a repeated events finding if the events
+// contain suspicious repeated runs.
+func repeatedEventsFindings(events []tcg.Event) []*pb.Finding {
+	runs := RepeatedEventRuns(events, DefaultMinRepeatedRunLength)
+	if len(runs) == 0 {
+		return nil
+	}
+	descriptions := make([]string, 0, len(runs))
+	for _, run := range runs {
+		descriptions = append(descriptions, run.String())
+	}
+	return []*pb.Finding{{
+		Type:        pb.FindingType_FINDING_TYPE_REPEATED_EVENTS,
+		Description: "event log contains repeated event runs: " + strings.Join(descriptions, "; "),
+	}}
+}
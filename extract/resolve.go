@@ -0,0 +1,66 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"context"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// A DigestResolver verifies content that an event measures by reference.
+// Some events log a digest of external content in their data, e.g., an EV_IPL
+// event with a hash string, so replaying the event log only verifies the
+// digest, not the content. A resolver fetches the referenced content and
+// checks it against that digest.
+type DigestResolver interface {
+	// Name identifies the resolver in DigestResolution results.
+	Name() string
+	// Resolve returns whether the resolver handles the event, a reference to
+	// the content, e.g., a file path, and an error if the content could not
+	// be fetched or does not match the digest in the event data. Fetching the
+	// content, e.g., over the network, should stop when ctx is done.
+	Resolve(ctx context.Context, event tcg.Event) (handled bool, reference string, err error)
+}
+
+// DigestResolutions runs the resolvers on the events. Each event is resolved
+// by the first resolver that handles it. Failed resolutions are recorded in
+// the results rather than returned, so policy can decide how to treat them.
+//
+// The events should be replayed first, as resolvers trust the event data.
+func DigestResolutions(ctx context.Context, events []tcg.Event, resolvers []DigestResolver) []*pb.DigestResolution {
+	var resolutions []*pb.DigestResolution
+	for _, event := range events {
+		for _, resolver := range resolvers {
+			handled, reference, err := resolver.Resolve(ctx, event)
+			if !handled {
+				continue
+			}
+			resolution := &pb.DigestResolution{
+				EventNum:  event.Num(),
+				Resolver:  resolver.Name(),
+				Reference: reference,
+				Verified:  err == nil,
+			}
+			if err != nil {
+				resolution.Error = err.Error()
+			}
+			resolutions = append(resolutions, resolution)
+			break
+		}
+	}
+	return resolutions
+}
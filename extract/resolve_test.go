@@ -0,0 +1,87 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// fakeResolver resolves EV_IPL events whose data has a prefix, failing those
+// that also contain bad.
+type fakeResolver struct {
+	name   string
+	prefix []byte
+	bad    []byte
+}
+
+func (r fakeResolver) Name() string { return r.name }
+
+func (r fakeResolver) Resolve(ctx context.Context, event tcg.Event) (bool, string, error) {
+	if event.Type != tcg.Ipl || !bytes.HasPrefix(event.RawData(), r.prefix) {
+		return false, "", nil
+	}
+	ref := string(bytes.TrimPrefix(event.RawData(), r.prefix))
+	if err := ctx.Err(); err != nil {
+		return true, ref, err
+	}
+	if r.bad != nil && bytes.Contains(event.RawData(), r.bad) {
+		return true, ref, errors.New("digest mismatch")
+	}
+	return true, ref, nil
+}
+
+func TestDigestResolutions(t *testing.T) {
+	events := numberedEvents(t, []tcg.Event{
+		{Index: 8, Type: tcg.Ipl, Data: []byte("file:/boot/good")},
+		{Index: 8, Type: tcg.Ipl, Data: []byte("file:/boot/bad")},
+		{Index: 8, Type: tcg.Ipl, Data: []byte("grub_cmd: linux /vmlinuz")},
+		{Index: 8, Type: tcg.Ipl, Data: []byte("url:https://example.com/initrd")},
+		{Index: 4, Type: tcg.EFIBootServicesApplication, Data: []byte("file:/boot/ignored")},
+	})
+	resolvers := []DigestResolver{
+		fakeResolver{name: "file", prefix: []byte("file:"), bad: []byte("bad")},
+		fakeResolver{name: "url", prefix: []byte("url:")},
+		fakeResolver{name: "catch-all", prefix: []byte("file:")},
+	}
+
+	got := DigestResolutions(context.Background(), events, resolvers)
+	want := []*pb.DigestResolution{
+		{EventNum: 0, Resolver: "file", Reference: "/boot/good", Verified: true},
+		{EventNum: 1, Resolver: "file", Reference: "/boot/bad", Error: "digest mismatch"},
+		{EventNum: 3, Resolver: "url", Reference: "https://example.com/initrd", Verified: true},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("DigestResolutions() returned unexpected diff (-want +got):\n%s", diff)
+	}
+	if got := DigestResolutions(context.Background(), events, nil); got != nil {
+		t.Errorf("DigestResolutions(no resolvers) = %v, want nil", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, resolution := range DigestResolutions(ctx, events, resolvers) {
+		if resolution.GetVerified() || resolution.GetError() != context.Canceled.Error() {
+			t.Errorf("DigestResolutions(canceled) = %v, want a %v error", resolution, context.Canceled)
+		}
+	}
+}
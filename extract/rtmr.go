@@ -17,7 +17,6 @@ package extract
 import (
 	"bytes"
 	"crypto"
-	"errors"
 	"fmt"
 
 	pb "github.com/google/go-eventlog/proto/state"
@@ -26,12 +25,19 @@ import (
 
 // GrubStateFromRTMRLog extracts GRUB commands from RTMR2.
 func GrubStateFromRTMRLog(hash crypto.Hash, events []tcg.Event) (*pb.GrubState, error) {
+	pbHash, err := pb.HashAlgoFromCryptoHash(hash)
+	if err != nil {
+		return nil, err
+	}
 	var commands []string
+	var commandDigests []*pb.BankDigests
+	typeCounts := make(map[tcg.EventType]int)
 	for eventNum, event := range events {
 		ccMRIndex := event.MRIndex()
 		if ccMRIndex != 3 {
 			continue
 		}
+		typeCounts[event.UntrustedType()]++
 
 		// Skip parsing EV_EVENT_TAG event since it likely comes from Linux.
 		if event.UntrustedType() == tcg.EventTag {
@@ -68,9 +74,10 @@ func GrubStateFromRTMRLog(hash crypto.Hash, events []tcg.Event) (*pb.GrubState,
 		}
 		hasher.Reset()
 		commands = append(commands, string(rawData))
+		commandDigests = append(commandDigests, singleBankDigests(pbHash, event.ReplayedDigest()))
 	}
 	if len(commands) == 0 {
-		return nil, errors.New("no GRUB measurements found")
+		return nil, GRUBMeasurementsNotFoundError{SearchedMRs: []uint32{3}, EventTypeCounts: typeCounts}
 	}
-	return &pb.GrubState{Commands: commands}, nil
+	return &pb.GrubState{Commands: commands, NormalizedCommands: normalizeGRUBCommands(commands), CommandDigests: commandDigests}, nil
 }
@@ -0,0 +1,88 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"strings"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// SecureBootContradictions reports contradictions between the measured
+// SecureBoot, SetupMode, and PK variables. Per the UEFI spec, enrolling a PK
+// leaves setup mode, and Secure Boot can only be enabled in user mode, so:
+//   - SecureBoot=1 requires SetupMode=0 and a PK.
+//   - SetupMode=1 requires that there is no PK, and SetupMode=0 that there is.
+//
+// SetupMode is not required to be measured, so the checks involving it only
+// apply if it is present. Variables that are not measured, or fail to parse,
+// are not checked; ParseSecurebootState reports malformed variables.
+func SecureBootContradictions(events []tcg.Event, registerCfg RegisterConfig) []string {
+	var secureBoot, setupMode []byte
+	var pk []byte
+	var seenPK bool
+	for _, event := range events {
+		if event.MRIndex() != registerCfg.SecureBootIdx || event.Type != tcg.EFIVariableDriverConfig {
+			continue
+		}
+		v, err := tcg.ParseVariableEvent(event)
+		if err != nil {
+			continue
+		}
+		switch v.VarName() {
+		case "SecureBoot":
+			secureBoot = v.VariableData
+		case "SetupMode":
+			setupMode = v.VariableData
+		case "PK":
+			pk = v.VariableData
+			seenPK = true
+		}
+	}
+
+	enabled := len(secureBoot) == 1 && secureBoot[0] == 1
+	measuredSetupMode := len(setupMode) == 1
+	inSetupMode := measuredSetupMode && setupMode[0] == 1
+	hasPK := len(pk) != 0
+
+	var contradictions []string
+	if enabled && inSetupMode {
+		contradictions = append(contradictions, "SecureBoot is enabled in setup mode")
+	}
+	if enabled && seenPK && !hasPK {
+		contradictions = append(contradictions, "SecureBoot is enabled without a PK")
+	}
+	if inSetupMode && hasPK {
+		contradictions = append(contradictions, "SetupMode is 1 but a PK is enrolled")
+	}
+	if measuredSetupMode && !inSetupMode && seenPK && !hasPK {
+		contradictions = append(contradictions, "SetupMode is 0 but no PK is enrolled")
+	}
+	return contradictions
+}
+
+// secureBootFindings reports a Secure Boot inconsistency finding if the
+// measured Secure Boot variables contradict each other.
+func secureBootFindings(events []tcg.Event, registerCfg RegisterConfig) []*pb.Finding {
+	contradictions := SecureBootContradictions(events, registerCfg)
+	if len(contradictions) == 0 {
+		return nil
+	}
+	return []*pb.Finding{{
+		Type:        pb.FindingType_FINDING_TYPE_SECURE_BOOT_INCONSISTENT,
+		Description: "Secure Boot variables are contradictory: " + strings.Join(contradictions, "; "),
+	}}
+}
@@ -49,6 +49,17 @@ type SecurebootState struct {
 	ForbiddenKeys []x509.Certificate
 	// ForbiddenKeys enumerates hashes which must not permit a binary to run.
 	ForbiddenHashes [][]byte
+	// ForbiddenErrors describes the malformed dbx entries that were skipped.
+	// ForbiddenKeys and ForbiddenHashes only contain the well-formed entries.
+	ForbiddenErrors []tcg.SignatureListError
+
+	// PlatformKeyEntries, ExchangeKeyEntries, PermittedEntries, and
+	// ForbiddenEntries hold every entry of the PK, KEK, db, and dbx
+	// variables, with its signature type and owner.
+	PlatformKeyEntries []tcg.SignatureEntry
+	ExchangeKeyEntries []tcg.SignatureEntry
+	PermittedEntries   []tcg.SignatureEntry
+	ForbiddenEntries   []tcg.SignatureEntry
 
 	// PreSeparatorAuthority describes the use of a secure-boot key to authorize
 	// the execution of a binary before the separator.
@@ -56,6 +67,9 @@ type SecurebootState struct {
 	// PostSeparatorAuthority describes the use of a secure-boot key to authorize
 	// the execution of a binary after the separator.
 	PostSeparatorAuthority []x509.Certificate
+	// PostSeparatorAuthorityUses describes each post-separator authority
+	// event, in log order.
+	PostSeparatorAuthorityUses []AuthorityUse
 
 	// DriverLoadSourceHints describes the origin of boot services drivers.
 	// This data is not tamper-proof and must only be used as a hint.
@@ -66,6 +80,76 @@ type SecurebootState struct {
 	//
 	// See: https://docs.microsoft.com/en-us/windows-hardware/design/device-experiences/oem-kernel-dma-protection
 	DMAProtectionDisabled bool
+
+	// DebugModeEnabled is true if the firmware measured the "UEFI Debug Mode"
+	// action, i.e., a UEFI debugger was enabled during boot. It is only set
+	// with Opts.AllowDebugMode, as parsing fails otherwise.
+	DebugModeEnabled bool
+}
+
+// AuthorityUse describes the use of secure-boot keys recorded by an
+// EV_EFI_VARIABLE_AUTHORITY event.
+type AuthorityUse struct {
+	// EventNum is the number of the authority event.
+	EventNum uint32
+	// VariableName is the variable the keys were found in, e.g., "db".
+	VariableName string
+	// Certs are the keys used.
+	Certs []x509.Certificate
+}
+
+// VariablePresence controls whether a measured UEFI variable may be missing
+// (not measured) or empty (measured with no data).
+type VariablePresence int
+
+// Supported variable presences.
+const (
+	// DefaultVariable applies the default checks. The SecureBoot variable may
+	// be missing, but not empty. PK, KEK, and db may be missing or empty
+	// unless Secure Boot is enabled. dbx may always be missing or empty.
+	DefaultVariable VariablePresence = iota
+	// OptionalVariable allows the variable to be missing or empty, even if
+	// Secure Boot is enabled. An empty SecureBoot variable means disabled.
+	OptionalVariable
+	// RequiredVariable requires the variable to be measured with data, even
+	// if Secure Boot is disabled.
+	RequiredVariable
+)
+
+// VariablePolicy controls how missing or empty Secure Boot variables are
+// handled, since different firmware omits different variables.
+type VariablePolicy struct {
+	SecureBoot VariablePresence
+	PK         VariablePresence
+	KEK        VariablePresence
+	DB         VariablePresence
+	DBX        VariablePresence
+}
+
+func (o Opts) variablePolicy(quirks tcg.Quirks) VariablePolicy {
+	policy := o.VariablePolicy
+	if (o.AllowEmptySBVar || quirks.Has(tcg.QuirkEmptySecureBootVar)) && policy.SecureBoot == DefaultVariable {
+		policy.SecureBoot = OptionalVariable
+	}
+	return policy
+}
+
+// variablePresence is the presence of a named variable in a VariablePolicy.
+type variablePresence struct {
+	name     string
+	presence VariablePresence
+}
+
+// variables returns the presence of each variable in the policy, in a fixed
+// order so errors are deterministic.
+func (p VariablePolicy) variables() []variablePresence {
+	return []variablePresence{
+		{"SecureBoot", p.SecureBoot},
+		{"PK", p.PK},
+		{"KEK", p.KEK},
+		{"db", p.DB},
+		{"dbx", p.DBX},
+	}
 }
 
 // DriverLoadSource describes the logical origin of a boot services driver.
@@ -97,21 +181,34 @@ func ParseSecurebootStateLegacy(events []tcg.Event) (*SecurebootState, error) {
 	// - If SecureBoot was 1 (enabled), platform + exchange + database keys
 	//   were specified.
 	// - No UEFI debugger was attached.
-	return ParseSecurebootState(events, TPMRegisterConfig, Opts{})
+	// Callers are not expected to check ForbiddenErrors, so a malformed dbx
+	// fails parsing, as in schema version 1.
+	return ParseSecurebootState(events, TPMRegisterConfig, Opts{CompatLevel: 1})
 }
 
 // ParseSecurebootState parses a series of events to determine the
 // configuration of secure boot on a device. An error is returned if
 // the state cannot be determined, or if the event log is structured
 // in such a way that it may have been tampered post-execution of
-// platform firmware.
-func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts Opts) (*SecurebootState, error) {
+// platform firmware. Malformed dbx entries are skipped and reported in
+// ForbiddenErrors, unless opts.CompatLevel is below 2.
+func ParseSecurebootState(events []tcg.Event, registerCfg RegisterConfig, opts Opts) (*SecurebootState, error) {
+	compatLevel, err := opts.compatLevel()
+	if err != nil {
+		return nil, err
+	}
+	quirks, err := opts.EnabledQuirks()
+	if err != nil {
+		return nil, err
+	}
 	var (
 		out            SecurebootState
 		seenSeparator7 bool
 		seenSeparator2 bool
 		seenAuthority  bool
 		seenVars       = map[string]bool{}
+		varSizes       = map[string]int{}
+		policy         = opts.variablePolicy(quirks)
 		driverSources  [][]tcg.EFIDevicePathElement
 	)
 
@@ -134,7 +231,7 @@ func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts O
 					return nil, fmt.Errorf("duplicate separator at event %d", e.Num())
 				}
 				seenSeparator7 = true
-				if !bytes.Equal(e.RawData(), []byte{0, 0, 0, 0}) {
+				if !validSeparatorData(e.RawData(), quirks) {
 					return nil, fmt.Errorf("invalid separator data at event %d: %v", e.Num(), e.RawData())
 				}
 				if digestVerify != nil {
@@ -143,9 +240,15 @@ func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts O
 
 			case tcg.EFIAction:
 				switch string(e.RawData()) {
-				case "UEFI Debug Mode":
-					return nil, errors.New("a UEFI debugger was present during boot")
-				case "DMA Protection Disabled":
+				case tcg.UEFIDebugMode:
+					if !opts.AllowDebugMode {
+						return nil, errors.New("a UEFI debugger was present during boot")
+					}
+					if digestVerify != nil {
+						return nil, fmt.Errorf("invalid digest for EFI Action 'UEFI Debug Mode' on event %d: %v", e.Num(), digestVerify)
+					}
+					out.DebugModeEnabled = true
+				case tcg.DMAProtectionDisabled:
 					if digestVerify != nil {
 						return nil, fmt.Errorf("invalid digest for EFI Action 'DMA Protection Disabled' on event %d: %v", e.Num(), digestVerify)
 					}
@@ -155,7 +258,7 @@ func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts O
 				}
 
 			case tcg.EFIVariableDriverConfig:
-				v, err := tcg.ParseUEFIVariableData(bytes.NewReader(e.RawData()))
+				v, err := tcg.ParseVariableEvent(e)
 				if err != nil {
 					return nil, fmt.Errorf("failed parsing EFI variable at event %d: %v", e.Num(), err)
 				}
@@ -163,6 +266,7 @@ func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts O
 					return nil, fmt.Errorf("duplicate EFI variable %q at event %d", v.VarName(), e.Num())
 				}
 				seenVars[v.VarName()] = true
+				varSizes[v.VarName()] = len(v.VariableData)
 				if seenSeparator7 {
 					return nil, fmt.Errorf("event %d: variable %q specified after separator", e.Num(), v.VarName())
 				}
@@ -170,12 +274,15 @@ func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts O
 				if digestVerify != nil {
 					return nil, fmt.Errorf("invalid digest for variable %q on event %d: %v", v.VarName(), e.Num(), digestVerify)
 				}
+				if err := checkVariableVendor(v, compatLevel); err != nil {
+					return nil, fmt.Errorf("event %d: %v", e.Num(), err)
+				}
 
 				switch v.VarName() {
 				case "SecureBoot":
 					if len(v.VariableData) == 1 {
 						out.Enabled = v.VariableData[0] == 1
-					} else if len(v.VariableData) == 0 && opts.AllowEmptySBVar {
+					} else if len(v.VariableData) == 0 && policy.SecureBoot == OptionalVariable {
 						out.Enabled = false
 					} else {
 						return nil, fmt.Errorf("event %d: SecureBoot data len is %d, expected 1", e.Num(), len(v.VariableData))
@@ -185,25 +292,35 @@ func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts O
 					if out.PlatformKeys, out.PlatformKeyHashes, err = v.SignatureData(); err != nil {
 						return nil, fmt.Errorf("event %d: failed parsing platform keys: %v", e.Num(), err)
 					}
+					out.PlatformKeyEntries, _ = v.SignatureEntries()
 				case "KEK":
 					if out.ExchangeKeys, out.ExchangeKeyHashes, err = v.SignatureData(); err != nil {
 						return nil, fmt.Errorf("event %d: failed parsing key exchange keys: %v", e.Num(), err)
 					}
+					out.ExchangeKeyEntries, _ = v.SignatureEntries()
 				case "db":
 					if out.PermittedKeys, out.PermittedHashes, err = v.SignatureData(); err != nil {
 						return nil, fmt.Errorf("event %d: failed parsing signature database: %v", e.Num(), err)
 					}
+					out.PermittedEntries, _ = v.SignatureEntries()
 				case "dbx":
-					if out.ForbiddenKeys, out.ForbiddenHashes, err = v.SignatureData(); err != nil {
+					if compatLevel >= 2 {
+						out.ForbiddenKeys, out.ForbiddenHashes, out.ForbiddenErrors = v.PartialSignatureData()
+					} else if out.ForbiddenKeys, out.ForbiddenHashes, err = v.SignatureData(); err != nil {
 						return nil, fmt.Errorf("event %d: failed parsing forbidden signature database: %v", e.Num(), err)
 					}
+					// Malformed entries are already reported in ForbiddenErrors.
+					out.ForbiddenEntries, _ = v.SignatureEntries()
 				}
 
 			case tcg.EFIVariableAuthority:
-				v, err := tcg.ParseUEFIVariableData(bytes.NewReader(e.RawData()))
+				v, err := tcg.ParseVariableEvent(e)
 				if err != nil {
 					return nil, fmt.Errorf("failed parsing UEFI variable data: %v", err)
 				}
+				if err := checkVariableVendor(v, compatLevel); err != nil {
+					return nil, fmt.Errorf("event %d: %v", e.Num(), err)
+				}
 
 				a, err := tcg.ParseUEFIVariableAuthority(v)
 				if err != nil {
@@ -228,6 +345,11 @@ func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts O
 					out.PreSeparatorAuthority = append(out.PreSeparatorAuthority, a.Certs...)
 				} else {
 					out.PostSeparatorAuthority = append(out.PostSeparatorAuthority, a.Certs...)
+					out.PostSeparatorAuthorityUses = append(out.PostSeparatorAuthorityUses, AuthorityUse{
+						EventNum:     e.Num(),
+						VariableName: v.VarName(),
+						Certs:        a.Certs,
+					})
 				}
 
 			default:
@@ -244,7 +366,7 @@ func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts O
 					return nil, fmt.Errorf("duplicate separator at event %d", e.Num())
 				}
 				seenSeparator2 = true
-				if !bytes.Equal(e.RawData(), []byte{0, 0, 0, 0}) {
+				if !validSeparatorData(e.RawData(), quirks) {
 					return nil, fmt.Errorf("invalid separator data at event %d: %v", e.Num(), e.RawData())
 				}
 				if digestVerify != nil {
@@ -269,28 +391,24 @@ func ParseSecurebootState(events []tcg.Event, registerCfg registerConfig, opts O
 
 	// Compute driver source hints based on the EFI device path observed in
 	// EFI Boot-services driver-load events.
-sourceLoop:
 	for _, source := range driverSources {
-		// We consider a driver to have originated from PCI-MMIO if any number
-		// of elements in the device path [1] were PCI devices, and are followed by
-		// an element representing a "relative offset range" read.
-		// In the wild, we have typically observed 4-tuple device paths for such
-		// devices: ACPI device -> PCI device -> PCI device -> relative offset.
-		//
-		// [1]: See section 9 of the UEFI specification v2.6 or greater.
-		var seenPCI bool
-		for _, e := range source {
-			// subtype 0x1 corresponds to a PCI device (See: 9.3.2.1)
-			if e.Type == tcg.HardwareDevice && e.Subtype == 0x1 {
-				seenPCI = true
-			}
-			// subtype 0x8 corresponds to "relative offset range" (See: 9.3.6.8)
-			if seenPCI && e.Type == tcg.MediaDevice && e.Subtype == 0x8 {
-				out.DriverLoadSourceHints = append(out.DriverLoadSourceHints, PciMmioSource)
-				continue sourceLoop
-			}
+		if isPCIOptionROM(source) {
+			out.DriverLoadSourceHints = append(out.DriverLoadSourceHints, PciMmioSource)
+		} else {
+			out.DriverLoadSourceHints = append(out.DriverLoadSourceHints, UnknownSource)
+		}
+	}
+
+	for _, v := range policy.variables() {
+		if v.presence != RequiredVariable {
+			continue
+		}
+		if !seenVars[v.name] {
+			return nil, fmt.Errorf("required variable %q was not measured", v.name)
+		}
+		if varSizes[v.name] == 0 {
+			return nil, fmt.Errorf("required variable %q was measured empty", v.name)
 		}
-		out.DriverLoadSourceHints = append(out.DriverLoadSourceHints, UnknownSource)
 	}
 
 	if !out.Enabled {
@@ -300,14 +418,39 @@ sourceLoop:
 	if !seenAuthority {
 		return nil, errors.New("secure boot was enabled but no key was used")
 	}
-	if len(out.PlatformKeys) == 0 && len(out.PlatformKeyHashes) == 0 {
+	if len(out.PlatformKeys) == 0 && len(out.PlatformKeyHashes) == 0 && policy.PK != OptionalVariable {
 		return nil, errors.New("secure boot was enabled but no platform keys were known")
 	}
-	if len(out.ExchangeKeys) == 0 && len(out.ExchangeKeyHashes) == 0 {
+	if len(out.ExchangeKeys) == 0 && len(out.ExchangeKeyHashes) == 0 && policy.KEK != OptionalVariable {
 		return nil, errors.New("secure boot was enabled but no key exchange keys were known")
 	}
-	if len(out.PermittedKeys) == 0 && len(out.PermittedHashes) == 0 {
+	if len(out.PermittedKeys) == 0 && len(out.PermittedHashes) == 0 && policy.DB != OptionalVariable {
 		return nil, errors.New("secure boot was enabled but no keys or hashes were permitted")
 	}
 	return &out, nil
 }
+
+// variableVendors are the vendor GUIDs of the variables extracted by name, so
+// that a variable of another vendor with the same name is not mistaken for
+// them.
+var variableVendors = map[string]string{
+	"SecureBoot":     tcg.EFIGlobalVariableGUID,
+	"PK":             tcg.EFIGlobalVariableGUID,
+	"KEK":            tcg.EFIGlobalVariableGUID,
+	"db":             tcg.EFIImageSecurityDatabaseGUID,
+	"dbx":            tcg.EFIImageSecurityDatabaseGUID,
+	"MokList":        tcg.ShimLockGUID,
+	"MokListRT":      tcg.ShimLockGUID,
+	shimSbatLevelVar: tcg.ShimLockGUID,
+}
+
+// checkVariableVendor returns an error if v has the name of a variable in
+// variableVendors, but not its vendor GUID. Vendor GUIDs are not checked
+// below compat level 17.
+func checkVariableVendor(v tcg.UEFIVariableData, compatLevel uint32) error {
+	want, ok := variableVendors[v.VarName()]
+	if !ok || v.VendorGUID() == want || compatLevel < 17 {
+		return nil
+	}
+	return fmt.Errorf("variable %q has vendor GUID %s, want %s", v.VarName(), v.VendorGUID(), want)
+}
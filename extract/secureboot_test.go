@@ -29,6 +29,7 @@ import (
 	"github.com/google/go-eventlog/proto/state"
 	"github.com/google/go-eventlog/register"
 	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/testdata"
 )
 
 func TestSecureBoot(t *testing.T) {
@@ -357,3 +358,245 @@ func TestSecureBootAllowEmptySBVar(t *testing.T) {
 	}
 
 }
+
+// replaceVariable returns the events with the variable data replaced, or with
+// the variable event removed if remove is set.
+func replaceVariable(t *testing.T, events []tcg.Event, name string, newVar []byte, remove bool) []tcg.Event {
+	t.Helper()
+	var evts []tcg.Event
+	for _, evt := range events {
+		if evt.Type == tcg.EFIVariableDriverConfig {
+			v, err := tcg.ParseUEFIVariableData(bytes.NewReader(evt.RawData()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.VarName() == name {
+				if remove {
+					continue
+				}
+				v.VariableData = newVar
+				evt.Data, err = v.Encode()
+				if err != nil {
+					t.Fatal(err)
+				}
+				dgst := sha256.Sum256(evt.Data)
+				evt.Digest = dgst[:]
+			}
+		}
+		evts = append(evts, evt)
+	}
+	return evts
+}
+
+func TestSecureBootVariablePolicy(t *testing.T) {
+	el, err := tcg.ParseEventLog(testdata.Rhel8EventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	withVariable := func(name string, newVar []byte, remove bool) []tcg.Event {
+		return replaceVariable(t, el.Events(register.HashSHA256), name, newVar, remove)
+	}
+
+	tests := []struct {
+		name    string
+		evts    []tcg.Event
+		policy  extract.VariablePolicy
+		wantErr bool
+	}{
+		{
+			name: "default",
+			evts: withVariable("", nil, false),
+		},
+		{
+			name:    "emptyPKDefault",
+			evts:    withVariable("PK", nil, false),
+			wantErr: true,
+		},
+		{
+			name:   "emptyPKOptional",
+			evts:   withVariable("PK", nil, false),
+			policy: extract.VariablePolicy{PK: extract.OptionalVariable},
+		},
+		{
+			name:   "missingKEKOptional",
+			evts:   withVariable("KEK", nil, true),
+			policy: extract.VariablePolicy{KEK: extract.OptionalVariable},
+		},
+		{
+			name:    "missingKEKRequired",
+			evts:    withVariable("KEK", nil, true),
+			policy:  extract.VariablePolicy{KEK: extract.RequiredVariable},
+			wantErr: true,
+		},
+		{
+			name: "emptyDBXDefault",
+			evts: withVariable("dbx", nil, false),
+		},
+		{
+			name:    "emptyDBXRequired",
+			evts:    withVariable("dbx", nil, false),
+			policy:  extract.VariablePolicy{DBX: extract.RequiredVariable},
+			wantErr: true,
+		},
+		{
+			name: "missingSecureBootDefault",
+			evts: withVariable("SecureBoot", nil, true),
+		},
+		{
+			name:    "missingSecureBootRequired",
+			evts:    withVariable("SecureBoot", nil, true),
+			policy:  extract.VariablePolicy{SecureBoot: extract.RequiredVariable},
+			wantErr: true,
+		},
+		{
+			name:   "emptySecureBootOptional",
+			evts:   withVariable("SecureBoot", nil, false),
+			policy: extract.VariablePolicy{SecureBoot: extract.OptionalVariable},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := extract.ParseSecurebootState(tt.evts, extract.TPMRegisterConfig, extract.Opts{VariablePolicy: tt.policy})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSecurebootState() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecureBootDebugMode(t *testing.T) {
+	el, err := tcg.ParseEventLog(testdata.Rhel8EventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	dgst := sha256.Sum256([]byte(tcg.UEFIDebugMode))
+	debugEvent := tcg.Event{Index: 7, Type: tcg.EFIAction, Data: []byte(tcg.UEFIDebugMode), Digest: dgst[:]}
+	var evts []tcg.Event
+	for _, evt := range el.Events(register.HashSHA256) {
+		if evt.Index == 7 && evt.Type == tcg.Separator {
+			evts = append(evts, debugEvent)
+		}
+		evts = append(evts, evt)
+	}
+
+	if _, err := extract.ParseSecurebootState(evts, extract.TPMRegisterConfig, extract.Opts{}); err == nil {
+		t.Errorf("ParseSecurebootState(): got nil, want error for a UEFI debugger by default")
+	}
+	sbState, err := extract.ParseSecurebootState(evts, extract.TPMRegisterConfig, extract.Opts{AllowDebugMode: true})
+	if err != nil {
+		t.Fatalf("ParseSecurebootState(AllowDebugMode): %v", err)
+	}
+	if !sbState.DebugModeEnabled {
+		t.Errorf("ParseSecurebootState(): got DebugModeEnabled false, want true")
+	}
+
+	debugEvent.Digest = make([]byte, len(dgst))
+	for i, evt := range evts {
+		if evt.Type == tcg.EFIAction && evt.Index == 7 {
+			evts[i] = debugEvent
+		}
+	}
+	if _, err := extract.ParseSecurebootState(evts, extract.TPMRegisterConfig, extract.Opts{AllowDebugMode: true}); err == nil {
+		t.Errorf("ParseSecurebootState(bad debug mode digest): got nil, want error")
+	}
+}
+
+func TestSecureBootMalformedDBX(t *testing.T) {
+	el, err := tcg.ParseEventLog(testdata.Rhel8EventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	events := el.Events(register.HashSHA256)
+	var dbx tcg.UEFIVariableData
+	for _, evt := range events {
+		if evt.Type != tcg.EFIVariableDriverConfig {
+			continue
+		}
+		v, err := tcg.ParseUEFIVariableData(bytes.NewReader(evt.RawData()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.VarName() == "dbx" {
+			dbx = v
+		}
+	}
+	want, err := extract.ParseSecurebootState(events, extract.TPMRegisterConfig, extract.Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupt the DER of the first dbx certificate.
+	const listHeaderSize, ownerSize = 28, 16
+	corrupt := bytes.Clone(dbx.VariableData)
+	corrupt[listHeaderSize+ownerSize] ^= 0xff
+	events = replaceVariable(t, events, "dbx", corrupt, false)
+
+	got, err := extract.ParseSecurebootState(events, extract.TPMRegisterConfig, extract.Opts{})
+	if err != nil {
+		t.Fatalf("ParseSecurebootState(): %v", err)
+	}
+	if !got.Enabled || len(got.PermittedKeys) != len(want.PermittedKeys) {
+		t.Errorf("ParseSecurebootState() = enabled %v with %d db keys, want enabled with %d db keys", got.Enabled, len(got.PermittedKeys), len(want.PermittedKeys))
+	}
+	if len(got.ForbiddenKeys) != len(want.ForbiddenKeys)-1 || len(got.ForbiddenHashes) != len(want.ForbiddenHashes) {
+		t.Errorf("ParseSecurebootState() = %d dbx keys and %d dbx hashes, want %d and %d", len(got.ForbiddenKeys), len(got.ForbiddenHashes), len(want.ForbiddenKeys)-1, len(want.ForbiddenHashes))
+	}
+	if len(got.ForbiddenErrors) != 1 || got.ForbiddenErrors[0].Offset != listHeaderSize {
+		t.Errorf("ParseSecurebootState() = dbx errors %v, want one at offset %d", got.ForbiddenErrors, listHeaderSize)
+	}
+
+	sbState, err := extract.SecureBootState(events, extract.TPMRegisterConfig, extract.Opts{})
+	if err != nil {
+		t.Fatalf("SecureBootState(): %v", err)
+	}
+	if errs := sbState.GetDbxErrors(); len(errs) != 1 || errs[0].GetOffset() != listHeaderSize || errs[0].GetError() == "" {
+		t.Errorf("SecureBootState() = dbx errors %v, want one at offset %d", errs, listHeaderSize)
+	}
+
+	if _, err := extract.ParseSecurebootState(events, extract.TPMRegisterConfig, extract.Opts{CompatLevel: 1}); err == nil {
+		t.Errorf("ParseSecurebootState(CompatLevel 1) = got nil, want error")
+	}
+	if _, err := extract.ParseSecurebootStateLegacy(events); err == nil {
+		t.Errorf("ParseSecurebootStateLegacy() = got nil, want error")
+	}
+}
+
+func TestSecureBootVariableVendor(t *testing.T) {
+	el, err := tcg.ParseEventLog(testdata.Rhel8EventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	for _, name := range []string{"SecureBoot", "PK", "KEK", "db", "dbx"} {
+		t.Run(name, func(t *testing.T) {
+			var events []tcg.Event
+			for _, evt := range el.Events(register.HashSHA256) {
+				if evt.Type == tcg.EFIVariableDriverConfig {
+					v, err := tcg.ParseUEFIVariableData(bytes.NewReader(evt.RawData()))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if v.VarName() == name {
+						// A variable of another vendor with the same name.
+						other, err := tcg.NewUEFIVariableData(tcg.ShimLockGUID, name, v.VariableData)
+						if err != nil {
+							t.Fatal(err)
+						}
+						if evt.Data, err = other.Encode(); err != nil {
+							t.Fatal(err)
+						}
+						dgst := sha256.Sum256(evt.Data)
+						evt.Digest = dgst[:]
+					}
+				}
+				events = append(events, evt)
+			}
+			if _, err := extract.ParseSecurebootState(events, extract.TPMRegisterConfig, extract.Opts{}); err == nil {
+				t.Errorf("ParseSecurebootState(%s of another vendor) = got nil, want error", name)
+			}
+			// Vendor GUIDs are not checked below schema version 17.
+			if _, err := extract.ParseSecurebootState(events, extract.TPMRegisterConfig, extract.Opts{CompatLevel: 16}); err != nil {
+				t.Errorf("ParseSecurebootState(%s of another vendor, CompatLevel: 16): %v", name, err)
+			}
+		})
+	}
+}
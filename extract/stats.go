@@ -0,0 +1,66 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"math"
+	"sort"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// EventLogStats computes summary statistics about the events, such as the
+// number of events per register and type. The statistics describe the events
+// as logged and do not imply the events were verified.
+func EventLogStats(events []tcg.Event) *pb.EventLogStats {
+	type key struct {
+		index uint32
+		typ   uint32
+	}
+	counts := make(map[key]uint32)
+	var byteCounts [256]uint64
+	stats := &pb.EventLogStats{TotalEvents: uint32(len(events))}
+	for _, event := range events {
+		counts[key{event.MRIndex(), uint32(event.Type)}]++
+		if _, ok := event.Type.KnownName(); !ok {
+			stats.UnknownTypeEvents++
+		}
+		stats.TotalDataSize += uint64(len(event.Data))
+		for _, b := range event.Data {
+			byteCounts[b]++
+		}
+	}
+
+	for k, count := range counts {
+		stats.Counts = append(stats.Counts, &pb.EventCount{PcrIndex: k.index, UntrustedType: k.typ, Count: count})
+	}
+	sort.Slice(stats.Counts, func(i, j int) bool {
+		a, b := stats.Counts[i], stats.Counts[j]
+		if a.GetPcrIndex() != b.GetPcrIndex() {
+			return a.GetPcrIndex() < b.GetPcrIndex()
+		}
+		return a.GetUntrustedType() < b.GetUntrustedType()
+	})
+
+	for _, count := range byteCounts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / float64(stats.TotalDataSize)
+		stats.DataEntropy -= p * math.Log2(p)
+	}
+	return stats
+}
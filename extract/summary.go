@@ -0,0 +1,72 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"sort"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+// Summarize returns a compact summary of the state for metrics and logging
+// pipelines. The summary omits free-form event log content, e.g., kernel
+// command lines, and is deterministic: equal states have equal summaries,
+// with repeated fields in a stable order.
+func Summarize(state *pb.FirmwareLogState) *pb.FirmwareLogSummary {
+	sb := state.GetSecureBoot()
+	efi := state.GetEfi()
+	kernel := state.GetLinuxKernel()
+	summary := &pb.FirmwareLogSummary{
+		SchemaVersion:              state.GetSchemaVersion(),
+		LogType:                    state.GetLogType(),
+		Hash:                       state.GetHash(),
+		Technology:                 state.GetPlatform().GetTechnology(),
+		SecureBootEnabled:          sb.GetEnabled(),
+		SecureBootDebugModeEnabled: sb.GetDebugModeEnabled(),
+		DbCerts:                    uint32(len(sb.GetDb().GetCerts())),
+		DbHashes:                   uint32(len(sb.GetDb().GetHashes())),
+		DbxCerts:                   uint32(len(sb.GetDbx().GetCerts())),
+		DbxHashes:                  uint32(len(sb.GetDbx().GetHashes())),
+		AuthorityCerts:             uint32(len(sb.GetAuthority().GetCerts())),
+		EfiDrivers:                 uint32(len(efi.GetBootServicesDrivers()) + len(efi.GetRuntimeServicesDrivers())),
+		Grub:                       state.GetGrub() != nil,
+		Uki:                        state.GetUki() != nil,
+		Windows:                    state.GetWindowsSipa() != nil,
+		Drtm:                       state.GetDrtm() != nil,
+		KernelDigest:               kernel.GetKernelDigest(),
+		InitrdDigest:               kernel.GetInitrdDigest(),
+		LoadOptionsMatch:           kernel.GetLoadOptionsMatch(),
+		KexecCount:                 uint32(len(state.GetKexec())),
+		TotalEvents:                state.GetStats().GetTotalEvents(),
+		UnknownTypeEvents:          state.GetStats().GetUnknownTypeEvents(),
+		QuirkProfile:               state.GetQuirkProfile(),
+	}
+	for _, app := range efi.GetApps() {
+		summary.EfiAppDigests = append(summary.EfiAppDigests, app.GetDigest())
+	}
+
+	seen := make(map[pb.FindingType]bool)
+	for _, finding := range state.GetFindings() {
+		if !seen[finding.GetType()] {
+			seen[finding.GetType()] = true
+			summary.FindingTypes = append(summary.FindingTypes, finding.GetType())
+		}
+	}
+	sort.Slice(summary.FindingTypes, func(i, j int) bool { return summary.FindingTypes[i] < summary.FindingTypes[j] })
+
+	summary.AppliedQuirks = append(summary.AppliedQuirks, state.GetAppliedQuirks()...)
+	sort.Strings(summary.AppliedQuirks)
+	return summary
+}
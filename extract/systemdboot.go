@@ -0,0 +1,98 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// systemdKernelConfigPCR is the PCR that systemd-boot 254 and later measure
+// the kernel command line into. Earlier versions measure it into PCR 8, the
+// GRUB command register.
+const systemdKernelConfigPCR = 12
+
+// LinuxKernelStateFromSystemdBoot extracts the kernel command line and kernel
+// digest from the events measured by systemd-boot and the firmware.
+//
+// systemd-boot measures the kernel command line of the boot entry as an EV_IPL
+// event holding the UTF-16 command line, in the GRUB command register or, for
+// TPM event logs, PCR 12. Only command lines matching their event digest are
+// extracted. The kernel digest is the digest of the last EFI application the
+// firmware started before ExitBootServices(), i.e., the kernel EFI stub or
+// unified kernel image started by systemd-boot.
+func LinuxKernelStateFromSystemdBoot(hash crypto.Hash, events []tcg.Event, registerCfg RegisterConfig) (*pb.LinuxKernelState, error) {
+	var kernel pb.LinuxKernelState
+	seen := false
+	for _, event := range events {
+		index := event.MRIndex()
+		isCmdIdx := index == registerCfg.GRUBCmdIdx ||
+			(registerCfg.LogType == pb.LogType_LOG_TYPE_TCG2 && index == systemdKernelConfigPCR)
+		if event.UntrustedType() != tcg.Ipl || !isCmdIdx {
+			continue
+		}
+		cmdline, ok := systemdCommandLine(hash, event)
+		if !ok {
+			continue
+		}
+		// The Linux EFI stub of a unified kernel image may measure the same
+		// command line again.
+		if seen && cmdline != kernel.CommandLine {
+			return nil, fmt.Errorf("more than one kernel command line in systemd-boot events: %q and %q", kernel.CommandLine, cmdline)
+		}
+		seen = true
+		kernel.CommandLine = cmdline
+	}
+
+	ebsPos := exitBootServicesPosition(hash, events, registerCfg)
+	for i, event := range events {
+		if ebsPos != -1 && i > ebsPos {
+			break
+		}
+		if event.MRIndex() == registerCfg.EFIAppIdx && event.UntrustedType() == tcg.EFIBootServicesApplication {
+			kernel.KernelDigest = event.ReplayedDigest()
+		}
+	}
+	if !seen && kernel.KernelDigest == nil {
+		return nil, errors.New("no systemd-boot measurements found")
+	}
+	return &kernel, nil
+}
+
+// systemdCommandLine decodes the UTF-16 kernel command line of a systemd-boot
+// EV_IPL event, and reports whether it matches the event digest. Some
+// versions of systemd-boot measure the NUL-terminated command line, but only
+// log the first byte of the NUL terminator.
+func systemdCommandLine(hash crypto.Hash, event tcg.Event) (string, bool) {
+	cmdline := decodeUTF16(event.RawData())
+	if cmdline == "" {
+		return "", false
+	}
+	encoded := encodeUTF16(cmdline)
+	for _, candidate := range [][]byte{encoded, append(bytes.Clone(encoded), 0, 0)} {
+		h := hash.New()
+		h.Write(candidate)
+		if subtle.ConstantTimeCompare(h.Sum(nil), event.ReplayedDigest()) == 1 {
+			return cmdline, true
+		}
+	}
+	return "", false
+}
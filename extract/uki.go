@@ -0,0 +1,96 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// ukiPCR is the PCR that systemd-stub measures the UKI sections into, and
+// systemd-pcrphase measures the boot phases into.
+const ukiPCR = 11
+
+// UkiState extracts the unified kernel image (UKI) state from the EV_IPL
+// events in PCR 11 of a TPM event log. It returns nil if there are no UKI
+// events.
+//
+// systemd-stub measures each PE section of the UKI as two events, both
+// described by the section name: one whose digest is that of the
+// NUL-terminated section name, and one whose digest is that of the section
+// contents. systemd-pcrphase measures each boot phase as an event whose
+// digest is that of the phase string. Section names and phases are only
+// extracted if their digest matches the event data, so the state only holds
+// measured content. Other events are ignored.
+//
+// The booted OS can also extend PCR 11, so the sections are not necessarily
+// those of the booted UKI. Use BootedKernelDigests for the booted kernel.
+func UkiState(hash crypto.Hash, events []tcg.Event) (*pb.UkiState, error) {
+	var state pb.UkiState
+	found := false
+	// section is the section whose contents are measured next, if any.
+	var section string
+	for _, event := range events {
+		if event.UntrustedType() != tcg.Ipl || event.MRIndex() != ukiPCR {
+			continue
+		}
+		description := ukiDescription(event.RawData())
+		if section != "" {
+			if description != section {
+				return nil, fmt.Errorf("event %d: found %q after the name of UKI section %q, want its contents", event.Num(), description, section)
+			}
+			state.Sections = append(state.Sections, &pb.UkiSection{Name: section, Digest: event.ReplayedDigest()})
+			section = ""
+			continue
+		}
+		switch {
+		case strings.HasPrefix(description, ".") && digestMatches(hash, description+"\x00", event.ReplayedDigest()):
+			section = description
+		case description != "" && digestMatches(hash, description, event.ReplayedDigest()):
+			state.Phases = append(state.Phases, description)
+		default:
+			continue
+		}
+		found = true
+	}
+	if section != "" {
+		return nil, fmt.Errorf("found the name of UKI section %q without its contents", section)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// ukiDescription decodes the description of a UKI event. systemd-stub logs
+// NUL-terminated UTF-16 descriptions, and systemd-pcrphase logs ASCII
+// strings.
+func ukiDescription(data []byte) string {
+	if len(data) >= 2 && len(data)%2 == 0 && data[1] == 0 {
+		return decodeUTF16(data)
+	}
+	return strings.TrimRight(string(data), "\x00")
+}
+
+func digestMatches(hash crypto.Hash, measured string, digest []byte) bool {
+	h := hash.New()
+	h.Write([]byte(measured))
+	return subtle.ConstantTimeCompare(h.Sum(nil), digest) == 1
+}
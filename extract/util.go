@@ -15,14 +15,15 @@
 package extract
 
 import (
-	"bytes"
 	"crypto"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash"
+	"unicode"
 
 	"github.com/google/go-eventlog/tcg"
 )
@@ -38,17 +39,17 @@ func DigestEquals(e tcg.Event, b []byte) error {
 	case crypto.SHA384.Size():
 		hasher := crypto.SHA384.New()
 		hasher.Write(b)
-		if bytes.Equal(hasher.Sum(nil), digest) {
+		if subtle.ConstantTimeCompare(hasher.Sum(nil), digest) == 1 {
 			return nil
 		}
 	case crypto.SHA256.Size():
 		s := sha256.Sum256(b)
-		if bytes.Equal(s[:], digest) {
+		if subtle.ConstantTimeCompare(s[:], digest) == 1 {
 			return nil
 		}
 	case crypto.SHA1.Size():
 		s := sha1.Sum(b)
-		if bytes.Equal(s[:], digest) {
+		if subtle.ConstantTimeCompare(s[:], digest) == 1 {
 			return nil
 		}
 	default:
@@ -79,8 +80,20 @@ func verifyDataDigest(hasher hash.Hash, data []byte, digest []byte) error {
 	hasher.Reset()
 	hasher.Write(data)
 	defer hasher.Reset()
-	if !bytes.Equal(digest, hasher.Sum(nil)) {
+	if subtle.ConstantTimeCompare(digest, hasher.Sum(nil)) != 1 {
 		return fmt.Errorf("invalid digest: %s", hex.EncodeToString(digest))
 	}
 	return nil
 }
+
+// printableDescription returns the descriptor string of an event, e.g.,
+// "MokList", or "" if the event data is not a printable string.
+func printableDescription(data []byte) string {
+	description := ukiDescription(data)
+	for _, r := range description {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return ""
+		}
+	}
+	return description
+}
@@ -15,6 +15,7 @@
 package extract
 
 import (
+	"bytes"
 	"crypto"
 	"testing"
 )
@@ -57,3 +58,17 @@ func TestNullTerminatedDataDigest(t *testing.T) {
 		t.Errorf("len() == 0 should always fail")
 	}
 }
+
+func TestGetSeparatorInfo(t *testing.T) {
+	info := getSeparatorInfo(crypto.SHA256)
+	if getSeparatorInfo(crypto.SHA256) != info {
+		t.Errorf("getSeparatorInfo() was not memoized")
+	}
+	for i, data := range info.separatorData {
+		hasher := crypto.SHA256.New()
+		hasher.Write(data)
+		if !bytes.Equal(info.separatorDigests[i], hasher.Sum(nil)) {
+			t.Errorf("getSeparatorInfo() = got digest %x for data %x, want its SHA-256 digest", info.separatorDigests[i], data)
+		}
+	}
+}
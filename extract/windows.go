@@ -0,0 +1,187 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package extract
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// windowsSipaIdxs are the PCRs that Windows measures SIPA events into.
+var windowsSipaIdxs = map[uint32]bool{11: true, 12: true, 13: true, 14: true}
+
+// WindowsSipaState extracts the Windows boot state from the SIPA events that
+// Windows measures as EV_EVENT_TAG events in PCRs 11 to 14 of a TPM event
+// log. It returns nil if there are no SIPA events.
+//
+// The event data must match the replayed digest, as Windows measures the
+// digest of the SIPA events, so that the state only holds measured content.
+// Events holding only non-measured SIPA events are skipped.
+func WindowsSipaState(hash crypto.Hash, events []tcg.Event) (*pb.WindowsSipaState, error) {
+	var state pb.WindowsSipaState
+	found := false
+	for _, event := range events {
+		if event.UntrustedType() != tcg.EventTag || !windowsSipaIdxs[event.MRIndex()] {
+			continue
+		}
+		sipaEvents, err := tcg.ParseSipaEvents(event.RawData())
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %v", event.Num(), err)
+		}
+		h := hash.New()
+		h.Write(event.RawData())
+		if subtle.ConstantTimeCompare(h.Sum(nil), event.ReplayedDigest()) != 1 {
+			if allNonMeasured(sipaEvents) {
+				continue
+			}
+			return nil, fmt.Errorf("event %d: SIPA event data does not match the event digest", event.Num())
+		}
+		for _, sipa := range sipaEvents {
+			if err := addSipaEvent(&state, sipa); err != nil {
+				return nil, fmt.Errorf("event %d: %v", event.Num(), err)
+			}
+		}
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func allNonMeasured(events []tcg.SipaEvent) bool {
+	for _, event := range events {
+		if event.Type&tcg.SipaNonMeasured == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// addSipaEvent adds a SIPA event, and any events it holds, to the state.
+// Non-measured events, and event types without a field in the state, are
+// skipped.
+func addSipaEvent(state *pb.WindowsSipaState, event tcg.SipaEvent) error {
+	if event.Type&tcg.SipaNonMeasured != 0 {
+		return nil
+	}
+	var err error
+	switch event.Type {
+	case tcg.SipaLoadedModuleAggregation:
+		var module *pb.WindowsLoadedModule
+		if module, err = loadedModule(event.Children); err == nil {
+			state.LoadedModules = append(state.LoadedModules, module)
+		}
+	case tcg.SipaBootCounter:
+		state.BootCount, err = sipaUint(event)
+	case tcg.SipaBootDebugging:
+		state.BootDebuggingEnabled, err = sipaBool(event)
+	case tcg.SipaOSKernelDebug:
+		state.KernelDebugEnabled, err = sipaBool(event)
+	case tcg.SipaCodeIntegrity:
+		state.CodeIntegrityEnabled, err = sipaBool(event)
+	case tcg.SipaTestSigning:
+		state.TestSigningEnabled, err = sipaBool(event)
+	case tcg.SipaDataExecutionPrevention:
+		var policy uint64
+		policy, err = sipaUint(event)
+		state.DepEnabled = policy != 0
+	case tcg.SipaSafeMode:
+		state.SafeMode, err = sipaBool(event)
+	case tcg.SipaWinPE:
+		state.Winpe, err = sipaBool(event)
+	case tcg.SipaHypervisorLaunchType:
+		state.HypervisorLaunchType, err = sipaUint(event)
+	case tcg.SipaBitLockerUnlock:
+		var flags uint64
+		flags, err = sipaUint(event)
+		state.BitlockerUnlocks = append(state.BitlockerUnlocks, uint32(flags))
+	default:
+		for _, child := range event.Children {
+			if err := addSipaEvent(state, child); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// loadedModule parses the events of a SIPAEVENT_LOADEDMODULE_AGGREGATION.
+func loadedModule(events []tcg.SipaEvent) (*pb.WindowsLoadedModule, error) {
+	var module pb.WindowsLoadedModule
+	for _, event := range events {
+		var (
+			n   uint64
+			err error
+		)
+		switch event.Type {
+		case tcg.SipaFilePath:
+			module.FilePath = sipaString(event)
+		case tcg.SipaImageSize:
+			module.ImageSize, err = sipaUint(event)
+		case tcg.SipaHashAlgorithmID:
+			n, err = sipaUint(event)
+			module.HashAlgorithmId = uint32(n)
+		case tcg.SipaAuthenticodeHash:
+			module.AuthenticodeHash = event.Data
+		case tcg.SipaAuthorityIssuer:
+			module.AuthorityIssuer = sipaString(event)
+		case tcg.SipaAuthorityPublisher:
+			module.AuthorityPublisher = sipaString(event)
+		case tcg.SipaAuthoritySerial:
+			module.AuthoritySerial = event.Data
+		case tcg.SipaAuthoritySHA1Thumbprint:
+			module.AuthoritySha1Thumbprint = event.Data
+		case tcg.SipaImageValidated:
+			module.ImageValidated, err = sipaBool(event)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &module, nil
+}
+
+func sipaBool(event tcg.SipaEvent) (bool, error) {
+	if len(event.Data) != 1 {
+		return false, fmt.Errorf("%v: got %d bytes, want a 1-byte boolean", event.Type, len(event.Data))
+	}
+	return event.Data[0] != 0, nil
+}
+
+// sipaUint parses a little-endian unsigned integer of 1, 2, 4, or 8 bytes.
+func sipaUint(event tcg.SipaEvent) (uint64, error) {
+	switch len(event.Data) {
+	case 1:
+		return uint64(event.Data[0]), nil
+	case 2:
+		return uint64(binary.LittleEndian.Uint16(event.Data)), nil
+	case 4:
+		return uint64(binary.LittleEndian.Uint32(event.Data)), nil
+	case 8:
+		return binary.LittleEndian.Uint64(event.Data), nil
+	}
+	return 0, fmt.Errorf("%v: got %d bytes, want an integer of 1, 2, 4, or 8 bytes", event.Type, len(event.Data))
+}
+
+// sipaString decodes the NUL-terminated UTF-16LE string of a SIPA event.
+func sipaString(event tcg.SipaEvent) string {
+	return decodeUTF16(event.Data)
+}
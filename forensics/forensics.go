@@ -0,0 +1,542 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package forensics explains attestation failures. It compares the
+// FirmwareLogState extracted from the event log of a machine that failed
+// attestation to a baseline state of a known-good boot, and turns the
+// differences into a ranked list of likely causes, e.g., a firmware update or
+// a dbx update. Causes are ranked by the mismatched measurement registers
+// they explain, if given, and then in boot chain order.
+//
+// Both states must be extracted from verified event logs. A state extracted
+// from an event log that does not replay against the measurement registers
+// can not be trusted to explain anything.
+package forensics
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-eventlog/extract"
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+const (
+	// firmwareIdx holds the platform firmware measurements: PCR 0 for TPMs,
+	// and MRTD (CC MR 0) for confidential computing.
+	firmwareIdx = 0
+	// ukiIdx holds the UKI section measurements of systemd-stub in TPM event
+	// logs.
+	ukiIdx = 11
+)
+
+// CauseKind is the kind of a Cause.
+type CauseKind int
+
+// Cause kinds, in boot chain order. Causes that explain the same number of
+// mismatched registers rank in this order, as changes earlier in the boot
+// chain often cascade into the later changes, e.g., a firmware update may
+// also update the dbx and the EFI drivers.
+const (
+	// FirmwareUpdate means the platform firmware version changed.
+	FirmwareUpdate CauseKind = iota
+	// SecureBootDisabled means Secure Boot was enabled for the baseline, but
+	// not for the failed boot.
+	SecureBootDisabled
+	// DbxUpdate means the forbidden signature database changed.
+	DbxUpdate
+	// SecureBootKeysChange means the PK, KEK, or db changed.
+	SecureBootKeysChange
+	// NewEFIDriver means the failed boot loaded EFI drivers, e.g., from an
+	// option ROM, that the baseline did not.
+	NewEFIDriver
+	// BootloaderChange means the EFI applications or the files GRUB loaded
+	// changed.
+	BootloaderChange
+	// KernelChange means the kernel, the initrd, or the UKI changed.
+	KernelChange
+	// KernelCmdlineChange means the kernel command line changed.
+	KernelCmdlineChange
+	// BootConfigChange means the boot order or the boot options changed.
+	BootConfigChange
+)
+
+func (k CauseKind) String() string {
+	switch k {
+	case FirmwareUpdate:
+		return "firmware update"
+	case SecureBootDisabled:
+		return "Secure Boot disabled"
+	case DbxUpdate:
+		return "dbx update"
+	case SecureBootKeysChange:
+		return "Secure Boot keys change"
+	case NewEFIDriver:
+		return "new EFI driver"
+	case BootloaderChange:
+		return "bootloader change"
+	case KernelChange:
+		return "kernel change"
+	case KernelCmdlineChange:
+		return "kernel command line change"
+	case BootConfigChange:
+		return "boot config change"
+	}
+	return fmt.Sprintf("CauseKind(%d)", int(k))
+}
+
+// Cause is a likely cause of an attestation failure.
+type Cause struct {
+	Kind CauseKind
+	// Description summarizes the change, e.g., "firmware version changed from
+	// 1 to 2".
+	Description string
+	// Details lists the individual changes, e.g., the added dbx entries.
+	Details []string
+	// Registers are the measurement registers, as encoded in the event log,
+	// that hold the changed measurements.
+	Registers []uint32
+	// MismatchedRegisters are the Registers in Opts.MismatchedRegisters, i.e.,
+	// the register mismatches the cause explains.
+	MismatchedRegisters []uint32
+}
+
+func (c Cause) String() string {
+	return fmt.Sprintf("%v: %s", c.Kind, c.Description)
+}
+
+// Opts gives options for AnalyzeWithOpts.
+type Opts struct {
+	// MismatchedRegisters are the measurement registers, as encoded in the
+	// event log, whose values caused the attestation failure, e.g., the PCRs
+	// that did not match a policy. Causes that changed more of them rank
+	// first, and causes that changed none of them rank last.
+	MismatchedRegisters []uint32
+	// RegisterConfig maps the causes to measurement registers. If nil,
+	// extract.RTMRRegisterConfig is used for confidential computing event
+	// logs, and extract.TPMRegisterConfig otherwise.
+	RegisterConfig *extract.RegisterConfig
+}
+
+// Analyze compares the state of a failed boot to a baseline state, and returns
+// the likely causes of the failure, most likely first. It returns no causes
+// if the states have no difference it can explain.
+//
+// Without the mismatched registers, the causes are ranked in boot chain
+// order. Use AnalyzeWithOpts to rank them by the registers they explain.
+//
+// Digests are only comparable if both states were extracted from the same
+// bank, so Analyze returns an error if the states have different hashes.
+func Analyze(baseline, failed *pb.FirmwareLogState) ([]Cause, error) {
+	return AnalyzeWithOpts(baseline, failed, Opts{})
+}
+
+// AnalyzeWithOpts is Analyze with options. It ranks the causes by the number
+// of Opts.MismatchedRegisters they explain, then in boot chain order.
+func AnalyzeWithOpts(baseline, failed *pb.FirmwareLogState, opts Opts) ([]Cause, error) {
+	if baseline.GetHash() != failed.GetHash() {
+		return nil, fmt.Errorf("states were extracted from different banks: baseline %v, failed %v", baseline.GetHash(), failed.GetHash())
+	}
+	registerCfg := extract.TPMRegisterConfig
+	if opts.RegisterConfig != nil {
+		registerCfg = *opts.RegisterConfig
+	} else if failed.GetLogType() == pb.LogType_LOG_TYPE_CC {
+		registerCfg = extract.RTMRRegisterConfig
+	}
+	var causes []Cause
+	for _, analyzer := range []func(baseline, failed *pb.FirmwareLogState, registerCfg extract.RegisterConfig) []Cause{
+		firmwareCauses,
+		secureBootCauses,
+		efiDriverCauses,
+		bootloaderCauses,
+		kernelCauses,
+		bootConfigCauses,
+	} {
+		causes = append(causes, analyzer(baseline, failed, registerCfg)...)
+	}
+
+	mismatched := make(map[uint32]bool)
+	for _, idx := range opts.MismatchedRegisters {
+		mismatched[idx] = true
+	}
+	for i := range causes {
+		for _, idx := range causes[i].Registers {
+			if mismatched[idx] {
+				causes[i].MismatchedRegisters = append(causes[i].MismatchedRegisters, idx)
+			}
+		}
+	}
+	sort.SliceStable(causes, func(i, j int) bool {
+		if a, b := len(causes[i].MismatchedRegisters), len(causes[j].MismatchedRegisters); a != b {
+			return a > b
+		}
+		return causes[i].Kind < causes[j].Kind
+	})
+	return causes, nil
+}
+
+// addRegister adds idx to the registers, if it is not already in them, as
+// several kinds of measurements may share a register, e.g., an RTMR.
+func addRegister(registers []uint32, idx uint32) []uint32 {
+	for _, r := range registers {
+		if r == idx {
+			return registers
+		}
+	}
+	return append(registers, idx)
+}
+
+func firmwareCauses(baseline, failed *pb.FirmwareLogState, _ extract.RegisterConfig) []Cause {
+	from, to := firmwareVersion(baseline.GetPlatform()), firmwareVersion(failed.GetPlatform())
+	if from == to {
+		return nil
+	}
+	return []Cause{{
+		Kind:        FirmwareUpdate,
+		Description: fmt.Sprintf("firmware version changed from %s to %s", from, to),
+		Registers:   []uint32{firmwareIdx},
+	}}
+}
+
+func firmwareVersion(platform *pb.PlatformState) string {
+	switch firmware := platform.GetFirmware().(type) {
+	case *pb.PlatformState_GceVersion:
+		return fmt.Sprintf("GCE version %d", firmware.GceVersion)
+	case *pb.PlatformState_ScrtmVersionId:
+		return fmt.Sprintf("S-CRTM version %q", firmware.ScrtmVersionId)
+	}
+	return "unknown"
+}
+
+func secureBootCauses(baseline, failed *pb.FirmwareLogState, registerCfg extract.RegisterConfig) []Cause {
+	from, to := baseline.GetSecureBoot(), failed.GetSecureBoot()
+	registers := []uint32{registerCfg.SecureBootIdx}
+	var causes []Cause
+	if from.GetEnabled() && !to.GetEnabled() {
+		causes = append(causes, Cause{
+			Kind:        SecureBootDisabled,
+			Description: "Secure Boot was disabled",
+			Registers:   registers,
+		})
+	}
+	if details := databaseChanges("dbx", from.GetDbx(), to.GetDbx()); len(details) > 0 {
+		causes = append(causes, Cause{
+			Kind:        DbxUpdate,
+			Description: fmt.Sprintf("dbx changed from %d to %d entries", databaseSize(from.GetDbx()), databaseSize(to.GetDbx())),
+			Details:     details,
+			Registers:   registers,
+		})
+	}
+	var details []string
+	details = append(details, databaseChanges("PK", from.GetPk(), to.GetPk())...)
+	details = append(details, databaseChanges("KEK", from.GetKek(), to.GetKek())...)
+	details = append(details, databaseChanges("db", from.GetDb(), to.GetDb())...)
+	if len(details) > 0 {
+		causes = append(causes, Cause{
+			Kind:        SecureBootKeysChange,
+			Description: fmt.Sprintf("%d Secure Boot key or db entries changed", len(details)),
+			Details:     details,
+			Registers:   registers,
+		})
+	}
+	return causes
+}
+
+func databaseSize(db *pb.Database) int {
+	return len(db.GetCerts()) + len(db.GetHashes())
+}
+
+// databaseChanges describes the certificates and hashes added to and removed
+// from a signature database.
+func databaseChanges(name string, from, to *pb.Database) []string {
+	fromEntries, toEntries := databaseEntries(from), databaseEntries(to)
+	var changes []string
+	for _, entry := range sortedKeys(toEntries) {
+		if _, ok := fromEntries[entry]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: added %s", name, toEntries[entry]))
+		}
+	}
+	for _, entry := range sortedKeys(fromEntries) {
+		if _, ok := toEntries[entry]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: removed %s", name, fromEntries[entry]))
+		}
+	}
+	return changes
+}
+
+// databaseEntries maps the certificates and hashes of a signature database to
+// their descriptions.
+func databaseEntries(db *pb.Database) map[string]string {
+	entries := make(map[string]string)
+	for _, cert := range db.GetCerts() {
+		if wk := cert.GetWellKnown(); wk != pb.WellKnownCertificate_UNKNOWN {
+			entries["well-known:"+wk.String()] = fmt.Sprintf("certificate %v", wk)
+			continue
+		}
+		fingerprint := sha256.Sum256(cert.GetDer())
+		desc := fmt.Sprintf("certificate with SHA-256 fingerprint %x", fingerprint)
+		if subject := cert.GetMetadata().GetSubject(); subject != "" {
+			desc = fmt.Sprintf("certificate %q", subject)
+		}
+		entries["der:"+string(cert.GetDer())] = desc
+	}
+	for _, hash := range db.GetHashes() {
+		entries["hash:"+string(hash)] = fmt.Sprintf("hash %x", hash)
+	}
+	return entries
+}
+
+func efiDriverCauses(baseline, failed *pb.FirmwareLogState, registerCfg extract.RegisterConfig) []Cause {
+	known := make(map[string]bool)
+	for _, driver := range efiDrivers(baseline.GetEfi()) {
+		known[string(driver.GetDigest())] = true
+	}
+	var details []string
+	for _, driver := range efiDrivers(failed.GetEfi()) {
+		if known[string(driver.GetDigest())] {
+			continue
+		}
+		detail := fmt.Sprintf("driver %x", driver.GetDigest())
+		if path := driver.GetUntrustedDevicePath(); path != "" {
+			detail += " from " + path
+		}
+		if driver.GetUntrustedOptionRom() {
+			detail += " (option ROM)"
+		}
+		details = append(details, detail)
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return []Cause{{
+		Kind:        NewEFIDriver,
+		Description: fmt.Sprintf("%d EFI drivers not loaded by the baseline", len(details)),
+		Details:     details,
+		Registers:   []uint32{registerCfg.FirmwareDriverIdx},
+	}}
+}
+
+// efiDrivers returns the EFI drivers of an EfiState. States extracted before
+// the drivers field was added only have the boot and runtime services drivers.
+func efiDrivers(efi *pb.EfiState) []*pb.EfiDriver {
+	if len(efi.GetDrivers()) > 0 {
+		return efi.GetDrivers()
+	}
+	var drivers []*pb.EfiDriver
+	for _, app := range efi.GetBootServicesDrivers() {
+		drivers = append(drivers, &pb.EfiDriver{Digest: app.GetDigest(), UntrustedDevicePath: app.GetUntrustedDevicePath()})
+	}
+	for _, app := range efi.GetRuntimeServicesDrivers() {
+		drivers = append(drivers, &pb.EfiDriver{Digest: app.GetDigest(), Runtime: true, UntrustedDevicePath: app.GetUntrustedDevicePath()})
+	}
+	return drivers
+}
+
+func bootloaderCauses(baseline, failed *pb.FirmwareLogState, registerCfg extract.RegisterConfig) []Cause {
+	var details []string
+	var registers []uint32
+	fromApps, toApps := baseline.GetEfi().GetApps(), failed.GetEfi().GetApps()
+	n := len(fromApps)
+	if len(toApps) > n {
+		n = len(toApps)
+	}
+	for i := 0; i < n; i++ {
+		switch {
+		case i >= len(fromApps):
+			details = append(details, fmt.Sprintf("EFI app %d added: %s", i, appString(toApps[i])))
+		case i >= len(toApps):
+			details = append(details, fmt.Sprintf("EFI app %d removed: %s", i, appString(fromApps[i])))
+		case !bytes.Equal(fromApps[i].GetDigest(), toApps[i].GetDigest()):
+			details = append(details, fmt.Sprintf("EFI app %d changed from %s to %s", i, appString(fromApps[i]), appString(toApps[i])))
+		}
+	}
+	if len(details) > 0 {
+		registers = append(registers, registerCfg.EFIAppIdx)
+	}
+
+	appChanges := len(details)
+	fromFiles := make(map[string][]byte)
+	for _, file := range baseline.GetGrub().GetFiles() {
+		fromFiles[string(file.GetUntrustedFilename())] = file.GetDigest()
+	}
+	toFiles := make(map[string]bool)
+	for _, file := range failed.GetGrub().GetFiles() {
+		name := string(file.GetUntrustedFilename())
+		toFiles[name] = true
+		digest, ok := fromFiles[name]
+		switch {
+		case !ok:
+			details = append(details, fmt.Sprintf("GRUB loaded new file %s", name))
+		case !bytes.Equal(digest, file.GetDigest()):
+			details = append(details, fmt.Sprintf("GRUB file %s changed from %x to %x", name, digest, file.GetDigest()))
+		}
+	}
+	for _, file := range baseline.GetGrub().GetFiles() {
+		if name := string(file.GetUntrustedFilename()); !toFiles[name] {
+			details = append(details, fmt.Sprintf("GRUB no longer loaded file %s", name))
+		}
+	}
+	if len(details) > appChanges {
+		registers = addRegister(registers, registerCfg.GRUBFileIdx)
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return []Cause{{
+		Kind:        BootloaderChange,
+		Description: fmt.Sprintf("%d bootloader measurements changed", len(details)),
+		Details:     details,
+		Registers:   registers,
+	}}
+}
+
+func appString(app *pb.EfiApp) string {
+	if path := app.GetUntrustedFilePath(); path != "" {
+		return fmt.Sprintf("%s (%x)", path, app.GetDigest())
+	}
+	return fmt.Sprintf("%x", app.GetDigest())
+}
+
+func kernelCauses(baseline, failed *pb.FirmwareLogState, registerCfg extract.RegisterConfig) []Cause {
+	from, to := baseline.GetLinuxKernel(), failed.GetLinuxKernel()
+	var details []string
+	var registers []uint32
+	if !bytes.Equal(from.GetKernelDigest(), to.GetKernelDigest()) {
+		details = append(details, fmt.Sprintf("kernel changed from %x to %x", from.GetKernelDigest(), to.GetKernelDigest()))
+	}
+	if !bytes.Equal(from.GetInitrdDigest(), to.GetInitrdDigest()) {
+		details = append(details, fmt.Sprintf("initrd changed from %x to %x", from.GetInitrdDigest(), to.GetInitrdDigest()))
+	}
+	if len(details) > 0 {
+		registers = append(registers, registerCfg.GRUBFileIdx)
+	}
+
+	kernelChanges := len(details)
+	fromSections := make(map[string][]byte)
+	for _, section := range baseline.GetUki().GetSections() {
+		fromSections[section.GetName()] = section.GetDigest()
+	}
+	toSections := make(map[string]bool)
+	for _, section := range failed.GetUki().GetSections() {
+		toSections[section.GetName()] = true
+		digest, ok := fromSections[section.GetName()]
+		switch {
+		case !ok:
+			details = append(details, fmt.Sprintf("UKI section %s added: %x", section.GetName(), section.GetDigest()))
+		case !bytes.Equal(digest, section.GetDigest()):
+			details = append(details, fmt.Sprintf("UKI section %s changed from %x to %x", section.GetName(), digest, section.GetDigest()))
+		}
+	}
+	for _, section := range baseline.GetUki().GetSections() {
+		if !toSections[section.GetName()] {
+			details = append(details, fmt.Sprintf("UKI section %s removed: %x", section.GetName(), section.GetDigest()))
+		}
+	}
+	if len(details) > kernelChanges {
+		registers = addRegister(registers, ukiIdx)
+	}
+
+	var causes []Cause
+	if len(details) > 0 {
+		causes = append(causes, Cause{
+			Kind:        KernelChange,
+			Description: fmt.Sprintf("%d kernel measurements changed", len(details)),
+			Details:     details,
+			Registers:   registers,
+		})
+	}
+	if from.GetCommandLine() != to.GetCommandLine() {
+		causes = append(causes, Cause{
+			Kind:        KernelCmdlineChange,
+			Description: fmt.Sprintf("kernel command line changed from %q to %q", from.GetCommandLine(), to.GetCommandLine()),
+			Details:     cmdlineChanges(from.GetCommandLine(), to.GetCommandLine()),
+			Registers:   []uint32{registerCfg.GRUBCmdIdx},
+		})
+	}
+	return causes
+}
+
+// cmdlineChanges describes the kernel command line parameters added and
+// removed, ignoring their order.
+func cmdlineChanges(from, to string) []string {
+	count := func(cmdline string) map[string]int {
+		params := make(map[string]int)
+		for _, param := range strings.Fields(strings.TrimRight(cmdline, "\x00")) {
+			params[param]++
+		}
+		return params
+	}
+	fromParams, toParams := count(from), count(to)
+	var changes []string
+	for _, param := range sortedKeys(toParams) {
+		if toParams[param] > fromParams[param] {
+			changes = append(changes, "added "+param)
+		}
+	}
+	for _, param := range sortedKeys(fromParams) {
+		if fromParams[param] > toParams[param] {
+			changes = append(changes, "removed "+param)
+		}
+	}
+	return changes
+}
+
+func bootConfigCauses(baseline, failed *pb.FirmwareLogState, registerCfg extract.RegisterConfig) []Cause {
+	from, to := baseline.GetBootConfig(), failed.GetBootConfig()
+	var details []string
+	if fmt.Sprint(from.GetBootOrder()) != fmt.Sprint(to.GetBootOrder()) {
+		details = append(details, fmt.Sprintf("boot order changed from %v to %v", from.GetBootOrder(), to.GetBootOrder()))
+	}
+	fromOptions := make(map[uint32]*pb.BootOption)
+	for _, option := range from.GetBootOptions() {
+		fromOptions[option.GetNumber()] = option
+	}
+	toOptions := make(map[uint32]bool)
+	for _, option := range to.GetBootOptions() {
+		toOptions[option.GetNumber()] = true
+		old, ok := fromOptions[option.GetNumber()]
+		switch {
+		case !ok:
+			details = append(details, fmt.Sprintf("Boot%04X added: %q %s", option.GetNumber(), option.GetDescription(), option.GetDevicePath()))
+		case old.GetDescription() != option.GetDescription() || old.GetDevicePath() != option.GetDevicePath() || old.GetActive() != option.GetActive():
+			details = append(details, fmt.Sprintf("Boot%04X changed from %q %s to %q %s", option.GetNumber(), old.GetDescription(), old.GetDevicePath(), option.GetDescription(), option.GetDevicePath()))
+		}
+	}
+	for _, option := range from.GetBootOptions() {
+		if !toOptions[option.GetNumber()] {
+			details = append(details, fmt.Sprintf("Boot%04X removed: %q %s", option.GetNumber(), option.GetDescription(), option.GetDevicePath()))
+		}
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return []Cause{{
+		Kind:        BootConfigChange,
+		Description: fmt.Sprintf("%d boot config changes", len(details)),
+		Details:     details,
+		Registers:   []uint32{registerCfg.BootConfigIdx},
+	}}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,328 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package forensics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"google.golang.org/protobuf/proto"
+)
+
+func baselineState() *pb.FirmwareLogState {
+	return &pb.FirmwareLogState{
+		Hash:     pb.HashAlgo_SHA256,
+		Platform: &pb.PlatformState{Firmware: &pb.PlatformState_GceVersion{GceVersion: 1}},
+		SecureBoot: &pb.SecureBootState{
+			Enabled: true,
+			Db:      &pb.Database{Certs: []*pb.Certificate{{Representation: &pb.Certificate_WellKnown{WellKnown: pb.WellKnownCertificate_MS_WINDOWS_PROD_PCA_2011}}}},
+			Dbx:     &pb.Database{Hashes: [][]byte{{0x01}, {0x02}}},
+		},
+		Efi: &pb.EfiState{
+			Apps:    []*pb.EfiApp{{Digest: []byte{0xa1}, UntrustedFilePath: `\EFI\BOOT\BOOTX64.EFI`}, {Digest: []byte{0xa2}}},
+			Drivers: []*pb.EfiDriver{{Digest: []byte{0xd1}}},
+		},
+		Grub: &pb.GrubState{Files: []*pb.GrubFile{{UntrustedFilename: []byte("(hd0,gpt15)/boot/grub/grub.cfg"), Digest: []byte{0xc1}}}},
+		LinuxKernel: &pb.LinuxKernelState{
+			CommandLine:  "/vmlinuz root=/dev/sda1 ro console=ttyS0",
+			KernelDigest: []byte{0xb1},
+			InitrdDigest: []byte{0xb2},
+		},
+		BootConfig: &pb.BootConfigState{
+			BootOrder:   []uint32{1, 0},
+			BootOptions: []*pb.BootOption{{Number: 1, Active: true, Description: "ubuntu"}},
+		},
+	}
+}
+
+func TestAnalyzeNoChanges(t *testing.T) {
+	causes, err := Analyze(baselineState(), baselineState())
+	if err != nil {
+		t.Fatalf("Analyze(): %v", err)
+	}
+	if len(causes) != 0 {
+		t.Errorf("Analyze(): got causes %v for identical states, want none", causes)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		modifyBaseline func(*pb.FirmwareLogState)
+		modify         func(*pb.FirmwareLogState)
+		wantKind       CauseKind
+		wantDetails    []string
+		wantRegisters  []uint32
+	}{
+		{
+			name: "firmware update",
+			modify: func(s *pb.FirmwareLogState) {
+				s.Platform.Firmware = &pb.PlatformState_GceVersion{GceVersion: 2}
+			},
+			wantKind:      FirmwareUpdate,
+			wantRegisters: []uint32{0},
+		},
+		{
+			name:          "Secure Boot disabled",
+			modify:        func(s *pb.FirmwareLogState) { s.SecureBoot.Enabled = false },
+			wantKind:      SecureBootDisabled,
+			wantRegisters: []uint32{7},
+		},
+		{
+			name: "dbx update",
+			modify: func(s *pb.FirmwareLogState) {
+				s.SecureBoot.Dbx.Hashes = [][]byte{{0x02}, {0x03}, {0x04}}
+			},
+			wantKind:      DbxUpdate,
+			wantDetails:   []string{"dbx: added hash 03", "dbx: added hash 04", "dbx: removed hash 01"},
+			wantRegisters: []uint32{7},
+		},
+		{
+			name: "db update",
+			modify: func(s *pb.FirmwareLogState) {
+				s.SecureBoot.Db.Certs = append(s.SecureBoot.Db.Certs, &pb.Certificate{
+					Representation: &pb.Certificate_Der{Der: []byte{0x30}},
+					Metadata:       &pb.CertificateMetadata{Subject: "CN=Custom DB"},
+				})
+			},
+			wantKind:      SecureBootKeysChange,
+			wantDetails:   []string{`db: added certificate "CN=Custom DB"`},
+			wantRegisters: []uint32{7},
+		},
+		{
+			name: "new EFI driver",
+			modify: func(s *pb.FirmwareLogState) {
+				s.Efi.Drivers = append(s.Efi.Drivers, &pb.EfiDriver{Digest: []byte{0xd2}, UntrustedDevicePath: "PciRoot(0x0)/Pci(0x3,0x0)", UntrustedOptionRom: true})
+			},
+			wantKind:      NewEFIDriver,
+			wantDetails:   []string{"driver d2 from PciRoot(0x0)/Pci(0x3,0x0) (option ROM)"},
+			wantRegisters: []uint32{2},
+		},
+		{
+			name: "bootloader change",
+			modify: func(s *pb.FirmwareLogState) {
+				s.Efi.Apps[0].Digest = []byte{0xa3}
+				s.Grub.Files[0].Digest = []byte{0xc2}
+			},
+			wantKind: BootloaderChange,
+			wantDetails: []string{
+				`EFI app 0 changed from \EFI\BOOT\BOOTX64.EFI (a1) to \EFI\BOOT\BOOTX64.EFI (a3)`,
+				"GRUB file (hd0,gpt15)/boot/grub/grub.cfg changed from c1 to c2",
+			},
+			wantRegisters: []uint32{4, 9},
+		},
+		{
+			name: "GRUB file removed",
+			modify: func(s *pb.FirmwareLogState) {
+				s.Grub.Files = []*pb.GrubFile{{UntrustedFilename: []byte("(hd0,gpt15)/boot/grub/custom.cfg"), Digest: []byte{0xc3}}}
+			},
+			wantKind: BootloaderChange,
+			wantDetails: []string{
+				"GRUB loaded new file (hd0,gpt15)/boot/grub/custom.cfg",
+				"GRUB no longer loaded file (hd0,gpt15)/boot/grub/grub.cfg",
+			},
+			wantRegisters: []uint32{9},
+		},
+		{
+			name:          "kernel change",
+			modify:        func(s *pb.FirmwareLogState) { s.LinuxKernel.KernelDigest = []byte{0xb3} },
+			wantKind:      KernelChange,
+			wantDetails:   []string{"kernel changed from b1 to b3"},
+			wantRegisters: []uint32{9},
+		},
+		{
+			name: "UKI sections added and removed",
+			modifyBaseline: func(s *pb.FirmwareLogState) {
+				s.Uki = &pb.UkiState{Sections: []*pb.UkiSection{
+					{Name: ".linux", Digest: []byte{0xe1}},
+					{Name: ".dtb", Digest: []byte{0xe2}},
+				}}
+			},
+			modify: func(s *pb.FirmwareLogState) {
+				s.Uki = &pb.UkiState{Sections: []*pb.UkiSection{
+					{Name: ".linux", Digest: []byte{0xe3}},
+					{Name: ".initrd", Digest: []byte{0xe4}},
+				}}
+			},
+			wantKind: KernelChange,
+			wantDetails: []string{
+				"UKI section .linux changed from e1 to e3",
+				"UKI section .initrd added: e4",
+				"UKI section .dtb removed: e2",
+			},
+			wantRegisters: []uint32{11},
+		},
+		{
+			name: "kernel command line change",
+			modify: func(s *pb.FirmwareLogState) {
+				s.LinuxKernel.CommandLine = "/vmlinuz root=/dev/sda1 ro console=ttyS0 init=/bin/sh\x00"
+			},
+			wantKind:      KernelCmdlineChange,
+			wantDetails:   []string{"added init=/bin/sh"},
+			wantRegisters: []uint32{8},
+		},
+		{
+			name: "boot config change",
+			modify: func(s *pb.FirmwareLogState) {
+				s.BootConfig.BootOrder = []uint32{0, 1}
+			},
+			wantKind:      BootConfigChange,
+			wantDetails:   []string{"boot order changed from [1 0] to [0 1]"},
+			wantRegisters: []uint32{1},
+		},
+		{
+			name: "boot option removed",
+			modify: func(s *pb.FirmwareLogState) {
+				s.BootConfig.BootOptions = nil
+			},
+			wantKind:      BootConfigChange,
+			wantDetails:   []string{`Boot0001 removed: "ubuntu" `},
+			wantRegisters: []uint32{1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			baseline, failed := baselineState(), baselineState()
+			if tc.modifyBaseline != nil {
+				tc.modifyBaseline(baseline)
+			}
+			tc.modify(failed)
+			causes, err := Analyze(baseline, failed)
+			if err != nil {
+				t.Fatalf("Analyze(): %v", err)
+			}
+			if len(causes) != 1 {
+				t.Fatalf("Analyze(): got causes %v, want one %v", causes, tc.wantKind)
+			}
+			if causes[0].Kind != tc.wantKind {
+				t.Errorf("Analyze(): got kind %v, want %v", causes[0].Kind, tc.wantKind)
+			}
+			if !reflect.DeepEqual(causes[0].Details, tc.wantDetails) {
+				t.Errorf("Analyze(): got details %q, want %q", causes[0].Details, tc.wantDetails)
+			}
+			if !reflect.DeepEqual(causes[0].Registers, tc.wantRegisters) {
+				t.Errorf("Analyze(): got registers %v, want %v", causes[0].Registers, tc.wantRegisters)
+			}
+		})
+	}
+}
+
+func rankingState() *pb.FirmwareLogState {
+	failed := baselineState()
+	failed.LinuxKernel.CommandLine += " debug"
+	failed.Efi.Drivers = append(failed.Efi.Drivers, &pb.EfiDriver{Digest: []byte{0xd2}})
+	failed.SecureBoot.Dbx.Hashes = append(failed.SecureBoot.Dbx.Hashes, []byte{0x03})
+	failed.Platform.Firmware = &pb.PlatformState_ScrtmVersionId{ScrtmVersionId: []byte("v2")}
+	return failed
+}
+
+func TestAnalyzeRanking(t *testing.T) {
+	failed := rankingState()
+	causes, err := Analyze(baselineState(), failed)
+	if err != nil {
+		t.Fatalf("Analyze(): %v", err)
+	}
+	var kinds []CauseKind
+	for _, cause := range causes {
+		kinds = append(kinds, cause.Kind)
+	}
+	want := []CauseKind{FirmwareUpdate, DbxUpdate, NewEFIDriver, KernelCmdlineChange}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("Analyze(): got kinds %v, want %v", kinds, want)
+	}
+	if got := causes[0].String(); !strings.Contains(got, `from GCE version 1 to S-CRTM version "v2"`) {
+		t.Errorf("Cause.String() = %q, want the firmware versions", got)
+	}
+}
+
+func TestAnalyzeLegacyDrivers(t *testing.T) {
+	baseline := baselineState()
+	baseline.Efi.Drivers = nil
+	baseline.Efi.BootServicesDrivers = []*pb.EfiApp{{Digest: []byte{0xd1}}}
+	failed := proto.Clone(baseline).(*pb.FirmwareLogState)
+	failed.Efi.RuntimeServicesDrivers = []*pb.EfiApp{{Digest: []byte{0xd2}}}
+
+	causes, err := Analyze(baseline, failed)
+	if err != nil {
+		t.Fatalf("Analyze(): %v", err)
+	}
+	if len(causes) != 1 || causes[0].Kind != NewEFIDriver || len(causes[0].Details) != 1 {
+		t.Errorf("Analyze(): got causes %v, want one new EFI driver", causes)
+	}
+}
+
+func TestAnalyzeDifferentBanks(t *testing.T) {
+	failed := baselineState()
+	failed.Hash = pb.HashAlgo_SHA1
+	if _, err := Analyze(baselineState(), failed); err == nil {
+		t.Errorf("Analyze(): got nil, want error for states from different banks")
+	}
+}
+
+func TestAnalyzeWithOptsRanking(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		logType             pb.LogType
+		mismatched          []uint32
+		want                []CauseKind
+		wantFirstMismatched []uint32
+	}{
+		{
+			name:                "PCR 8",
+			mismatched:          []uint32{8},
+			want:                []CauseKind{KernelCmdlineChange, FirmwareUpdate, DbxUpdate, NewEFIDriver},
+			wantFirstMismatched: []uint32{8},
+		},
+		{
+			name:                "PCRs 2 and 8",
+			mismatched:          []uint32{2, 8},
+			want:                []CauseKind{NewEFIDriver, KernelCmdlineChange, FirmwareUpdate, DbxUpdate},
+			wantFirstMismatched: []uint32{2},
+		},
+		{
+			name:                "unchanged PCR",
+			mismatched:          []uint32{14},
+			want:                []CauseKind{FirmwareUpdate, DbxUpdate, NewEFIDriver, KernelCmdlineChange},
+			wantFirstMismatched: nil,
+		},
+		{
+			name:                "RTMR 3",
+			logType:             pb.LogType_LOG_TYPE_CC,
+			mismatched:          []uint32{3},
+			want:                []CauseKind{KernelCmdlineChange, FirmwareUpdate, DbxUpdate, NewEFIDriver},
+			wantFirstMismatched: []uint32{3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			baseline, failed := baselineState(), rankingState()
+			baseline.LogType, failed.LogType = tc.logType, tc.logType
+			causes, err := AnalyzeWithOpts(baseline, failed, Opts{MismatchedRegisters: tc.mismatched})
+			if err != nil {
+				t.Fatalf("AnalyzeWithOpts(): %v", err)
+			}
+			var kinds []CauseKind
+			for _, cause := range causes {
+				kinds = append(kinds, cause.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tc.want) {
+				t.Errorf("AnalyzeWithOpts(): got kinds %v, want %v", kinds, tc.want)
+			}
+			if !reflect.DeepEqual(causes[0].MismatchedRegisters, tc.wantFirstMismatched) {
+				t.Errorf("AnalyzeWithOpts(): got mismatched registers %v, want %v", causes[0].MismatchedRegisters, tc.wantFirstMismatched)
+			}
+		})
+	}
+}
@@ -5,7 +5,9 @@ go 1.20
 require (
 	github.com/google/go-cmp v0.6.0
 	github.com/google/go-tpm v0.9.0
+	github.com/klauspost/compress v1.17.9
 	google.golang.org/protobuf v1.34.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require golang.org/x/sys v0.19.0 // indirect
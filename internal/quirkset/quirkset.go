@@ -0,0 +1,69 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package quirkset implements the named bit sets of quirks shared by the tcg
+// and cel packages.
+package quirkset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Name names a single quirk of a package's quirk set type Q.
+type Name[Q ~uint32] struct {
+	Quirk Q
+	Name  string
+}
+
+// Lookup returns the quirk set called name in sets. kind describes the quirk
+// sets in the error, e.g., "CEL quirk set".
+func Lookup[Q ~uint32](sets map[string]Q, kind, name string) (Q, error) {
+	quirks, ok := sets[name]
+	if !ok {
+		names := make([]string, 0, len(sets))
+		for name := range sets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return 0, fmt.Errorf("unknown %s %q, want one of %v", kind, name, names)
+	}
+	return quirks, nil
+}
+
+// Names returns the names of the quirks in s, in the order of names. Unknown
+// quirks are named by their hex value.
+func Names[Q ~uint32](s Q, names []Name[Q]) []string {
+	var out []string
+	for _, q := range names {
+		if s&q.Quirk == q.Quirk {
+			out = append(out, q.Name)
+			s &^= q.Quirk
+		}
+	}
+	if s != 0 {
+		out = append(out, fmt.Sprintf("Quirks(%#x)", uint32(s)))
+	}
+	return out
+}
+
+// String returns the names of the quirks in s joined by "|", or "none" for
+// the empty set.
+func String[Q ~uint32](s Q, names []Name[Q]) string {
+	if s == 0 {
+		return "none"
+	}
+	return strings.Join(Names(s, names), "|")
+}
@@ -14,14 +14,11 @@
 
 package testutil
 
-import (
-	pb "github.com/google/go-eventlog/proto/state"
-	"github.com/google/go-eventlog/register"
-)
+import "github.com/google/go-eventlog/register"
 
 // MakePCRBank takes a hash and a map of index to digest and creates the
 // corresponding PCRBank.
-func MakePCRBank(hashAlgo pb.HashAlgo, pcrIdxToDigest map[uint32][]byte) register.PCRBank {
+func MakePCRBank(hashAlgo register.HashAlgo, pcrIdxToDigest map[uint32][]byte) register.PCRBank {
 	pcrs := make([]register.PCR, 0, len(pcrIdxToDigest))
 	digestAlg, err := hashAlgo.CryptoHash()
 	if err != nil {
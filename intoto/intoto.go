@@ -0,0 +1,99 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package intoto exports a FirmwareLogState as an in-toto attestation, so boot
+// integrity evidence can be consumed by supply-chain policy engines.
+//
+// See https://github.com/in-toto/attestation/blob/main/spec/v1/statement.md.
+package intoto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// StatementType is the in-toto Statement v1 type.
+	StatementType = "https://in-toto.io/Statement/v1"
+	// FirmwareLogStatePredicateType identifies a predicate containing the
+	// protojson encoding of a FirmwareLogState.
+	FirmwareLogStatePredicateType = "https://github.com/google/go-eventlog/FirmwareLogState/v1"
+)
+
+// Subject is an in-toto ResourceDescriptor identifying the artifact the
+// boot measurements are about.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Statement is an in-toto Statement v1 with a FirmwareLogState predicate.
+type Statement struct {
+	Type          string          `json:"_type"`
+	Subject       []Subject       `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// EventLogSubject returns a Subject identifying a raw event log by its SHA-256
+// digest.
+func EventLogSubject(name string, rawEventLog []byte) Subject {
+	digest := sha256.Sum256(rawEventLog)
+	return Subject{Name: name, Digest: map[string]string{"sha256": hex.EncodeToString(digest[:])}}
+}
+
+// NewStatement returns an in-toto Statement with the FirmwareLogState as its
+// predicate. At least one subject is required.
+func NewStatement(state *pb.FirmwareLogState, subjects ...Subject) (*Statement, error) {
+	if state == nil {
+		return nil, errors.New("nil FirmwareLogState")
+	}
+	if len(subjects) == 0 {
+		return nil, errors.New("in-toto statements require at least one subject")
+	}
+	for _, subject := range subjects {
+		if len(subject.Digest) == 0 {
+			return nil, fmt.Errorf("subject %q has no digest", subject.Name)
+		}
+	}
+	predicate, err := protojson.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal FirmwareLogState: %v", err)
+	}
+	return &Statement{
+		Type:          StatementType,
+		Subject:       subjects,
+		PredicateType: FirmwareLogStatePredicateType,
+		Predicate:     predicate,
+	}, nil
+}
+
+// FirmwareLogState decodes the predicate of a Statement into a
+// FirmwareLogState.
+func (s *Statement) FirmwareLogState() (*pb.FirmwareLogState, error) {
+	if s.PredicateType != FirmwareLogStatePredicateType {
+		return nil, fmt.Errorf("unexpected predicate type %q", s.PredicateType)
+	}
+	state := &pb.FirmwareLogState{}
+	if err := protojson.Unmarshal(s.Predicate, state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal FirmwareLogState: %v", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package intoto
+
+import (
+	"encoding/json"
+	"testing"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/testdata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStatementRoundTrip(t *testing.T) {
+	state := &pb.FirmwareLogState{
+		Hash:        pb.HashAlgo_SHA256,
+		LogType:     pb.LogType_LOG_TYPE_TCG2,
+		LinuxKernel: &pb.LinuxKernelState{CommandLine: "root=/dev/sda1 ro"},
+		SecureBoot:  &pb.SecureBootState{Enabled: true},
+	}
+	subject := EventLogSubject("ubuntu-2404", testdata.Ubuntu2404AmdSevSnpEventLog)
+	statement, err := NewStatement(state, subject)
+	if err != nil {
+		t.Fatalf("NewStatement(): %v", err)
+	}
+
+	out, err := json.Marshal(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generic map[string]any
+	if err := json.Unmarshal(out, &generic); err != nil {
+		t.Fatal(err)
+	}
+	if generic["_type"] != StatementType || generic["predicateType"] != FirmwareLogStatePredicateType {
+		t.Errorf("got statement %s, want in-toto v1 statement with FirmwareLogState predicate", out)
+	}
+
+	var decoded Statement
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Subject) != 1 || len(decoded.Subject[0].Digest["sha256"]) != 64 {
+		t.Errorf("got subjects %v, want one SHA-256 subject", decoded.Subject)
+	}
+	got, err := decoded.FirmwareLogState()
+	if err != nil {
+		t.Fatalf("FirmwareLogState(): %v", err)
+	}
+	if !proto.Equal(got, state) {
+		t.Errorf("FirmwareLogState(): got %v, want %v", got, state)
+	}
+}
+
+func TestNewStatementFails(t *testing.T) {
+	if _, err := NewStatement(&pb.FirmwareLogState{}); err == nil {
+		t.Errorf("NewStatement(no subjects): got nil, want error")
+	}
+	if _, err := NewStatement(&pb.FirmwareLogState{}, Subject{Name: "no digest"}); err == nil {
+		t.Errorf("NewStatement(subject without digest): got nil, want error")
+	}
+	if _, err := NewStatement(nil, EventLogSubject("log", nil)); err == nil {
+		t.Errorf("NewStatement(nil): got nil, want error")
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package merkle summarizes verified event logs as per-register Merkle trees,
+// so a verifier that kept only the tree roots can later check proofs that an
+// event was in the log, without the log being shared again.
+//
+// The trees follow the RFC 6962 (Certificate Transparency) construction with
+// SHA-256, and each leaf commits to an event's number, register index, type,
+// digest, and data.
+package merkle
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/google/go-eventlog/tcg"
+)
+
+// Domain separation prefixes for leaf and interior node hashes.
+const (
+	leafPrefix = 0
+	nodePrefix = 1
+)
+
+// Tree holds a Merkle tree over the events of each register. The events
+// should be verified, e.g., from tcg.ParseAndReplay, before a tree is built
+// over them.
+type Tree struct {
+	// leaves holds the leaf hashes of each register, in log order.
+	leaves map[uint32][][]byte
+	// positions maps event numbers to their register and leaf index.
+	positions map[uint32]position
+}
+
+type position struct {
+	mrIndex uint32
+	leaf    int
+}
+
+// Proof proves that an event is in the Merkle tree of a register.
+type Proof struct {
+	// MRIndex is the register of the tree.
+	MRIndex uint32
+	// LeafIndex is the position of the event among the register's events.
+	LeafIndex int
+	// TreeSize is the number of events in the register's tree.
+	TreeSize int
+	// Path holds the sibling hashes from the leaf to the root.
+	Path [][]byte
+}
+
+// New builds a Tree over the events. Events are grouped by register in the
+// order given, which should be log order. Event numbers must be unique.
+func New(events []tcg.Event) (*Tree, error) {
+	t := &Tree{
+		leaves:    make(map[uint32][][]byte),
+		positions: make(map[uint32]position, len(events)),
+	}
+	for _, event := range events {
+		if _, ok := t.positions[event.Num()]; ok {
+			return nil, fmt.Errorf("duplicate event number %d", event.Num())
+		}
+		idx := event.MRIndex()
+		t.positions[event.Num()] = position{mrIndex: idx, leaf: len(t.leaves[idx])}
+		t.leaves[idx] = append(t.leaves[idx], LeafHash(event))
+	}
+	return t, nil
+}
+
+// MRIndexes returns the registers with a tree, in increasing order.
+func (t *Tree) MRIndexes() []uint32 {
+	indexes := make([]uint32, 0, len(t.leaves))
+	for idx := range t.leaves {
+		indexes = append(indexes, idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes
+}
+
+// Root returns the root hash of the register's tree, or false if the register
+// has no events.
+func (t *Tree) Root(mrIndex uint32) ([]byte, bool) {
+	leaves, ok := t.leaves[mrIndex]
+	if !ok {
+		return nil, false
+	}
+	return rootHash(leaves), true
+}
+
+// Roots returns the root hash of each register's tree.
+func (t *Tree) Roots() map[uint32][]byte {
+	roots := make(map[uint32][]byte, len(t.leaves))
+	for idx, leaves := range t.leaves {
+		roots[idx] = rootHash(leaves)
+	}
+	return roots
+}
+
+// Prove returns an inclusion proof for the event with the given number.
+func (t *Tree) Prove(eventNum uint32) (Proof, error) {
+	pos, ok := t.positions[eventNum]
+	if !ok {
+		return Proof{}, fmt.Errorf("no event number %d in tree", eventNum)
+	}
+	leaves := t.leaves[pos.mrIndex]
+	return Proof{
+		MRIndex:   pos.mrIndex,
+		LeafIndex: pos.leaf,
+		TreeSize:  len(leaves),
+		Path:      inclusionPath(pos.leaf, leaves),
+	}, nil
+}
+
+// VerifyInclusion checks that the proof places the event in the tree with
+// the given root. The root must come from a trusted source, e.g., a Tree
+// built by the verifier over a verified event log.
+func VerifyInclusion(root []byte, event tcg.Event, proof Proof) error {
+	if event.MRIndex() != proof.MRIndex {
+		return fmt.Errorf("event is in register %d, but proof is for register %d", event.MRIndex(), proof.MRIndex)
+	}
+	if proof.LeafIndex < 0 || proof.LeafIndex >= proof.TreeSize {
+		return fmt.Errorf("leaf index %d out of range for tree size %d", proof.LeafIndex, proof.TreeSize)
+	}
+	// See RFC 9162, section 2.1.3.2.
+	fn, sn := proof.LeafIndex, proof.TreeSize-1
+	hash := LeafHash(event)
+	for _, sibling := range proof.Path {
+		if sn == 0 {
+			return errors.New("inclusion proof too long")
+		}
+		if fn&1 == 1 || fn == sn {
+			hash = nodeHash(sibling, hash)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			hash = nodeHash(hash, sibling)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return errors.New("inclusion proof too short")
+	}
+	if subtle.ConstantTimeCompare(hash, root) != 1 {
+		return errors.New("inclusion proof does not match the root")
+	}
+	return nil
+}
+
+// LeafHash returns the leaf hash of an event. It commits to the event number,
+// register index, type, replayed digest, and data.
+func LeafHash(event tcg.Event) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	binary.Write(h, binary.BigEndian, event.Num())
+	binary.Write(h, binary.BigEndian, event.MRIndex())
+	binary.Write(h, binary.BigEndian, uint32(event.Type))
+	binary.Write(h, binary.BigEndian, uint32(len(event.ReplayedDigest())))
+	h.Write(event.ReplayedDigest())
+	binary.Write(h, binary.BigEndian, uint32(len(event.Data)))
+	h.Write(event.Data)
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// split returns the largest power of two smaller than n, for n > 1.
+func split(n int) int {
+	return 1 << (bits.Len(uint(n-1)) - 1)
+}
+
+func rootHash(leaves [][]byte) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := split(len(leaves))
+	return nodeHash(rootHash(leaves[:k]), rootHash(leaves[k:]))
+}
+
+// inclusionPath returns the audit path for leaf m, as in RFC 6962, section
+// 2.1.1.
+func inclusionPath(m int, leaves [][]byte) [][]byte {
+	if len(leaves) == 1 {
+		return nil
+	}
+	k := split(len(leaves))
+	if m < k {
+		return append(inclusionPath(m, leaves[:k]), rootHash(leaves[k:]))
+	}
+	return append(inclusionPath(m-k, leaves[k:]), rootHash(leaves[:k]))
+}
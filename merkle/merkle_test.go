@@ -0,0 +1,147 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package merkle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/testdata"
+)
+
+func testEvents(t *testing.T) []tcg.Event {
+	t.Helper()
+	el, err := tcg.ParseEventLog(testdata.Ubuntu2404AmdSevSnpEventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return el.Events(register.HashSHA256)
+}
+
+func TestProveAndVerify(t *testing.T) {
+	events := testEvents(t)
+	tree, err := New(events)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	roots := tree.Roots()
+	if len(roots) != len(tree.MRIndexes()) {
+		t.Errorf("Roots(): got %d roots for %d registers", len(roots), len(tree.MRIndexes()))
+	}
+	for _, event := range events {
+		proof, err := tree.Prove(event.Num())
+		if err != nil {
+			t.Fatalf("Prove(%d): %v", event.Num(), err)
+		}
+		if err := VerifyInclusion(roots[event.MRIndex()], event, proof); err != nil {
+			t.Errorf("VerifyInclusion(event %d): %v", event.Num(), err)
+		}
+	}
+}
+
+func TestRootHash(t *testing.T) {
+	events := []tcg.Event{
+		{Index: 4, Type: tcg.EFIAction, Data: []byte("a"), Digest: []byte{1}},
+		{Index: 4, Type: tcg.EFIAction, Data: []byte("b"), Digest: []byte{2}},
+		{Index: 4, Type: tcg.EFIAction, Data: []byte("c"), Digest: []byte{3}},
+	}
+	var leaves [][]byte
+	for _, event := range events {
+		leaves = append(leaves, LeafHash(event))
+	}
+	// New requires unique event numbers, so build the tree directly.
+	tree := &Tree{leaves: map[uint32][][]byte{4: leaves}}
+	root, ok := tree.Root(4)
+	if !ok {
+		t.Fatalf("Root(4): got no root")
+	}
+	if want := nodeHash(nodeHash(leaves[0], leaves[1]), leaves[2]); !bytes.Equal(root, want) {
+		t.Errorf("Root(4) = %x, want %x", root, want)
+	}
+	if _, ok := tree.Root(7); ok {
+		t.Errorf("Root(7): got a root for a register without events")
+	}
+}
+
+func TestVerifyInclusionFails(t *testing.T) {
+	events := testEvents(t)
+	tree, err := New(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Pick an event in a register with several events, so the proof has a
+	// path.
+	var event tcg.Event
+	var proof Proof
+	for _, e := range events {
+		p, err := tree.Prove(e.Num())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p.Path) > 1 {
+			event, proof = e, p
+			break
+		}
+	}
+	if proof.Path == nil {
+		t.Fatal("no event with an inclusion path")
+	}
+	root, _ := tree.Root(event.MRIndex())
+
+	tampered := event
+	tampered.Data = append([]byte{0xff}, event.Data...)
+	shortProof := proof
+	shortProof.Path = proof.Path[1:]
+	longProof := proof
+	longProof.Path = append(append([][]byte(nil), proof.Path...), root)
+	otherRegister := proof
+	otherRegister.MRIndex++
+
+	for _, tc := range []struct {
+		name  string
+		root  []byte
+		event tcg.Event
+		proof Proof
+	}{
+		{"tampered event", root, tampered, proof},
+		{"wrong root", make([]byte, len(root)), event, proof},
+		{"short path", root, event, shortProof},
+		{"long path", root, event, longProof},
+		{"other register", root, event, otherRegister},
+		{"leaf index out of range", root, event, Proof{MRIndex: proof.MRIndex, LeafIndex: proof.TreeSize, TreeSize: proof.TreeSize}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := VerifyInclusion(tc.root, tc.event, tc.proof); err == nil {
+				t.Errorf("VerifyInclusion(): got nil, want error")
+			}
+		})
+	}
+}
+
+func TestNewDuplicateEventNumber(t *testing.T) {
+	events := testEvents(t)
+	if _, err := New(append(events, events[0])); err == nil {
+		t.Errorf("New(): got nil, want error for a duplicate event number")
+	}
+	tree, err := New(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tree.Prove(uint32(len(events) + 100)); err == nil {
+		t.Errorf("Prove(): got nil, want error for an unknown event number")
+	}
+}
@@ -0,0 +1,35 @@
+# Copyright 2024 Google LLC
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may not
+# use this file except in compliance with the License. You may obtain a copy of
+# the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations under
+# the License.
+
+# Rejects kernel command lines, including kexec-loaded kernels, that disable
+# kernel lockdown or boot into a shell.
+package eventlog.kernel
+
+import rego.v1
+
+forbidden_args := {"init=/bin/sh", "init=/bin/bash", "lockdown=none", "module.sig_enforce=0"}
+
+cmdlines := array.concat([input.kernel_cmdline], input.kexec_cmdlines)
+
+deny contains msg if {
+	some cmdline in cmdlines
+	some arg in split(cmdline, " ")
+	arg in forbidden_args
+	msg := sprintf("forbidden kernel argument %q", [arg])
+}
+
+allow if {
+	input.kernel_cmdline != ""
+	count(deny) == 0
+}
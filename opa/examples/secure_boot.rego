@@ -0,0 +1,42 @@
+# Copyright 2024 Google LLC
+#
+# Licensed under the Apache License, Version 2.0 (the "License"); you may not
+# use this file except in compliance with the License. You may obtain a copy of
+# the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+# WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+# License for the specific language governing permissions and limitations under
+# the License.
+
+# Requires Secure Boot with the Microsoft UEFI CA in db and a non-empty dbx.
+package eventlog.secure_boot
+
+import rego.v1
+
+default allow := false
+
+allow if {
+	input.secure_boot_enabled
+	"MS_THIRD_PARTY_UEFI_CA_2011" in input.db.certs
+	count(input.dbx.hashes) > 0
+}
+
+deny contains msg if {
+	not input.secure_boot_enabled
+	msg := "Secure Boot is disabled"
+}
+
+deny contains msg if {
+	input.debug_mode_enabled
+	msg := "a UEFI debugger was enabled during boot"
+}
+
+deny contains msg if {
+	some app in input.efi_apps
+	app in input.dbx.hashes
+	msg := sprintf("EFI application %s is revoked in dbx", [app])
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package opa converts a FirmwareLogState into a flattened document for use as
+// Open Policy Agent (OPA) input.
+//
+// Digests are lowercase hex strings, enums are their names, and certificates
+// are either their well-known name or the SHA-256 fingerprint of their DER.
+// Example Rego modules consuming this input are in the examples directory.
+package opa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+// Input is the flattened OPA input document for a FirmwareLogState.
+type Input struct {
+	Hash                   string       `json:"hash"`
+	LogType                string       `json:"log_type"`
+	Technology             string       `json:"technology"`
+	GCEFirmwareVersion     uint32       `json:"gce_firmware_version,omitempty"`
+	SCRTMVersionID         string       `json:"scrtm_version_id,omitempty"`
+	SecureBootEnabled      bool         `json:"secure_boot_enabled"`
+	DebugModeEnabled       bool         `json:"debug_mode_enabled"`
+	DB                     Database     `json:"db"`
+	DBX                    Database     `json:"dbx"`
+	Authority              Database     `json:"authority"`
+	PK                     Database     `json:"pk"`
+	KEK                    Database     `json:"kek"`
+	EFIApps                []string     `json:"efi_apps"`
+	BootServicesDrivers    []string     `json:"boot_services_drivers"`
+	RuntimeServicesDrivers []string     `json:"runtime_services_drivers"`
+	GRUBCommands           []string     `json:"grub_commands"`
+	GRUBFiles              []GRUBFile   `json:"grub_files"`
+	KernelCommandLine      string       `json:"kernel_cmdline"`
+	KexecCommandLines      []string     `json:"kexec_cmdlines"`
+	Events                 []EventInput `json:"events"`
+	// Findings are the FindingType names of the state's findings, e.g.,
+	// "FINDING_TYPE_WEAK_BANK".
+	Findings []string `json:"findings"`
+}
+
+// Database is a flattened Secure Boot database.
+type Database struct {
+	// Certs are well-known certificate names or "sha256:<hex>" fingerprints.
+	Certs  []string `json:"certs"`
+	Hashes []string `json:"hashes"`
+}
+
+// GRUBFile is a flattened GRUB file measurement.
+type GRUBFile struct {
+	Filename string `json:"filename"`
+	Digest   string `json:"digest"`
+}
+
+// EventInput is a flattened raw event.
+type EventInput struct {
+	Index          uint32 `json:"index"`
+	Type           uint32 `json:"type"`
+	Digest         string `json:"digest"`
+	DigestVerified bool   `json:"digest_verified"`
+}
+
+// NewInput flattens a FirmwareLogState into an OPA input document.
+// Lists are never nil, so policies can use them without existence checks.
+func NewInput(state *pb.FirmwareLogState) *Input {
+	in := &Input{
+		Hash:                   state.GetHash().String(),
+		LogType:                state.GetLogType().String(),
+		Technology:             state.GetPlatform().GetTechnology().String(),
+		GCEFirmwareVersion:     state.GetPlatform().GetGceVersion(),
+		SCRTMVersionID:         hex.EncodeToString(state.GetPlatform().GetScrtmVersionId()),
+		SecureBootEnabled:      state.GetSecureBoot().GetEnabled(),
+		DebugModeEnabled:       state.GetSecureBoot().GetDebugModeEnabled(),
+		DB:                     database(state.GetSecureBoot().GetDb()),
+		DBX:                    database(state.GetSecureBoot().GetDbx()),
+		Authority:              database(state.GetSecureBoot().GetAuthority()),
+		PK:                     database(state.GetSecureBoot().GetPk()),
+		KEK:                    database(state.GetSecureBoot().GetKek()),
+		EFIApps:                efiApps(state.GetEfi().GetApps()),
+		BootServicesDrivers:    efiApps(state.GetEfi().GetBootServicesDrivers()),
+		RuntimeServicesDrivers: efiApps(state.GetEfi().GetRuntimeServicesDrivers()),
+		GRUBCommands:           append([]string{}, state.GetGrub().GetCommands()...),
+		GRUBFiles:              []GRUBFile{},
+		KernelCommandLine:      state.GetLinuxKernel().GetCommandLine(),
+		KexecCommandLines:      []string{},
+		Events:                 []EventInput{},
+		Findings:               []string{},
+	}
+	for _, file := range state.GetGrub().GetFiles() {
+		in.GRUBFiles = append(in.GRUBFiles, GRUBFile{
+			Filename: string(file.GetUntrustedFilename()),
+			Digest:   hex.EncodeToString(file.GetDigest()),
+		})
+	}
+	for _, kexec := range state.GetKexec() {
+		in.KexecCommandLines = append(in.KexecCommandLines, kexec.GetLinuxKernel().GetCommandLine())
+	}
+	for _, event := range state.GetRawEvents() {
+		in.Events = append(in.Events, EventInput{
+			Index:          event.GetPcrIndex(),
+			Type:           event.GetUntrustedType(),
+			Digest:         hex.EncodeToString(event.GetDigest()),
+			DigestVerified: event.GetDigestVerified(),
+		})
+	}
+	for _, finding := range state.GetFindings() {
+		in.Findings = append(in.Findings, finding.GetType().String())
+	}
+	return in
+}
+
+// InputJSON returns the JSON encoding of the OPA input document for a
+// FirmwareLogState, e.g., for `opa eval --input`.
+func InputJSON(state *pb.FirmwareLogState) ([]byte, error) {
+	return json.Marshal(NewInput(state))
+}
+
+func database(db *pb.Database) Database {
+	out := Database{Certs: []string{}, Hashes: []string{}}
+	for _, cert := range db.GetCerts() {
+		if cert.GetWellKnown() != pb.WellKnownCertificate_UNKNOWN {
+			out.Certs = append(out.Certs, cert.GetWellKnown().String())
+			continue
+		}
+		fingerprint := sha256.Sum256(cert.GetDer())
+		out.Certs = append(out.Certs, "sha256:"+hex.EncodeToString(fingerprint[:]))
+	}
+	for _, hash := range db.GetHashes() {
+		out.Hashes = append(out.Hashes, hex.EncodeToString(hash))
+	}
+	return out
+}
+
+func efiApps(apps []*pb.EfiApp) []string {
+	digests := []string{}
+	for _, app := range apps {
+		digests = append(digests, hex.EncodeToString(app.GetDigest()))
+	}
+	return digests
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package opa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+func TestInputJSON(t *testing.T) {
+	state := &pb.FirmwareLogState{
+		Hash:     pb.HashAlgo_SHA256,
+		LogType:  pb.LogType_LOG_TYPE_TCG2,
+		Platform: &pb.PlatformState{Firmware: &pb.PlatformState_GceVersion{GceVersion: 20}, Technology: pb.GCEConfidentialTechnology_AMD_SEV_SNP},
+		SecureBoot: &pb.SecureBootState{
+			Enabled: true,
+			Db: &pb.Database{Certs: []*pb.Certificate{
+				{Representation: &pb.Certificate_WellKnown{WellKnown: pb.WellKnownCertificate_MS_THIRD_PARTY_UEFI_CA_2011}},
+				{Representation: &pb.Certificate_Der{Der: []byte("not really DER")}},
+			}},
+			Dbx: &pb.Database{Hashes: [][]byte{{0xab, 0xcd}}},
+		},
+		Efi:         &pb.EfiState{Apps: []*pb.EfiApp{{Digest: []byte{0x01, 0x02}}}},
+		Grub:        &pb.GrubState{Commands: []string{"grub_cmd: linux /vmlinuz"}, Files: []*pb.GrubFile{{Digest: []byte{0xff}, UntrustedFilename: []byte("/vmlinuz")}}},
+		LinuxKernel: &pb.LinuxKernelState{CommandLine: "ro quiet"},
+		Kexec:       []*pb.KexecState{{LinuxKernel: &pb.LinuxKernelState{CommandLine: "ro kexec"}}},
+		Findings:    []*pb.Finding{{Type: pb.FindingType_FINDING_TYPE_WEAK_BANK}},
+	}
+	out, err := InputJSON(state)
+	if err != nil {
+		t.Fatalf("InputJSON(): %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := sha256.Sum256([]byte("not really DER"))
+	want := map[string]any{
+		"hash":                 "SHA256",
+		"log_type":             "LOG_TYPE_TCG2",
+		"technology":           "AMD_SEV_SNP",
+		"gce_firmware_version": float64(20),
+		"secure_boot_enabled":  true,
+		"debug_mode_enabled":   false,
+		"db": map[string]any{
+			"certs":  []any{"MS_THIRD_PARTY_UEFI_CA_2011", "sha256:" + hex.EncodeToString(fingerprint[:])},
+			"hashes": []any{},
+		},
+		"dbx":                      map[string]any{"certs": []any{}, "hashes": []any{"abcd"}},
+		"authority":                map[string]any{"certs": []any{}, "hashes": []any{}},
+		"pk":                       map[string]any{"certs": []any{}, "hashes": []any{}},
+		"kek":                      map[string]any{"certs": []any{}, "hashes": []any{}},
+		"efi_apps":                 []any{"0102"},
+		"boot_services_drivers":    []any{},
+		"runtime_services_drivers": []any{},
+		"grub_commands":            []any{"grub_cmd: linux /vmlinuz"},
+		"grub_files":               []any{map[string]any{"filename": "/vmlinuz", "digest": "ff"}},
+		"kernel_cmdline":           "ro quiet",
+		"kexec_cmdlines":           []any{"ro kexec"},
+		"events":                   []any{},
+		"findings":                 []any{"FINDING_TYPE_WEAK_BANK"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("InputJSON() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestInputJSONEmptyState(t *testing.T) {
+	out, err := InputJSON(&pb.FirmwareLogState{})
+	if err != nil {
+		t.Fatalf("InputJSON(): %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, list := range []string{"efi_apps", "grub_commands", "grub_files", "kexec_cmdlines", "events", "findings"} {
+		if _, ok := got[list].([]any); !ok {
+			t.Errorf("InputJSON(empty)[%q] = %v, want an empty list", list, got[list])
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package policy provides building blocks for evaluating a FirmwareLogState
+// against the expected configuration of a machine.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+// Typed wildcards for command line patterns. "*" matches any text.
+var cmdlineWildcards = map[string]string{
+	"{uuid}": `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	"{int}":  `[0-9]+`,
+	"{hex}":  `(?:0x)?[0-9a-fA-F]+`,
+	"{path}": `(?:\([^()\s]*\))?/\S*`,
+	"{word}": `[A-Za-z0-9_.:-]+`,
+}
+
+var cmdlineWildcard = regexp.MustCompile(`\*|\{[a-z]*\}`)
+
+// A CmdlineTemplate is an expected kernel command line. Parameters are
+// matched against patterns, which are literal text with wildcards: "*"
+// matches any text, and the typed wildcards "{uuid}", "{int}", "{hex}",
+// "{path}", and "{word}" match UUIDs, decimal integers, optionally
+// 0x-prefixed hex integers, absolute paths with an optional GRUB device
+// prefix like "(hd0,gpt2)", and words. For example,
+// "root=UUID={uuid}" and "console=*" match the root and console parameters.
+type CmdlineTemplate struct {
+	// Name identifies the template in match results.
+	Name string
+	// Image, if set, is the pattern of the first word of the command line, the
+	// kernel image path that GRUB measures before the parameters.
+	Image string
+	// Required are patterns that must each match a parameter.
+	Required []string
+	// Allowed are patterns of optional parameters.
+	Allowed []string
+	// Forbidden are patterns that must not match any parameter.
+	Forbidden []string
+	// AllowUnknown permits parameters that match no Required or Allowed
+	// pattern.
+	AllowUnknown bool
+}
+
+type compiledTemplate struct {
+	CmdlineTemplate
+	image     *regexp.Regexp
+	required  []*regexp.Regexp
+	allowed   []*regexp.Regexp
+	forbidden []*regexp.Regexp
+}
+
+// CmdlineMatcher matches kernel command lines against registered templates.
+// The zero value has no templates and matches nothing.
+type CmdlineMatcher struct {
+	templates []compiledTemplate
+}
+
+// Register adds a template to the matcher. It returns an error if a pattern
+// has an unknown typed wildcard, or if a template with the same name is
+// registered.
+func (m *CmdlineMatcher) Register(template CmdlineTemplate) error {
+	for _, t := range m.templates {
+		if t.Name == template.Name {
+			return fmt.Errorf("template %q is already registered", template.Name)
+		}
+	}
+	compiled := compiledTemplate{CmdlineTemplate: template}
+	var err error
+	if template.Image != "" {
+		if compiled.image, err = compileCmdlinePattern(template.Image); err != nil {
+			return fmt.Errorf("template %q: %v", template.Name, err)
+		}
+	}
+	for _, p := range []struct {
+		patterns []string
+		out      *[]*regexp.Regexp
+	}{
+		{template.Required, &compiled.required},
+		{template.Allowed, &compiled.allowed},
+		{template.Forbidden, &compiled.forbidden},
+	} {
+		for _, pattern := range p.patterns {
+			re, err := compileCmdlinePattern(pattern)
+			if err != nil {
+				return fmt.Errorf("template %q: %v", template.Name, err)
+			}
+			*p.out = append(*p.out, re)
+		}
+	}
+	m.templates = append(m.templates, compiled)
+	return nil
+}
+
+func compileCmdlinePattern(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, loc := range cmdlineWildcard.FindAllStringIndex(pattern, -1) {
+		expr.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		wildcard := pattern[loc[0]:loc[1]]
+		if wildcard == "*" {
+			expr.WriteString(".*")
+		} else if typed, ok := cmdlineWildcards[wildcard]; ok {
+			expr.WriteString(typed)
+		} else {
+			return nil, fmt.Errorf("unknown wildcard %s in pattern %q", wildcard, pattern)
+		}
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(pattern[last:]))
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// Match returns the name of the first registered template that the command
+// line matches. If none match, it returns an error with the reason each
+// template did not match.
+func (m *CmdlineMatcher) Match(cmdline string) (string, error) {
+	if len(m.templates) == 0 {
+		return "", errors.New("no command line templates registered")
+	}
+	words := SplitCmdline(cmdline)
+	var errs []error
+	for _, t := range m.templates {
+		err := t.match(words)
+		if err == nil {
+			return t.Name, nil
+		}
+		errs = append(errs, fmt.Errorf("template %q: %v", t.Name, err))
+	}
+	return "", errors.Join(errs...)
+}
+
+// MatchKernel matches the command line of the kernel state, like Match.
+func (m *CmdlineMatcher) MatchKernel(kernel *pb.LinuxKernelState) (string, error) {
+	return m.Match(kernel.GetCommandLine())
+}
+
+func (t compiledTemplate) match(words []string) error {
+	params := words
+	if t.image != nil {
+		if len(words) == 0 || !t.image.MatchString(words[0]) {
+			return errors.New("kernel image does not match")
+		}
+		params = words[1:]
+	}
+	for i, re := range t.required {
+		if !matchesAny(params, re) {
+			return fmt.Errorf("missing required parameter %q", t.Required[i])
+		}
+	}
+	for _, param := range params {
+		for i, re := range t.forbidden {
+			if re.MatchString(param) {
+				return fmt.Errorf("parameter %q matches forbidden %q", param, t.Forbidden[i])
+			}
+		}
+		if !t.AllowUnknown && !matchesPattern(param, t.required) && !matchesPattern(param, t.allowed) {
+			return fmt.Errorf("unexpected parameter %q", param)
+		}
+	}
+	return nil
+}
+
+func matchesAny(params []string, re *regexp.Regexp) bool {
+	for _, param := range params {
+		if re.MatchString(param) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPattern(param string, res []*regexp.Regexp) bool {
+	for _, re := range res {
+		if re.MatchString(param) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitCmdline splits a kernel command line into words at unquoted
+// whitespace, removing double quotes, like the kernel's parameter parsing.
+// For example, `a="b c" d` splits into "a=b c" and "d". Trailing NULs, which
+// GRUB includes in some measured command lines, are removed.
+func SplitCmdline(cmdline string) []string {
+	cmdline = strings.TrimRight(cmdline, "\x00")
+	var words []string
+	var word strings.Builder
+	inWord, quoted := false, false
+	for _, r := range cmdline {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inWord = true
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+func TestSplitCmdline(t *testing.T) {
+	got := SplitCmdline(` /vmlinuz  root=UUID=x  dyndbg="file a.c +p" ""  quiet`)
+	want := []string{"/vmlinuz", "root=UUID=x", "dyndbg=file a.c +p", "", "quiet"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SplitCmdline() returned unexpected diff (-want +got):\n%s", diff)
+	}
+	got = SplitCmdline("/vmlinuz console=ttyS0,38400n8\x00\x00")
+	want = []string{"/vmlinuz", "console=ttyS0,38400n8"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SplitCmdline(trailing NULs) returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestCmdlineMatcher(t *testing.T) {
+	var m CmdlineMatcher
+	if _, err := m.Match("ro"); err == nil {
+		t.Errorf("Match() without templates: got nil, want error")
+	}
+	templates := []CmdlineTemplate{
+		{
+			Name:      "cos",
+			Image:     "/syslinux/vmlinuz.{word}",
+			Required:  []string{"root=PARTUUID={uuid}", "ro"},
+			Allowed:   []string{"console=*", "loglevel={int}"},
+			Forbidden: []string{"init=*"},
+		},
+		{
+			Name:     "rhel",
+			Image:    "{path}",
+			Required: []string{"root={path}", "console=ttyS0,38400n8"},
+		},
+		{
+			Name:         "debug",
+			Required:     []string{"debug"},
+			Forbidden:    []string{"lockdown=none"},
+			AllowUnknown: true,
+		},
+	}
+	for _, template := range templates {
+		if err := m.Register(template); err != nil {
+			t.Fatalf("Register(%q): %v", template.Name, err)
+		}
+	}
+	if err := m.Register(CmdlineTemplate{Name: "cos"}); err == nil {
+		t.Errorf("Register(duplicate name): got nil, want error")
+	}
+	if err := m.Register(CmdlineTemplate{Name: "bad", Allowed: []string{"a={float}"}}); err == nil {
+		t.Errorf("Register(unknown wildcard): got nil, want error")
+	}
+
+	for _, tc := range []struct {
+		cmdline string
+		want    string
+	}{
+		{"/syslinux/vmlinuz.A root=PARTUUID=6a1b0c2d-3e4f-5a6b-7c8d-9e0f1a2b3c4d ro console=ttyS0,115200 loglevel=7", "cos"},
+		{"/syslinux/vmlinuz.B ro root=PARTUUID=6A1B0C2D-3E4F-5A6B-7C8D-9E0F1A2B3C4D", "cos"},
+		{"/syslinux/vmlinuz.A root=PARTUUID=6a1b0c2d-3e4f-5a6b-7c8d-9e0f1a2b3c4d ro init=/bin/sh debug", "debug"},
+		{"/boot/vmlinuz debug anything=goes", "debug"},
+		// A RHEL 8 GRUB command line, with a device prefix and a trailing NUL.
+		{"(hd0,gpt2)/boot/vmlinuz-4.18.0-513.el8.x86_64 root=/dev/mapper/rhel-root console=ttyS0,38400n8\x00", "rhel"},
+		{"/vmlinuz root=/dev/sda1 console=ttyS0,38400n8", "rhel"},
+	} {
+		got, err := m.Match(tc.cmdline)
+		if err != nil {
+			t.Errorf("Match(%q): %v", tc.cmdline, err)
+		} else if got != tc.want {
+			t.Errorf("Match(%q) = %q, want %q", tc.cmdline, got, tc.want)
+		}
+	}
+
+	for _, cmdline := range []string{
+		// Not a UUID.
+		"/syslinux/vmlinuz.A root=PARTUUID=sda1 ro",
+		// Missing ro.
+		"/syslinux/vmlinuz.A root=PARTUUID=6a1b0c2d-3e4f-5a6b-7c8d-9e0f1a2b3c4d",
+		// Unknown parameter.
+		"/syslinux/vmlinuz.A root=PARTUUID=6a1b0c2d-3e4f-5a6b-7c8d-9e0f1a2b3c4d ro loglevel=high",
+		// Forbidden parameter.
+		"/boot/vmlinuz debug lockdown=none",
+		// Not a path.
+		"(hd0,gpt2)vmlinuz root=/dev/sda1 console=ttyS0,38400n8",
+	} {
+		if got, err := m.Match(cmdline); err == nil {
+			t.Errorf("Match(%q) = %q, want error", cmdline, got)
+		}
+	}
+
+	got, err := m.MatchKernel(&pb.LinuxKernelState{CommandLine: "/boot/vmlinuz debug"})
+	if err != nil || got != "debug" {
+		t.Errorf("MatchKernel() = %q, %v, want \"debug\"", got, err)
+	}
+}
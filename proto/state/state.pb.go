@@ -97,6 +97,8 @@ const (
 	GCEConfidentialTechnology_AMD_SEV_ES  GCEConfidentialTechnology = 2
 	GCEConfidentialTechnology_INTEL_TDX   GCEConfidentialTechnology = 3
 	GCEConfidentialTechnology_AMD_SEV_SNP GCEConfidentialTechnology = 4
+	GCEConfidentialTechnology_ARM_CCA     GCEConfidentialTechnology = 5
+	GCEConfidentialTechnology_RISCV_COVE  GCEConfidentialTechnology = 6
 )
 
 // Enum value maps for GCEConfidentialTechnology.
@@ -107,6 +109,8 @@ var (
 		2: "AMD_SEV_ES",
 		3: "INTEL_TDX",
 		4: "AMD_SEV_SNP",
+		5: "ARM_CCA",
+		6: "RISCV_COVE",
 	}
 	GCEConfidentialTechnology_value = map[string]int32{
 		"NONE":        0,
@@ -114,6 +118,8 @@ var (
 		"AMD_SEV_ES":  2,
 		"INTEL_TDX":   3,
 		"AMD_SEV_SNP": 4,
+		"ARM_CCA":     5,
+		"RISCV_COVE":  6,
 	}
 )
 
@@ -144,6 +150,58 @@ func (GCEConfidentialTechnology) EnumDescriptor() ([]byte, []int) {
 	return file_state_proto_rawDescGZIP(), []int{1}
 }
 
+// The technology used for a dynamic launch.
+type DrtmTechnology int32
+
+const (
+	DrtmTechnology_DRTM_TECHNOLOGY_UNSPECIFIED DrtmTechnology = 0
+	// Intel Trusted Execution Technology (GETSEC[SENTER]).
+	DrtmTechnology_DRTM_TECHNOLOGY_INTEL_TXT DrtmTechnology = 1
+	// AMD Secure Startup (SKINIT).
+	DrtmTechnology_DRTM_TECHNOLOGY_AMD_SKINIT DrtmTechnology = 2
+)
+
+// Enum value maps for DrtmTechnology.
+var (
+	DrtmTechnology_name = map[int32]string{
+		0: "DRTM_TECHNOLOGY_UNSPECIFIED",
+		1: "DRTM_TECHNOLOGY_INTEL_TXT",
+		2: "DRTM_TECHNOLOGY_AMD_SKINIT",
+	}
+	DrtmTechnology_value = map[string]int32{
+		"DRTM_TECHNOLOGY_UNSPECIFIED": 0,
+		"DRTM_TECHNOLOGY_INTEL_TXT":   1,
+		"DRTM_TECHNOLOGY_AMD_SKINIT":  2,
+	}
+)
+
+func (x DrtmTechnology) Enum() *DrtmTechnology {
+	p := new(DrtmTechnology)
+	*p = x
+	return p
+}
+
+func (x DrtmTechnology) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DrtmTechnology) Descriptor() protoreflect.EnumDescriptor {
+	return file_state_proto_enumTypes[2].Descriptor()
+}
+
+func (DrtmTechnology) Type() protoreflect.EnumType {
+	return &file_state_proto_enumTypes[2]
+}
+
+func (x DrtmTechnology) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DrtmTechnology.Descriptor instead.
+func (DrtmTechnology) EnumDescriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{2}
+}
+
 // Common, publicly-listed certificates by different vendors.
 type WellKnownCertificate int32
 
@@ -189,11 +247,11 @@ func (x WellKnownCertificate) String() string {
 }
 
 func (WellKnownCertificate) Descriptor() protoreflect.EnumDescriptor {
-	return file_state_proto_enumTypes[2].Descriptor()
+	return file_state_proto_enumTypes[3].Descriptor()
 }
 
 func (WellKnownCertificate) Type() protoreflect.EnumType {
-	return &file_state_proto_enumTypes[2]
+	return &file_state_proto_enumTypes[3]
 }
 
 func (x WellKnownCertificate) Number() protoreflect.EnumNumber {
@@ -202,7 +260,198 @@ func (x WellKnownCertificate) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use WellKnownCertificate.Descriptor instead.
 func (WellKnownCertificate) EnumDescriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{2}
+	return file_state_proto_rawDescGZIP(), []int{3}
+}
+
+// The trust status of a certificate relative to caller-supplied trust anchors,
+// e.g., an enterprise UEFI CA.
+type TrustStatus int32
+
+const (
+	TrustStatus_TRUST_STATUS_UNSPECIFIED TrustStatus = 0
+	// The certificate is a trust anchor, or was issued by one.
+	TrustStatus_TRUST_STATUS_TRUSTED TrustStatus = 1
+	// The certificate is not related to any of the trust anchors.
+	TrustStatus_TRUST_STATUS_UNTRUSTED TrustStatus = 2
+	// The certificate, or the certificate that issued it, is revoked by dbx,
+	// either directly or by the digest of its TBSCertificate. This takes
+	// precedence over the trust anchors.
+	TrustStatus_TRUST_STATUS_REVOKED TrustStatus = 3
+)
+
+// Enum value maps for TrustStatus.
+var (
+	TrustStatus_name = map[int32]string{
+		0: "TRUST_STATUS_UNSPECIFIED",
+		1: "TRUST_STATUS_TRUSTED",
+		2: "TRUST_STATUS_UNTRUSTED",
+		3: "TRUST_STATUS_REVOKED",
+	}
+	TrustStatus_value = map[string]int32{
+		"TRUST_STATUS_UNSPECIFIED": 0,
+		"TRUST_STATUS_TRUSTED":     1,
+		"TRUST_STATUS_UNTRUSTED":   2,
+		"TRUST_STATUS_REVOKED":     3,
+	}
+)
+
+func (x TrustStatus) Enum() *TrustStatus {
+	p := new(TrustStatus)
+	*p = x
+	return p
+}
+
+func (x TrustStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TrustStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_state_proto_enumTypes[4].Descriptor()
+}
+
+func (TrustStatus) Type() protoreflect.EnumType {
+	return &file_state_proto_enumTypes[4]
+}
+
+func (x TrustStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TrustStatus.Descriptor instead.
+func (TrustStatus) EnumDescriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{4}
+}
+
+// The signature type of an EFI_SIGNATURE_LIST, after its EFI_CERT_*_GUID
+// definition in the UEFI specification.
+type SignatureType int32
+
+const (
+	SignatureType_SIGNATURE_TYPE_UNSPECIFIED    SignatureType = 0
+	SignatureType_SIGNATURE_TYPE_X509           SignatureType = 1
+	SignatureType_SIGNATURE_TYPE_SHA256         SignatureType = 2
+	SignatureType_SIGNATURE_TYPE_SHA1           SignatureType = 3
+	SignatureType_SIGNATURE_TYPE_SHA224         SignatureType = 4
+	SignatureType_SIGNATURE_TYPE_SHA384         SignatureType = 5
+	SignatureType_SIGNATURE_TYPE_SHA512         SignatureType = 6
+	SignatureType_SIGNATURE_TYPE_RSA2048        SignatureType = 7
+	SignatureType_SIGNATURE_TYPE_RSA2048_SHA256 SignatureType = 8
+	SignatureType_SIGNATURE_TYPE_RSA2048_SHA1   SignatureType = 9
+	SignatureType_SIGNATURE_TYPE_X509_SHA256    SignatureType = 10
+	SignatureType_SIGNATURE_TYPE_X509_SHA384    SignatureType = 11
+	SignatureType_SIGNATURE_TYPE_X509_SHA512    SignatureType = 12
+)
+
+// Enum value maps for SignatureType.
+var (
+	SignatureType_name = map[int32]string{
+		0:  "SIGNATURE_TYPE_UNSPECIFIED",
+		1:  "SIGNATURE_TYPE_X509",
+		2:  "SIGNATURE_TYPE_SHA256",
+		3:  "SIGNATURE_TYPE_SHA1",
+		4:  "SIGNATURE_TYPE_SHA224",
+		5:  "SIGNATURE_TYPE_SHA384",
+		6:  "SIGNATURE_TYPE_SHA512",
+		7:  "SIGNATURE_TYPE_RSA2048",
+		8:  "SIGNATURE_TYPE_RSA2048_SHA256",
+		9:  "SIGNATURE_TYPE_RSA2048_SHA1",
+		10: "SIGNATURE_TYPE_X509_SHA256",
+		11: "SIGNATURE_TYPE_X509_SHA384",
+		12: "SIGNATURE_TYPE_X509_SHA512",
+	}
+	SignatureType_value = map[string]int32{
+		"SIGNATURE_TYPE_UNSPECIFIED":    0,
+		"SIGNATURE_TYPE_X509":           1,
+		"SIGNATURE_TYPE_SHA256":         2,
+		"SIGNATURE_TYPE_SHA1":           3,
+		"SIGNATURE_TYPE_SHA224":         4,
+		"SIGNATURE_TYPE_SHA384":         5,
+		"SIGNATURE_TYPE_SHA512":         6,
+		"SIGNATURE_TYPE_RSA2048":        7,
+		"SIGNATURE_TYPE_RSA2048_SHA256": 8,
+		"SIGNATURE_TYPE_RSA2048_SHA1":   9,
+		"SIGNATURE_TYPE_X509_SHA256":    10,
+		"SIGNATURE_TYPE_X509_SHA384":    11,
+		"SIGNATURE_TYPE_X509_SHA512":    12,
+	}
+)
+
+func (x SignatureType) Enum() *SignatureType {
+	p := new(SignatureType)
+	*p = x
+	return p
+}
+
+func (x SignatureType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SignatureType) Descriptor() protoreflect.EnumDescriptor {
+	return file_state_proto_enumTypes[5].Descriptor()
+}
+
+func (SignatureType) Type() protoreflect.EnumType {
+	return &file_state_proto_enumTypes[5]
+}
+
+func (x SignatureType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SignatureType.Descriptor instead.
+func (SignatureType) EnumDescriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{5}
+}
+
+// The result of an ExitBootServices() call, as measured in the event log.
+type ExitBootServicesResult int32
+
+const (
+	// No "Exit Boot Services Returned" event was measured.
+	ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_UNSPECIFIED ExitBootServicesResult = 0
+	ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_SUCCESS     ExitBootServicesResult = 1
+	ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_FAILURE     ExitBootServicesResult = 2
+)
+
+// Enum value maps for ExitBootServicesResult.
+var (
+	ExitBootServicesResult_name = map[int32]string{
+		0: "EXIT_BOOT_SERVICES_RESULT_UNSPECIFIED",
+		1: "EXIT_BOOT_SERVICES_RESULT_SUCCESS",
+		2: "EXIT_BOOT_SERVICES_RESULT_FAILURE",
+	}
+	ExitBootServicesResult_value = map[string]int32{
+		"EXIT_BOOT_SERVICES_RESULT_UNSPECIFIED": 0,
+		"EXIT_BOOT_SERVICES_RESULT_SUCCESS":     1,
+		"EXIT_BOOT_SERVICES_RESULT_FAILURE":     2,
+	}
+)
+
+func (x ExitBootServicesResult) Enum() *ExitBootServicesResult {
+	p := new(ExitBootServicesResult)
+	*p = x
+	return p
+}
+
+func (x ExitBootServicesResult) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExitBootServicesResult) Descriptor() protoreflect.EnumDescriptor {
+	return file_state_proto_enumTypes[6].Descriptor()
+}
+
+func (ExitBootServicesResult) Type() protoreflect.EnumType {
+	return &file_state_proto_enumTypes[6]
+}
+
+func (x ExitBootServicesResult) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExitBootServicesResult.Descriptor instead.
+func (ExitBootServicesResult) EnumDescriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{6}
 }
 
 // Enum values come from the TCG Algorithm Registry - v1.27 - Table 3.
@@ -214,6 +463,9 @@ const (
 	HashAlgo_SHA256       HashAlgo = 11
 	HashAlgo_SHA384       HashAlgo = 12
 	HashAlgo_SHA512       HashAlgo = 13
+	HashAlgo_SHA3_256     HashAlgo = 39
+	HashAlgo_SHA3_384     HashAlgo = 40
+	HashAlgo_SHA3_512     HashAlgo = 41
 )
 
 // Enum value maps for HashAlgo.
@@ -224,6 +476,9 @@ var (
 		11: "SHA256",
 		12: "SHA384",
 		13: "SHA512",
+		39: "SHA3_256",
+		40: "SHA3_384",
+		41: "SHA3_512",
 	}
 	HashAlgo_value = map[string]int32{
 		"HASH_INVALID": 0,
@@ -231,6 +486,9 @@ var (
 		"SHA256":       11,
 		"SHA384":       12,
 		"SHA512":       13,
+		"SHA3_256":     39,
+		"SHA3_384":     40,
+		"SHA3_512":     41,
 	}
 )
 
@@ -245,11 +503,11 @@ func (x HashAlgo) String() string {
 }
 
 func (HashAlgo) Descriptor() protoreflect.EnumDescriptor {
-	return file_state_proto_enumTypes[3].Descriptor()
+	return file_state_proto_enumTypes[7].Descriptor()
 }
 
 func (HashAlgo) Type() protoreflect.EnumType {
-	return &file_state_proto_enumTypes[3]
+	return &file_state_proto_enumTypes[7]
 }
 
 func (x HashAlgo) Number() protoreflect.EnumNumber {
@@ -258,7 +516,115 @@ func (x HashAlgo) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use HashAlgo.Descriptor instead.
 func (HashAlgo) EnumDescriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{3}
+	return file_state_proto_rawDescGZIP(), []int{7}
+}
+
+// The type of a finding about how a FirmwareLogState was verified.
+type FindingType int32
+
+const (
+	FindingType_FINDING_TYPE_UNSPECIFIED FindingType = 0
+	// The event log was replayed against a bank whose hash algorithm is no
+	// longer collision resistant, e.g., SHA-1. Policy should decide whether to
+	// accept the state, as only weak bank values were available.
+	FindingType_FINDING_TYPE_WEAK_BANK FindingType = 1
+	// A register contains a run of events that is immediately measured again,
+	// identically. The log replays, but this suggests measurements were
+	// stuffed or re-measured without a reboot.
+	FindingType_FINDING_TYPE_REPEATED_EVENTS FindingType = 2
+	// The measured SecureBoot, SetupMode, and PK variables contradict each
+	// other, e.g., Secure Boot is enabled in setup mode. This is a known
+	// firmware bug pattern, and the reported Secure Boot state may not reflect
+	// what was enforced.
+	FindingType_FINDING_TYPE_SECURE_BOOT_INCONSISTENT FindingType = 3
+	// The firmware measured legacy (CSM) option ROMs, which run without Secure
+	// Boot verification on some platforms.
+	FindingType_FINDING_TYPE_LEGACY_OPTION_ROM FindingType = 4
+	// The trailing padding of the event log contains bytes other than the fill
+	// byte. These bytes are not measured, and may be hidden data.
+	FindingType_FINDING_TYPE_PADDING_GARBAGE FindingType = 5
+	// The dbx variable contains malformed entries that were skipped, so some
+	// revocations may be missing from SecureBootState.dbx.
+	FindingType_FINDING_TYPE_DBX_PARSE_ERRORS FindingType = 6
+	// The event log was parsed or extracted with quirk workarounds, and
+	// nonstandard events were skipped or accepted. See applied_quirks.
+	FindingType_FINDING_TYPE_QUIRKS_APPLIED FindingType = 7
+	// An event's data size or encoded lengths do not match its type, e.g., a
+	// separator that is not 4 bytes. The event may be malformed or of a
+	// different type than it claims.
+	FindingType_FINDING_TYPE_EVENT_SIZE_ANOMALY FindingType = 8
+	// The load options measured by the Linux EFI stub do not match the kernel
+	// command line measured by the bootloader. The command line may have been
+	// changed between the loader and the kernel.
+	FindingType_FINDING_TYPE_LOAD_OPTIONS_MISMATCH FindingType = 9
+	// The firmware capped PCRs 0-7 with error separators, e.g., on a TPM
+	// error, and stopped measuring. The event log does not describe the boot,
+	// so no state was extracted from it.
+	FindingType_FINDING_TYPE_PCRS_CAPPED FindingType = 10
+	// The events of some registers were replaced by redacted register events
+	// for selective disclosure, see redacted_mrs. Their register values were
+	// verified, but none of their state, e.g., the boot applications, could be
+	// extracted.
+	FindingType_FINDING_TYPE_REDACTED_MRS FindingType = 11
+)
+
+// Enum value maps for FindingType.
+var (
+	FindingType_name = map[int32]string{
+		0:  "FINDING_TYPE_UNSPECIFIED",
+		1:  "FINDING_TYPE_WEAK_BANK",
+		2:  "FINDING_TYPE_REPEATED_EVENTS",
+		3:  "FINDING_TYPE_SECURE_BOOT_INCONSISTENT",
+		4:  "FINDING_TYPE_LEGACY_OPTION_ROM",
+		5:  "FINDING_TYPE_PADDING_GARBAGE",
+		6:  "FINDING_TYPE_DBX_PARSE_ERRORS",
+		7:  "FINDING_TYPE_QUIRKS_APPLIED",
+		8:  "FINDING_TYPE_EVENT_SIZE_ANOMALY",
+		9:  "FINDING_TYPE_LOAD_OPTIONS_MISMATCH",
+		10: "FINDING_TYPE_PCRS_CAPPED",
+		11: "FINDING_TYPE_REDACTED_MRS",
+	}
+	FindingType_value = map[string]int32{
+		"FINDING_TYPE_UNSPECIFIED":              0,
+		"FINDING_TYPE_WEAK_BANK":                1,
+		"FINDING_TYPE_REPEATED_EVENTS":          2,
+		"FINDING_TYPE_SECURE_BOOT_INCONSISTENT": 3,
+		"FINDING_TYPE_LEGACY_OPTION_ROM":        4,
+		"FINDING_TYPE_PADDING_GARBAGE":          5,
+		"FINDING_TYPE_DBX_PARSE_ERRORS":         6,
+		"FINDING_TYPE_QUIRKS_APPLIED":           7,
+		"FINDING_TYPE_EVENT_SIZE_ANOMALY":       8,
+		"FINDING_TYPE_LOAD_OPTIONS_MISMATCH":    9,
+		"FINDING_TYPE_PCRS_CAPPED":              10,
+		"FINDING_TYPE_REDACTED_MRS":             11,
+	}
+)
+
+func (x FindingType) Enum() *FindingType {
+	p := new(FindingType)
+	*p = x
+	return p
+}
+
+func (x FindingType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FindingType) Descriptor() protoreflect.EnumDescriptor {
+	return file_state_proto_enumTypes[8].Descriptor()
+}
+
+func (FindingType) Type() protoreflect.EnumType {
+	return &file_state_proto_enumTypes[8]
+}
+
+func (x FindingType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FindingType.Descriptor instead.
+func (FindingType) EnumDescriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{8}
 }
 
 // Information uniquely identifying a GCE instance. Can be used to create an
@@ -447,6 +813,110 @@ func (*PlatformState_ScrtmVersionId) isPlatformState_Firmware() {}
 
 func (*PlatformState_GceVersion) isPlatformState_Firmware() {}
 
+// The digest of a measurement in one PCR or RTMR bank.
+type BankDigest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash   HashAlgo `protobuf:"varint,1,opt,name=hash,proto3,enum=state.HashAlgo" json:"hash,omitempty"`
+	Digest []byte   `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *BankDigest) Reset() {
+	*x = BankDigest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankDigest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankDigest) ProtoMessage() {}
+
+func (x *BankDigest) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankDigest.ProtoReflect.Descriptor instead.
+func (*BankDigest) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BankDigest) GetHash() HashAlgo {
+	if x != nil {
+		return x.Hash
+	}
+	return HashAlgo_HASH_INVALID
+}
+
+func (x *BankDigest) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+// The digests of a measurement in each verified bank.
+type BankDigests struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Digests []*BankDigest `protobuf:"bytes,1,rep,name=digests,proto3" json:"digests,omitempty"`
+}
+
+func (x *BankDigests) Reset() {
+	*x = BankDigests{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankDigests) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankDigests) ProtoMessage() {}
+
+func (x *BankDigests) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankDigests.ProtoReflect.Descriptor instead.
+func (*BankDigests) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BankDigests) GetDigests() []*BankDigest {
+	if x != nil {
+		return x.Digests
+	}
+	return nil
+}
+
 type GrubFile struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -456,12 +926,18 @@ type GrubFile struct {
 	Digest []byte `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
 	// The event data. This is not measured, so it is untrusted.
 	UntrustedFilename []byte `protobuf:"bytes,2,opt,name=untrusted_filename,json=untrustedFilename,proto3" json:"untrusted_filename,omitempty"`
+	// The hash algorithm of digest.
+	Hash HashAlgo `protobuf:"varint,3,opt,name=hash,proto3,enum=state.HashAlgo" json:"hash,omitempty"`
+	// The digests of the file in each verified bank. A state extracted from
+	// one bank has only its digest. extract.MergeFirmwareLogStates adds the
+	// digests of the other banks, after those of the authoritative bank.
+	BankDigests []*BankDigest `protobuf:"bytes,4,rep,name=bank_digests,json=bankDigests,proto3" json:"bank_digests,omitempty"`
 }
 
 func (x *GrubFile) Reset() {
 	*x = GrubFile{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[2]
+		mi := &file_state_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -474,7 +950,7 @@ func (x *GrubFile) String() string {
 func (*GrubFile) ProtoMessage() {}
 
 func (x *GrubFile) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[2]
+	mi := &file_state_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -487,7 +963,7 @@ func (x *GrubFile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GrubFile.ProtoReflect.Descriptor instead.
 func (*GrubFile) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{2}
+	return file_state_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GrubFile) GetDigest() []byte {
@@ -504,6 +980,20 @@ func (x *GrubFile) GetUntrustedFilename() []byte {
 	return nil
 }
 
+func (x *GrubFile) GetHash() HashAlgo {
+	if x != nil {
+		return x.Hash
+	}
+	return HashAlgo_HASH_INVALID
+}
+
+func (x *GrubFile) GetBankDigests() []*BankDigest {
+	if x != nil {
+		return x.BankDigests
+	}
+	return nil
+}
+
 type GrubState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -514,12 +1004,21 @@ type GrubState struct {
 	// A list of executed GRUB commands and command lines passed to the kernel
 	// and kernel modules.
 	Commands []string `protobuf:"bytes,2,rep,name=commands,proto3" json:"commands,omitempty"`
+	// The commands with cosmetic differences removed, for matching across a
+	// fleet. normalized_commands[i] is the normalized form of commands[i].
+	// See extract.NormalizeGRUBCommand for the normalization applied.
+	NormalizedCommands []string `protobuf:"bytes,3,rep,name=normalized_commands,json=normalizedCommands,proto3" json:"normalized_commands,omitempty"`
+	// The digests of the command events in each verified bank.
+	// command_digests[i] holds the digests of commands[i]. As with
+	// GrubFile.bank_digests, extract.MergeFirmwareLogStates adds the digests of
+	// the other banks.
+	CommandDigests []*BankDigests `protobuf:"bytes,4,rep,name=command_digests,json=commandDigests,proto3" json:"command_digests,omitempty"`
 }
 
 func (x *GrubState) Reset() {
 	*x = GrubState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[3]
+		mi := &file_state_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -532,7 +1031,7 @@ func (x *GrubState) String() string {
 func (*GrubState) ProtoMessage() {}
 
 func (x *GrubState) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[3]
+	mi := &file_state_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -545,7 +1044,7 @@ func (x *GrubState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GrubState.ProtoReflect.Descriptor instead.
 func (*GrubState) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{3}
+	return file_state_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GrubState) GetFiles() []*GrubFile {
@@ -562,9 +1061,24 @@ func (x *GrubState) GetCommands() []string {
 	return nil
 }
 
+func (x *GrubState) GetNormalizedCommands() []string {
+	if x != nil {
+		return x.NormalizedCommands
+	}
+	return nil
+}
+
+func (x *GrubState) GetCommandDigests() []*BankDigests {
+	if x != nil {
+		return x.CommandDigests
+	}
+	return nil
+}
+
 // The state of the Linux kernel.
-// At the moment, parsing LinuxKernelState relies on parsing the GrubState.
-// To do so, use ExtractOpts{Loader: GRUB} when calling ParseMachineState.
+// At the moment, parsing the command line relies on parsing the GrubState or
+// the systemd-boot events. To do so, use ExtractOpts{Loader: GRUB} or
+// ExtractOpts{Loader: SystemdBoot} when calling ParseMachineState.
 type LinuxKernelState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -572,12 +1086,26 @@ type LinuxKernelState struct {
 
 	// The kernel command line.
 	CommandLine string `protobuf:"bytes,1,opt,name=command_line,json=commandLine,proto3" json:"command_line,omitempty"`
+	// The digest of the kernel's load options, i.e., the UTF-16 command line
+	// passed by the loader, measured by the Linux EFI stub in an EV_EVENT_TAG
+	// event. Empty if the stub did not measure them.
+	LoadOptionsDigest []byte `protobuf:"bytes,2,opt,name=load_options_digest,json=loadOptionsDigest,proto3" json:"load_options_digest,omitempty"`
+	// The digest of the initrd measured by the Linux EFI stub in an
+	// EV_EVENT_TAG event. Empty if the stub did not measure it.
+	InitrdDigest []byte `protobuf:"bytes,3,opt,name=initrd_digest,json=initrdDigest,proto3" json:"initrd_digest,omitempty"`
+	// Whether load_options_digest is the digest of command_line, as passed to
+	// the kernel by the loader. False if either is missing.
+	LoadOptionsMatch bool `protobuf:"varint,4,opt,name=load_options_match,json=loadOptionsMatch,proto3" json:"load_options_match,omitempty"`
+	// The digest of the kernel image, measured by the firmware when the loader
+	// started it as an EFI application. Only set for loaders that do so, e.g.,
+	// systemd-boot.
+	KernelDigest []byte `protobuf:"bytes,5,opt,name=kernel_digest,json=kernelDigest,proto3" json:"kernel_digest,omitempty"`
 }
 
 func (x *LinuxKernelState) Reset() {
 	*x = LinuxKernelState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[4]
+		mi := &file_state_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -590,7 +1118,7 @@ func (x *LinuxKernelState) String() string {
 func (*LinuxKernelState) ProtoMessage() {}
 
 func (x *LinuxKernelState) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[4]
+	mi := &file_state_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -603,7 +1131,7 @@ func (x *LinuxKernelState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LinuxKernelState.ProtoReflect.Descriptor instead.
 func (*LinuxKernelState) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{4}
+	return file_state_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *LinuxKernelState) GetCommandLine() string {
@@ -613,130 +1141,63 @@ func (x *LinuxKernelState) GetCommandLine() string {
 	return ""
 }
 
-// A parsed event from the source firmware event log. This can be from either
-// the firmware TPM event log, the Confidential Computing event log, or any
-// other TCG-like event log used by firmware to record its measurements.
-type Event struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// The register this event was extended into. Can be PCR, RTMR, etc.
-	// Named pcr_index for backcompat reasons.
-	PcrIndex uint32 `protobuf:"varint,1,opt,name=pcr_index,json=pcrIndex,proto3" json:"pcr_index,omitempty"`
-	// The type of this event. Note that this value is not verified, so it should
-	// only be used as a hint during event parsing.
-	UntrustedType uint32 `protobuf:"varint,2,opt,name=untrusted_type,json=untrustedType,proto3" json:"untrusted_type,omitempty"`
-	// The raw data associated to this event. The meaning of this data is
-	// specific to the type of the event.
-	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
-	// The event digest actually extended into the TPM. This is often the hash of
-	// the data field, but in some cases it may have a type-specific calculation.
-	Digest []byte `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"`
-	// This is true if hash(data) == digest.
-	DigestVerified bool `protobuf:"varint,5,opt,name=digest_verified,json=digestVerified,proto3" json:"digest_verified,omitempty"`
-}
-
-func (x *Event) Reset() {
-	*x = Event{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *Event) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*Event) ProtoMessage() {}
-
-func (x *Event) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use Event.ProtoReflect.Descriptor instead.
-func (*Event) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *Event) GetPcrIndex() uint32 {
-	if x != nil {
-		return x.PcrIndex
-	}
-	return 0
-}
-
-func (x *Event) GetUntrustedType() uint32 {
+func (x *LinuxKernelState) GetLoadOptionsDigest() []byte {
 	if x != nil {
-		return x.UntrustedType
+		return x.LoadOptionsDigest
 	}
-	return 0
+	return nil
 }
 
-func (x *Event) GetData() []byte {
+func (x *LinuxKernelState) GetInitrdDigest() []byte {
 	if x != nil {
-		return x.Data
+		return x.InitrdDigest
 	}
 	return nil
 }
 
-func (x *Event) GetDigest() []byte {
+func (x *LinuxKernelState) GetLoadOptionsMatch() bool {
 	if x != nil {
-		return x.Digest
+		return x.LoadOptionsMatch
 	}
-	return nil
+	return false
 }
 
-func (x *Event) GetDigestVerified() bool {
+func (x *LinuxKernelState) GetKernelDigest() []byte {
 	if x != nil {
-		return x.DigestVerified
+		return x.KernelDigest
 	}
-	return false
+	return nil
 }
 
-type Certificate struct {
+// A PE section of a unified kernel image (UKI), measured by systemd-stub.
+type UkiSection struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The representation of the certificate. If the certificate matches a
-	// well-known certificate above, representation should contain the value in
-	// the enum. Otherwise, it will contain the raw DER.
-	//
-	// Types that are assignable to Representation:
-	//
-	//	*Certificate_Der
-	//	*Certificate_WellKnown
-	Representation isCertificate_Representation `protobuf_oneof:"representation"`
+	// The section name, e.g., ".linux" or ".cmdline".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The digest of the section contents.
+	Digest []byte `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
 }
 
-func (x *Certificate) Reset() {
-	*x = Certificate{}
+func (x *UkiSection) Reset() {
+	*x = UkiSection{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[6]
+		mi := &file_state_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Certificate) String() string {
+func (x *UkiSection) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Certificate) ProtoMessage() {}
+func (*UkiSection) ProtoMessage() {}
 
-func (x *Certificate) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[6]
+func (x *UkiSection) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -747,77 +1208,55 @@ func (x *Certificate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Certificate.ProtoReflect.Descriptor instead.
-func (*Certificate) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use UkiSection.ProtoReflect.Descriptor instead.
+func (*UkiSection) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{7}
 }
 
-func (m *Certificate) GetRepresentation() isCertificate_Representation {
-	if m != nil {
-		return m.Representation
+func (x *UkiSection) GetName() string {
+	if x != nil {
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *Certificate) GetDer() []byte {
-	if x, ok := x.GetRepresentation().(*Certificate_Der); ok {
-		return x.Der
+func (x *UkiSection) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
 	}
 	return nil
 }
 
-func (x *Certificate) GetWellKnown() WellKnownCertificate {
-	if x, ok := x.GetRepresentation().(*Certificate_WellKnown); ok {
-		return x.WellKnown
-	}
-	return WellKnownCertificate_UNKNOWN
-}
-
-type isCertificate_Representation interface {
-	isCertificate_Representation()
-}
-
-type Certificate_Der struct {
-	// DER representation of the certificate.
-	Der []byte `protobuf:"bytes,1,opt,name=der,proto3,oneof"`
-}
-
-type Certificate_WellKnown struct {
-	WellKnown WellKnownCertificate `protobuf:"varint,2,opt,name=well_known,json=wellKnown,proto3,enum=state.WellKnownCertificate,oneof"`
-}
-
-func (*Certificate_Der) isCertificate_Representation() {}
-
-func (*Certificate_WellKnown) isCertificate_Representation() {}
-
-// A Secure Boot database containing lists of hashes and certificates,
-// as defined by section 32.4.1 Signature Database in the UEFI spec.
-type Database struct {
+// The state of a unified kernel image (UKI) boot, from the EV_IPL events
+// measured into PCR 11 by systemd-stub and systemd-pcrphase.
+type UkiState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Certs  []*Certificate `protobuf:"bytes,1,rep,name=certs,proto3" json:"certs,omitempty"`
-	Hashes [][]byte       `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	// The measured sections of the UKI, in measurement order.
+	Sections []*UkiSection `protobuf:"bytes,1,rep,name=sections,proto3" json:"sections,omitempty"`
+	// The boot phases, e.g., "enter-initrd" or "ready", in measurement order.
+	Phases []string `protobuf:"bytes,2,rep,name=phases,proto3" json:"phases,omitempty"`
 }
 
-func (x *Database) Reset() {
-	*x = Database{}
+func (x *UkiState) Reset() {
+	*x = UkiState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[7]
+		mi := &file_state_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Database) String() string {
+func (x *UkiState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Database) ProtoMessage() {}
+func (*UkiState) ProtoMessage() {}
 
-func (x *Database) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[7]
+func (x *UkiState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -828,63 +1267,74 @@ func (x *Database) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Database.ProtoReflect.Descriptor instead.
-func (*Database) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use UkiState.ProtoReflect.Descriptor instead.
+func (*UkiState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Database) GetCerts() []*Certificate {
+func (x *UkiState) GetSections() []*UkiSection {
 	if x != nil {
-		return x.Certs
+		return x.Sections
 	}
 	return nil
 }
 
-func (x *Database) GetHashes() [][]byte {
+func (x *UkiState) GetPhases() []string {
 	if x != nil {
-		return x.Hashes
+		return x.Phases
 	}
 	return nil
 }
 
-// The Secure Boot state for this instance.
-type SecureBootState struct {
+// The state of a Windows boot, from the System Integrity Platform Attestation
+// (SIPA) events measured by the Windows Boot Manager and kernel in the WBCL.
+type WindowsSipaState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Whether Secure Boot is enabled.
-	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	// The Secure Boot signature (allowed) database.
-	Db *Database `protobuf:"bytes,2,opt,name=db,proto3" json:"db,omitempty"`
-	// The Secure Boot revoked signature (forbidden) database.
-	Dbx *Database `protobuf:"bytes,3,opt,name=dbx,proto3" json:"dbx,omitempty"`
-	// Authority events post-separator. Pre-separator authorities
-	// are currently not supported.
-	Authority *Database `protobuf:"bytes,4,opt,name=authority,proto3" json:"authority,omitempty"`
-	// The Secure Boot Platform key, used to sign key exchange keys.
-	Pk *Database `protobuf:"bytes,5,opt,name=pk,proto3" json:"pk,omitempty"`
-	// The Secure Boot Key Exchange Keys, used to sign db and dbx updates.
-	Kek *Database `protobuf:"bytes,6,opt,name=kek,proto3" json:"kek,omitempty"`
-}
-
-func (x *SecureBootState) Reset() {
-	*x = SecureBootState{}
+	// The boot counter, which Windows increments on every boot.
+	BootCount uint64 `protobuf:"varint,1,opt,name=boot_count,json=bootCount,proto3" json:"boot_count,omitempty"`
+	// Whether boot debugging was enabled in the boot configuration.
+	BootDebuggingEnabled bool `protobuf:"varint,2,opt,name=boot_debugging_enabled,json=bootDebuggingEnabled,proto3" json:"boot_debugging_enabled,omitempty"`
+	// Whether kernel debugging was enabled.
+	KernelDebugEnabled bool `protobuf:"varint,3,opt,name=kernel_debug_enabled,json=kernelDebugEnabled,proto3" json:"kernel_debug_enabled,omitempty"`
+	// Whether code integrity was enforced.
+	CodeIntegrityEnabled bool `protobuf:"varint,4,opt,name=code_integrity_enabled,json=codeIntegrityEnabled,proto3" json:"code_integrity_enabled,omitempty"`
+	// Whether test-signed code was allowed to load.
+	TestSigningEnabled bool `protobuf:"varint,5,opt,name=test_signing_enabled,json=testSigningEnabled,proto3" json:"test_signing_enabled,omitempty"`
+	// Whether data execution prevention was enabled.
+	DepEnabled bool `protobuf:"varint,6,opt,name=dep_enabled,json=depEnabled,proto3" json:"dep_enabled,omitempty"`
+	// Whether Windows booted into safe mode.
+	SafeMode bool `protobuf:"varint,7,opt,name=safe_mode,json=safeMode,proto3" json:"safe_mode,omitempty"`
+	// Whether Windows booted into Windows PE.
+	Winpe bool `protobuf:"varint,8,opt,name=winpe,proto3" json:"winpe,omitempty"`
+	// The hypervisor launch type. 0 if the hypervisor was not launched.
+	HypervisorLaunchType uint64 `protobuf:"varint,9,opt,name=hypervisor_launch_type,json=hypervisorLaunchType,proto3" json:"hypervisor_launch_type,omitempty"`
+	// The flags of each BitLocker volume unlock, in log order.
+	BitlockerUnlocks []uint32 `protobuf:"varint,10,rep,packed,name=bitlocker_unlocks,json=bitlockerUnlocks,proto3" json:"bitlocker_unlocks,omitempty"`
+	// The boot modules loaded by the Windows Boot Manager and kernel, in log
+	// order.
+	LoadedModules []*WindowsLoadedModule `protobuf:"bytes,11,rep,name=loaded_modules,json=loadedModules,proto3" json:"loaded_modules,omitempty"`
+}
+
+func (x *WindowsSipaState) Reset() {
+	*x = WindowsSipaState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[8]
+		mi := &file_state_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecureBootState) String() string {
+func (x *WindowsSipaState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecureBootState) ProtoMessage() {}
+func (*WindowsSipaState) ProtoMessage() {}
 
-func (x *SecureBootState) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[8]
+func (x *WindowsSipaState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -895,80 +1345,130 @@ func (x *SecureBootState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecureBootState.ProtoReflect.Descriptor instead.
-func (*SecureBootState) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use WindowsSipaState.ProtoReflect.Descriptor instead.
+func (*WindowsSipaState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *SecureBootState) GetEnabled() bool {
+func (x *WindowsSipaState) GetBootCount() uint64 {
 	if x != nil {
-		return x.Enabled
+		return x.BootCount
+	}
+	return 0
+}
+
+func (x *WindowsSipaState) GetBootDebuggingEnabled() bool {
+	if x != nil {
+		return x.BootDebuggingEnabled
 	}
 	return false
 }
 
-func (x *SecureBootState) GetDb() *Database {
+func (x *WindowsSipaState) GetKernelDebugEnabled() bool {
 	if x != nil {
-		return x.Db
+		return x.KernelDebugEnabled
 	}
-	return nil
+	return false
 }
 
-func (x *SecureBootState) GetDbx() *Database {
+func (x *WindowsSipaState) GetCodeIntegrityEnabled() bool {
 	if x != nil {
-		return x.Dbx
+		return x.CodeIntegrityEnabled
 	}
-	return nil
+	return false
 }
 
-func (x *SecureBootState) GetAuthority() *Database {
+func (x *WindowsSipaState) GetTestSigningEnabled() bool {
 	if x != nil {
-		return x.Authority
+		return x.TestSigningEnabled
 	}
-	return nil
+	return false
 }
 
-func (x *SecureBootState) GetPk() *Database {
+func (x *WindowsSipaState) GetDepEnabled() bool {
 	if x != nil {
-		return x.Pk
+		return x.DepEnabled
+	}
+	return false
+}
+
+func (x *WindowsSipaState) GetSafeMode() bool {
+	if x != nil {
+		return x.SafeMode
+	}
+	return false
+}
+
+func (x *WindowsSipaState) GetWinpe() bool {
+	if x != nil {
+		return x.Winpe
+	}
+	return false
+}
+
+func (x *WindowsSipaState) GetHypervisorLaunchType() uint64 {
+	if x != nil {
+		return x.HypervisorLaunchType
+	}
+	return 0
+}
+
+func (x *WindowsSipaState) GetBitlockerUnlocks() []uint32 {
+	if x != nil {
+		return x.BitlockerUnlocks
 	}
 	return nil
 }
 
-func (x *SecureBootState) GetKek() *Database {
+func (x *WindowsSipaState) GetLoadedModules() []*WindowsLoadedModule {
 	if x != nil {
-		return x.Kek
+		return x.LoadedModules
 	}
 	return nil
 }
 
-type EfiApp struct {
+// A module measured in a SIPAEVENT_LOADEDMODULE_AGGREGATION.
+type WindowsLoadedModule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The PE/COFF digest of the EFI application (pulled from the raw event digest).
-	// This can also represent digest of the EFI boot/runtime service drivers.
-	Digest []byte `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
-}
-
-func (x *EfiApp) Reset() {
-	*x = EfiApp{}
+	// The file path of the module.
+	FilePath string `protobuf:"bytes,1,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	// The size of the image in bytes.
+	ImageSize uint64 `protobuf:"varint,2,opt,name=image_size,json=imageSize,proto3" json:"image_size,omitempty"`
+	// The Windows ALG_ID of authenticode_hash, e.g., 0x800c for SHA-256.
+	HashAlgorithmId uint32 `protobuf:"varint,3,opt,name=hash_algorithm_id,json=hashAlgorithmId,proto3" json:"hash_algorithm_id,omitempty"`
+	// The Authenticode hash of the image.
+	AuthenticodeHash []byte `protobuf:"bytes,4,opt,name=authenticode_hash,json=authenticodeHash,proto3" json:"authenticode_hash,omitempty"`
+	// The issuer and publisher names of the certificate the image was signed
+	// with.
+	AuthorityIssuer    string `protobuf:"bytes,5,opt,name=authority_issuer,json=authorityIssuer,proto3" json:"authority_issuer,omitempty"`
+	AuthorityPublisher string `protobuf:"bytes,6,opt,name=authority_publisher,json=authorityPublisher,proto3" json:"authority_publisher,omitempty"`
+	// The serial number and SHA-1 thumbprint of the signing certificate.
+	AuthoritySerial         []byte `protobuf:"bytes,7,opt,name=authority_serial,json=authoritySerial,proto3" json:"authority_serial,omitempty"`
+	AuthoritySha1Thumbprint []byte `protobuf:"bytes,8,opt,name=authority_sha1_thumbprint,json=authoritySha1Thumbprint,proto3" json:"authority_sha1_thumbprint,omitempty"`
+	// Whether the image signature was validated.
+	ImageValidated bool `protobuf:"varint,9,opt,name=image_validated,json=imageValidated,proto3" json:"image_validated,omitempty"`
+}
+
+func (x *WindowsLoadedModule) Reset() {
+	*x = WindowsLoadedModule{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[9]
+		mi := &file_state_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *EfiApp) String() string {
+func (x *WindowsLoadedModule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EfiApp) ProtoMessage() {}
+func (*WindowsLoadedModule) ProtoMessage() {}
 
-func (x *EfiApp) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[9]
+func (x *WindowsLoadedModule) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -979,55 +1479,3181 @@ func (x *EfiApp) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EfiApp.ProtoReflect.Descriptor instead.
-func (*EfiApp) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use WindowsLoadedModule.ProtoReflect.Descriptor instead.
+func (*WindowsLoadedModule) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *EfiApp) GetDigest() []byte {
+func (x *WindowsLoadedModule) GetFilePath() string {
 	if x != nil {
-		return x.Digest
+		return x.FilePath
 	}
-	return nil
+	return ""
 }
 
-// The verified state of EFI Drivers and Applications. Policy usage on this machine state
+func (x *WindowsLoadedModule) GetImageSize() uint64 {
+	if x != nil {
+		return x.ImageSize
+	}
+	return 0
+}
+
+func (x *WindowsLoadedModule) GetHashAlgorithmId() uint32 {
+	if x != nil {
+		return x.HashAlgorithmId
+	}
+	return 0
+}
+
+func (x *WindowsLoadedModule) GetAuthenticodeHash() []byte {
+	if x != nil {
+		return x.AuthenticodeHash
+	}
+	return nil
+}
+
+func (x *WindowsLoadedModule) GetAuthorityIssuer() string {
+	if x != nil {
+		return x.AuthorityIssuer
+	}
+	return ""
+}
+
+func (x *WindowsLoadedModule) GetAuthorityPublisher() string {
+	if x != nil {
+		return x.AuthorityPublisher
+	}
+	return ""
+}
+
+func (x *WindowsLoadedModule) GetAuthoritySerial() []byte {
+	if x != nil {
+		return x.AuthoritySerial
+	}
+	return nil
+}
+
+func (x *WindowsLoadedModule) GetAuthoritySha1Thumbprint() []byte {
+	if x != nil {
+		return x.AuthoritySha1Thumbprint
+	}
+	return nil
+}
+
+func (x *WindowsLoadedModule) GetImageValidated() bool {
+	if x != nil {
+		return x.ImageValidated
+	}
+	return false
+}
+
+// A kernel generation loaded via kexec by an already-running kernel.
+// Loader measurements (GRUB-style EV_IPL events) recorded after
+// ExitBootServices mark the boundary between generations.
+type KexecState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The loader measurements for this generation.
+	Grub *GrubState `protobuf:"bytes,1,opt,name=grub,proto3" json:"grub,omitempty"`
+	// The state of the kexec-loaded kernel.
+	LinuxKernel *LinuxKernelState `protobuf:"bytes,2,opt,name=linux_kernel,json=linuxKernel,proto3" json:"linux_kernel,omitempty"`
+}
+
+func (x *KexecState) Reset() {
+	*x = KexecState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KexecState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KexecState) ProtoMessage() {}
+
+func (x *KexecState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KexecState.ProtoReflect.Descriptor instead.
+func (*KexecState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *KexecState) GetGrub() *GrubState {
+	if x != nil {
+		return x.Grub
+	}
+	return nil
+}
+
+func (x *KexecState) GetLinuxKernel() *LinuxKernelState {
+	if x != nil {
+		return x.LinuxKernel
+	}
+	return nil
+}
+
+// A parsed event from the source firmware event log. This can be from either
+// the firmware TPM event log, the Confidential Computing event log, or any
+// other TCG-like event log used by firmware to record its measurements.
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The register this event was extended into. Can be PCR, RTMR, etc.
+	// Named pcr_index for backcompat reasons.
+	PcrIndex uint32 `protobuf:"varint,1,opt,name=pcr_index,json=pcrIndex,proto3" json:"pcr_index,omitempty"`
+	// The type of this event. Note that this value is not verified, so it should
+	// only be used as a hint during event parsing.
+	UntrustedType uint32 `protobuf:"varint,2,opt,name=untrusted_type,json=untrustedType,proto3" json:"untrusted_type,omitempty"`
+	// The raw data associated to this event. The meaning of this data is
+	// specific to the type of the event.
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// The event digest actually extended into the TPM. This is often the hash of
+	// the data field, but in some cases it may have a type-specific calculation.
+	Digest []byte `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"`
+	// This is true if hash(data) == digest.
+	DigestVerified bool `protobuf:"varint,5,opt,name=digest_verified,json=digestVerified,proto3" json:"digest_verified,omitempty"`
+	// A human-readable summary of the event: the type name and facts decoded
+	// from the data, e.g., "EFI Action: Exit Boot Services Invocation". Only
+	// populated when requested. It is decoded from the data, so it is only as
+	// trustworthy as the data.
+	Summary string `protobuf:"bytes,6,opt,name=summary,proto3" json:"summary,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Event) GetPcrIndex() uint32 {
+	if x != nil {
+		return x.PcrIndex
+	}
+	return 0
+}
+
+func (x *Event) GetUntrustedType() uint32 {
+	if x != nil {
+		return x.UntrustedType
+	}
+	return 0
+}
+
+func (x *Event) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Event) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *Event) GetDigestVerified() bool {
+	if x != nil {
+		return x.DigestVerified
+	}
+	return false
+}
+
+func (x *Event) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+// The state of a dynamic launch (DRTM), such as Intel TXT with tboot, from
+// the events in the DRTM PCRs 17-19.
+type DrtmState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The digest extended for the SINIT ACM measurement (EVTYPE_HASH_START).
+	SinitDigest []byte `protobuf:"bytes,1,opt,name=sinit_digest,json=sinitDigest,proto3" json:"sinit_digest,omitempty"`
+	// The digest extended for the SINIT signing key (EVTYPE_SINIT_PUBKEY_HASH).
+	SinitPubkeyDigest []byte `protobuf:"bytes,2,opt,name=sinit_pubkey_digest,json=sinitPubkeyDigest,proto3" json:"sinit_pubkey_digest,omitempty"`
+	// The digest extended for the measured launch environment (MLE), e.g.,
+	// tboot (EVTYPE_MLE_HASH).
+	MleDigest []byte `protobuf:"bytes,3,opt,name=mle_digest,json=mleDigest,proto3" json:"mle_digest,omitempty"`
+	// Events measured by the MLE, e.g., tboot's EV_EVENT_TAG measurements of
+	// the kernel, initrd, and command line.
+	MleEvents []*Event `protobuf:"bytes,4,rep,name=mle_events,json=mleEvents,proto3" json:"mle_events,omitempty"`
+	// All DRTM events, in log order.
+	Events []*Event `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+	// The dynamic launch technology, inferred from the DRTM events.
+	Technology DrtmTechnology `protobuf:"varint,6,opt,name=technology,proto3,enum=state.DrtmTechnology" json:"technology,omitempty"`
+	// Linux Secure Launch measurements (EVTYPE_SLAUNCH), e.g., of the boot
+	// parameters, kernel command line, and initramfs. The event data is a
+	// description of the measured object, not the object itself.
+	SecureLaunchEvents []*Event `protobuf:"bytes,7,rep,name=secure_launch_events,json=secureLaunchEvents,proto3" json:"secure_launch_events,omitempty"`
+}
+
+func (x *DrtmState) Reset() {
+	*x = DrtmState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrtmState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrtmState) ProtoMessage() {}
+
+func (x *DrtmState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrtmState.ProtoReflect.Descriptor instead.
+func (*DrtmState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DrtmState) GetSinitDigest() []byte {
+	if x != nil {
+		return x.SinitDigest
+	}
+	return nil
+}
+
+func (x *DrtmState) GetSinitPubkeyDigest() []byte {
+	if x != nil {
+		return x.SinitPubkeyDigest
+	}
+	return nil
+}
+
+func (x *DrtmState) GetMleDigest() []byte {
+	if x != nil {
+		return x.MleDigest
+	}
+	return nil
+}
+
+func (x *DrtmState) GetMleEvents() []*Event {
+	if x != nil {
+		return x.MleEvents
+	}
+	return nil
+}
+
+func (x *DrtmState) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *DrtmState) GetTechnology() DrtmTechnology {
+	if x != nil {
+		return x.Technology
+	}
+	return DrtmTechnology_DRTM_TECHNOLOGY_UNSPECIFIED
+}
+
+func (x *DrtmState) GetSecureLaunchEvents() []*Event {
+	if x != nil {
+		return x.SecureLaunchEvents
+	}
+	return nil
+}
+
+type Certificate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The representation of the certificate. If the certificate matches a
+	// well-known certificate above, representation should contain the value in
+	// the enum. Otherwise, it will contain the raw DER.
+	//
+	// Types that are assignable to Representation:
+	//
+	//	*Certificate_Der
+	//	*Certificate_WellKnown
+	Representation isCertificate_Representation `protobuf_oneof:"representation"`
+	// Parsed fields of the certificate. Only set when requested at extraction
+	// time.
+	Metadata *CertificateMetadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// Whether the certificate is trusted relative to the trust anchors given at
+	// extraction time, or revoked by dbx. Only set for the db and authority
+	// certificates when trust anchors are given.
+	Trust TrustStatus `protobuf:"varint,4,opt,name=trust,proto3,enum=state.TrustStatus" json:"trust,omitempty"`
+	// The name of the trust anchor the certificate is or was issued by, if
+	// trusted.
+	TrustAnchor string `protobuf:"bytes,5,opt,name=trust_anchor,json=trustAnchor,proto3" json:"trust_anchor,omitempty"`
+}
+
+func (x *Certificate) Reset() {
+	*x = Certificate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Certificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Certificate) ProtoMessage() {}
+
+func (x *Certificate) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Certificate.ProtoReflect.Descriptor instead.
+func (*Certificate) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{14}
+}
+
+func (m *Certificate) GetRepresentation() isCertificate_Representation {
+	if m != nil {
+		return m.Representation
+	}
+	return nil
+}
+
+func (x *Certificate) GetDer() []byte {
+	if x, ok := x.GetRepresentation().(*Certificate_Der); ok {
+		return x.Der
+	}
+	return nil
+}
+
+func (x *Certificate) GetWellKnown() WellKnownCertificate {
+	if x, ok := x.GetRepresentation().(*Certificate_WellKnown); ok {
+		return x.WellKnown
+	}
+	return WellKnownCertificate_UNKNOWN
+}
+
+func (x *Certificate) GetMetadata() *CertificateMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Certificate) GetTrust() TrustStatus {
+	if x != nil {
+		return x.Trust
+	}
+	return TrustStatus_TRUST_STATUS_UNSPECIFIED
+}
+
+func (x *Certificate) GetTrustAnchor() string {
+	if x != nil {
+		return x.TrustAnchor
+	}
+	return ""
+}
+
+type isCertificate_Representation interface {
+	isCertificate_Representation()
+}
+
+type Certificate_Der struct {
+	// DER representation of the certificate.
+	Der []byte `protobuf:"bytes,1,opt,name=der,proto3,oneof"`
+}
+
+type Certificate_WellKnown struct {
+	WellKnown WellKnownCertificate `protobuf:"varint,2,opt,name=well_known,json=wellKnown,proto3,enum=state.WellKnownCertificate,oneof"`
+}
+
+func (*Certificate_Der) isCertificate_Representation() {}
+
+func (*Certificate_WellKnown) isCertificate_Representation() {}
+
+// Parsed fields of an X.509 certificate, so consumers do not need to re-parse
+// the DER for display and policy.
+type CertificateMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The subject distinguished name, in RFC 2253 form.
+	Subject string `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	// The issuer distinguished name, in RFC 2253 form.
+	Issuer string `protobuf:"bytes,2,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	// The start of the validity period, in seconds since the Unix epoch.
+	NotBefore int64 `protobuf:"varint,3,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	// The end of the validity period, in seconds since the Unix epoch.
+	NotAfter int64 `protobuf:"varint,4,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	// The SHA-256 digest of the DER.
+	Sha256Fingerprint []byte `protobuf:"bytes,5,opt,name=sha256_fingerprint,json=sha256Fingerprint,proto3" json:"sha256_fingerprint,omitempty"`
+}
+
+func (x *CertificateMetadata) Reset() {
+	*x = CertificateMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CertificateMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CertificateMetadata) ProtoMessage() {}
+
+func (x *CertificateMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CertificateMetadata.ProtoReflect.Descriptor instead.
+func (*CertificateMetadata) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CertificateMetadata) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *CertificateMetadata) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *CertificateMetadata) GetNotBefore() int64 {
+	if x != nil {
+		return x.NotBefore
+	}
+	return 0
+}
+
+func (x *CertificateMetadata) GetNotAfter() int64 {
+	if x != nil {
+		return x.NotAfter
+	}
+	return 0
+}
+
+func (x *CertificateMetadata) GetSha256Fingerprint() []byte {
+	if x != nil {
+		return x.Sha256Fingerprint
+	}
+	return nil
+}
+
+// A Secure Boot database containing lists of hashes and certificates,
+// as defined by section 32.4.1 Signature Database in the UEFI spec.
+type Database struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Certs  []*Certificate `protobuf:"bytes,1,rep,name=certs,proto3" json:"certs,omitempty"`
+	Hashes [][]byte       `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	// Every EFI_SIGNATURE_DATA entry of the EFI_SIGNATURE_LISTs in the
+	// variable, in variable order. Only set for the PK, KEK, db, and dbx
+	// databases.
+	Entries []*SignatureEntry `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *Database) Reset() {
+	*x = Database{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Database) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Database) ProtoMessage() {}
+
+func (x *Database) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Database.ProtoReflect.Descriptor instead.
+func (*Database) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Database) GetCerts() []*Certificate {
+	if x != nil {
+		return x.Certs
+	}
+	return nil
+}
+
+func (x *Database) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+func (x *Database) GetEntries() []*SignatureEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// An EFI_SIGNATURE_DATA entry of a Secure Boot database.
+type SignatureEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The signature type of the entry's list, or SIGNATURE_TYPE_UNSPECIFIED if
+	// the type is unknown.
+	Type SignatureType `protobuf:"varint,1,opt,name=type,proto3,enum=state.SignatureType" json:"type,omitempty"`
+	// The SignatureType GUID of the entry's list.
+	TypeGuid string `protobuf:"bytes,2,opt,name=type_guid,json=typeGuid,proto3" json:"type_guid,omitempty"`
+	// The SignatureOwner GUID, e.g., "77fa9abd-0359-4d32-bd60-28f4e78f784b" for
+	// Microsoft.
+	Owner string `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	// The signature. Certificates and SHA-256 hashes refer to the database's
+	// certs and hashes rather than repeating them.
+	//
+	// Types that are assignable to Value:
+	//
+	//	*SignatureEntry_CertIndex
+	//	*SignatureEntry_HashIndex
+	//	*SignatureEntry_Data
+	Value isSignatureEntry_Value `protobuf_oneof:"value"`
+}
+
+func (x *SignatureEntry) Reset() {
+	*x = SignatureEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignatureEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureEntry) ProtoMessage() {}
+
+func (x *SignatureEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureEntry.ProtoReflect.Descriptor instead.
+func (*SignatureEntry) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SignatureEntry) GetType() SignatureType {
+	if x != nil {
+		return x.Type
+	}
+	return SignatureType_SIGNATURE_TYPE_UNSPECIFIED
+}
+
+func (x *SignatureEntry) GetTypeGuid() string {
+	if x != nil {
+		return x.TypeGuid
+	}
+	return ""
+}
+
+func (x *SignatureEntry) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (m *SignatureEntry) GetValue() isSignatureEntry_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *SignatureEntry) GetCertIndex() uint32 {
+	if x, ok := x.GetValue().(*SignatureEntry_CertIndex); ok {
+		return x.CertIndex
+	}
+	return 0
+}
+
+func (x *SignatureEntry) GetHashIndex() uint32 {
+	if x, ok := x.GetValue().(*SignatureEntry_HashIndex); ok {
+		return x.HashIndex
+	}
+	return 0
+}
+
+func (x *SignatureEntry) GetData() []byte {
+	if x, ok := x.GetValue().(*SignatureEntry_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+type isSignatureEntry_Value interface {
+	isSignatureEntry_Value()
+}
+
+type SignatureEntry_CertIndex struct {
+	// The index of the certificate in the database's certs.
+	CertIndex uint32 `protobuf:"varint,4,opt,name=cert_index,json=certIndex,proto3,oneof"`
+}
+
+type SignatureEntry_HashIndex struct {
+	// The index of the hash in the database's hashes.
+	HashIndex uint32 `protobuf:"varint,5,opt,name=hash_index,json=hashIndex,proto3,oneof"`
+}
+
+type SignatureEntry_Data struct {
+	// The signature data of other entries.
+	Data []byte `protobuf:"bytes,6,opt,name=data,proto3,oneof"`
+}
+
+func (*SignatureEntry_CertIndex) isSignatureEntry_Value() {}
+
+func (*SignatureEntry_HashIndex) isSignatureEntry_Value() {}
+
+func (*SignatureEntry_Data) isSignatureEntry_Value() {}
+
+// The Secure Boot state for this instance.
+type SecureBootState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether Secure Boot is enabled.
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// The Secure Boot signature (allowed) database.
+	Db *Database `protobuf:"bytes,2,opt,name=db,proto3" json:"db,omitempty"`
+	// The Secure Boot revoked signature (forbidden) database.
+	Dbx *Database `protobuf:"bytes,3,opt,name=dbx,proto3" json:"dbx,omitempty"`
+	// Authority events post-separator, without duplicate certificates.
+	// Pre-separator authorities are currently not supported.
+	Authority *Database `protobuf:"bytes,4,opt,name=authority,proto3" json:"authority,omitempty"`
+	// The Secure Boot Platform key, used to sign key exchange keys.
+	Pk *Database `protobuf:"bytes,5,opt,name=pk,proto3" json:"pk,omitempty"`
+	// The Secure Boot Key Exchange Keys, used to sign db and dbx updates.
+	Kek *Database `protobuf:"bytes,6,opt,name=kek,proto3" json:"kek,omitempty"`
+	// The uses of each distinct certificate in authority, in order of first use.
+	// Firmware, shim, and GRUB may each log the same authority.
+	AuthorityUsages []*AuthorityUsage `protobuf:"bytes,7,rep,name=authority_usages,json=authorityUsages,proto3" json:"authority_usages,omitempty"`
+	// Whether the firmware measured the "UEFI Debug Mode" EV_EFI_ACTION event
+	// into the Secure Boot register, i.e., a UEFI debugger was enabled. Only
+	// set when the caller opted in to debug mode boots, as extraction fails by
+	// default. The debugger can modify the firmware at runtime, so most policies
+	// should reject this state.
+	DebugModeEnabled bool `protobuf:"varint,8,opt,name=debug_mode_enabled,json=debugModeEnabled,proto3" json:"debug_mode_enabled,omitempty"`
+	// The malformed dbx entries that were skipped. dbx only contains the
+	// well-formed entries, so revocations in these entries are not reflected.
+	DbxErrors []*SignatureListError `protobuf:"bytes,9,rep,name=dbx_errors,json=dbxErrors,proto3" json:"dbx_errors,omitempty"`
+	// The Machine Owner Key (MOK) state of shim. Only set for TPM event logs
+	// with MOK or SBAT measurements.
+	Mok *MokState `protobuf:"bytes,10,opt,name=mok,proto3" json:"mok,omitempty"`
+}
+
+func (x *SecureBootState) Reset() {
+	*x = SecureBootState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecureBootState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecureBootState) ProtoMessage() {}
+
+func (x *SecureBootState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecureBootState.ProtoReflect.Descriptor instead.
+func (*SecureBootState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SecureBootState) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SecureBootState) GetDb() *Database {
+	if x != nil {
+		return x.Db
+	}
+	return nil
+}
+
+func (x *SecureBootState) GetDbx() *Database {
+	if x != nil {
+		return x.Dbx
+	}
+	return nil
+}
+
+func (x *SecureBootState) GetAuthority() *Database {
+	if x != nil {
+		return x.Authority
+	}
+	return nil
+}
+
+func (x *SecureBootState) GetPk() *Database {
+	if x != nil {
+		return x.Pk
+	}
+	return nil
+}
+
+func (x *SecureBootState) GetKek() *Database {
+	if x != nil {
+		return x.Kek
+	}
+	return nil
+}
+
+func (x *SecureBootState) GetAuthorityUsages() []*AuthorityUsage {
+	if x != nil {
+		return x.AuthorityUsages
+	}
+	return nil
+}
+
+func (x *SecureBootState) GetDebugModeEnabled() bool {
+	if x != nil {
+		return x.DebugModeEnabled
+	}
+	return false
+}
+
+func (x *SecureBootState) GetDbxErrors() []*SignatureListError {
+	if x != nil {
+		return x.DbxErrors
+	}
+	return nil
+}
+
+func (x *SecureBootState) GetMok() *MokState {
+	if x != nil {
+		return x.Mok
+	}
+	return nil
+}
+
+// The Machine Owner Key (MOK) state of a shim boot, from the events measured
+// into PCR 14 and the Secure Boot register.
+type MokState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The EV_IPL and EV_COMPACT_HASH events in PCR 14, in log order.
+	Measurements []*MokMeasurement `protobuf:"bytes,1,rep,name=measurements,proto3" json:"measurements,omitempty"`
+	// The digest of the MokList variable measured into PCR 14. MokList holds
+	// the EFI_SIGNATURE_LISTs of the enrolled MOKs, so this can be compared
+	// with the digest of the expected MOKs, e.g., encoded with EncodeDatabase.
+	MokListDigest []byte `protobuf:"bytes,2,opt,name=mok_list_digest,json=mokListDigest,proto3" json:"mok_list_digest,omitempty"`
+	// The digest of the MokListX variable, which holds the revoked MOKs.
+	MokListXDigest []byte `protobuf:"bytes,3,opt,name=mok_list_x_digest,json=mokListXDigest,proto3" json:"mok_list_x_digest,omitempty"`
+	// Whether MokListTrusted was measured as enabled, i.e., the Linux kernel
+	// trusts the MOKs for its machine keyring, and not only for booting.
+	MokListTrusted bool `protobuf:"varint,4,opt,name=mok_list_trusted,json=mokListTrusted,proto3" json:"mok_list_trusted,omitempty"`
+	// The SBAT level shim measured from the SbatLevel variable, e.g.,
+	// "sbat,1,2021030218\n". Images with an older SBAT generation are revoked.
+	SbatLevel string `protobuf:"bytes,5,opt,name=sbat_level,json=sbatLevel,proto3" json:"sbat_level,omitempty"`
+	// The indexes in SecureBootState.authority.certs of the certificates that
+	// authorized images from MokList or MokListRT, i.e., the MOKs used during
+	// boot.
+	AuthorityCertIndexes []uint32 `protobuf:"varint,6,rep,packed,name=authority_cert_indexes,json=authorityCertIndexes,proto3" json:"authority_cert_indexes,omitempty"`
+}
+
+func (x *MokState) Reset() {
+	*x = MokState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MokState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MokState) ProtoMessage() {}
+
+func (x *MokState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MokState.ProtoReflect.Descriptor instead.
+func (*MokState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *MokState) GetMeasurements() []*MokMeasurement {
+	if x != nil {
+		return x.Measurements
+	}
+	return nil
+}
+
+func (x *MokState) GetMokListDigest() []byte {
+	if x != nil {
+		return x.MokListDigest
+	}
+	return nil
+}
+
+func (x *MokState) GetMokListXDigest() []byte {
+	if x != nil {
+		return x.MokListXDigest
+	}
+	return nil
+}
+
+func (x *MokState) GetMokListTrusted() bool {
+	if x != nil {
+		return x.MokListTrusted
+	}
+	return false
+}
+
+func (x *MokState) GetSbatLevel() string {
+	if x != nil {
+		return x.SbatLevel
+	}
+	return ""
+}
+
+func (x *MokState) GetAuthorityCertIndexes() []uint32 {
+	if x != nil {
+		return x.AuthorityCertIndexes
+	}
+	return nil
+}
+
+// A measurement into PCR 14, e.g., of the MokList variable by shim.
+type MokMeasurement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of the event.
+	EventNum uint32 `protobuf:"varint,1,opt,name=event_num,json=eventNum,proto3" json:"event_num,omitempty"`
+	// The event type, EV_IPL or EV_COMPACT_HASH.
+	UntrustedType uint32 `protobuf:"varint,2,opt,name=untrusted_type,json=untrustedType,proto3" json:"untrusted_type,omitempty"`
+	// The descriptor string of the event, e.g., "MokList" or "MokListX".
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// The measured digest, e.g., of the MokList variable contents.
+	Digest []byte `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *MokMeasurement) Reset() {
+	*x = MokMeasurement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MokMeasurement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MokMeasurement) ProtoMessage() {}
+
+func (x *MokMeasurement) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MokMeasurement.ProtoReflect.Descriptor instead.
+func (*MokMeasurement) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MokMeasurement) GetEventNum() uint32 {
+	if x != nil {
+		return x.EventNum
+	}
+	return 0
+}
+
+func (x *MokMeasurement) GetUntrustedType() uint32 {
+	if x != nil {
+		return x.UntrustedType
+	}
+	return 0
+}
+
+func (x *MokMeasurement) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *MokMeasurement) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+// A malformed EFI_SIGNATURE_LIST or signature entry in a Secure Boot
+// variable.
+type SignatureListError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The byte offset of the entry in the variable data.
+	Offset uint32 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Why the entry could not be parsed.
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SignatureListError) Reset() {
+	*x = SignatureListError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignatureListError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureListError) ProtoMessage() {}
+
+func (x *SignatureListError) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureListError.ProtoReflect.Descriptor instead.
+func (*SignatureListError) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SignatureListError) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SignatureListError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// The uses of a distinct Secure Boot authority certificate.
+type AuthorityUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The index of the certificate in SecureBootState.authority.certs.
+	CertIndex uint32 `protobuf:"varint,1,opt,name=cert_index,json=certIndex,proto3" json:"cert_index,omitempty"`
+	// The number of times the certificate was used.
+	Count uint32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// Each use of the certificate, in log order.
+	Uses []*AuthorityUse `protobuf:"bytes,3,rep,name=uses,proto3" json:"uses,omitempty"`
+}
+
+func (x *AuthorityUsage) Reset() {
+	*x = AuthorityUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthorityUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorityUsage) ProtoMessage() {}
+
+func (x *AuthorityUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorityUsage.ProtoReflect.Descriptor instead.
+func (*AuthorityUsage) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AuthorityUsage) GetCertIndex() uint32 {
+	if x != nil {
+		return x.CertIndex
+	}
+	return 0
+}
+
+func (x *AuthorityUsage) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *AuthorityUsage) GetUses() []*AuthorityUse {
+	if x != nil {
+		return x.Uses
+	}
+	return nil
+}
+
+// A single use of a Secure Boot authority, as recorded by an
+// EV_EFI_VARIABLE_AUTHORITY event.
+type AuthorityUse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of the authority event.
+	EventNum uint32 `protobuf:"varint,1,opt,name=event_num,json=eventNum,proto3" json:"event_num,omitempty"`
+	// The variable the authority was found in, e.g., db or MokListRT.
+	VariableName string `protobuf:"bytes,2,opt,name=variable_name,json=variableName,proto3" json:"variable_name,omitempty"`
+	// The number of the image load event the authority authorized: the first
+	// EFI application or driver load after the authority event, or for
+	// authorities measured by shim (Shim, MokList, and MokListRT), the last EFI
+	// application load before it. 0 if there is none.
+	ImageEventNum uint32 `protobuf:"varint,3,opt,name=image_event_num,json=imageEventNum,proto3" json:"image_event_num,omitempty"`
+	// The digest of the authorized image load event.
+	ImageDigest []byte `protobuf:"bytes,4,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+}
+
+func (x *AuthorityUse) Reset() {
+	*x = AuthorityUse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthorityUse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorityUse) ProtoMessage() {}
+
+func (x *AuthorityUse) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorityUse.ProtoReflect.Descriptor instead.
+func (*AuthorityUse) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AuthorityUse) GetEventNum() uint32 {
+	if x != nil {
+		return x.EventNum
+	}
+	return 0
+}
+
+func (x *AuthorityUse) GetVariableName() string {
+	if x != nil {
+		return x.VariableName
+	}
+	return ""
+}
+
+func (x *AuthorityUse) GetImageEventNum() uint32 {
+	if x != nil {
+		return x.ImageEventNum
+	}
+	return 0
+}
+
+func (x *AuthorityUse) GetImageDigest() []byte {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return nil
+}
+
+type EfiApp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The PE/COFF digest of the EFI application (pulled from the raw event digest).
+	// This can also represent digest of the EFI boot/runtime service drivers.
+	Digest []byte `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
+	// The fields below are decoded from the image load event data, which is
+	// not measured, so they are untrusted. They are only set for
+	// EfiState.apps; see EfiDriver for the drivers.
+	//
+	// The UEFI text form of the device path the application was loaded from,
+	// e.g., "PciRoot(0x0)/Pci(0x3,0x0)/Scsi(0x0,0x1)/HD(15,GPT,...)/\EFI\BOOT\BOOTX64.EFI".
+	UntrustedDevicePath string `protobuf:"bytes,2,opt,name=untrusted_device_path,json=untrustedDevicePath,proto3" json:"untrusted_device_path,omitempty"`
+	// The file path of the device path, e.g., "\EFI\BOOT\BOOTX64.EFI".
+	UntrustedFilePath string `protobuf:"bytes,3,opt,name=untrusted_file_path,json=untrustedFilePath,proto3" json:"untrusted_file_path,omitempty"`
+	// The unique partition GUID of the GPT partition in the device path, which
+	// can be matched against GptPartition.partition_guid.
+	UntrustedPartitionGuid string `protobuf:"bytes,4,opt,name=untrusted_partition_guid,json=untrustedPartitionGuid,proto3" json:"untrusted_partition_guid,omitempty"`
+	// The address of the image in memory.
+	UntrustedImageLocation uint64 `protobuf:"varint,5,opt,name=untrusted_image_location,json=untrustedImageLocation,proto3" json:"untrusted_image_location,omitempty"`
+	// The length of the image in memory.
+	UntrustedImageLength uint64 `protobuf:"varint,6,opt,name=untrusted_image_length,json=untrustedImageLength,proto3" json:"untrusted_image_length,omitempty"`
+}
+
+func (x *EfiApp) Reset() {
+	*x = EfiApp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EfiApp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EfiApp) ProtoMessage() {}
+
+func (x *EfiApp) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EfiApp.ProtoReflect.Descriptor instead.
+func (*EfiApp) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *EfiApp) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *EfiApp) GetUntrustedDevicePath() string {
+	if x != nil {
+		return x.UntrustedDevicePath
+	}
+	return ""
+}
+
+func (x *EfiApp) GetUntrustedFilePath() string {
+	if x != nil {
+		return x.UntrustedFilePath
+	}
+	return ""
+}
+
+func (x *EfiApp) GetUntrustedPartitionGuid() string {
+	if x != nil {
+		return x.UntrustedPartitionGuid
+	}
+	return ""
+}
+
+func (x *EfiApp) GetUntrustedImageLocation() uint64 {
+	if x != nil {
+		return x.UntrustedImageLocation
+	}
+	return 0
+}
+
+func (x *EfiApp) GetUntrustedImageLength() uint64 {
+	if x != nil {
+		return x.UntrustedImageLength
+	}
+	return 0
+}
+
+// The verified state of EFI Drivers and Applications. Policy usage on this machine state
 // should check the entire set of EFI App digests matches, not a subset.
 type EfiState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// UEFI's OS Loader code is required to measure attempts to load and execute
-	// UEFI applications.
-	// UEFI applications are typically bootloaders such as shim and GRUB.
-	// These run and are measured using the UEFI LoadImage() service.
-	Apps []*EfiApp `protobuf:"bytes,1,rep,name=apps,proto3" json:"apps,omitempty"`
-	// The EFI drivers,
-	// obtained from https://trustedcomputinggroup.org/wp-content/uploads/TCG_EFI_Platform_1_22_Final_-v15.pdf#page=22.
-	// The EFI Boot Services Drivers from adapter or loaded bydriver in adapter.
-	BootServicesDrivers []*EfiApp `protobuf:"bytes,2,rep,name=boot_services_drivers,json=bootServicesDrivers,proto3" json:"boot_services_drivers,omitempty"`
-	// The EFI Runtime Drivers from adapter or loaded bydriver in adapter.
-	RuntimeServicesDrivers []*EfiApp `protobuf:"bytes,3,rep,name=runtime_services_drivers,json=runtimeServicesDrivers,proto3" json:"runtime_services_drivers,omitempty"`
-}
-
-func (x *EfiState) Reset() {
-	*x = EfiState{}
+	// UEFI's OS Loader code is required to measure attempts to load and execute
+	// UEFI applications.
+	// UEFI applications are typically bootloaders such as shim and GRUB.
+	// These run and are measured using the UEFI LoadImage() service.
+	Apps []*EfiApp `protobuf:"bytes,1,rep,name=apps,proto3" json:"apps,omitempty"`
+	// The EFI drivers,
+	// obtained from https://trustedcomputinggroup.org/wp-content/uploads/TCG_EFI_Platform_1_22_Final_-v15.pdf#page=22.
+	// The EFI Boot Services Drivers from adapter or loaded bydriver in adapter.
+	BootServicesDrivers []*EfiApp `protobuf:"bytes,2,rep,name=boot_services_drivers,json=bootServicesDrivers,proto3" json:"boot_services_drivers,omitempty"`
+	// The EFI Runtime Drivers from adapter or loaded bydriver in adapter.
+	RuntimeServicesDrivers []*EfiApp `protobuf:"bytes,3,rep,name=runtime_services_drivers,json=runtimeServicesDrivers,proto3" json:"runtime_services_drivers,omitempty"`
+	// Events in the EFI application and ExitBootServices registers measured
+	// after the ExitBootServices invocation. Only populated when requested.
+	// These events are recorded after firmware relinquished control of the
+	// platform, so they are UNTRUSTED and are not used for any other field.
+	UntrustedPostEbsEvents []*Event `protobuf:"bytes,4,rep,name=untrusted_post_ebs_events,json=untrustedPostEbsEvents,proto3" json:"untrusted_post_ebs_events,omitempty"`
+	// The result of ExitBootServices(), from the last "Exit Boot Services
+	// Returned" event after the invocation. These events are measured after
+	// firmware relinquished control of the platform, so the result is
+	// UNTRUSTED.
+	UntrustedExitBootServicesResult ExitBootServicesResult `protobuf:"varint,5,opt,name=untrusted_exit_boot_services_result,json=untrustedExitBootServicesResult,proto3,enum=state.ExitBootServicesResult" json:"untrusted_exit_boot_services_result,omitempty"`
+	// All boot and runtime services drivers measured into the firmware driver
+	// register, in log order. Unlike boot_services_drivers and
+	// runtime_services_drivers, this includes drivers loaded after the
+	// separator, e.g., option ROMs dispatched late by the boot manager.
+	Drivers []*EfiDriver `protobuf:"bytes,6,rep,name=drivers,proto3" json:"drivers,omitempty"`
+}
+
+func (x *EfiState) Reset() {
+	*x = EfiState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EfiState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EfiState) ProtoMessage() {}
+
+func (x *EfiState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EfiState.ProtoReflect.Descriptor instead.
+func (*EfiState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *EfiState) GetApps() []*EfiApp {
+	if x != nil {
+		return x.Apps
+	}
+	return nil
+}
+
+func (x *EfiState) GetBootServicesDrivers() []*EfiApp {
+	if x != nil {
+		return x.BootServicesDrivers
+	}
+	return nil
+}
+
+func (x *EfiState) GetRuntimeServicesDrivers() []*EfiApp {
+	if x != nil {
+		return x.RuntimeServicesDrivers
+	}
+	return nil
+}
+
+func (x *EfiState) GetUntrustedPostEbsEvents() []*Event {
+	if x != nil {
+		return x.UntrustedPostEbsEvents
+	}
+	return nil
+}
+
+func (x *EfiState) GetUntrustedExitBootServicesResult() ExitBootServicesResult {
+	if x != nil {
+		return x.UntrustedExitBootServicesResult
+	}
+	return ExitBootServicesResult_EXIT_BOOT_SERVICES_RESULT_UNSPECIFIED
+}
+
+func (x *EfiState) GetDrivers() []*EfiDriver {
+	if x != nil {
+		return x.Drivers
+	}
+	return nil
+}
+
+// An EFI driver load measured by firmware.
+type EfiDriver struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The PE/COFF digest of the driver (pulled from the raw event digest).
+	Digest []byte `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
+	// The number of the driver load event.
+	EventNum uint32 `protobuf:"varint,2,opt,name=event_num,json=eventNum,proto3" json:"event_num,omitempty"`
+	// Whether the driver is a runtime services driver rather than a boot
+	// services driver.
+	Runtime bool `protobuf:"varint,3,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	// Whether the driver was loaded after the separator in the firmware driver
+	// register.
+	PostSeparator bool `protobuf:"varint,4,opt,name=post_separator,json=postSeparator,proto3" json:"post_separator,omitempty"`
+	// The UEFI text form of the device path the driver was loaded from, e.g.,
+	// "PciRoot(0x0)/Pci(0x1,0x0)/Pci(0x0,0x0)/Offset(0xf250,0x201ff)". The
+	// device path is not measured, so it is untrusted.
+	UntrustedDevicePath string `protobuf:"bytes,5,opt,name=untrusted_device_path,json=untrustedDevicePath,proto3" json:"untrusted_device_path,omitempty"`
+	// Whether the device path identifies a PCI option ROM, i.e., a PCI device
+	// followed by a relative offset range. This is derived from the untrusted
+	// device path.
+	UntrustedOptionRom bool `protobuf:"varint,6,opt,name=untrusted_option_rom,json=untrustedOptionRom,proto3" json:"untrusted_option_rom,omitempty"`
+	// Why the device path could not be parsed, if it could not. The device
+	// path and option ROM fields are then unset.
+	UntrustedDevicePathError string `protobuf:"bytes,7,opt,name=untrusted_device_path_error,json=untrustedDevicePathError,proto3" json:"untrusted_device_path_error,omitempty"`
+	// Why the EFI_IMAGE_LOAD_EVENT data of a driver loaded after the separator
+	// could not be parsed, if it could not. The device path fields are then
+	// unset. Drivers loaded before the separator must have valid event data.
+	UntrustedImageLoadError string `protobuf:"bytes,8,opt,name=untrusted_image_load_error,json=untrustedImageLoadError,proto3" json:"untrusted_image_load_error,omitempty"`
+}
+
+func (x *EfiDriver) Reset() {
+	*x = EfiDriver{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EfiDriver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EfiDriver) ProtoMessage() {}
+
+func (x *EfiDriver) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EfiDriver.ProtoReflect.Descriptor instead.
+func (*EfiDriver) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *EfiDriver) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *EfiDriver) GetEventNum() uint32 {
+	if x != nil {
+		return x.EventNum
+	}
+	return 0
+}
+
+func (x *EfiDriver) GetRuntime() bool {
+	if x != nil {
+		return x.Runtime
+	}
+	return false
+}
+
+func (x *EfiDriver) GetPostSeparator() bool {
+	if x != nil {
+		return x.PostSeparator
+	}
+	return false
+}
+
+func (x *EfiDriver) GetUntrustedDevicePath() string {
+	if x != nil {
+		return x.UntrustedDevicePath
+	}
+	return ""
+}
+
+func (x *EfiDriver) GetUntrustedOptionRom() bool {
+	if x != nil {
+		return x.UntrustedOptionRom
+	}
+	return false
+}
+
+func (x *EfiDriver) GetUntrustedDevicePathError() string {
+	if x != nil {
+		return x.UntrustedDevicePathError
+	}
+	return ""
+}
+
+func (x *EfiDriver) GetUntrustedImageLoadError() string {
+	if x != nil {
+		return x.UntrustedImageLoadError
+	}
+	return ""
+}
+
+// The number of events measured into a register with a given type.
+type EventCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PcrIndex      uint32 `protobuf:"varint,1,opt,name=pcr_index,json=pcrIndex,proto3" json:"pcr_index,omitempty"`
+	UntrustedType uint32 `protobuf:"varint,2,opt,name=untrusted_type,json=untrustedType,proto3" json:"untrusted_type,omitempty"`
+	Count         uint32 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *EventCount) Reset() {
+	*x = EventCount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventCount) ProtoMessage() {}
+
+func (x *EventCount) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventCount.ProtoReflect.Descriptor instead.
+func (*EventCount) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *EventCount) GetPcrIndex() uint32 {
+	if x != nil {
+		return x.PcrIndex
+	}
+	return 0
+}
+
+func (x *EventCount) GetUntrustedType() uint32 {
+	if x != nil {
+		return x.UntrustedType
+	}
+	return 0
+}
+
+func (x *EventCount) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Summary statistics about an event log, for monitoring and anomaly
+// detection across a fleet.
+type EventLogStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of events.
+	TotalEvents uint32 `protobuf:"varint,1,opt,name=total_events,json=totalEvents,proto3" json:"total_events,omitempty"`
+	// The total size in bytes of the event data.
+	TotalDataSize uint64 `protobuf:"varint,2,opt,name=total_data_size,json=totalDataSize,proto3" json:"total_data_size,omitempty"`
+	// The Shannon entropy of the event data, in bits per byte.
+	DataEntropy float64 `protobuf:"fixed64,3,opt,name=data_entropy,json=dataEntropy,proto3" json:"data_entropy,omitempty"`
+	// Event counts per register and type, sorted by register, then type.
+	Counts []*EventCount `protobuf:"bytes,4,rep,name=counts,proto3" json:"counts,omitempty"`
+	// The number of events whose type is not a known TCG event type.
+	UnknownTypeEvents uint32 `protobuf:"varint,5,opt,name=unknown_type_events,json=unknownTypeEvents,proto3" json:"unknown_type_events,omitempty"`
+}
+
+func (x *EventLogStats) Reset() {
+	*x = EventLogStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventLogStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventLogStats) ProtoMessage() {}
+
+func (x *EventLogStats) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventLogStats.ProtoReflect.Descriptor instead.
+func (*EventLogStats) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *EventLogStats) GetTotalEvents() uint32 {
+	if x != nil {
+		return x.TotalEvents
+	}
+	return 0
+}
+
+func (x *EventLogStats) GetTotalDataSize() uint64 {
+	if x != nil {
+		return x.TotalDataSize
+	}
+	return 0
+}
+
+func (x *EventLogStats) GetDataEntropy() float64 {
+	if x != nil {
+		return x.DataEntropy
+	}
+	return 0
+}
+
+func (x *EventLogStats) GetCounts() []*EventCount {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *EventLogStats) GetUnknownTypeEvents() uint32 {
+	if x != nil {
+		return x.UnknownTypeEvents
+	}
+	return 0
+}
+
+// A requirement of the TCG PC Client Platform Firmware Profile on the events
+// measured into a PCR.
+type ConformanceCheck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The PCR index.
+	PcrIndex uint32 `protobuf:"varint,1,opt,name=pcr_index,json=pcrIndex,proto3" json:"pcr_index,omitempty"`
+	// A stable identifier of the requirement, e.g., "separator" or
+	// "scrtm_version".
+	Requirement string `protobuf:"bytes,2,opt,name=requirement,proto3" json:"requirement,omitempty"`
+	// A readable description of the requirement.
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Whether the event log has an event satisfying the requirement.
+	Present bool `protobuf:"varint,4,opt,name=present,proto3" json:"present,omitempty"`
+	// The numbers of the events satisfying the requirement.
+	EventNums []uint32 `protobuf:"varint,5,rep,packed,name=event_nums,json=eventNums,proto3" json:"event_nums,omitempty"`
+}
+
+func (x *ConformanceCheck) Reset() {
+	*x = ConformanceCheck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConformanceCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConformanceCheck) ProtoMessage() {}
+
+func (x *ConformanceCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConformanceCheck.ProtoReflect.Descriptor instead.
+func (*ConformanceCheck) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ConformanceCheck) GetPcrIndex() uint32 {
+	if x != nil {
+		return x.PcrIndex
+	}
+	return 0
+}
+
+func (x *ConformanceCheck) GetRequirement() string {
+	if x != nil {
+		return x.Requirement
+	}
+	return ""
+}
+
+func (x *ConformanceCheck) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ConformanceCheck) GetPresent() bool {
+	if x != nil {
+		return x.Present
+	}
+	return false
+}
+
+func (x *ConformanceCheck) GetEventNums() []uint32 {
+	if x != nil {
+		return x.EventNums
+	}
+	return nil
+}
+
+// The spec-required events present and missing in an event log.
+type ConformanceReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The checks, sorted by PCR index, in a fixed order per PCR.
+	Checks []*ConformanceCheck `protobuf:"bytes,1,rep,name=checks,proto3" json:"checks,omitempty"`
+	// Whether all required events are present.
+	Conformant bool `protobuf:"varint,2,opt,name=conformant,proto3" json:"conformant,omitempty"`
+}
+
+func (x *ConformanceReport) Reset() {
+	*x = ConformanceReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConformanceReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConformanceReport) ProtoMessage() {}
+
+func (x *ConformanceReport) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConformanceReport.ProtoReflect.Descriptor instead.
+func (*ConformanceReport) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ConformanceReport) GetChecks() []*ConformanceCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *ConformanceReport) GetConformant() bool {
+	if x != nil {
+		return x.Conformant
+	}
+	return false
+}
+
+// A property of the verification that policy may want to act on. Findings do
+// not cause extraction to fail.
+type Finding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type FindingType `protobuf:"varint,1,opt,name=type,proto3,enum=state.FindingType" json:"type,omitempty"`
+	// A human-readable description of the finding.
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+}
+
+func (x *Finding) Reset() {
+	*x = Finding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Finding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Finding) ProtoMessage() {}
+
+func (x *Finding) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Finding.ProtoReflect.Descriptor instead.
+func (*Finding) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Finding) GetType() FindingType {
+	if x != nil {
+		return x.Type
+	}
+	return FindingType_FINDING_TYPE_UNSPECIFIED
+}
+
+func (x *Finding) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// The verified state of a booted machine, obtained from a UEFI event log.
+// The state is extracted from either EFI_TCG2_PROTOCOL or
+// EFI_CC_MEASUREMENT_PROTOCOL. Both of these follow the TCG-defined format
+// in https://trustedcomputinggroup.org/resource/tcg-efi-protocol-specification/
+// The TCG2-related (TPM) logs are structured using TCG_PCR_EVENT (SHA1 format)
+// or TCG_PCR_EVENT2 (Crypto Agile format).
+// The CC logs are structured using CC_EVENT.
+type FirmwareLogState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Platform   *PlatformState   `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	SecureBoot *SecureBootState `protobuf:"bytes,2,opt,name=secure_boot,json=secureBoot,proto3" json:"secure_boot,omitempty"`
+	// The complete parsed Firmware Event Log, including those events used to
+	// create this MachineState.
+	RawEvents []*Event `protobuf:"bytes,3,rep,name=raw_events,json=rawEvents,proto3" json:"raw_events,omitempty"`
+	// The hash algorithm used to calculate event digests to verify a log entry.
+	Hash        HashAlgo          `protobuf:"varint,4,opt,name=hash,proto3,enum=state.HashAlgo" json:"hash,omitempty"`
+	Grub        *GrubState        `protobuf:"bytes,5,opt,name=grub,proto3" json:"grub,omitempty"`
+	LinuxKernel *LinuxKernelState `protobuf:"bytes,6,opt,name=linux_kernel,json=linuxKernel,proto3" json:"linux_kernel,omitempty"`
+	Efi         *EfiState         `protobuf:"bytes,8,opt,name=efi,proto3" json:"efi,omitempty"`
+	LogType     LogType           `protobuf:"varint,9,opt,name=log_type,json=logType,proto3,enum=state.LogType" json:"log_type,omitempty"`
+	// Kernels loaded via kexec after the first kernel, in boot order.
+	// The grub and linux_kernel fields only describe the first generation.
+	Kexec []*KexecState `protobuf:"bytes,10,rep,name=kexec,proto3" json:"kexec,omitempty"`
+	// Findings about how this state was verified, e.g., against a weak bank.
+	Findings []*Finding `protobuf:"bytes,11,rep,name=findings,proto3" json:"findings,omitempty"`
+	// Summary statistics about the events in raw_events.
+	Stats *EventLogStats `protobuf:"bytes,12,opt,name=stats,proto3" json:"stats,omitempty"`
+	// The dynamic launch state. Only set if the event log contains DRTM events.
+	Drtm *DrtmState `protobuf:"bytes,13,opt,name=drtm,proto3" json:"drtm,omitempty"`
+	// How the register values the event log was replayed against were
+	// verified. Only set when verifying an AttestationBundle.
+	Provenance *Provenance `protobuf:"bytes,14,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	// The results of the digest resolvers given at extraction time, in event
+	// order.
+	DigestResolutions []*DigestResolution `protobuf:"bytes,15,rep,name=digest_resolutions,json=digestResolutions,proto3" json:"digest_resolutions,omitempty"`
+	// The extraction schema version this state was extracted with. States
+	// extracted from the same event log with the same schema version are equal,
+	// regardless of the library version.
+	SchemaVersion uint32 `protobuf:"varint,16,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	// The quirk profile selected at extraction time, e.g., "ovmf-edk2-2022".
+	QuirkProfile string `protobuf:"bytes,17,opt,name=quirk_profile,json=quirkProfile,proto3" json:"quirk_profile,omitempty"`
+	// The names of the quirks that were needed to parse the event log or
+	// extract this state, e.g., "padding". Policy may want to treat states that
+	// needed quirks with suspicion.
+	AppliedQuirks []string `protobuf:"bytes,18,rep,name=applied_quirks,json=appliedQuirks,proto3" json:"applied_quirks,omitempty"`
+	// The Windows boot state. Only set for Windows Boot Manager TPM event logs.
+	WindowsSipa *WindowsSipaState `protobuf:"bytes,19,opt,name=windows_sipa,json=windowsSipa,proto3" json:"windows_sipa,omitempty"`
+	// The unified kernel image state. Only set if the TPM event log contains
+	// systemd-stub or systemd-pcrphase events in PCR 11.
+	Uki *UkiState `protobuf:"bytes,20,opt,name=uki,proto3" json:"uki,omitempty"`
+	// The boot configuration measured into PCR 1, e.g., the boot options.
+	BootConfig *BootConfigState `protobuf:"bytes,21,opt,name=boot_config,json=bootConfig,proto3" json:"boot_config,omitempty"`
+	// The partition table of the boot disk, from the EV_EFI_GPT_EVENT in PCR 5
+	// (RTMR1 on Intel TDX).
+	Gpt *GptState `protobuf:"bytes,22,opt,name=gpt,proto3" json:"gpt,omitempty"`
+	// The measurement registers whose events were replaced by redacted register
+	// events, as encoded in the event log, in increasing order. No state was extracted from them, so
+	// sections measured into them are missing.
+	RedactedMrs []uint32 `protobuf:"varint,23,rep,packed,name=redacted_mrs,json=redactedMrs,proto3" json:"redacted_mrs,omitempty"`
+}
+
+func (x *FirmwareLogState) Reset() {
+	*x = FirmwareLogState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FirmwareLogState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FirmwareLogState) ProtoMessage() {}
+
+func (x *FirmwareLogState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FirmwareLogState.ProtoReflect.Descriptor instead.
+func (*FirmwareLogState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *FirmwareLogState) GetPlatform() *PlatformState {
+	if x != nil {
+		return x.Platform
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetSecureBoot() *SecureBootState {
+	if x != nil {
+		return x.SecureBoot
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetRawEvents() []*Event {
+	if x != nil {
+		return x.RawEvents
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetHash() HashAlgo {
+	if x != nil {
+		return x.Hash
+	}
+	return HashAlgo_HASH_INVALID
+}
+
+func (x *FirmwareLogState) GetGrub() *GrubState {
+	if x != nil {
+		return x.Grub
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetLinuxKernel() *LinuxKernelState {
+	if x != nil {
+		return x.LinuxKernel
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetEfi() *EfiState {
+	if x != nil {
+		return x.Efi
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetLogType() LogType {
+	if x != nil {
+		return x.LogType
+	}
+	return LogType_LOG_TYPE_UNDEFINED
+}
+
+func (x *FirmwareLogState) GetKexec() []*KexecState {
+	if x != nil {
+		return x.Kexec
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetFindings() []*Finding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetStats() *EventLogStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetDrtm() *DrtmState {
+	if x != nil {
+		return x.Drtm
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetDigestResolutions() []*DigestResolution {
+	if x != nil {
+		return x.DigestResolutions
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *FirmwareLogState) GetQuirkProfile() string {
+	if x != nil {
+		return x.QuirkProfile
+	}
+	return ""
+}
+
+func (x *FirmwareLogState) GetAppliedQuirks() []string {
+	if x != nil {
+		return x.AppliedQuirks
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetWindowsSipa() *WindowsSipaState {
+	if x != nil {
+		return x.WindowsSipa
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetUki() *UkiState {
+	if x != nil {
+		return x.Uki
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetBootConfig() *BootConfigState {
+	if x != nil {
+		return x.BootConfig
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetGpt() *GptState {
+	if x != nil {
+		return x.Gpt
+	}
+	return nil
+}
+
+func (x *FirmwareLogState) GetRedactedMrs() []uint32 {
+	if x != nil {
+		return x.RedactedMrs
+	}
+	return nil
+}
+
+// The GUID partition table of the boot disk, as measured by the boot manager.
+type GptState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The disk GUID.
+	DiskGuid string `protobuf:"bytes,1,opt,name=disk_guid,json=diskGuid,proto3" json:"disk_guid,omitempty"`
+	// The partitions in use, in partition table order.
+	Partitions []*GptPartition `protobuf:"bytes,2,rep,name=partitions,proto3" json:"partitions,omitempty"`
+}
+
+func (x *GptState) Reset() {
+	*x = GptState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GptState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GptState) ProtoMessage() {}
+
+func (x *GptState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GptState.ProtoReflect.Descriptor instead.
+func (*GptState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GptState) GetDiskGuid() string {
+	if x != nil {
+		return x.DiskGuid
+	}
+	return ""
+}
+
+func (x *GptState) GetPartitions() []*GptPartition {
+	if x != nil {
+		return x.Partitions
+	}
+	return nil
+}
+
+// A partition of a GUID partition table.
+type GptPartition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The partition type GUID, e.g., "c12a7328-f81f-11d2-ba4b-00a0c93ec93b" for
+	// the EFI system partition.
+	TypeGuid string `protobuf:"bytes,1,opt,name=type_guid,json=typeGuid,proto3" json:"type_guid,omitempty"`
+	// The unique partition GUID.
+	PartitionGuid string `protobuf:"bytes,2,opt,name=partition_guid,json=partitionGuid,proto3" json:"partition_guid,omitempty"`
+	// The first logical block of the partition.
+	StartingLba uint64 `protobuf:"varint,3,opt,name=starting_lba,json=startingLba,proto3" json:"starting_lba,omitempty"`
+	// The last logical block of the partition, inclusive.
+	EndingLba uint64 `protobuf:"varint,4,opt,name=ending_lba,json=endingLba,proto3" json:"ending_lba,omitempty"`
+	// The partition attribute bits.
+	Attributes uint64 `protobuf:"varint,5,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	// The user-readable partition name, e.g., "EFI System Partition".
+	Name string `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GptPartition) Reset() {
+	*x = GptPartition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GptPartition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GptPartition) ProtoMessage() {}
+
+func (x *GptPartition) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GptPartition.ProtoReflect.Descriptor instead.
+func (*GptPartition) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GptPartition) GetTypeGuid() string {
+	if x != nil {
+		return x.TypeGuid
+	}
+	return ""
+}
+
+func (x *GptPartition) GetPartitionGuid() string {
+	if x != nil {
+		return x.PartitionGuid
+	}
+	return ""
+}
+
+func (x *GptPartition) GetStartingLba() uint64 {
+	if x != nil {
+		return x.StartingLba
+	}
+	return 0
+}
+
+func (x *GptPartition) GetEndingLba() uint64 {
+	if x != nil {
+		return x.EndingLba
+	}
+	return 0
+}
+
+func (x *GptPartition) GetAttributes() uint64 {
+	if x != nil {
+		return x.Attributes
+	}
+	return 0
+}
+
+func (x *GptPartition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// The boot configuration of a machine, from the boot variable and platform
+// configuration events measured into PCR 1 (RTMR0 on Intel TDX).
+type BootConfigState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The boot option numbers of the BootOrder variable, in boot order.
+	BootOrder []uint32 `protobuf:"varint,1,rep,packed,name=boot_order,json=bootOrder,proto3" json:"boot_order,omitempty"`
+	// The measured Boot#### variables, in log order.
+	BootOptions []*BootOption `protobuf:"bytes,2,rep,name=boot_options,json=bootOptions,proto3" json:"boot_options,omitempty"`
+	// The EV_PLATFORM_CONFIG_FLAGS and EV_EFI_HANDOFF_TABLES events, e.g., of
+	// the ACPI and SMBIOS tables, in log order.
+	PlatformConfigs []*ConfigMeasurement `protobuf:"bytes,3,rep,name=platform_configs,json=platformConfigs,proto3" json:"platform_configs,omitempty"`
+	// The names of the boot variables whose digest does not match their data.
+	// Their contents are untrusted, so they are not extracted.
+	UnverifiedVariables []string `protobuf:"bytes,4,rep,name=unverified_variables,json=unverifiedVariables,proto3" json:"unverified_variables,omitempty"`
+}
+
+func (x *BootConfigState) Reset() {
+	*x = BootConfigState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BootConfigState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BootConfigState) ProtoMessage() {}
+
+func (x *BootConfigState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BootConfigState.ProtoReflect.Descriptor instead.
+func (*BootConfigState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *BootConfigState) GetBootOrder() []uint32 {
+	if x != nil {
+		return x.BootOrder
+	}
+	return nil
+}
+
+func (x *BootConfigState) GetBootOptions() []*BootOption {
+	if x != nil {
+		return x.BootOptions
+	}
+	return nil
+}
+
+func (x *BootConfigState) GetPlatformConfigs() []*ConfigMeasurement {
+	if x != nil {
+		return x.PlatformConfigs
+	}
+	return nil
+}
+
+func (x *BootConfigState) GetUnverifiedVariables() []string {
+	if x != nil {
+		return x.UnverifiedVariables
+	}
+	return nil
+}
+
+// An EFI_LOAD_OPTION of a Boot#### variable.
+type BootOption struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The option number, e.g., 1 for Boot0001.
+	Number uint32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	// The LOAD_OPTION_* attributes.
+	Attributes uint32 `protobuf:"varint,2,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	// Whether the LOAD_OPTION_ACTIVE attribute is set. Boot managers skip
+	// inactive options.
+	Active bool `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	// The user-readable description, e.g., "UEFI Misc Device".
+	Description string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// The UEFI text form of the device path of the option, e.g.,
+	// "PciRoot(0x0)/Pci(0x3,0x0)/HD(1,0x800,0x32000)/\EFI\BOOT\BOOTX64.EFI".
+	DevicePath string `protobuf:"bytes,5,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+	// The data passed to the loaded image, e.g., its load options.
+	OptionalData []byte `protobuf:"bytes,6,opt,name=optional_data,json=optionalData,proto3" json:"optional_data,omitempty"`
+}
+
+func (x *BootOption) Reset() {
+	*x = BootOption{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BootOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BootOption) ProtoMessage() {}
+
+func (x *BootOption) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BootOption.ProtoReflect.Descriptor instead.
+func (*BootOption) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *BootOption) GetNumber() uint32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *BootOption) GetAttributes() uint32 {
+	if x != nil {
+		return x.Attributes
+	}
+	return 0
+}
+
+func (x *BootOption) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *BootOption) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *BootOption) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+func (x *BootOption) GetOptionalData() []byte {
+	if x != nil {
+		return x.OptionalData
+	}
+	return nil
+}
+
+// A platform configuration measurement, e.g., of the ACPI or SMBIOS tables.
+type ConfigMeasurement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of the event.
+	EventNum uint32 `protobuf:"varint,1,opt,name=event_num,json=eventNum,proto3" json:"event_num,omitempty"`
+	// The event type, e.g., EV_PLATFORM_CONFIG_FLAGS.
+	UntrustedType uint32 `protobuf:"varint,2,opt,name=untrusted_type,json=untrustedType,proto3" json:"untrusted_type,omitempty"`
+	// The description of the measured configuration, e.g., "ACPI DATA" or
+	// "SMBIOS". The event data is not measured, so this is untrusted.
+	UntrustedDescription string `protobuf:"bytes,3,opt,name=untrusted_description,json=untrustedDescription,proto3" json:"untrusted_description,omitempty"`
+	// The measured digest of the configuration.
+	Digest []byte `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *ConfigMeasurement) Reset() {
+	*x = ConfigMeasurement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigMeasurement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigMeasurement) ProtoMessage() {}
+
+func (x *ConfigMeasurement) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigMeasurement.ProtoReflect.Descriptor instead.
+func (*ConfigMeasurement) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ConfigMeasurement) GetEventNum() uint32 {
+	if x != nil {
+		return x.EventNum
+	}
+	return 0
+}
+
+func (x *ConfigMeasurement) GetUntrustedType() uint32 {
+	if x != nil {
+		return x.UntrustedType
+	}
+	return 0
+}
+
+func (x *ConfigMeasurement) GetUntrustedDescription() string {
+	if x != nil {
+		return x.UntrustedDescription
+	}
+	return ""
+}
+
+func (x *ConfigMeasurement) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+// A compact summary of a FirmwareLogState for metrics and logging pipelines.
+// It only holds counts, booleans, enums, and digests of measured images, so
+// its fields have low cardinality, and it omits event log content that may
+// identify a user or machine, e.g., kernel command lines, file paths, and
+// certificates. See extract.Summarize.
+type FirmwareLogSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SchemaVersion              uint32                    `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	LogType                    LogType                   `protobuf:"varint,2,opt,name=log_type,json=logType,proto3,enum=state.LogType" json:"log_type,omitempty"`
+	Hash                       HashAlgo                  `protobuf:"varint,3,opt,name=hash,proto3,enum=state.HashAlgo" json:"hash,omitempty"`
+	Technology                 GCEConfidentialTechnology `protobuf:"varint,4,opt,name=technology,proto3,enum=state.GCEConfidentialTechnology" json:"technology,omitempty"`
+	SecureBootEnabled          bool                      `protobuf:"varint,5,opt,name=secure_boot_enabled,json=secureBootEnabled,proto3" json:"secure_boot_enabled,omitempty"`
+	SecureBootDebugModeEnabled bool                      `protobuf:"varint,6,opt,name=secure_boot_debug_mode_enabled,json=secureBootDebugModeEnabled,proto3" json:"secure_boot_debug_mode_enabled,omitempty"`
+	// The number of certificates and hashes in the Secure Boot databases.
+	DbCerts   uint32 `protobuf:"varint,7,opt,name=db_certs,json=dbCerts,proto3" json:"db_certs,omitempty"`
+	DbHashes  uint32 `protobuf:"varint,8,opt,name=db_hashes,json=dbHashes,proto3" json:"db_hashes,omitempty"`
+	DbxCerts  uint32 `protobuf:"varint,9,opt,name=dbx_certs,json=dbxCerts,proto3" json:"dbx_certs,omitempty"`
+	DbxHashes uint32 `protobuf:"varint,10,opt,name=dbx_hashes,json=dbxHashes,proto3" json:"dbx_hashes,omitempty"`
+	// The number of distinct Secure Boot authority certificates.
+	AuthorityCerts uint32 `protobuf:"varint,11,opt,name=authority_certs,json=authorityCerts,proto3" json:"authority_certs,omitempty"`
+	// The digests of the EFI applications, in load order.
+	EfiAppDigests [][]byte `protobuf:"bytes,12,rep,name=efi_app_digests,json=efiAppDigests,proto3" json:"efi_app_digests,omitempty"`
+	// The number of boot and runtime services drivers.
+	EfiDrivers uint32 `protobuf:"varint,13,opt,name=efi_drivers,json=efiDrivers,proto3" json:"efi_drivers,omitempty"`
+	// Whether the state has GRUB, UKI, Windows, and dynamic launch states.
+	Grub    bool `protobuf:"varint,14,opt,name=grub,proto3" json:"grub,omitempty"`
+	Uki     bool `protobuf:"varint,15,opt,name=uki,proto3" json:"uki,omitempty"`
+	Windows bool `protobuf:"varint,16,opt,name=windows,proto3" json:"windows,omitempty"`
+	Drtm    bool `protobuf:"varint,17,opt,name=drtm,proto3" json:"drtm,omitempty"`
+	// The kernel image and initrd digests from LinuxKernelState.
+	KernelDigest []byte `protobuf:"bytes,18,opt,name=kernel_digest,json=kernelDigest,proto3" json:"kernel_digest,omitempty"`
+	InitrdDigest []byte `protobuf:"bytes,19,opt,name=initrd_digest,json=initrdDigest,proto3" json:"initrd_digest,omitempty"`
+	// Whether the kernel command line matches the Linux EFI stub load options.
+	LoadOptionsMatch bool `protobuf:"varint,20,opt,name=load_options_match,json=loadOptionsMatch,proto3" json:"load_options_match,omitempty"`
+	// The number of kernels loaded via kexec.
+	KexecCount        uint32 `protobuf:"varint,21,opt,name=kexec_count,json=kexecCount,proto3" json:"kexec_count,omitempty"`
+	TotalEvents       uint32 `protobuf:"varint,22,opt,name=total_events,json=totalEvents,proto3" json:"total_events,omitempty"`
+	UnknownTypeEvents uint32 `protobuf:"varint,23,opt,name=unknown_type_events,json=unknownTypeEvents,proto3" json:"unknown_type_events,omitempty"`
+	// The distinct finding types, sorted.
+	FindingTypes []FindingType `protobuf:"varint,24,rep,packed,name=finding_types,json=findingTypes,proto3,enum=state.FindingType" json:"finding_types,omitempty"`
+	QuirkProfile string        `protobuf:"bytes,25,opt,name=quirk_profile,json=quirkProfile,proto3" json:"quirk_profile,omitempty"`
+	// The applied quirks, sorted.
+	AppliedQuirks []string `protobuf:"bytes,26,rep,name=applied_quirks,json=appliedQuirks,proto3" json:"applied_quirks,omitempty"`
+}
+
+func (x *FirmwareLogSummary) Reset() {
+	*x = FirmwareLogSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FirmwareLogSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FirmwareLogSummary) ProtoMessage() {}
+
+func (x *FirmwareLogSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FirmwareLogSummary.ProtoReflect.Descriptor instead.
+func (*FirmwareLogSummary) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *FirmwareLogSummary) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetLogType() LogType {
+	if x != nil {
+		return x.LogType
+	}
+	return LogType_LOG_TYPE_UNDEFINED
+}
+
+func (x *FirmwareLogSummary) GetHash() HashAlgo {
+	if x != nil {
+		return x.Hash
+	}
+	return HashAlgo_HASH_INVALID
+}
+
+func (x *FirmwareLogSummary) GetTechnology() GCEConfidentialTechnology {
+	if x != nil {
+		return x.Technology
+	}
+	return GCEConfidentialTechnology_NONE
+}
+
+func (x *FirmwareLogSummary) GetSecureBootEnabled() bool {
+	if x != nil {
+		return x.SecureBootEnabled
+	}
+	return false
+}
+
+func (x *FirmwareLogSummary) GetSecureBootDebugModeEnabled() bool {
+	if x != nil {
+		return x.SecureBootDebugModeEnabled
+	}
+	return false
+}
+
+func (x *FirmwareLogSummary) GetDbCerts() uint32 {
+	if x != nil {
+		return x.DbCerts
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetDbHashes() uint32 {
+	if x != nil {
+		return x.DbHashes
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetDbxCerts() uint32 {
+	if x != nil {
+		return x.DbxCerts
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetDbxHashes() uint32 {
+	if x != nil {
+		return x.DbxHashes
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetAuthorityCerts() uint32 {
+	if x != nil {
+		return x.AuthorityCerts
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetEfiAppDigests() [][]byte {
+	if x != nil {
+		return x.EfiAppDigests
+	}
+	return nil
+}
+
+func (x *FirmwareLogSummary) GetEfiDrivers() uint32 {
+	if x != nil {
+		return x.EfiDrivers
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetGrub() bool {
+	if x != nil {
+		return x.Grub
+	}
+	return false
+}
+
+func (x *FirmwareLogSummary) GetUki() bool {
+	if x != nil {
+		return x.Uki
+	}
+	return false
+}
+
+func (x *FirmwareLogSummary) GetWindows() bool {
+	if x != nil {
+		return x.Windows
+	}
+	return false
+}
+
+func (x *FirmwareLogSummary) GetDrtm() bool {
+	if x != nil {
+		return x.Drtm
+	}
+	return false
+}
+
+func (x *FirmwareLogSummary) GetKernelDigest() []byte {
+	if x != nil {
+		return x.KernelDigest
+	}
+	return nil
+}
+
+func (x *FirmwareLogSummary) GetInitrdDigest() []byte {
+	if x != nil {
+		return x.InitrdDigest
+	}
+	return nil
+}
+
+func (x *FirmwareLogSummary) GetLoadOptionsMatch() bool {
+	if x != nil {
+		return x.LoadOptionsMatch
+	}
+	return false
+}
+
+func (x *FirmwareLogSummary) GetKexecCount() uint32 {
+	if x != nil {
+		return x.KexecCount
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetTotalEvents() uint32 {
+	if x != nil {
+		return x.TotalEvents
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetUnknownTypeEvents() uint32 {
+	if x != nil {
+		return x.UnknownTypeEvents
+	}
+	return 0
+}
+
+func (x *FirmwareLogSummary) GetFindingTypes() []FindingType {
+	if x != nil {
+		return x.FindingTypes
+	}
+	return nil
+}
+
+func (x *FirmwareLogSummary) GetQuirkProfile() string {
+	if x != nil {
+		return x.QuirkProfile
+	}
+	return ""
+}
+
+func (x *FirmwareLogSummary) GetAppliedQuirks() []string {
+	if x != nil {
+		return x.AppliedQuirks
+	}
+	return nil
+}
+
+// The result of resolving the external content that an event refers to by
+// digest, e.g., a file whose hash is logged in EV_IPL event data.
+type DigestResolution struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of the event in the event log.
+	EventNum uint32 `protobuf:"varint,1,opt,name=event_num,json=eventNum,proto3" json:"event_num,omitempty"`
+	// The name of the resolver that handled the event.
+	Resolver string `protobuf:"bytes,2,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	// Identifies the resolved content, e.g., a file path or URL.
+	Reference string `protobuf:"bytes,3,opt,name=reference,proto3" json:"reference,omitempty"`
+	// Whether the content was fetched and matched the digest in the event.
+	Verified bool `protobuf:"varint,4,opt,name=verified,proto3" json:"verified,omitempty"`
+	// Why the content could not be verified. Empty if verified.
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *DigestResolution) Reset() {
+	*x = DigestResolution{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DigestResolution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DigestResolution) ProtoMessage() {}
+
+func (x *DigestResolution) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DigestResolution.ProtoReflect.Descriptor instead.
+func (*DigestResolution) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DigestResolution) GetEventNum() uint32 {
+	if x != nil {
+		return x.EventNum
+	}
+	return 0
+}
+
+func (x *DigestResolution) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
+func (x *DigestResolution) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *DigestResolution) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *DigestResolution) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// The evidence a FirmwareLogState was verified against.
+type Provenance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether the register values were verified against TPM quotes signed by an
+	// attestation key with a verified certificate chain. False if they were
+	// trusted as given, or if the attestation key was not certified.
+	QuotesVerified bool `protobuf:"varint,1,opt,name=quotes_verified,json=quotesVerified,proto3" json:"quotes_verified,omitempty"`
+	// The caller-supplied nonce that the verified quotes contain as extra data,
+	// binding the state to a fresh attestation. Empty if no nonce was checked.
+	Nonce []byte `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// When the register values were verified, in Unix seconds.
+	VerifyTime int64 `protobuf:"varint,3,opt,name=verify_time,json=verifyTime,proto3" json:"verify_time,omitempty"`
+}
+
+func (x *Provenance) Reset() {
+	*x = Provenance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Provenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provenance) ProtoMessage() {}
+
+func (x *Provenance) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provenance.ProtoReflect.Descriptor instead.
+func (*Provenance) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *Provenance) GetQuotesVerified() bool {
+	if x != nil {
+		return x.QuotesVerified
+	}
+	return false
+}
+
+func (x *Provenance) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *Provenance) GetVerifyTime() int64 {
+	if x != nil {
+		return x.VerifyTime
+	}
+	return 0
+}
+
+// A bank of measurement register values for a single hash algorithm.
+type RegisterBank struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash HashAlgo `protobuf:"varint,1,opt,name=hash,proto3,enum=state.HashAlgo" json:"hash,omitempty"`
+	// Map from register index to register value.
+	// For LOG_TYPE_TCG2, the index is the PCR index. For LOG_TYPE_CC, the index
+	// is the RTMR index (e.g., 1 for RTMR[1]), not the CC MR index.
+	Values map[uint32][]byte `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *RegisterBank) Reset() {
+	*x = RegisterBank{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterBank) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterBank) ProtoMessage() {}
+
+func (x *RegisterBank) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterBank.ProtoReflect.Descriptor instead.
+func (*RegisterBank) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RegisterBank) GetHash() HashAlgo {
+	if x != nil {
+		return x.Hash
+	}
+	return HashAlgo_HASH_INVALID
+}
+
+func (x *RegisterBank) GetValues() map[uint32][]byte {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// A TPM2_Quote over a selection of PCRs.
+type TpmQuote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The TPMS_ATTEST structure signed by the attestation key.
+	Quote []byte `protobuf:"bytes,1,opt,name=quote,proto3" json:"quote,omitempty"`
+	// The TPMT_SIGNATURE over quote.
+	RawSig []byte `protobuf:"bytes,2,opt,name=raw_sig,json=rawSig,proto3" json:"raw_sig,omitempty"`
+}
+
+func (x *TpmQuote) Reset() {
+	*x = TpmQuote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TpmQuote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TpmQuote) ProtoMessage() {}
+
+func (x *TpmQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TpmQuote.ProtoReflect.Descriptor instead.
+func (*TpmQuote) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *TpmQuote) GetQuote() []byte {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+func (x *TpmQuote) GetRawSig() []byte {
+	if x != nil {
+		return x.RawSig
+	}
+	return nil
+}
+
+// AttestationBundle packages the evidence needed to verify a firmware event
+// log, for transport between an attester and a verifier.
+type AttestationBundle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogType LogType `protobuf:"varint,1,opt,name=log_type,json=logType,proto3,enum=state.LogType" json:"log_type,omitempty"`
+	// The raw event log, optionally compressed.
+	RawEventLog []byte `protobuf:"bytes,2,opt,name=raw_event_log,json=rawEventLog,proto3" json:"raw_event_log,omitempty"`
+	// The CCEL ACPI table. Only set for LOG_TYPE_CC.
+	CcelAcpiTable []byte `protobuf:"bytes,3,opt,name=ccel_acpi_table,json=ccelAcpiTable,proto3" json:"ccel_acpi_table,omitempty"`
+	// The measurement register values the event log is replayed against.
+	Banks []*RegisterBank `protobuf:"bytes,4,rep,name=banks,proto3" json:"banks,omitempty"`
+	// TPM quotes over the PCR values in banks. Only used for LOG_TYPE_TCG2.
+	Quotes []*TpmQuote `protobuf:"bytes,5,rep,name=quotes,proto3" json:"quotes,omitempty"`
+	// The DER-encoded PKIX public key of the attestation key that signed the
+	// quotes. If empty, the public key of the first certificate is used.
+	AkPublic []byte `protobuf:"bytes,6,opt,name=ak_public,json=akPublic,proto3" json:"ak_public,omitempty"`
+	// DER-encoded X.509 certificates for the attestation key, leaf first.
+	Certificates [][]byte `protobuf:"bytes,7,rep,name=certificates,proto3" json:"certificates,omitempty"`
+}
+
+func (x *AttestationBundle) Reset() {
+	*x = AttestationBundle{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[10]
+		mi := &file_state_proto_msgTypes[43]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *EfiState) String() string {
+func (x *AttestationBundle) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EfiState) ProtoMessage() {}
+func (*AttestationBundle) ProtoMessage() {}
 
-func (x *EfiState) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[10]
+func (x *AttestationBundle) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1038,74 +4664,94 @@ func (x *EfiState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EfiState.ProtoReflect.Descriptor instead.
-func (*EfiState) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use AttestationBundle.ProtoReflect.Descriptor instead.
+func (*AttestationBundle) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *EfiState) GetApps() []*EfiApp {
+func (x *AttestationBundle) GetLogType() LogType {
 	if x != nil {
-		return x.Apps
+		return x.LogType
+	}
+	return LogType_LOG_TYPE_UNDEFINED
+}
+
+func (x *AttestationBundle) GetRawEventLog() []byte {
+	if x != nil {
+		return x.RawEventLog
 	}
 	return nil
 }
 
-func (x *EfiState) GetBootServicesDrivers() []*EfiApp {
+func (x *AttestationBundle) GetCcelAcpiTable() []byte {
 	if x != nil {
-		return x.BootServicesDrivers
+		return x.CcelAcpiTable
 	}
 	return nil
 }
 
-func (x *EfiState) GetRuntimeServicesDrivers() []*EfiApp {
+func (x *AttestationBundle) GetBanks() []*RegisterBank {
 	if x != nil {
-		return x.RuntimeServicesDrivers
+		return x.Banks
 	}
 	return nil
 }
 
-// The verified state of a booted machine, obtained from a UEFI event log.
-// The state is extracted from either EFI_TCG2_PROTOCOL or
-// EFI_CC_MEASUREMENT_PROTOCOL. Both of these follow the TCG-defined format
-// in https://trustedcomputinggroup.org/resource/tcg-efi-protocol-specification/
-// The TCG2-related (TPM) logs are structured using TCG_PCR_EVENT (SHA1 format)
-// or TCG_PCR_EVENT2 (Crypto Agile format).
-// The CC logs are structured using CC_EVENT.
-type FirmwareLogState struct {
+func (x *AttestationBundle) GetQuotes() []*TpmQuote {
+	if x != nil {
+		return x.Quotes
+	}
+	return nil
+}
+
+func (x *AttestationBundle) GetAkPublic() []byte {
+	if x != nil {
+		return x.AkPublic
+	}
+	return nil
+}
+
+func (x *AttestationBundle) GetCertificates() [][]byte {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+// The event log of a single boot of a machine, in a LogArchive.
+type ArchivedBoot struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Platform   *PlatformState   `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
-	SecureBoot *SecureBootState `protobuf:"bytes,2,opt,name=secure_boot,json=secureBoot,proto3" json:"secure_boot,omitempty"`
-	// The complete parsed Firmware Event Log, including those events used to
-	// create this MachineState.
-	RawEvents []*Event `protobuf:"bytes,3,rep,name=raw_events,json=rawEvents,proto3" json:"raw_events,omitempty"`
-	// The hash algorithm used to calculate event digests to verify a log entry.
-	Hash        HashAlgo          `protobuf:"varint,4,opt,name=hash,proto3,enum=state.HashAlgo" json:"hash,omitempty"`
-	Grub        *GrubState        `protobuf:"bytes,5,opt,name=grub,proto3" json:"grub,omitempty"`
-	LinuxKernel *LinuxKernelState `protobuf:"bytes,6,opt,name=linux_kernel,json=linuxKernel,proto3" json:"linux_kernel,omitempty"`
-	Efi         *EfiState         `protobuf:"bytes,8,opt,name=efi,proto3" json:"efi,omitempty"`
-	LogType     LogType           `protobuf:"varint,9,opt,name=log_type,json=logType,proto3,enum=state.LogType" json:"log_type,omitempty"`
+	// The boot counter of the machine, e.g., the TPM reset count. It increases
+	// with each boot.
+	BootCounter uint64 `protobuf:"varint,1,opt,name=boot_counter,json=bootCounter,proto3" json:"boot_counter,omitempty"`
+	// When the machine booted, in Unix seconds. 0 if unknown.
+	BootTime int64 `protobuf:"varint,2,opt,name=boot_time,json=bootTime,proto3" json:"boot_time,omitempty"`
+	// When the log was archived, in Unix seconds.
+	ArchiveTime int64 `protobuf:"varint,3,opt,name=archive_time,json=archiveTime,proto3" json:"archive_time,omitempty"`
+	// The evidence for the boot.
+	Bundle *AttestationBundle `protobuf:"bytes,4,opt,name=bundle,proto3" json:"bundle,omitempty"`
 }
 
-func (x *FirmwareLogState) Reset() {
-	*x = FirmwareLogState{}
+func (x *ArchivedBoot) Reset() {
+	*x = ArchivedBoot{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_state_proto_msgTypes[11]
+		mi := &file_state_proto_msgTypes[44]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *FirmwareLogState) String() string {
+func (x *ArchivedBoot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FirmwareLogState) ProtoMessage() {}
+func (*ArchivedBoot) ProtoMessage() {}
 
-func (x *FirmwareLogState) ProtoReflect() protoreflect.Message {
-	mi := &file_state_proto_msgTypes[11]
+func (x *ArchivedBoot) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[44]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1116,65 +4762,87 @@ func (x *FirmwareLogState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FirmwareLogState.ProtoReflect.Descriptor instead.
-func (*FirmwareLogState) Descriptor() ([]byte, []int) {
-	return file_state_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ArchivedBoot.ProtoReflect.Descriptor instead.
+func (*ArchivedBoot) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *FirmwareLogState) GetPlatform() *PlatformState {
+func (x *ArchivedBoot) GetBootCounter() uint64 {
 	if x != nil {
-		return x.Platform
+		return x.BootCounter
 	}
-	return nil
+	return 0
 }
 
-func (x *FirmwareLogState) GetSecureBoot() *SecureBootState {
+func (x *ArchivedBoot) GetBootTime() int64 {
 	if x != nil {
-		return x.SecureBoot
+		return x.BootTime
 	}
-	return nil
+	return 0
 }
 
-func (x *FirmwareLogState) GetRawEvents() []*Event {
+func (x *ArchivedBoot) GetArchiveTime() int64 {
 	if x != nil {
-		return x.RawEvents
+		return x.ArchiveTime
 	}
-	return nil
+	return 0
 }
 
-func (x *FirmwareLogState) GetHash() HashAlgo {
+func (x *ArchivedBoot) GetBundle() *AttestationBundle {
 	if x != nil {
-		return x.Hash
+		return x.Bundle
 	}
-	return HashAlgo_HASH_INVALID
+	return nil
 }
 
-func (x *FirmwareLogState) GetGrub() *GrubState {
-	if x != nil {
-		return x.Grub
-	}
-	return nil
+// LogArchive retains the event logs of successive boots of a machine, for
+// audit trails.
+type LogArchive struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The archived boots, in increasing boot_counter order.
+	Boots []*ArchivedBoot `protobuf:"bytes,1,rep,name=boots,proto3" json:"boots,omitempty"`
 }
 
-func (x *FirmwareLogState) GetLinuxKernel() *LinuxKernelState {
-	if x != nil {
-		return x.LinuxKernel
+func (x *LogArchive) Reset() {
+	*x = LogArchive{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_state_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *FirmwareLogState) GetEfi() *EfiState {
-	if x != nil {
-		return x.Efi
+func (x *LogArchive) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogArchive) ProtoMessage() {}
+
+func (x *LogArchive) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *FirmwareLogState) GetLogType() LogType {
+// Deprecated: Use LogArchive.ProtoReflect.Descriptor instead.
+func (*LogArchive) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *LogArchive) GetBoots() []*ArchivedBoot {
 	if x != nil {
-		return x.LogType
+		return x.Boots
 	}
-	return LogType_LOG_TYPE_UNDEFINED
+	return nil
 }
 
 var File_state_proto protoreflect.FileDescriptor
@@ -1207,43 +4875,206 @@ var file_state_proto_rawDesc = []byte{
 	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x47, 0x43, 0x45, 0x49, 0x6e,
 	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74,
 	0x61, 0x6e, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x42, 0x0a, 0x0a, 0x08, 0x66, 0x69, 0x72, 0x6d,
-	0x77, 0x61, 0x72, 0x65, 0x22, 0x51, 0x0a, 0x08, 0x47, 0x72, 0x75, 0x62, 0x46, 0x69, 0x6c, 0x65,
-	0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x12, 0x75, 0x6e, 0x74, 0x72,
-	0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x46,
-	0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x4e, 0x0a, 0x09, 0x47, 0x72, 0x75, 0x62, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x47, 0x72, 0x75, 0x62,
-	0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63,
-	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x63,
-	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x22, 0x35, 0x0a, 0x10, 0x4c, 0x69, 0x6e, 0x75, 0x78,
-	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63,
-	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x22, 0xa0,
-	0x01, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x63, 0x72, 0x5f,
-	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x63, 0x72,
-	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74,
-	0x65, 0x64, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x75,
-	0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65,
-	0x73, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65,
-	0x64, 0x22, 0x71, 0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
-	0x12, 0x12, 0x0a, 0x03, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52,
-	0x03, 0x64, 0x65, 0x72, 0x12, 0x3c, 0x0a, 0x0a, 0x77, 0x65, 0x6c, 0x6c, 0x5f, 0x6b, 0x6e, 0x6f,
-	0x77, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x2e, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
-	0x69, 0x63, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x09, 0x77, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f,
-	0x77, 0x6e, 0x42, 0x10, 0x0a, 0x0e, 0x72, 0x65, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4c, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
-	0x12, 0x28, 0x0a, 0x05, 0x63, 0x65, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x12, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
-	0x61, 0x74, 0x65, 0x52, 0x05, 0x63, 0x65, 0x72, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61,
-	0x73, 0x68, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68,
-	0x65, 0x73, 0x22, 0xe2, 0x01, 0x0a, 0x0f, 0x53, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f,
+	0x77, 0x61, 0x72, 0x65, 0x22, 0x49, 0x0a, 0x0a, 0x42, 0x61, 0x6e, 0x6b, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x12, 0x23, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67,
+	0x6f, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22,
+	0x3a, 0x0a, 0x0b, 0x42, 0x61, 0x6e, 0x6b, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x12, 0x2b,
+	0x0a, 0x07, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x52, 0x07, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x22, 0xac, 0x01, 0x0a, 0x08,
+	0x47, 0x72, 0x75, 0x62, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x12, 0x2d, 0x0a, 0x12, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x69,
+	0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x75, 0x6e,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x23, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x52, 0x04,
+	0x68, 0x61, 0x73, 0x68, 0x12, 0x34, 0x0a, 0x0c, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x0b, 0x62,
+	0x61, 0x6e, 0x6b, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x22, 0xbc, 0x01, 0x0a, 0x09, 0x47,
+	0x72, 0x75, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x47, 0x72, 0x75, 0x62, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x2f, 0x0a, 0x13, 0x6e,
+	0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x3b, 0x0a, 0x0f,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x42, 0x61,
+	0x6e, 0x6b, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x52, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x22, 0xdd, 0x01, 0x0a, 0x10, 0x4c, 0x69,
+	0x6e, 0x75, 0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21,
+	0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c, 0x69, 0x6e,
+	0x65, 0x12, 0x2e, 0x0a, 0x13, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11,
+	0x6c, 0x6f, 0x61, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x44, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x69, 0x74, 0x72, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x69, 0x6e, 0x69, 0x74, 0x72, 0x64,
+	0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x10, 0x6c, 0x6f, 0x61, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x6b, 0x65, 0x72,
+	0x6e, 0x65, 0x6c, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x38, 0x0a, 0x0a, 0x55, 0x6b, 0x69,
+	0x53, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x22, 0x51, 0x0a, 0x08, 0x55, 0x6b, 0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x2d, 0x0a, 0x08, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x55, 0x6b, 0x69, 0x53, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06,
+	0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x22, 0xfb, 0x03, 0x0a, 0x10, 0x57, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x73, 0x53, 0x69, 0x70, 0x61, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x62,
+	0x6f, 0x6f, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x62, 0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x16, 0x62, 0x6f,
+	0x6f, 0x74, 0x5f, 0x64, 0x65, 0x62, 0x75, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x62, 0x6f, 0x6f, 0x74,
+	0x44, 0x65, 0x62, 0x75, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x12, 0x30, 0x0a, 0x14, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x64, 0x65, 0x62, 0x75, 0x67,
+	0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12,
+	0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x44, 0x65, 0x62, 0x75, 0x67, 0x45, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x12, 0x34, 0x0a, 0x16, 0x63, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x67,
+	0x72, 0x69, 0x74, 0x79, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x14, 0x63, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74,
+	0x79, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x74, 0x65, 0x73, 0x74,
+	0x5f, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x74, 0x65, 0x73, 0x74, 0x53, 0x69, 0x67, 0x6e,
+	0x69, 0x6e, 0x67, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65,
+	0x70, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x64, 0x65, 0x70, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73,
+	0x61, 0x66, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x73, 0x61, 0x66, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x6e, 0x70,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x77, 0x69, 0x6e, 0x70, 0x65, 0x12, 0x34,
+	0x0a, 0x16, 0x68, 0x79, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x5f, 0x6c, 0x61, 0x75,
+	0x6e, 0x63, 0x68, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x14,
+	0x68, 0x79, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x62, 0x69, 0x74, 0x6c, 0x6f, 0x63, 0x6b, 0x65,
+	0x72, 0x5f, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0d, 0x52,
+	0x10, 0x62, 0x69, 0x74, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x12, 0x41, 0x0a, 0x0e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x75,
+	0x6c, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x4c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x4d,
+	0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x0d, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x4d, 0x6f, 0x64,
+	0x75, 0x6c, 0x65, 0x73, 0x22, 0x96, 0x03, 0x0a, 0x13, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73,
+	0x4c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x68, 0x61, 0x73, 0x68,
+	0x5f, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0f, 0x68, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74,
+	0x68, 0x6d, 0x49, 0x64, 0x12, 0x2b, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
+	0x63, 0x6f, 0x64, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x10, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x6f, 0x64, 0x65, 0x48, 0x61, 0x73,
+	0x68, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x69,
+	0x73, 0x73, 0x75, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x12, 0x2f, 0x0a, 0x13,
+	0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73,
+	0x68, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x61, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x69, 0x74, 0x79, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x12, 0x29, 0x0a,
+	0x10, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x69, 0x61,
+	0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12, 0x3a, 0x0a, 0x19, 0x61, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x73, 0x68, 0x61, 0x31, 0x5f, 0x74, 0x68, 0x75, 0x6d, 0x62,
+	0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x17, 0x61, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x53, 0x68, 0x61, 0x31, 0x54, 0x68, 0x75, 0x6d, 0x62, 0x70,
+	0x72, 0x69, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x64, 0x22, 0x6e, 0x0a,
+	0x0a, 0x4b, 0x65, 0x78, 0x65, 0x63, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x67,
+	0x72, 0x75, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x2e, 0x47, 0x72, 0x75, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x04, 0x67, 0x72, 0x75,
+	0x62, 0x12, 0x3a, 0x0a, 0x0c, 0x6c, 0x69, 0x6e, 0x75, 0x78, 0x5f, 0x6b, 0x65, 0x72, 0x6e, 0x65,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x4c, 0x69, 0x6e, 0x75, 0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x0b, 0x6c, 0x69, 0x6e, 0x75, 0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x22, 0xba, 0x01,
+	0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x63, 0x72, 0x5f, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x63, 0x72, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65,
+	0x64, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x75, 0x6e,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22, 0xc7, 0x02, 0x0a, 0x09, 0x44,
+	0x72, 0x74, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x69, 0x6e, 0x69,
+	0x74, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
+	0x73, 0x69, 0x6e, 0x69, 0x74, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x73,
+	0x69, 0x6e, 0x69, 0x74, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x5f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x73, 0x69, 0x6e, 0x69, 0x74, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d,
+	0x6c, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x6d, 0x6c, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x0a, 0x6d, 0x6c,
+	0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x6d, 0x6c,
+	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x35, 0x0a,
+	0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x15, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x44, 0x72, 0x74, 0x6d, 0x54, 0x65,
+	0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x52, 0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x6f,
+	0x6c, 0x6f, 0x67, 0x79, 0x12, 0x3e, 0x0a, 0x14, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x6c,
+	0x61, 0x75, 0x6e, 0x63, 0x68, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x52, 0x12, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x22, 0xf6, 0x01, 0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x03, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x48, 0x00, 0x52, 0x03, 0x64, 0x65, 0x72, 0x12, 0x3c, 0x0a, 0x0a, 0x77, 0x65, 0x6c, 0x6c,
+	0x5f, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x2e, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x43, 0x65,
+	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x09, 0x77, 0x65, 0x6c,
+	0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x12, 0x36, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x28,
+	0x0a, 0x05, 0x74, 0x72, 0x75, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x54, 0x72, 0x75, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x05, 0x74, 0x72, 0x75, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x5f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x42, 0x10, 0x0a, 0x0e, 0x72,
+	0x65, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xb2, 0x01,
+	0x0a, 0x13, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62,
+	0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x6f, 0x74,
+	0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x66,
+	0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41, 0x66,
+	0x74, 0x65, 0x72, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x5f, 0x66, 0x69,
+	0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x11, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x22, 0x7d, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x28,
+	0x0a, 0x05, 0x63, 0x65, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x52, 0x05, 0x63, 0x65, 0x72, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73,
+	0x12, 0x2f, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x22, 0xce, 0x01, 0x0a, 0x0e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x28, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x14, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x67, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x74, 0x79, 0x70, 0x65, 0x47, 0x75, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6f,
+	0x77, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65,
+	0x72, 0x12, 0x1f, 0x0a, 0x0a, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x09, 0x63, 0x65, 0x72, 0x74, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x1f, 0x0a, 0x0a, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x09, 0x68, 0x61, 0x73, 0x68, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x22, 0xaf, 0x03, 0x0a, 0x0f, 0x53, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f,
 	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
 	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
 	0x12, 0x1f, 0x0a, 0x02, 0x64, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73,
@@ -1257,74 +5088,532 @@ var file_state_proto_rawDesc = []byte{
 	0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
 	0x52, 0x02, 0x70, 0x6b, 0x12, 0x21, 0x0a, 0x03, 0x6b, 0x65, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
-	0x73, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x6b, 0x22, 0x20, 0x0a, 0x06, 0x45, 0x66, 0x69, 0x41, 0x70,
-	0x70, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0xb9, 0x01, 0x0a, 0x08, 0x45, 0x66,
-	0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x04, 0x61, 0x70, 0x70, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x66, 0x69,
-	0x41, 0x70, 0x70, 0x52, 0x04, 0x61, 0x70, 0x70, 0x73, 0x12, 0x41, 0x0a, 0x15, 0x62, 0x6f, 0x6f,
-	0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x64, 0x72, 0x69, 0x76, 0x65,
-	0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x2e, 0x45, 0x66, 0x69, 0x41, 0x70, 0x70, 0x52, 0x13, 0x62, 0x6f, 0x6f, 0x74, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x44, 0x72, 0x69, 0x76, 0x65, 0x72, 0x73, 0x12, 0x47, 0x0a, 0x18,
-	0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x5f, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d,
-	0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x66, 0x69, 0x41, 0x70, 0x70, 0x52, 0x16, 0x72,
-	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x44, 0x72,
-	0x69, 0x76, 0x65, 0x72, 0x73, 0x22, 0x85, 0x03, 0x0a, 0x10, 0x46, 0x69, 0x72, 0x6d, 0x77, 0x61,
-	0x72, 0x65, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x70, 0x6c,
-	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73,
-	0x74, 0x61, 0x74, 0x65, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x37, 0x0a, 0x0b,
-	0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x62, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x16, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x65, 0x63, 0x75, 0x72, 0x65,
-	0x42, 0x6f, 0x6f, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x75, 0x72,
-	0x65, 0x42, 0x6f, 0x6f, 0x74, 0x12, 0x2b, 0x0a, 0x0a, 0x72, 0x61, 0x77, 0x5f, 0x65, 0x76, 0x65,
-	0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x73, 0x74, 0x61, 0x74,
-	0x65, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x72, 0x61, 0x77, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x73, 0x12, 0x23, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
+	0x73, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x6b, 0x12, 0x40, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x69, 0x74, 0x79, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x74, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0f, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x74, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x64, 0x65, 0x62, 0x75, 0x67, 0x4d, 0x6f, 0x64, 0x65,
+	0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x38, 0x0a, 0x0a, 0x64, 0x62, 0x78, 0x5f, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4c, 0x69, 0x73,
+	0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x09, 0x64, 0x62, 0x78, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x73, 0x12, 0x21, 0x0a, 0x03, 0x6d, 0x6f, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4d, 0x6f, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x03, 0x6d, 0x6f, 0x6b, 0x22, 0x97, 0x02, 0x0a, 0x08, 0x4d, 0x6f, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x39, 0x0a, 0x0c, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x4d, 0x6f, 0x6b, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0c,
+	0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f,
+	0x6d, 0x6f, 0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x6d, 0x6f, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x11, 0x6d, 0x6f, 0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74,
+	0x5f, 0x78, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0e, 0x6d, 0x6f, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x58, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12,
+	0x28, 0x0a, 0x10, 0x6d, 0x6f, 0x6b, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6d, 0x6f, 0x6b, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x62, 0x61,
+	0x74, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73,
+	0x62, 0x61, 0x74, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x34, 0x0a, 0x16, 0x61, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x14, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x74, 0x79, 0x43, 0x65, 0x72, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x22, 0x8e,
+	0x01, 0x0a, 0x0e, 0x4d, 0x6f, 0x6b, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x4e, 0x75, 0x6d, 0x12, 0x25,
+	0x0a, 0x0e, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65,
+	0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22,
+	0x42, 0x0a, 0x12, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4c, 0x69, 0x73, 0x74,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x22, 0x6e, 0x0a, 0x0e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79,
+	0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x65, 0x72, 0x74, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x75, 0x73,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x55, 0x73, 0x65, 0x52, 0x04, 0x75,
+	0x73, 0x65, 0x73, 0x22, 0x9b, 0x01, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74,
+	0x79, 0x55, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x75,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x4e, 0x75,
+	0x6d, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62,
+	0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0d, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4e, 0x75, 0x6d, 0x12, 0x21,
+	0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x22, 0xae, 0x02, 0x0a, 0x06, 0x45, 0x66, 0x69, 0x41, 0x70, 0x70, 0x12, 0x16, 0x0a, 0x06,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x15, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65,
+	0x64, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x13, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x2e, 0x0a, 0x13, 0x75, 0x6e, 0x74, 0x72,
+	0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64,
+	0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x38, 0x0a, 0x18, 0x75, 0x6e, 0x74, 0x72,
+	0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x67, 0x75, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x75, 0x6e, 0x74, 0x72,
+	0x75, 0x73, 0x74, 0x65, 0x64, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x47, 0x75,
+	0x69, 0x64, 0x12, 0x38, 0x0a, 0x18, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f,
+	0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x16, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x49,
+	0x6d, 0x61, 0x67, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x34, 0x0a, 0x16,
+	0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f,
+	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x14, 0x75, 0x6e,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x4c, 0x65, 0x6e, 0x67,
+	0x74, 0x68, 0x22, 0x9b, 0x03, 0x0a, 0x08, 0x45, 0x66, 0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x21, 0x0a, 0x04, 0x61, 0x70, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x66, 0x69, 0x41, 0x70, 0x70, 0x52, 0x04, 0x61, 0x70,
+	0x70, 0x73, 0x12, 0x41, 0x0a, 0x15, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x5f, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x66, 0x69, 0x41, 0x70, 0x70,
+	0x52, 0x13, 0x62, 0x6f, 0x6f, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x44, 0x72,
+	0x69, 0x76, 0x65, 0x72, 0x73, 0x12, 0x47, 0x0a, 0x18, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x45, 0x66, 0x69, 0x41, 0x70, 0x70, 0x52, 0x16, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x44, 0x72, 0x69, 0x76, 0x65, 0x72, 0x73, 0x12, 0x47,
+	0x0a, 0x19, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x6f, 0x73, 0x74,
+	0x5f, 0x65, 0x62, 0x73, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0c, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52,
+	0x16, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x50, 0x6f, 0x73, 0x74, 0x45, 0x62,
+	0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x6b, 0x0a, 0x23, 0x75, 0x6e, 0x74, 0x72, 0x75,
+	0x73, 0x74, 0x65, 0x64, 0x5f, 0x65, 0x78, 0x69, 0x74, 0x5f, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x78, 0x69,
+	0x74, 0x42, 0x6f, 0x6f, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x1f, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x45, 0x78,
+	0x69, 0x74, 0x42, 0x6f, 0x6f, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x2a, 0x0a, 0x07, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x66,
+	0x69, 0x44, 0x72, 0x69, 0x76, 0x65, 0x72, 0x52, 0x07, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x73,
+	0x22, 0xe3, 0x02, 0x0a, 0x09, 0x45, 0x66, 0x69, 0x44, 0x72, 0x69, 0x76, 0x65, 0x72, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f,
+	0x6e, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x4e, 0x75, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x25, 0x0a,
+	0x0e, 0x70, 0x6f, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x70, 0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x70, 0x6f, 0x73, 0x74, 0x53, 0x65, 0x70, 0x61, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x12, 0x32, 0x0a, 0x15, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65,
+	0x64, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x13, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x30, 0x0a, 0x14, 0x75, 0x6e, 0x74, 0x72,
+	0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x6f, 0x6d,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65,
+	0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x6f, 0x6d, 0x12, 0x3d, 0x0a, 0x1b, 0x75, 0x6e,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x70,
+	0x61, 0x74, 0x68, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x18, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x50, 0x61, 0x74, 0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x3b, 0x0a, 0x1a, 0x75, 0x6e, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x6c, 0x6f, 0x61,
+	0x64, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x17, 0x75,
+	0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x4c, 0x6f, 0x61,
+	0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x66, 0x0a, 0x0a, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x63, 0x72, 0x5f, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x63, 0x72, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x75, 0x6e, 0x74, 0x72, 0x75,
+	0x73, 0x74, 0x65, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xd8,
+	0x01, 0x0a, 0x0d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64,
+	0x61, 0x74, 0x61, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x6f, 0x70, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x6f, 0x70, 0x79, 0x12, 0x29,
+	0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x75, 0x6e, 0x6b,
+	0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x54,
+	0x79, 0x70, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xac, 0x01, 0x0a, 0x10, 0x43, 0x6f,
+	0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x1b,
+	0x0a, 0x09, 0x70, 0x63, 0x72, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x70, 0x63, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x20, 0x0a, 0x0b, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x20, 0x0a,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x09, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x4e, 0x75, 0x6d, 0x73, 0x22, 0x64, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x66,
+	0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x2f, 0x0a,
+	0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x63,
+	0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x74, 0x22, 0x53,
+	0x0a, 0x07, 0x46, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x46, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0xf8, 0x07, 0x0a, 0x10, 0x46, 0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65,
+	0x4c, 0x6f, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74,
+	0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x37, 0x0a, 0x0b, 0x73, 0x65,
+	0x63, 0x75, 0x72, 0x65, 0x5f, 0x62, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f,
+	0x6f, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42,
+	0x6f, 0x6f, 0x74, 0x12, 0x2b, 0x0a, 0x0a, 0x72, 0x61, 0x77, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x72, 0x61, 0x77, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x23, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x52,
+	0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x24, 0x0a, 0x04, 0x67, 0x72, 0x75, 0x62, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x47, 0x72, 0x75, 0x62,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x04, 0x67, 0x72, 0x75, 0x62, 0x12, 0x3a, 0x0a, 0x0c, 0x6c,
+	0x69, 0x6e, 0x75, 0x78, 0x5f, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x4b,
+	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x6c, 0x69, 0x6e, 0x75,
+	0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x21, 0x0a, 0x03, 0x65, 0x66, 0x69, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x66, 0x69,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x03, 0x65, 0x66, 0x69, 0x12, 0x29, 0x0a, 0x08, 0x6c, 0x6f,
+	0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x2e, 0x4c, 0x6f, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x6c, 0x6f,
+	0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x6b, 0x65, 0x78, 0x65, 0x63, 0x18, 0x0a,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4b, 0x65, 0x78,
+	0x65, 0x63, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x6b, 0x65, 0x78, 0x65, 0x63, 0x12, 0x2a,
+	0x0a, 0x08, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x52, 0x08, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x2a, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x04, 0x64, 0x72, 0x74, 0x6d, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x44, 0x72, 0x74,
+	0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x04, 0x64, 0x72, 0x74, 0x6d, 0x12, 0x31, 0x0a, 0x0a,
+	0x70, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12,
+	0x46, 0x0a, 0x12, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x11, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x6f,
+	0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x23,
+	0x0a, 0x0d, 0x71, 0x75, 0x69, 0x72, 0x6b, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x71, 0x75, 0x69, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x5f, 0x71,
+	0x75, 0x69, 0x72, 0x6b, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x70, 0x70,
+	0x6c, 0x69, 0x65, 0x64, 0x51, 0x75, 0x69, 0x72, 0x6b, 0x73, 0x12, 0x3a, 0x0a, 0x0c, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x73, 0x5f, 0x73, 0x69, 0x70, 0x61, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73,
+	0x53, 0x69, 0x70, 0x61, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x77, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x73, 0x53, 0x69, 0x70, 0x61, 0x12, 0x21, 0x0a, 0x03, 0x75, 0x6b, 0x69, 0x18, 0x14, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x55, 0x6b, 0x69, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x03, 0x75, 0x6b, 0x69, 0x12, 0x37, 0x0a, 0x0b, 0x62, 0x6f, 0x6f,
+	0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x21, 0x0a, 0x03, 0x67, 0x70, 0x74, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x47, 0x70, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x03, 0x67, 0x70, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x61, 0x63, 0x74, 0x65,
+	0x64, 0x5f, 0x6d, 0x72, 0x73, 0x18, 0x17, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0b, 0x72, 0x65, 0x64,
+	0x61, 0x63, 0x74, 0x65, 0x64, 0x4d, 0x72, 0x73, 0x4a, 0x04, 0x08, 0x07, 0x10, 0x08, 0x22, 0x5c,
+	0x0a, 0x08, 0x47, 0x70, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x69,
+	0x73, 0x6b, 0x5f, 0x67, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64,
+	0x69, 0x73, 0x6b, 0x47, 0x75, 0x69, 0x64, 0x12, 0x33, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x2e, 0x47, 0x70, 0x74, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xc8, 0x01, 0x0a,
+	0x0c, 0x47, 0x70, 0x74, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a,
+	0x09, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x67, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x74, 0x79, 0x70, 0x65, 0x47, 0x75, 0x69, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61,
+	0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x67, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x47, 0x75, 0x69,
+	0x64, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x62,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e,
+	0x67, 0x4c, 0x62, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x6c,
+	0x62, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x4c, 0x62, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xde, 0x01, 0x0a, 0x0f, 0x42, 0x6f, 0x6f, 0x74,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x62,
+	0x6f, 0x6f, 0x74, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52,
+	0x09, 0x62, 0x6f, 0x6f, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x0c, 0x62, 0x6f,
+	0x6f, 0x74, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x62, 0x6f, 0x6f, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x43, 0x0a, 0x10, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x5f, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0f, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x73, 0x12, 0x31, 0x0a, 0x14, 0x75, 0x6e, 0x76, 0x65, 0x72, 0x69, 0x66,
+	0x69, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x13, 0x75, 0x6e, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x56,
+	0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x22, 0xc4, 0x01, 0x0a, 0x0a, 0x42, 0x6f, 0x6f,
+	0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x1e, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0c, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x22,
+	0xa4, 0x01, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x6e,
+	0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x4e,
+	0x75, 0x6d, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x75, 0x6e, 0x74, 0x72,
+	0x75, 0x73, 0x74, 0x65, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x33, 0x0a, 0x15, 0x75, 0x6e, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x75, 0x6e, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x65, 0x64, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0xec, 0x07, 0x0a, 0x12, 0x46, 0x69, 0x72, 0x6d, 0x77,
+	0x61, 0x72, 0x65, 0x4c, 0x6f, 0x67, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x25, 0x0a,
+	0x0e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4c,
+	0x6f, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x23, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x52, 0x04,
+	0x68, 0x61, 0x73, 0x68, 0x12, 0x40, 0x0a, 0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f,
+	0x67, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x2e, 0x47, 0x43, 0x45, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c,
+	0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x52, 0x0a, 0x74, 0x65, 0x63, 0x68,
+	0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65,
+	0x5f, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x11, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f, 0x74, 0x45,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x42, 0x0a, 0x1e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65,
+	0x5f, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x64, 0x65, 0x62, 0x75, 0x67, 0x5f, 0x6d, 0x6f, 0x64, 0x65,
+	0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a,
+	0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x42, 0x6f, 0x6f, 0x74, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4d,
+	0x6f, 0x64, 0x65, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x62,
+	0x5f, 0x63, 0x65, 0x72, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x64, 0x62,
+	0x43, 0x65, 0x72, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x62, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x62, 0x48, 0x61, 0x73, 0x68,
+	0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x62, 0x78, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x73, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x62, 0x78, 0x43, 0x65, 0x72, 0x74, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x64, 0x62, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x64, 0x62, 0x78, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x12, 0x27,
+	0x0a, 0x0f, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x63, 0x65, 0x72, 0x74,
+	0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x43, 0x65, 0x72, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x65, 0x66, 0x69, 0x5f, 0x61,
+	0x70, 0x70, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0c,
+	0x52, 0x0d, 0x65, 0x66, 0x69, 0x41, 0x70, 0x70, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x65, 0x66, 0x69, 0x5f, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x73, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x65, 0x66, 0x69, 0x44, 0x72, 0x69, 0x76, 0x65, 0x72, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x67, 0x72, 0x75, 0x62, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04,
+	0x67, 0x72, 0x75, 0x62, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x6b, 0x69, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x03, 0x75, 0x6b, 0x69, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x72, 0x74, 0x6d, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04,
+	0x64, 0x72, 0x74, 0x6d, 0x12, 0x23, 0x0a, 0x0d, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x6b, 0x65, 0x72,
+	0x6e, 0x65, 0x6c, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x69,
+	0x74, 0x72, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0c, 0x69, 0x6e, 0x69, 0x74, 0x72, 0x64, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x2c,
+	0x0a, 0x12, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x6c, 0x6f, 0x61, 0x64,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6b, 0x65, 0x78, 0x65, 0x63, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x15, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0a, 0x6b, 0x65, 0x78, 0x65, 0x63, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x16, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x2e, 0x0a, 0x13, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x75,
+	0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x37, 0x0a, 0x0d, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x18, 0x18, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x46, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x66, 0x69, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x71, 0x75, 0x69,
+	0x72, 0x6b, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x71, 0x75, 0x69, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x25,
+	0x0a, 0x0e, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x5f, 0x71, 0x75, 0x69, 0x72, 0x6b, 0x73,
+	0x18, 0x1a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x51,
+	0x75, 0x69, 0x72, 0x6b, 0x73, 0x22, 0x9b, 0x01, 0x0a, 0x10, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x4e, 0x75, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c,
+	0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c,
+	0x76, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x22, 0x6c, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x12, 0x27, 0x0a, 0x0f, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x69,
+	0x66, 0x69, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x71, 0x75, 0x6f, 0x74,
+	0x65, 0x73, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f,
+	0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x54, 0x69, 0x6d,
+	0x65, 0x22, 0xa7, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x61,
+	0x6e, 0x6b, 0x12, 0x23, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
 	0x32, 0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67,
-	0x6f, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x24, 0x0a, 0x04, 0x67, 0x72, 0x75, 0x62, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x47, 0x72,
-	0x75, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x04, 0x67, 0x72, 0x75, 0x62, 0x12, 0x3a, 0x0a,
-	0x0c, 0x6c, 0x69, 0x6e, 0x75, 0x78, 0x5f, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4c, 0x69, 0x6e, 0x75,
-	0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x6c, 0x69,
-	0x6e, 0x75, 0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x21, 0x0a, 0x03, 0x65, 0x66, 0x69,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45,
-	0x66, 0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x03, 0x65, 0x66, 0x69, 0x12, 0x29, 0x0a, 0x08,
-	0x6c, 0x6f, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e,
+	0x6f, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x37, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x2e, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x1a, 0x39, 0x0a, 0x0b, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x39, 0x0a, 0x08, 0x54,
+	0x70, 0x6d, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x12, 0x17, 0x0a,
+	0x07, 0x72, 0x61, 0x77, 0x5f, 0x73, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x72, 0x61, 0x77, 0x53, 0x69, 0x67, 0x22, 0x9f, 0x02, 0x0a, 0x11, 0x41, 0x74, 0x74, 0x65, 0x73,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x29, 0x0a, 0x08,
+	0x6c, 0x6f, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e,
 	0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4c, 0x6f, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07,
-	0x6c, 0x6f, 0x67, 0x54, 0x79, 0x70, 0x65, 0x4a, 0x04, 0x08, 0x07, 0x10, 0x08, 0x2a, 0x45, 0x0a,
-	0x07, 0x4c, 0x6f, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x4c, 0x4f, 0x47, 0x5f,
-	0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00,
-	0x12, 0x11, 0x0a, 0x0d, 0x4c, 0x4f, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x54, 0x43, 0x47,
-	0x32, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x4c, 0x4f, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
-	0x43, 0x43, 0x10, 0x02, 0x2a, 0x62, 0x0a, 0x19, 0x47, 0x43, 0x45, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67,
-	0x79, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x41,
-	0x4d, 0x44, 0x5f, 0x53, 0x45, 0x56, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x4d, 0x44, 0x5f,
-	0x53, 0x45, 0x56, 0x5f, 0x45, 0x53, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x54, 0x45,
-	0x4c, 0x5f, 0x54, 0x44, 0x58, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x4d, 0x44, 0x5f, 0x53,
-	0x45, 0x56, 0x5f, 0x53, 0x4e, 0x50, 0x10, 0x04, 0x2a, 0x96, 0x01, 0x0a, 0x14, 0x57, 0x65, 0x6c,
-	0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
-	0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x1c,
-	0x0a, 0x18, 0x4d, 0x53, 0x5f, 0x57, 0x49, 0x4e, 0x44, 0x4f, 0x57, 0x53, 0x5f, 0x50, 0x52, 0x4f,
-	0x44, 0x5f, 0x50, 0x43, 0x41, 0x5f, 0x32, 0x30, 0x31, 0x31, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b,
-	0x4d, 0x53, 0x5f, 0x54, 0x48, 0x49, 0x52, 0x44, 0x5f, 0x50, 0x41, 0x52, 0x54, 0x59, 0x5f, 0x55,
-	0x45, 0x46, 0x49, 0x5f, 0x43, 0x41, 0x5f, 0x32, 0x30, 0x31, 0x31, 0x10, 0x02, 0x12, 0x1e, 0x0a,
-	0x1a, 0x4d, 0x53, 0x5f, 0x54, 0x48, 0x49, 0x52, 0x44, 0x5f, 0x50, 0x41, 0x52, 0x54, 0x59, 0x5f,
-	0x4b, 0x45, 0x4b, 0x5f, 0x43, 0x41, 0x5f, 0x32, 0x30, 0x31, 0x31, 0x10, 0x03, 0x12, 0x12, 0x0a,
-	0x0e, 0x47, 0x43, 0x45, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x50, 0x4b, 0x10,
-	0x04, 0x2a, 0x4a, 0x0a, 0x08, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x12, 0x10, 0x0a,
-	0x0c, 0x48, 0x41, 0x53, 0x48, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x00, 0x12,
-	0x08, 0x0a, 0x04, 0x53, 0x48, 0x41, 0x31, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41,
-	0x32, 0x35, 0x36, 0x10, 0x0b, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41, 0x33, 0x38, 0x34, 0x10,
-	0x0c, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41, 0x35, 0x31, 0x32, 0x10, 0x0d, 0x42, 0x2b, 0x5a,
-	0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2f, 0x67, 0x6f, 0x2d, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x6c, 0x6f, 0x67, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x6c, 0x6f, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x72, 0x61, 0x77, 0x5f, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
+	0x72, 0x61, 0x77, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x67, 0x12, 0x26, 0x0a, 0x0f, 0x63,
+	0x63, 0x65, 0x6c, 0x5f, 0x61, 0x63, 0x70, 0x69, 0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x63, 0x63, 0x65, 0x6c, 0x41, 0x63, 0x70, 0x69, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x62, 0x61, 0x6e, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x05, 0x62, 0x61, 0x6e, 0x6b, 0x73, 0x12, 0x27,
+	0x0a, 0x06, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x54, 0x70, 0x6d, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x52,
+	0x06, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6b, 0x5f, 0x70, 0x75,
+	0x62, 0x6c, 0x69, 0x63, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x61, 0x6b, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x63, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x63, 0x65, 0x72, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x0c, 0x41, 0x72, 0x63,
+	0x68, 0x69, 0x76, 0x65, 0x64, 0x42, 0x6f, 0x6f, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6f, 0x6f,
+	0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x62, 0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09,
+	0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x62, 0x6f, 0x6f, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x72, 0x63,
+	0x68, 0x69, 0x76, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x06,
+	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x52, 0x06, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x22, 0x37,
+	0x0a, 0x0a, 0x4c, 0x6f, 0x67, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x12, 0x29, 0x0a, 0x05,
+	0x62, 0x6f, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x42, 0x6f, 0x6f, 0x74,
+	0x52, 0x05, 0x62, 0x6f, 0x6f, 0x74, 0x73, 0x2a, 0x45, 0x0a, 0x07, 0x4c, 0x6f, 0x67, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x4c, 0x4f, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55,
+	0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x4c, 0x4f,
+	0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x54, 0x43, 0x47, 0x32, 0x10, 0x01, 0x12, 0x0f, 0x0a,
+	0x0b, 0x4c, 0x4f, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x43, 0x10, 0x02, 0x2a, 0x7f,
+	0x0a, 0x19, 0x47, 0x43, 0x45, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61,
+	0x6c, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x12, 0x08, 0x0a, 0x04, 0x4e,
+	0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x56,
+	0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x56, 0x5f, 0x45, 0x53,
+	0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x54, 0x45, 0x4c, 0x5f, 0x54, 0x44, 0x58, 0x10,
+	0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x56, 0x5f, 0x53, 0x4e, 0x50,
+	0x10, 0x04, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x52, 0x4d, 0x5f, 0x43, 0x43, 0x41, 0x10, 0x05, 0x12,
+	0x0e, 0x0a, 0x0a, 0x52, 0x49, 0x53, 0x43, 0x56, 0x5f, 0x43, 0x4f, 0x56, 0x45, 0x10, 0x06, 0x2a,
+	0x70, 0x0a, 0x0e, 0x44, 0x72, 0x74, 0x6d, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67,
+	0x79, 0x12, 0x1f, 0x0a, 0x1b, 0x44, 0x52, 0x54, 0x4d, 0x5f, 0x54, 0x45, 0x43, 0x48, 0x4e, 0x4f,
+	0x4c, 0x4f, 0x47, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x44, 0x52, 0x54, 0x4d, 0x5f, 0x54, 0x45, 0x43, 0x48, 0x4e,
+	0x4f, 0x4c, 0x4f, 0x47, 0x59, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x4c, 0x5f, 0x54, 0x58, 0x54, 0x10,
+	0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x44, 0x52, 0x54, 0x4d, 0x5f, 0x54, 0x45, 0x43, 0x48, 0x4e, 0x4f,
+	0x4c, 0x4f, 0x47, 0x59, 0x5f, 0x41, 0x4d, 0x44, 0x5f, 0x53, 0x4b, 0x49, 0x4e, 0x49, 0x54, 0x10,
+	0x02, 0x2a, 0x96, 0x01, 0x0a, 0x14, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e,
+	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x4d, 0x53, 0x5f, 0x57, 0x49,
+	0x4e, 0x44, 0x4f, 0x57, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x44, 0x5f, 0x50, 0x43, 0x41, 0x5f, 0x32,
+	0x30, 0x31, 0x31, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x4d, 0x53, 0x5f, 0x54, 0x48, 0x49, 0x52,
+	0x44, 0x5f, 0x50, 0x41, 0x52, 0x54, 0x59, 0x5f, 0x55, 0x45, 0x46, 0x49, 0x5f, 0x43, 0x41, 0x5f,
+	0x32, 0x30, 0x31, 0x31, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x4d, 0x53, 0x5f, 0x54, 0x48, 0x49,
+	0x52, 0x44, 0x5f, 0x50, 0x41, 0x52, 0x54, 0x59, 0x5f, 0x4b, 0x45, 0x4b, 0x5f, 0x43, 0x41, 0x5f,
+	0x32, 0x30, 0x31, 0x31, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x47, 0x43, 0x45, 0x5f, 0x44, 0x45,
+	0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x50, 0x4b, 0x10, 0x04, 0x2a, 0x7b, 0x0a, 0x0b, 0x54, 0x72,
+	0x75, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x18, 0x54, 0x52, 0x55,
+	0x53, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x54, 0x52, 0x55, 0x53, 0x54,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x54, 0x52, 0x55, 0x53, 0x54, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x1a, 0x0a, 0x16, 0x54, 0x52, 0x55, 0x53, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x55, 0x4e, 0x54, 0x52, 0x55, 0x53, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x18, 0x0a,
+	0x14, 0x54, 0x52, 0x55, 0x53, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52, 0x45,
+	0x56, 0x4f, 0x4b, 0x45, 0x44, 0x10, 0x03, 0x2a, 0x8d, 0x03, 0x0a, 0x0d, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x1a, 0x53, 0x49, 0x47,
+	0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50,
+	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x49, 0x47,
+	0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x58, 0x35, 0x30, 0x39,
+	0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x02, 0x12, 0x17, 0x0a,
+	0x13, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x53, 0x48, 0x41, 0x31, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54,
+	0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x48, 0x41, 0x32, 0x32, 0x34, 0x10,
+	0x04, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x53, 0x48, 0x41, 0x33, 0x38, 0x34, 0x10, 0x05, 0x12, 0x19, 0x0a, 0x15,
+	0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53,
+	0x48, 0x41, 0x35, 0x31, 0x32, 0x10, 0x06, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x49, 0x47, 0x4e, 0x41,
+	0x54, 0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x53, 0x41, 0x32, 0x30, 0x34,
+	0x38, 0x10, 0x07, 0x12, 0x21, 0x0a, 0x1d, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x53, 0x41, 0x32, 0x30, 0x34, 0x38, 0x5f, 0x53, 0x48,
+	0x41, 0x32, 0x35, 0x36, 0x10, 0x08, 0x12, 0x1f, 0x0a, 0x1b, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54,
+	0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x53, 0x41, 0x32, 0x30, 0x34, 0x38,
+	0x5f, 0x53, 0x48, 0x41, 0x31, 0x10, 0x09, 0x12, 0x1e, 0x0a, 0x1a, 0x53, 0x49, 0x47, 0x4e, 0x41,
+	0x54, 0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x58, 0x35, 0x30, 0x39, 0x5f, 0x53,
+	0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x0a, 0x12, 0x1e, 0x0a, 0x1a, 0x53, 0x49, 0x47, 0x4e, 0x41,
+	0x54, 0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x58, 0x35, 0x30, 0x39, 0x5f, 0x53,
+	0x48, 0x41, 0x33, 0x38, 0x34, 0x10, 0x0b, 0x12, 0x1e, 0x0a, 0x1a, 0x53, 0x49, 0x47, 0x4e, 0x41,
+	0x54, 0x55, 0x52, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x58, 0x35, 0x30, 0x39, 0x5f, 0x53,
+	0x48, 0x41, 0x35, 0x31, 0x32, 0x10, 0x0c, 0x2a, 0x91, 0x01, 0x0a, 0x16, 0x45, 0x78, 0x69, 0x74,
+	0x42, 0x6f, 0x6f, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x29, 0x0a, 0x25, 0x45, 0x58, 0x49, 0x54, 0x5f, 0x42, 0x4f, 0x4f, 0x54, 0x5f,
+	0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x53, 0x5f, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x25, 0x0a,
+	0x21, 0x45, 0x58, 0x49, 0x54, 0x5f, 0x42, 0x4f, 0x4f, 0x54, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49,
+	0x43, 0x45, 0x53, 0x5f, 0x52, 0x45, 0x53, 0x55, 0x4c, 0x54, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45,
+	0x53, 0x53, 0x10, 0x01, 0x12, 0x25, 0x0a, 0x21, 0x45, 0x58, 0x49, 0x54, 0x5f, 0x42, 0x4f, 0x4f,
+	0x54, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x53, 0x5f, 0x52, 0x45, 0x53, 0x55, 0x4c,
+	0x54, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x10, 0x02, 0x2a, 0x74, 0x0a, 0x08, 0x48,
+	0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x12, 0x10, 0x0a, 0x0c, 0x48, 0x41, 0x53, 0x48, 0x5f,
+	0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x48, 0x41,
+	0x31, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x0b, 0x12,
+	0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41, 0x33, 0x38, 0x34, 0x10, 0x0c, 0x12, 0x0a, 0x0a, 0x06, 0x53,
+	0x48, 0x41, 0x35, 0x31, 0x32, 0x10, 0x0d, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x48, 0x41, 0x33, 0x5f,
+	0x32, 0x35, 0x36, 0x10, 0x27, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x48, 0x41, 0x33, 0x5f, 0x33, 0x38,
+	0x34, 0x10, 0x28, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x48, 0x41, 0x33, 0x5f, 0x35, 0x31, 0x32, 0x10,
+	0x29, 0x2a, 0xa8, 0x03, 0x0a, 0x0b, 0x46, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x1c, 0x0a, 0x18, 0x46, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x1a, 0x0a, 0x16, 0x46, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x57, 0x45, 0x41, 0x4b, 0x5f, 0x42, 0x41, 0x4e, 0x4b, 0x10, 0x01, 0x12, 0x20, 0x0a, 0x1c, 0x46,
+	0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45, 0x50, 0x45,
+	0x41, 0x54, 0x45, 0x44, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x53, 0x10, 0x02, 0x12, 0x29, 0x0a,
+	0x25, 0x46, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x45,
+	0x43, 0x55, 0x52, 0x45, 0x5f, 0x42, 0x4f, 0x4f, 0x54, 0x5f, 0x49, 0x4e, 0x43, 0x4f, 0x4e, 0x53,
+	0x49, 0x53, 0x54, 0x45, 0x4e, 0x54, 0x10, 0x03, 0x12, 0x22, 0x0a, 0x1e, 0x46, 0x49, 0x4e, 0x44,
+	0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4c, 0x45, 0x47, 0x41, 0x43, 0x59, 0x5f,
+	0x4f, 0x50, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x4f, 0x4d, 0x10, 0x04, 0x12, 0x20, 0x0a, 0x1c,
+	0x46, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x41, 0x44,
+	0x44, 0x49, 0x4e, 0x47, 0x5f, 0x47, 0x41, 0x52, 0x42, 0x41, 0x47, 0x45, 0x10, 0x05, 0x12, 0x21,
+	0x0a, 0x1d, 0x46, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44,
+	0x42, 0x58, 0x5f, 0x50, 0x41, 0x52, 0x53, 0x45, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x53, 0x10,
+	0x06, 0x12, 0x1f, 0x0a, 0x1b, 0x46, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x51, 0x55, 0x49, 0x52, 0x4b, 0x53, 0x5f, 0x41, 0x50, 0x50, 0x4c, 0x49, 0x45, 0x44,
+	0x10, 0x07, 0x12, 0x23, 0x0a, 0x1f, 0x46, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x49, 0x5a, 0x45, 0x5f, 0x41, 0x4e,
+	0x4f, 0x4d, 0x41, 0x4c, 0x59, 0x10, 0x08, 0x12, 0x26, 0x0a, 0x22, 0x46, 0x49, 0x4e, 0x44, 0x49,
+	0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x4f, 0x50, 0x54,
+	0x49, 0x4f, 0x4e, 0x53, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x09, 0x12,
+	0x1c, 0x0a, 0x18, 0x46, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x50, 0x43, 0x52, 0x53, 0x5f, 0x43, 0x41, 0x50, 0x50, 0x45, 0x44, 0x10, 0x0a, 0x12, 0x1d, 0x0a,
+	0x19, 0x46, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45,
+	0x44, 0x41, 0x43, 0x54, 0x45, 0x44, 0x5f, 0x4d, 0x52, 0x53, 0x10, 0x0b, 0x42, 0x2b, 0x5a, 0x29,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x67, 0x6f, 0x2d, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x6c, 0x6f, 0x67, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -1339,53 +5628,145 @@ func file_state_proto_rawDescGZIP() []byte {
 	return file_state_proto_rawDescData
 }
 
-var file_state_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_state_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_state_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_state_proto_msgTypes = make([]protoimpl.MessageInfo, 47)
 var file_state_proto_goTypes = []any{
 	(LogType)(0),                   // 0: state.LogType
 	(GCEConfidentialTechnology)(0), // 1: state.GCEConfidentialTechnology
-	(WellKnownCertificate)(0),      // 2: state.WellKnownCertificate
-	(HashAlgo)(0),                  // 3: state.HashAlgo
-	(*GCEInstanceInfo)(nil),        // 4: state.GCEInstanceInfo
-	(*PlatformState)(nil),          // 5: state.PlatformState
-	(*GrubFile)(nil),               // 6: state.GrubFile
-	(*GrubState)(nil),              // 7: state.GrubState
-	(*LinuxKernelState)(nil),       // 8: state.LinuxKernelState
-	(*Event)(nil),                  // 9: state.Event
-	(*Certificate)(nil),            // 10: state.Certificate
-	(*Database)(nil),               // 11: state.Database
-	(*SecureBootState)(nil),        // 12: state.SecureBootState
-	(*EfiApp)(nil),                 // 13: state.EfiApp
-	(*EfiState)(nil),               // 14: state.EfiState
-	(*FirmwareLogState)(nil),       // 15: state.FirmwareLogState
+	(DrtmTechnology)(0),            // 2: state.DrtmTechnology
+	(WellKnownCertificate)(0),      // 3: state.WellKnownCertificate
+	(TrustStatus)(0),               // 4: state.TrustStatus
+	(SignatureType)(0),             // 5: state.SignatureType
+	(ExitBootServicesResult)(0),    // 6: state.ExitBootServicesResult
+	(HashAlgo)(0),                  // 7: state.HashAlgo
+	(FindingType)(0),               // 8: state.FindingType
+	(*GCEInstanceInfo)(nil),        // 9: state.GCEInstanceInfo
+	(*PlatformState)(nil),          // 10: state.PlatformState
+	(*BankDigest)(nil),             // 11: state.BankDigest
+	(*BankDigests)(nil),            // 12: state.BankDigests
+	(*GrubFile)(nil),               // 13: state.GrubFile
+	(*GrubState)(nil),              // 14: state.GrubState
+	(*LinuxKernelState)(nil),       // 15: state.LinuxKernelState
+	(*UkiSection)(nil),             // 16: state.UkiSection
+	(*UkiState)(nil),               // 17: state.UkiState
+	(*WindowsSipaState)(nil),       // 18: state.WindowsSipaState
+	(*WindowsLoadedModule)(nil),    // 19: state.WindowsLoadedModule
+	(*KexecState)(nil),             // 20: state.KexecState
+	(*Event)(nil),                  // 21: state.Event
+	(*DrtmState)(nil),              // 22: state.DrtmState
+	(*Certificate)(nil),            // 23: state.Certificate
+	(*CertificateMetadata)(nil),    // 24: state.CertificateMetadata
+	(*Database)(nil),               // 25: state.Database
+	(*SignatureEntry)(nil),         // 26: state.SignatureEntry
+	(*SecureBootState)(nil),        // 27: state.SecureBootState
+	(*MokState)(nil),               // 28: state.MokState
+	(*MokMeasurement)(nil),         // 29: state.MokMeasurement
+	(*SignatureListError)(nil),     // 30: state.SignatureListError
+	(*AuthorityUsage)(nil),         // 31: state.AuthorityUsage
+	(*AuthorityUse)(nil),           // 32: state.AuthorityUse
+	(*EfiApp)(nil),                 // 33: state.EfiApp
+	(*EfiState)(nil),               // 34: state.EfiState
+	(*EfiDriver)(nil),              // 35: state.EfiDriver
+	(*EventCount)(nil),             // 36: state.EventCount
+	(*EventLogStats)(nil),          // 37: state.EventLogStats
+	(*ConformanceCheck)(nil),       // 38: state.ConformanceCheck
+	(*ConformanceReport)(nil),      // 39: state.ConformanceReport
+	(*Finding)(nil),                // 40: state.Finding
+	(*FirmwareLogState)(nil),       // 41: state.FirmwareLogState
+	(*GptState)(nil),               // 42: state.GptState
+	(*GptPartition)(nil),           // 43: state.GptPartition
+	(*BootConfigState)(nil),        // 44: state.BootConfigState
+	(*BootOption)(nil),             // 45: state.BootOption
+	(*ConfigMeasurement)(nil),      // 46: state.ConfigMeasurement
+	(*FirmwareLogSummary)(nil),     // 47: state.FirmwareLogSummary
+	(*DigestResolution)(nil),       // 48: state.DigestResolution
+	(*Provenance)(nil),             // 49: state.Provenance
+	(*RegisterBank)(nil),           // 50: state.RegisterBank
+	(*TpmQuote)(nil),               // 51: state.TpmQuote
+	(*AttestationBundle)(nil),      // 52: state.AttestationBundle
+	(*ArchivedBoot)(nil),           // 53: state.ArchivedBoot
+	(*LogArchive)(nil),             // 54: state.LogArchive
+	nil,                            // 55: state.RegisterBank.ValuesEntry
 }
 var file_state_proto_depIdxs = []int32{
 	1,  // 0: state.PlatformState.technology:type_name -> state.GCEConfidentialTechnology
-	4,  // 1: state.PlatformState.instance_info:type_name -> state.GCEInstanceInfo
-	6,  // 2: state.GrubState.files:type_name -> state.GrubFile
-	2,  // 3: state.Certificate.well_known:type_name -> state.WellKnownCertificate
-	10, // 4: state.Database.certs:type_name -> state.Certificate
-	11, // 5: state.SecureBootState.db:type_name -> state.Database
-	11, // 6: state.SecureBootState.dbx:type_name -> state.Database
-	11, // 7: state.SecureBootState.authority:type_name -> state.Database
-	11, // 8: state.SecureBootState.pk:type_name -> state.Database
-	11, // 9: state.SecureBootState.kek:type_name -> state.Database
-	13, // 10: state.EfiState.apps:type_name -> state.EfiApp
-	13, // 11: state.EfiState.boot_services_drivers:type_name -> state.EfiApp
-	13, // 12: state.EfiState.runtime_services_drivers:type_name -> state.EfiApp
-	5,  // 13: state.FirmwareLogState.platform:type_name -> state.PlatformState
-	12, // 14: state.FirmwareLogState.secure_boot:type_name -> state.SecureBootState
-	9,  // 15: state.FirmwareLogState.raw_events:type_name -> state.Event
-	3,  // 16: state.FirmwareLogState.hash:type_name -> state.HashAlgo
-	7,  // 17: state.FirmwareLogState.grub:type_name -> state.GrubState
-	8,  // 18: state.FirmwareLogState.linux_kernel:type_name -> state.LinuxKernelState
-	14, // 19: state.FirmwareLogState.efi:type_name -> state.EfiState
-	0,  // 20: state.FirmwareLogState.log_type:type_name -> state.LogType
-	21, // [21:21] is the sub-list for method output_type
-	21, // [21:21] is the sub-list for method input_type
-	21, // [21:21] is the sub-list for extension type_name
-	21, // [21:21] is the sub-list for extension extendee
-	0,  // [0:21] is the sub-list for field type_name
+	9,  // 1: state.PlatformState.instance_info:type_name -> state.GCEInstanceInfo
+	7,  // 2: state.BankDigest.hash:type_name -> state.HashAlgo
+	11, // 3: state.BankDigests.digests:type_name -> state.BankDigest
+	7,  // 4: state.GrubFile.hash:type_name -> state.HashAlgo
+	11, // 5: state.GrubFile.bank_digests:type_name -> state.BankDigest
+	13, // 6: state.GrubState.files:type_name -> state.GrubFile
+	12, // 7: state.GrubState.command_digests:type_name -> state.BankDigests
+	16, // 8: state.UkiState.sections:type_name -> state.UkiSection
+	19, // 9: state.WindowsSipaState.loaded_modules:type_name -> state.WindowsLoadedModule
+	14, // 10: state.KexecState.grub:type_name -> state.GrubState
+	15, // 11: state.KexecState.linux_kernel:type_name -> state.LinuxKernelState
+	21, // 12: state.DrtmState.mle_events:type_name -> state.Event
+	21, // 13: state.DrtmState.events:type_name -> state.Event
+	2,  // 14: state.DrtmState.technology:type_name -> state.DrtmTechnology
+	21, // 15: state.DrtmState.secure_launch_events:type_name -> state.Event
+	3,  // 16: state.Certificate.well_known:type_name -> state.WellKnownCertificate
+	24, // 17: state.Certificate.metadata:type_name -> state.CertificateMetadata
+	4,  // 18: state.Certificate.trust:type_name -> state.TrustStatus
+	23, // 19: state.Database.certs:type_name -> state.Certificate
+	26, // 20: state.Database.entries:type_name -> state.SignatureEntry
+	5,  // 21: state.SignatureEntry.type:type_name -> state.SignatureType
+	25, // 22: state.SecureBootState.db:type_name -> state.Database
+	25, // 23: state.SecureBootState.dbx:type_name -> state.Database
+	25, // 24: state.SecureBootState.authority:type_name -> state.Database
+	25, // 25: state.SecureBootState.pk:type_name -> state.Database
+	25, // 26: state.SecureBootState.kek:type_name -> state.Database
+	31, // 27: state.SecureBootState.authority_usages:type_name -> state.AuthorityUsage
+	30, // 28: state.SecureBootState.dbx_errors:type_name -> state.SignatureListError
+	28, // 29: state.SecureBootState.mok:type_name -> state.MokState
+	29, // 30: state.MokState.measurements:type_name -> state.MokMeasurement
+	32, // 31: state.AuthorityUsage.uses:type_name -> state.AuthorityUse
+	33, // 32: state.EfiState.apps:type_name -> state.EfiApp
+	33, // 33: state.EfiState.boot_services_drivers:type_name -> state.EfiApp
+	33, // 34: state.EfiState.runtime_services_drivers:type_name -> state.EfiApp
+	21, // 35: state.EfiState.untrusted_post_ebs_events:type_name -> state.Event
+	6,  // 36: state.EfiState.untrusted_exit_boot_services_result:type_name -> state.ExitBootServicesResult
+	35, // 37: state.EfiState.drivers:type_name -> state.EfiDriver
+	36, // 38: state.EventLogStats.counts:type_name -> state.EventCount
+	38, // 39: state.ConformanceReport.checks:type_name -> state.ConformanceCheck
+	8,  // 40: state.Finding.type:type_name -> state.FindingType
+	10, // 41: state.FirmwareLogState.platform:type_name -> state.PlatformState
+	27, // 42: state.FirmwareLogState.secure_boot:type_name -> state.SecureBootState
+	21, // 43: state.FirmwareLogState.raw_events:type_name -> state.Event
+	7,  // 44: state.FirmwareLogState.hash:type_name -> state.HashAlgo
+	14, // 45: state.FirmwareLogState.grub:type_name -> state.GrubState
+	15, // 46: state.FirmwareLogState.linux_kernel:type_name -> state.LinuxKernelState
+	34, // 47: state.FirmwareLogState.efi:type_name -> state.EfiState
+	0,  // 48: state.FirmwareLogState.log_type:type_name -> state.LogType
+	20, // 49: state.FirmwareLogState.kexec:type_name -> state.KexecState
+	40, // 50: state.FirmwareLogState.findings:type_name -> state.Finding
+	37, // 51: state.FirmwareLogState.stats:type_name -> state.EventLogStats
+	22, // 52: state.FirmwareLogState.drtm:type_name -> state.DrtmState
+	49, // 53: state.FirmwareLogState.provenance:type_name -> state.Provenance
+	48, // 54: state.FirmwareLogState.digest_resolutions:type_name -> state.DigestResolution
+	18, // 55: state.FirmwareLogState.windows_sipa:type_name -> state.WindowsSipaState
+	17, // 56: state.FirmwareLogState.uki:type_name -> state.UkiState
+	44, // 57: state.FirmwareLogState.boot_config:type_name -> state.BootConfigState
+	42, // 58: state.FirmwareLogState.gpt:type_name -> state.GptState
+	43, // 59: state.GptState.partitions:type_name -> state.GptPartition
+	45, // 60: state.BootConfigState.boot_options:type_name -> state.BootOption
+	46, // 61: state.BootConfigState.platform_configs:type_name -> state.ConfigMeasurement
+	0,  // 62: state.FirmwareLogSummary.log_type:type_name -> state.LogType
+	7,  // 63: state.FirmwareLogSummary.hash:type_name -> state.HashAlgo
+	1,  // 64: state.FirmwareLogSummary.technology:type_name -> state.GCEConfidentialTechnology
+	8,  // 65: state.FirmwareLogSummary.finding_types:type_name -> state.FindingType
+	7,  // 66: state.RegisterBank.hash:type_name -> state.HashAlgo
+	55, // 67: state.RegisterBank.values:type_name -> state.RegisterBank.ValuesEntry
+	0,  // 68: state.AttestationBundle.log_type:type_name -> state.LogType
+	50, // 69: state.AttestationBundle.banks:type_name -> state.RegisterBank
+	51, // 70: state.AttestationBundle.quotes:type_name -> state.TpmQuote
+	52, // 71: state.ArchivedBoot.bundle:type_name -> state.AttestationBundle
+	53, // 72: state.LogArchive.boots:type_name -> state.ArchivedBoot
+	73, // [73:73] is the sub-list for method output_type
+	73, // [73:73] is the sub-list for method input_type
+	73, // [73:73] is the sub-list for extension type_name
+	73, // [73:73] is the sub-list for extension extendee
+	0,  // [0:73] is the sub-list for field type_name
 }
 
 func init() { file_state_proto_init() }
@@ -1406,8 +5787,140 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*PlatformState); i {
+		file_state_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*PlatformState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*BankDigest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*BankDigests); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GrubFile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*GrubState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*LinuxKernelState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*UkiSection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*UkiState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*WindowsSipaState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*WindowsLoadedModule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*KexecState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*Event); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1418,8 +5931,8 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*GrubFile); i {
+		file_state_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*DrtmState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1430,8 +5943,8 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*GrubState); i {
+		file_state_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*Certificate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1442,8 +5955,8 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*LinuxKernelState); i {
+		file_state_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*CertificateMetadata); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1454,8 +5967,8 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[5].Exporter = func(v any, i int) any {
-			switch v := v.(*Event); i {
+		file_state_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*Database); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1466,8 +5979,8 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*Certificate); i {
+		file_state_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*SignatureEntry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1478,8 +5991,8 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[7].Exporter = func(v any, i int) any {
-			switch v := v.(*Database); i {
+		file_state_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*SecureBootState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1490,8 +6003,8 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[8].Exporter = func(v any, i int) any {
-			switch v := v.(*SecureBootState); i {
+		file_state_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*MokState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1502,7 +6015,55 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[9].Exporter = func(v any, i int) any {
+		file_state_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*MokMeasurement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*SignatureListError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*AuthorityUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*AuthorityUse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[24].Exporter = func(v any, i int) any {
 			switch v := v.(*EfiApp); i {
 			case 0:
 				return &v.state
@@ -1514,7 +6075,7 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[10].Exporter = func(v any, i int) any {
+		file_state_proto_msgTypes[25].Exporter = func(v any, i int) any {
 			switch v := v.(*EfiState); i {
 			case 0:
 				return &v.state
@@ -1526,7 +6087,79 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
-		file_state_proto_msgTypes[11].Exporter = func(v any, i int) any {
+		file_state_proto_msgTypes[26].Exporter = func(v any, i int) any {
+			switch v := v.(*EfiDriver); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[27].Exporter = func(v any, i int) any {
+			switch v := v.(*EventCount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[28].Exporter = func(v any, i int) any {
+			switch v := v.(*EventLogStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[29].Exporter = func(v any, i int) any {
+			switch v := v.(*ConformanceCheck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[30].Exporter = func(v any, i int) any {
+			switch v := v.(*ConformanceReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[31].Exporter = func(v any, i int) any {
+			switch v := v.(*Finding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[32].Exporter = func(v any, i int) any {
 			switch v := v.(*FirmwareLogState); i {
 			case 0:
 				return &v.state
@@ -1538,22 +6171,183 @@ func file_state_proto_init() {
 				return nil
 			}
 		}
+		file_state_proto_msgTypes[33].Exporter = func(v any, i int) any {
+			switch v := v.(*GptState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[34].Exporter = func(v any, i int) any {
+			switch v := v.(*GptPartition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[35].Exporter = func(v any, i int) any {
+			switch v := v.(*BootConfigState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[36].Exporter = func(v any, i int) any {
+			switch v := v.(*BootOption); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[37].Exporter = func(v any, i int) any {
+			switch v := v.(*ConfigMeasurement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[38].Exporter = func(v any, i int) any {
+			switch v := v.(*FirmwareLogSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[39].Exporter = func(v any, i int) any {
+			switch v := v.(*DigestResolution); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[40].Exporter = func(v any, i int) any {
+			switch v := v.(*Provenance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[41].Exporter = func(v any, i int) any {
+			switch v := v.(*RegisterBank); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[42].Exporter = func(v any, i int) any {
+			switch v := v.(*TpmQuote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[43].Exporter = func(v any, i int) any {
+			switch v := v.(*AttestationBundle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[44].Exporter = func(v any, i int) any {
+			switch v := v.(*ArchivedBoot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_state_proto_msgTypes[45].Exporter = func(v any, i int) any {
+			switch v := v.(*LogArchive); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_state_proto_msgTypes[1].OneofWrappers = []any{
 		(*PlatformState_ScrtmVersionId)(nil),
 		(*PlatformState_GceVersion)(nil),
 	}
-	file_state_proto_msgTypes[6].OneofWrappers = []any{
+	file_state_proto_msgTypes[14].OneofWrappers = []any{
 		(*Certificate_Der)(nil),
 		(*Certificate_WellKnown)(nil),
 	}
+	file_state_proto_msgTypes[17].OneofWrappers = []any{
+		(*SignatureEntry_CertIndex)(nil),
+		(*SignatureEntry_HashIndex)(nil),
+		(*SignatureEntry_Data)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_state_proto_rawDesc,
-			NumEnums:      4,
-			NumMessages:   12,
+			NumEnums:      9,
+			NumMessages:   47,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
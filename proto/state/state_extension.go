@@ -18,16 +18,65 @@ package state
 
 import (
 	"crypto"
+	"fmt"
 
 	"github.com/google/go-tpm/legacy/tpm2"
 )
 
-// CryptoHash converts the TCG registry hash identifier to a crypto.Hash.
+// CryptoHash converts the TCG registry hash identifier to a crypto.Hash. It
+// returns an error if the hash implementation is not linked into the binary,
+// e.g., for the SHA3 family.
 func (ha HashAlgo) CryptoHash() (crypto.Hash, error) {
 	tcgHash := tpm2.Algorithm(uint16(ha))
 	cryptoHash, err := tcgHash.Hash()
 	if err != nil {
 		return crypto.Hash(0), err
 	}
+	if !cryptoHash.Available() {
+		return crypto.Hash(0), fmt.Errorf("hash algorithm %v is not available", cryptoHash)
+	}
 	return cryptoHash, nil
 }
+
+// HashAlgoFromCryptoHash converts a crypto.Hash to the TCG registry hash
+// identifier, including the SHA3 family.
+func HashAlgoFromCryptoHash(hash crypto.Hash) (HashAlgo, error) {
+	tcgHash, err := tpm2.HashToAlgorithm(hash)
+	if err != nil {
+		return HashAlgo_HASH_INVALID, err
+	}
+	if _, ok := HashAlgo_name[int32(tcgHash)]; !ok {
+		return HashAlgo_HASH_INVALID, fmt.Errorf("unsupported hash algorithm %v", hash)
+	}
+	return HashAlgo(tcgHash), nil
+}
+
+// Strength ranks hash algorithms, with stronger algorithms ranked higher.
+// SHA3 ranks above SHA2 of the same digest size. Unknown algorithms rank 0.
+func (ha HashAlgo) Strength() int {
+	switch ha {
+	case HashAlgo_SHA1:
+		return 1
+	case HashAlgo_SHA256:
+		return 2
+	case HashAlgo_SHA3_256:
+		return 3
+	case HashAlgo_SHA384:
+		return 4
+	case HashAlgo_SHA3_384:
+		return 5
+	case HashAlgo_SHA512:
+		return 6
+	case HashAlgo_SHA3_512:
+		return 7
+	default:
+		return 0
+	}
+}
+
+// Weak reports whether the hash algorithm is no longer collision resistant,
+// so a register bank using it offers weaker guarantees than the log format
+// suggests.
+func (ha HashAlgo) Weak() bool {
+	return ha == HashAlgo_SHA1
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package register
+
+import (
+	"crypto"
+	// Link in the SHA-1 and SHA-2 implementations, so replay works without
+	// the caller importing them.
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"fmt"
+)
+
+// TCG Algorithm Registry IDs of the supported hash algorithms.
+const (
+	algSHA1     = 0x0004
+	algSHA256   = 0x000B
+	algSHA384   = 0x000C
+	algSHA512   = 0x000D
+	algSHA3_256 = 0x0027
+	algSHA3_384 = 0x0028
+	algSHA3_512 = 0x0029
+)
+
+var tpmAlgHashes = []struct {
+	id   uint16
+	hash crypto.Hash
+}{
+	{algSHA1, crypto.SHA1},
+	{algSHA256, crypto.SHA256},
+	{algSHA384, crypto.SHA384},
+	{algSHA512, crypto.SHA512},
+	{algSHA3_256, crypto.SHA3_256},
+	{algSHA3_384, crypto.SHA3_384},
+	{algSHA3_512, crypto.SHA3_512},
+}
+
+// TPMAlgHash returns the crypto.Hash for a TCG Algorithm Registry hash
+// algorithm ID (TPM_ALG_ID). It returns an error if the hash implementation
+// is not linked into the binary.
+func TPMAlgHash(id uint16) (crypto.Hash, error) {
+	for _, alg := range tpmAlgHashes {
+		if alg.id == id {
+			if !alg.hash.Available() {
+				return crypto.Hash(0), fmt.Errorf("hash algorithm %v is not available", alg.hash)
+			}
+			return alg.hash, nil
+		}
+	}
+	return crypto.Hash(0), fmt.Errorf("unsupported hash algorithm ID: %#x", id)
+}
+
+// HashTPMAlg returns the TCG Algorithm Registry hash algorithm ID
+// (TPM_ALG_ID) for a crypto.Hash.
+func HashTPMAlg(hash crypto.Hash) (uint16, error) {
+	for _, alg := range tpmAlgHashes {
+		if alg.hash == hash {
+			return alg.id, nil
+		}
+	}
+	return 0, fmt.Errorf("hash algorithm %v has no TPM algorithm ID", hash)
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package register
+
+import (
+	"crypto"
+	"testing"
+)
+
+func TestTPMAlgHash(t *testing.T) {
+	for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+		id, err := HashTPMAlg(hash)
+		if err != nil {
+			t.Fatalf("HashTPMAlg(%v): %v", hash, err)
+		}
+		got, err := TPMAlgHash(id)
+		if err != nil {
+			t.Fatalf("TPMAlgHash(%#x): %v", id, err)
+		}
+		if got != hash {
+			t.Errorf("TPMAlgHash(HashTPMAlg(%v)) = %v", hash, got)
+		}
+	}
+	if id, _ := HashTPMAlg(crypto.SHA256); id != uint16(HashSHA256) {
+		t.Errorf("HashTPMAlg(SHA256) = %#x, want %#x", id, uint16(HashSHA256))
+	}
+	if _, err := HashTPMAlg(crypto.MD5); err == nil {
+		t.Errorf("HashTPMAlg(MD5): got nil, want error")
+	}
+	if _, err := TPMAlgHash(0x0012); err == nil {
+		t.Errorf("TPMAlgHash(SM3_256): got nil, want error")
+	}
+}
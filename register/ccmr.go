@@ -0,0 +1,96 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package register
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+)
+
+// numCCMRs is the number of runtime CC measurement registers, i.e., CC MR
+// indexes 1 to 4.
+const numCCMRs = 4
+
+// CCMRBank is a bank of Confidential Computing measurement registers that all
+// correspond to the same hash algorithm, for technologies without a dedicated
+// register type, e.g., AMD SEV-SNP.
+type CCMRBank struct {
+	Hash  crypto.Hash
+	CCMRs []CCMR
+}
+
+// CryptoHash returns the crypto.Hash algorithm related to the CC MR bank.
+func (b CCMRBank) CryptoHash() (crypto.Hash, error) {
+	if len(b.CCMRs) == 0 {
+		return crypto.Hash(0), errors.New("received an empty CC MR bank")
+	}
+	if b.Hash == crypto.Hash(0) || !b.Hash.Available() {
+		return crypto.Hash(0), fmt.Errorf("received a CC MR bank of unavailable hash algorithm %v", b.Hash)
+	}
+	var invalidCCMRs []int
+	for _, mr := range b.CCMRs {
+		if mr.Index < 1 || mr.Index > numCCMRs {
+			return crypto.Hash(0), fmt.Errorf("invalid CC MR index %d", mr.Index)
+		}
+		if len(mr.Digest) != b.Hash.Size() {
+			invalidCCMRs = append(invalidCCMRs, mr.Index)
+		}
+	}
+	if len(invalidCCMRs) != 0 {
+		return crypto.Hash(0), fmt.Errorf("found an invalid digest length in CC MRs %v for bank of algorithm %v", invalidCCMRs, b.Hash)
+	}
+	return b.Hash, nil
+}
+
+// MRs returns a slice of MR from the CC MR implementation.
+func (b CCMRBank) MRs() []MR {
+	mrs := make([]MR, len(b.CCMRs))
+	for i, v := range b.CCMRs {
+		mrs[i] = ccmr{v, b.Hash}
+	}
+	return mrs
+}
+
+// CCMR encapsulates the value of a Confidential Computing measurement
+// register at a point in time.
+type CCMR struct {
+	// The CC MR index, as used in Confidential Computing event logs. CC MR
+	// index 0 is the static launch measurement, so the first runtime
+	// measurement register uses 1.
+	Index  int
+	Digest []byte
+}
+
+// ccmr is a CCMR with the hash algorithm of its bank.
+type ccmr struct {
+	CCMR
+	hash crypto.Hash
+}
+
+// Idx gives the CC Measurement Register index.
+func (r ccmr) Idx() int {
+	return r.Index
+}
+
+// Dgst gives the CC MR digest.
+func (r ccmr) Dgst() []byte {
+	return r.Digest
+}
+
+// DgstAlg gives the CC MR digest algorithm as a crypto.Hash.
+func (r ccmr) DgstAlg() crypto.Hash {
+	return r.hash
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package register
+
+import (
+	"crypto"
+	"testing"
+)
+
+func TestCCMRBank(t *testing.T) {
+	bank := CCMRBank{Hash: crypto.SHA384, CCMRs: []CCMR{
+		{Index: 1, Digest: make([]byte, 48)},
+		{Index: 3, Digest: make([]byte, 48)},
+	}}
+	if hash, err := bank.CryptoHash(); err != nil || hash != crypto.SHA384 {
+		t.Errorf("CryptoHash() = %v, %v, want SHA384", hash, err)
+	}
+	var indexes []int
+	for _, mr := range bank.MRs() {
+		indexes = append(indexes, mr.Idx())
+		if mr.DgstAlg() != crypto.SHA384 {
+			t.Errorf("MRs() have digest algorithm %v, want SHA384", mr.DgstAlg())
+		}
+	}
+	if len(indexes) != 2 || indexes[0] != 1 || indexes[1] != 3 {
+		t.Errorf("MRs() have CC MR indexes %v, want [1 3]", indexes)
+	}
+
+	for _, tc := range []struct {
+		name string
+		bank CCMRBank
+	}{
+		{"empty", CCMRBank{Hash: crypto.SHA384}},
+		{"no hash", CCMRBank{CCMRs: []CCMR{{Index: 1}}}},
+		{"mixed algorithms", CCMRBank{Hash: crypto.SHA384, CCMRs: []CCMR{{Index: 1, Digest: make([]byte, 48)}, {Index: 2, Digest: make([]byte, 32)}}}},
+		{"launch measurement", CCMRBank{Hash: crypto.SHA384, CCMRs: []CCMR{{Index: 0, Digest: make([]byte, 48)}}}},
+		{"index out of range", CCMRBank{Hash: crypto.SHA384, CCMRs: []CCMR{{Index: 5, Digest: make([]byte, 48)}}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.bank.CryptoHash(); err == nil {
+				t.Errorf("CryptoHash(): got nil, want error")
+			}
+		})
+	}
+}
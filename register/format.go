@@ -0,0 +1,187 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal
+
+package register
+
+import (
+	"crypto"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+// pcrReadAlgs maps the bank names used by tpm2-tools to hash algorithms.
+var pcrReadAlgs = map[string]pb.HashAlgo{
+	"sha1":     pb.HashAlgo_SHA1,
+	"sha256":   pb.HashAlgo_SHA256,
+	"sha384":   pb.HashAlgo_SHA384,
+	"sha512":   pb.HashAlgo_SHA512,
+	"sha3_256": pb.HashAlgo_SHA3_256,
+	"sha3_384": pb.HashAlgo_SHA3_384,
+	"sha3_512": pb.HashAlgo_SHA3_512,
+}
+
+var (
+	pcrReadBankLine = regexp.MustCompile(`^\s*([a-z0-9_]+)\s*:\s*$`)
+	pcrReadPCRLine  = regexp.MustCompile(`^\s*(\d+)\s*:\s*(?:0x)?([0-9A-Fa-f]+)\s*$`)
+	rtmrJSONKey     = regexp.MustCompile(`^rtmr[_\[]?([0-3])\]?$`)
+)
+
+// ParsePCRRead parses the output of tpm2_pcrread into PCR banks, in the order
+// the banks appear. Lines that are not part of a known bank are ignored.
+func ParsePCRRead(out string) ([]PCRBank, error) {
+	var banks []PCRBank
+	var current *PCRBank
+	var hash crypto.Hash
+	for i, line := range strings.Split(out, "\n") {
+		if m := pcrReadBankLine.FindStringSubmatch(line); m != nil {
+			alg, ok := pcrReadAlgs[m[1]]
+			if !ok {
+				current = nil
+				continue
+			}
+			var err error
+			if hash, err = alg.CryptoHash(); err != nil {
+				return nil, err
+			}
+			banks = append(banks, PCRBank{TCGHashAlgo: alg})
+			current = &banks[len(banks)-1]
+			continue
+		}
+		m := pcrReadPCRLine.FindStringSubmatch(line)
+		if m == nil || current == nil {
+			continue
+		}
+		idx, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid PCR index: %v", i+1, err)
+		}
+		digest, err := hex.DecodeString(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid PCR %d digest: %v", i+1, idx, err)
+		}
+		if len(digest) != hash.Size() {
+			return nil, fmt.Errorf("line %d: PCR %d in bank %v has %d-byte digest, expected %d", i+1, idx, current.TCGHashAlgo, len(digest), hash.Size())
+		}
+		current.PCRs = append(current.PCRs, PCR{Index: idx, Digest: digest, DigestAlg: hash})
+	}
+	if len(banks) == 0 {
+		return nil, fmt.Errorf("no PCR banks found")
+	}
+	return banks, nil
+}
+
+// FormatPCRRead formats PCR banks the way tpm2_pcrread prints them.
+func FormatPCRRead(banks ...PCRBank) string {
+	var sb strings.Builder
+	for _, bank := range banks {
+		fmt.Fprintf(&sb, "  %s:\n", strings.ToLower(bank.TCGHashAlgo.String()))
+		pcrs := append([]PCR(nil), bank.PCRs...)
+		sort.Slice(pcrs, func(i, j int) bool { return pcrs[i].Index < pcrs[j].Index })
+		for _, pcr := range pcrs {
+			fmt.Fprintf(&sb, "    %-2d: 0x%X\n", pcr.Index, pcr.Digest)
+		}
+	}
+	return sb.String()
+}
+
+// ParseRTMRJSON parses hex-encoded RTMRs from a JSON object, such as one
+// extracted from a TDX quote. The RTMRs are given either as an "rtmrs" array
+// or as individual keys like "rtmr0", "rtmr_1", or "RTMR[2]". Other keys are
+// ignored.
+func ParseRTMRJSON(data []byte) (RTMRBank, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return RTMRBank{}, fmt.Errorf("failed to parse RTMR JSON: %v", err)
+	}
+	// Visit the keys in order, so errors do not depend on map iteration
+	// order.
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make(map[int]string)
+	for _, key := range keys {
+		raw := obj[key]
+		key = strings.ToLower(key)
+		if key == "rtmrs" {
+			var list []string
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return RTMRBank{}, fmt.Errorf("invalid %q: %v", key, err)
+			}
+			if len(list) > numRTMRs {
+				return RTMRBank{}, fmt.Errorf("got %d RTMRs, expected at most %d", len(list), numRTMRs)
+			}
+			for idx, value := range list {
+				if _, ok := values[idx]; ok {
+					return RTMRBank{}, fmt.Errorf("RTMR[%d] given more than once", idx)
+				}
+				values[idx] = value
+			}
+			continue
+		}
+		m := rtmrJSONKey.FindStringSubmatch(key)
+		if m == nil {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return RTMRBank{}, fmt.Errorf("invalid %q: %v", key, err)
+		}
+		idx, _ := strconv.Atoi(m[1])
+		if _, ok := values[idx]; ok {
+			return RTMRBank{}, fmt.Errorf("RTMR[%d] given more than once", idx)
+		}
+		values[idx] = value
+	}
+	if len(values) == 0 {
+		return RTMRBank{}, fmt.Errorf("no RTMRs found")
+	}
+
+	var bank RTMRBank
+	for idx := 0; idx < numRTMRs; idx++ {
+		value, ok := values[idx]
+		if !ok {
+			continue
+		}
+		digest, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(value), "0x"))
+		if err != nil {
+			return RTMRBank{}, fmt.Errorf("invalid RTMR[%d] digest: %v", idx, err)
+		}
+		if len(digest) != crypto.SHA384.Size() {
+			return RTMRBank{}, fmt.Errorf("RTMR[%d] has %d-byte digest, expected %d", idx, len(digest), crypto.SHA384.Size())
+		}
+		bank.RTMRs = append(bank.RTMRs, RTMR{Index: idx, Digest: digest})
+	}
+	return bank, nil
+}
+
+// FormatRTMRJSON formats an RTMR bank as a JSON object with hex-encoded
+// "rtmr0" to "rtmr3" keys, which ParseRTMRJSON accepts.
+func FormatRTMRJSON(bank RTMRBank) ([]byte, error) {
+	obj := make(map[string]string)
+	for _, rtmr := range bank.RTMRs {
+		obj[fmt.Sprintf("rtmr%d", rtmr.Index)] = hex.EncodeToString(rtmr.Digest)
+	}
+	return json.MarshalIndent(obj, "", "  ")
+}
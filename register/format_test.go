@@ -0,0 +1,104 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal
+
+package register
+
+import (
+	"bytes"
+	"crypto"
+	"strings"
+	"testing"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+const pcrReadOutput = `  sha1:
+    0 : 0x3DCAA8B8C8A3E1E1B0A9B56B7B8F1A1B2C3D4E5F
+    10: 0x0000000000000000000000000000000000000000
+  sha256:
+    0 : 0xA0B1C2D3E4F5061728394A5B6C7D8E9FA0B1C2D3E4F5061728394A5B6C7D8E9F
+  sm3_256:
+    0 : 0x0000000000000000000000000000000000000000000000000000000000000000
+`
+
+func TestPCRReadRoundTrip(t *testing.T) {
+	banks, err := ParsePCRRead(pcrReadOutput)
+	if err != nil {
+		t.Fatalf("ParsePCRRead(): %v", err)
+	}
+	if len(banks) != 2 || banks[0].TCGHashAlgo != pb.HashAlgo_SHA1 || banks[1].TCGHashAlgo != pb.HashAlgo_SHA256 {
+		t.Fatalf("ParsePCRRead(): got banks %v, want SHA1 and SHA256", banks)
+	}
+	if len(banks[0].PCRs) != 2 || banks[0].PCRs[1].Index != 10 {
+		t.Errorf("ParsePCRRead(): got SHA1 PCRs %v, want PCRs 0 and 10", banks[0].PCRs)
+	}
+	if _, err := banks[1].CryptoHash(); err != nil {
+		t.Errorf("CryptoHash(): %v", err)
+	}
+
+	want := strings.Replace(pcrReadOutput, "  sm3_256:\n    0 : 0x0000000000000000000000000000000000000000000000000000000000000000\n", "", 1)
+	if got := FormatPCRRead(banks...); got != want {
+		t.Errorf("FormatPCRRead() = %q, want %q", got, want)
+	}
+
+	if _, err := ParsePCRRead("  sha256:\n    0 : 0x00\n"); err == nil {
+		t.Errorf("ParsePCRRead(short digest): got nil, want error")
+	}
+	if _, err := ParsePCRRead("no banks here"); err == nil {
+		t.Errorf("ParsePCRRead(no banks): got nil, want error")
+	}
+}
+
+func TestRTMRJSONRoundTrip(t *testing.T) {
+	rtmr1 := bytes.Repeat([]byte{0x11}, crypto.SHA384.Size())
+	rtmr2 := bytes.Repeat([]byte{0x22}, crypto.SHA384.Size())
+	bank := RTMRBank{RTMRs: []RTMR{{Index: 1, Digest: rtmr1}, {Index: 2, Digest: rtmr2}}}
+	out, err := FormatRTMRJSON(bank)
+	if err != nil {
+		t.Fatalf("FormatRTMRJSON(): %v", err)
+	}
+	got, err := ParseRTMRJSON(out)
+	if err != nil {
+		t.Fatalf("ParseRTMRJSON(): %v", err)
+	}
+	if len(got.RTMRs) != 2 || got.RTMRs[0].Index != 1 || !bytes.Equal(got.RTMRs[1].Digest, rtmr2) {
+		t.Errorf("ParseRTMRJSON(FormatRTMRJSON()) = %v, want %v", got, bank)
+	}
+
+	list := []byte(`{"rtmrs": ["0x` + strings.Repeat("00", 48) + `", "` + strings.Repeat("aa", 48) + `"], "mrtd": "ignored"}`)
+	got, err = ParseRTMRJSON(list)
+	if err != nil {
+		t.Fatalf("ParseRTMRJSON(rtmrs array): %v", err)
+	}
+	if len(got.RTMRs) != 2 || got.RTMRs[1].Index != 1 {
+		t.Errorf("ParseRTMRJSON(rtmrs array) = %v, want RTMR[0] and RTMR[1]", got)
+	}
+
+	if _, err := ParseRTMRJSON([]byte(`{"RTMR[0]": "00"}`)); err == nil {
+		t.Errorf("ParseRTMRJSON(short digest): got nil, want error")
+	}
+	duplicate := []byte(`{"rtmr1": "` + strings.Repeat("11", 48) + `", "RTMR[1]": "` + strings.Repeat("22", 48) + `"}`)
+	if _, err := ParseRTMRJSON(duplicate); err == nil {
+		t.Errorf("ParseRTMRJSON(duplicate RTMR): got nil, want error")
+	}
+	// Of several malformed keys, the first in sorted order is reported.
+	malformed := []byte(`{"rtmr3": 3, "rtmr0": 0, "rtmr2": 2}`)
+	for i := 0; i < 10; i++ {
+		if _, err := ParseRTMRJSON(malformed); err == nil || !strings.Contains(err.Error(), `"rtmr0"`) {
+			t.Fatalf("ParseRTMRJSON(malformed) = %v, want error for \"rtmr0\"", err)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal
+
+package register
+
+import pb "github.com/google/go-eventlog/proto/state"
+
+// HashAlgo is the TCG Algorithm Registry ID of the hash algorithm of a PCR
+// bank. Builds with the eventlog_minimal tag define it as a plain integer
+// type, so they do not depend on protobuf.
+type HashAlgo = pb.HashAlgo
@@ -0,0 +1,42 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build eventlog_minimal
+
+package register
+
+import (
+	"crypto"
+	"fmt"
+)
+
+// HashAlgo is the TCG Algorithm Registry ID of the hash algorithm of a PCR
+// bank. It has the same values as the state.HashAlgo proto enum used by
+// default builds.
+type HashAlgo int32
+
+// CryptoHash converts the TCG registry hash identifier to a crypto.Hash.
+func (a HashAlgo) CryptoHash() (crypto.Hash, error) {
+	return TPMAlgHash(uint16(a))
+}
+
+// String returns the name of the hash algorithm.
+func (a HashAlgo) String() string {
+	for _, alg := range tpmAlgHashes {
+		if int32(alg.id) == int32(a) {
+			return alg.hash.String()
+		}
+	}
+	return fmt.Sprintf("HashAlgo(%d)", int32(a))
+}
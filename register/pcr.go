@@ -18,14 +18,11 @@ package register
 import (
 	"crypto"
 	"fmt"
-
-	pb "github.com/google/go-eventlog/proto/state"
-	"github.com/google/go-tpm/legacy/tpm2"
 )
 
 // PCRBank is a bank of PCRs that all correspond to the same hash algorithm.
 type PCRBank struct {
-	TCGHashAlgo pb.HashAlgo
+	TCGHashAlgo HashAlgo
 	PCRs        []PCR
 }
 
@@ -104,9 +101,14 @@ type HashAlg uint8
 
 // Valid hash algorithms.
 var (
-	HashSHA1   = HashAlg(tpm2.AlgSHA1)
-	HashSHA256 = HashAlg(tpm2.AlgSHA256)
-	HashSHA384 = HashAlg(tpm2.AlgSHA384)
+	HashSHA1   = HashAlg(algSHA1)
+	HashSHA256 = HashAlg(algSHA256)
+	HashSHA384 = HashAlg(algSHA384)
+	// The SHA3 family requires the caller to link in an implementation
+	// registered with crypto.RegisterHash to replay or compute digests.
+	HashSHA3_256 = HashAlg(algSHA3_256)
+	HashSHA3_384 = HashAlg(algSHA3_384)
+	HashSHA3_512 = HashAlg(algSHA3_512)
 )
 
 // CryptoHash turns the hash algo into a crypto.Hash
@@ -118,20 +120,12 @@ func (a HashAlg) CryptoHash() crypto.Hash {
 		return crypto.SHA256
 	case HashSHA384:
 		return crypto.SHA384
-	}
-	return 0
-}
-
-// GoTPMAlg returns the go-tpm definition of this crypto.Hash, based on the
-// TCG Algorithm Registry.
-func (a HashAlg) GoTPMAlg() tpm2.Algorithm {
-	switch a {
-	case HashSHA1:
-		return tpm2.AlgSHA1
-	case HashSHA256:
-		return tpm2.AlgSHA256
-	case HashSHA384:
-		return tpm2.AlgSHA384
+	case HashSHA3_256:
+		return crypto.SHA3_256
+	case HashSHA3_384:
+		return crypto.SHA3_384
+	case HashSHA3_512:
+		return crypto.SHA3_512
 	}
 	return 0
 }
@@ -145,6 +139,12 @@ func (a HashAlg) String() string {
 		return "SHA256"
 	case HashSHA384:
 		return "SHA384"
+	case HashSHA3_256:
+		return "SHA3_256"
+	case HashSHA3_384:
+		return "SHA3_384"
+	case HashSHA3_512:
+		return "SHA3_512"
 	}
 	return fmt.Sprintf("HashAlg<%d>", int(a))
 }
@@ -0,0 +1,39 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal
+
+package register
+
+import "github.com/google/go-tpm/legacy/tpm2"
+
+// GoTPMAlg returns the go-tpm definition of this crypto.Hash, based on the
+// TCG Algorithm Registry.
+func (a HashAlg) GoTPMAlg() tpm2.Algorithm {
+	switch a {
+	case HashSHA1:
+		return tpm2.AlgSHA1
+	case HashSHA256:
+		return tpm2.AlgSHA256
+	case HashSHA384:
+		return tpm2.AlgSHA384
+	case HashSHA3_256:
+		return tpm2.AlgSHA3_256
+	case HashSHA3_384:
+		return tpm2.AlgSHA3_384
+	case HashSHA3_512:
+		return tpm2.AlgSHA3_512
+	}
+	return 0
+}
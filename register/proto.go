@@ -0,0 +1,117 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal
+
+package register
+
+import (
+	"crypto"
+	"fmt"
+	"sort"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+// PCRBankProto converts a PCR bank to a RegisterBank proto, for transport
+// alongside a FirmwareLogState.
+func PCRBankProto(bank PCRBank) (*pb.RegisterBank, error) {
+	cryptoHash, err := bank.CryptoHash()
+	if err != nil {
+		return nil, err
+	}
+	out := &pb.RegisterBank{Hash: bank.TCGHashAlgo, Values: make(map[uint32][]byte)}
+	for _, pcr := range bank.PCRs {
+		if err := addRegister(out, pcr.Index, pcr.Digest, cryptoHash); err != nil {
+			return nil, fmt.Errorf("PCR %v", err)
+		}
+	}
+	return out, nil
+}
+
+// PCRBankFromProto converts a RegisterBank proto to a PCR bank, ordered by
+// PCR index.
+func PCRBankFromProto(bank *pb.RegisterBank) (PCRBank, error) {
+	cryptoHash, err := bank.GetHash().CryptoHash()
+	if err != nil {
+		return PCRBank{}, fmt.Errorf("bad register bank hash %v: %v", bank.GetHash(), err)
+	}
+	pcrBank := PCRBank{TCGHashAlgo: bank.GetHash()}
+	for _, idx := range sortedIndexes(bank) {
+		value := bank.GetValues()[idx]
+		if len(value) != cryptoHash.Size() {
+			return PCRBank{}, fmt.Errorf("PCR %d in %v bank has length %d, want %d", idx, bank.GetHash(), len(value), cryptoHash.Size())
+		}
+		pcrBank.PCRs = append(pcrBank.PCRs, PCR{Index: int(idx), Digest: value, DigestAlg: cryptoHash})
+	}
+	return pcrBank, nil
+}
+
+// RTMRBankProto converts an RTMR bank to a SHA384 RegisterBank proto, indexed
+// by RTMR index.
+func RTMRBankProto(bank RTMRBank) (*pb.RegisterBank, error) {
+	out := &pb.RegisterBank{Hash: pb.HashAlgo_SHA384, Values: make(map[uint32][]byte)}
+	for _, rtmr := range bank.RTMRs {
+		if rtmr.Index >= numRTMRs {
+			return nil, fmt.Errorf("RTMR index %d out of range", rtmr.Index)
+		}
+		if err := addRegister(out, rtmr.Index, rtmr.Digest, crypto.SHA384); err != nil {
+			return nil, fmt.Errorf("RTMR %v", err)
+		}
+	}
+	return out, nil
+}
+
+// RTMRBankFromProto converts a SHA384 RegisterBank proto, indexed by RTMR
+// index, to an RTMR bank, ordered by RTMR index.
+func RTMRBankFromProto(bank *pb.RegisterBank) (RTMRBank, error) {
+	if bank.GetHash() != pb.HashAlgo_SHA384 {
+		return RTMRBank{}, fmt.Errorf("got %v register bank for RTMRs, want SHA384", bank.GetHash())
+	}
+	var rtmrBank RTMRBank
+	for _, idx := range sortedIndexes(bank) {
+		value := bank.GetValues()[idx]
+		if idx >= numRTMRs {
+			return RTMRBank{}, fmt.Errorf("RTMR index %d out of range", idx)
+		}
+		if len(value) != crypto.SHA384.Size() {
+			return RTMRBank{}, fmt.Errorf("RTMR %d has length %d, want %d", idx, len(value), crypto.SHA384.Size())
+		}
+		rtmrBank.RTMRs = append(rtmrBank.RTMRs, RTMR{Index: int(idx), Digest: value})
+	}
+	return rtmrBank, nil
+}
+
+func addRegister(bank *pb.RegisterBank, idx int, digest []byte, hash crypto.Hash) error {
+	if idx < 0 {
+		return fmt.Errorf("index %d is negative", idx)
+	}
+	if _, ok := bank.Values[uint32(idx)]; ok {
+		return fmt.Errorf("%d is duplicated", idx)
+	}
+	if len(digest) != hash.Size() {
+		return fmt.Errorf("%d has length %d, want %d", idx, len(digest), hash.Size())
+	}
+	bank.Values[uint32(idx)] = digest
+	return nil
+}
+
+func sortedIndexes(bank *pb.RegisterBank) []uint32 {
+	indexes := make([]uint32, 0, len(bank.GetValues()))
+	for idx := range bank.GetValues() {
+		indexes = append(indexes, idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes
+}
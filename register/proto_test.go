@@ -0,0 +1,83 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal
+
+package register
+
+import (
+	"bytes"
+	"crypto"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+func TestPCRBankProtoRoundTrip(t *testing.T) {
+	bank := PCRBank{TCGHashAlgo: pb.HashAlgo_SHA256, PCRs: []PCR{
+		{Index: 0, Digest: bytes.Repeat([]byte{0x01}, 32), DigestAlg: crypto.SHA256},
+		{Index: 7, Digest: bytes.Repeat([]byte{0x07}, 32), DigestAlg: crypto.SHA256},
+		{Index: 14, Digest: bytes.Repeat([]byte{0x0e}, 32), DigestAlg: crypto.SHA256},
+	}}
+	msg, err := PCRBankProto(bank)
+	if err != nil {
+		t.Fatalf("PCRBankProto(): %v", err)
+	}
+	got, err := PCRBankFromProto(msg)
+	if err != nil {
+		t.Fatalf("PCRBankFromProto(): %v", err)
+	}
+	if diff := cmp.Diff(bank, got, cmp.AllowUnexported(PCR{})); diff != "" {
+		t.Errorf("PCRBankFromProto(PCRBankProto()) returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	bank.PCRs = append(bank.PCRs, bank.PCRs[0])
+	if _, err := PCRBankProto(bank); err == nil {
+		t.Errorf("PCRBankProto(duplicate PCR): got nil, want error")
+	}
+	if _, err := PCRBankFromProto(&pb.RegisterBank{Hash: pb.HashAlgo_SHA1, Values: map[uint32][]byte{0: make([]byte, 32)}}); err == nil {
+		t.Errorf("PCRBankFromProto(wrong digest length): got nil, want error")
+	}
+}
+
+func TestRTMRBankProtoRoundTrip(t *testing.T) {
+	bank := RTMRBank{RTMRs: []RTMR{
+		{Index: 0, Digest: bytes.Repeat([]byte{0x00}, 48)},
+		{Index: 2, Digest: bytes.Repeat([]byte{0x22}, 48)},
+	}}
+	msg, err := RTMRBankProto(bank)
+	if err != nil {
+		t.Fatalf("RTMRBankProto(): %v", err)
+	}
+	if msg.GetHash() != pb.HashAlgo_SHA384 {
+		t.Errorf("RTMRBankProto() hash = %v, want SHA384", msg.GetHash())
+	}
+	got, err := RTMRBankFromProto(msg)
+	if err != nil {
+		t.Fatalf("RTMRBankFromProto(): %v", err)
+	}
+	if diff := cmp.Diff(bank, got); diff != "" {
+		t.Errorf("RTMRBankFromProto(RTMRBankProto()) returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	for _, msg := range []*pb.RegisterBank{
+		{Hash: pb.HashAlgo_SHA256, Values: map[uint32][]byte{0: make([]byte, 32)}},
+		{Hash: pb.HashAlgo_SHA384, Values: map[uint32][]byte{4: make([]byte, 48)}},
+	} {
+		if _, err := RTMRBankFromProto(msg); err == nil {
+			t.Errorf("RTMRBankFromProto(%v): got nil, want error", msg)
+		}
+	}
+}
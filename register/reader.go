@@ -0,0 +1,36 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal
+
+package register
+
+import "crypto"
+
+// RegisterReader reads measurement registers from a root of trust.
+type RegisterReader interface {
+	// ReadMRBank reads the measurement registers in mrSelection from the
+	// bank of the given hash algorithm.
+	ReadMRBank(hash crypto.Hash, mrSelection []int) (MRBank, error)
+}
+
+// ReadMRBank is ReadMRs, returning the FakeMRBank as an MRBank.
+func (f FakeROT) ReadMRBank(hash crypto.Hash, mrSelection []int) (MRBank, error) {
+	return f.ReadMRs(hash, mrSelection)
+}
+
+var (
+	_ RegisterReader = FakeROT{}
+	_ RegisterReader = TDXReader{}
+)
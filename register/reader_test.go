@@ -0,0 +1,57 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal
+
+package register
+
+import (
+	"crypto"
+	"testing"
+)
+
+func TestFakeROTRegisterReader(t *testing.T) {
+	rot, err := CreateFakeRot([]crypto.Hash{crypto.SHA256}, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rot.ExtendMR(FakeMR{Index: 16, Digest: make([]byte, crypto.SHA256.Size()), DigestAlg: crypto.SHA256}); err != nil {
+		t.Fatal(err)
+	}
+	want, err := rot.ReadMRs(crypto.SHA256, []int{16, 23})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reader RegisterReader = rot
+	got, err := reader.ReadMRBank(crypto.SHA256, []int{16, 23})
+	if err != nil {
+		t.Fatalf("ReadMRBank() failed: %v", err)
+	}
+	bank, ok := got.(FakeMRBank)
+	if !ok {
+		t.Fatalf("ReadMRBank() returned a %T, want a FakeMRBank", got)
+	}
+	if len(bank.FakeMRs) != 2 {
+		t.Fatalf("ReadMRBank() returned %d MRs, want 2", len(bank.FakeMRs))
+	}
+	for i, mr := range bank.FakeMRs {
+		if mr.Index != want.FakeMRs[i].Index || string(mr.Digest) != string(want.FakeMRs[i].Digest) {
+			t.Errorf("ReadMRBank() MR %d = %v, want %v", i, mr, want.FakeMRs[i])
+		}
+	}
+	if _, err := reader.ReadMRBank(crypto.SHA384, []int{16}); err == nil {
+		t.Error("ReadMRBank(missing bank): got nil, want error")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal && !js && !wasip1
+
+package register
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"sort"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+var _ RegisterReader = TPMReader{}
+
+// maxPCRsPerRead is the number of PCRs a TPM2_PCR_Read command is guaranteed
+// to return.
+const maxPCRsPerRead = 8
+
+// TPMReader reads PCRs from a TPM 2.0 device, e.g., one opened with
+// github.com/google/go-tpm/legacy/tpm2.OpenTPM. It is unavailable in
+// WebAssembly builds, which have no TPM device.
+type TPMReader struct {
+	RW io.ReadWriter
+}
+
+// ReadMRBank reads the selected PCRs. The returned MRBank is a PCRBank.
+func (r TPMReader) ReadMRBank(hash crypto.Hash, mrSelection []int) (MRBank, error) {
+	return r.ReadPCRBank(hash, mrSelection)
+}
+
+// ReadPCRBank reads the selected PCRs from the bank of the given hash
+// algorithm. The result can be passed to tpmeventlog.ReplayAndExtract.
+func (r TPMReader) ReadPCRBank(hash crypto.Hash, pcrSelection []int) (PCRBank, error) {
+	alg, err := tpm2.HashToAlgorithm(hash)
+	if err != nil {
+		return PCRBank{}, err
+	}
+	sel := append([]int(nil), pcrSelection...)
+	sort.Ints(sel)
+
+	bank := PCRBank{TCGHashAlgo: pb.HashAlgo(alg)}
+	for start := 0; start < len(sel); start += maxPCRsPerRead {
+		end := start + maxPCRsPerRead
+		if end > len(sel) {
+			end = len(sel)
+		}
+		values, err := tpm2.ReadPCRs(r.RW, tpm2.PCRSelection{Hash: alg, PCRs: sel[start:end]})
+		if err != nil {
+			return PCRBank{}, fmt.Errorf("failed to read PCRs %v in bank %v: %v", sel[start:end], hash, err)
+		}
+		for _, idx := range sel[start:end] {
+			digest, ok := values[idx]
+			if !ok {
+				return PCRBank{}, fmt.Errorf("TPM did not return PCR %d in bank %v", idx, hash)
+			}
+			bank.PCRs = append(bank.PCRs, PCR{Index: idx, Digest: digest, DigestAlg: hash})
+		}
+	}
+	return bank, nil
+}
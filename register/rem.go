@@ -0,0 +1,98 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package register
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+)
+
+/*
+RIM  => CC MR 0 (the Realm Initial Measurement)
+REM0 => PCR1,7
+REM1 => PCR2-6
+REM2 => PCR8-15
+REM3 => N/A (for userspace)
+*/
+
+// numREMs is the number of Realm Extensible Measurements of a realm.
+const numREMs = 4
+
+// REMBank is a bank of Arm CCA Realm Extensible Measurements (REMs), which all
+// use the realm's hash algorithm, SHA-256 or SHA-512.
+type REMBank struct {
+	REMs []REM
+}
+
+// CryptoHash returns the crypto.Hash algorithm related to the REM bank.
+func (b REMBank) CryptoHash() (crypto.Hash, error) {
+	if len(b.REMs) == 0 {
+		return crypto.Hash(0), errors.New("received an empty REM bank")
+	}
+	hash := b.REMs[0].DigestAlg
+	if hash != crypto.SHA256 && hash != crypto.SHA512 {
+		return crypto.Hash(0), fmt.Errorf("REMs only support SHA256 and SHA512, got %v", hash)
+	}
+	var invalidREMs []int
+	for _, rem := range b.REMs {
+		if rem.Index < 0 || rem.Index >= numREMs {
+			return crypto.Hash(0), fmt.Errorf("invalid REM index %d", rem.Index)
+		}
+		if rem.DigestAlg != hash {
+			invalidREMs = append(invalidREMs, rem.Index)
+		}
+	}
+	if len(invalidREMs) != 0 {
+		return crypto.Hash(0), fmt.Errorf("found an invalid hash algorithm in REMs %v for bank of algorithm %v", invalidREMs, hash)
+	}
+	return hash, nil
+}
+
+// MRs returns a slice of MR from the REM implementation.
+func (b REMBank) MRs() []MR {
+	mrs := make([]MR, len(b.REMs))
+	for i, v := range b.REMs {
+		mrs[i] = v
+	}
+	return mrs
+}
+
+// REM encapsulates the value of an Arm CCA Realm Extensible Measurement at a
+// point in time.
+type REM struct {
+	// The REM index, not the CC MR index. e.g., for REM[1], put 1, not 2.
+	Index     int
+	Digest    []byte
+	DigestAlg crypto.Hash
+}
+
+// Idx gives the CC Measurement Register index.
+// This value is the one used in Confidential Computing event logs. As for
+// TDX RTMRs, the Realm Initial Measurement uses CC MR index 0, so REM0 uses
+// 1, REM1 uses 2, and so on.
+func (r REM) Idx() int {
+	return r.Index + 1
+}
+
+// Dgst gives the REM digest.
+func (r REM) Dgst() []byte {
+	return r.Digest
+}
+
+// DgstAlg gives the REM digest algorithm as a crypto.Hash.
+func (r REM) DgstAlg() crypto.Hash {
+	return r.DigestAlg
+}
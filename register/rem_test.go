@@ -0,0 +1,53 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package register
+
+import (
+	"crypto"
+	"testing"
+)
+
+func TestREMBank(t *testing.T) {
+	bank := REMBank{REMs: []REM{
+		{Index: 0, Digest: make([]byte, 32), DigestAlg: crypto.SHA256},
+		{Index: 2, Digest: make([]byte, 32), DigestAlg: crypto.SHA256},
+	}}
+	if hash, err := bank.CryptoHash(); err != nil || hash != crypto.SHA256 {
+		t.Errorf("CryptoHash() = %v, %v, want SHA256", hash, err)
+	}
+	var indexes []int
+	for _, mr := range bank.MRs() {
+		indexes = append(indexes, mr.Idx())
+	}
+	if len(indexes) != 2 || indexes[0] != 1 || indexes[1] != 3 {
+		t.Errorf("MRs() have CC MR indexes %v, want [1 3]", indexes)
+	}
+
+	for _, tc := range []struct {
+		name string
+		bank REMBank
+	}{
+		{"empty", REMBank{}},
+		{"SHA384", REMBank{REMs: []REM{{Index: 0, DigestAlg: crypto.SHA384}}}},
+		{"mixed algorithms", REMBank{REMs: []REM{{Index: 0, DigestAlg: crypto.SHA256}, {Index: 1, DigestAlg: crypto.SHA512}}}},
+		{"index out of range", REMBank{REMs: []REM{{Index: 4, DigestAlg: crypto.SHA256}}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.bank.CryptoHash(); err == nil {
+				t.Errorf("CryptoHash(): got nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package register
+
+import (
+	"crypto"
+	"fmt"
+)
+
+const (
+	// tdReportLen is the length of a TDREPORT_STRUCT.
+	tdReportLen = 1024
+	// tdReportRTMROffset is the offset of RTMR[0] in a TDREPORT_STRUCT:
+	// REPORTMACSTRUCT (256) + TEE_TCB_INFO (239) + reserved (17), followed by
+	// TDINFO ATTRIBUTES (8), XFAM (8), MRTD, MRCONFIGID, MROWNER, and
+	// MROWNERCONFIG (48 each).
+	tdReportRTMROffset = 256 + 239 + 17 + 8 + 8 + 4*48
+	// numRTMRs is the number of RTMRs in a TD.
+	numRTMRs = 4
+	// tdxDevice is the Linux TDX guest device.
+	tdxDevice = "/dev/tdx_guest"
+)
+
+// TDXReader reads RTMRs from the TDREPORT of the running TDX guest.
+type TDXReader struct {
+	// Device is the TDX guest device. If empty, /dev/tdx_guest is used.
+	Device string
+}
+
+// ReadMRBank reads the selected RTMRs. The selection uses RTMR indexes, e.g.,
+// 1 for RTMR[1], and hash must be SHA-384. The returned MRBank is a RTMRBank.
+func (r TDXReader) ReadMRBank(hash crypto.Hash, mrSelection []int) (MRBank, error) {
+	if hash != crypto.SHA384 {
+		return RTMRBank{}, fmt.Errorf("RTMRs only support SHA384, got %v", hash)
+	}
+	bank, err := r.ReadRTMRBank()
+	if err != nil {
+		return RTMRBank{}, err
+	}
+	return selectRTMRs(bank, mrSelection)
+}
+
+// selectRTMRs returns the RTMRs of bank in mrSelection, in selection order.
+func selectRTMRs(bank RTMRBank, mrSelection []int) (RTMRBank, error) {
+	selected := RTMRBank{}
+	for _, idx := range mrSelection {
+		if idx < 0 || idx >= numRTMRs {
+			return RTMRBank{}, fmt.Errorf("invalid RTMR index %d", idx)
+		}
+		selected.RTMRs = append(selected.RTMRs, bank.RTMRs[idx])
+	}
+	return selected, nil
+}
+
+// ReadRTMRBank reads all RTMRs. The result can be passed to
+// ccel.ReplayAndExtract.
+func (r TDXReader) ReadRTMRBank() (RTMRBank, error) {
+	device := r.Device
+	if device == "" {
+		device = tdxDevice
+	}
+	report, err := getTDReport(device)
+	if err != nil {
+		return RTMRBank{}, fmt.Errorf("failed to get TDREPORT from %v: %v", device, err)
+	}
+	return ParseTDReportRTMRs(report)
+}
+
+// ParseTDReportRTMRs parses the RTMRs from a TDREPORT_STRUCT, as defined in
+// the Intel TDX Module ABI specification.
+func ParseTDReportRTMRs(report []byte) (RTMRBank, error) {
+	if len(report) < tdReportLen {
+		return RTMRBank{}, fmt.Errorf("TDREPORT too short: got %d bytes, want %d", len(report), tdReportLen)
+	}
+	var bank RTMRBank
+	for idx := 0; idx < numRTMRs; idx++ {
+		offset := tdReportRTMROffset + idx*crypto.SHA384.Size()
+		digest := make([]byte, crypto.SHA384.Size())
+		copy(digest, report[offset:])
+		bank.RTMRs = append(bank.RTMRs, RTMR{Index: idx, Digest: digest})
+	}
+	return bank, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build linux
+
+package register
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// tdxCmdGetReport0 is TDX_CMD_GET_REPORT0 from Linux's
+// include/uapi/linux/tdx-guest.h: _IOWR('T', 1, struct tdx_report_req).
+const tdxCmdGetReport0 = 0xc4405401
+
+// tdxReportReq is struct tdx_report_req.
+type tdxReportReq struct {
+	reportData [64]byte
+	tdReport   [tdReportLen]byte
+}
+
+func getTDReport(device string) ([]byte, error) {
+	f, err := os.Open(device)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var req tdxReportReq
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), tdxCmdGetReport0, uintptr(unsafe.Pointer(&req))); errno != 0 {
+		return nil, errno
+	}
+	return req.tdReport[:], nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package register
+
+import (
+	"bytes"
+	"crypto"
+	"path/filepath"
+	"testing"
+)
+
+func testTDReport() []byte {
+	report := make([]byte, tdReportLen)
+	for idx := 0; idx < numRTMRs; idx++ {
+		offset := tdReportRTMROffset + idx*crypto.SHA384.Size()
+		copy(report[offset:], bytes.Repeat([]byte{byte(idx + 1)}, crypto.SHA384.Size()))
+	}
+	return report
+}
+
+func TestParseTDReportRTMRs(t *testing.T) {
+	bank, err := ParseTDReportRTMRs(testTDReport())
+	if err != nil {
+		t.Fatalf("ParseTDReportRTMRs() failed: %v", err)
+	}
+	if len(bank.RTMRs) != numRTMRs {
+		t.Fatalf("ParseTDReportRTMRs() returned %d RTMRs, want %d", len(bank.RTMRs), numRTMRs)
+	}
+	for idx, rtmr := range bank.RTMRs {
+		want := bytes.Repeat([]byte{byte(idx + 1)}, crypto.SHA384.Size())
+		if rtmr.Index != idx || !bytes.Equal(rtmr.Digest, want) {
+			t.Errorf("RTMR[%d] = %d, %x, want %d, %x", idx, rtmr.Index, rtmr.Digest, idx, want)
+		}
+	}
+	if _, err := bank.CryptoHash(); err != nil {
+		t.Errorf("CryptoHash() failed: %v", err)
+	}
+
+	if _, err := ParseTDReportRTMRs(make([]byte, tdReportLen-1)); err == nil {
+		t.Error("ParseTDReportRTMRs(short report): got nil, want error")
+	}
+}
+
+func TestSelectRTMRs(t *testing.T) {
+	bank, err := ParseTDReportRTMRs(testTDReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	selected, err := selectRTMRs(bank, []int{2, 1})
+	if err != nil {
+		t.Fatalf("selectRTMRs() failed: %v", err)
+	}
+	if len(selected.RTMRs) != 2 || selected.RTMRs[0].Index != 2 || selected.RTMRs[1].Index != 1 {
+		t.Errorf("selectRTMRs([2 1]) = %v, want RTMR[2] and RTMR[1]", selected.RTMRs)
+	}
+	for _, sel := range [][]int{{-1}, {numRTMRs}} {
+		if _, err := selectRTMRs(bank, sel); err == nil {
+			t.Errorf("selectRTMRs(%v): got nil, want error", sel)
+		}
+	}
+}
+
+func TestTDXReaderErrors(t *testing.T) {
+	reader := TDXReader{Device: filepath.Join(t.TempDir(), "tdx_guest")}
+	if _, err := reader.ReadMRBank(crypto.SHA256, []int{1}); err == nil {
+		t.Error("ReadMRBank(SHA256): got nil, want error")
+	}
+	if _, err := reader.ReadMRBank(crypto.SHA384, []int{1}); err == nil {
+		t.Error("ReadMRBank(missing device): got nil, want error")
+	}
+}
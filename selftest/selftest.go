@@ -0,0 +1,193 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package selftest re-verifies the event logs bundled in the testdata package
+// against golden FirmwareLogStates, so downstream packagers can check that
+// their build and toolchain produce the same extraction results as upstream.
+//
+// Importing this package embeds the bundled event logs and golden states in
+// the binary.
+package selftest
+
+import (
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-eventlog"
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/testdata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+//go:embed golden/*.json
+var goldenFS embed.FS
+
+// eventLogs are the bundled event logs, named after their file in
+// testdata/eventlogs/tpm.
+var eventLogs = []struct {
+	name string
+	log  []byte
+}{
+	{"arch-linux-workstation", testdata.ArchLinuxWorkstationEventLog},
+	{"cos-85-amd-sev", testdata.Cos85AmdSevEventLog},
+	{"cos-93-amd-sev", testdata.Cos93AmdSevEventLog},
+	{"cos-101-amd-sev", testdata.Cos101AmdSevEventLog},
+	{"debian-10", testdata.Debian10EventLog},
+	{"glinux-alex", testdata.GlinuxAlexEventLog},
+	{"rhel8-uefi", testdata.Rhel8EventLog},
+	{"ubuntu-1804-amd-sev", testdata.Ubuntu1804AmdSevEventLog},
+	{"ubuntu-2104-no-dbx", testdata.Ubuntu2104NoDbxEventLog},
+	{"ubuntu-2104-no-secure-boot", testdata.Ubuntu2104NoSecureBootEventLog},
+	{"ubuntu-2404-amd-sevsnp", testdata.Ubuntu2404AmdSevSnpEventLog},
+}
+
+// golden is the expected extraction result of a bundled event log.
+type golden struct {
+	// Hash is the pb.HashAlgo name of the bank, e.g., "SHA256". It is "SHA1"
+	// for event logs without SHA-256 digests.
+	Hash string `json:"hash"`
+	// Registers maps PCR indexes to hex-encoded register values.
+	Registers map[int]string `json:"registers"`
+	// CompatLevel is the SchemaVersion the state was extracted at.
+	CompatLevel uint32 `json:"compat_level"`
+	// Error is the extraction error, if the state is partial.
+	Error string `json:"error,omitempty"`
+	// State is the protojson FirmwareLogState, without its raw events.
+	State json.RawMessage `json:"state,omitempty"`
+}
+
+// Result is the outcome of re-verifying a bundled event log.
+type Result struct {
+	// Name identifies the event log, e.g., "ubuntu-2404-amd-sevsnp".
+	Name string
+	// Err describes how the extraction differed from the golden state, or is
+	// nil if it matched.
+	Err error
+}
+
+// Run re-verifies each bundled event log against its golden register values,
+// extracts a FirmwareLogState at the golden CompatLevel, and compares it and
+// any extraction error to the golden result. It returns a Result per event
+// log.
+//
+// Raw events are not compared, as they are copied from the event log.
+// Floating-point fields, e.g., EventLogStats.data_entropy, are compared with
+// a tolerance, as toolchains may fuse floating-point operations differently.
+func Run() []Result {
+	results := make([]Result, 0, len(eventLogs))
+	for _, el := range eventLogs {
+		results = append(results, Result{Name: el.name, Err: check(el.name, el.log)})
+	}
+	return results
+}
+
+// Check runs the self-test and returns an error describing every event log
+// whose extraction differed from its golden result, or nil if all matched.
+func Check() error {
+	var errs []error
+	for _, result := range Run() {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", result.Name, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func check(name string, log []byte) error {
+	data, err := goldenFS.ReadFile("golden/" + name + ".json")
+	if err != nil {
+		return fmt.Errorf("failed to read golden result: %v", err)
+	}
+	var want golden
+	if err := json.Unmarshal(data, &want); err != nil {
+		return fmt.Errorf("failed to parse golden result: %v", err)
+	}
+	bank, err := want.bank()
+	if err != nil {
+		return err
+	}
+	var wantState *pb.FirmwareLogState
+	if len(want.State) > 0 {
+		wantState = &pb.FirmwareLogState{}
+		if err := protojson.Unmarshal(want.State, wantState); err != nil {
+			return fmt.Errorf("failed to parse golden state: %v", err)
+		}
+	}
+
+	gotState, gotErr := extractState(log, bank, want.CompatLevel)
+	if errString(gotErr) != want.Error {
+		return fmt.Errorf("got extraction error %q, want %q", errString(gotErr), want.Error)
+	}
+	if diff := cmp.Diff(wantState, gotState, protocmp.Transform(), cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		return fmt.Errorf("extracted state differs from the golden state (-want +got):\n%s", diff)
+	}
+	return nil
+}
+
+// extractState verifies the event log against bank and extracts its
+// FirmwareLogState, without raw events.
+func extractState(log []byte, bank eventlog.Bank, compatLevel uint32) (*pb.FirmwareLogState, error) {
+	parsed, err := eventlog.ParsePCClient(log)
+	if err != nil {
+		return nil, err
+	}
+	state, err := eventlog.Extract(parsed, bank, eventlog.ExtractOpts{
+		CompatLevel: compatLevel,
+		Loader:      eventlog.AutoDetectLoader,
+	})
+	if state != nil {
+		state.RawEvents = nil
+	}
+	return state, err
+}
+
+func (g golden) bank() (eventlog.Bank, error) {
+	alg, ok := pb.HashAlgo_value[g.Hash]
+	if !ok {
+		return eventlog.Bank{}, fmt.Errorf("unknown golden hash %q", g.Hash)
+	}
+	hash, err := pb.HashAlgo(alg).CryptoHash()
+	if err != nil {
+		return eventlog.Bank{}, err
+	}
+	indexes := make([]int, 0, len(g.Registers))
+	for idx := range g.Registers {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	bank := eventlog.Bank{Hash: hash, Values: make(map[int][]byte, len(g.Registers))}
+	for _, idx := range indexes {
+		value := g.Registers[idx]
+		digest, err := hex.DecodeString(value)
+		if err != nil || len(digest) != hash.Size() {
+			return eventlog.Bank{}, fmt.Errorf("malformed golden value of register %d: %q", idx, value)
+		}
+		bank.Values[idx] = digest
+	}
+	return bank, nil
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package selftest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-eventlog"
+	"github.com/google/go-eventlog/internal/testutil"
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/tcg"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden results from the current extraction")
+
+// generate returns the golden result of an event log at the current
+// SchemaVersion. The register values are replayed from the event log, using
+// its SHA-256 bank if it has one.
+func generate(t *testing.T, log []byte) []byte {
+	t.Helper()
+	parsed, err := tcg.ParseEventLog(log, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alg := pb.HashAlgo_SHA1
+	for _, a := range parsed.Algs {
+		if pb.HashAlgo(a) == pb.HashAlgo_SHA256 {
+			alg = pb.HashAlgo_SHA256
+		}
+	}
+	hash, err := alg.CryptoHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zeros := make(map[uint32][]byte)
+	for idx := uint32(0); idx < 24; idx++ {
+		zeros[idx] = make([]byte, hash.Size())
+	}
+	g := golden{
+		Hash:        alg.String(),
+		Registers:   make(map[int]string),
+		CompatLevel: eventlog.SchemaVersion,
+	}
+	for _, trace := range parsed.Trace(testutil.MakePCRBank(alg, zeros).MRs()) {
+		if trace.Err == nil && len(trace.Steps) > 0 {
+			g.Registers[trace.Index] = hex.EncodeToString(trace.Final())
+		}
+	}
+	bank, err := g.bank()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := extractState(log, bank, g.CompatLevel)
+	g.Error = errString(err)
+	if state != nil {
+		if g.State, err = protojson.Marshal(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// MarshalIndent also normalizes the unstable protojson whitespace.
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(data, '\n')
+}
+
+func TestRun(t *testing.T) {
+	if *update {
+		for _, el := range eventLogs {
+			if err := os.WriteFile(filepath.Join("golden", el.name+".json"), generate(t, el.log), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	results := Run()
+	if len(results) != len(eventLogs) {
+		t.Errorf("Run(): got %d results, want %d", len(results), len(eventLogs))
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("Run(): %s: %v", result.Name, result.Err)
+		}
+	}
+	if err := Check(); err != nil {
+		t.Errorf("Check(): %v", err)
+	}
+}
+
+func TestCheckDetectsDifferences(t *testing.T) {
+	log := append([]byte(nil), eventLogs[len(eventLogs)-1].log...)
+	// Change the last byte of the event log, which is in the data of its
+	// last event.
+	log[len(log)-1] ^= 0xff
+	if err := check(eventLogs[len(eventLogs)-1].name, log); err == nil {
+		t.Errorf("check(modified event log): got nil, want error")
+	}
+	if err := check("unknown", eventLogs[0].log); err == nil {
+		t.Errorf("check(unknown event log): got nil, want error")
+	}
+}
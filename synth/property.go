@@ -0,0 +1,235 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package synth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// These are generators and invariant checks for property-based tests of
+// event log replay, e.g., with testing/quick. Boot implements
+// quick.Generator, so properties can take a Boot argument directly.
+
+var randomHashes = []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384}
+
+// newSigningCert returns a self-signed certificate to use as PK, KEK, db, and
+// authority of a random boot with Secure Boot enabled. The key is generated
+// from rand, so the certificate is reproducible.
+func newSigningCert(rand io.Reader) (*x509.Certificate, error) {
+	pub, priv, err := ed25519.GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "go-eventlog synth signing key"},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand, template, template, pub, priv)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// RandomBoot returns a random boot with a random subset of banks, random
+// firmware, EFI applications, and GRUB measurements, with Secure Boot
+// enabled or disabled. Random boots with Secure Boot enabled use a
+// self-signed certificate generated from r for all keys, so a boot, and its
+// event log, only depend on the state of r.
+func RandomBoot(r *rand.Rand) Boot {
+	var boot Boot
+	for len(boot.Hashes) == 0 {
+		for _, hash := range randomHashes {
+			if r.Intn(2) == 0 {
+				boot.Hashes = append(boot.Hashes, hash)
+			}
+		}
+	}
+	boot.FirmwareVersion = fmt.Sprintf("edk2-stable20%02d%02d", 20+r.Intn(5), 1+r.Intn(12))
+	boot.Firmware = randomBytes(r, 64)
+
+	var authority *x509.Certificate
+	if r.Intn(2) == 0 {
+		// Reading from r never fails, so neither does newSigningCert.
+		authority, _ = newSigningCert(r)
+	}
+	if cert := authority; cert != nil {
+		boot.SecureBoot = SecureBoot{
+			Enabled: true,
+			PK:      []x509.Certificate{*cert},
+			KEK:     []x509.Certificate{*cert},
+			DB:      []x509.Certificate{*cert},
+		}
+		for i := r.Intn(4); i > 0; i-- {
+			hash := make([]byte, crypto.SHA256.Size())
+			r.Read(hash)
+			boot.SecureBoot.DBX = append(boot.SecureBoot.DBX, hash)
+		}
+	}
+	for i := 0; i <= r.Intn(3); i++ {
+		boot.BootApps = append(boot.BootApps, EFIApp{
+			Path:      fmt.Sprintf(`\EFI\BOOT\%s.EFI`, randomWord(r)),
+			Image:     randomBytes(r, 256),
+			Authority: authority,
+		})
+	}
+
+	if r.Intn(2) == 0 {
+		return boot
+	}
+	boot.GRUB = &GRUB{}
+	for i := r.Intn(3); i > 0; i-- {
+		boot.GRUB.Files = append(boot.GRUB.Files, File{Path: "/boot/grub/" + randomWord(r), Content: randomBytes(r, 128)})
+	}
+	for i := r.Intn(4); i > 0; i-- {
+		boot.GRUB.Commands = append(boot.GRUB.Commands, "set "+randomWord(r)+"="+randomWord(r))
+	}
+	if r.Intn(2) == 0 {
+		boot.GRUB.Kernel = &Kernel{
+			Path:        "/" + randomWord(r),
+			Image:       randomBytes(r, 256),
+			CommandLine: "root=/dev/" + randomWord(r) + " ro",
+		}
+		if r.Intn(2) == 0 {
+			boot.GRUB.Kernel.InitrdPath = "/" + randomWord(r)
+			boot.GRUB.Kernel.Initrd = randomBytes(r, 256)
+		}
+	}
+	return boot
+}
+
+// Generate returns a RandomBoot, implementing quick.Generator.
+func (Boot) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(RandomBoot(r))
+}
+
+func randomBytes(r *rand.Rand, maxLen int) []byte {
+	b := make([]byte, 1+r.Intn(maxLen))
+	r.Read(b)
+	return b
+}
+
+func randomWord(r *rand.Rand) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
+	word := make([]byte, 1+r.Intn(12))
+	for i := range word {
+		word[i] = letters[r.Intn(len(letters))]
+	}
+	return string(word)
+}
+
+// CheckReplay checks the invariant that a generated log replays to each of
+// its banks.
+func CheckReplay(log *Log) error {
+	for _, bank := range log.Banks {
+		if _, err := tcg.ParseAndReplay(log.Raw, bank.MRs(), tcg.ParseOpts{}); err != nil {
+			return fmt.Errorf("%v bank: %v", bank.TCGHashAlgo, err)
+		}
+	}
+	return nil
+}
+
+// CheckMutation checks the invariant that XORing the byte at offset of the
+// raw event log with mask is detected by a verifier of each bank. A mutation
+// is detected if the mutated log fails to parse or replay, or if an event
+// whose digest is the digest of its data no longer matches it (see
+// tcg.Event.DigestVerified). Replay does not check registers without events,
+// so a mutation that moves the only events of a register elsewhere is
+// detected by the verifier requiring events for that register.
+//
+// Event types are not measured, and neither are the data of events whose
+// digest is of other content, e.g., an EFI application image. Mutations of
+// these only need to be detected by extraction, so they are not reported.
+// Neither are mutations that do not change the events of a bank, e.g., of a
+// digest for another bank.
+func CheckMutation(log *Log, offset int, mask byte) error {
+	if offset < 0 || offset >= len(log.Raw) {
+		return fmt.Errorf("offset %d out of range for a %d byte log", offset, len(log.Raw))
+	}
+	if mask == 0 {
+		return nil
+	}
+	mutated := bytes.Clone(log.Raw)
+	mutated[offset] ^= mask
+	original, err := tcg.ParseEventLog(log.Raw, tcg.ParseOpts{})
+	if err != nil {
+		return fmt.Errorf("parsing original log: %v", err)
+	}
+	mutatedLog, err := tcg.ParseEventLog(mutated, tcg.ParseOpts{})
+	if err != nil {
+		return nil
+	}
+	for _, bank := range log.Banks {
+		if err := checkBankMutation(original, mutatedLog, bank); err != nil {
+			return fmt.Errorf("undetected mutation at offset %d with mask %#x: %v bank: %v", offset, mask, bank.TCGHashAlgo, err)
+		}
+	}
+	return nil
+}
+
+func checkBankMutation(original, mutated *tcg.EventLog, bank register.PCRBank) error {
+	want, err := original.Verify(bank.MRs())
+	if err != nil {
+		return fmt.Errorf("replaying original log: %v", err)
+	}
+	got, err := mutated.Verify(bank.MRs())
+	if err != nil {
+		return nil
+	}
+	if !sameIndexes(got, want) {
+		return nil
+	}
+	if len(got) != len(want) {
+		return fmt.Errorf("replayed %d events, want %d", len(got), len(want))
+	}
+	for i := range want {
+		switch {
+		case got[i].Index != want[i].Index || !bytes.Equal(got[i].Digest, want[i].Digest):
+			return fmt.Errorf("event %d: index or digest changed", want[i].Num())
+		case bytes.Equal(got[i].Data, want[i].Data):
+		case want[i].DigestVerified() && got[i].DigestVerified():
+			return fmt.Errorf("event %d: data changed and still matches the digest", want[i].Num())
+		}
+	}
+	return nil
+}
+
+// sameIndexes reports whether both sets of events have events for the same
+// registers.
+func sameIndexes(a, b []tcg.Event) bool {
+	indexes := func(events []tcg.Event) map[int]bool {
+		out := make(map[int]bool)
+		for _, event := range events {
+			out[event.Index] = true
+		}
+		return out
+	}
+	return reflect.DeepEqual(indexes(a), indexes(b))
+}
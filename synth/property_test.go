@@ -0,0 +1,92 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package synth_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/google/go-eventlog/extract"
+	"github.com/google/go-eventlog/synth"
+	"github.com/google/go-eventlog/tpmeventlog"
+)
+
+func TestRandomBootsReplay(t *testing.T) {
+	property := func(boot synth.Boot) bool {
+		log, err := synth.Generate(boot)
+		if err != nil {
+			t.Logf("Generate(): %v", err)
+			return false
+		}
+		if err := synth.CheckReplay(log); err != nil {
+			t.Logf("CheckReplay(): %v", err)
+			return false
+		}
+		for _, bank := range log.Banks {
+			if _, err := tpmeventlog.ReplayAndExtract(log.Raw, bank, extract.Opts{Loader: extract.AutoDetect}); err != nil {
+				t.Logf("ReplayAndExtract(%v): %v", bank.TCGHashAlgo, err)
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 50}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRandomBootsMutations(t *testing.T) {
+	for seed := int64(0); seed < 3; seed++ {
+		log, err := synth.Generate(synth.RandomBoot(rand.New(rand.NewSource(seed))))
+		if err != nil {
+			t.Fatalf("Generate(): %v", err)
+		}
+		for offset := range log.Raw {
+			for _, mask := range []byte{0x01, 0x80} {
+				if err := synth.CheckMutation(log, offset, mask); err != nil {
+					t.Errorf("seed %d: CheckMutation(): %v", seed, err)
+				}
+			}
+		}
+	}
+}
+
+func TestRandomBootDeterministic(t *testing.T) {
+	for seed := int64(0); seed < 4; seed++ {
+		var raws [2][]byte
+		for i := range raws {
+			log, err := synth.Generate(synth.RandomBoot(rand.New(rand.NewSource(seed))))
+			if err != nil {
+				t.Fatalf("Generate(): %v", err)
+			}
+			raws[i] = log.Raw
+		}
+		if !bytes.Equal(raws[0], raws[1]) {
+			t.Errorf("seed %d: RandomBoot() logs differ for the same seed", seed)
+		}
+	}
+}
+
+func TestCheckMutationOutOfRange(t *testing.T) {
+	log, err := synth.Generate(synth.Boot{})
+	if err != nil {
+		t.Fatalf("Generate(): %v", err)
+	}
+	if err := synth.CheckMutation(log, len(log.Raw), 0xff); err == nil {
+		t.Errorf("CheckMutation(out of range) = nil error, want error")
+	}
+}
@@ -0,0 +1,475 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package synth synthesizes realistic OVMF-style TCG PC Client event logs
+// from a high-level description of a boot, for building regression fixtures
+// across many firmware, Secure Boot, and bootloader configurations.
+//
+// The synthesized logs are not measured by real firmware, so they must only
+// be used as test fixtures.
+package synth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf16"
+
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
+)
+
+// Vendor GUIDs of the measured UEFI variables.
+const (
+	efiGlobalVariableGUID     = "8be4df61-93ca-11d2-aa0d-00e098032b8c"
+	imageSecurityDatabaseGUID = "d719b2cb-3d3a-4596-a3bc-dad00e67656f"
+)
+
+const defaultFirmwareVersion = "OVMF"
+
+// Boot describes a boot to synthesize an event log for.
+type Boot struct {
+	// Hashes are the hash algorithms of the PCR banks the log has digests
+	// for, e.g., SHA-1, SHA-256, or SHA-384. If empty, the log has SHA-1 and
+	// SHA-256 digests.
+	Hashes []crypto.Hash
+	// FirmwareVersion is the S-CRTM version string measured into PCR0.
+	// If empty, "OVMF" is used.
+	FirmwareVersion string
+	// Firmware is the platform firmware volume measured into PCR0.
+	Firmware []byte
+	// SecureBoot is the Secure Boot configuration measured into PCR7.
+	SecureBoot SecureBoot
+	// BootApps are the EFI applications loaded from the boot option, in
+	// order, e.g., shim, GRUB, and an EFI stub kernel. They are measured into
+	// PCR4.
+	BootApps []EFIApp
+	// GRUB is the GRUB configuration and commands measured into PCR8 and
+	// PCR9, or nil if GRUB is not used.
+	GRUB *GRUB
+	// CC synthesizes a Confidential Computing event log, as measured by
+	// confidential VM firmware, e.g., for Intel TDX, Arm CCA, or RISC-V CoVE.
+	// Events are measured into the CC measurement registers the UEFI CC
+	// measurement protocol maps their PCRs to: CC MR 1 for PCR1 and PCR7,
+	// CC MR 2 for PCR2 to PCR6, and CC MR 3 for PCR8 to PCR15. The firmware
+	// is part of the static measurement, so PCR0 events are not logged.
+	CC bool
+}
+
+// SecureBoot describes the Secure Boot variables.
+type SecureBoot struct {
+	// Enabled is the value of the SecureBoot variable.
+	Enabled bool
+	// PK, KEK, and DB are the certificates in the Secure Boot databases.
+	// Empty databases are measured as empty variables, as OVMF does when no
+	// keys are enrolled.
+	PK  []x509.Certificate
+	KEK []x509.Certificate
+	DB  []x509.Certificate
+	// DBX holds the SHA-256 hashes of revoked images.
+	DBX [][]byte
+	// Owner is the owner GUID of the database entries and authorities, e.g.,
+	// "77fa9abd-0359-4d32-bd60-28f4e78f784b". If empty, the zero GUID is used.
+	Owner string
+}
+
+// EFIApp is an EFI application loaded from the boot option.
+type EFIApp struct {
+	// Path is the file path of the application on the EFI system partition,
+	// e.g., `\EFI\BOOT\BOOTX64.EFI`.
+	Path string
+	// Image is the application image. Its measured digest is the digest of
+	// the whole image, rather than an Authenticode digest.
+	Image []byte
+	// Authority is the db certificate that verified the application, or nil
+	// if it was not verified. Each authority is measured into PCR7 before the
+	// first application it verified.
+	Authority *x509.Certificate
+}
+
+// GRUB describes the measurements of GRUB.
+type GRUB struct {
+	// Files are the files GRUB read before running commands, e.g.,
+	// grub.cfg, measured into PCR9.
+	Files []File
+	// Commands are the GRUB commands run before loading the kernel, e.g.,
+	// "set root=hd0,gpt2", measured into PCR8.
+	Commands []string
+	// Kernel is the Linux kernel GRUB loads, or nil if it does not load one.
+	Kernel *Kernel
+}
+
+// File is a file read by GRUB.
+type File struct {
+	// Path is the path GRUB read the file from, e.g., "/boot/grub/grub.cfg".
+	Path    string
+	Content []byte
+}
+
+// Kernel describes a Linux kernel and initramfs loaded by GRUB.
+type Kernel struct {
+	// Path is the path of the kernel image, e.g., "/vmlinuz".
+	Path  string
+	Image []byte
+	// CommandLine holds the kernel arguments. GRUB measures the kernel
+	// command line as Path followed by the arguments.
+	CommandLine string
+	// InitrdPath is the path of the initramfs, or empty if there is none.
+	InitrdPath string
+	Initrd     []byte
+}
+
+// Log is a synthesized event log.
+type Log struct {
+	// Raw is the crypto agile event log.
+	Raw []byte
+	// Banks hold the PCR values the event log replays to, in the order of
+	// Boot.Hashes. Only PCRs with events are included. For CC event logs,
+	// the banks hold the CC measurement register values by CC MR index.
+	Banks []register.PCRBank
+}
+
+// Generate synthesizes the event log of the boot, in the order OVMF measures
+// events: the firmware and Secure Boot variables, the boot variables, the
+// separators at ReadyToBoot, the boot applications, GRUB, and finally
+// ExitBootServices.
+func Generate(boot Boot) (*Log, error) {
+	hashes := boot.Hashes
+	if len(hashes) == 0 {
+		hashes = []crypto.Hash{crypto.SHA1, crypto.SHA256}
+	}
+	b, err := newBuilder(hashes)
+	if err != nil {
+		return nil, err
+	}
+	b.cc = boot.CC
+
+	if !boot.CC {
+		version := boot.FirmwareVersion
+		if version == "" {
+			version = defaultFirmwareVersion
+		}
+		b.measure(0, tcg.SCRTMVersion, utf16Bytes(version))
+		if boot.Firmware != nil {
+			var blob bytes.Buffer
+			binary.Write(&blob, binary.LittleEndian, uint64(0xffc00000))
+			binary.Write(&blob, binary.LittleEndian, uint64(len(boot.Firmware)))
+			b.measureDigest(0, tcg.EFIPlatformFirmwareBlob, blob.Bytes(), boot.Firmware)
+		}
+	}
+	if err := b.secureBootVariables(boot.SecureBoot); err != nil {
+		return nil, err
+	}
+	if err := b.bootVariables(boot.BootApps); err != nil {
+		return nil, err
+	}
+
+	b.measure(4, tcg.EFIAction, []byte(tcg.CallingEFIApplication))
+	// Registers shared by several PCRs only get one separator.
+	separated := make(map[int]bool)
+	for index := 0; index <= 7; index++ {
+		if mr := b.mrIndex(index); !separated[mr] {
+			separated[mr] = true
+			b.measure(index, tcg.Separator, []byte{0, 0, 0, 0})
+		}
+	}
+
+	measuredAuthorities := make(map[string]bool)
+	for _, app := range boot.BootApps {
+		if app.Authority != nil && !measuredAuthorities[string(app.Authority.Raw)] {
+			measuredAuthorities[string(app.Authority.Raw)] = true
+			if err := b.authority(app.Authority, boot.SecureBoot.Owner); err != nil {
+				return nil, err
+			}
+		}
+		image, err := imageLoadEvent(app)
+		if err != nil {
+			return nil, err
+		}
+		b.measureDigest(4, tcg.EFIBootServicesApplication, image, app.Image)
+	}
+	if boot.GRUB != nil {
+		b.grub(*boot.GRUB)
+	}
+
+	b.measure(5, tcg.EFIAction, []byte(tcg.ExitBootServicesInvocation))
+	b.measure(5, tcg.EFIAction, []byte(tcg.ExitBootServicesSuccess))
+	return b.log()
+}
+
+// builder accumulates crypto agile events and the PCR values they extend.
+type builder struct {
+	hashes []crypto.Hash
+	algIDs []uint16
+	events bytes.Buffer
+	// pcrs holds the PCR values of each bank, by hash and PCR index, or CC
+	// MR index if cc is set.
+	pcrs map[crypto.Hash]map[int][]byte
+	// cc measures events into the CC measurement registers of their PCRs.
+	cc bool
+}
+
+func newBuilder(hashes []crypto.Hash) (*builder, error) {
+	b := &builder{hashes: hashes, pcrs: make(map[crypto.Hash]map[int][]byte)}
+	for _, hash := range hashes {
+		if !hash.Available() {
+			return nil, fmt.Errorf("hash %v is not available", hash)
+		}
+		algID, err := register.HashTPMAlg(hash)
+		if err != nil {
+			return nil, err
+		}
+		if register.HashAlg(algID).CryptoHash() != hash {
+			return nil, fmt.Errorf("event log replay does not support hash %v", hash)
+		}
+		b.algIDs = append(b.algIDs, algID)
+		b.pcrs[hash] = make(map[int][]byte)
+	}
+	return b, nil
+}
+
+// mrIndex returns the register index that events for the PCR are logged in.
+func (b *builder) mrIndex(pcr int) int {
+	if !b.cc {
+		return pcr
+	}
+	switch {
+	case pcr <= 1 || pcr == 7:
+		return 1
+	case pcr <= 6:
+		return 2
+	case pcr <= 15:
+		return 3
+	}
+	return pcr
+}
+
+// measure logs an event whose digest is the digest of its data.
+func (b *builder) measure(index int, typ tcg.EventType, data []byte) {
+	b.measureDigest(index, typ, data, data)
+}
+
+// measureDigest logs an event with data, whose digest is the digest of
+// measured.
+func (b *builder) measureDigest(index int, typ tcg.EventType, data []byte, measured []byte) {
+	index = b.mrIndex(index)
+	binary.Write(&b.events, binary.LittleEndian, uint32(index))
+	binary.Write(&b.events, binary.LittleEndian, uint32(typ))
+	binary.Write(&b.events, binary.LittleEndian, uint32(len(b.hashes)))
+	for i, hash := range b.hashes {
+		h := hash.New()
+		h.Write(measured)
+		digest := h.Sum(nil)
+		binary.Write(&b.events, binary.LittleEndian, b.algIDs[i])
+		b.events.Write(digest)
+
+		pcr, ok := b.pcrs[hash][index]
+		if !ok {
+			pcr = make([]byte, hash.Size())
+		}
+		h = hash.New()
+		h.Write(pcr)
+		h.Write(digest)
+		b.pcrs[hash][index] = h.Sum(nil)
+	}
+	binary.Write(&b.events, binary.LittleEndian, uint32(len(data)))
+	b.events.Write(data)
+}
+
+func (b *builder) variable(index int, typ tcg.EventType, vendorGUID string, name string, data []byte) error {
+	v, err := tcg.NewUEFIVariableData(vendorGUID, name, data)
+	if err != nil {
+		return err
+	}
+	encoded, err := v.Encode()
+	if err != nil {
+		return fmt.Errorf("encoding variable %q: %v", name, err)
+	}
+	b.measure(index, typ, encoded)
+	return nil
+}
+
+func (b *builder) secureBootVariables(sb SecureBoot) error {
+	enabled := []byte{0}
+	if sb.Enabled {
+		enabled[0] = 1
+	}
+	if err := b.variable(7, tcg.EFIVariableDriverConfig, efiGlobalVariableGUID, "SecureBoot", enabled); err != nil {
+		return err
+	}
+	databases := []struct {
+		vendorGUID string
+		name       string
+		certs      []x509.Certificate
+		hashes     [][]byte
+	}{
+		{efiGlobalVariableGUID, "PK", sb.PK, nil},
+		{efiGlobalVariableGUID, "KEK", sb.KEK, nil},
+		{imageSecurityDatabaseGUID, "db", sb.DB, nil},
+		{imageSecurityDatabaseGUID, "dbx", nil, sb.DBX},
+	}
+	for _, db := range databases {
+		data, err := tcg.EncodeEFISignatureList(db.certs, db.hashes, sb.Owner)
+		if err != nil {
+			return fmt.Errorf("encoding %s: %v", db.name, err)
+		}
+		if err := b.variable(7, tcg.EFIVariableDriverConfig, db.vendorGUID, db.name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bootVariables measures BootOrder and a Boot#### load option for the first
+// boot application.
+func (b *builder) bootVariables(apps []EFIApp) error {
+	if len(apps) == 0 {
+		return nil
+	}
+	if err := b.variable(1, tcg.EFIVariableBoot, efiGlobalVariableGUID, "BootOrder", []byte{0, 0}); err != nil {
+		return err
+	}
+	filePath := filePathDevicePath(apps[0].Path)
+	var option bytes.Buffer
+	// LOAD_OPTION_ACTIVE.
+	binary.Write(&option, binary.LittleEndian, uint32(1))
+	binary.Write(&option, binary.LittleEndian, uint16(len(filePath)))
+	option.Write(utf16Bytes("UEFI Misc Device"))
+	option.Write(filePath)
+	return b.variable(1, tcg.EFIVariableBoot, efiGlobalVariableGUID, "Boot0000", option.Bytes())
+}
+
+func (b *builder) authority(cert *x509.Certificate, owner string) error {
+	data, err := tcg.EncodeEFISignatureData(owner, cert.Raw)
+	if err != nil {
+		return err
+	}
+	return b.variable(7, tcg.EFIVariableAuthority, imageSecurityDatabaseGUID, "db", data)
+}
+
+func (b *builder) grub(grub GRUB) {
+	file := func(path string, content []byte) {
+		b.measureDigest(9, tcg.Ipl, append([]byte(path), 0), content)
+	}
+	command := func(prefix, command string) {
+		b.measureDigest(8, tcg.Ipl, append([]byte(prefix+command), 0), []byte(command))
+	}
+	for _, f := range grub.Files {
+		file(f.Path, f.Content)
+	}
+	for _, c := range grub.Commands {
+		command("grub_cmd: ", c)
+	}
+	if grub.Kernel == nil {
+		return
+	}
+	kernel := grub.Kernel
+	cmdline := kernel.Path
+	if kernel.CommandLine != "" {
+		cmdline += " " + kernel.CommandLine
+	}
+	command("grub_cmd: ", "linux "+cmdline)
+	file(kernel.Path, kernel.Image)
+	command("kernel_cmdline: ", cmdline)
+	if kernel.InitrdPath != "" {
+		command("grub_cmd: ", "initrd "+kernel.InitrdPath)
+		file(kernel.InitrdPath, kernel.Initrd)
+	}
+}
+
+func (b *builder) log() (*Log, error) {
+	var out bytes.Buffer
+	// The Spec ID event is in the SHA-1 log format.
+	var specID bytes.Buffer
+	specID.Write([]byte("Spec ID Event03\x00"))
+	binary.Write(&specID, binary.LittleEndian, struct {
+		PlatformClass uint32
+		VersionMinor  uint8
+		VersionMajor  uint8
+		Errata        uint8
+		UintnSize     uint8
+		NumAlgs       uint32
+	}{VersionMajor: 2, UintnSize: 2, NumAlgs: uint32(len(b.hashes))})
+	for i, hash := range b.hashes {
+		binary.Write(&specID, binary.LittleEndian, b.algIDs[i])
+		binary.Write(&specID, binary.LittleEndian, uint16(hash.Size()))
+	}
+	// No vendor info.
+	specID.WriteByte(0)
+
+	binary.Write(&out, binary.LittleEndian, uint32(0))
+	binary.Write(&out, binary.LittleEndian, uint32(tcg.NoAction))
+	out.Write(make([]byte, crypto.SHA1.Size()))
+	binary.Write(&out, binary.LittleEndian, uint32(specID.Len()))
+	out.Write(specID.Bytes())
+	out.Write(b.events.Bytes())
+
+	log := &Log{Raw: out.Bytes()}
+	for _, hash := range b.hashes {
+		algo, err := pb.HashAlgoFromCryptoHash(hash)
+		if err != nil {
+			return nil, err
+		}
+		bank := register.PCRBank{TCGHashAlgo: algo}
+		for index, digest := range b.pcrs[hash] {
+			bank.PCRs = append(bank.PCRs, register.PCR{Index: index, Digest: digest, DigestAlg: hash})
+		}
+		sort.Slice(bank.PCRs, func(i, j int) bool { return bank.PCRs[i].Index < bank.PCRs[j].Index })
+		log.Banks = append(log.Banks, bank)
+	}
+	return log, nil
+}
+
+// imageLoadEvent encodes the EFI_IMAGE_LOAD_EVENT of an application loaded
+// from a file path.
+func imageLoadEvent(app EFIApp) ([]byte, error) {
+	if app.Path == "" {
+		return nil, errors.New("EFI application has no path")
+	}
+	devicePath := filePathDevicePath(app.Path)
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, tcg.EFIImageLoadHeader{
+		Length:        uint64(len(app.Image)),
+		DevicePathLen: uint64(len(devicePath)),
+	})
+	out.Write(devicePath)
+	return out.Bytes(), nil
+}
+
+// filePathDevicePath encodes a device path with a single media file path
+// node.
+func filePathDevicePath(path string) []byte {
+	var out bytes.Buffer
+	name := utf16Bytes(path)
+	out.WriteByte(byte(tcg.MediaDevice))
+	// Media file path subtype.
+	out.WriteByte(4)
+	binary.Write(&out, binary.LittleEndian, uint16(4+len(name)))
+	out.Write(name)
+	// End of the entire device path.
+	out.Write([]byte{byte(tcg.EndDeviceArrayMarker), 0xff, 4, 0})
+	return out.Bytes()
+}
+
+// utf16Bytes encodes s as a null-terminated UTF-16 string.
+func utf16Bytes(s string) []byte {
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, append(utf16.Encode([]rune(s)), 0))
+	return out.Bytes()
+}
@@ -0,0 +1,240 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package synth_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-eventlog/extract"
+	pb "github.com/google/go-eventlog/proto/state"
+	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/synth"
+	"github.com/google/go-eventlog/tcg"
+	"github.com/google/go-eventlog/testdata"
+	"github.com/google/go-eventlog/tpmeventlog"
+)
+
+// rhel8SecureBoot returns the Secure Boot configuration of the RHEL 8 event
+// log, so the synthesized logs use real certificates.
+func rhel8SecureBoot(t *testing.T) (*extract.SecurebootState, synth.SecureBoot) {
+	t.Helper()
+	el, err := tcg.ParseEventLog(testdata.Rhel8EventLog, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, err := extract.ParseSecurebootState(el.Events(register.HashSHA256), extract.TPMRegisterConfig, extract.Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sb, synth.SecureBoot{
+		Enabled: true,
+		PK:      sb.PlatformKeys,
+		KEK:     sb.ExchangeKeys,
+		DB:      sb.PermittedKeys,
+		DBX:     sb.ForbiddenHashes,
+		Owner:   "77fa9abd-0359-4d32-bd60-28f4e78f784b",
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	sb, sbConfig := rhel8SecureBoot(t)
+	authority := &sb.PostSeparatorAuthority[0]
+	shim := []byte("shim image")
+	grub := []byte("grub image")
+	boot := synth.Boot{
+		Hashes:          []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384},
+		FirmwareVersion: "edk2-stable202211",
+		Firmware:        []byte("firmware volume"),
+		SecureBoot:      sbConfig,
+		BootApps: []synth.EFIApp{
+			{Path: `\EFI\BOOT\BOOTX64.EFI`, Image: shim, Authority: authority},
+			{Path: `\EFI\ubuntu\grubx64.efi`, Image: grub, Authority: authority},
+		},
+		GRUB: &synth.GRUB{
+			Files:    []synth.File{{Path: "/boot/grub/grub.cfg", Content: []byte("linux /vmlinuz")}},
+			Commands: []string{"set root=hd0,gpt2"},
+			Kernel: &synth.Kernel{
+				Path:        "/vmlinuz",
+				Image:       []byte("kernel image"),
+				CommandLine: "root=/dev/sda2 ro console=ttyS0",
+				InitrdPath:  "/initrd.img",
+				Initrd:      []byte("initrd image"),
+			},
+		},
+	}
+	log, err := synth.Generate(boot)
+	if err != nil {
+		t.Fatalf("Generate(): %v", err)
+	}
+	if len(log.Banks) != len(boot.Hashes) {
+		t.Fatalf("Generate() = %d banks, want %d", len(log.Banks), len(boot.Hashes))
+	}
+
+	var states []*pb.FirmwareLogState
+	for _, bank := range log.Banks {
+		state, err := tpmeventlog.ReplayAndExtract(log.Raw, bank, extract.Opts{Loader: extract.AutoDetect})
+		if err != nil {
+			t.Fatalf("ReplayAndExtract(%v): %v", bank.TCGHashAlgo, err)
+		}
+		states = append(states, state)
+	}
+	state, err := extract.MergeFirmwareLogStates(states...)
+	if err != nil {
+		t.Fatalf("MergeFirmwareLogStates(): %v", err)
+	}
+
+	if got := state.GetPlatform().GetScrtmVersionId(); !bytes.Contains(got, []byte{'e', 0, 'd', 0, 'k', 0}) {
+		t.Errorf("got SCRTM version %x, want edk2-stable202211", got)
+	}
+	if !state.GetSecureBoot().GetEnabled() {
+		t.Errorf("got Secure Boot disabled, want enabled")
+	}
+	if got, want := len(state.GetSecureBoot().GetDb().GetCerts()), len(sbConfig.DB); got != want {
+		t.Errorf("got %d db certs, want %d", got, want)
+	}
+	if got, want := len(state.GetSecureBoot().GetDbx().GetHashes()), len(sbConfig.DBX); got != want {
+		t.Errorf("got %d dbx hashes, want %d", got, want)
+	}
+	if got := len(state.GetSecureBoot().GetAuthority().GetCerts()); got != 1 {
+		t.Errorf("got %d authorities, want 1", got)
+	}
+
+	shimDigest := crypto.SHA384.New()
+	shimDigest.Write(shim)
+	if apps := state.GetEfi().GetApps(); len(apps) != 2 || !bytes.Equal(apps[0].GetDigest(), shimDigest.Sum(nil)) {
+		t.Errorf("got EFI apps %v, want shim and GRUB", apps)
+	}
+
+	wantCommands := []string{
+		"grub_cmd: set root=hd0,gpt2\x00",
+		"grub_cmd: linux /vmlinuz root=/dev/sda2 ro console=ttyS0\x00",
+		"kernel_cmdline: /vmlinuz root=/dev/sda2 ro console=ttyS0\x00",
+		"grub_cmd: initrd /initrd.img\x00",
+	}
+	if diff := cmp.Diff(wantCommands, state.GetGrub().GetCommands()); diff != "" {
+		t.Errorf("got unexpected GRUB commands (-want +got):\n%s", diff)
+	}
+	if got, want := len(state.GetGrub().GetFiles()), 3; got != want {
+		t.Errorf("got %d GRUB files, want %d", got, want)
+	}
+	if got, want := state.GetLinuxKernel().GetCommandLine(), "/vmlinuz root=/dev/sda2 ro console=ttyS0\x00"; got != want {
+		t.Errorf("got kernel command line %q, want %q", got, want)
+	}
+}
+
+func TestGenerateSecureBootDisabled(t *testing.T) {
+	log, err := synth.Generate(synth.Boot{
+		BootApps: []synth.EFIApp{{Path: `\EFI\BOOT\BOOTX64.EFI`, Image: []byte("grub image")}},
+	})
+	if err != nil {
+		t.Fatalf("Generate(): %v", err)
+	}
+	if got := log.Banks[1].TCGHashAlgo; got != pb.HashAlgo_SHA256 {
+		t.Fatalf("Generate() = second bank %v, want SHA256", got)
+	}
+	state, err := tpmeventlog.ReplayAndExtract(log.Raw, log.Banks[1], extract.Opts{Loader: extract.UnsupportedLoader})
+	if err != nil {
+		t.Fatalf("ReplayAndExtract(): %v", err)
+	}
+	if state.GetSecureBoot().GetEnabled() {
+		t.Errorf("got Secure Boot enabled, want disabled")
+	}
+	image := sha256.Sum256([]byte("grub image"))
+	if apps := state.GetEfi().GetApps(); len(apps) != 1 || !bytes.Equal(apps[0].GetDigest(), image[:]) {
+		t.Errorf("got EFI apps %v, want the GRUB image digest", apps)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	if _, err := synth.Generate(synth.Boot{BootApps: []synth.EFIApp{{Image: []byte("no path")}}}); err == nil {
+		t.Errorf("Generate(app without path) = nil error, want error")
+	}
+	if _, err := synth.Generate(synth.Boot{Hashes: []crypto.Hash{crypto.SHA512}}); err == nil {
+		t.Errorf("Generate(SHA-512) = nil error, want error")
+	}
+	if _, err := synth.Generate(synth.Boot{SecureBoot: synth.SecureBoot{Owner: "not a GUID"}}); err == nil {
+		t.Errorf("Generate(invalid owner) = nil error, want error")
+	}
+}
+
+func TestGenerateCC(t *testing.T) {
+	sb, sbConfig := rhel8SecureBoot(t)
+	for _, tc := range []struct {
+		name           string
+		hash           crypto.Hash
+		registerCfg    extract.RegisterConfig
+		wantTechnology pb.GCEConfidentialTechnology
+	}{
+		{"TDX", crypto.SHA384, extract.RTMRRegisterConfig, pb.GCEConfidentialTechnology_INTEL_TDX},
+		{"CCA", crypto.SHA256, extract.CCARegisterConfig, pb.GCEConfidentialTechnology_ARM_CCA},
+		{"CoVE", crypto.SHA384, extract.CoVERegisterConfig, pb.GCEConfidentialTechnology_RISCV_COVE},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			log, err := synth.Generate(synth.Boot{
+				Hashes:     []crypto.Hash{tc.hash},
+				CC:         true,
+				SecureBoot: sbConfig,
+				BootApps:   []synth.EFIApp{{Path: `\EFI\BOOT\BOOTAA64.EFI`, Image: []byte("grub image"), Authority: &sb.PostSeparatorAuthority[0]}},
+				GRUB: &synth.GRUB{
+					Kernel: &synth.Kernel{Path: "/vmlinuz", Image: []byte("kernel image"), CommandLine: "console=ttyAMA0"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Generate(): %v", err)
+			}
+			for _, mr := range log.Banks[0].PCRs {
+				if mr.Index < 1 || mr.Index > 3 {
+					t.Errorf("Generate() = events in CC MR %d, want CC MRs 1 to 3", mr.Index)
+				}
+			}
+			mrs := log.Banks[0].MRs()
+			if tc.registerCfg.Name == extract.CCARegisterConfig.Name {
+				// Replay against the REMs the CC MRs map to.
+				var rems register.REMBank
+				for _, mr := range log.Banks[0].PCRs {
+					rems.REMs = append(rems.REMs, register.REM{Index: mr.Index - 1, Digest: mr.Digest, DigestAlg: tc.hash})
+				}
+				mrs = rems.MRs()
+			}
+			events, err := tcg.ParseAndReplay(log.Raw, mrs, tcg.ParseOpts{})
+			if err != nil {
+				t.Fatalf("ParseAndReplay(): %v", err)
+			}
+			state, err := extract.FirmwareLogState(events, tc.hash, tc.registerCfg, extract.Opts{Loader: extract.GRUB})
+			if err != nil {
+				t.Fatalf("FirmwareLogState(): %v", err)
+			}
+			if got := state.GetPlatform().GetTechnology(); got != tc.wantTechnology {
+				t.Errorf("got technology %v, want %v", got, tc.wantTechnology)
+			}
+			if state.GetLogType() != pb.LogType_LOG_TYPE_CC {
+				t.Errorf("got log type %v, want LOG_TYPE_CC", state.GetLogType())
+			}
+			if !state.GetSecureBoot().GetEnabled() {
+				t.Errorf("got Secure Boot disabled, want enabled")
+			}
+			if got := len(state.GetEfi().GetApps()); got != 1 {
+				t.Errorf("got %d EFI apps, want 1", got)
+			}
+			if got, want := state.GetLinuxKernel().GetCommandLine(), "/vmlinuz console=ttyAMA0\x00"; got != want {
+				t.Errorf("got kernel command line %q, want %q", got, want)
+			}
+		})
+	}
+}
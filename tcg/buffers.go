@@ -0,0 +1,98 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import "sync"
+
+// maxPooledDigests bounds the digests per event taken from ParseBuffers. The
+// digest count is untrusted, so events with more digests are allocated as
+// they are read.
+const maxPooledDigests = 8
+
+// ParseBuffers holds memory reused across event log parses, to reduce
+// allocations in verifiers parsing many logs. Set ParseOpts.Buffers to parse
+// into it.
+//
+// The EventLog parsed with the buffers, and the events and event data from
+// it, share the buffers' memory. They must not be used after the buffers are
+// used for another parse, or released. This includes state extracted from
+// the events, e.g., by extract.FirmwareLogState, whose byte fields share the
+// event data and digests: copy it with proto.Clone before releasing the
+// buffers. tpmeventlog.ReplayAndExtract and ccel.ReplayAndExtract return
+// copies when extract.Opts.ParseOpts has the buffers.
+type ParseBuffers struct {
+	// data backs the event data and digests. Both are read from the log, so
+	// the log size bounds their total size.
+	data      []byte
+	digests   []digest
+	rawEvents []rawEvent
+}
+
+var parseBuffersPool = sync.Pool{
+	New: func() any { return &ParseBuffers{} },
+}
+
+// GetParseBuffers returns ParseBuffers from a shared pool. Call Release once
+// the EventLog parsed with them is no longer used.
+func GetParseBuffers() *ParseBuffers {
+	return parseBuffersPool.Get().(*ParseBuffers)
+}
+
+// Release returns the buffers to the shared pool.
+func (b *ParseBuffers) Release() {
+	b.reset(0)
+	parseBuffersPool.Put(b)
+}
+
+// reset prepares the buffers to parse a log of logSize bytes.
+func (b *ParseBuffers) reset(logSize int) {
+	if cap(b.data) < logSize {
+		b.data = make([]byte, 0, logSize)
+	}
+	b.data = b.data[:0]
+	b.digests = b.digests[:0]
+	b.rawEvents = b.rawEvents[:0]
+}
+
+// bytes returns n bytes for event data or a digest. If b is nil, or out of
+// space, the bytes are allocated.
+func (b *ParseBuffers) bytes(n int) []byte {
+	if b == nil || cap(b.data)-len(b.data) < n {
+		return make([]byte, n)
+	}
+	start := len(b.data)
+	b.data = b.data[:start+n]
+	return b.data[start : start+n : start+n]
+}
+
+// digestSlice returns an empty slice with capacity for the digests of an
+// event. If b is nil, or the count is too large, it returns nil.
+func (b *ParseBuffers) digestSlice(count uint32) []digest {
+	if b == nil || count > maxPooledDigests {
+		return nil
+	}
+	n := int(count)
+	if cap(b.digests)-len(b.digests) < n {
+		// Earlier events keep the old backing array.
+		newCap := 2 * cap(b.digests)
+		if newCap < 64 {
+			newCap = 64
+		}
+		b.digests = make([]digest, 0, newCap)
+	}
+	start := len(b.digests)
+	b.digests = b.digests[:start+n]
+	return b.digests[start : start : start+n]
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"fmt"
+)
+
+// numPreOSPCRs is the number of PCRs capped on a pre-OS measurement error,
+// PCRs 0-7.
+const numPreOSPCRs = 8
+
+// errorSeparatorValues are the separator data measured to cap a register on
+// an error: FFFFFFFFh, as in the TCG PC Client Platform Firmware Profile
+// Specification, or 00000001h, as measured by some EDK II versions.
+var errorSeparatorValues = [][]byte{
+	{0xff, 0xff, 0xff, 0xff},
+	{0x01, 0x00, 0x00, 0x00},
+}
+
+// Capping is the capping of PCRs 0-7 with error separators. Firmware that
+// fails to measure the boot, e.g., EDK II on a TPM error, extends an error
+// separator into each pre-OS PCR and stops measuring, so that the PCR values
+// cannot match those of a measured boot.
+//
+// A capped event log replays, but it does not describe the boot: the pre-OS
+// PCRs hold no separators, Secure Boot state, or boot applications.
+type Capping struct {
+	// Nums are the event numbers of the capping separators, in log order.
+	Nums []uint32
+	// Data is the error separator data, e.g., FFFFFFFFh.
+	Data []byte
+}
+
+// FindCapping returns the capping of PCRs 0-7 in verified events, e.g., those
+// returned by ParseAndReplay, or nil if the PCRs were not capped. hash is the
+// hash algorithm of the bank the events were replayed against. Error
+// separators that do not cap all of PCRs 0-7, e.g., as measured by OVMF in
+// place of a register's separator, are not a capping.
+//
+// It returns an error if the capping is invalid: a capping separator's digest
+// does not match its data, the PCRs are capped with different data, or an
+// event, e.g., a second separator, is measured into a PCR after it was
+// capped.
+func FindCapping(events []Event, hash crypto.Hash) (*Capping, error) {
+	caps := make(map[uint32]Event, numPreOSPCRs)
+	for _, event := range events {
+		idx := event.MRIndex()
+		if _, ok := caps[idx]; !ok && idx < numPreOSPCRs && event.Type == Separator && isErrorSeparator(event.Data) {
+			caps[idx] = event
+		}
+	}
+	if len(caps) < numPreOSPCRs {
+		return nil, nil
+	}
+
+	var capping Capping
+	for _, event := range events {
+		idx := event.MRIndex()
+		capEvent, ok := caps[idx]
+		switch {
+		case !ok:
+			continue
+		case event.Num() > capEvent.Num():
+			return nil, fmt.Errorf("event %d measured into PCR%d after it was capped at event %d", event.Num(), idx, capEvent.Num())
+		case event.Num() < capEvent.Num():
+			continue
+		}
+		if capping.Data != nil && !bytes.Equal(event.Data, capping.Data) {
+			return nil, fmt.Errorf("event %d caps PCR%d with separator data %x, but other PCRs were capped with %x", event.Num(), idx, event.Data, capping.Data)
+		}
+		if !separatorDigestMatches(event, hash) {
+			return nil, fmt.Errorf("event %d: capping separator digest for PCR%d does not match its data", event.Num(), idx)
+		}
+		capping.Nums = append(capping.Nums, event.Num())
+		capping.Data = event.Data
+	}
+	return &capping, nil
+}
+
+// separatorDigestMatches reports whether the digest of a separator event
+// matches its data hashed with the bank's hash algorithm. The algorithm can
+// not be chosen by the digest size, e.g., SHA-256 and SHA3-256 digests have
+// the same size.
+func separatorDigestMatches(event Event, hash crypto.Hash) bool {
+	if !hash.Available() {
+		return false
+	}
+	h := hash.New()
+	h.Write(event.Data)
+	return subtle.ConstantTimeCompare(h.Sum(nil), event.Digest) == 1
+}
+
+func isErrorSeparator(data []byte) bool {
+	for _, value := range errorSeparatorValues {
+		if bytes.Equal(data, value) {
+			return true
+		}
+	}
+	return false
+}
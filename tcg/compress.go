@@ -0,0 +1,143 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxDecompressedLogLen is the maximum accepted byte length of a decompressed
+// event log. This value should be larger than any reasonable value.
+const maxDecompressedLogLen = 64 << 20
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// CompressionFormat is a compression format for raw event logs.
+type CompressionFormat int
+
+// Supported compression formats.
+const (
+	// Uncompressed is a raw event log.
+	Uncompressed CompressionFormat = iota
+	// Gzip is an event log compressed with gzip (RFC 1952).
+	Gzip
+	// Zstd is an event log compressed with Zstandard (RFC 8878).
+	Zstd
+)
+
+// String returns a human-friendly representation of the compression format.
+func (f CompressionFormat) String() string {
+	switch f {
+	case Uncompressed:
+		return "Uncompressed"
+	case Gzip:
+		return "Gzip"
+	case Zstd:
+		return "Zstd"
+	}
+	return fmt.Sprintf("CompressionFormat<%d>", int(f))
+}
+
+// DetectCompression detects the compression format of a raw event log by its
+// magic number.
+//
+// Neither magic number is a valid start of a TCG event log, as it would
+// encode an out-of-range PCR index in the first event.
+func DetectCompression(rawEventLog []byte) CompressionFormat {
+	switch {
+	case bytes.HasPrefix(rawEventLog, gzipMagic):
+		return Gzip
+	case bytes.HasPrefix(rawEventLog, zstdMagic):
+		return Zstd
+	default:
+		return Uncompressed
+	}
+}
+
+// Decompress decompresses a raw event log compressed in any supported format.
+// Uncompressed event logs are returned unchanged.
+func Decompress(rawEventLog []byte) ([]byte, error) {
+	var r io.Reader
+	switch format := DetectCompression(rawEventLog); format {
+	case Uncompressed:
+		return rawEventLog, nil
+	case Gzip:
+		gr, err := gzip.NewReader(bytes.NewReader(rawEventLog))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip header: %v", err)
+		}
+		defer gr.Close()
+		r = gr
+	case Zstd:
+		zr, err := zstd.NewReader(bytes.NewReader(rawEventLog), zstd.WithDecoderMaxMemory(maxDecompressedLogLen))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %v", err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("unsupported compression format %v", format)
+	}
+
+	decompressed, err := io.ReadAll(io.LimitReader(r, maxDecompressedLogLen+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress event log: %v", err)
+	}
+	if len(decompressed) > maxDecompressedLogLen {
+		return nil, fmt.Errorf("decompressed event log too long: > %d", maxDecompressedLogLen)
+	}
+	return decompressed, nil
+}
+
+// Compress compresses a raw event log in the given format, for producers
+// shipping event logs to a verifier. The result is accepted by
+// ParseEventLog and ParseAndReplay.
+func Compress(rawEventLog []byte, format CompressionFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case Uncompressed:
+		return rawEventLog, nil
+	case Gzip:
+		gw := gzip.NewWriter(&buf)
+		if _, err := gw.Write(rawEventLog); err != nil {
+			return nil, err
+		}
+		if err := gw.Close(); err != nil {
+			return nil, err
+		}
+	case Zstd:
+		zw, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(rawEventLog); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression format %v", format)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"unicode/utf16"
+)
+
+// Device path node subtypes with a text representation.
+//
+// Section 10.3 of the UEFI specification, accessible at:
+// https://uefi.org/specs/UEFI/2.10/10_Protocols_Device_Path_Protocol.html
+const (
+	pciSubtype                = 0x01
+	hardwareVendorSubtype     = 0x04
+	acpiSubtype               = 0x01
+	scsiSubtype               = 0x02
+	usbSubtype                = 0x05
+	macSubtype                = 0x0b
+	ipv4Subtype               = 0x0c
+	ipv6Subtype               = 0x0d
+	sataSubtype               = 0x12
+	nvmeSubtype               = 0x17
+	uriSubtype                = 0x18
+	hardDriveSubtype          = 0x01
+	filePathSubtype           = 0x04
+	fvFileSubtype             = 0x06
+	fvSubtype                 = 0x07
+	relativeOffsetSubtype     = 0x08
+	pciRootEISAID             = 0x0a0341d0 // PNP0A03
+	pcieRootEISAID            = 0x0a0841d0 // PNP0A08
+	hardDriveSignatureGUIDLen = 16
+	hardDriveLen              = 4 + 8 + 8 + hardDriveSignatureGUIDLen + 2
+)
+
+// Hard drive node partition formats and signature types.
+const (
+	mbrPartitionFormat = 0x01
+	gptPartitionFormat = 0x02
+	mbrSignatureType   = 0x01
+	guidSignatureType  = 0x02
+)
+
+// Network boot node data lengths. Later UEFI versions added gateway fields to
+// the IPv4 and IPv6 nodes, so both lengths are found in the wild.
+const (
+	macAddrLen         = 32
+	ipv4Len            = 4 + 4 + 2 + 2 + 2 + 1
+	ipv4WithGatewayLen = ipv4Len + 4 + 4
+	ipv6Len            = 16 + 16 + 2 + 2 + 2 + 1
+	ipv6WithGatewayLen = ipv6Len + 1 + 16
+)
+
+// IsPCI reports whether the element is a PCI device node.
+func (e EFIDevicePathElement) IsPCI() bool {
+	return e.Type == HardwareDevice && e.Subtype == pciSubtype
+}
+
+// IsRelativeOffsetRange reports whether the element is a relative offset
+// range node, as used for images read from memory-mapped I/O, e.g., PCI option
+// ROMs.
+func (e EFIDevicePathElement) IsRelativeOffsetRange() bool {
+	return e.Type == MediaDevice && e.Subtype == relativeOffsetSubtype
+}
+
+// IsFilePath reports whether the element is a media file path node, holding
+// a UTF-16 path.
+func (e EFIDevicePathElement) IsFilePath() bool {
+	return e.Type == MediaDevice && e.Subtype == filePathSubtype
+}
+
+// PartitionGUID returns the unique partition GUID of a hard drive media node
+// of a GPT partition, e.g., to find the partition in the GUID partition table
+// measured with EFIGPTEvent. It returns false for other nodes.
+func (e EFIDevicePathElement) PartitionGUID() (string, bool) {
+	if e.Type != MediaDevice || e.Subtype != hardDriveSubtype || len(e.Data) != hardDriveLen {
+		return "", false
+	}
+	if e.Data[hardDriveLen-2] != gptPartitionFormat || e.Data[hardDriveLen-1] != guidSignatureType {
+		return "", false
+	}
+	var guid efiGUID
+	binary.Read(bytes.NewReader(e.Data[20:]), binary.LittleEndian, &guid)
+	return guid.String(), true
+}
+
+// String returns the UEFI text form of the device path node, e.g.,
+// "Pci(0x1,0x0)". Nodes without a specific text form, or with malformed data,
+// use the generic "Path(type,subtype,data)" form.
+func (e EFIDevicePathElement) String() string {
+	r := bytes.NewReader(e.Data)
+	switch {
+	case e.IsPCI() && len(e.Data) == 2:
+		return fmt.Sprintf("Pci(%#x,%#x)", e.Data[1], e.Data[0])
+	case e.Type == HardwareDevice && e.Subtype == hardwareVendorSubtype && len(e.Data) >= 16:
+		var guid efiGUID
+		binary.Read(r, binary.LittleEndian, &guid)
+		return fmt.Sprintf("VenHw(%v)", guid)
+	case e.Type == ACPIDevice && e.Subtype == acpiSubtype && len(e.Data) == 8:
+		hid := binary.LittleEndian.Uint32(e.Data[:4])
+		uid := binary.LittleEndian.Uint32(e.Data[4:])
+		switch hid {
+		case pciRootEISAID:
+			return fmt.Sprintf("PciRoot(%#x)", uid)
+		case pcieRootEISAID:
+			return fmt.Sprintf("PcieRoot(%#x)", uid)
+		}
+		return fmt.Sprintf("Acpi(%#x,%#x)", hid, uid)
+	case e.Type == MessagingDevice:
+		if s, ok := e.messagingString(); ok {
+			return s
+		}
+	case e.Type == MediaDevice && e.Subtype == hardDriveSubtype && len(e.Data) == hardDriveLen:
+		partition := binary.LittleEndian.Uint32(e.Data[:4])
+		start := binary.LittleEndian.Uint64(e.Data[4:12])
+		size := binary.LittleEndian.Uint64(e.Data[12:20])
+		if guid, ok := e.PartitionGUID(); ok {
+			return fmt.Sprintf("HD(%d,GPT,%s,%#x,%#x)", partition, guid, start, size)
+		}
+		if e.Data[hardDriveLen-2] == mbrPartitionFormat && e.Data[hardDriveLen-1] == mbrSignatureType {
+			signature := binary.LittleEndian.Uint32(e.Data[20:24])
+			return fmt.Sprintf("HD(%d,MBR,%#08x,%#x,%#x)", partition, signature, start, size)
+		}
+		return fmt.Sprintf("HD(%d,%#x,%#x)", partition, start, size)
+	case e.IsFilePath() && len(e.Data)%2 == 0:
+		path := make([]uint16, len(e.Data)/2)
+		binary.Read(r, binary.LittleEndian, &path)
+		return strings.TrimRight(string(utf16.Decode(path)), "\x00")
+	case e.Type == MediaDevice && (e.Subtype == fvFileSubtype || e.Subtype == fvSubtype) && len(e.Data) == 16:
+		var guid efiGUID
+		binary.Read(r, binary.LittleEndian, &guid)
+		if e.Subtype == fvSubtype {
+			return fmt.Sprintf("Fv(%v)", guid)
+		}
+		return fmt.Sprintf("FvFile(%v)", guid)
+	case e.IsRelativeOffsetRange() && len(e.Data) == 4+8+8:
+		start := binary.LittleEndian.Uint64(e.Data[4:12])
+		end := binary.LittleEndian.Uint64(e.Data[12:])
+		return fmt.Sprintf("Offset(%#x,%#x)", start, end)
+	}
+	return fmt.Sprintf("Path(%d,%d,%x)", e.Type, e.Subtype, e.Data)
+}
+
+// messagingString returns the UEFI text form of a messaging device node, e.g.,
+// a disk controller or network boot node. It returns false for unknown
+// subtypes and malformed data.
+func (e EFIDevicePathElement) messagingString() (string, bool) {
+	d := e.Data
+	switch {
+	case e.Subtype == scsiSubtype && len(d) == 4:
+		return fmt.Sprintf("Scsi(%#x,%#x)", binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:])), true
+	case e.Subtype == usbSubtype && len(d) == 2:
+		return fmt.Sprintf("USB(%#x,%#x)", d[0], d[1]), true
+	case e.Subtype == sataSubtype && len(d) == 6:
+		return fmt.Sprintf("Sata(%#x,%#x,%#x)", binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:]), binary.LittleEndian.Uint16(d[4:])), true
+	case e.Subtype == nvmeSubtype && len(d) == 4+8:
+		// The IEEE Extended Unique Identifier is printed most significant
+		// byte first.
+		eui := make([]string, 8)
+		for i := range eui {
+			eui[i] = fmt.Sprintf("%02X", d[11-i])
+		}
+		return fmt.Sprintf("NVMe(%#x,%s)", binary.LittleEndian.Uint32(d), strings.Join(eui, "-")), true
+	case e.Subtype == macSubtype && len(d) == macAddrLen+1:
+		ifType := d[macAddrLen]
+		addr := d[:macAddrLen]
+		// Interface types 0 and 1 are Ethernet, with 6-byte addresses.
+		if ifType <= 1 {
+			addr = addr[:6]
+		}
+		return fmt.Sprintf("MAC(%x,%#x)", addr, ifType), true
+	case e.Subtype == ipv4Subtype && (len(d) == ipv4Len || len(d) == ipv4WithGatewayLen):
+		origin := "DHCP"
+		if d[14] != 0 {
+			origin = "Static"
+		}
+		s := fmt.Sprintf("IPv4(%v,%s,%s,%v", net.IP(d[4:8]), ipProtocol(binary.LittleEndian.Uint16(d[12:])), origin, net.IP(d[:4]))
+		if len(d) == ipv4WithGatewayLen {
+			s += fmt.Sprintf(",%v,%v", net.IP(d[15:19]), net.IP(d[19:23]))
+		}
+		return s + ")", true
+	case e.Subtype == ipv6Subtype && (len(d) == ipv6Len || len(d) == ipv6WithGatewayLen):
+		var origin string
+		switch d[38] {
+		case 0:
+			origin = "Static"
+		case 1:
+			origin = "StatelessAutoConfigure"
+		default:
+			origin = "StatefulAutoConfigure"
+		}
+		s := fmt.Sprintf("IPv6(%v,%s,%s,%v", net.IP(d[16:32]), ipProtocol(binary.LittleEndian.Uint16(d[36:])), origin, net.IP(d[:16]))
+		if len(d) == ipv6WithGatewayLen {
+			s += fmt.Sprintf(",%v,%d", net.IP(d[40:56]), d[39])
+		}
+		return s + ")", true
+	case e.Subtype == uriSubtype:
+		return fmt.Sprintf("Uri(%s)", d), true
+	}
+	return "", false
+}
+
+// ipProtocol returns the text form of an IP protocol number of a network
+// boot node.
+func ipProtocol(protocol uint16) string {
+	switch protocol {
+	case 6:
+		return "TCP"
+	case 17:
+		return "UDP"
+	}
+	return fmt.Sprintf("%#x", protocol)
+}
+
+// FilePathString returns the file path of a device path, e.g.,
+// `\EFI\BOOT\BOOTX64.EFI`, or "" if it has no file path nodes. Firmware may
+// split a path across several nodes, which are concatenated.
+func FilePathString(path []EFIDevicePathElement) string {
+	var b strings.Builder
+	for _, e := range path {
+		if e.IsFilePath() && len(e.Data)%2 == 0 {
+			b.WriteString(e.String())
+		}
+	}
+	return b.String()
+}
+
+// DevicePathString returns the UEFI text form of a device path, e.g.,
+// "PciRoot(0x0)/Pci(0x1,0x0)/Pci(0x0,0x0)/Offset(0xf250,0x201ff)".
+func DevicePathString(path []EFIDevicePathElement) string {
+	nodes := make([]string, 0, len(path))
+	for _, e := range path {
+		nodes = append(nodes, e.String())
+	}
+	return strings.Join(nodes, "/")
+}
@@ -16,13 +16,23 @@ package tcg
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"io"
 	"os"
+	"sort"
 	"testing"
+	"testing/iotest"
+	"unicode/utf16"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-eventlog/internal/testutil"
 	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/testdata"
 	"github.com/google/go-tpm/legacy/tpm2"
 )
 
@@ -143,7 +153,7 @@ func TestParseEventLogEventSizeZero(t *testing.T) {
 		// no "event data"
 	}
 
-	if _, err := parseRawEvent(bytes.NewBuffer(data), nil); err != nil {
+	if _, err := parseRawEvent(bytes.NewBuffer(data), nil, nil); err != nil {
 		t.Fatalf("parsing event log: %v", err)
 	}
 }
@@ -180,7 +190,7 @@ func TestParseEventLog2EventSizeZero(t *testing.T) {
 		},
 	}
 
-	if _, err := parseRawEvent2(bytes.NewBuffer(data), specID); err != nil {
+	if _, err := parseRawEvent2(bytes.NewBuffer(data), specID, 0, nil); err != nil {
 		t.Fatalf("parsing event log: %v", err)
 	}
 }
@@ -391,3 +401,1712 @@ YWNrLHRvbW95byxicGYgcGFuaWM9MzAgaTkxNS5lbmFibGVfcHNyPTA=`)
 		}
 	}
 }
+
+// rewriteTestLogs returns a crypto agile and a SHA1-format event log, with
+// the register values they replay to.
+func rewriteTestLogs(t *testing.T) []struct {
+	name string
+	raw  []byte
+	mrs  []register.MR
+} {
+	t.Helper()
+	data, err := os.ReadFile("../testdata/legacydata/linux_tpm12.json")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	var dump testutil.Dump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("parsing test data: %v", err)
+	}
+	return []struct {
+		name string
+		raw  []byte
+		mrs  []register.MR
+	}{
+		{"CryptoAgile", testdata.Ubuntu2404AmdSevSnpEventLog, replayedMRs(t, testdata.Ubuntu2404AmdSevSnpEventLog, ParseOpts{})},
+		{"SHA1", dump.Log.Raw, convertToMRs(dump.Log.PCRs)},
+	}
+}
+
+func TestFilterEventLog(t *testing.T) {
+	for _, test := range rewriteTestLogs(t) {
+		t.Run(test.name, func(t *testing.T) {
+			var pcr7 []register.MR
+			for _, mr := range test.mrs {
+				if mr.Idx() == 7 {
+					pcr7 = append(pcr7, mr)
+				}
+			}
+
+			filtered, err := FilterEventLog(test.raw, []int{7})
+			if err != nil {
+				t.Fatalf("FilterEventLog(): %v", err)
+			}
+			if len(filtered) >= len(test.raw) {
+				t.Errorf("FilterEventLog(): got %d bytes, want fewer than %d", len(filtered), len(test.raw))
+			}
+			el, err := ParseEventLog(filtered, ParseOpts{})
+			if err != nil {
+				t.Fatalf("ParseEventLog(filtered log): %v", err)
+			}
+			events, err := el.Verify(pcr7)
+			if err != nil {
+				t.Fatalf("Verify(filtered log): %v", err)
+			}
+			if len(events) == 0 {
+				t.Errorf("Verify(filtered log): got no events")
+			}
+			for _, e := range el.rawEvents {
+				if e.index != 7 {
+					t.Errorf("FilterEventLog(): got event for PCR %d", e.index)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactEventLog(t *testing.T) {
+	for _, test := range rewriteTestLogs(t) {
+		t.Run(test.name, func(t *testing.T) {
+			redacted, err := RedactEventLog(test.raw, []int{0, 1, 4}, ParseOpts{})
+			if err != nil {
+				t.Fatalf("RedactEventLog(): %v", err)
+			}
+			original, err := ParseEventLog(test.raw, ParseOpts{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Redacted registers only verify with AllowRedactedMRs.
+			unallowed, err := ParseEventLog(redacted, ParseOpts{})
+			if err != nil {
+				t.Fatalf("ParseEventLog(redacted log): %v", err)
+			}
+			if _, err := unallowed.Verify(test.mrs); err == nil {
+				t.Errorf("Verify(redacted log without AllowRedactedMRs): got nil, want error")
+			}
+			if len(unallowed.RedactedMRs) != 0 {
+				t.Errorf("ParseEventLog(redacted log without AllowRedactedMRs): got RedactedMRs %v, want none", unallowed.RedactedMRs)
+			}
+			if _, err := NewIncrementalReplay(ParseOpts{}).Replay(redacted, test.mrs); err == nil {
+				t.Errorf("IncrementalReplay.Replay(redacted log without AllowRedactedMRs): got nil, want error")
+			}
+
+			el, err := ParseEventLog(redacted, ParseOpts{AllowRedactedMRs: true})
+			if err != nil {
+				t.Fatalf("ParseEventLog(redacted log): %v", err)
+			}
+			redactedMRs := append([]int(nil), el.RedactedMRs...)
+			sort.Ints(redactedMRs)
+			if !cmp.Equal(redactedMRs, []int{0, 1, 4}) {
+				t.Errorf("ParseEventLog(redacted log): got RedactedMRs %v, want [0 1 4]", el.RedactedMRs)
+			}
+			events, err := el.Verify(test.mrs)
+			if err != nil {
+				t.Fatalf("Verify(redacted log): %v", err)
+			}
+			for _, e := range events {
+				if e.Index == 0 || e.Index == 1 || e.Index == 4 {
+					t.Errorf("Verify(redacted log): got verified event %d in redacted PCR %d", e.Num(), e.Index)
+				}
+			}
+			for _, trace := range el.Trace(test.mrs) {
+				wantRedacted := trace.Index == 0 || trace.Index == 1 || trace.Index == 4
+				if trace.Redacted != wantRedacted || (wantRedacted && !trace.Matches()) {
+					t.Errorf("Trace(redacted log): PCR %d got Redacted %v, want %v", trace.Index, trace.Redacted, wantRedacted)
+				}
+			}
+
+			var kept []rawEvent
+			for _, e := range original.rawEvents {
+				if e.index != 0 && e.index != 1 && e.index != 4 {
+					kept = append(kept, e)
+				}
+			}
+			var gotKept []rawEvent
+			redactedEvents := make(map[int]int)
+			for _, e := range el.rawEvents {
+				switch e.index {
+				case 0, 1, 4:
+					// Only the register value is kept, not the digests of the
+					// redacted events.
+					if !isRedactedMR(e) || len(e.digests) != len(el.Algs) {
+						t.Errorf("RedactEventLog(): got %v event in PCR %d, want a redacted register event", e.typ, e.index)
+					}
+					redactedEvents[e.index]++
+				default:
+					gotKept = append(gotKept, e)
+				}
+			}
+			if len(gotKept) != len(kept) {
+				t.Fatalf("RedactEventLog(): got %d unredacted events, want %d", len(gotKept), len(kept))
+			}
+			for i := range kept {
+				if gotKept[i].typ != kept[i].typ || !bytes.Equal(gotKept[i].data, kept[i].data) {
+					t.Errorf("RedactEventLog(): unredacted event %d changed", kept[i].sequence)
+				}
+			}
+			for _, idx := range []int{0, 1, 4} {
+				if redactedEvents[idx] != 1 {
+					t.Errorf("RedactEventLog(): got %d events in PCR %d, want 1", redactedEvents[idx], idx)
+				}
+			}
+
+			replay := NewIncrementalReplay(ParseOpts{AllowRedactedMRs: true})
+			if _, err := replay.Replay(redacted, test.mrs); err != nil {
+				t.Errorf("IncrementalReplay.Replay(redacted log): %v", err)
+			}
+			if got := replay.RedactedMRs(); !cmp.Equal(got, []int{0, 1, 4}) {
+				t.Errorf("IncrementalReplay.RedactedMRs() = %v, want [0 1 4]", got)
+			}
+		})
+	}
+}
+
+func TestRedactedMRMismatch(t *testing.T) {
+	raw := testdata.Ubuntu2404AmdSevSnpEventLog
+	mrs := replayedMRs(t, raw, ParseOpts{})
+	redacted, err := RedactEventLog(raw, []int{1}, ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered, err := ParseEventLog(redacted, ParseOpts{AllowRedactedMRs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range tampered.rawEvents {
+		if isRedactedMR(e) {
+			for _, d := range e.digests {
+				d.data[0] ^= 0xff
+			}
+		}
+	}
+	if _, err := tampered.Verify(mrs); err == nil {
+		t.Errorf("Verify(tampered redacted register value): got nil, want error")
+	}
+
+	// A redacted register event cannot hide only some of the events of a
+	// register.
+	original, err := ParseEventLog(raw, ParseOpts{AllowRedactedMRs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pcr4 []rawEvent
+	for _, e := range original.rawEvents {
+		if e.index == 4 {
+			pcr4 = append(pcr4, e)
+		}
+	}
+	partial, err := redactedMREvent(&EventLog{rawEvents: pcr4[:len(pcr4)-1], Algs: original.Algs}, pcr4[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	original.rawEvents = append(original.rawEvents, partial)
+	for _, trace := range original.Trace(mrs) {
+		if trace.Index == 4 && trace.Err == nil {
+			t.Errorf("Trace(partially redacted PCR 4): got nil error, want error")
+		}
+	}
+	if _, err := original.Verify(mrs); err == nil {
+		t.Errorf("Verify(partially redacted PCR 4): got nil, want error")
+	}
+}
+
+func TestRewritePlatformAttestation(t *testing.T) {
+	// The Spec ID event of a crypto agile log must be read from the unwrapped
+	// log.
+	raw := testdata.Ubuntu2404AmdSevSnpEventLog
+	blob := wrapPlatformAttestation(32, []byte("pcrs"), []byte("quote"), []byte("sig"), raw)
+
+	for _, tc := range []struct {
+		name    string
+		rewrite func([]byte) ([]byte, error)
+	}{
+		{"FilterEventLog", func(log []byte) ([]byte, error) { return FilterEventLog(log, []int{7}) }},
+		{"RedactEventLog", func(log []byte) ([]byte, error) { return RedactEventLog(log, []int{1}, ParseOpts{}) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.rewrite(blob)
+			if err != nil {
+				t.Fatalf("%s(PLAT blob): %v", tc.name, err)
+			}
+			want, err := tc.rewrite(raw)
+			if err != nil {
+				t.Fatalf("%s(raw log): %v", tc.name, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s(PLAT blob) differs from %s(raw log)", tc.name, tc.name)
+			}
+		})
+	}
+}
+
+func TestDevicePathString(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		path []EFIDevicePathElement
+		want string
+	}{
+		{
+			name: "OptionROM",
+			path: []EFIDevicePathElement{
+				{Type: ACPIDevice, Subtype: 1, Data: []byte{0xd0, 0x41, 0x03, 0x0a, 0, 0, 0, 0}},
+				{Type: HardwareDevice, Subtype: 1, Data: []byte{0, 1}},
+				{Type: MediaDevice, Subtype: 8, Data: []byte{0, 0, 0, 0, 0x50, 0xf2, 0, 0, 0, 0, 0, 0, 0xff, 0x01, 0x02, 0, 0, 0, 0, 0}},
+			},
+			want: "PciRoot(0x0)/Pci(0x1,0x0)/Offset(0xf250,0x201ff)",
+		},
+		{
+			name: "FilePath",
+			path: []EFIDevicePathElement{
+				{Type: MediaDevice, Subtype: 4, Data: []byte{'\\', 0, 'a', 0, 0, 0}},
+			},
+			want: "\\a",
+		},
+		{
+			name: "NVMeGPT",
+			path: []EFIDevicePathElement{
+				{Type: MessagingDevice, Subtype: 0x17, Data: []byte{1, 0, 0, 0, 8, 7, 6, 5, 4, 3, 2, 0xa1}},
+				{Type: MediaDevice, Subtype: 1, Data: append(append([]byte{
+					1, 0, 0, 0,
+					0, 8, 0, 0, 0, 0, 0, 0,
+					0, 0, 1, 0, 0, 0, 0, 0},
+					0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff),
+					2, 2)},
+			},
+			want: "NVMe(0x1,A1-02-03-04-05-06-07-08)/HD(1,GPT,00112233-4455-6677-8899-aabbccddeeff,0x800,0x10000)",
+		},
+		{
+			name: "SCSIMBR",
+			path: []EFIDevicePathElement{
+				{Type: MessagingDevice, Subtype: 0x02, Data: []byte{0, 0, 1, 0}},
+				{Type: MediaDevice, Subtype: 1, Data: append(append([]byte{
+					2, 0, 0, 0,
+					0, 8, 0, 0, 0, 0, 0, 0,
+					0, 0, 1, 0, 0, 0, 0, 0,
+					0x78, 0x56, 0x34, 0x12}, make([]byte, 12)...), 1, 1)},
+			},
+			want: "Scsi(0x0,0x1)/HD(2,MBR,0x12345678,0x800,0x10000)",
+		},
+		{
+			name: "PXE",
+			path: []EFIDevicePathElement{
+				{Type: MessagingDevice, Subtype: 0x0b, Data: append(append([]byte{0x52, 0x54, 0, 0x12, 0x34, 0x56}, make([]byte, 26)...), 1)},
+				{Type: MessagingDevice, Subtype: 0x0c, Data: []byte{
+					10, 0, 0, 2, 10, 0, 0, 1, 0, 0, 0x45, 0, 17, 0, 0,
+					10, 0, 0, 1, 255, 255, 255, 0}},
+			},
+			want: "MAC(525400123456,0x1)/IPv4(10.0.0.1,UDP,DHCP,10.0.0.2,10.0.0.1,255.255.255.0)",
+		},
+		{
+			name: "HTTP",
+			path: []EFIDevicePathElement{
+				{Type: MessagingDevice, Subtype: 0x0d, Data: append(append(make([]byte, 32), 0, 0, 0, 0, 6, 0, 1, 64), make([]byte, 16)...)},
+				{Type: MessagingDevice, Subtype: 0x18, Data: []byte("http://boot/x.efi")},
+			},
+			want: "IPv6(::,TCP,StatelessAutoConfigure,::,::,64)/Uri(http://boot/x.efi)",
+		},
+		{
+			name: "Unknown",
+			path: []EFIDevicePathElement{
+				{Type: MessagingDevice, Subtype: 0x20, Data: []byte{0xab}},
+				{Type: HardwareDevice, Subtype: 1, Data: []byte{0}},
+			},
+			want: "Path(3,32,ab)/Path(1,1,00)",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DevicePathString(test.path); got != test.want {
+				t.Errorf("DevicePathString() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseEventLogPadding(t *testing.T) {
+	raw, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	for _, test := range []struct {
+		name        string
+		padding     []byte
+		wantGarbage int
+	}{
+		{"Zeros", make([]byte, 64), -1},
+		{"FFs", bytes.Repeat([]byte{0xff}, 64), -1},
+		{"HiddenData", append(bytes.Repeat([]byte{0xff}, 8), "hidden"...), len(raw) + 8},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			padded := append(append([]byte(nil), raw...), test.padding...)
+			el, err := ParseEventLog(padded, ParseOpts{AllowPadding: true})
+			if err != nil {
+				t.Fatalf("ParseEventLog(): %v", err)
+			}
+			want := &Padding{Offset: len(raw), Length: len(test.padding), GarbageOffset: test.wantGarbage}
+			if el.Padding == nil || *el.Padding != *want {
+				t.Errorf("ParseEventLog(): got padding %+v, want %+v", el.Padding, want)
+			}
+		})
+	}
+}
+
+func TestParseEventLogLimits(t *testing.T) {
+	data, err := os.ReadFile("../testdata/legacydata/linux_tpm12.json")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	var dump testutil.Dump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("parsing test data: %v", err)
+	}
+	cryptoAgile, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	for _, test := range []struct {
+		name string
+		log  []byte
+	}{
+		{"SHA1", dump.Log.Raw},
+		{"CryptoAgile", cryptoAgile},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			el, err := ParseEventLog(test.log, ParseOpts{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			numEvents := len(el.rawEvents)
+			maxDataSize := 0
+			for _, e := range el.rawEvents {
+				if len(e.data) > maxDataSize {
+					maxDataSize = len(e.data)
+				}
+			}
+
+			if _, err := ParseEventLog(test.log, ParseOpts{MaxEvents: numEvents, MaxEventDataSize: maxDataSize}); err != nil {
+				t.Errorf("ParseEventLog(limits at the log's size): %v", err)
+			}
+			if _, err := ParseEventLog(test.log, ParseOpts{MaxEvents: numEvents - 1}); err == nil {
+				t.Errorf("ParseEventLog(MaxEvents: %d): got nil, want error", numEvents-1)
+			}
+			if _, err := ParseEventLog(test.log, ParseOpts{MaxEventDataSize: maxDataSize - 1}); err == nil {
+				t.Errorf("ParseEventLog(MaxEventDataSize: %d): got nil, want error", maxDataSize-1)
+			}
+		})
+	}
+}
+
+func TestParseEventLogMRIndexes(t *testing.T) {
+	raw, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	el, err := ParseEventLog(raw, ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered, err := ParseEventLog(raw, ParseOpts{MRIndexes: []int{4, 7}})
+	if err != nil {
+		t.Fatalf("ParseEventLog(): %v", err)
+	}
+	var want []int
+	for _, e := range el.rawEvents {
+		if e.index == 4 || e.index == 7 {
+			want = append(want, e.sequence)
+		}
+	}
+	var got []int
+	for _, e := range filtered.rawEvents {
+		got = append(got, e.sequence)
+	}
+	if len(want) == 0 {
+		t.Fatal("no events in PCRs 4 and 7")
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParseEventLog(MRIndexes: [4 7]) returned unexpected event numbers (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseCompressedEventLog(t *testing.T) {
+	data, err := os.ReadFile("../testdata/legacydata/windows_gcp_shielded_vm.json")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	var dump testutil.Dump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("parsing test data: %v", err)
+	}
+	if got := DetectCompression(dump.Log.Raw); got != Uncompressed {
+		t.Fatalf("DetectCompression(raw) = %v, want %v", got, Uncompressed)
+	}
+
+	for _, format := range []CompressionFormat{Gzip, Zstd} {
+		t.Run(format.String(), func(t *testing.T) {
+			compressed, err := Compress(dump.Log.Raw, format)
+			if err != nil {
+				t.Fatalf("Compress(): %v", err)
+			}
+			if got := DetectCompression(compressed); got != format {
+				t.Errorf("DetectCompression() = %v, want %v", got, format)
+			}
+			events, err := ParseAndReplay(compressed, convertToMRs(dump.Log.PCRs), ParseOpts{})
+			if err != nil {
+				t.Fatalf("ParseAndReplay(compressed): %v", err)
+			}
+			if len(events) == 0 {
+				t.Errorf("ParseAndReplay(compressed): got no events")
+			}
+
+			if _, err := Decompress(compressed[:len(compressed)/2]); err == nil {
+				t.Errorf("Decompress(truncated): got nil, want error")
+			}
+		})
+	}
+}
+
+// parseAll parses all events of the log with a Parser.
+func parseAll(t *testing.T, r io.Reader, parseOpts ParseOpts) (*Parser, []rawEvent) {
+	t.Helper()
+	p, err := NewParser(r, parseOpts)
+	if err != nil {
+		t.Fatalf("NewParser(): %v", err)
+	}
+	var events []rawEvent
+	for {
+		e, err := p.next()
+		if err == io.EOF {
+			return p, events
+		}
+		if err != nil {
+			t.Fatalf("Next(): %v", err)
+		}
+		events = append(events, e)
+	}
+}
+
+func TestParser(t *testing.T) {
+	data, err := os.ReadFile("../testdata/legacydata/linux_tpm12.json")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	var dump testutil.Dump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("parsing test data: %v", err)
+	}
+	cryptoAgile, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	ccel, err := os.ReadFile("../testdata/eventlogs/ccel/cos-113-intel-tdx.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	gzipped, err := Compress(cryptoAgile, Gzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zstdCompressed, err := Compress(cryptoAgile, Zstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty := make([]byte, 16)
+	empty[0] = 16
+	withEmpty := append(append([]byte(nil), cryptoAgile...), empty...)
+	ffPadded := append(append([]byte(nil), cryptoAgile...), bytes.Repeat([]byte{0xff}, 64)...)
+
+	for _, test := range []struct {
+		name      string
+		log       []byte
+		parseOpts ParseOpts
+	}{
+		{"SHA1", dump.Log.Raw, ParseOpts{}},
+		{"CryptoAgile", cryptoAgile, ParseOpts{}},
+		{"CCEL", ccel, ParseOpts{AllowPadding: true}},
+		{"CCELPaddingQuirk", ccel, ParseOpts{Quirks: QuirkPadding}},
+		{"FFPadding", ffPadded, ParseOpts{AllowPadding: true}},
+		{"Gzip", gzipped, ParseOpts{}},
+		{"Zstd", zstdCompressed, ParseOpts{}},
+		{"EmptyEvents", withEmpty, ParseOpts{Quirks: QuirkEmptyEvents}},
+		{"MRIndexes", cryptoAgile, ParseOpts{MRIndexes: []int{4, 7}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			el, err := ParseEventLog(test.log, test.parseOpts)
+			if err != nil {
+				t.Fatalf("ParseEventLog(): %v", err)
+			}
+			// Read one byte at a time, to check events spanning reads.
+			p, events := parseAll(t, iotest.OneByteReader(bytes.NewReader(test.log)), test.parseOpts)
+			if diff := cmp.Diff(el.Algs, p.Algs); diff != "" {
+				t.Errorf("Parser.Algs returned unexpected diff (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(el.rawEvents, events, cmp.AllowUnexported(rawEvent{}, digest{})); diff != "" {
+				t.Errorf("Parser returned unexpected diff (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(el.Padding, p.Padding); diff != "" {
+				t.Errorf("Parser.Padding returned unexpected diff (-want +got):\n%s", diff)
+			}
+			if p.AppliedQuirks != el.AppliedQuirks {
+				t.Errorf("Parser.AppliedQuirks = %v, want %v", p.AppliedQuirks, el.AppliedQuirks)
+			}
+		})
+	}
+}
+
+func TestParserNext(t *testing.T) {
+	raw, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	el, err := ParseEventLog(raw, ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewParser(bytes.NewReader(raw), ParseOpts{})
+	if err != nil {
+		t.Fatalf("NewParser(): %v", err)
+	}
+	for _, want := range el.Events(register.HashSHA256) {
+		got, err := p.Next(register.HashSHA256)
+		if err != nil {
+			t.Fatalf("Next(): %v", err)
+		}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(Event{})); diff != "" {
+			t.Fatalf("Next() returned unexpected diff (-want +got):\n%s", diff)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := p.Next(register.HashSHA256); err != io.EOF {
+			t.Errorf("Next() after the last event = %v, want io.EOF", err)
+		}
+	}
+}
+
+func TestParserFails(t *testing.T) {
+	raw, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	ccel, err := os.ReadFile("../testdata/eventlogs/ccel/cos-113-intel-tdx.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	for _, test := range []struct {
+		name      string
+		log       []byte
+		parseOpts ParseOpts
+	}{
+		{"Truncated", raw[:len(raw)-1], ParseOpts{}},
+		{"Padding", ccel, ParseOpts{}},
+		{"MaxEvents", raw, ParseOpts{MaxEvents: 10}},
+		{"MaxEventDataSize", raw, ParseOpts{MaxEventDataSize: 16}},
+		{"HugeEventSize", append(append([]byte(nil), raw...), 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0x7f), ParseOpts{}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseEventLog(test.log, test.parseOpts); err == nil {
+				t.Fatalf("ParseEventLog(): got nil, want error")
+			}
+			p, err := NewParser(bytes.NewReader(test.log), test.parseOpts)
+			if err != nil {
+				return
+			}
+			for {
+				_, err := p.Next(register.HashSHA256)
+				if err == io.EOF {
+					t.Fatalf("Next(): got io.EOF, want error")
+				}
+				if err != nil {
+					break
+				}
+			}
+		})
+	}
+	if _, err := NewParser(bytes.NewReader(nil), ParseOpts{}); err == nil {
+		t.Errorf("NewParser(empty log): got nil, want error")
+	}
+}
+
+// replayedMRs returns the register values replayed from the log.
+func replayedMRs(t *testing.T, log []byte, parseOpts ParseOpts) []register.MR {
+	t.Helper()
+	el, err := ParseEventLog(log, parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mrs []register.MR
+	for _, alg := range el.Algs {
+		for idx := 0; idx < 24; idx++ {
+			trace := traceMR(el.rawEvents, register.FakeMR{Index: idx, Digest: make([]byte, alg.CryptoHash().Size()), DigestAlg: alg.CryptoHash()}, false)
+			if trace.Err != nil {
+				t.Fatal(trace.Err)
+			}
+			mrs = append(mrs, register.FakeMR{Index: idx, Digest: trace.Final(), DigestAlg: alg.CryptoHash()})
+		}
+	}
+	return mrs
+}
+
+// eventEnds returns the offset of the end of each event in the log.
+func eventEnds(t *testing.T, log []byte, parseOpts ParseOpts) []int {
+	t.Helper()
+	p, err := NewParser(bytes.NewReader(log), parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ends []int
+	for {
+		if _, err := p.next(); err == io.EOF {
+			return ends
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		ends = append(ends, p.offset)
+	}
+}
+
+func TestIncrementalReplay(t *testing.T) {
+	cryptoAgile, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	ccel, err := os.ReadFile("../testdata/eventlogs/ccel/cos-113-intel-tdx.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	for _, test := range []struct {
+		name      string
+		log       []byte
+		parseOpts ParseOpts
+		// padding is appended to each replayed part of the log.
+		padding []byte
+	}{
+		{"CryptoAgile", cryptoAgile, ParseOpts{}, nil},
+		{"CCEL", ccel, ParseOpts{AllowPadding: true}, make([]byte, 64)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			finalMRs := replayedMRs(t, test.log, test.parseOpts)
+			want, err := ParseAndReplay(test.log, finalMRs, test.parseOpts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ends := eventEnds(t, test.log, test.parseOpts)
+			// Replay the log in parts of increasing size.
+			var parts []int
+			for i := 1; i < len(ends); i *= 2 {
+				parts = append(parts, ends[i])
+			}
+			parts = append(parts, ends[len(ends)-1])
+
+			r := NewIncrementalReplay(test.parseOpts)
+			var got []Event
+			for _, end := range parts {
+				appended := append(append([]byte(nil), test.log[r.Offset():end]...), test.padding...)
+				mrs := replayedMRs(t, test.log[:end], test.parseOpts)
+				events, err := r.Replay(appended, mrs)
+				if err != nil {
+					t.Fatalf("Replay(log up to %d): %v", end, err)
+				}
+				if r.Offset() != end {
+					t.Errorf("Offset() = %d, want %d", r.Offset(), end)
+				}
+				got = append(got, events...)
+			}
+			if diff := cmp.Diff(want, got, cmp.AllowUnexported(Event{})); diff != "" {
+				t.Errorf("Replay() returned unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIncrementalReplayFails(t *testing.T) {
+	raw, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	ends := eventEnds(t, raw, ParseOpts{})
+	mid := ends[len(ends)/2]
+	r := NewIncrementalReplay(ParseOpts{})
+	if _, err := r.Replay(raw[:mid], replayedMRs(t, raw[:mid], ParseOpts{})); err != nil {
+		t.Fatalf("Replay(first half): %v", err)
+	}
+
+	finalMRs := replayedMRs(t, raw, ParseOpts{})
+	// The second half checked against the first half's register values.
+	_, err = r.Replay(raw[mid:], replayedMRs(t, raw[:mid], ParseOpts{}))
+	var replayErr ReplayError
+	if !errors.As(err, &replayErr) || len(replayErr.InvalidMRs) == 0 {
+		t.Errorf("Replay(stale register values) = %v, want ReplayError", err)
+	}
+	if _, err := r.Replay(raw[mid:len(raw)-1], finalMRs); err == nil {
+		t.Errorf("Replay(truncated event): got nil, want error")
+	}
+	if r.Offset() != mid {
+		t.Errorf("Offset() after failed replays = %d, want %d", r.Offset(), mid)
+	}
+	// Failed replays leave the state unchanged, so the events can be replayed
+	// again.
+	if _, err := r.Replay(raw[mid:], finalMRs); err != nil {
+		t.Errorf("Replay(second half): %v", err)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	data, err := os.ReadFile("../testdata/legacydata/windows_gcp_shielded_vm.json")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	var dump testutil.Dump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("parsing test data: %v", err)
+	}
+	el, err := ParseEventLog(dump.Log.Raw, ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+
+	pcrs := dump.Log.PCRs
+	tampered := pcrs[0]
+	tampered.Digest = bytes.Repeat([]byte{0xff}, len(tampered.Digest))
+	traces := el.Trace(convertToMRs(append([]register.PCR{tampered}, pcrs...)))
+	if len(traces) != len(pcrs)+1 {
+		t.Fatalf("Trace(): got %d traces, want %d", len(traces), len(pcrs)+1)
+	}
+	if traces[0].Matches() {
+		t.Errorf("Trace(): tampered PCR %d matches", tampered.Index)
+	}
+	for _, trace := range traces[1:] {
+		if len(trace.Steps) > 0 && !trace.Matches() {
+			t.Errorf("Trace(): PCR %d final value %x, want %x", trace.Index, trace.Final(), trace.Expected)
+		}
+		prev := trace.Initial
+		for _, step := range trace.Steps {
+			hash := trace.Hash.New()
+			hash.Write(prev)
+			hash.Write(step.Event.Digest)
+			if !bytes.Equal(hash.Sum(nil), step.Value) {
+				t.Errorf("Trace(): PCR %d event %d value does not extend the previous value", trace.Index, step.Event.Num())
+			}
+			prev = step.Value
+		}
+	}
+}
+
+func TestEncodeEFISignatureList(t *testing.T) {
+	data, err := os.ReadFile("../testdata/eventlogs/tpm/rhel8-uefi.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	el, err := ParseEventLog(data, ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	owners := map[string]string{
+		"PK":  "d281fad2-8d88-47a4-9792-5baa47bb1b89",
+		"KEK": "d281fad2-8d88-47a4-9792-5baa47bb1b89",
+		"db":  "d281fad2-8d88-47a4-9792-5baa47bb1b89",
+		"dbx": "77fa9abd-0359-4d32-bd60-28f4e78f784b",
+	}
+	var encoded int
+	for _, e := range el.Events(register.HashSHA256) {
+		if e.Type != EFIVariableDriverConfig {
+			continue
+		}
+		v, err := ParseUEFIVariableData(bytes.NewReader(e.Data))
+		if err != nil {
+			t.Fatalf("parsing variable data: %v", err)
+		}
+		owner, ok := owners[v.VarName()]
+		if !ok {
+			continue
+		}
+		certs, hashes, err := v.SignatureData()
+		if err != nil {
+			t.Fatalf("%s: SignatureData(): %v", v.VarName(), err)
+		}
+		got, err := EncodeEFISignatureList(certs, hashes, owner)
+		if err != nil {
+			t.Fatalf("%s: EncodeEFISignatureList(): %v", v.VarName(), err)
+		}
+		if !bytes.Equal(got, v.VariableData) {
+			t.Errorf("%s: EncodeEFISignatureList() does not match the measured variable", v.VarName())
+		}
+		encoded++
+	}
+	if encoded != len(owners) {
+		t.Errorf("encoded %d variables, want %d", encoded, len(owners))
+	}
+
+	if _, err := EncodeEFISignatureList(nil, [][]byte{{1, 2, 3}}, ""); err == nil {
+		t.Errorf("EncodeEFISignatureList(short hash): got nil, want error")
+	}
+	if _, err := EncodeEFISignatureList(nil, nil, "not-a-guid"); err == nil {
+		t.Errorf("EncodeEFISignatureList(invalid owner): got nil, want error")
+	}
+}
+
+func TestEncodeSignatureEntries(t *testing.T) {
+	data, err := os.ReadFile("../testdata/eventlogs/tpm/rhel8-uefi.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	el, err := ParseEventLog(data, ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	var encoded int
+	for _, e := range el.Events(register.HashSHA256) {
+		if e.Type != EFIVariableDriverConfig {
+			continue
+		}
+		v, err := ParseUEFIVariableData(bytes.NewReader(e.Data))
+		if err != nil {
+			t.Fatalf("parsing variable data: %v", err)
+		}
+		entries, errs := v.SignatureEntries()
+		if len(entries) == 0 || len(errs) != 0 {
+			continue
+		}
+		got, err := EncodeSignatureEntries(entries)
+		if err != nil {
+			t.Fatalf("%s: EncodeSignatureEntries(): %v", v.VarName(), err)
+		}
+		if !bytes.Equal(got, v.VariableData) {
+			t.Errorf("%s: EncodeSignatureEntries() does not match the measured variable", v.VarName())
+		}
+		encoded++
+	}
+	if encoded == 0 {
+		t.Error("encoded no variables")
+	}
+
+	for _, tc := range []struct {
+		name  string
+		entry SignatureEntry
+	}{
+		{"invalid type", SignatureEntry{TypeGUID: "not-a-guid"}},
+		{"invalid owner", SignatureEntry{TypeGUID: hashSHA256SigGUID.String(), Owner: "not-a-guid", Data: make([]byte, 32)}},
+		{"too large", SignatureEntry{TypeGUID: certX509SigGUID.String(), Data: make([]byte, maxDataLen)}},
+	} {
+		if _, err := EncodeSignatureEntries([]SignatureEntry{tc.entry}); err == nil {
+			t.Errorf("EncodeSignatureEntries(%s): got nil, want error", tc.name)
+		}
+	}
+}
+
+func TestPartialSignatureData(t *testing.T) {
+	data, err := os.ReadFile("../testdata/eventlogs/tpm/rhel8-uefi.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	el, err := ParseEventLog(data, ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	var dbx UEFIVariableData
+	for _, e := range el.Events(register.HashSHA256) {
+		if e.Type != EFIVariableDriverConfig {
+			continue
+		}
+		v, err := ParseUEFIVariableData(bytes.NewReader(e.Data))
+		if err != nil {
+			t.Fatalf("parsing variable data: %v", err)
+		}
+		if v.VarName() == "dbx" {
+			dbx = v
+		}
+	}
+	certs, hashes, err := dbx.SignatureData()
+	if err != nil {
+		t.Fatalf("SignatureData(): %v", err)
+	}
+	partialCerts, partialHashes, errs := dbx.PartialSignatureData()
+	if len(errs) != 0 || len(partialCerts) != len(certs) || len(partialHashes) != len(hashes) {
+		t.Fatalf("PartialSignatureData() = %d certs, %d hashes, errors %v; want %d certs, %d hashes", len(partialCerts), len(partialHashes), errs, len(certs), len(hashes))
+	}
+
+	// Corrupt the second certificate, and append a list of an unhandled type.
+	const listHeaderSize, ownerSize = 28, 16
+	certList := listHeaderSize + ownerSize + len(certs[0].Raw)
+	corrupt := bytes.Clone(dbx.VariableData)
+	corrupt[certList+listHeaderSize+ownerSize] ^= 0xff
+	var sha1List bytes.Buffer
+	binary.Write(&sha1List, binary.LittleEndian, efiSignatureListHeader{SignatureType: hashSHA1SigGUID, SignatureListSize: listHeaderSize})
+	corrupt = append(corrupt, sha1List.Bytes()...)
+	dbx.VariableData = corrupt
+
+	if _, _, err := dbx.SignatureData(); err == nil {
+		t.Errorf("SignatureData(corrupt) = got nil, want error")
+	}
+	partialCerts, partialHashes, errs = dbx.PartialSignatureData()
+	if len(partialCerts) != len(certs)-1 || len(partialHashes) != len(hashes) {
+		t.Errorf("PartialSignatureData(corrupt) = %d certs, %d hashes; want %d certs, %d hashes", len(partialCerts), len(partialHashes), len(certs)-1, len(hashes))
+	}
+	wantOffsets := []int{certList + listHeaderSize, len(dbx.VariableData) - listHeaderSize}
+	if len(errs) != len(wantOffsets) {
+		t.Fatalf("PartialSignatureData(corrupt) = errors %v, want %d errors", errs, len(wantOffsets))
+	}
+	for i, err := range errs {
+		if err.Offset != wantOffsets[i] {
+			t.Errorf("PartialSignatureData(corrupt) = error %v, want offset %d", err, wantOffsets[i])
+		}
+	}
+
+	dbx.VariableData = corrupt[:len(corrupt)-listHeaderSize-1]
+	if _, _, errs := dbx.PartialSignatureData(); len(errs) != 2 {
+		t.Errorf("PartialSignatureData(truncated) = errors %v, want 2 errors", errs)
+	}
+}
+
+func TestSignatureEntries(t *testing.T) {
+	data, err := os.ReadFile("../testdata/eventlogs/tpm/rhel8-uefi.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	el, err := ParseEventLog(data, ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	var dbx UEFIVariableData
+	for _, e := range el.Events(register.HashSHA256) {
+		if e.Type != EFIVariableDriverConfig {
+			continue
+		}
+		v, err := ParseUEFIVariableData(bytes.NewReader(e.Data))
+		if err != nil {
+			t.Fatalf("parsing variable data: %v", err)
+		}
+		if v.VarName() == "dbx" {
+			dbx = v
+		}
+	}
+	certs, hashes, err := dbx.SignatureData()
+	if err != nil {
+		t.Fatalf("SignatureData(): %v", err)
+	}
+
+	// Append a SHA-1 list with another owner to the mixed certificate and
+	// hash lists.
+	const microsoftOwner = "77fa9abd-0359-4d32-bd60-28f4e78f784b"
+	const listHeaderSize, ownerSize, sha1Size = 28, 16, 20
+	otherOwner := efiGUID{0x01020304, 0x0506, 0x0708, [8]byte{9, 10, 11, 12, 13, 14, 15, 16}}
+	var sha1List bytes.Buffer
+	binary.Write(&sha1List, binary.LittleEndian, efiSignatureListHeader{
+		SignatureType:     hashSHA1SigGUID,
+		SignatureListSize: listHeaderSize + ownerSize + sha1Size,
+		SignatureSize:     ownerSize + sha1Size,
+	})
+	binary.Write(&sha1List, binary.LittleEndian, otherOwner)
+	sha1List.Write(bytes.Repeat([]byte{0xaa}, sha1Size))
+	dbx.VariableData = append(bytes.Clone(dbx.VariableData), sha1List.Bytes()...)
+
+	entries, errs := dbx.SignatureEntries()
+	if len(errs) != 0 {
+		t.Fatalf("SignatureEntries() = errors %v, want none", errs)
+	}
+	if want := len(certs) + len(hashes) + 1; len(entries) != want {
+		t.Fatalf("SignatureEntries() = %d entries, want %d", len(entries), want)
+	}
+	for i, entry := range entries[:len(certs)] {
+		if entry.Type != "X509" || entry.TypeGUID != certX509SigGUID.String() || entry.Owner != microsoftOwner {
+			t.Errorf("SignatureEntries()[%d] = type %q (%s), owner %s; want X509, owner %s", i, entry.Type, entry.TypeGUID, entry.Owner, microsoftOwner)
+		}
+		if entry.Cert == nil || !bytes.Equal(entry.Cert.Raw, certs[i].Raw) || !bytes.Equal(entry.Data, certs[i].Raw) {
+			t.Errorf("SignatureEntries()[%d] does not hold certificate %d", i, i)
+		}
+	}
+	for i, entry := range entries[len(certs) : len(certs)+len(hashes)] {
+		if entry.Type != "SHA256" || entry.Owner != microsoftOwner || entry.Cert != nil || !bytes.Equal(entry.Data, hashes[i]) {
+			t.Errorf("SignatureEntries()[%d] = %+v, want SHA-256 hash %x", len(certs)+i, entry, hashes[i])
+		}
+	}
+	last := entries[len(entries)-1]
+	if last.Type != "SHA1" || last.Owner != otherOwner.String() || len(last.Data) != sha1Size {
+		t.Errorf("SignatureEntries() last entry = %+v, want SHA1 hash owned by %s", last, otherOwner)
+	}
+
+	// Corrupt the second certificate, so its entry is skipped.
+	corrupt := bytes.Clone(dbx.VariableData)
+	corrupt[listHeaderSize+ownerSize+len(certs[0].Raw)+listHeaderSize+ownerSize] ^= 0xff
+	dbx.VariableData = corrupt
+	entries, errs = dbx.SignatureEntries()
+	if len(errs) != 1 || len(entries) != len(certs)+len(hashes) {
+		t.Errorf("SignatureEntries(corrupt) = %d entries, errors %v; want %d entries, 1 error", len(entries), errs, len(certs)+len(hashes))
+	}
+}
+
+func TestParseEventLogQuirks(t *testing.T) {
+	base, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseLog, err := ParseEventLog(base, ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseEvents := len(baseLog.Events(register.HashSHA256))
+
+	// unlistedDigest is an event with an additional SHA-512 digest, which is
+	// not listed in the Spec ID event.
+	var unlistedDigest bytes.Buffer
+	data := []byte("ftpm event")
+	binary.Write(&unlistedDigest, binary.LittleEndian, rawEvent2Header{PCRIndex: 16, Type: uint32(Ipl)})
+	binary.Write(&unlistedDigest, binary.LittleEndian, uint32(len(baseLog.Algs)+1))
+	for _, alg := range baseLog.Algs {
+		h := alg.CryptoHash().New()
+		h.Write(data)
+		binary.Write(&unlistedDigest, binary.LittleEndian, uint16(alg))
+		unlistedDigest.Write(h.Sum(nil))
+	}
+	sha512ID, err := register.HashTPMAlg(crypto.SHA512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.Write(&unlistedDigest, binary.LittleEndian, sha512ID)
+	unlistedDigest.Write(make([]byte, crypto.SHA512.Size()))
+	binary.Write(&unlistedDigest, binary.LittleEndian, uint32(len(data)))
+	unlistedDigest.Write(data)
+
+	// empty is an event with no digests or data.
+	var empty bytes.Buffer
+	binary.Write(&empty, binary.LittleEndian, rawEvent2Header{PCRIndex: 16})
+	binary.Write(&empty, binary.LittleEndian, uint32(0))
+	binary.Write(&empty, binary.LittleEndian, uint32(0))
+
+	tests := []struct {
+		name        string
+		appended    []byte
+		quirks      Quirks
+		wantErr     bool
+		wantEvents  int
+		wantApplied Quirks
+	}{
+		{"unlisted digest", unlistedDigest.Bytes(), 0, true, 0, 0},
+		{"unlisted digest with AMD quirks", unlistedDigest.Bytes(), AMDfTPMQuirks, false, baseEvents + 1, QuirkUnlistedDigests},
+		{"empty event", empty.Bytes(), 0, false, baseEvents + 1, 0},
+		{"empty event with Intel quirks", empty.Bytes(), IntelPTTQuirks, false, baseEvents, QuirkEmptyEvents},
+		{"both with AMD quirks", append(bytes.Clone(unlistedDigest.Bytes()), empty.Bytes()...), AMDfTPMQuirks, false, baseEvents + 1, AMDfTPMQuirks},
+		{"no quirks needed", nil, AMDfTPMQuirks, false, baseEvents, 0},
+		{"padding", bytes.Repeat([]byte{0xff}, 64), 0, true, 0, 0},
+		{"padding with Azure quirks", bytes.Repeat([]byte{0xff}, 64), AzureQuirks, false, baseEvents, QuirkPadding},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			el, err := ParseEventLog(append(bytes.Clone(base), tc.appended...), ParseOpts{Quirks: tc.quirks})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseEventLog() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := len(el.Events(register.HashSHA256)); got != tc.wantEvents {
+				t.Errorf("ParseEventLog() = %d events, want %d", got, tc.wantEvents)
+			}
+			if el.AppliedQuirks != tc.wantApplied {
+				t.Errorf("ParseEventLog() = applied quirks %v, want %v", el.AppliedQuirks, tc.wantApplied)
+			}
+		})
+	}
+}
+
+func TestQuirkSet(t *testing.T) {
+	quirks, err := QuirkSet("amd-ftpm")
+	if err != nil || quirks != AMDfTPMQuirks {
+		t.Errorf("QuirkSet(amd-ftpm) = %v, %v; want %v", quirks, err, AMDfTPMQuirks)
+	}
+	quirks, err = QuirkSet("ovmf-edk2-2022")
+	if err != nil || quirks != OVMFEDK22022Quirks {
+		t.Errorf("QuirkSet(ovmf-edk2-2022) = %v, %v; want %v", quirks, err, OVMFEDK22022Quirks)
+	}
+	if _, err := QuirkSet("acme-tpm"); err == nil {
+		t.Errorf("QuirkSet(acme-tpm) = got nil, want error")
+	}
+	if got, want := (AMDfTPMQuirks | 1<<8).String(), "unlisted-digests|empty-events|Quirks(0x100)"; got != want {
+		t.Errorf("Quirks.String() = %q, want %q", got, want)
+	}
+}
+
+func TestEFIActionDigest(t *testing.T) {
+	for _, action := range []string{CallingEFIApplication, "not a known action"} {
+		for i := 0; i < 2; i++ {
+			hasher := crypto.SHA256.New()
+			hasher.Write([]byte(action))
+			want := hasher.Sum(nil)
+			got := EFIActionDigest(crypto.SHA256, action)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("EFIActionDigest(%q) = %x, want %x", action, got, want)
+			}
+			// Modifying the result must not affect later calls.
+			got[0] ^= 0xff
+		}
+	}
+}
+
+func TestParseEventLogBuffers(t *testing.T) {
+	buffers := GetParseBuffers()
+	defer buffers.Release()
+	for _, path := range []string{
+		"../testdata/legacydata/crypto_agile_eventlog",
+		"../testdata/legacydata/ubuntu_2104_shielded_vm_no_secure_boot_eventlog",
+		"../testdata/legacydata/short_no_action_eventlog",
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := ParseEventLog(data, ParseOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseEventLog(data, ParseOpts{Buffers: buffers})
+		if err != nil {
+			t.Fatalf("ParseEventLog(%s, Buffers): %v", path, err)
+		}
+		for _, alg := range want.Algs {
+			if diff := cmp.Diff(want.Events(alg), got.Events(alg), cmp.AllowUnexported(Event{})); diff != "" {
+				t.Errorf("ParseEventLog(%s, Buffers) returned unexpected %v events diff (-want +got):\n%s", path, alg, diff)
+			}
+		}
+	}
+}
+
+func BenchmarkParseEventLog(b *testing.B) {
+	data, err := os.ReadFile("../testdata/legacydata/ubuntu_2104_shielded_vm_no_secure_boot_eventlog")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("default", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := ParseEventLog(data, ParseOpts{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buffers := GetParseBuffers()
+			if _, err := ParseEventLog(data, ParseOpts{Buffers: buffers}); err != nil {
+				b.Fatal(err)
+			}
+			buffers.Release()
+		}
+	})
+}
+
+func TestParseTCGString(t *testing.T) {
+	for et := range eventTypeStrings {
+		want := EventType(et)
+		got, err := ParseTCGString(want.TCGString())
+		if err != nil {
+			t.Errorf("ParseTCGString(%q): %v", want.TCGString(), err)
+		} else if got != want {
+			t.Errorf("ParseTCGString(%q) = %v, want %v", want.TCGString(), got, want)
+		}
+	}
+	if _, err := ParseTCGString("EV_NOT_A_TYPE"); err == nil {
+		t.Errorf("ParseTCGString(unknown): got nil, want error")
+	}
+}
+
+func TestCheckEventSize(t *testing.T) {
+	variable := UEFIVariableData{UnicodeName: []uint16{'d', 'b'}, VariableData: []byte{1, 2, 3}}
+	variableData, err := variable.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	imageLoad := make([]byte, imageLoadHeaderLen)
+	imageLoad[24] = 4
+
+	for _, tc := range []struct {
+		name string
+		typ  EventType
+		data []byte
+		want string
+	}{
+		{"separator", Separator, []byte{0, 0, 0, 0}, ""},
+		{"short separator", Separator, []byte{0}, "want exactly 4"},
+		{"empty action", EFIAction, nil, "want at least 1"},
+		{"long S-CRTM version", SCRTMVersion, make([]byte, maxShortStringLen+1), "want at most 1024"},
+		{"variable", EFIVariableDriverConfig, variableData, ""},
+		{"short variable", EFIVariableDriverConfig, variableData[:variableDataHeaderLen-1], "want at least 32"},
+		{"variable with trailing data", EFIVariableDriverConfig, append(variableData, 0), "variable name length 2 and data length 3 do not match the 8 bytes after the header"},
+		{"image load with overrunning device path", EFIBootServicesApplication, imageLoad, "device path length 4 exceeds the 0 bytes after the header"},
+		{"image load with trailing data", EFIBootServicesApplication, make([]byte, imageLoadHeaderLen+8), ""},
+		{"firmware blob 2", EFIPlatformFirmwareBlob2, append([]byte{2, 'f', 'w'}, make([]byte, firmwareBlobLen)...), ""},
+		{"firmware blob 2 with short description", EFIPlatformFirmwareBlob2, append([]byte{3, 'f', 'w'}, make([]byte, firmwareBlobLen)...), "blob description length 3 implies 20 bytes"},
+		{"unchecked type", Ipl, nil, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CheckEventSize(tc.typ, tc.data); got != tc.want {
+				t.Errorf("CheckEventSize() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSizeAnomalies(t *testing.T) {
+	data, err := os.ReadFile("../testdata/eventlogs/tpm/ubuntu-2404-amd-sevsnp.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	el, err := ParseEventLog(data, ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anomalies := el.SizeAnomalies(); len(anomalies) != 0 {
+		t.Errorf("SizeAnomalies() = %v, want none", anomalies)
+	}
+	events := []Event{{sequence: 3, Index: 0, Type: Separator, Data: []byte{0, 0}}}
+	want := []SizeAnomaly{{Num: 3, Index: 0, Type: Separator, Size: 2, Reason: "want exactly 4"}}
+	if diff := cmp.Diff(want, CheckEventSizes(events)); diff != "" {
+		t.Errorf("CheckEventSizes() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestReplayMissingBankDigest(t *testing.T) {
+	measure := func(seq, index int, typ EventType, data string, hashes ...crypto.Hash) rawEvent {
+		e := rawEvent{sequence: seq, index: index, typ: typ, data: []byte(data)}
+		for _, h := range hashes {
+			e.digests = append(e.digests, digest{hash: h, data: EFIActionDigest(h, data)})
+		}
+		return e
+	}
+	// Changes to the PCR bank allocation only take effect at the next TPM
+	// reset, so an event claiming a reallocation does not change how the
+	// banks are replayed.
+	el := &EventLog{
+		Algs: []register.HashAlg{register.HashSHA1, register.HashSHA256},
+		rawEvents: []rawEvent{
+			measure(1, 0, EFIAction, "before", crypto.SHA1, crypto.SHA256),
+			measure(2, 4, EFIAction, "PCR Bank Reallocation", crypto.SHA1, crypto.SHA256),
+			measure(3, 0, EFIAction, "after", crypto.SHA256),
+		},
+	}
+	replay := func(h crypto.Hash, data ...string) register.MR {
+		value := make([]byte, h.Size())
+		for _, d := range data {
+			hash := h.New()
+			hash.Write(value)
+			hash.Write(EFIActionDigest(h, d))
+			value = hash.Sum(nil)
+		}
+		return register.PCR{Index: 0, Digest: value, DigestAlg: h}
+	}
+	if _, err := el.Verify([]register.MR{replay(crypto.SHA256, "before", "after")}); err != nil {
+		t.Errorf("Verify(SHA-256): %v", err)
+	}
+	if _, err := el.Verify([]register.MR{replay(crypto.SHA1, "before")}); err == nil {
+		t.Errorf("Verify(SHA-1): got nil, want error for an event without a SHA-1 digest")
+	}
+}
+
+func TestParseLinuxEventTag(t *testing.T) {
+	tagged := func(id uint32, desc string) []byte {
+		data := binary.LittleEndian.AppendUint32(nil, id)
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(desc)))
+		return append(data, desc...)
+	}
+	for _, tc := range []struct {
+		name   string
+		data   []byte
+		wantID uint32
+		wantOK bool
+	}{
+		{"load options", tagged(LinuxLoadOptionsEventTag, "LOADED_IMAGE::LoadOptions\x00"), LinuxLoadOptionsEventTag, true},
+		{"initrd", tagged(LinuxInitrdEventTag, "Linux initrd\x00"), LinuxInitrdEventTag, true},
+		{"description only", []byte("LOADED_IMAGE::LoadOptions\x00"), LinuxLoadOptionsEventTag, true},
+		{"other tag", tagged(0x7, "LOADED_IMAGE::LoadOptions\x00"), 0, false},
+		{"other text", []byte("tboot"), 0, false},
+		{"truncated", tagged(LinuxLoadOptionsEventTag, "LOADED_IMAGE::LoadOptions\x00")[:12], 0, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			id, ok := ParseLinuxEventTag(tc.data)
+			if id != tc.wantID || ok != tc.wantOK {
+				t.Errorf("ParseLinuxEventTag() = %#x, %v, want %#x, %v", id, ok, tc.wantID, tc.wantOK)
+			}
+		})
+	}
+}
+
+// wrapPlatformAttestation returns a Windows platform attestation (PLAT) blob
+// holding the event log. A 32 byte header has the PCR algorithm.
+func wrapPlatformAttestation(headerSize uint32, pcrs, quote, sig, log []byte) []byte {
+	blob := []byte("PLAT")
+	for _, field := range []uint32{2, headerSize, uint32(len(pcrs)), uint32(len(quote)), uint32(len(sig)), uint32(len(log))} {
+		blob = binary.LittleEndian.AppendUint32(blob, field)
+	}
+	if headerSize == 32 {
+		blob = binary.LittleEndian.AppendUint32(blob, uint32(tpm2.AlgSHA256))
+	}
+	blob = append(blob, pcrs...)
+	blob = append(blob, quote...)
+	blob = append(blob, sig...)
+	return append(blob, log...)
+}
+
+func TestParsePlatformAttestation(t *testing.T) {
+	data, err := os.ReadFile("../testdata/legacydata/windows_gcp_shielded_vm.json")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	var dump testutil.Dump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("parsing test data: %v", err)
+	}
+	wrap := wrapPlatformAttestation
+	blob := wrap(32, []byte("pcrs"), []byte("quote"), []byte("sig"), dump.Log.Raw)
+
+	pa, err := ParsePlatformAttestation(blob)
+	if err != nil {
+		t.Fatalf("ParsePlatformAttestation(): %v", err)
+	}
+	want := &PlatformAttestation{
+		Platform:  2,
+		PCRAlg:    uint16(tpm2.AlgSHA256),
+		PCRValues: []byte("pcrs"),
+		Quote:     []byte("quote"),
+		Signature: []byte("sig"),
+		Log:       dump.Log.Raw,
+	}
+	if diff := cmp.Diff(want, pa); diff != "" {
+		t.Errorf("ParsePlatformAttestation() returned unexpected diff (-want +got):\n%s", diff)
+	}
+	if _, err := ParseAndReplay(blob, convertToMRs(dump.Log.PCRs), ParseOpts{}); err != nil {
+		t.Errorf("ParseAndReplay(blob): %v", err)
+	}
+	if unwrapped, err := UnwrapWBCL(dump.Log.Raw); err != nil || !bytes.Equal(unwrapped, dump.Log.Raw) {
+		t.Errorf("UnwrapWBCL(raw log) = %v, want the log unchanged", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		blob []byte
+	}{
+		{"not a blob", dump.Log.Raw},
+		{"truncated header", blob[:20]},
+		{"small header size", wrap(12, nil, nil, nil, dump.Log.Raw)},
+		{"truncated log", blob[:len(blob)-1]},
+		{"trailing data", append(append([]byte(nil), blob...), 0)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParsePlatformAttestation(tc.blob); err == nil {
+				t.Errorf("ParsePlatformAttestation(): got nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseSipaEvents(t *testing.T) {
+	sipa := func(typ SipaEventType, data ...byte) []byte {
+		out := binary.LittleEndian.AppendUint32(nil, uint32(typ))
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(data)))
+		return append(out, data...)
+	}
+	module := sipa(SipaLoadedModuleAggregation, sipa(SipaImageSize, 1, 0, 0, 0)...)
+	data := sipa(SipaTrustBoundary, append(sipa(SipaBootCounter, 4, 0, 0, 0, 0, 0, 0, 0), module...)...)
+
+	events, err := ParseSipaEvents(data)
+	if err != nil {
+		t.Fatalf("ParseSipaEvents(): %v", err)
+	}
+	want := []SipaEvent{{
+		Type: SipaTrustBoundary,
+		Children: []SipaEvent{
+			{Type: SipaBootCounter, Data: []byte{4, 0, 0, 0, 0, 0, 0, 0}},
+			{Type: SipaLoadedModuleAggregation, Children: []SipaEvent{
+				{Type: SipaImageSize, Data: []byte{1, 0, 0, 0}},
+			}},
+		},
+	}}
+	if diff := cmp.Diff(want, events); diff != "" {
+		t.Errorf("ParseSipaEvents() returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	deep := sipa(SipaBootCounter)
+	for i := 0; i <= sipaMaxNesting+1; i++ {
+		deep = sipa(SipaTrustBoundary, deep...)
+	}
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"truncated header", data[:4]},
+		{"truncated data", data[:len(data)-1]},
+		{"truncated child", sipa(SipaTrustBoundary, module[:len(module)-1]...)},
+		{"nested too deep", deep},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseSipaEvents(tc.data); err == nil {
+				t.Errorf("ParseSipaEvents(): got nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseEFILoadOption(t *testing.T) {
+	utf16le := func(s string) []byte {
+		var b []byte
+		for _, c := range utf16.Encode([]rune(s)) {
+			b = binary.LittleEndian.AppendUint16(b, c)
+		}
+		return b
+	}
+	file := utf16le(`\EFI\boot.efi` + "\x00")
+	var filePath []byte
+	filePath = append(filePath, byte(MediaDevice), filePathSubtype)
+	filePath = binary.LittleEndian.AppendUint16(filePath, uint16(4+len(file)))
+	filePath = append(filePath, file...)
+	filePath = append(filePath, byte(EndDeviceArrayMarker), 0xff, 4, 0)
+
+	var option []byte
+	option = binary.LittleEndian.AppendUint32(option, LoadOptionActive)
+	option = binary.LittleEndian.AppendUint16(option, uint16(len(filePath)))
+	option = append(option, utf16le("Linux\x00")...)
+	option = append(option, filePath...)
+	option = append(option, "args"...)
+
+	got, err := ParseEFILoadOption(option)
+	if err != nil {
+		t.Fatalf("ParseEFILoadOption(): %v", err)
+	}
+	if got.Attributes != LoadOptionActive || got.Description != "Linux" || !bytes.Equal(got.OptionalData, []byte("args")) {
+		t.Errorf("ParseEFILoadOption() = %+v, want an active option Linux with optional data args", got)
+	}
+	if path := DevicePathString(got.FilePath); path != `\EFI\boot.efi` {
+		t.Errorf("ParseEFILoadOption(): got file path %q, want %q", path, `\EFI\boot.efi`)
+	}
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"short header", option[:5]},
+		{"unterminated description", option[:6+len("Linux")*2]},
+		{"overrunning file path", option[:len(option)-len("args")-1]},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseEFILoadOption(tc.data); err == nil {
+				t.Errorf("ParseEFILoadOption(): got nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseBootOrder(t *testing.T) {
+	got, err := ParseBootOrder([]byte{2, 0, 1, 0, 0x0a, 0x10})
+	if err != nil {
+		t.Fatalf("ParseBootOrder(): %v", err)
+	}
+	if diff := cmp.Diff([]uint16{2, 1, 0x100a}, got); diff != "" {
+		t.Errorf("ParseBootOrder(): unexpected diff (-want +got):\n%s", diff)
+	}
+	if _, err := ParseBootOrder([]byte{1, 0, 2}); err == nil {
+		t.Errorf("ParseBootOrder(odd length): got nil, want error")
+	}
+}
+
+func TestParseUEFIGPTData(t *testing.T) {
+	data, err := os.ReadFile("../testdata/eventlogs/tpm/rhel8-uefi.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	el, err := ParseEventLog(data, ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	var gptData []byte
+	for _, e := range el.Events(register.HashSHA256) {
+		if e.Type == EFIGPTEvent {
+			gptData = e.Data
+		}
+	}
+	got, err := ParseUEFIGPTData(gptData)
+	if err != nil {
+		t.Fatalf("ParseUEFIGPTData(): %v", err)
+	}
+	want := UEFIGPTData{
+		DiskGUID: "2aeb90b6-e13c-4197-8ee2-59e9ed2959dc",
+		Partitions: []GPTPartition{
+			{
+				TypeGUID:      "c12a7328-f81f-11d2-ba4b-00a0c93ec93b",
+				PartitionGUID: "ef72374c-2630-46a1-88d6-082693781140",
+				StartingLBA:   2048,
+				EndingLBA:     411647,
+				Name:          "EFI System Partition",
+			},
+			{
+				TypeGUID:      "0fc63daf-8483-4772-8e79-3d69d8477de4",
+				PartitionGUID: "784c61c1-1ef8-46c5-8001-12ff4c289c3e",
+				StartingLBA:   411648,
+				EndingLBA:     41940991,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParseUEFIGPTData(): unexpected diff (-want +got):\n%s", diff)
+	}
+
+	badSignature := append([]byte("NOT PART"), gptData[8:]...)
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"short header", gptData[:gptDataMinLen-1]},
+		{"bad signature", badSignature},
+		{"truncated partition", gptData[:len(gptData)-1]},
+		{"trailing data", append(append([]byte(nil), gptData...), 0)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseUEFIGPTData(tc.data); err == nil {
+				t.Errorf("ParseUEFIGPTData(): got nil, want error")
+			}
+		})
+	}
+}
+
+func TestFindCapping(t *testing.T) {
+	event := func(seq, index int, typ EventType, data []byte) Event {
+		digest := sha256.Sum256(data)
+		return Event{sequence: seq, Index: index, Type: typ, Data: data, Digest: digest[:], hash: crypto.SHA256}
+	}
+	errorSeparator := []byte{0xff, 0xff, 0xff, 0xff}
+	var capped []Event
+	capped = append(capped, event(0, 0, SCRTMVersion, []byte("v1")))
+	for pcr := 0; pcr < 8; pcr++ {
+		capped = append(capped, event(pcr+1, pcr, Separator, errorSeparator))
+	}
+	capped = append(capped, event(9, 14, Ipl, []byte("MokList\x00")))
+
+	got, err := FindCapping(capped, crypto.SHA256)
+	if err != nil {
+		t.Fatalf("FindCapping(): %v", err)
+	}
+	want := &Capping{Nums: []uint32{1, 2, 3, 4, 5, 6, 7, 8}, Data: errorSeparator}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FindCapping(): unexpected diff (-want +got):\n%s", diff)
+	}
+
+	// Error separators in only some PCRs, as measured by OVMF, are not a
+	// capping.
+	partial := []Event{
+		event(1, 2, Separator, errorSeparator),
+		event(2, 7, Separator, errorSeparator),
+		event(3, 7, EFIVariableAuthority, []byte("db")),
+	}
+	if got, err := FindCapping(partial, crypto.SHA256); err != nil || got != nil {
+		t.Errorf("FindCapping(partial) = %v, %v, want nil", got, err)
+	}
+	if got, err := FindCapping(nil, crypto.SHA256); err != nil || got != nil {
+		t.Errorf("FindCapping(nil) = %v, %v, want nil", got, err)
+	}
+
+	// The capping separator digests are checked with the bank's hash, not
+	// one chosen by the digest size.
+	if crypto.SHA3_256.Available() {
+		sha3Capped := make([]Event, len(capped))
+		for i, e := range capped {
+			h := crypto.SHA3_256.New()
+			h.Write(e.Data)
+			e.Digest, e.hash = h.Sum(nil), crypto.SHA3_256
+			sha3Capped[i] = e
+		}
+		if got, err := FindCapping(sha3Capped, crypto.SHA3_256); err != nil || got == nil {
+			t.Errorf("FindCapping(SHA3-256) = %v, %v, want a capping", got, err)
+		}
+		if _, err := FindCapping(sha3Capped, crypto.SHA256); err == nil {
+			t.Errorf("FindCapping(SHA3-256 events, SHA-256): got nil, want error")
+		}
+	}
+
+	unmeasured := append([]Event(nil), capped...)
+	unmeasured[3].Digest = make([]byte, crypto.SHA256.Size())
+	mixed := append([]Event(nil), capped...)
+	mixed[3] = event(3, 2, Separator, []byte{1, 0, 0, 0})
+	for _, tc := range []struct {
+		name   string
+		events []Event
+	}{
+		{"unmeasured cap", unmeasured},
+		{"mixed separator data", mixed},
+		{"event after cap", append(append([]Event(nil), capped...), event(10, 4, EFIAction, []byte("Calling EFI Application from Boot Option")))},
+		{"capped twice", append(append([]Event(nil), capped...), event(10, 7, Separator, errorSeparator))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := FindCapping(tc.events, crypto.SHA256); err == nil {
+				t.Errorf("FindCapping(): got nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseVariableEvent(t *testing.T) {
+	data, err := os.ReadFile("../testdata/eventlogs/tpm/rhel8-uefi.bin")
+	if err != nil {
+		t.Fatalf("reading test data: %v", err)
+	}
+	el, err := ParseEventLog(data, ParseOpts{})
+	if err != nil {
+		t.Fatalf("parsing event log: %v", err)
+	}
+	vendors := make(map[string]string)
+	for _, e := range el.Events(register.HashSHA256) {
+		v, err := ParseVariableEvent(e)
+		switch e.Type {
+		case EFIVariableDriverConfig, EFIVariableBoot, EFIVariableBoot2, EFIVariableAuthority:
+			if err != nil {
+				t.Fatalf("ParseVariableEvent(event %d): %v", e.Num(), err)
+			}
+			vendors[v.VarName()] = v.VendorGUID()
+		default:
+			if err == nil {
+				t.Errorf("ParseVariableEvent(%v event %d): got nil, want error", e.Type, e.Num())
+			}
+		}
+	}
+	for name, want := range map[string]string{
+		"SecureBoot": EFIGlobalVariableGUID,
+		"PK":         EFIGlobalVariableGUID,
+		"BootOrder":  EFIGlobalVariableGUID,
+		"db":         EFIImageSecurityDatabaseGUID,
+		"dbx":        EFIImageSecurityDatabaseGUID,
+		"Shim":       ShimLockGUID,
+	} {
+		if got := vendors[name]; got != want {
+			t.Errorf("ParseVariableEvent(): got %s vendor GUID %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestKnownEFIAction(t *testing.T) {
+	for _, action := range []string{ExitBootServicesSuccess, StartOptionROMScan, CallingINT19h} {
+		if got, ok := KnownEFIAction([]byte(action)); !ok || got != action {
+			t.Errorf("KnownEFIAction(%q) = %q, %v, want %q, true", action, got, ok, action)
+		}
+	}
+	if got, ok := KnownEFIAction([]byte("Booting BEV Device USB")); ok {
+		t.Errorf("KnownEFIAction() = %q, want no known action", got)
+	}
+}
+
+func TestVerifyUnavailableHash(t *testing.T) {
+	// MD4 stands in for a hash, e.g., SHA3 before Go 1.24, whose
+	// implementation is not linked in.
+	unavailable := crypto.MD4
+	if unavailable.Available() {
+		t.Fatalf("%v is linked in", unavailable)
+	}
+	el := &EventLog{
+		Algs: []register.HashAlg{register.HashSHA256},
+		rawEvents: []rawEvent{{
+			sequence: 1,
+			typ:      EFIAction,
+			data:     []byte("action"),
+			digests:  []digest{{hash: unavailable, data: make([]byte, unavailable.Size())}},
+		}},
+	}
+	mr := register.PCR{Index: 0, Digest: make([]byte, unavailable.Size()), DigestAlg: unavailable}
+	if _, err := el.Verify([]register.MR{mr}); err == nil {
+		t.Errorf("Verify() with an unavailable %v bank succeeded, want error", unavailable)
+	}
+}
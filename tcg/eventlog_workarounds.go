@@ -55,33 +55,27 @@ func inject(e *EventLog, pcr int, data string) error {
 	return nil
 }
 
-const (
-	ebsInvocation = "Exit Boot Services Invocation"
-	ebsSuccess    = "Exit Boot Services Returned with Success"
-	ebsFailure    = "Exit Boot Services Returned with Failure"
-)
-
 // EventlogWorkarounds fix known event log issues/bugs.
 var EventlogWorkarounds = []elWorkaround{
 	{
 		id:          "EBS Invocation + Success",
 		affectedPCR: 5,
 		apply: func(e *EventLog) error {
-			return inject2(e, 5, ebsInvocation, ebsSuccess)
+			return inject2(e, 5, ExitBootServicesInvocation, ExitBootServicesSuccess)
 		},
 	},
 	{
 		id:          "EBS Invocation + Failure",
 		affectedPCR: 5,
 		apply: func(e *EventLog) error {
-			return inject2(e, 5, ebsInvocation, ebsFailure)
+			return inject2(e, 5, ExitBootServicesInvocation, ExitBootServicesFailure)
 		},
 	},
 	{
 		id:          "EBS Invocation + Failure + Success",
 		affectedPCR: 5,
 		apply: func(e *EventLog) error {
-			return inject3(e, 5, ebsInvocation, ebsFailure, ebsSuccess)
+			return inject3(e, 5, ExitBootServicesInvocation, ExitBootServicesFailure, ExitBootServicesSuccess)
 		},
 	},
 }
@@ -16,11 +16,13 @@ package tcg
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/x509"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"unicode/utf16"
 )
 
@@ -105,6 +107,44 @@ const (
 	EFIVariableAuthority       EventType = 0x800000E0
 )
 
+// Intel TXT Events (Intel Trusted Execution Technology Software Development
+// Guide, Appendix "TPM Event Log"). These are measured into the DRTM PCRs
+// during a dynamic launch.
+const (
+	TXTEventBase          EventType = 0x00000400
+	TXTPCRMapping         EventType = 0x00000401
+	TXTHashStart          EventType = 0x00000402
+	TXTCombinedHash       EventType = 0x00000403
+	TXTMLEHash            EventType = 0x00000404
+	TXTBIOSACRegData      EventType = 0x0000040A
+	TXTCPUSCRTMStat       EventType = 0x0000040B
+	TXTLCPControlHash     EventType = 0x0000040C
+	TXTElementsHash       EventType = 0x0000040D
+	TXTSTMHash            EventType = 0x0000040E
+	TXTOSSINITDataCapHash EventType = 0x0000040F
+	TXTSINITPubKeyHash    EventType = 0x00000410
+	TXTLCPHash            EventType = 0x00000411
+	TXTLCPDetailsHash     EventType = 0x00000412
+	TXTLCPAuthoritiesHash EventType = 0x00000413
+	TXTNVInfoHash         EventType = 0x00000414
+	TXTColdBootBIOSHash   EventType = 0x00000415
+	TXTKMHash             EventType = 0x00000416
+	TXTBPMHash            EventType = 0x00000417
+	TXTKMInfoHash         EventType = 0x00000418
+	TXTBPMInfoHash        EventType = 0x00000419
+	TXTBootPolHash        EventType = 0x0000041A
+	TXTCapValue           EventType = 0x000004FF
+)
+
+// Linux Secure Launch Events (TrenchBoot). The Secure Launch kernel measures
+// its configuration and images into the DRTM PCRs after an Intel TXT or AMD
+// SKINIT dynamic launch.
+const (
+	SLaunch      EventType = 0x00000502
+	SLaunchStart EventType = 0x00000503
+	SLaunchEnd   EventType = 0x00000504
+)
+
 // EventTypeNames maps an EventType to its name.
 var EventTypeNames = map[EventType]string{
 	PrebootCert:          "Preboot Cert",
@@ -142,6 +182,33 @@ var EventTypeNames = map[EventType]string{
 	EFIVariableBoot2:           "EFI Variable Boot2",
 	EFIHCRTMEvent:              "EFI H-CRTM Event",
 	EFIVariableAuthority:       "EFI Variable Authority",
+
+	TXTEventBase:          "TXT Event Base",
+	TXTPCRMapping:         "TXT PCR Mapping",
+	TXTHashStart:          "TXT Hash Start",
+	TXTCombinedHash:       "TXT Combined Hash",
+	TXTMLEHash:            "TXT MLE Hash",
+	TXTBIOSACRegData:      "TXT BIOS AC Reg Data",
+	TXTCPUSCRTMStat:       "TXT CPU S-CRTM Stat",
+	TXTLCPControlHash:     "TXT LCP Control Hash",
+	TXTElementsHash:       "TXT Elements Hash",
+	TXTSTMHash:            "TXT STM Hash",
+	TXTOSSINITDataCapHash: "TXT OS SINIT Data Cap Hash",
+	TXTSINITPubKeyHash:    "TXT SINIT Public Key Hash",
+	TXTLCPHash:            "TXT LCP Hash",
+	TXTLCPDetailsHash:     "TXT LCP Details Hash",
+	TXTLCPAuthoritiesHash: "TXT LCP Authorities Hash",
+	TXTNVInfoHash:         "TXT NV Info Hash",
+	TXTColdBootBIOSHash:   "TXT Cold Boot BIOS Hash",
+	TXTKMHash:             "TXT KM Hash",
+	TXTBPMHash:            "TXT BPM Hash",
+	TXTKMInfoHash:         "TXT KM Info Hash",
+	TXTBPMInfoHash:        "TXT BPM Info Hash",
+	TXTBootPolHash:        "TXT Boot Policy Hash",
+	TXTCapValue:           "TXT Cap Value",
+	SLaunch:               "Secure Launch",
+	SLaunchStart:          "Secure Launch Start",
+	SLaunchEnd:            "Secure Launch End",
 }
 
 var eventTypeStrings = map[uint32]string{
@@ -179,6 +246,32 @@ var eventTypeStrings = map[uint32]string{
 	0x8000000C: "EV_EFI_VARIABLE_BOOT2",
 	0x80000010: "EV_EFI_HCRTM_EVENT",
 	0x800000E0: "EV_EFI_VARIABLE_AUTHORITY",
+	0x00000400: "EVTYPE_BASE",
+	0x00000401: "EVTYPE_PCRMAPPING",
+	0x00000402: "EVTYPE_HASH_START",
+	0x00000403: "EVTYPE_COMBINED_HASH",
+	0x00000404: "EVTYPE_MLE_HASH",
+	0x0000040A: "EVTYPE_BIOSAC_REG_DATA",
+	0x0000040B: "EVTYPE_CPU_SCRTM_STAT",
+	0x0000040C: "EVTYPE_LCP_CONTROL_HASH",
+	0x0000040D: "EVTYPE_ELEMENTS_HASH",
+	0x0000040E: "EVTYPE_STM_HASH",
+	0x0000040F: "EVTYPE_OSSINITDATA_CAP_HASH",
+	0x00000410: "EVTYPE_SINIT_PUBKEY_HASH",
+	0x00000411: "EVTYPE_LCP_HASH",
+	0x00000412: "EVTYPE_LCP_DETAILS_HASH",
+	0x00000413: "EVTYPE_LCP_AUTHORITIES_HASH",
+	0x00000414: "EVTYPE_NV_INFO_HASH",
+	0x00000415: "EVTYPE_COLD_BOOT_BIOS_HASH",
+	0x00000416: "EVTYPE_KM_HASH",
+	0x00000417: "EVTYPE_BPM_HASH",
+	0x00000418: "EVTYPE_KM_INFO_HASH",
+	0x00000419: "EVTYPE_BPM_INFO_HASH",
+	0x0000041A: "EVTYPE_BOOT_POL_HASH",
+	0x000004FF: "EVTYPE_CAP_VALUE",
+	0x00000502: "EVTYPE_SLAUNCH",
+	0x00000503: "EVTYPE_SLAUNCH_START",
+	0x00000504: "EVTYPE_SLAUNCH_END",
 }
 
 // KnownName returns an event type's readable name if it exists.
@@ -204,6 +297,17 @@ func (e EventType) TCGString() string {
 	return fmt.Sprintf("EventType(0x%08x)", uint32(e))
 }
 
+// ParseTCGString returns the event type with the given name as it appears in
+// the TCG spec, e.g., "EV_EFI_ACTION". It is the inverse of TCGString.
+func ParseTCGString(name string) (EventType, error) {
+	for et, tcgStr := range eventTypeStrings {
+		if tcgStr == name {
+			return EventType(et), nil
+		}
+	}
+	return EventType(0), fmt.Errorf("unknown event type name %q", name)
+}
+
 // UntrustedParseEventType returns the event type indicated by
 // the provided value.
 func UntrustedParseEventType(et uint32) (EventType, error) {
@@ -223,10 +327,107 @@ func UntrustedParseEventType(et uint32) (EventType, error) {
 // Table 17 EV_EFI_ACTION Strings.
 const (
 	// Measured when Boot Manager attempts to execute code from a Boot Option.
-	CallingEFIApplication      string = "Calling EFI Application from Boot Option"
+	CallingEFIApplication string = "Calling EFI Application from Boot Option"
+	// Measured when a Boot Option returns control to the Boot Manager.
+	ReturningFromEFIApplication string = "Returning from EFI Application from Boot Option"
+	// Measured when the OS loader calls ExitBootServices().
 	ExitBootServicesInvocation string = "Exit Boot Services Invocation"
+	// Measured when ExitBootServices() fails, e.g., due to a stale memory map.
+	ExitBootServicesFailure string = "Exit Boot Services Returned with Failure"
+	// Measured when ExitBootServices() succeeds.
+	ExitBootServicesSuccess string = "Exit Boot Services Returned with Success"
+	// Measured into PCR7 when a UEFI debugger is enabled.
+	UEFIDebugMode string = "UEFI Debug Mode"
+	// Measured into PCR7 when the firmware disables DMA protection.
+	DMAProtectionDisabled string = "DMA Protection Disabled"
+)
+
+// Constant events used with type "EV_ACTION".
+// Taken from TCG PC Client Platform Firmware Profile Specification,
+// Table "EV_ACTION Event Types". The "Booting BCV Device" and "Booting BEV
+// Device" actions are followed by the device name, so they are not constant.
+const (
+	// Measured when the BIOS calls the boot loader through INT 19h.
+	CallingINT19h string = "Calling INT 19h"
+	// Measured when the boot loader returns from INT 19h.
+	ReturnedINT19h string = "Returned INT 19h"
+	// Measured when the boot loader returns through INT 18h.
+	ReturnViaINT18h string = "Return via INT 18h"
+	// Measured when the firmware starts to scan for option ROMs.
+	StartOptionROMScan string = "Start Option ROM Scan"
+	// Measured when the user enters the firmware setup.
+	EnteringROMBasedSetup string = "Entering ROM Based Setup"
+	// Measured when the firmware user password is entered.
+	UserPasswordEntered string = "User Password Entered"
+	// Measured when the firmware administrator password is entered.
+	AdministratorPasswordEntered string = "Administrator Password Entered"
+	// Measured when the firmware maintenance password is entered.
+	MaintenancePasswordEntered string = "Maintenance Password Entered"
+	// Measured when the chassis was opened.
+	ChassisIntrusion string = "Chassis Intrusion"
+	// Measured when the platform was woken by a wake event.
+	WakeEvent1 string = "Wake Event 1"
 )
 
+// efiActions are the known EV_EFI_ACTION and EV_ACTION strings.
+var efiActions = []string{
+	CallingEFIApplication,
+	ReturningFromEFIApplication,
+	ExitBootServicesInvocation,
+	ExitBootServicesFailure,
+	ExitBootServicesSuccess,
+	UEFIDebugMode,
+	DMAProtectionDisabled,
+	CallingINT19h,
+	ReturnedINT19h,
+	ReturnViaINT18h,
+	StartOptionROMScan,
+	EnteringROMBasedSetup,
+	UserPasswordEntered,
+	AdministratorPasswordEntered,
+	MaintenancePasswordEntered,
+	ChassisIntrusion,
+	WakeEvent1,
+}
+
+// KnownEFIAction returns the known EV_EFI_ACTION or EV_ACTION string the event
+// data is equal to, if any. The data is not trusted unless the event digest
+// has been verified against it.
+func KnownEFIAction(data []byte) (string, bool) {
+	for _, action := range efiActions {
+		if string(data) == action {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+type actionDigestKey struct {
+	hash   crypto.Hash
+	action string
+}
+
+// actionDigests memoizes the digests of the known EFI actions, which are
+// compared against on every extraction.
+var actionDigests sync.Map
+
+// EFIActionDigest returns the digest measured for the action string, to
+// compare against an event's replayed digest. Digests of the known actions
+// are only computed once per hash algorithm.
+func EFIActionDigest(hash crypto.Hash, action string) []byte {
+	key := actionDigestKey{hash, action}
+	if digest, ok := actionDigests.Load(key); ok {
+		return bytes.Clone(digest.([]byte))
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(action))
+	digest := hasher.Sum(nil)
+	if _, known := KnownEFIAction([]byte(action)); known {
+		actionDigests.Store(key, bytes.Clone(digest))
+	}
+	return digest
+}
+
 // EFIDeviceType describes the type of a device specified by a device path.
 type EFIDeviceType uint8
 
@@ -270,8 +471,8 @@ func ParseTaggedEventData(d []byte) (*TaggedEventData, error) {
 		return nil, fmt.Errorf("reading header: %w", err)
 	}
 
-	if int(header.DataLen) > len(d) {
-		return nil, fmt.Errorf("tagged event len (%d bytes) larger than data length (%d bytes)", header.DataLen, len(d))
+	if int(header.DataLen) > r.Len() {
+		return nil, fmt.Errorf("tagged event len (%d bytes) larger than data length (%d bytes)", header.DataLen, r.Len())
 	}
 
 	out := TaggedEventData{
@@ -341,6 +542,21 @@ func (v *UEFIVariableData) Encode() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// NewUEFIVariableData returns the UEFI_VARIABLE_DATA of a variable, given the
+// text form of its vendor GUID, e.g., "8be4df61-93ca-11d2-aa0d-00e098032b8c"
+// for EFI global variables.
+func NewUEFIVariableData(vendorGUID string, name string, data []byte) (UEFIVariableData, error) {
+	guid, err := parseEFIGUID(vendorGUID)
+	if err != nil {
+		return UEFIVariableData{}, err
+	}
+	return UEFIVariableData{
+		Header:       UEFIVariableDataHeader{VariableName: guid},
+		UnicodeName:  utf16.Encode([]rune(name)),
+		VariableData: data,
+	}, nil
+}
+
 // ParseUEFIVariableData parses the data section of an event structured as
 // a UEFI variable.
 //
@@ -373,6 +589,39 @@ func (v *UEFIVariableData) VarName() string {
 	return string(utf16.Decode(v.UnicodeName))
 }
 
+// VendorGUID returns the text form of the UEFI variable vendor GUID, e.g.,
+// EFIGlobalVariableGUID.
+func (v *UEFIVariableData) VendorGUID() string {
+	return v.Header.VariableName.String()
+}
+
+// Vendor GUIDs of measured UEFI variables, as returned by
+// UEFIVariableData.VendorGUID.
+const (
+	// EFIGlobalVariableGUID is the vendor of the architectural variables,
+	// e.g., SecureBoot, PK, KEK, BootOrder, and Boot####.
+	EFIGlobalVariableGUID = "8be4df61-93ca-11d2-aa0d-00e098032b8c"
+	// EFIImageSecurityDatabaseGUID is the vendor of the db and dbx variables.
+	EFIImageSecurityDatabaseGUID = "d719b2cb-3d3a-4596-a3bc-dad00e67656f"
+	// ShimLockGUID is the vendor of the variables measured by shim, e.g.,
+	// MokList and SbatLevel.
+	ShimLockGUID = "605dab50-e046-4300-abb6-3dd810dd8b23"
+)
+
+// ParseVariableEvent parses the UEFI_VARIABLE_DATA of an EV_EFI_VARIABLE_*
+// event, i.e., an EFIVariableDriverConfig, EFIVariableBoot, EFIVariableBoot2,
+// or EFIVariableAuthority event. The event type is untrusted, so callers
+// should check the digest of the event data. Trailing bytes after the variable
+// data are ignored, as measured by some versions of shim.
+func ParseVariableEvent(e Event) (UEFIVariableData, error) {
+	switch e.Type {
+	case EFIVariableDriverConfig, EFIVariableBoot, EFIVariableBoot2, EFIVariableAuthority:
+	default:
+		return UEFIVariableData{}, fmt.Errorf("event type %v is not a UEFI variable event", e.Type)
+	}
+	return ParseUEFIVariableData(bytes.NewReader(e.Data))
+}
+
 // SignatureData parses a UEFI variable for signature data.
 func (v *UEFIVariableData) SignatureData() (certs []x509.Certificate, hashes [][]byte, err error) {
 	return parseEfiSignatureList(v.VariableData)
@@ -528,6 +777,317 @@ func parseEfiSignatureList(b []byte) ([]x509.Certificate, [][]byte, error) {
 	return certificates, hashes, nil
 }
 
+// SignatureListError describes a malformed EFI_SIGNATURE_LIST or signature
+// entry that was skipped while parsing.
+type SignatureListError struct {
+	// Offset is the byte offset of the malformed entry in the variable data,
+	// or of its signature list if the whole list was skipped.
+	Offset int
+	Err    error
+}
+
+// Error returns the offset and cause of the malformed entry.
+func (e SignatureListError) Error() string {
+	return fmt.Sprintf("malformed signature list entry at offset %d: %v", e.Offset, e.Err)
+}
+
+// PartialSignatureData is like SignatureData, but skips malformed signature
+// lists and entries rather than failing. It returns the well-formed
+// certificates and hashes, and an error for each skipped list or entry.
+// Parsing stops at a malformed signature list header, as the lists after it
+// cannot be located.
+func (v *UEFIVariableData) PartialSignatureData() (certs []x509.Certificate, hashes [][]byte, errs []SignatureListError) {
+	return parsePartialEfiSignatureList(v.VariableData)
+}
+
+func parsePartialEfiSignatureList(b []byte) ([]x509.Certificate, [][]byte, []SignatureListError) {
+	var (
+		certificates []x509.Certificate
+		hashes       [][]byte
+		errs         []SignatureListError
+	)
+	if len(b) < 28 {
+		// Being passed an empty signature list here appears to be valid
+		return nil, nil, nil
+	}
+	headerSize := binary.Size(efiSignatureListHeader{})
+	for offset := 0; offset < len(b); {
+		listOffset := offset
+		var header efiSignatureListHeader
+		if len(b)-offset < headerSize {
+			errs = append(errs, SignatureListError{listOffset, errors.New("truncated signature list header")})
+			break
+		}
+		binary.Read(bytes.NewReader(b[offset:]), binary.LittleEndian, &header)
+		if header.SignatureListSize < uint32(headerSize) || header.SignatureListSize > uint32(len(b)-offset) {
+			errs = append(errs, SignatureListError{listOffset, fmt.Errorf("invalid signature list size %d", header.SignatureListSize)})
+			break
+		}
+		list := b[offset+headerSize : offset+int(header.SignatureListSize)]
+		offset += int(header.SignatureListSize)
+
+		if header.SignatureType != certX509SigGUID && header.SignatureType != hashSHA256SigGUID {
+			errs = append(errs, SignatureListError{listOffset, fmt.Errorf("unhandled signature type %s", header.SignatureType)})
+			continue
+		}
+		if header.SignatureSize <= 16 || header.SignatureHeaderSize > uint32(len(list)) {
+			errs = append(errs, SignatureListError{listOffset, fmt.Errorf("invalid signature header size %d or signature size %d", header.SignatureHeaderSize, header.SignatureSize)})
+			continue
+		}
+		sigSize := int(header.SignatureSize)
+		for entry := int(header.SignatureHeaderSize); entry < len(list); entry += sigSize {
+			entryOffset := listOffset + headerSize + entry
+			if entry+sigSize > len(list) {
+				errs = append(errs, SignatureListError{entryOffset, errors.New("truncated signature")})
+				break
+			}
+			// Skip the SignatureOwner GUID.
+			data := bytes.Clone(list[entry+16 : entry+sigSize])
+			if header.SignatureType == certX509SigGUID {
+				cert, err := x509.ParseCertificate(data)
+				if err != nil {
+					errs = append(errs, SignatureListError{entryOffset, err})
+					continue
+				}
+				certificates = append(certificates, *cert)
+				continue
+			}
+			if len(data) != crypto.SHA256.Size() {
+				errs = append(errs, SignatureListError{entryOffset, fmt.Errorf("invalid SHA-256 hash length: %d", len(data))})
+				continue
+			}
+			hashes = append(hashes, data)
+		}
+	}
+	return certificates, hashes, errs
+}
+
+// signatureTypeNames names the EFI_SIGNATURE_LIST signature types after
+// their EFI_CERT_<name>_GUID definitions.
+var signatureTypeNames = map[efiGUID]string{
+	certX509SigGUID:          "X509",
+	hashSHA256SigGUID:        "SHA256",
+	hashSHA1SigGUID:          "SHA1",
+	hashSHA224SigGUID:        "SHA224",
+	hashSHA384SigGUID:        "SHA384",
+	hashSHA512SigGUID:        "SHA512",
+	keyRSA2048SigGUID:        "RSA2048",
+	certRSA2048SHA256SigGUID: "RSA2048_SHA256",
+	certRSA2048SHA1SigGUID:   "RSA2048_SHA1",
+	certHashSHA256SigGUID:    "X509_SHA256",
+	certHashSHA384SigGUID:    "X509_SHA384",
+	certHashSHA512SigGUID:    "X509_SHA512",
+}
+
+// SignatureEntry is an EFI_SIGNATURE_DATA entry of an EFI_SIGNATURE_LIST.
+type SignatureEntry struct {
+	// Type names the signature type of the list, e.g., "X509" or "SHA256"
+	// for EFI_CERT_X509_GUID or EFI_CERT_SHA256_GUID, or is empty if the type
+	// is unknown.
+	Type string
+	// TypeGUID is the SignatureType GUID of the list.
+	TypeGUID string
+	// Owner is the SignatureOwner GUID, e.g.,
+	// "77fa9abd-0359-4d32-bd60-28f4e78f784b" for Microsoft.
+	Owner string
+	// Data is the signature data, e.g., a DER certificate or a hash.
+	Data []byte
+	// Cert is the parsed certificate of an X.509 entry.
+	Cert *x509.Certificate
+}
+
+// SignatureEntries parses every entry of the EFI_SIGNATURE_LISTs in a UEFI
+// variable, in variable order, with its owner. Unlike SignatureData, it
+// decodes lists of all signature types, so variables mixing certificates,
+// hashes, and other signatures are fully represented. Malformed lists and
+// entries, including X.509 entries that do not parse, are skipped as in
+// PartialSignatureData.
+func (v *UEFIVariableData) SignatureEntries() ([]SignatureEntry, []SignatureListError) {
+	return parseEfiSignatureEntries(v.VariableData)
+}
+
+func parseEfiSignatureEntries(b []byte) ([]SignatureEntry, []SignatureListError) {
+	var (
+		entries []SignatureEntry
+		errs    []SignatureListError
+	)
+	if len(b) < 28 {
+		// Being passed an empty signature list here appears to be valid
+		return nil, nil
+	}
+	headerSize := binary.Size(efiSignatureListHeader{})
+	for offset := 0; offset < len(b); {
+		listOffset := offset
+		var header efiSignatureListHeader
+		if len(b)-offset < headerSize {
+			errs = append(errs, SignatureListError{listOffset, errors.New("truncated signature list header")})
+			break
+		}
+		binary.Read(bytes.NewReader(b[offset:]), binary.LittleEndian, &header)
+		if header.SignatureListSize < uint32(headerSize) || header.SignatureListSize > uint32(len(b)-offset) {
+			errs = append(errs, SignatureListError{listOffset, fmt.Errorf("invalid signature list size %d", header.SignatureListSize)})
+			break
+		}
+		list := b[offset+headerSize : offset+int(header.SignatureListSize)]
+		offset += int(header.SignatureListSize)
+
+		if header.SignatureSize < 16 || header.SignatureHeaderSize > uint32(len(list)) {
+			errs = append(errs, SignatureListError{listOffset, fmt.Errorf("invalid signature header size %d or signature size %d", header.SignatureHeaderSize, header.SignatureSize)})
+			continue
+		}
+		sigSize := int(header.SignatureSize)
+		for entry := int(header.SignatureHeaderSize); entry < len(list); entry += sigSize {
+			entryOffset := listOffset + headerSize + entry
+			if entry+sigSize > len(list) {
+				errs = append(errs, SignatureListError{entryOffset, errors.New("truncated signature")})
+				break
+			}
+			var owner efiGUID
+			binary.Read(bytes.NewReader(list[entry:entry+16]), binary.LittleEndian, &owner)
+			out := SignatureEntry{
+				Type:     signatureTypeNames[header.SignatureType],
+				TypeGUID: header.SignatureType.String(),
+				Owner:    owner.String(),
+				Data:     bytes.Clone(list[entry+16 : entry+sigSize]),
+			}
+			if header.SignatureType == certX509SigGUID {
+				cert, err := x509.ParseCertificate(out.Data)
+				if err != nil {
+					errs = append(errs, SignatureListError{entryOffset, err})
+					continue
+				}
+				out.Cert = cert
+			}
+			entries = append(entries, out)
+		}
+	}
+	return entries, errs
+}
+
+// EncodeEFISignatureList encodes certificates and SHA-256 hashes as
+// EFI_SIGNATURE_LISTs, as in the db, dbx, PK, and KEK variables. Each
+// certificate is encoded in its own list, followed by a single list of all
+// hashes. All signatures use the given owner GUID, e.g.,
+// "77fa9abd-0359-4d32-bd60-28f4e78f784b", or the zero GUID if empty.
+//
+// Parsing the result with UEFIVariableData.SignatureData returns the same
+// certificates and hashes. Variables authored in the same layout encode to
+// the same bytes.
+func EncodeEFISignatureList(certs []x509.Certificate, hashes [][]byte, owner string) ([]byte, error) {
+	var ownerGUID efiGUID
+	if owner != "" {
+		var err error
+		if ownerGUID, err = parseEFIGUID(owner); err != nil {
+			return nil, err
+		}
+	}
+	buf := new(bytes.Buffer)
+	writeList := func(sigType efiGUID, signatures [][]byte) {
+		header := efiSignatureListHeader{
+			SignatureType:     sigType,
+			SignatureListSize: uint32(28 + len(signatures)*(16+len(signatures[0]))),
+			SignatureSize:     uint32(16 + len(signatures[0])),
+		}
+		binary.Write(buf, binary.LittleEndian, header)
+		for _, signature := range signatures {
+			binary.Write(buf, binary.LittleEndian, ownerGUID)
+			buf.Write(signature)
+		}
+	}
+	for _, cert := range certs {
+		if 28+16+len(cert.Raw) > maxDataLen {
+			return nil, fmt.Errorf("certificate too large: %d > %d", len(cert.Raw), maxDataLen)
+		}
+		writeList(certX509SigGUID, [][]byte{cert.Raw})
+	}
+	if len(hashes) > 0 {
+		for _, hash := range hashes {
+			if len(hash) != crypto.SHA256.Size() {
+				return nil, fmt.Errorf("invalid SHA-256 hash length: %d", len(hash))
+			}
+		}
+		if 28+len(hashes)*(16+crypto.SHA256.Size()) > maxDataLen {
+			return nil, fmt.Errorf("too many hashes: %d", len(hashes))
+		}
+		writeList(hashSHA256SigGUID, hashes)
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeSignatureEntries encodes signature list entries as EFI_SIGNATURE_LISTs,
+// keeping the type and owner GUID of each entry. It is the inverse of
+// UEFIVariableData.SignatureEntries. X.509 entries are encoded in their own
+// list, and consecutive entries of other types with the same type and size
+// share a list, which is the layout of variables authored by common tools.
+func EncodeSignatureEntries(entries []SignatureEntry) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for start := 0; start < len(entries); {
+		sigType, err := parseEFIGUID(entries[start].TypeGUID)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: invalid signature type: %v", start, err)
+		}
+		size := len(entries[start].Data)
+		end := start + 1
+		for sigType != certX509SigGUID && end < len(entries) &&
+			entries[end].TypeGUID == entries[start].TypeGUID && len(entries[end].Data) == size {
+			end++
+		}
+		listSize := 28 + (end-start)*(16+size)
+		if listSize > maxDataLen {
+			return nil, fmt.Errorf("entry %d: signature list too large: %d > %d", start, listSize, maxDataLen)
+		}
+		binary.Write(buf, binary.LittleEndian, efiSignatureListHeader{
+			SignatureType:     sigType,
+			SignatureListSize: uint32(listSize),
+			SignatureSize:     uint32(16 + size),
+		})
+		for i := start; i < end; i++ {
+			var owner efiGUID
+			if entries[i].Owner != "" {
+				if owner, err = parseEFIGUID(entries[i].Owner); err != nil {
+					return nil, fmt.Errorf("entry %d: invalid owner: %v", i, err)
+				}
+			}
+			binary.Write(buf, binary.LittleEndian, owner)
+			buf.Write(entries[i].Data)
+		}
+		start = end
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeEFISignatureData encodes an EFI_SIGNATURE_DATA, e.g., the variable
+// data of a UEFI variable authority event, with the given owner GUID. An
+// empty owner encodes the zero GUID.
+func EncodeEFISignatureData(owner string, data []byte) ([]byte, error) {
+	var ownerGUID efiGUID
+	if owner != "" {
+		var err error
+		if ownerGUID, err = parseEFIGUID(owner); err != nil {
+			return nil, err
+		}
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, ownerGUID)
+	buf.Write(data)
+	return buf.Bytes(), nil
+}
+
+// parseEFIGUID parses the text form of an EFI_GUID, e.g.,
+// "77fa9abd-0359-4d32-bd60-28f4e78f784b".
+func parseEFIGUID(s string) (efiGUID, error) {
+	var (
+		guid   efiGUID
+		d4, d5 uint64
+	)
+	_, err := fmt.Sscanf(s, "%08x-%04x-%04x-%04x-%012x", &guid.Data1, &guid.Data2, &guid.Data3, &d4, &d5)
+	if err != nil || len(s) != 36 {
+		return efiGUID{}, fmt.Errorf("invalid GUID %q", s)
+	}
+	binary.BigEndian.PutUint64(guid.Data4[:], d4<<48|d5)
+	return guid, nil
+}
+
 // EFISignatureData represents the EFI_SIGNATURE_DATA type.
 // See section "31.4.1 Signature Database" in the specification
 // for more information.
@@ -621,8 +1181,14 @@ func parseDevicePathElement(r io.Reader) (EFIDevicePathElement, error) {
 
 // DevicePath returns the device path of an EFI_IMAGE_LOAD_EVENT.
 func (h *EFIImageLoad) DevicePath() ([]EFIDevicePathElement, error) {
+	return parseDevicePath(h.DevPathData)
+}
+
+// parseDevicePath parses the nodes of a device path up to its first end
+// node.
+func parseDevicePath(b []byte) ([]EFIDevicePathElement, error) {
 	var (
-		r   = bytes.NewReader(h.DevPathData)
+		r   = bytes.NewReader(b)
 		out []EFIDevicePathElement
 	)
 
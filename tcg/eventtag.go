@@ -0,0 +1,52 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bytes"
+)
+
+// Tagged event IDs of the EV_EVENT_TAG events measured by the Linux EFI stub
+// into PCR 9. The event digest is the digest of the measured content, e.g.,
+// the kernel's load options, and the tagged event data only describes it.
+const (
+	LinuxInitrdEventTag      uint32 = 0x8F3B22EC
+	LinuxLoadOptionsEventTag uint32 = 0x8F3B22ED
+)
+
+var linuxEventTagDescriptions = map[uint32]string{
+	LinuxInitrdEventTag:      "Linux initrd",
+	LinuxLoadOptionsEventTag: "LOADED_IMAGE::LoadOptions",
+}
+
+// ParseLinuxEventTag returns the Linux EFI stub tagged event ID of the
+// EV_EVENT_TAG event data, e.g., LinuxLoadOptionsEventTag. The data is either
+// a TCG_PCClientTaggedEvent structure with a known ID, or, as measured by
+// some kernels, only the NUL-terminated description of the tag. The data is
+// not trusted unless the event is verified.
+func ParseLinuxEventTag(data []byte) (uint32, bool) {
+	if tagged, err := ParseTaggedEventData(data); err == nil {
+		if _, ok := linuxEventTagDescriptions[tagged.ID]; ok {
+			return tagged.ID, true
+		}
+	}
+	desc := string(bytes.TrimRight(data, "\x00"))
+	for id, want := range linuxEventTagDescriptions {
+		if desc == want {
+			return id, true
+		}
+	}
+	return 0, false
+}
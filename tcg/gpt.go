@@ -0,0 +1,135 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+// gptSignature is the signature of a UEFI partition table header.
+const gptSignature = "EFI PART"
+
+// gptPartitionEntryLen is the size of the fields of a partition entry. The
+// entries of a partition table may be larger.
+const gptPartitionEntryLen = 128
+
+// uefiPartitionTableHeader is the UEFI_PARTITION_TABLE_HEADER, as measured.
+type uefiPartitionTableHeader struct {
+	Signature                [8]byte
+	Revision                 uint32
+	HeaderSize               uint32
+	HeaderCRC32              uint32
+	Reserved                 uint32
+	MyLBA                    uint64
+	AlternateLBA             uint64
+	FirstUsableLBA           uint64
+	LastUsableLBA            uint64
+	DiskGUID                 efiGUID
+	PartitionEntryLBA        uint64
+	NumberOfPartitionEntries uint32
+	SizeOfPartitionEntry     uint32
+	PartitionEntryArrayCRC32 uint32
+}
+
+// uefiPartitionEntry holds the fields of an EFI_PARTITION_ENTRY.
+type uefiPartitionEntry struct {
+	PartitionTypeGUID   efiGUID
+	UniquePartitionGUID efiGUID
+	StartingLBA         uint64
+	EndingLBA           uint64
+	Attributes          uint64
+	PartitionName       [36]uint16
+}
+
+// GPTPartition is a partition of a GUID partition table.
+type GPTPartition struct {
+	// TypeGUID identifies the purpose of the partition, e.g.,
+	// c12a7328-f81f-11d2-ba4b-00a0c93ec93b for the EFI system partition.
+	TypeGUID string
+	// PartitionGUID is unique to the partition.
+	PartitionGUID string
+	// StartingLBA and EndingLBA are the first and last logical blocks of the
+	// partition, inclusive.
+	StartingLBA uint64
+	EndingLBA   uint64
+	// Attributes are the partition attribute bits.
+	Attributes uint64
+	// Name is the user-readable name of the partition.
+	Name string
+}
+
+// UEFIGPTData is the GUID partition table of the boot disk, as measured in an
+// EV_EFI_GPT_EVENT event.
+type UEFIGPTData struct {
+	// DiskGUID uniquely identifies the disk.
+	DiskGUID string
+	// Partitions are the partitions in use, in partition table order.
+	Partitions []GPTPartition
+}
+
+// ParseUEFIGPTData parses a UEFI_GPT_DATA structure, the data of an
+// EV_EFI_GPT_EVENT event.
+//
+// Section 10.2.6 of the TCG PC Client Platform Firmware Profile Specification.
+func ParseUEFIGPTData(b []byte) (UEFIGPTData, error) {
+	var header uefiPartitionTableHeader
+	r := bytes.NewReader(b)
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return UEFIGPTData{}, fmt.Errorf("reading partition table header: %v", err)
+	}
+	if string(header.Signature[:]) != gptSignature {
+		return UEFIGPTData{}, fmt.Errorf("partition table header has signature %q, want %q", header.Signature[:], gptSignature)
+	}
+	if header.SizeOfPartitionEntry < gptPartitionEntryLen {
+		return UEFIGPTData{}, fmt.Errorf("partition entry size %d is less than %d", header.SizeOfPartitionEntry, gptPartitionEntryLen)
+	}
+	var numPartitions uint64
+	if err := binary.Read(r, binary.LittleEndian, &numPartitions); err != nil {
+		return UEFIGPTData{}, fmt.Errorf("reading number of partitions: %v", err)
+	}
+	entrySize := uint64(header.SizeOfPartitionEntry)
+	if rest := uint64(r.Len()); rest%entrySize != 0 || rest/entrySize != numPartitions {
+		return UEFIGPTData{}, fmt.Errorf("%d partitions of %d bytes do not match the %d bytes after the header", numPartitions, entrySize, rest)
+	}
+
+	out := UEFIGPTData{DiskGUID: header.DiskGUID.String()}
+	entry := make([]byte, entrySize)
+	for i := uint64(0); i < numPartitions; i++ {
+		r.Read(entry)
+		var e uefiPartitionEntry
+		if err := binary.Read(bytes.NewReader(entry), binary.LittleEndian, &e); err != nil {
+			return UEFIGPTData{}, fmt.Errorf("reading partition %d: %v", i, err)
+		}
+		name := e.PartitionName[:]
+		for j, c := range name {
+			if c == 0 {
+				name = name[:j]
+				break
+			}
+		}
+		out.Partitions = append(out.Partitions, GPTPartition{
+			TypeGUID:      e.PartitionTypeGUID.String(),
+			PartitionGUID: e.UniquePartitionGUID.String(),
+			StartingLBA:   e.StartingLBA,
+			EndingLBA:     e.EndingLBA,
+			Attributes:    e.Attributes,
+			Name:          string(utf16.Decode(name)),
+		})
+	}
+	return out, nil
+}
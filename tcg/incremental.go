@@ -0,0 +1,298 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/go-eventlog/register"
+)
+
+// IncrementalReplay replays an event log that is appended to, e.g., a CCEL or
+// TPM event log polled by a runtime attester. Each call to Replay only parses
+// and extends the events appended since the previous call, and verifies the
+// replayed values against the current register values.
+//
+// Unlike EventLog.Verify, it does not apply EventlogWorkarounds.
+type IncrementalReplay struct {
+	parseOpts ParseOpts
+	// parser parsed the events replayed so far, or is nil before the first
+	// replay.
+	parser *Parser
+	// values holds the replayed value of each register and bank with
+	// events.
+	values map[mrKey]mrValue
+	// locality is the locality TPM2_Startup was issued from, which sets the
+	// initial value of PCR 0.
+	locality byte
+}
+
+type mrKey struct {
+	index int
+	hash  crypto.Hash
+}
+
+type mrValue struct {
+	value []byte
+	// redacted is set if value is from a redacted register event.
+	redacted bool
+	// err is set if an event could not be extended into the bank.
+	err error
+}
+
+// NewIncrementalReplay returns an IncrementalReplay for an event log parsed
+// with parseOpts.
+func NewIncrementalReplay(parseOpts ParseOpts) *IncrementalReplay {
+	return &IncrementalReplay{parseOpts: parseOpts, values: make(map[mrKey]mrValue)}
+}
+
+// Offset returns the length of the event log replayed so far, excluding any
+// trailing padding. The bytes appended to the log start at this offset.
+func (r *IncrementalReplay) Offset() int {
+	if r.parser == nil {
+		return 0
+	}
+	return r.parser.eventsEnd()
+}
+
+// AppliedQuirks returns the parsing quirks that were needed for the events
+// replayed so far.
+func (r *IncrementalReplay) AppliedQuirks() Quirks {
+	if r.parser == nil {
+		return 0
+	}
+	return r.parser.AppliedQuirks
+}
+
+// RedactedMRs returns the registers replayed so far whose events were
+// replaced by a redacted register event, in increasing order. See
+// ParseOpts.AllowRedactedMRs.
+func (r *IncrementalReplay) RedactedMRs() []int {
+	var redacted []int
+	for key, value := range r.values {
+		if value.redacted && !containsInt(redacted, key.index) {
+			redacted = append(redacted, key.index)
+		}
+	}
+	sort.Ints(redacted)
+	return redacted
+}
+
+func containsInt(set []int, value int) bool {
+	for _, item := range set {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Replay parses the events in appended and replays them against mrs, the
+// current register values. appended holds the whole event log on the first
+// call, and the bytes of the log from Offset on later calls. Trailing
+// padding is allowed as in ParseOpts.AllowPadding, and is replaced by the
+// events appended to it.
+//
+// Replay returns the appended events in the registers that verified. As with
+// EventLog.Verify, every register in mrs with events must match the replayed
+// value of at least one bank, or a ReplayError is returned. If Replay fails,
+// the appended events are not replayed, so Replay can be called again, e.g.,
+// with register values read after the log.
+func (r *IncrementalReplay) Replay(appended []byte, mrs []register.MR) ([]Event, error) {
+	var p *Parser
+	if r.parser == nil {
+		var err error
+		if p, err = NewParser(bytes.NewReader(appended), r.parseOpts); err != nil {
+			return nil, fmt.Errorf("failed to parse event log: %v", err)
+		}
+	} else {
+		p = r.parser.resume(bytes.NewReader(appended))
+		p.AppliedQuirks = r.parser.AppliedQuirks
+	}
+	var rawEvents []rawEvent
+	for {
+		e, err := p.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse event log: %v", err)
+		}
+		rawEvents = append(rawEvents, e)
+	}
+
+	values := make(map[mrKey]mrValue, len(r.values))
+	for key, value := range r.values {
+		values[key] = value
+	}
+	locality := r.locality
+	for _, e := range rawEvents {
+		if e.typ == eventTypeNoAction {
+			if e.index == 0 && isStartupLocality(e) {
+				locality = e.data[len(e.data)-1]
+			}
+			if isRedactedMR(e) {
+				for _, alg := range p.Algs {
+					key := mrKey{e.index, alg.CryptoHash()}
+					values[key] = redactValue(values[key], key, e, r.parseOpts.AllowRedactedMRs)
+				}
+			}
+			continue
+		}
+		for _, alg := range p.Algs {
+			key := mrKey{e.index, alg.CryptoHash()}
+			values[key] = extendValue(values[key], key, e, locality)
+		}
+	}
+
+	events, err := verifyAppended(rawEvents, values, mrs)
+	if err != nil {
+		return nil, err
+	}
+	r.parser = p
+	r.values = values
+	r.locality = locality
+	return events, nil
+}
+
+// extendValue extends the event's digest for key.hash into the register
+// value.
+func extendValue(v mrValue, key mrKey, e rawEvent, locality byte) mrValue {
+	if v.err != nil {
+		return v
+	}
+	if v.redacted {
+		return mrValue{err: fmt.Errorf("event %d: redacted register value with other events", e.sequence)}
+	}
+	var digest []byte
+	for _, d := range e.digests {
+		if d.hash == key.hash {
+			digest = d.data
+			break
+		}
+	}
+	if digest == nil {
+		return mrValue{err: fmt.Errorf("event %d: no event digest matches pcr algorithm: %v", e.sequence, key.hash)}
+	}
+	if len(digest) != key.hash.Size() {
+		return mrValue{err: fmt.Errorf("event %d: digest data length (%d) doesn't match PCR digest length (%d)", e.sequence, len(digest), key.hash.Size())}
+	}
+	if !key.hash.Available() {
+		return mrValue{err: fmt.Errorf("event %d: hash algorithm %v is not available", e.sequence, key.hash)}
+	}
+	value := v.value
+	if value == nil {
+		if key.index != 0 {
+			locality = 0
+		}
+		value = initialValue(key.hash, locality)
+	}
+	h := key.hash.New()
+	h.Write(value)
+	h.Write(digest)
+	return mrValue{value: h.Sum(nil)}
+}
+
+// redactValue sets the register value for key.hash to that of a redacted
+// register event, which must be the only event of the register, and must be
+// allowed.
+func redactValue(v mrValue, key mrKey, e rawEvent, allowRedacted bool) mrValue {
+	if v.err != nil {
+		return v
+	}
+	if !allowRedacted {
+		return mrValue{err: fmt.Errorf("event %d: %v", e.sequence, errRedactedMR)}
+	}
+	if v.value != nil {
+		return mrValue{err: fmt.Errorf("event %d: redacted register value with other events", e.sequence)}
+	}
+	value, err := redactedMRValue(e, key.hash)
+	if err != nil {
+		return mrValue{err: fmt.Errorf("event %d: %v", e.sequence, err)}
+	}
+	return mrValue{value: value, redacted: true}
+}
+
+// verifyAppended checks the replayed values against mrs, and returns the
+// appended events with the digests of the first matching bank in mrs.
+func verifyAppended(rawEvents []rawEvent, values map[mrKey]mrValue, mrs []register.MR) ([]Event, error) {
+	matched := make(map[int]crypto.Hash)
+	var indexes []int
+	for _, mr := range mrs {
+		key := mrKey{mr.Idx(), mr.DgstAlg()}
+		if hash, ok := matched[key.index]; !ok {
+			matched[key.index] = 0
+			indexes = append(indexes, key.index)
+		} else if hash != 0 {
+			continue
+		}
+		value, ok := values[key]
+		if !ok {
+			// As with Verify, registers without events are not checked.
+			if !hasEvents(values, key.index) {
+				matched[key.index] = key.hash
+			}
+			continue
+		}
+		if value.err == nil && subtle.ConstantTimeCompare(value.value, mr.Dgst()) == 1 {
+			matched[key.index] = key.hash
+		}
+	}
+	var invalid []int
+	for _, idx := range indexes {
+		if matched[idx] == 0 {
+			invalid = append(invalid, idx)
+		}
+	}
+	sort.Ints(invalid)
+
+	var events []Event
+	for _, e := range rawEvents {
+		hash, ok := matched[e.index]
+		if len(invalid) > 0 {
+			events = append(events, Event{sequence: e.sequence, Index: e.index, Type: e.typ, Data: e.data})
+			continue
+		}
+		if !ok || e.typ == eventTypeNoAction {
+			continue
+		}
+		ev := Event{sequence: e.sequence, Index: e.index, Type: e.typ, Data: e.data, hash: hash}
+		for _, d := range e.digests {
+			if d.hash == hash {
+				ev.Digest = d.data
+				break
+			}
+		}
+		events = append(events, ev)
+	}
+	if len(invalid) > 0 {
+		return nil, ReplayError{Events: events, InvalidMRs: invalid}
+	}
+	return events, nil
+}
+
+func hasEvents(values map[mrKey]mrValue, index int) bool {
+	for key := range values {
+		if key.index == index {
+			return true
+		}
+	}
+	return false
+}
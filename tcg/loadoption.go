@@ -0,0 +1,101 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+)
+
+// LoadOptionActive is the LOAD_OPTION_ACTIVE attribute of an EFI_LOAD_OPTION.
+// Boot managers skip inactive boot options.
+const LoadOptionActive = 0x00000001
+
+// EFILoadOption describes an EFI_LOAD_OPTION, the contents of a Boot####
+// variable.
+//
+// Section 3.1.3 of the UEFI specification, accessible at:
+// https://uefi.org/specs/UEFI/2.10/03_Boot_Manager.html#load-options
+type EFILoadOption struct {
+	// Attributes are the LOAD_OPTION_* attributes, e.g., LoadOptionActive.
+	Attributes uint32
+	// Description is the user-readable description of the option.
+	Description string
+	// FilePath is the first device path of the option, usually the path to
+	// the boot loader.
+	FilePath []EFIDevicePathElement
+	// OptionalData is passed to the loaded image, e.g., its load options.
+	OptionalData []byte
+}
+
+// ParseEFILoadOption parses an EFI_LOAD_OPTION, e.g., the VariableData of an
+// EV_EFI_VARIABLE_BOOT event for a Boot#### variable.
+func ParseEFILoadOption(b []byte) (EFILoadOption, error) {
+	var header struct {
+		Attributes         uint32
+		FilePathListLength uint16
+	}
+	r := bytes.NewReader(b)
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return EFILoadOption{}, fmt.Errorf("reading load option header: %v", err)
+	}
+	var description []uint16
+	for {
+		var c uint16
+		if err := binary.Read(r, binary.LittleEndian, &c); err != nil {
+			return EFILoadOption{}, errors.New("load option description is not NUL-terminated")
+		}
+		if c == 0 {
+			break
+		}
+		if len(description) >= maxNameLen {
+			return EFILoadOption{}, fmt.Errorf("load option description too long: > %d", maxNameLen)
+		}
+		description = append(description, c)
+	}
+	if int(header.FilePathListLength) > r.Len() {
+		return EFILoadOption{}, fmt.Errorf("load option file path list length %d exceeds the %d remaining bytes", header.FilePathListLength, r.Len())
+	}
+	filePathList := make([]byte, header.FilePathListLength)
+	r.Read(filePathList)
+	filePath, err := parseDevicePath(filePathList)
+	if err != nil {
+		return EFILoadOption{}, fmt.Errorf("parsing load option file path: %v", err)
+	}
+	optionalData := make([]byte, r.Len())
+	r.Read(optionalData)
+	return EFILoadOption{
+		Attributes:   header.Attributes,
+		Description:  string(utf16.Decode(description)),
+		FilePath:     filePath,
+		OptionalData: optionalData,
+	}, nil
+}
+
+// ParseBootOrder parses the contents of the BootOrder variable, a list of
+// Boot#### option numbers.
+func ParseBootOrder(b []byte) ([]uint16, error) {
+	if len(b)%2 != 0 {
+		return nil, fmt.Errorf("BootOrder length %d is not a multiple of 2", len(b))
+	}
+	order := make([]uint16, len(b)/2)
+	for i := range order {
+		order[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return order, nil
+}
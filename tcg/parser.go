@@ -0,0 +1,356 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-eventlog/register"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Parser parses an unverified measurement log incrementally from an
+// io.Reader, so large event logs can be processed without holding the whole
+// log in memory. Logs compressed in a format supported by Decompress are
+// decompressed as they are read. Unlike ParseEventLog, Windows platform
+// attestation blobs are not supported, and ParseOpts.Buffers is not used.
+//
+// Events must be replayed against the measurement registers before they can
+// be trusted.
+type Parser struct {
+	// Algs holds the set of algorithms that the event log uses.
+	Algs []register.HashAlg
+	// Padding describes the trailing padding of a log parsed with
+	// ParseOpts.AllowPadding, or is nil if there is none. It is set once Next
+	// returns io.EOF. The padding is read into memory.
+	Padding *Padding
+	// AppliedQuirks are the quirks that were needed to parse the events
+	// returned so far.
+	AppliedQuirks Quirks
+
+	r            *bufio.Reader
+	opts         ParseOpts
+	allowPadding bool
+	specID       *specIDEvent
+	// first is the first event of a SHA-1 log, which is read to tell the
+	// log format.
+	first *rawEvent
+	// offset is the offset of the next event in the uncompressed log.
+	offset   int
+	sequence int
+	// count is the number of events parsed, excluding the Spec ID event.
+	count int
+	// err is returned by all calls to Next after the first error.
+	err error
+}
+
+// NewParser returns a Parser for the event log read from r. It reads the
+// first event, which for crypto agile logs gives the digest algorithms.
+func NewParser(r io.Reader, parseOpts ParseOpts) (*Parser, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(zstdMagic))
+	switch DetectCompression(magic) {
+	case Gzip:
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip header: %v", err)
+		}
+		br = bufio.NewReader(gr)
+	case Zstd:
+		// A single-threaded decoder does not need to be closed.
+		zr, err := zstd.NewReader(br, zstd.WithDecoderMaxMemory(maxDecompressedLogLen), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %v", err)
+		}
+		br = bufio.NewReader(zr)
+	}
+
+	p := &Parser{
+		r:            br,
+		opts:         parseOpts,
+		allowPadding: parseOpts.AllowPadding || parseOpts.Quirks.Has(QuirkPadding),
+	}
+	e, err := p.readEvent()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse first event: %v", err)
+	}
+	if e.typ == eventTypeNoAction && len(e.data) >= binary.Size(specIDEventHeader{}) {
+		p.specID, err = parseSpecIDEvent(e.data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse spec ID event: %v", err)
+		}
+		for _, alg := range p.specID.algs {
+			if hashAlg := register.HashAlg(alg.ID); uint16(hashAlg) == alg.ID && hashAlg.CryptoHash().Available() {
+				p.Algs = append(p.Algs, hashAlg)
+			}
+		}
+		if len(p.Algs) == 0 {
+			return nil, fmt.Errorf("measurement log didn't use sha1, sha256, sha384, or sha3 digests")
+		}
+	} else {
+		p.Algs = []register.HashAlg{register.HashSHA1}
+		p.count++
+		if err := parseOpts.check(e, p.count); err != nil {
+			return nil, err
+		}
+		p.first = &e
+	}
+	p.sequence = 1
+	return p, nil
+}
+
+// Next returns the next event, with the digest for hash as in
+// EventLog.Events, or io.EOF after the last event. The returned events are
+// unverified.
+func (p *Parser) Next(hash register.HashAlg) (Event, error) {
+	e, err := p.next()
+	if err != nil {
+		return Event{}, err
+	}
+	ev := Event{
+		sequence: e.sequence,
+		Index:    e.index,
+		Type:     e.typ,
+		Data:     e.data,
+	}
+	for _, digest := range e.digests {
+		if digest.hash == hash.CryptoHash() {
+			ev.Digest = digest.data
+			break
+		}
+	}
+	return ev, nil
+}
+
+// resume returns a Parser for the bytes appended to the log after the last
+// event parsed by p, which must have returned io.EOF. The appended bytes
+// replace any trailing padding.
+func (p *Parser) resume(r io.Reader) *Parser {
+	return &Parser{
+		Algs:         p.Algs,
+		r:            bufio.NewReader(r),
+		opts:         p.opts,
+		allowPadding: p.allowPadding,
+		specID:       p.specID,
+		offset:       p.eventsEnd(),
+		sequence:     p.sequence,
+		count:        p.count,
+	}
+}
+
+// eventsEnd returns the offset of the end of the events parsed so far, which
+// is the start of any trailing padding.
+func (p *Parser) eventsEnd() int {
+	if p.Padding != nil {
+		return p.Padding.Offset
+	}
+	return p.offset
+}
+
+func (p *Parser) next() (rawEvent, error) {
+	if p.err != nil {
+		return rawEvent{}, p.err
+	}
+	e, err := p.nextEvent()
+	if err != nil {
+		p.err = err
+	}
+	return e, err
+}
+
+func (p *Parser) nextEvent() (rawEvent, error) {
+	if p.first != nil {
+		e := *p.first
+		p.first = nil
+		if p.opts.keep(e) {
+			return e, nil
+		}
+	}
+	for {
+		e, err := p.readEvent()
+		if err == io.EOF {
+			if p.Padding != nil && !p.opts.AllowPadding {
+				p.AppliedQuirks |= QuirkPadding
+			}
+			return rawEvent{}, io.EOF
+		}
+		if err != nil {
+			return rawEvent{}, err
+		}
+		e.sequence = p.sequence
+		p.sequence++
+		p.count++
+		if err := p.opts.check(e, p.count); err != nil {
+			return rawEvent{}, err
+		}
+		p.AppliedQuirks |= e.quirks
+		if p.opts.Quirks.Has(QuirkEmptyEvents) && len(e.digests) == 0 && len(e.data) == 0 {
+			p.AppliedQuirks |= QuirkEmptyEvents
+			continue
+		}
+		if p.opts.keep(e) {
+			return e, nil
+		}
+	}
+}
+
+// readEvent reads and parses the next event, or returns io.EOF at the end of
+// the log or its padding.
+func (p *Parser) readEvent() (rawEvent, error) {
+	if _, err := p.r.Peek(1); err == io.EOF {
+		return rawEvent{}, io.EOF
+	}
+	if p.allowPadding && p.sequence > 0 {
+		// As with ParseEventLog, all-zero trailing bytes after the first
+		// event are padding. Only
+		// read the rest of the log into memory if the next event could start
+		// with padding.
+		if header, _ := p.r.Peek(8); allZero(header) {
+			rest, err := io.ReadAll(p.r)
+			if err != nil {
+				return rawEvent{}, err
+			}
+			if allZero(rest) {
+				p.setPadding(rest)
+				return rawEvent{}, io.EOF
+			}
+			p.r = bufio.NewReader(bytes.NewReader(rest))
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if p.specID == nil {
+		err = p.readSHA1Event(&buf)
+	} else {
+		err = p.readCryptoAgileEvent(&buf)
+	}
+	if err == errEventLogPadding && p.allowPadding {
+		rest, err := io.ReadAll(p.r)
+		if err != nil {
+			return rawEvent{}, err
+		}
+		p.setPadding(append(buf.Bytes(), rest...))
+		return rawEvent{}, io.EOF
+	}
+	if err != nil {
+		return rawEvent{}, err
+	}
+	p.offset += buf.Len()
+	if p.specID == nil {
+		return parseRawEvent(&buf, nil, nil)
+	}
+	return parseRawEvent2(&buf, p.specID, p.opts.Quirks, nil)
+}
+
+func (p *Parser) setPadding(padding []byte) {
+	p.Padding = newPadding(padding, 0)
+	p.Padding.Offset += p.offset
+	if p.Padding.GarbageOffset >= 0 {
+		p.Padding.GarbageOffset += p.offset
+	}
+	p.offset += len(padding)
+}
+
+// readSHA1Event copies the next SHA-1 log event to buf. See rawEventHeader.
+func (p *Parser) readSHA1Event(buf *bytes.Buffer) error {
+	if err := p.copyN(buf, int64(binary.Size(rawEventHeader{}))); err != nil {
+		return fmt.Errorf("header deserialization error: %w", err)
+	}
+	eventSize := binary.LittleEndian.Uint32(buf.Bytes()[buf.Len()-4:])
+	return p.copyData(buf, eventSize)
+}
+
+// readCryptoAgileEvent copies the next crypto agile log event to buf. See
+// rawEvent2Header.
+func (p *Parser) readCryptoAgileEvent(buf *bytes.Buffer) error {
+	if err := p.copyN(buf, int64(binary.Size(rawEvent2Header{}))); err != nil {
+		return err
+	}
+	if binary.LittleEndian.Uint32(buf.Bytes()) == 0xFFFFFFFF {
+		return errEventLogPadding
+	}
+	numDigests, err := p.readUint32(buf)
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < numDigests; i++ {
+		if err := p.copyN(buf, 2); err != nil {
+			return err
+		}
+		algID := binary.LittleEndian.Uint16(buf.Bytes()[buf.Len()-2:])
+		size := -1
+		for _, alg := range p.specID.algs {
+			if alg.ID == algID {
+				size = int(alg.Size)
+			}
+		}
+		if size < 0 && p.opts.Quirks.Has(QuirkUnlistedDigests) {
+			if hash, err := register.TPMAlgHash(algID); err == nil {
+				size = hash.Size()
+			}
+		}
+		if size < 0 {
+			return fmt.Errorf("unknown algorithm ID %x", algID)
+		}
+		if err := p.copyN(buf, int64(size)); err != nil {
+			return fmt.Errorf("reading digest: %v", err)
+		}
+	}
+	eventSize, err := p.readUint32(buf)
+	if err != nil {
+		return err
+	}
+	return p.copyData(buf, eventSize)
+}
+
+func (p *Parser) readUint32(buf *bytes.Buffer) (uint32, error) {
+	if err := p.copyN(buf, 4); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf.Bytes()[buf.Len()-4:]), nil
+}
+
+// copyData copies event data to buf. The data is copied as it is read, so
+// the memory used is bounded by the size of the log rather than the untrusted
+// event size.
+func (p *Parser) copyData(buf *bytes.Buffer, eventSize uint32) error {
+	if p.opts.MaxEventDataSize > 0 && int64(eventSize) > int64(p.opts.MaxEventDataSize) {
+		return fmt.Errorf("event %d has %d data bytes, more than the limit of %d", p.sequence, eventSize, p.opts.MaxEventDataSize)
+	}
+	if err := p.copyN(buf, int64(eventSize)); err != nil {
+		return fmt.Errorf("reading data error: %w", err)
+	}
+	return nil
+}
+
+// copyN copies n bytes of the log to buf. It returns io.ErrUnexpectedEOF if
+// the log ends first.
+func (p *Parser) copyN(buf *bytes.Buffer, n int64) error {
+	_, err := io.CopyN(buf, p.r, n)
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
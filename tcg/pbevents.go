@@ -0,0 +1,58 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !eventlog_minimal
+
+package tcg
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"fmt"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+// ConvertOpts gives options for converting events to state.proto Events.
+type ConvertOpts struct {
+	// Summaries populates the human-readable summary of each event.
+	Summaries bool
+}
+
+// ConvertToPbEvents returns the state.proto Events from the GenericEvents.
+func ConvertToPbEvents(hash crypto.Hash, events []Event) []*pb.Event {
+	return ConvertToPbEventsWithOpts(hash, events, ConvertOpts{})
+}
+
+// ConvertToPbEventsWithOpts is like ConvertToPbEvents, with conversion
+// options.
+func ConvertToPbEventsWithOpts(hash crypto.Hash, events []Event, opts ConvertOpts) []*pb.Event {
+	pbEvents := make([]*pb.Event, len(events))
+	for i, event := range events {
+		hasher := hash.New()
+		hasher.Write(event.RawData())
+		digest := hasher.Sum(nil)
+		pbEvents[i] = &pb.Event{
+			PcrIndex:       event.MRIndex(),
+			UntrustedType:  uint32(event.UntrustedType()),
+			Data:           event.RawData(),
+			Digest:         event.ReplayedDigest(),
+			DigestVerified: subtle.ConstantTimeCompare(digest, event.ReplayedDigest()) == 1,
+		}
+		if opts.Summaries {
+			pbEvents[i].Summary = fmt.Sprintf("%v: %s", event.Type, Summarize(event))
+		}
+	}
+	return pbEvents
+}
@@ -19,16 +19,14 @@ package tcg
 import (
 	"bytes"
 	"crypto"
+	"crypto/subtle"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"sort"
-	"strings"
 
-	pb "github.com/google/go-eventlog/proto/state"
 	"github.com/google/go-eventlog/register"
-	"github.com/google/go-tpm/legacy/tpm2"
 )
 
 type digestVerified int
@@ -112,7 +110,7 @@ func (e Event) DigestVerified() bool {
 	hasher := e.hash.New()
 	hasher.Write(e.Data)
 	digest := hasher.Sum(nil)
-	if bytes.Equal(digest, e.Digest) {
+	if subtle.ConstantTimeCompare(digest, e.Digest) == 1 {
 		e.digestVerified = VERIFIED
 	} else {
 		e.digestVerified = UNVERIFIED
@@ -120,24 +118,6 @@ func (e Event) DigestVerified() bool {
 	return e.digestVerified == VERIFIED
 }
 
-// ConvertToPbEvents returns the state.proto Events from the GenericEvents.
-func ConvertToPbEvents(hash crypto.Hash, events []Event) []*pb.Event {
-	pbEvents := make([]*pb.Event, len(events))
-	for i, event := range events {
-		hasher := hash.New()
-		hasher.Write(event.RawData())
-		digest := hasher.Sum(nil)
-		pbEvents[i] = &pb.Event{
-			PcrIndex:       event.MRIndex(),
-			UntrustedType:  uint32(event.UntrustedType()),
-			Data:           event.RawData(),
-			Digest:         event.ReplayedDigest(),
-			DigestVerified: bytes.Equal(digest, event.ReplayedDigest()),
-		}
-	}
-	return pbEvents
-}
-
 // ReplayError describes the parsed events that failed to verify against
 // a particular PCR.
 type ReplayError struct {
@@ -162,7 +142,106 @@ func (e ReplayError) affected(mr int) bool {
 
 // ParseOpts gives options for parsing the event log.
 type ParseOpts struct {
+	// AllowPadding stops parsing at trailing padding instead of failing, e.g.,
+	// for fixed-size CCEL buffers. Padding is all zeros or, in crypto agile
+	// logs, starts with a 0xFFFFFFFF register index. It is described in
+	// EventLog.Padding.
 	AllowPadding bool
+	// Quirks enables workarounds for nonstandard event logs. The parsing
+	// quirks that were needed are reported in EventLog.AppliedQuirks.
+	Quirks Quirks
+	// Buffers, if set, provides reusable memory for the parsed events, to
+	// reduce allocations. See ParseBuffers for how long the parsed events
+	// remain valid.
+	Buffers *ParseBuffers
+	// MaxEvents, if positive, fails parsing if the event log holds more
+	// events, to bound the memory used by logs from untrusted sources.
+	MaxEvents int
+	// MaxEventDataSize, if positive, fails parsing if an event holds more
+	// data bytes.
+	MaxEventDataSize int
+	// MRIndexes, if set, keeps only the events measured into these event
+	// log-encoded register indexes, e.g., PCR or CC MR indexes. The other
+	// events are skipped, but still count towards MaxEvents and the event
+	// numbers.
+	MRIndexes []int
+	// AllowRedactedMRs accepts the redacted register events written by
+	// RedactEventLog, whose registers replay to the value in the event, with
+	// no events verified. The redacted registers are reported in
+	// EventLog.RedactedMRs. Only set it if the state measured into the
+	// redacted registers may be missing, e.g., for bundles redacted with
+	// bundle.Redact. By default, a register with a redacted register event
+	// does not verify.
+	AllowRedactedMRs bool
+}
+
+// check checks an event against the parsing limits, given the number of
+// events parsed so far, including it.
+func (o ParseOpts) check(e rawEvent, count int) error {
+	if o.MaxEvents > 0 && count > o.MaxEvents {
+		return fmt.Errorf("event log has more than %d events", o.MaxEvents)
+	}
+	if o.MaxEventDataSize > 0 && len(e.data) > o.MaxEventDataSize {
+		return fmt.Errorf("event %d has %d data bytes, more than the limit of %d", e.sequence, len(e.data), o.MaxEventDataSize)
+	}
+	return nil
+}
+
+// keep reports whether an event is in the registers selected by MRIndexes.
+func (o ParseOpts) keep(e rawEvent) bool {
+	if o.MRIndexes == nil {
+		return true
+	}
+	for _, idx := range o.MRIndexes {
+		if e.index == idx {
+			return true
+		}
+	}
+	return false
+}
+
+// Padding describes the trailing bytes after the last event of a log parsed
+// with ParseOpts.AllowPadding.
+type Padding struct {
+	// Offset is the offset of the padding in the uncompressed event log.
+	Offset int
+	// Length is the number of padding bytes.
+	Length int
+	// GarbageOffset is the offset in the uncompressed event log of the first
+	// byte that does not match the fill byte (0x00 or 0xFF) of the padding,
+	// or -1 if the padding is all fill. Non-fill bytes may be hidden data.
+	GarbageOffset int
+}
+
+// Clean reports whether the padding is all fill bytes.
+func (p Padding) Clean() bool {
+	return p.GarbageOffset < 0
+}
+
+func newPadding(measurementLog []byte, offset int) *Padding {
+	padding := measurementLog[offset:]
+	p := &Padding{Offset: offset, Length: len(padding), GarbageOffset: -1}
+	fill := padding[0]
+	if fill != 0x00 && fill != 0xff {
+		p.GarbageOffset = offset
+		return p
+	}
+	for i, b := range padding {
+		if b != fill {
+			p.GarbageOffset = offset + i
+			break
+		}
+	}
+	return p
+}
+
+func allZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
 }
 
 // ParseAndReplay takes a raw TCG measurement log, parses it, and replays it
@@ -184,11 +263,32 @@ func ParseAndReplay(rawEventLog []byte, mrs []register.MR, parseOpts ParseOpts)
 }
 
 // ParseEventLog parses an unverified measurement log.
+// Logs compressed in a format supported by Decompress are decompressed first.
+// The WBCL is taken out of Windows platform attestation blobs, see UnwrapWBCL.
 func ParseEventLog(measurementLog []byte, parseOpts ParseOpts) (*EventLog, error) {
+	measurementLog, err := Decompress(measurementLog)
+	if err != nil {
+		return nil, err
+	}
+	measurementLog, err = UnwrapWBCL(measurementLog)
+	if err != nil {
+		return nil, err
+	}
 	var specID *specIDEvent
+	// count is the number of events parsed, excluding the Spec ID event.
+	var count int
 	r := bytes.NewBuffer(measurementLog)
-	parseFn := parseRawEvent
-	var el EventLog
+	buffers := parseOpts.Buffers
+	el := EventLog{allowRedactedMRs: parseOpts.AllowRedactedMRs}
+	if buffers != nil {
+		buffers.reset(len(measurementLog))
+		el.rawEvents = buffers.rawEvents
+		// Keep the grown events slice for the next parse.
+		defer func() { buffers.rawEvents = el.rawEvents }()
+	}
+	parseFn := func(r *bytes.Buffer, specID *specIDEvent) (rawEvent, error) {
+		return parseRawEvent(r, specID, buffers)
+	}
 	e, err := parseFn(r, specID)
 	if err != nil {
 		return nil, fmt.Errorf("parse first event: %v", err)
@@ -199,33 +299,45 @@ func ParseEventLog(measurementLog []byte, parseOpts ParseOpts) (*EventLog, error
 			return nil, fmt.Errorf("failed to parse spec ID event: %v", err)
 		}
 		for _, alg := range specID.algs {
-			switch tpm2.Algorithm(alg.ID) {
-			case tpm2.AlgSHA1:
-				el.Algs = append(el.Algs, register.HashSHA1)
-			case tpm2.AlgSHA256:
-				el.Algs = append(el.Algs, register.HashSHA256)
-			case tpm2.AlgSHA384:
-				el.Algs = append(el.Algs, register.HashSHA384)
+			// HashAlg only has the supported algorithms, which all fit in a byte.
+			// The SHA3 family is skipped unless an implementation is linked in.
+			if hashAlg := register.HashAlg(alg.ID); uint16(hashAlg) == alg.ID && hashAlg.CryptoHash().Available() {
+				el.Algs = append(el.Algs, hashAlg)
 			}
 		}
 		if len(el.Algs) == 0 {
-			return nil, fmt.Errorf("measurement log didn't use sha1, sha256, or sha384 digests")
+			return nil, fmt.Errorf("measurement log didn't use sha1, sha256, sha384, or sha3 digests")
 		}
 		// Switch to parsing crypto agile events. Don't include this in the
 		// replayed events since it intentionally doesn't extend the PCRs.
 		//
 		// Note that this doesn't actually guarantee that events have SHA256
 		// digests.
-		parseFn = parseRawEvent2
+		parseFn = func(r *bytes.Buffer, specID *specIDEvent) (rawEvent, error) {
+			return parseRawEvent2(r, specID, parseOpts.Quirks, buffers)
+		}
 		el.specIDEvent = specID
 	} else {
 		el.Algs = []register.HashAlg{register.HashSHA1}
-		el.rawEvents = append(el.rawEvents, e)
+		count++
+		if err := parseOpts.check(e, count); err != nil {
+			return nil, err
+		}
+		if parseOpts.keep(e) {
+			el.rawEvents = append(el.rawEvents, e)
+		}
 	}
+	allowPadding := parseOpts.AllowPadding || parseOpts.Quirks.Has(QuirkPadding)
 	sequence := 1
 	for r.Len() != 0 {
+		offset := len(measurementLog) - r.Len()
+		if allowPadding && allZero(r.Bytes()) {
+			el.Padding = newPadding(measurementLog, offset)
+			break
+		}
 		e, err := parseFn(r, specID)
-		if err == errEventLogPadding && parseOpts.AllowPadding {
+		if err == errEventLogPadding && allowPadding {
+			el.Padding = newPadding(measurementLog, offset)
 			break
 		}
 		if err != nil {
@@ -233,7 +345,28 @@ func ParseEventLog(measurementLog []byte, parseOpts ParseOpts) (*EventLog, error
 		}
 		e.sequence = sequence
 		sequence++
-		el.rawEvents = append(el.rawEvents, e)
+		count++
+		if err := parseOpts.check(e, count); err != nil {
+			return nil, err
+		}
+		el.AppliedQuirks |= e.quirks
+		if parseOpts.Quirks.Has(QuirkEmptyEvents) && len(e.digests) == 0 && len(e.data) == 0 {
+			el.AppliedQuirks |= QuirkEmptyEvents
+			continue
+		}
+		if parseOpts.keep(e) {
+			el.rawEvents = append(el.rawEvents, e)
+		}
+	}
+	if el.Padding != nil && !parseOpts.AllowPadding {
+		el.AppliedQuirks |= QuirkPadding
+	}
+	if parseOpts.AllowRedactedMRs {
+		for _, e := range el.rawEvents {
+			if isRedactedMR(e) {
+				el.RedactedMRs = append(el.RedactedMRs, e.index)
+			}
+		}
 	}
 	return &el, nil
 }
@@ -243,18 +376,38 @@ func ParseEventLog(measurementLog []byte, parseOpts ParseOpts) (*EventLog, error
 type EventLog struct {
 	// Algs holds the set of algorithms that the event log uses.
 	Algs []register.HashAlg
-
-	rawEvents   []rawEvent
-	specIDEvent *specIDEvent
+	// Padding describes the trailing padding of a log parsed with
+	// ParseOpts.AllowPadding, or is nil if there is none.
+	Padding *Padding
+	// AppliedQuirks are the ParseOpts.Quirks that were needed to parse the
+	// event log. Policy may want to treat logs that needed quirks with
+	// suspicion.
+	AppliedQuirks Quirks
+	// RedactedMRs are the registers whose events were replaced by a redacted
+	// register event, in log order, see RedactEventLog. It is only set with
+	// ParseOpts.AllowRedactedMRs. None of the state measured into these
+	// registers can be verified.
+	RedactedMRs []int
+
+	rawEvents        []rawEvent
+	specIDEvent      *specIDEvent
+	allowRedactedMRs bool
 }
 
 func (e *EventLog) clone() *EventLog {
 	out := EventLog{
-		Algs:      make([]register.HashAlg, len(e.Algs)),
-		rawEvents: make([]rawEvent, len(e.rawEvents)),
+		Algs:             make([]register.HashAlg, len(e.Algs)),
+		AppliedQuirks:    e.AppliedQuirks,
+		RedactedMRs:      append([]int(nil), e.RedactedMRs...),
+		rawEvents:        make([]rawEvent, len(e.rawEvents)),
+		allowRedactedMRs: e.allowRedactedMRs,
 	}
 	copy(out.Algs, e.Algs)
 	copy(out.rawEvents, e.rawEvents)
+	if e.Padding != nil {
+		padding := *e.Padding
+		out.Padding = &padding
+	}
 	if e.specIDEvent != nil {
 		dupe := *e.specIDEvent
 		out.specIDEvent = &dupe
@@ -273,9 +426,10 @@ func (e *EventLog) Events(hash register.HashAlg) []Event {
 	var events []Event
 	for _, re := range e.rawEvents {
 		ev := Event{
-			Index: re.index,
-			Type:  re.typ,
-			Data:  re.data,
+			sequence: re.sequence,
+			Index:    re.index,
+			Type:     re.typ,
+			Data:     re.data,
 		}
 
 		for _, digest := range re.digests {
@@ -322,7 +476,7 @@ func (e *EventLog) Verify(mrs []register.MR) ([]Event, error) {
 }
 
 func (e *EventLog) verify(mrs []register.MR) ([]Event, error) {
-	events, err := replayEvents(e.rawEvents, mrs)
+	events, err := replayEvents(e.rawEvents, mrs, e.allowRedactedMRs)
 	if err != nil {
 		if _, isReplayErr := err.(ReplayError); isReplayErr {
 			return nil, err
@@ -341,13 +495,14 @@ func extend(pcr register.MR, replay []byte, e rawEvent, locality byte) (pcrDiges
 		if len(digest.data) != len(pcr.Dgst()) {
 			return nil, nil, fmt.Errorf("digest data length (%d) doesn't match PCR digest length (%d)", len(digest.data), len(pcr.Dgst()))
 		}
+		if !h.Available() {
+			return nil, nil, fmt.Errorf("hash algorithm %v is not available", h)
+		}
 		hash := h.New()
 		if len(replay) != 0 {
 			hash.Write(replay)
 		} else {
-			b := make([]byte, h.Size())
-			b[h.Size()-1] = locality
-			hash.Write(b)
+			hash.Write(initialValue(h, locality))
 		}
 		hash.Write(digest.data)
 		return hash.Sum(nil), digest.data, nil
@@ -359,46 +514,18 @@ func extend(pcr register.MR, replay []byte, e rawEvent, locality byte) (pcrDiges
 // event digests with the algorithm in pcr. An error is returned if the
 // replayed values do not match the final PCR digest, or any event tagged
 // with that PCR does not possess an event digest with the specified algorithm.
-func replayPCR(rawEvents []rawEvent, mr register.MR) ([]Event, bool) {
-	var (
-		replay    []byte
-		outEvents []Event
-		locality  byte
-	)
-	mrIdx := mr.Idx()
-	for _, e := range rawEvents {
-		if e.index != mrIdx {
-			continue
-		}
-		// If TXT is enabled then the first event for PCR0
-		// should be a StartupLocality event. The final byte
-		// of this event indicates the locality from which
-		// TPM2_Startup() was issued. The initial value of
-		// PCR0 is equal to the locality.
-		if e.typ == eventTypeNoAction {
-			if mr.Idx() == 0 && len(e.data) == 17 && strings.HasPrefix(string(e.data), "StartupLocality") {
-				locality = e.data[len(e.data)-1]
-			}
-			continue
-		}
-		replayValue, digest, err := extend(mr, replay, e, locality)
-		if err != nil {
-			return nil, false
-		}
-		replay = replayValue
-		outEvents = append(outEvents, Event{
-			sequence: e.sequence,
-			Data:     e.data,
-			Digest:   digest,
-			Index:    mrIdx,
-			Type:     e.typ,
-			hash:     mr.DgstAlg(),
-		})
+func replayPCR(rawEvents []rawEvent, mr register.MR, allowRedacted bool) ([]Event, bool) {
+	trace := traceMR(rawEvents, mr, allowRedacted)
+	if trace.Err != nil {
+		return nil, false
 	}
-
-	if len(outEvents) > 0 && !bytes.Equal(replay, mr.Dgst()) {
+	if (len(trace.Steps) > 0 || trace.Redacted) && !trace.Matches() {
 		return nil, false
 	}
+	var outEvents []Event
+	for _, step := range trace.Steps {
+		outEvents = append(outEvents, step.Event)
+	}
 	return outEvents, true
 }
 
@@ -407,7 +534,7 @@ type pcrReplayResult struct {
 	successful bool
 }
 
-func replayEvents(rawEvents []rawEvent, mrs []register.MR) ([]Event, error) {
+func replayEvents(rawEvents []rawEvent, mrs []register.MR, allowRedacted bool) ([]Event, error) {
 	var (
 		invalidReplays []int
 		verifiedEvents []Event
@@ -416,7 +543,7 @@ func replayEvents(rawEvents []rawEvent, mrs []register.MR) ([]Event, error) {
 
 	// Replay the event log for every PCR and digest algorithm combination.
 	for _, mr := range mrs {
-		events, ok := replayPCR(rawEvents, mr)
+		events, ok := replayPCR(rawEvents, mr, allowRedacted)
 		allPCRReplays[mr.Idx()] = append(allPCRReplays[mr.Idx()], pcrReplayResult{events, ok})
 	}
 
@@ -552,6 +679,8 @@ type rawEvent struct {
 	typ      EventType
 	data     []byte
 	digests  []digest
+	// quirks are the quirks applied to parse the event.
+	quirks Quirks
 }
 
 type eventSizeErr struct {
@@ -601,40 +730,202 @@ func AppendEvents(base []byte, additional ...[]byte) ([]byte, error) {
 		}
 
 		for x, e := range log.rawEvents {
-			// Serialize header (PCR index, event type, number of digests)
-			binary.Write(out, binary.LittleEndian, rawEvent2Header{
-				PCRIndex: uint32(e.index),
-				Type:     uint32(e.typ),
-			})
-			binary.Write(out, binary.LittleEndian, uint32(len(e.digests)))
-
-			// Serialize digests
-			for _, d := range e.digests {
-				var algID uint16
-				switch d.hash {
-				case crypto.SHA384:
-					algID = uint16(register.HashSHA384)
-				case crypto.SHA256:
-					algID = uint16(register.HashSHA256)
-				case crypto.SHA1:
-					algID = uint16(register.HashSHA1)
-				default:
-					return nil, fmt.Errorf("log %d: event %d: unhandled hash function %v", i, x, d.hash)
-				}
+			if err := writeRawEvent2(out, e); err != nil {
+				return nil, fmt.Errorf("log %d: event %d: %v", i, x, err)
+			}
+		}
+	}
 
-				binary.Write(out, binary.LittleEndian, algID)
-				out.Write(d.data)
+	return out.Bytes(), nil
+}
+
+// FilterEventLog returns a raw event log with only the events measured into
+// the given registers, in their original order. The Spec ID header of crypto
+// agile logs is preserved, so the result can be parsed and replayed against
+// the same registers. This is useful to share minimal evidence, e.g., only the
+// PCR7 events, with a third party.
+//
+// Compressed logs are decompressed, Windows platform attestation blobs are
+// unwrapped, and the result is a plain, uncompressed event log.
+func FilterEventLog(rawEventLog []byte, mrIndexes []int) ([]byte, error) {
+	keep := indexSet(mrIndexes)
+	return rewriteEventLog(rawEventLog, ParseOpts{}, func(log *EventLog) ([]rawEvent, error) {
+		var events []rawEvent
+		for _, e := range log.rawEvents {
+			if keep[e.index] {
+				events = append(events, e)
 			}
+		}
+		return events, nil
+	})
+}
 
-			// Serialize event data
-			binary.Write(out, binary.LittleEndian, uint32(len(e.data)))
-			out.Write(e.data)
+// RedactEventLog returns a raw event log where the events measured into the
+// given registers are replaced by a single redacted register event per
+// register, and all other events are unchanged. This allows selective
+// disclosure, e.g., sharing the PCR7 Secure Boot events while hiding the PCR1
+// hardware configuration.
+//
+// The redacted register event holds the value the register's events replay
+// to, for each bank of the log, rather than their digests: the digests of
+// low-entropy events, e.g., the PCR1 boot variables, could be brute-forced to
+// recover the hidden data. With ParseOpts.AllowRedactedMRs, Verify and Trace
+// check the redacted register values against the registers, but no events
+// are verified for them. Without it, the redacted registers do not verify.
+// Other event log parsers treat the event as an unknown EV_NO_ACTION event.
+//
+// Compressed logs are decompressed, Windows platform attestation blobs are
+// unwrapped, and the result is a plain, uncompressed event log. Trailing
+// padding allowed by opts is dropped.
+func RedactEventLog(rawEventLog []byte, mrIndexes []int, opts ParseOpts) ([]byte, error) {
+	redact := indexSet(mrIndexes)
+	return rewriteEventLog(rawEventLog, opts, func(log *EventLog) ([]rawEvent, error) {
+		var events []rawEvent
+		redacted := make(map[int]bool)
+		for _, e := range log.rawEvents {
+			if !redact[e.index] {
+				events = append(events, e)
+				continue
+			}
+			if redacted[e.index] {
+				continue
+			}
+			redacted[e.index] = true
+			event, err := redactedMREvent(log, e)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
 		}
+		return events, nil
+	})
+}
+
+// redactedMREvent returns the redacted register event replacing the events of
+// the register of first, the first of them.
+func redactedMREvent(log *EventLog, first rawEvent) (rawEvent, error) {
+	event := rawEvent{
+		sequence: first.sequence,
+		index:    first.index,
+		typ:      eventTypeNoAction,
+		data:     []byte(redactedMRSignature),
+	}
+	for _, alg := range log.Algs {
+		hash := alg.CryptoHash()
+		trace := traceMR(log.rawEvents, register.FakeMR{Index: first.index, Digest: make([]byte, hash.Size()), DigestAlg: hash}, log.allowRedactedMRs)
+		if trace.Err != nil {
+			return rawEvent{}, fmt.Errorf("failed to replay register %d: %v", first.index, trace.Err)
+		}
+		event.digests = append(event.digests, digest{hash: hash, data: trace.Final()})
 	}
+	return event, nil
+}
+
+func indexSet(mrIndexes []int) map[int]bool {
+	set := make(map[int]bool, len(mrIndexes))
+	for _, idx := range mrIndexes {
+		set[idx] = true
+	}
+	return set
+}
 
+// rewriteEventLog re-serializes a raw event log in its original format, with
+// the events returned by fn for the parsed log.
+func rewriteEventLog(rawEventLog []byte, opts ParseOpts, fn func(*EventLog) ([]rawEvent, error)) ([]byte, error) {
+	rawEventLog, err := Decompress(rawEventLog)
+	if err != nil {
+		return nil, err
+	}
+	// The Spec ID event is copied from the raw bytes, so they must be the TCG
+	// event log itself.
+	rawEventLog, err = UnwrapWBCL(rawEventLog)
+	if err != nil {
+		return nil, err
+	}
+	log, err := ParseEventLog(rawEventLog, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	writeFn := writeRawEvent
+	var out bytes.Buffer
+	if log.specIDEvent != nil {
+		// The Spec ID event is always in the SHA1 log format, and is not kept
+		// in the parsed events.
+		var h rawEventHeader
+		if err := binary.Read(bytes.NewReader(rawEventLog), binary.LittleEndian, &h); err != nil {
+			return nil, fmt.Errorf("reading spec ID event: %v", err)
+		}
+		out.Write(rawEventLog[:binary.Size(h)+int(h.EventSize)])
+		writeFn = writeRawEvent2
+	}
+	events, err := fn(log)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if err := writeFn(&out, e); err != nil {
+			return nil, fmt.Errorf("event %d: %v", e.sequence, err)
+		}
+	}
 	return out.Bytes(), nil
 }
 
+// writeRawEvent2 serializes the event in the crypto agile log format.
+func writeRawEvent2(out *bytes.Buffer, e rawEvent) error {
+	// Serialize header (PCR index, event type, number of digests)
+	binary.Write(out, binary.LittleEndian, rawEvent2Header{
+		PCRIndex: uint32(e.index),
+		Type:     uint32(e.typ),
+	})
+	binary.Write(out, binary.LittleEndian, uint32(len(e.digests)))
+
+	// Serialize digests
+	for _, d := range e.digests {
+		var algID uint16
+		switch d.hash {
+		case crypto.SHA384:
+			algID = uint16(register.HashSHA384)
+		case crypto.SHA256:
+			algID = uint16(register.HashSHA256)
+		case crypto.SHA1:
+			algID = uint16(register.HashSHA1)
+		case crypto.SHA3_256:
+			algID = uint16(register.HashSHA3_256)
+		case crypto.SHA3_384:
+			algID = uint16(register.HashSHA3_384)
+		case crypto.SHA3_512:
+			algID = uint16(register.HashSHA3_512)
+		default:
+			return fmt.Errorf("unhandled hash function %v", d.hash)
+		}
+
+		binary.Write(out, binary.LittleEndian, algID)
+		out.Write(d.data)
+	}
+
+	// Serialize event data
+	binary.Write(out, binary.LittleEndian, uint32(len(e.data)))
+	out.Write(e.data)
+	return nil
+}
+
+// writeRawEvent serializes the event in the SHA1 log format.
+func writeRawEvent(out *bytes.Buffer, e rawEvent) error {
+	h := rawEventHeader{
+		PCRIndex:  uint32(e.index),
+		Type:      uint32(e.typ),
+		EventSize: uint32(len(e.data)),
+	}
+	if len(e.digests) != 1 || len(e.digests[0].data) != len(h.Digest) {
+		return errors.New("SHA1 log event must have a single SHA1 digest")
+	}
+	copy(h.Digest[:], e.digests[0].data)
+	binary.Write(out, binary.LittleEndian, h)
+	out.Write(e.data)
+	return nil
+}
+
 // SHA1 event log format. See "5.1 SHA1 Event Log Entry Format"
 // https://trustedcomputinggroup.org/wp-content/uploads/EFI-Protocol-Specification-rev13-160330final.pdf#page=15
 type rawEventHeader struct {
@@ -644,7 +935,7 @@ type rawEventHeader struct {
 	EventSize uint32
 }
 
-func parseRawEvent(r *bytes.Buffer, _ *specIDEvent) (event rawEvent, err error) {
+func parseRawEvent(r *bytes.Buffer, _ *specIDEvent, buffers *ParseBuffers) (event rawEvent, err error) {
 	var h rawEventHeader
 	if err = binary.Read(r, binary.LittleEndian, &h); err != nil {
 		return event, fmt.Errorf("header deserialization error: %w", err)
@@ -653,12 +944,14 @@ func parseRawEvent(r *bytes.Buffer, _ *specIDEvent) (event rawEvent, err error)
 		return event, &eventSizeErr{h.EventSize, r.Len()}
 	}
 
-	data := make([]byte, int(h.EventSize))
+	data := buffers.bytes(int(h.EventSize))
 	if _, err := io.ReadFull(r, data); err != nil {
 		return event, fmt.Errorf("reading data error: %w", err)
 	}
 
-	digests := []digest{{hash: crypto.SHA1, data: h.Digest[:]}}
+	digestData := buffers.bytes(len(h.Digest))
+	copy(digestData, h.Digest[:])
+	digests := append(buffers.digestSlice(1), digest{hash: crypto.SHA1, data: digestData})
 
 	return rawEvent{
 		typ:     EventType(h.Type),
@@ -675,7 +968,7 @@ type rawEvent2Header struct {
 	Type     uint32
 }
 
-func parseRawEvent2(r *bytes.Buffer, specID *specIDEvent) (event rawEvent, err error) {
+func parseRawEvent2(r *bytes.Buffer, specID *specIDEvent, quirks Quirks, buffers *ParseBuffers) (event rawEvent, err error) {
 	var h rawEvent2Header
 
 	if err = binary.Read(r, binary.LittleEndian, &h); err != nil {
@@ -692,6 +985,7 @@ func parseRawEvent2(r *bytes.Buffer, specID *specIDEvent) (event rawEvent, err e
 	if err := binary.Read(r, binary.LittleEndian, &numDigests); err != nil {
 		return event, err
 	}
+	event.digests = buffers.digestSlice(numDigests)
 
 	for i := 0; i < int(numDigests); i++ {
 		var algID uint16
@@ -707,9 +1001,20 @@ func parseRawEvent2(r *bytes.Buffer, specID *specIDEvent) (event rawEvent, err e
 			if r.Len() < int(alg.Size) {
 				return event, fmt.Errorf("reading digest: %v", io.ErrUnexpectedEOF)
 			}
-			digest.data = make([]byte, alg.Size)
+			digest.data = buffers.bytes(int(alg.Size))
 			digest.hash = register.HashAlg(alg.ID).CryptoHash()
 		}
+		if len(digest.data) == 0 && quirks.Has(QuirkUnlistedDigests) {
+			// Skip digests of known algorithms not listed in the Spec ID event.
+			if hash, err := register.TPMAlgHash(algID); err == nil {
+				if r.Len() < hash.Size() {
+					return event, fmt.Errorf("reading digest: %v", io.ErrUnexpectedEOF)
+				}
+				r.Next(hash.Size())
+				event.quirks |= QuirkUnlistedDigests
+				continue
+			}
+		}
 		if len(digest.data) == 0 {
 			digest.data = make([]byte, 8)
 			digest.data[0] = 0
@@ -729,7 +1034,7 @@ func parseRawEvent2(r *bytes.Buffer, specID *specIDEvent) (event rawEvent, err e
 	if eventSize > uint32(r.Len()) {
 		return event, &eventSizeErr{eventSize, r.Len()}
 	}
-	event.data = make([]byte, int(eventSize))
+	event.data = buffers.bytes(int(eventSize))
 	if _, err := io.ReadFull(r, event.data); err != nil {
 		return event, err
 	}
@@ -0,0 +1,113 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import "github.com/google/go-eventlog/internal/quirkset"
+
+// Quirks is a set of workarounds for known deviations from the TCG PC Client
+// Platform Firmware Profile and the UEFI specification, e.g., in firmware TPM
+// (fTPM) or hypervisor event logs. Quirks relax verification, so they should
+// only be enabled for machines known to need them.
+//
+// Some quirks apply when parsing the event log with ParseOpts.Quirks, and
+// others when extracting state from the replayed events with the extract
+// package.
+type Quirks uint32
+
+// Supported quirks.
+const (
+	// QuirkUnlistedDigests accepts event digests for known hash algorithms
+	// that are not listed in the Spec ID event, rather than failing to parse
+	// the event log. The unlisted digests are skipped.
+	QuirkUnlistedDigests Quirks = 1 << iota
+	// QuirkEmptyEvents skips events with neither digests nor data, rather
+	// than returning them as events without a digest.
+	QuirkEmptyEvents
+	// QuirkPadding stops parsing at trailing padding, as with
+	// ParseOpts.AllowPadding.
+	QuirkPadding
+	// QuirkEmptySecureBootVar allows the SecureBoot variable to be measured
+	// with no data, meaning Secure Boot is disabled, as with
+	// extract.OptionalVariable. It applies during extraction.
+	QuirkEmptySecureBootVar
+	// QuirkErrorSeparators accepts separators with the FFFFFFFFh error value
+	// in the Secure Boot and driver registers, rather than only 00000000h. It
+	// applies during extraction.
+	QuirkErrorSeparators
+)
+
+// Named quirk sets for known fTPM vendors.
+const (
+	// AMDfTPMQuirks are the quirks enabled for AMD fTPM event logs.
+	AMDfTPMQuirks = QuirkUnlistedDigests | QuirkEmptyEvents
+	// IntelPTTQuirks are the quirks enabled for Intel Platform Trust
+	// Technology (PTT) fTPM event logs.
+	IntelPTTQuirks = QuirkEmptyEvents
+)
+
+// Named quirk profiles for known platforms.
+const (
+	// OVMFEDK22022Quirks are the quirks enabled for OVMF (EDK II) firmware
+	// released in 2022, which measures an empty SecureBoot variable when no
+	// keys are enrolled. QuirkErrorSeparators is not part of any profile, as
+	// error separators mean the firmware capped the PCRs.
+	OVMFEDK22022Quirks = QuirkEmptySecureBootVar
+	// GCEQuirks are the quirks enabled for Google Compute Engine VMs.
+	GCEQuirks = QuirkEmptySecureBootVar
+	// AzureQuirks are the quirks enabled for Azure VMs, whose event logs may
+	// be read from fixed-size buffers.
+	AzureQuirks = QuirkPadding | QuirkEmptyEvents
+)
+
+var quirkNames = []quirkset.Name[Quirks]{
+	{Quirk: QuirkUnlistedDigests, Name: "unlisted-digests"},
+	{Quirk: QuirkEmptyEvents, Name: "empty-events"},
+	{Quirk: QuirkPadding, Name: "padding"},
+	{Quirk: QuirkEmptySecureBootVar, Name: "empty-secureboot-var"},
+	{Quirk: QuirkErrorSeparators, Name: "error-separators"},
+}
+
+var quirkSets = map[string]Quirks{
+	"amd-ftpm":       AMDfTPMQuirks,
+	"intel-ptt":      IntelPTTQuirks,
+	"ovmf-edk2-2022": OVMFEDK22022Quirks,
+	"gce":            GCEQuirks,
+	"azure":          AzureQuirks,
+}
+
+// QuirkSet returns the named quirk set for an fTPM vendor or platform
+// profile, e.g., "amd-ftpm" or "ovmf-edk2-2022", for configuring quirks by
+// name.
+func QuirkSet(name string) (Quirks, error) {
+	return quirkset.Lookup(quirkSets, "quirk set", name)
+}
+
+// Has reports whether all of the quirks in q are in the set.
+func (s Quirks) Has(q Quirks) bool {
+	return s&q == q
+}
+
+// Names returns the names of the quirks in the set, e.g.,
+// ["unlisted-digests", "empty-events"]. Unknown quirks are named by their
+// hex value.
+func (s Quirks) Names() []string {
+	return quirkset.Names(s, quirkNames)
+}
+
+// String returns the names of the quirks in the set, e.g.,
+// "unlisted-digests|empty-events".
+func (s Quirks) String() string {
+	return quirkset.String(s, quirkNames)
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Fixed sizes of event data structures, from the TCG PC Client Platform
+// Firmware Profile Specification, section 10.2.
+const (
+	// UEFI_VARIABLE_DATA: VariableName GUID, UnicodeNameLength, and
+	// VariableDataLength.
+	variableDataHeaderLen = 16 + 8 + 8
+	// UEFI_IMAGE_LOAD_EVENT: ImageLocationInMemory, ImageLengthInMemory,
+	// ImageLinkTimeAddress, and LengthOfDevicePath.
+	imageLoadHeaderLen = 8 + 8 + 8 + 8
+	// UEFI_PLATFORM_FIRMWARE_BLOB: BlobBase and BlobLength.
+	firmwareBlobLen = 8 + 8
+	// UEFI_GPT_DATA: the UEFI partition table header and NumberOfPartitions.
+	gptDataMinLen = 92 + 8
+	// maxShortStringLen bounds the events holding short strings, e.g., the
+	// S-CRTM version and EFI actions.
+	maxShortStringLen = 1024
+)
+
+// sizeRule is the expected size of the event data of an event type.
+type sizeRule struct {
+	min, max int
+	// shape, if set, checks the lengths encoded in the event data against
+	// its size. It is only called if the size is within bounds.
+	shape func(data []byte) string
+}
+
+// eventSizeRules holds the expected data sizes of the event types with a
+// fixed or bounded size. max is 0 if unbounded.
+var eventSizeRules = map[EventType]sizeRule{
+	Separator:                  {min: 4, max: 4},
+	SCRTMVersion:               {min: 1, max: maxShortStringLen},
+	EFIAction:                  {min: 1, max: maxShortStringLen},
+	PlatformConfigFlags:        {min: 1},
+	EFIVariableDriverConfig:    {min: variableDataHeaderLen, shape: variableDataShape},
+	EFIVariableBoot:            {min: variableDataHeaderLen, shape: variableDataShape},
+	EFIVariableBoot2:           {min: variableDataHeaderLen, shape: variableDataShape},
+	EFIBootServicesApplication: {min: imageLoadHeaderLen, shape: imageLoadShape},
+	EFIBootServicesDriver:      {min: imageLoadHeaderLen, shape: imageLoadShape},
+	EFIRuntimeServicesDriver:   {min: imageLoadHeaderLen, shape: imageLoadShape},
+	EFIPlatformFirmwareBlob:    {min: firmwareBlobLen, max: firmwareBlobLen},
+	EFIPlatformFirmwareBlob2:   {min: 1 + firmwareBlobLen, shape: firmwareBlob2Shape},
+	EFIHandoffTables:           {min: 8},
+	EFIGPTEvent:                {min: gptDataMinLen},
+}
+
+// SizeAnomaly is an event whose data size or shape does not match the
+// expectation for its type. The event may still parse, but a well-formed log
+// has no size anomalies.
+type SizeAnomaly struct {
+	// Num is the position of the event in the event log.
+	Num uint32
+	// Index is the measurement register index encoded in the event log.
+	Index int
+	// Type is the untrusted event type.
+	Type EventType
+	// Size is the length of the event data.
+	Size int
+	// Reason describes the expectation the event data does not meet.
+	Reason string
+}
+
+func (a SizeAnomaly) String() string {
+	return fmt.Sprintf("event %d (%v in MR%d) has %d data bytes: %s", a.Num, a.Type, a.Index, a.Size, a.Reason)
+}
+
+// CheckEventSize checks the data size of an event of the given type against
+// the expected size, and the lengths encoded in the data. It returns a
+// description of the first mismatch, or "" if the data has the expected size
+// or the type has no expected size.
+func CheckEventSize(typ EventType, data []byte) string {
+	rule, ok := eventSizeRules[typ]
+	if !ok {
+		return ""
+	}
+	switch {
+	case rule.min == rule.max && len(data) != rule.min:
+		return fmt.Sprintf("want exactly %d", rule.min)
+	case len(data) < rule.min:
+		return fmt.Sprintf("want at least %d", rule.min)
+	case rule.max > 0 && len(data) > rule.max:
+		return fmt.Sprintf("want at most %d", rule.max)
+	case rule.shape != nil:
+		return rule.shape(data)
+	}
+	return ""
+}
+
+// CheckEventSizes returns the size anomalies of the events, e.g., those
+// returned by ParseAndReplay. See CheckEventSize.
+func CheckEventSizes(events []Event) []SizeAnomaly {
+	var anomalies []SizeAnomaly
+	for _, event := range events {
+		if reason := CheckEventSize(event.Type, event.Data); reason != "" {
+			anomalies = append(anomalies, SizeAnomaly{
+				Num:    event.Num(),
+				Index:  event.Index,
+				Type:   event.Type,
+				Size:   len(event.Data),
+				Reason: reason,
+			})
+		}
+	}
+	return anomalies
+}
+
+// SizeAnomalies returns the size anomalies of all events in the unverified
+// event log. See CheckEventSize.
+func (e *EventLog) SizeAnomalies() []SizeAnomaly {
+	var anomalies []SizeAnomaly
+	for _, event := range e.rawEvents {
+		if reason := CheckEventSize(event.typ, event.data); reason != "" {
+			anomalies = append(anomalies, SizeAnomaly{
+				Num:    uint32(event.sequence),
+				Index:  event.index,
+				Type:   event.typ,
+				Size:   len(event.data),
+				Reason: reason,
+			})
+		}
+	}
+	return anomalies
+}
+
+// variableDataShape checks that the name and data lengths of a
+// UEFI_VARIABLE_DATA account for the rest of the event data.
+func variableDataShape(data []byte) string {
+	nameLen := binary.LittleEndian.Uint64(data[16:24])
+	dataLen := binary.LittleEndian.Uint64(data[24:32])
+	rest := uint64(len(data) - variableDataHeaderLen)
+	if nameLen > rest/2 || dataLen != rest-2*nameLen {
+		return fmt.Sprintf("variable name length %d and data length %d do not match the %d bytes after the header", nameLen, dataLen, rest)
+	}
+	return ""
+}
+
+// imageLoadShape checks that the device path of a UEFI_IMAGE_LOAD_EVENT fits
+// in the event data. Trailing bytes are allowed, as some versions of shim
+// measure image loads with an empty device path followed by extra data.
+func imageLoadShape(data []byte) string {
+	pathLen := binary.LittleEndian.Uint64(data[24:32])
+	if rest := uint64(len(data) - imageLoadHeaderLen); pathLen > rest {
+		return fmt.Sprintf("device path length %d exceeds the %d bytes after the header", pathLen, rest)
+	}
+	return ""
+}
+
+// firmwareBlob2Shape checks that the description length of a
+// UEFI_PLATFORM_FIRMWARE_BLOB2 accounts for the rest of the event data.
+func firmwareBlob2Shape(data []byte) string {
+	descLen := int(data[0])
+	if want := 1 + descLen + firmwareBlobLen; len(data) != want {
+		return fmt.Sprintf("blob description length %d implies %d bytes", descLen, want)
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxSummaryLen bounds the length of an event summary, so large event data
+// does not dominate the summary.
+const MaxSummaryLen = 128
+
+// Summarize decodes the event data into a short, human-readable description,
+// e.g., the UEFI variable name or the action string. Malformed data is
+// described rather than returned as an error, since the summary is for
+// display. The summary is decoded from the event data, so it is only as
+// trustworthy as the event.
+func Summarize(event Event) string {
+	data := event.RawData()
+	var summary string
+	switch event.Type {
+	case EFIVariableDriverConfig, EFIVariableBoot, EFIVariableBoot2, EFIVariableAuthority:
+		v, err := ParseUEFIVariableData(bytes.NewReader(data))
+		if err != nil {
+			summary = fmt.Sprintf("malformed UEFI variable: %v", err)
+			break
+		}
+		summary = fmt.Sprintf("variable %s (%d bytes)", v.VarName(), len(v.VariableData))
+	case EFIBootServicesApplication, EFIBootServicesDriver, EFIRuntimeServicesDriver:
+		image, err := ParseEFIImageLoad(bytes.NewReader(data))
+		if err != nil {
+			summary = fmt.Sprintf("malformed EFI image load: %v", err)
+			break
+		}
+		summary = fmt.Sprintf("image of %d bytes at %#x", image.Header.Length, image.Header.LoadAddr)
+	case EventTag:
+		tagged, err := ParseTaggedEventData(data)
+		if err != nil {
+			// tboot and others log tagged events with plain text data.
+			summary = textOrSize(data)
+			break
+		}
+		summary = fmt.Sprintf("tag %#x (%d bytes)", tagged.ID, len(tagged.Data))
+	case Separator:
+		summary = "separator " + hex.EncodeToString(data)
+	default:
+		summary = textOrSize(data)
+	}
+	return truncate(summary)
+}
+
+// textOrSize returns the data as text if it is printable, ignoring trailing
+// NUL bytes, and its size otherwise.
+func textOrSize(data []byte) string {
+	text := bytes.TrimRight(data, "\x00")
+	if len(text) == 0 || !utf8.Valid(text) {
+		return fmt.Sprintf("%d bytes", len(data))
+	}
+	for _, r := range string(text) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return fmt.Sprintf("%d bytes", len(data))
+		}
+	}
+	return string(text)
+}
+
+func truncate(s string) string {
+	if len(s) <= MaxSummaryLen {
+		return s
+	}
+	// Cut at a rune boundary so the summary remains valid UTF-8.
+	end := MaxSummaryLen
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end] + "..."
+}
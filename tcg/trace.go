@@ -0,0 +1,198 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-eventlog/register"
+)
+
+// TraceStep is a single event extended during replay.
+type TraceStep struct {
+	// Event is the extended event. Its Digest is the digest that was extended.
+	Event Event
+	// Value is the register value after extending the event.
+	Value []byte
+}
+
+// MRTrace records the intermediate register values computed while replaying
+// the event log for a single measurement register, so tools can show where
+// the replayed chain diverges from the value read from the register.
+type MRTrace struct {
+	Index int
+	Hash  crypto.Hash
+	// Initial is the register value before the first event. For PCR 0, this
+	// reflects the locality TPM2_Startup was issued from. For a redacted
+	// register, it is the redacted register value.
+	Initial []byte
+	// Redacted is set if the events of the register were replaced by a
+	// redacted register event, see RedactEventLog. There are then no Steps.
+	Redacted bool
+	// Steps are the events extended into the register, in log order.
+	Steps []TraceStep
+	// Expected is the register value the replay is compared against.
+	Expected []byte
+	// Err is set if an event could not be replayed, e.g., because it has no
+	// digest for Hash. Steps holds the events replayed before it.
+	Err error
+}
+
+// Final returns the replayed register value after the last step.
+func (t MRTrace) Final() []byte {
+	if len(t.Steps) == 0 {
+		return t.Initial
+	}
+	return t.Steps[len(t.Steps)-1].Value
+}
+
+// Matches reports whether every event was replayed and the final replayed
+// value equals Expected.
+func (t MRTrace) Matches() bool {
+	return t.Err == nil && subtle.ConstantTimeCompare(t.Final(), t.Expected) == 1
+}
+
+// Trace replays the event log against each of the given measurement registers
+// and returns the intermediate values. Unlike Verify, it does not stop at the
+// first mismatch and does not apply any EventlogWorkarounds.
+func (e *EventLog) Trace(mrs []register.MR) []MRTrace {
+	traces := make([]MRTrace, 0, len(mrs))
+	for _, mr := range mrs {
+		traces = append(traces, traceMR(e.rawEvents, mr, e.allowRedactedMRs))
+	}
+	return traces
+}
+
+// traceMR replays the event log for a specific measurement register, using
+// register and event digests with the algorithm in mr. Redacted register
+// events are only replayed if allowRedacted is set.
+func traceMR(rawEvents []rawEvent, mr register.MR, allowRedacted bool) MRTrace {
+	trace := MRTrace{
+		Index:    mr.Idx(),
+		Hash:     mr.DgstAlg(),
+		Expected: mr.Dgst(),
+	}
+	var (
+		replay   []byte
+		locality byte
+	)
+	for _, e := range rawEvents {
+		if e.index != trace.Index {
+			continue
+		}
+		// If TXT is enabled then the first event for PCR0
+		// should be a StartupLocality event. The final byte
+		// of this event indicates the locality from which
+		// TPM2_Startup() was issued. The initial value of
+		// PCR0 is equal to the locality.
+		if e.typ == eventTypeNoAction {
+			if trace.Index == 0 && isStartupLocality(e) {
+				locality = e.data[len(e.data)-1]
+			}
+			if isRedactedMR(e) {
+				if !allowRedacted {
+					trace.Err = fmt.Errorf("event %d: %v", e.sequence, errRedactedMR)
+					return trace
+				}
+				if trace.Redacted || len(trace.Steps) > 0 {
+					trace.Err = fmt.Errorf("event %d: redacted register value with other events", e.sequence)
+					return trace
+				}
+				value, err := redactedMRValue(e, trace.Hash)
+				if err != nil {
+					trace.Err = fmt.Errorf("event %d: %v", e.sequence, err)
+					return trace
+				}
+				trace.Initial = value
+				trace.Redacted = true
+			}
+			continue
+		}
+		if trace.Redacted {
+			trace.Err = fmt.Errorf("event %d: redacted register value with other events", e.sequence)
+			return trace
+		}
+		if trace.Initial == nil {
+			trace.Initial = initialValue(trace.Hash, locality)
+		}
+		replayValue, digest, err := extend(mr, replay, e, locality)
+		if err != nil {
+			trace.Err = fmt.Errorf("event %d: %v", e.sequence, err)
+			return trace
+		}
+		replay = replayValue
+		trace.Steps = append(trace.Steps, TraceStep{
+			Event: Event{
+				sequence: e.sequence,
+				Data:     e.data,
+				Digest:   digest,
+				Index:    trace.Index,
+				Type:     e.typ,
+				hash:     trace.Hash,
+			},
+			Value: replayValue,
+		})
+	}
+	if trace.Initial == nil {
+		trace.Initial = initialValue(trace.Hash, locality)
+	}
+	return trace
+}
+
+func initialValue(h crypto.Hash, locality byte) []byte {
+	b := make([]byte, h.Size())
+	b[h.Size()-1] = locality
+	return b
+}
+
+// redactedMRSignature identifies the redacted register events written by
+// RedactEventLog. It is specific to this package, in the style of the
+// StartupLocality event signature.
+const redactedMRSignature = "RedactedMR\x00\x00\x00\x00\x00\x00"
+
+// errRedactedMR is the replay error of a redacted register event parsed
+// without ParseOpts.AllowRedactedMRs.
+var errRedactedMR = errors.New("redacted register event, which is only accepted with ParseOpts.AllowRedactedMRs")
+
+// isRedactedMR reports whether the event is a redacted register event, whose
+// digests are the register values its redacted events replay to.
+func isRedactedMR(e rawEvent) bool {
+	return e.typ == eventTypeNoAction && string(e.data) == redactedMRSignature
+}
+
+// redactedMRValue returns the register value of a redacted register event for
+// the hash algorithm.
+func redactedMRValue(e rawEvent, hash crypto.Hash) ([]byte, error) {
+	for _, d := range e.digests {
+		if d.hash != hash {
+			continue
+		}
+		if len(d.data) != hash.Size() {
+			return nil, fmt.Errorf("redacted register value length (%d) doesn't match register digest length (%d)", len(d.data), hash.Size())
+		}
+		return d.data, nil
+	}
+	return nil, fmt.Errorf("no redacted register value matches register algorithm: %v", hash)
+}
+
+// isStartupLocality reports whether the event is a StartupLocality event,
+// whose final byte is the locality TPM2_Startup was issued from.
+func isStartupLocality(e rawEvent) bool {
+	return e.typ == eventTypeNoAction && len(e.data) == 17 && strings.HasPrefix(string(e.data), "StartupLocality")
+}
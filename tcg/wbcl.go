@@ -0,0 +1,321 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package tcg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// platformAttestationMagic is the magic number of a PCP_PLATFORM_ATTESTATION_BLOB,
+// "PLAT" in little-endian.
+var platformAttestationMagic = []byte("PLAT")
+
+// platformAttestationHeader is the header of a PCP_PLATFORM_ATTESTATION_BLOB,
+// from the Windows Platform Crypto Provider. Version 2 headers add the PCR
+// bank algorithm, and later headers may be larger still, so the fields are
+// located using HeaderSize.
+type platformAttestationHeader struct {
+	Magic       uint32
+	Platform    uint32
+	HeaderSize  uint32
+	PCRValuesSz uint32
+	QuoteSz     uint32
+	SignatureSz uint32
+	LogSz       uint32
+}
+
+// PlatformAttestation is a Windows platform attestation blob, as produced by
+// the Platform Crypto Provider (e.g., NCryptCreateClaim), which wraps the
+// Windows Boot Configuration Log (WBCL) with the PCR values and a quote over
+// them.
+type PlatformAttestation struct {
+	// Platform is the TPM version, 1 for TPM 1.2 and 2 for TPM 2.0.
+	Platform uint32
+	// PCRAlg is the TPM_ALG_ID of PCRValues, if the header has one.
+	PCRAlg uint16
+	// PCRValues are the concatenated PCR values.
+	PCRValues []byte
+	// Quote is the TPM quote over the PCR values, and Signature its signature.
+	Quote     []byte
+	Signature []byte
+	// Log is the WBCL, a TCG event log.
+	Log []byte
+}
+
+// IsPlatformAttestation reports whether the data starts with the magic number
+// of a Windows platform attestation blob. The magic number is not a valid
+// start of a TCG event log, as it would encode an out-of-range PCR index in
+// the first event.
+func IsPlatformAttestation(data []byte) bool {
+	return bytes.HasPrefix(data, platformAttestationMagic)
+}
+
+// ParsePlatformAttestation parses a Windows platform attestation blob. None
+// of its contents are verified.
+func ParsePlatformAttestation(data []byte) (*PlatformAttestation, error) {
+	if !IsPlatformAttestation(data) {
+		return nil, fmt.Errorf("not a platform attestation blob")
+	}
+	var h platformAttestationHeader
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &h); err != nil {
+		return nil, fmt.Errorf("failed to parse platform attestation header: %v", err)
+	}
+	headerLen := uint64(binary.Size(h))
+	if uint64(h.HeaderSize) < headerLen {
+		return nil, fmt.Errorf("platform attestation header size %d is less than %d", h.HeaderSize, headerLen)
+	}
+	want := uint64(h.HeaderSize) + uint64(h.PCRValuesSz) + uint64(h.QuoteSz) + uint64(h.SignatureSz) + uint64(h.LogSz)
+	if want != uint64(len(data)) {
+		return nil, fmt.Errorf("platform attestation sizes sum to %d bytes, but the blob has %d", want, len(data))
+	}
+	pa := &PlatformAttestation{Platform: h.Platform}
+	if h.HeaderSize >= uint32(headerLen)+4 {
+		pa.PCRAlg = uint16(binary.LittleEndian.Uint32(data[headerLen:]))
+	}
+	rest := data[h.HeaderSize:]
+	for _, field := range []struct {
+		dst  *[]byte
+		size uint32
+	}{
+		{&pa.PCRValues, h.PCRValuesSz},
+		{&pa.Quote, h.QuoteSz},
+		{&pa.Signature, h.SignatureSz},
+		{&pa.Log, h.LogSz},
+	} {
+		*field.dst = rest[:field.size:field.size]
+		rest = rest[field.size:]
+	}
+	return pa, nil
+}
+
+// UnwrapWBCL returns the TCG event log of a Windows platform attestation
+// blob. Other data, e.g., a WBCL read with Tbsi_Get_TCG_Log, is returned
+// unchanged.
+func UnwrapWBCL(data []byte) ([]byte, error) {
+	if !IsPlatformAttestation(data) {
+		return data, nil
+	}
+	pa, err := ParsePlatformAttestation(data)
+	if err != nil {
+		return nil, err
+	}
+	return pa.Log, nil
+}
+
+// SipaEventType is the type of a Windows System Integrity Platform
+// Attestation (SIPA) event. The high bits of the type give its category, and
+// whether it is an aggregation of other SIPA events.
+type SipaEventType uint32
+
+// SIPA event type flags and categories, from wbcl.h in the Windows SDK.
+const (
+	SipaNonMeasured SipaEventType = 0x80000000
+	SipaAggregation SipaEventType = 0x40000000
+	// SipaContainer is the category of events holding other SIPA events.
+	SipaContainer    SipaEventType = 0x00010000
+	sipaCategoryMask SipaEventType = 0x000f0000
+	SipaInformation  SipaEventType = 0x00020000
+	SipaPreOSParam   SipaEventType = 0x00040000
+	SipaOSParam      SipaEventType = 0x00050000
+	SipaLoadedModule SipaEventType = 0x00070000
+	SipaELAMCategory SipaEventType = 0x00090000
+)
+
+const (
+	// sipaEventHeaderLen is the length of the SipaEventType and the event
+	// data size.
+	sipaEventHeaderLen = 8
+	// sipaMaxNesting bounds the depth of containers.
+	sipaMaxNesting = 8
+)
+
+// SIPA event types, from wbcl.h in the Windows SDK.
+const (
+	SipaTrustBoundary           SipaEventType = 0x40010001
+	SipaELAMAggregation         SipaEventType = 0x40010002
+	SipaLoadedModuleAggregation SipaEventType = 0x40010003
+	SipaTrustPointAggregation   SipaEventType = 0xC0010004
+	SipaKSRAggregation          SipaEventType = 0x40010005
+
+	SipaInformationEvent  SipaEventType = 0x00020001
+	SipaBootCounter       SipaEventType = 0x00020002
+	SipaTransferControl   SipaEventType = 0x00020003
+	SipaApplicationReturn SipaEventType = 0x00020004
+	SipaBitLockerUnlock   SipaEventType = 0x00020005
+	SipaEventCounter      SipaEventType = 0x00020006
+	SipaCounterID         SipaEventType = 0x00020007
+	SipaApplicationSVN    SipaEventType = 0x00020009
+	SipaSVNChainStatus    SipaEventType = 0x0002000A
+	SipaMORBitAPIStatus   SipaEventType = 0x0002000B
+
+	SipaBootDebugging      SipaEventType = 0x00040001
+	SipaBootRevocationList SipaEventType = 0x00040002
+
+	SipaOSKernelDebug           SipaEventType = 0x00050001
+	SipaCodeIntegrity           SipaEventType = 0x00050002
+	SipaTestSigning             SipaEventType = 0x00050003
+	SipaDataExecutionPrevention SipaEventType = 0x00050004
+	SipaSafeMode                SipaEventType = 0x00050005
+	SipaWinPE                   SipaEventType = 0x00050006
+	SipaPhysicalAddressExt      SipaEventType = 0x00050007
+	SipaOSDevice                SipaEventType = 0x00050008
+	SipaSystemRoot              SipaEventType = 0x00050009
+	SipaHypervisorLaunchType    SipaEventType = 0x0005000A
+	SipaHypervisorPath          SipaEventType = 0x0005000B
+	SipaHypervisorIOMMUPolicy   SipaEventType = 0x0005000C
+	SipaHypervisorDebug         SipaEventType = 0x0005000D
+	SipaDriverLoadPolicy        SipaEventType = 0x0005000E
+	SipaSIPolicy                SipaEventType = 0x0005000F
+	SipaVSMLaunchType           SipaEventType = 0x00050012
+	SipaOSRevocationList        SipaEventType = 0x00050013
+	SipaFlightSigning           SipaEventType = 0x00050021
+
+	SipaNoAuthority     SipaEventType = 0x00060001
+	SipaAuthorityPubKey SipaEventType = 0x00060002
+
+	SipaFilePath                SipaEventType = 0x00070001
+	SipaImageSize               SipaEventType = 0x00070002
+	SipaHashAlgorithmID         SipaEventType = 0x00070003
+	SipaAuthenticodeHash        SipaEventType = 0x00070004
+	SipaAuthorityIssuer         SipaEventType = 0x00070005
+	SipaAuthoritySerial         SipaEventType = 0x00070006
+	SipaImageBase               SipaEventType = 0x00070007
+	SipaAuthorityPublisher      SipaEventType = 0x00070008
+	SipaAuthoritySHA1Thumbprint SipaEventType = 0x00070009
+	SipaImageValidated          SipaEventType = 0x0007000A
+	SipaModuleSVN               SipaEventType = 0x0007000B
+
+	SipaELAMKeyname       SipaEventType = 0x00090001
+	SipaELAMConfiguration SipaEventType = 0x00090002
+	SipaELAMPolicy        SipaEventType = 0x00090003
+	SipaELAMMeasured      SipaEventType = 0x00090004
+)
+
+var sipaEventTypeNames = map[SipaEventType]string{
+	SipaTrustBoundary:           "SIPAEVENT_TRUSTBOUNDARY",
+	SipaELAMAggregation:         "SIPAEVENT_ELAM_AGGREGATION",
+	SipaLoadedModuleAggregation: "SIPAEVENT_LOADEDMODULE_AGGREGATION",
+	SipaTrustPointAggregation:   "SIPAEVENT_TRUSTPOINT_AGGREGATION",
+	SipaKSRAggregation:          "SIPAEVENT_KSR_AGGREGATION",
+	SipaInformationEvent:        "SIPAEVENT_INFORMATION",
+	SipaBootCounter:             "SIPAEVENT_BOOTCOUNTER",
+	SipaTransferControl:         "SIPAEVENT_TRANSFER_CONTROL",
+	SipaApplicationReturn:       "SIPAEVENT_APPLICATION_RETURN",
+	SipaBitLockerUnlock:         "SIPAEVENT_BITLOCKER_UNLOCK",
+	SipaEventCounter:            "SIPAEVENT_EVENTCOUNTER",
+	SipaCounterID:               "SIPAEVENT_COUNTERID",
+	SipaApplicationSVN:          "SIPAEVENT_APPLICATION_SVN",
+	SipaSVNChainStatus:          "SIPAEVENT_SVN_CHAIN_STATUS",
+	SipaMORBitAPIStatus:         "SIPAEVENT_MORBIT_API_STATUS",
+	SipaBootDebugging:           "SIPAEVENT_BOOTDEBUGGING",
+	SipaBootRevocationList:      "SIPAEVENT_BOOT_REVOCATION_LIST",
+	SipaOSKernelDebug:           "SIPAEVENT_OSKERNELDEBUG",
+	SipaCodeIntegrity:           "SIPAEVENT_CODEINTEGRITY",
+	SipaTestSigning:             "SIPAEVENT_TESTSIGNING",
+	SipaDataExecutionPrevention: "SIPAEVENT_DATAEXECUTIONPREVENTION",
+	SipaSafeMode:                "SIPAEVENT_SAFEMODE",
+	SipaWinPE:                   "SIPAEVENT_WINPE",
+	SipaPhysicalAddressExt:      "SIPAEVENT_PHYSICALADDRESSEXTENSION",
+	SipaOSDevice:                "SIPAEVENT_OSDEVICE",
+	SipaSystemRoot:              "SIPAEVENT_SYSTEMROOT",
+	SipaHypervisorLaunchType:    "SIPAEVENT_HYPERVISOR_LAUNCH_TYPE",
+	SipaHypervisorPath:          "SIPAEVENT_HYPERVISOR_PATH",
+	SipaHypervisorIOMMUPolicy:   "SIPAEVENT_HYPERVISOR_IOMMU_POLICY",
+	SipaHypervisorDebug:         "SIPAEVENT_HYPERVISOR_DEBUG",
+	SipaDriverLoadPolicy:        "SIPAEVENT_DRIVER_LOAD_POLICY",
+	SipaSIPolicy:                "SIPAEVENT_SI_POLICY",
+	SipaVSMLaunchType:           "SIPAEVENT_VSM_LAUNCH_TYPE",
+	SipaOSRevocationList:        "SIPAEVENT_OS_REVOCATION_LIST",
+	SipaFlightSigning:           "SIPAEVENT_FLIGHTSIGNING",
+	SipaNoAuthority:             "SIPAEVENT_NOAUTHORITY",
+	SipaAuthorityPubKey:         "SIPAEVENT_AUTHORITYPUBKEY",
+	SipaFilePath:                "SIPAEVENT_FILEPATH",
+	SipaImageSize:               "SIPAEVENT_IMAGESIZE",
+	SipaHashAlgorithmID:         "SIPAEVENT_HASHALGORITHMID",
+	SipaAuthenticodeHash:        "SIPAEVENT_AUTHENTICODEHASH",
+	SipaAuthorityIssuer:         "SIPAEVENT_AUTHORITYISSUER",
+	SipaAuthoritySerial:         "SIPAEVENT_AUTHORITYSERIAL",
+	SipaImageBase:               "SIPAEVENT_IMAGEBASE",
+	SipaAuthorityPublisher:      "SIPAEVENT_AUTHORITYPUBLISHER",
+	SipaAuthoritySHA1Thumbprint: "SIPAEVENT_AUTHORITYSHA1THUMBPRINT",
+	SipaImageValidated:          "SIPAEVENT_IMAGEVALIDATED",
+	SipaModuleSVN:               "SIPAEVENT_MODULE_SVN",
+	SipaELAMKeyname:             "SIPAEVENT_ELAM_KEYNAME",
+	SipaELAMConfiguration:       "SIPAEVENT_ELAM_CONFIGURATION",
+	SipaELAMPolicy:              "SIPAEVENT_ELAM_POLICY",
+	SipaELAMMeasured:            "SIPAEVENT_ELAM_MEASURED",
+}
+
+func (t SipaEventType) String() string {
+	if name, ok := sipaEventTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("SIPAEVENT(0x%08x)", uint32(t))
+}
+
+// IsContainer reports whether the event data holds other SIPA events.
+func (t SipaEventType) IsContainer() bool {
+	return t&sipaCategoryMask == SipaContainer
+}
+
+// SipaEvent is a Windows SIPA event. Windows measures SIPA events as
+// EV_EVENT_TAG events in PCRs 11 to 14 of the WBCL.
+type SipaEvent struct {
+	Type SipaEventType
+	// Data is the event data. It is nil for containers.
+	Data []byte
+	// Children are the events held by a container.
+	Children []SipaEvent
+}
+
+// ParseSipaEvents parses the SIPA events in EV_EVENT_TAG event data.
+// Containers are parsed recursively. The data is not trusted unless the
+// event is verified.
+func ParseSipaEvents(data []byte) ([]SipaEvent, error) {
+	return parseSipaEvents(data, 0)
+}
+
+func parseSipaEvents(data []byte, depth int) ([]SipaEvent, error) {
+	if depth > sipaMaxNesting {
+		return nil, fmt.Errorf("SIPA events nested more than %d deep", sipaMaxNesting)
+	}
+	var events []SipaEvent
+	for len(data) > 0 {
+		if len(data) < sipaEventHeaderLen {
+			return nil, fmt.Errorf("SIPA event header truncated: %d bytes", len(data))
+		}
+		typ := SipaEventType(binary.LittleEndian.Uint32(data))
+		size := binary.LittleEndian.Uint32(data[4:])
+		data = data[sipaEventHeaderLen:]
+		if uint64(size) > uint64(len(data)) {
+			return nil, fmt.Errorf("%v event size %d exceeds the remaining %d bytes", typ, size, len(data))
+		}
+		event := SipaEvent{Type: typ}
+		if typ.IsContainer() {
+			children, err := parseSipaEvents(data[:size], depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("in %v: %v", typ, err)
+			}
+			event.Children = children
+		} else {
+			event.Data = data[:size:size]
+		}
+		events = append(events, event)
+		data = data[size:]
+	}
+	return events, nil
+}
@@ -18,6 +18,10 @@
 package tpmeventlog
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/google/go-eventlog/extract"
 	pb "github.com/google/go-eventlog/proto/state"
 	"github.com/google/go-eventlog/register"
@@ -43,10 +47,167 @@ func ReplayAndExtract(rawEventLog []byte, pcrBank register.PCRBank, opts extract
 	if err != nil {
 		return &pb.FirmwareLogState{}, err
 	}
-	events, err := tcg.ParseAndReplay(rawEventLog, pcrBank.MRs(), tcg.ParseOpts{})
+	parseOpts, err := opts.EventLogParseOpts(tcg.ParseOpts{})
+	if err != nil {
+		return nil, err
+	}
+	// As with tcg.ParseAndReplay, an empty log has no events.
+	var events []tcg.Event
+	var applied tcg.Quirks
+	var redacted []int
+	if len(rawEventLog) > 0 {
+		eventLog, err := tcg.ParseEventLog(rawEventLog, parseOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse event log: %v", err)
+		}
+		if events, err = eventLog.Verify(pcrBank.MRs()); err != nil {
+			return nil, fmt.Errorf("failed to replay event log: %v", err)
+		}
+		applied = eventLog.AppliedQuirks
+		redacted = eventLog.RedactedMRs
+	}
+
+	// Report the findings once the parse quirk findings are added.
+	extractOpts := opts
+	extractOpts.FindingSink = nil
+	state, err := extract.FirmwareLogState(events, cryptoHash, extract.TPMRegisterConfig, extractOpts)
+	state = opts.OwnedState(state)
+	extract.ReportQuirks(state, applied)
+	if redactedErr := opts.ReportRedactedMRs(state, redacted); redactedErr != nil {
+		err = errors.Join(err, redactedErr)
+	}
+	opts.ReportFindings(state)
+	return state, err
+}
+
+// BankStrategy selects which PCR banks, and so which digests of a crypto
+// agile event log, are verified and reported by ReplayAndExtractBanks.
+type BankStrategy int
+
+// Supported bank strategies.
+const (
+	// StrongestBank verifies and reports the digests of the bank with the
+	// strongest hash algorithm that the event log has digests for.
+	StrongestBank BankStrategy = iota
+	// AllBanks verifies the digests of every bank. The states extracted from
+	// each bank are merged with extract.MergeFirmwareLogStates, which reports
+	// the digests of the strongest bank.
+	AllBanks
+	// SpecificBank verifies and reports the digests of the bank with
+	// BankOpts.Hash.
+	SpecificBank
+)
+
+// BankOpts gives options for selecting PCR banks in ReplayAndExtractBanks.
+type BankOpts struct {
+	Strategy BankStrategy
+	// Hash is the bank hash algorithm used with SpecificBank.
+	Hash pb.HashAlgo
+}
+
+// ReplayAndExtractBanks parses a PC Client event log, replays it against the
+// PCR banks selected by bankOpts, and extracts event info from the verified
+// log into a FirmwareLogState.
+//
+// Banks using a hash algorithm the event log has no digests for are ignored by
+// StrongestBank, and are an error for AllBanks and SpecificBank.
+//
+// As with ReplayAndExtract, the returned FirmwareLogState may be partial, and
+// it is the caller's responsibility to ensure that the PCR values can be
+// trusted.
+func ReplayAndExtractBanks(rawEventLog []byte, pcrBanks []register.PCRBank, bankOpts BankOpts, opts extract.Opts) (*pb.FirmwareLogState, error) {
+	parseOpts, err := opts.EventLogParseOpts(tcg.ParseOpts{})
+	if err != nil {
+		return nil, err
+	}
+	eventLog, err := tcg.ParseEventLog(rawEventLog, parseOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event log: %v", err)
+	}
+	banks, err := selectBanks(eventLog.Algs, pcrBanks, bankOpts)
 	if err != nil {
 		return nil, err
 	}
 
-	return extract.FirmwareLogState(events, cryptoHash, extract.TPMRegisterConfig, opts)
+	// Report the findings of the merged state, once the parse quirk findings
+	// are added.
+	extractOpts := opts
+	extractOpts.FindingSink = nil
+	states := make([]*pb.FirmwareLogState, 0, len(banks))
+	var joined error
+	for _, bank := range banks {
+		cryptoHash, err := bank.CryptoHash()
+		if err != nil {
+			return nil, err
+		}
+		events, err := eventLog.Verify(bank.MRs())
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay event log against the %v bank: %v", bank.TCGHashAlgo, err)
+		}
+		state, err := extract.FirmwareLogState(events, cryptoHash, extract.TPMRegisterConfig, extractOpts)
+		if err != nil {
+			joined = errors.Join(joined, err)
+		}
+		states = append(states, state)
+	}
+	if len(states) == 1 {
+		state := opts.OwnedState(states[0])
+		extract.ReportQuirks(state, eventLog.AppliedQuirks)
+		if redactedErr := opts.ReportRedactedMRs(state, eventLog.RedactedMRs); redactedErr != nil {
+			joined = errors.Join(joined, redactedErr)
+		}
+		opts.ReportFindings(state)
+		return state, joined
+	}
+	merged, err := extract.MergeFirmwareLogStates(states...)
+	merged = opts.OwnedState(merged)
+	extract.ReportQuirks(merged, eventLog.AppliedQuirks)
+	if redactedErr := opts.ReportRedactedMRs(merged, eventLog.RedactedMRs); redactedErr != nil {
+		joined = errors.Join(joined, redactedErr)
+	}
+	opts.ReportFindings(merged)
+	return merged, errors.Join(joined, err)
+}
+
+// selectBanks returns the PCR banks to verify for bankOpts, strongest first.
+func selectBanks(logAlgs []register.HashAlg, pcrBanks []register.PCRBank, bankOpts BankOpts) ([]register.PCRBank, error) {
+	inLog := func(alg pb.HashAlgo) bool {
+		for _, logAlg := range logAlgs {
+			if pb.HashAlgo(logAlg) == alg {
+				return true
+			}
+		}
+		return false
+	}
+
+	var banks []register.PCRBank
+	for _, bank := range pcrBanks {
+		if bankOpts.Strategy == SpecificBank && bank.TCGHashAlgo != bankOpts.Hash {
+			continue
+		}
+		if !inLog(bank.TCGHashAlgo) {
+			if bankOpts.Strategy == StrongestBank {
+				continue
+			}
+			return nil, fmt.Errorf("event log has no digests for the %v bank", bank.TCGHashAlgo)
+		}
+		banks = append(banks, bank)
+	}
+	if len(banks) == 0 {
+		if bankOpts.Strategy == SpecificBank {
+			return nil, fmt.Errorf("no %v bank provided", bankOpts.Hash)
+		}
+		return nil, errors.New("no PCR bank matches the event log digests")
+	}
+	sort.SliceStable(banks, func(i, j int) bool {
+		return banks[i].TCGHashAlgo.Strength() > banks[j].TCGHashAlgo.Strength()
+	})
+
+	switch bankOpts.Strategy {
+	case StrongestBank, SpecificBank:
+		return banks[:1], nil
+	case AllBanks:
+		return banks, nil
+	}
+	return nil, fmt.Errorf("unknown bank strategy %d", bankOpts.Strategy)
 }
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"crypto"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -27,6 +28,7 @@ import (
 	"github.com/google/go-eventlog/internal/testutil"
 	pb "github.com/google/go-eventlog/proto/state"
 	"github.com/google/go-eventlog/register"
+	"github.com/google/go-eventlog/tcg"
 	"github.com/google/go-eventlog/testdata"
 	"github.com/google/go-eventlog/wellknown"
 	"google.golang.org/protobuf/testing/protocmp"
@@ -531,7 +533,7 @@ func TestParseEventLogs(t *testing.T) {
 		{Ubuntu2104NoSecureBootGCE, "Ubuntu2104NoSecureBootGCE", extract.GRUB, []string{sbatErrorStr}},
 		{Ubuntu2404AmdSevSnp, "Ubuntu2404AmdSevSnp", extract.GRUB, nil},
 		// This event log has a SecureBoot variable length of 0.
-		{ArchLinuxWorkstation, "ArchLinuxWorkstation", extract.UnsupportedLoader, archLinuxKnownParsingFailures},
+		{ArchLinuxWorkstation, "ArchLinuxWorkstation", extract.SystemdBoot, archLinuxKnownParsingFailures},
 		{COS85AmdSev, "COS85AmdSev", extract.GRUB, nil},
 		{COS93AmdSev, "COS93AmdSev", extract.GRUB, nil},
 		{COS101AmdSev, "COS101AmdSev", extract.GRUB, nil},
@@ -555,6 +557,192 @@ func TestParseEventLogs(t *testing.T) {
 	}
 }
 
+func TestReplayAndExtractSystemdBoot(t *testing.T) {
+	const wantCmdline = `initrd=\intel-ucode.img initrd=\initramfs-linux-lts.img cryptdevice=UUID=5465369a-996d-42ca-9ad4-91d0082e0b34:cryptroot root=/dev/mapper/cryptroot rw intel_iommu=on iommu=pt l1tf=off`
+	for _, bank := range ArchLinuxWorkstation.Banks {
+		t.Run(bank.TCGHashAlgo.String(), func(t *testing.T) {
+			events, err := tcg.ParseAndReplay(ArchLinuxWorkstation.RawLog, bank.MRs(), tcg.ParseOpts{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The kernel is the last EFI application, started by systemd-boot.
+			var wantKernelDigest []byte
+			for _, event := range events {
+				if event.MRIndex() == 4 && event.UntrustedType() == tcg.EFIBootServicesApplication {
+					wantKernelDigest = event.ReplayedDigest()
+				}
+			}
+			// The Secure Boot and EFI state of this event log fail to parse, but
+			// the kernel state is still returned.
+			state, _ := ReplayAndExtract(ArchLinuxWorkstation.RawLog, bank, extract.Opts{Loader: extract.SystemdBoot})
+			kernel := state.GetLinuxKernel()
+			if kernel.GetCommandLine() != wantCmdline {
+				t.Errorf("ReplayAndExtract(): got kernel command line %q, want %q", kernel.GetCommandLine(), wantCmdline)
+			}
+			if !bytes.Equal(kernel.GetKernelDigest(), wantKernelDigest) {
+				t.Errorf("ReplayAndExtract(): got kernel digest %x, want %x", kernel.GetKernelDigest(), wantKernelDigest)
+			}
+
+			old, _ := ReplayAndExtract(ArchLinuxWorkstation.RawLog, bank, extract.Opts{Loader: extract.SystemdBoot, CompatLevel: 8})
+			if old.GetLinuxKernel() != nil {
+				t.Errorf("ReplayAndExtract(CompatLevel 8): got kernel state %v, want none", old.GetLinuxKernel())
+			}
+		})
+	}
+}
+
+func TestDetectBootloader(t *testing.T) {
+	logs := []struct {
+		eventLog
+		name string
+		want extract.Bootloader
+	}{
+		{Debian10GCE, "Debian10GCE", extract.UnsupportedLoader},
+		{Rhel8GCE, "Rhel8GCE", extract.GRUB},
+		{UbuntuAmdSevGCE, "UbuntuAmdSevGCE", extract.GRUB},
+		{Ubuntu2404AmdSevSnp, "Ubuntu2404AmdSevSnp", extract.GRUB},
+		{ArchLinuxWorkstation, "ArchLinuxWorkstation", extract.SystemdBoot},
+		{COS101AmdSev, "COS101AmdSev", extract.GRUB},
+	}
+	for _, log := range logs {
+		t.Run(log.name, func(t *testing.T) {
+			events, err := tcg.ParseAndReplay(log.RawLog, log.Banks[0].MRs(), tcg.ParseOpts{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := extract.DetectBootloader(events, extract.TPMRegisterConfig); got != log.want {
+				t.Errorf("DetectBootloader() = %v, want %v", got, log.want)
+			}
+		})
+	}
+}
+
+func TestMergeFirmwareLogStatesAcrossBanks(t *testing.T) {
+	var states []*pb.FirmwareLogState
+	for _, bank := range Ubuntu2404AmdSevSnp.Banks {
+		state, err := ReplayAndExtract(Ubuntu2404AmdSevSnp.RawLog, bank, extract.Opts{Loader: extract.GRUB})
+		if err != nil {
+			t.Fatalf("ReplayAndExtract(%v): %v", bank.TCGHashAlgo, err)
+		}
+		states = append(states, state)
+	}
+	if len(states) < 2 {
+		t.Fatalf("got %d banks, want at least 2", len(states))
+	}
+
+	merged, err := extract.MergeFirmwareLogStates(states...)
+	if err != nil {
+		t.Fatalf("MergeFirmwareLogStates(): %v", err)
+	}
+	if merged.GetHash() != pb.HashAlgo_SHA384 && merged.GetHash() != pb.HashAlgo_SHA256 {
+		t.Errorf("MergeFirmwareLogStates(): got authoritative hash %v, want the strongest bank", merged.GetHash())
+	}
+	if len(merged.GetGrub().GetFiles()) == 0 || len(merged.GetGrub().GetCommandDigests()) != len(merged.GetGrub().GetCommands()) {
+		t.Fatalf("MergeFirmwareLogStates(): got %d GRUB files and %d command digests for %d commands", len(merged.GetGrub().GetFiles()), len(merged.GetGrub().GetCommandDigests()), len(merged.GetGrub().GetCommands()))
+	}
+	for _, file := range merged.GetGrub().GetFiles() {
+		if file.GetHash() != merged.GetHash() {
+			t.Errorf("MergeFirmwareLogStates(): got GRUB file hash %v, want %v", file.GetHash(), merged.GetHash())
+		}
+		if len(file.GetBankDigests()) != len(states) || !bytes.Equal(file.GetBankDigests()[0].GetDigest(), file.GetDigest()) {
+			t.Errorf("MergeFirmwareLogStates(): got GRUB file bank digests %v, want %d banks starting with %x", file.GetBankDigests(), len(states), file.GetDigest())
+		}
+	}
+	for i, digests := range merged.GetGrub().GetCommandDigests() {
+		var hashes []pb.HashAlgo
+		for _, digest := range digests.GetDigests() {
+			hashes = append(hashes, digest.GetHash())
+		}
+		if len(hashes) != len(states) || hashes[0] != merged.GetHash() {
+			t.Errorf("MergeFirmwareLogStates(): got command %d digest banks %v, want %d banks starting with %v", i, hashes, len(states), merged.GetHash())
+		}
+	}
+
+	states[0].LinuxKernel.CommandLine += " init=/bin/sh"
+	_, err = extract.MergeFirmwareLogStates(states...)
+	var mismatch extract.BankMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("MergeFirmwareLogStates(tampered): got %v, want BankMismatchError", err)
+	}
+	if mismatch.Other != states[0].GetHash() || len(mismatch.Fields) != 1 || mismatch.Fields[0] != "linux_kernel" {
+		t.Errorf("MergeFirmwareLogStates(tampered): got %+v, want linux_kernel mismatch for %v", mismatch, states[0].GetHash())
+	}
+	states[0].LinuxKernel.CommandLine = states[1].GetLinuxKernel().GetCommandLine()
+
+	// Drivers of the same count, but with different contents, do not match.
+	for i, state := range states {
+		state.Efi.BootServicesDrivers = []*pb.EfiApp{{UntrustedFilePath: fmt.Sprintf(`\driver%d.efi`, i)}}
+	}
+	_, err = extract.MergeFirmwareLogStates(states...)
+	if !errors.As(err, &mismatch) || len(mismatch.Fields) != 1 || mismatch.Fields[0] != "efi" {
+		t.Errorf("MergeFirmwareLogStates(different drivers): got %v, want efi mismatch", err)
+	}
+}
+
+func TestReplayAndExtractBanks(t *testing.T) {
+	log := Ubuntu2404AmdSevSnp
+	opts := extract.Opts{Loader: extract.GRUB}
+	for _, tc := range []struct {
+		name     string
+		bankOpts BankOpts
+		wantHash pb.HashAlgo
+	}{
+		{"strongest", BankOpts{Strategy: StrongestBank}, pb.HashAlgo_SHA256},
+		{"all", BankOpts{Strategy: AllBanks}, pb.HashAlgo_SHA256},
+		{"specific", BankOpts{Strategy: SpecificBank, Hash: pb.HashAlgo_SHA1}, pb.HashAlgo_SHA1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			state, err := ReplayAndExtractBanks(log.RawLog, log.Banks, tc.bankOpts, opts)
+			if err != nil {
+				t.Fatalf("ReplayAndExtractBanks(): %v", err)
+			}
+			if state.GetHash() != tc.wantHash {
+				t.Errorf("ReplayAndExtractBanks(): got hash %v, want %v", state.GetHash(), tc.wantHash)
+			}
+		})
+	}
+
+	// Authority uses include bank-specific image digests, which must not
+	// cause a mismatch between banks.
+	state, err := ReplayAndExtractBanks(Rhel8GCE.RawLog, Rhel8GCE.Banks, BankOpts{Strategy: AllBanks}, opts)
+	if err != nil {
+		t.Fatalf("ReplayAndExtractBanks(all, RHEL 8): %v", err)
+	}
+	if len(state.GetSecureBoot().GetAuthorityUsages()) == 0 {
+		t.Errorf("ReplayAndExtractBanks(all, RHEL 8): got no authority usages")
+	}
+	// Shim is authorized by db, and GRUB by shim's vendor certificate. The
+	// kernel is also verified by the vendor certificate, but authorities are
+	// only measured on first use.
+	signers := extract.AppSigners(state)
+	var variables []string
+	for _, signer := range signers {
+		for _, authority := range signer.Authorities {
+			variables = append(variables, authority.VariableName)
+		}
+	}
+	if len(signers) != 3 || len(signers[2].Authorities) != 0 || !cmp.Equal(variables, []string{"db", "Shim"}) {
+		t.Errorf("AppSigners(RHEL 8) = got authority variables %v for %d apps, want db for shim and Shim for GRUB", variables, len(signers))
+	}
+
+	// A bad SHA-1 bank is only verified by AllBanks and SpecificBank.
+	badSHA1 := testutil.MakePCRBank(pb.HashAlgo_SHA1, map[uint32][]byte{0: make([]byte, crypto.SHA1.Size())})
+	banks := []register.PCRBank{badSHA1, log.Banks[1]}
+	if _, err := ReplayAndExtractBanks(log.RawLog, banks, BankOpts{Strategy: StrongestBank}, opts); err != nil {
+		t.Errorf("ReplayAndExtractBanks(strongest, bad SHA-1 bank): %v", err)
+	}
+	if _, err := ReplayAndExtractBanks(log.RawLog, banks, BankOpts{Strategy: AllBanks}, opts); err == nil {
+		t.Errorf("ReplayAndExtractBanks(all, bad SHA-1 bank): got nil, want error")
+	}
+	if _, err := ReplayAndExtractBanks(log.RawLog, banks, BankOpts{Strategy: SpecificBank, Hash: pb.HashAlgo_SHA384}, opts); err == nil {
+		t.Errorf("ReplayAndExtractBanks(specific, missing SHA-384 bank): got nil, want error")
+	}
+	sha512 := register.PCRBank{TCGHashAlgo: pb.HashAlgo_SHA512}
+	if _, err := ReplayAndExtractBanks(log.RawLog, []register.PCRBank{sha512}, BankOpts{Strategy: StrongestBank}, opts); err == nil {
+		t.Errorf("ReplayAndExtractBanks(strongest, bank not in log): got nil, want error")
+	}
+}
+
 func TestParseMachineStateReplayFail(t *testing.T) {
 	pcrMap := make(map[uint32][]byte)
 	pcrMap[0] = []byte{0, 0, 0, 0}
@@ -569,10 +757,12 @@ func TestParseMachineStateReplayFail(t *testing.T) {
 func TestEmptyEventlog(t *testing.T) {
 	emptyLog := []byte{}
 	emptyState := &pb.FirmwareLogState{
-		Hash:       pb.HashAlgo_SHA1,
-		Platform:   &pb.PlatformState{Firmware: &pb.PlatformState_ScrtmVersionId{}},
-		SecureBoot: &pb.SecureBootState{},
-		LogType:    pb.LogType_LOG_TYPE_TCG2,
+		Hash:          pb.HashAlgo_SHA1,
+		Platform:      &pb.PlatformState{Firmware: &pb.PlatformState_ScrtmVersionId{}},
+		SecureBoot:    &pb.SecureBootState{},
+		LogType:       pb.LogType_LOG_TYPE_TCG2,
+		Findings:      []*pb.Finding{{Type: pb.FindingType_FINDING_TYPE_WEAK_BANK}},
+		SchemaVersion: extract.SchemaVersion,
 	}
 	emptyBank := register.PCRBank{TCGHashAlgo: pb.HashAlgo_SHA1}
 
@@ -599,13 +789,195 @@ func TestEmptyEventlog(t *testing.T) {
 			if err != nil {
 				t.Errorf("parsing empty eventlog: %v", err)
 			}
-			if diff := cmp.Diff(state, emptyState, protocmp.Transform(), protocmp.IgnoreEmptyMessages()); diff != "" {
+			if diff := cmp.Diff(state, emptyState, protocmp.Transform(), protocmp.IgnoreEmptyMessages(), protocmp.IgnoreFields(&pb.Finding{}, "description")); diff != "" {
 				t.Errorf("unexpected non-empty MachineState:\n%v", diff)
 			}
 		})
 	}
 }
 
+func TestWeakBankFinding(t *testing.T) {
+	for _, bank := range UbuntuAmdSevGCE.Banks {
+		t.Run(bank.TCGHashAlgo.String(), func(t *testing.T) {
+			state, err := ReplayAndExtract(UbuntuAmdSevGCE.RawLog, bank, extract.Opts{Loader: extract.GRUB})
+			if err != nil {
+				t.Fatalf("ReplayAndExtract(): %v", err)
+			}
+			var weak bool
+			for _, finding := range state.GetFindings() {
+				if finding.GetType() == pb.FindingType_FINDING_TYPE_WEAK_BANK {
+					weak = true
+				}
+			}
+			if weak != (bank.TCGHashAlgo == pb.HashAlgo_SHA1) {
+				t.Errorf("got weak bank finding %v for the %v bank", weak, bank.TCGHashAlgo)
+			}
+		})
+	}
+}
+
+func TestQuirksAppliedFinding(t *testing.T) {
+	// An event with no digests or data, as logged by some firmware TPMs.
+	empty := make([]byte, 4+4+4+4)
+	empty[0] = 16
+	withEmpty := append(bytes.Clone(Ubuntu2404AmdSevSnp.RawLog), empty...)
+	bank := Ubuntu2404AmdSevSnp.Banks[1]
+
+	tests := []struct {
+		name        string
+		rawLog      []byte
+		wantFinding bool
+	}{
+		{"clean log", Ubuntu2404AmdSevSnp.RawLog, false},
+		{"empty event", withEmpty, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			state, err := ReplayAndExtract(tc.rawLog, bank, extract.Opts{Loader: extract.GRUB, Quirks: tcg.IntelPTTQuirks})
+			if err != nil {
+				t.Fatalf("ReplayAndExtract(): %v", err)
+			}
+			var found bool
+			for _, finding := range state.GetFindings() {
+				if finding.GetType() == pb.FindingType_FINDING_TYPE_QUIRKS_APPLIED {
+					found = true
+				}
+			}
+			if found != tc.wantFinding {
+				t.Errorf("ReplayAndExtract(): got quirks applied finding %v, want %v", found, tc.wantFinding)
+			}
+			if applied := len(state.GetAppliedQuirks()) > 0; applied != tc.wantFinding {
+				t.Errorf("ReplayAndExtract(): got applied quirks %v, want applied %v", state.GetAppliedQuirks(), tc.wantFinding)
+			}
+		})
+	}
+}
+
+func TestFindingSink(t *testing.T) {
+	// An event with no digests or data, so the parse quirk finding is added
+	// after extraction.
+	empty := make([]byte, 4+4+4+4)
+	empty[0] = 16
+	withEmpty := append(bytes.Clone(Ubuntu2404AmdSevSnp.RawLog), empty...)
+
+	extractors := []struct {
+		name    string
+		extract func(extract.Opts) (*pb.FirmwareLogState, error)
+	}{
+		{"ReplayAndExtract", func(opts extract.Opts) (*pb.FirmwareLogState, error) {
+			return ReplayAndExtract(withEmpty, Ubuntu2404AmdSevSnp.Banks[1], opts)
+		}},
+		{"ReplayAndExtractBanks", func(opts extract.Opts) (*pb.FirmwareLogState, error) {
+			return ReplayAndExtractBanks(withEmpty, Ubuntu2404AmdSevSnp.Banks, BankOpts{Strategy: AllBanks}, opts)
+		}},
+	}
+	for _, tc := range extractors {
+		t.Run(tc.name, func(t *testing.T) {
+			var sunk []*pb.Finding
+			opts := extract.Opts{
+				Loader:      extract.GRUB,
+				Quirks:      tcg.IntelPTTQuirks,
+				FindingSink: func(f *pb.Finding) { sunk = append(sunk, f) },
+			}
+			state, err := tc.extract(opts)
+			if err != nil {
+				t.Fatalf("%s(): %v", tc.name, err)
+			}
+			if len(state.GetFindings()) == 0 {
+				t.Fatalf("%s(): got no findings, want a quirks applied finding", tc.name)
+			}
+			if diff := cmp.Diff(state.GetFindings(), sunk, protocmp.Transform()); diff != "" {
+				t.Errorf("%s(): FindingSink got findings different from FirmwareLogState.Findings (-state +sunk):\n%s", tc.name, diff)
+			}
+		})
+	}
+}
+
+func TestReplayAndExtractParseBuffers(t *testing.T) {
+	bank := Ubuntu2404AmdSevSnp.Banks[1]
+	want, err := ReplayAndExtract(Ubuntu2404AmdSevSnp.RawLog, bank, extract.Opts{Loader: extract.GRUB})
+	if err != nil {
+		t.Fatalf("ReplayAndExtract(): %v", err)
+	}
+
+	buffers := tcg.GetParseBuffers()
+	defer buffers.Release()
+	opts := extract.Opts{Loader: extract.GRUB, ParseOpts: &tcg.ParseOpts{Buffers: buffers}}
+	got, err := ReplayAndExtract(Ubuntu2404AmdSevSnp.RawLog, bank, opts)
+	if err != nil {
+		t.Fatalf("ReplayAndExtract(Buffers): %v", err)
+	}
+	// Reusing the buffers overwrites the memory of the first parse.
+	if _, err := tcg.ParseEventLog(Rhel8GCE.RawLog, tcg.ParseOpts{Buffers: buffers}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("ReplayAndExtract(Buffers) state changed after the buffers were reused (-want +got):\n%s", diff)
+	}
+}
+
+func TestReplayAndExtractParseOpts(t *testing.T) {
+	bank := Ubuntu2404AmdSevSnp.Banks[1]
+	opts := extract.Opts{Loader: extract.GRUB, ParseOpts: &tcg.ParseOpts{MaxEvents: 10}}
+	if _, err := ReplayAndExtract(Ubuntu2404AmdSevSnp.RawLog, bank, opts); err == nil {
+		t.Errorf("ReplayAndExtract(MaxEvents: 10): got nil, want error")
+	}
+	if _, err := ReplayAndExtractBanks(Ubuntu2404AmdSevSnp.RawLog, []register.PCRBank{bank}, BankOpts{}, opts); err == nil {
+		t.Errorf("ReplayAndExtractBanks(MaxEvents: 10): got nil, want error")
+	}
+
+	// Quirks from the extraction options are added to the parse options.
+	empty := make([]byte, 4+4+4+4)
+	empty[0] = 16
+	withEmpty := append(bytes.Clone(Ubuntu2404AmdSevSnp.RawLog), empty...)
+	opts = extract.Opts{Loader: extract.GRUB, Quirks: tcg.QuirkEmptyEvents, ParseOpts: &tcg.ParseOpts{MaxEventDataSize: 1 << 20}}
+	if _, err := ReplayAndExtract(withEmpty, bank, opts); err != nil {
+		t.Errorf("ReplayAndExtract(empty event with QuirkEmptyEvents): %v", err)
+	}
+}
+
+func TestReplayAndExtractRedactedMRs(t *testing.T) {
+	bank := Ubuntu2404AmdSevSnp.Banks[1]
+	redacted, err := tcg.RedactEventLog(Ubuntu2404AmdSevSnp.RawLog, []int{2, 4, 8, 9}, tcg.ParseOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without AllowRedactedMRs, the hidden boot applications and bootloader
+	// cannot pass as a boot without them.
+	opts := extract.Opts{Loader: extract.AutoDetect}
+	if _, err := ReplayAndExtract(redacted, bank, opts); err == nil {
+		t.Errorf("ReplayAndExtract(redacted log): got nil, want error")
+	}
+	if _, err := ReplayAndExtractBanks(redacted, Ubuntu2404AmdSevSnp.Banks, BankOpts{}, opts); err == nil {
+		t.Errorf("ReplayAndExtractBanks(redacted log): got nil, want error")
+	}
+
+	opts.ParseOpts = &tcg.ParseOpts{AllowRedactedMRs: true}
+	state, err := ReplayAndExtract(redacted, bank, opts)
+	if err != nil {
+		t.Fatalf("ReplayAndExtract(redacted log, AllowRedactedMRs): %v", err)
+	}
+	if diff := cmp.Diff([]uint32{2, 4, 8, 9}, state.GetRedactedMrs()); diff != "" {
+		t.Errorf("ReplayAndExtract(redacted log, AllowRedactedMRs): redacted MRs mismatch (-want +got):\n%s", diff)
+	}
+	var found bool
+	for _, finding := range state.GetFindings() {
+		found = found || finding.GetType() == pb.FindingType_FINDING_TYPE_REDACTED_MRS
+	}
+	if !found {
+		t.Errorf("ReplayAndExtract(redacted log, AllowRedactedMRs): got findings %v, want a redacted registers finding", state.GetFindings())
+	}
+
+	opts.Strict = true
+	var redactedErr extract.RedactedMRsError
+	if _, err := ReplayAndExtract(redacted, bank, opts); !errors.As(err, &redactedErr) {
+		t.Errorf("ReplayAndExtract(redacted log, Strict): got %v, want a RedactedMRsError", err)
+	}
+	if _, err := ReplayAndExtractBanks(redacted, Ubuntu2404AmdSevSnp.Banks, BankOpts{}, opts); !errors.As(err, &redactedErr) {
+		t.Errorf("ReplayAndExtractBanks(redacted log, Strict): got %v, want a RedactedMRsError", err)
+	}
+}
+
 func TestParseSecureBootState(t *testing.T) {
 	for _, bank := range UbuntuAmdSevGCE.Banks {
 		msState, err := ReplayAndExtract(UbuntuAmdSevGCE.RawLog, bank, extract.Opts{})
@@ -0,0 +1,120 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package eventlog
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/google/go-eventlog/extract"
+)
+
+// BootPolicy is a small policy for VerifyBoot, covering the common checks of
+// a booted machine. The zero value accepts any boot whose event log verifies
+// and that had no UEFI debugger enabled.
+// For finer-grained checks, evaluate the FirmwareLogState returned by Extract,
+// e.g., with the policy package.
+type BootPolicy struct {
+	// RequireSecureBoot requires Secure Boot to be enabled.
+	RequireSecureBoot bool
+	// AllowedKernels, if set, are the digests of the allowed kernel images,
+	// using the hash algorithm of the bank. Only the kernel booted by the
+	// detected bootloader before ExitBootServices() is checked: the kernel
+	// file GRUB measured for its linux command, the kernel systemd-boot
+	// started, or the .linux section of the unified kernel image that was
+	// booted. Other files read by GRUB, e.g., a kernel that was read but not
+	// booted, and measurements made by the OS do not match.
+	AllowedKernels [][]byte
+	// MinFirmwareVersion, if set, is the minimum GCE virtual firmware
+	// version. Boots without a GCE firmware version are rejected.
+	MinFirmwareVersion uint32
+}
+
+// BootResult is the outcome of VerifyBoot.
+type BootResult struct {
+	// Passed is true if the boot meets the policy.
+	Passed bool
+	// Failures describes each policy check that the boot failed.
+	Failures []string
+	// State is the extracted state of the boot.
+	State *FirmwareLogState
+}
+
+// VerifyBoot verifies a PC Client (TPM) event log against the register values
+// in bank, extracts its FirmwareLogState with the bootloader detected from the
+// log, and checks it against the policy. It returns an error if the event log
+// cannot be parsed, verified, or fully extracted, in which case the boot
+// cannot be trusted. A boot that does not meet the policy is not an error:
+// the result describes the failed checks.
+//
+// It is the caller's responsibility to ensure the register values can be
+// trusted, e.g., by verifying a quote over them.
+func VerifyBoot(rawEventLog []byte, bank Bank, policy BootPolicy) (*BootResult, error) {
+	log, err := ParsePCClient(rawEventLog)
+	if err != nil {
+		return nil, err
+	}
+	state, err := Extract(log, bank, ExtractOpts{Loader: AutoDetectLoader})
+	if err != nil {
+		return nil, err
+	}
+	mrs, err := log.mrs(bank)
+	if err != nil {
+		return nil, err
+	}
+	events, err := log.parsed.Verify(mrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay event log: %v", err)
+	}
+	loader := extract.DetectBootloader(events, extract.TPMRegisterConfig)
+	kernels := extract.BootedKernelDigests(bank.Hash, events, extract.TPMRegisterConfig, loader)
+	result := &BootResult{State: state, Failures: policy.check(state, kernels)}
+	result.Passed = len(result.Failures) == 0
+	return result, nil
+}
+
+// check checks the state against the policy. kernels are the digests of the
+// booted kernel.
+func (p BootPolicy) check(state *FirmwareLogState, kernels [][]byte) []string {
+	var failures []string
+	if p.RequireSecureBoot && !state.GetSecureBoot().GetEnabled() {
+		failures = append(failures, "Secure Boot is disabled")
+	}
+	// A UEFI debugger can modify the firmware at runtime, so no policy
+	// accepts it.
+	if state.GetSecureBoot().GetDebugModeEnabled() {
+		failures = append(failures, "a UEFI debugger was enabled during boot")
+	}
+	if len(p.AllowedKernels) > 0 && !p.allowedKernel(kernels) {
+		failures = append(failures, "no allowed kernel was booted")
+	}
+	if p.MinFirmwareVersion > 0 {
+		if version := state.GetPlatform().GetGceVersion(); version < p.MinFirmwareVersion {
+			failures = append(failures, fmt.Sprintf("firmware version %d is below the minimum %d", version, p.MinFirmwareVersion))
+		}
+	}
+	return failures
+}
+
+func (p BootPolicy) allowedKernel(kernels [][]byte) bool {
+	for _, digest := range kernels {
+		for _, allowed := range p.AllowedKernels {
+			if len(digest) > 0 && bytes.Equal(digest, allowed) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,278 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package watchdog monitors event logs on long-running systems. A Watchdog
+// periodically re-reads event logs, e.g., the CCEL, the IMA runtime
+// measurements, or a CEL file written by an agent, and alerts if the bytes it
+// has already seen were rewritten or removed. Event logs are append-only, so
+// a rewrite indicates in-guest tampering between attestations.
+//
+// A Watchdog only checks that the logs are append-only. The appended events
+// still need to be replayed against the measurement registers.
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/go-eventlog/collect"
+)
+
+// Format determines which bytes of an event log must be append-only.
+type Format int
+
+// Supported formats.
+const (
+	// Raw logs only grow by appending bytes, e.g., CEL files and the IMA
+	// binary runtime measurements.
+	Raw Format = iota
+	// TCG logs, i.e., PC Client event logs and CCELs, may have trailing
+	// padding of 0x00 or 0xFF bytes, which is overwritten by appended events.
+	// The padding is not part of the append-only prefix.
+	TCG
+)
+
+// IMAPath is the IMA binary runtime measurements file on Linux.
+const IMAPath = "/sys/kernel/security/ima/binary_runtime_measurements"
+
+// Source is an event log monitored by a Watchdog.
+type Source struct {
+	// Name identifies the source in alerts.
+	Name string
+	// Read returns the current contents of the event log.
+	Read func() ([]byte, error)
+	// Format determines which bytes of the event log must be append-only.
+	Format Format
+}
+
+// FileSource returns a Source reading the event log at path.
+func FileSource(name, path string, format Format) Source {
+	return Source{
+		Name:   name,
+		Read:   func() ([]byte, error) { return os.ReadFile(path) },
+		Format: format,
+	}
+}
+
+// IMASource returns a Source reading the IMA binary runtime measurements.
+func IMASource() Source {
+	return FileSource("IMA", IMAPath, Raw)
+}
+
+// CCELSource returns a Source reading the Confidential Computing event log
+// with collect.CCEL.
+func CCELSource() Source {
+	return Source{
+		Name: "CCEL",
+		Read: func() ([]byte, error) {
+			_, log, err := collect.CCEL()
+			return log, err
+		},
+		Format: TCG,
+	}
+}
+
+// AlertKind is the kind of an Alert.
+type AlertKind int
+
+// Alert kinds.
+const (
+	// Rewritten means bytes of the previously seen prefix changed.
+	Rewritten AlertKind = iota
+	// Truncated means the event log is shorter than the previously seen
+	// prefix, which is otherwise unchanged.
+	Truncated
+	// ReadFailed means the event log could not be read, e.g., because it
+	// was removed.
+	ReadFailed
+)
+
+func (k AlertKind) String() string {
+	switch k {
+	case Rewritten:
+		return "rewritten"
+	case Truncated:
+		return "truncated"
+	case ReadFailed:
+		return "read failed"
+	}
+	return fmt.Sprintf("AlertKind(%d)", int(k))
+}
+
+// Alert reports a violation of the append-only property of a Source.
+type Alert struct {
+	// Source is the name of the Source.
+	Source string
+	Kind   AlertKind
+	// Time is when the violation was detected.
+	Time time.Time
+	// Offset is the offset of the first changed byte for Rewritten, and the
+	// new length of the event log for Truncated.
+	Offset int
+	// SeenLength is the length of the previously seen prefix.
+	SeenLength int
+	// Err is the read error for ReadFailed.
+	Err error
+}
+
+func (a Alert) String() string {
+	switch a.Kind {
+	case Rewritten:
+		return fmt.Sprintf("%s: event log rewritten at offset %d of %d previously seen bytes", a.Source, a.Offset, a.SeenLength)
+	case Truncated:
+		return fmt.Sprintf("%s: event log truncated to %d of %d previously seen bytes", a.Source, a.Offset, a.SeenLength)
+	case ReadFailed:
+		return fmt.Sprintf("%s: failed to read event log: %v", a.Source, a.Err)
+	}
+	return fmt.Sprintf("%s: %v", a.Source, a.Kind)
+}
+
+// Opts gives options for creating a Watchdog.
+type Opts struct {
+	Sources []Source
+	// Interval is the time between checks in Run. Defaults to one minute.
+	Interval time.Duration
+	// Alert, if set, is called by Run with each alert.
+	Alert func(Alert)
+	// Now returns the time recorded in alerts. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// Watchdog checks that event logs are append-only.
+type Watchdog struct {
+	opts Opts
+
+	mu sync.Mutex
+	// seen holds the append-only prefix of each source, or nil if the source
+	// has not been read yet.
+	seen [][]byte
+}
+
+// New returns a Watchdog for the sources. The sources are first read by Check
+// or Run, so the Watchdog should be created at a time the logs are trusted,
+// e.g., right after they were attested.
+func New(opts Opts) (*Watchdog, error) {
+	if len(opts.Sources) == 0 {
+		return nil, errors.New("no event log sources given")
+	}
+	for i, src := range opts.Sources {
+		if src.Name == "" {
+			return nil, fmt.Errorf("source %d has no name", i)
+		}
+		if src.Read == nil {
+			return nil, fmt.Errorf("source %v has no Read function", src.Name)
+		}
+		if src.Format != Raw && src.Format != TCG {
+			return nil, fmt.Errorf("source %v has unknown format %d", src.Name, src.Format)
+		}
+	}
+	if opts.Interval < 0 {
+		return nil, fmt.Errorf("negative interval %v", opts.Interval)
+	}
+	if opts.Interval == 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Watchdog{opts: opts, seen: make([][]byte, len(opts.Sources))}, nil
+}
+
+// Check reads each source once, and returns an alert for each source whose
+// previously seen prefix changed or that could not be read. Only an
+// append-only prefix becomes the one checked next: after a rewrite or
+// truncation, the source is still checked against the last trusted prefix,
+// so the violation is reported by every Check until the log is restored.
+func (w *Watchdog) Check() []Alert {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var alerts []Alert
+	for i, src := range w.opts.Sources {
+		data, err := src.Read()
+		if err != nil {
+			alerts = append(alerts, Alert{Source: src.Name, Kind: ReadFailed, Time: w.opts.Now(), SeenLength: len(w.seen[i]), Err: err})
+			continue
+		}
+		prefix := appendOnlyPrefix(data, src.Format)
+		if alert, ok := compare(w.seen[i], prefix); ok {
+			alert.Source = src.Name
+			alert.Time = w.opts.Now()
+			alerts = append(alerts, alert)
+			continue
+		}
+		w.seen[i] = bytes.Clone(prefix)
+	}
+	return alerts
+}
+
+// Run checks the sources right away and then every Interval, until ctx is
+// done, and passes each alert to Opts.Alert. It returns ctx.Err().
+func (w *Watchdog) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		for _, alert := range w.Check() {
+			if w.opts.Alert != nil {
+				w.opts.Alert(alert)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// appendOnlyPrefix returns the bytes of the event log that must not change
+// when events are appended.
+func appendOnlyPrefix(data []byte, format Format) []byte {
+	if format != TCG || len(data) == 0 {
+		return data
+	}
+	// Trailing fill bytes may also end the last event, in which case the
+	// prefix is shorter than the events, which is still append-only.
+	fill := data[len(data)-1]
+	if fill != 0x00 && fill != 0xff {
+		return data
+	}
+	end := len(data)
+	for end > 0 && data[end-1] == fill {
+		end--
+	}
+	return data[:end]
+}
+
+// compare returns an alert, without a source or time, if prefix does not
+// extend seen.
+func compare(seen, prefix []byte) (Alert, bool) {
+	n := len(seen)
+	if len(prefix) < n {
+		n = len(prefix)
+	}
+	for i := 0; i < n; i++ {
+		if seen[i] != prefix[i] {
+			return Alert{Kind: Rewritten, Offset: i, SeenLength: len(seen)}, true
+		}
+	}
+	if len(prefix) < len(seen) {
+		return Alert{Kind: Truncated, Offset: len(prefix), SeenLength: len(seen)}, true
+	}
+	return Alert{}, false
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-eventlog/eventlogtest"
+)
+
+// fakeLog is an event log whose contents are set by the test.
+type fakeLog struct {
+	mu   sync.Mutex
+	data []byte
+	err  error
+}
+
+func (l *fakeLog) set(data []byte, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.data, l.err = bytes.Clone(data), err
+}
+
+func (l *fakeLog) read() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return bytes.Clone(l.data), l.err
+}
+
+func TestCheck(t *testing.T) {
+	clock := eventlogtest.NewFakeClock(time.Unix(1700000000, 0))
+	padding := bytes.Repeat([]byte{0xff}, 16)
+	for _, tc := range []struct {
+		name      string
+		format    Format
+		first     []byte
+		next      []byte
+		readErr   error
+		wantAlert *Alert
+	}{
+		{name: "unchanged", format: Raw, first: []byte("abcd"), next: []byte("abcd")},
+		{name: "appended", format: Raw, first: []byte("abcd"), next: []byte("abcdef")},
+		{name: "rewritten", format: Raw, first: []byte("abcd"), next: []byte("abXdef"), wantAlert: &Alert{Kind: Rewritten, Offset: 2, SeenLength: 4}},
+		{name: "truncated", format: Raw, first: []byte("abcd"), next: []byte("ab"), wantAlert: &Alert{Kind: Truncated, Offset: 2, SeenLength: 4}},
+		{name: "read failed", format: Raw, first: []byte("abcd"), readErr: os.ErrNotExist, wantAlert: &Alert{Kind: ReadFailed, SeenLength: 4, Err: os.ErrNotExist}},
+		{name: "padding overwritten", format: TCG, first: append([]byte("abcd"), padding...), next: append([]byte("abcdef"), padding[2:]...)},
+		{name: "padding overwritten in raw log", format: Raw, first: append([]byte("abcd"), padding...), next: append([]byte("abcdef"), padding[2:]...), wantAlert: &Alert{Kind: Rewritten, Offset: 4, SeenLength: 20}},
+		{name: "padding rewritten", format: TCG, first: append([]byte("abcd"), padding...), next: append([]byte("abXd"), padding...), wantAlert: &Alert{Kind: Rewritten, Offset: 2, SeenLength: 4}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			log := &fakeLog{}
+			w, err := New(Opts{Sources: []Source{{Name: "log", Read: log.read, Format: tc.format}}, Now: clock.Now})
+			if err != nil {
+				t.Fatalf("New(): %v", err)
+			}
+			log.set(tc.first, nil)
+			if alerts := w.Check(); len(alerts) != 0 {
+				t.Fatalf("Check(first read): got alerts %v, want none", alerts)
+			}
+			log.set(tc.next, tc.readErr)
+			alerts := w.Check()
+			if tc.wantAlert == nil {
+				if len(alerts) != 0 {
+					t.Errorf("Check(): got alerts %v, want none", alerts)
+				}
+				return
+			}
+			if len(alerts) != 1 {
+				t.Fatalf("Check(): got alerts %v, want one", alerts)
+			}
+			want := *tc.wantAlert
+			want.Source = "log"
+			want.Time = clock.Now()
+			got := alerts[0]
+			if !errors.Is(got.Err, want.Err) {
+				t.Errorf("Check(): got error %v, want %v", got.Err, want.Err)
+			}
+			got.Err, want.Err = nil, nil
+			if got != want {
+				t.Errorf("Check() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCheckKeepsBaselineAfterViolation(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		violated []byte
+		next     []byte
+		wantKind AlertKind
+	}{
+		{"appended after rewrite", []byte("abXd"), []byte("abXdef"), Rewritten},
+		{"appended after truncation", []byte("ab"), []byte("abXYZ"), Rewritten},
+		{"truncated twice", []byte("abc"), []byte("ab"), Truncated},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			log := &fakeLog{}
+			w, err := New(Opts{Sources: []Source{{Name: "log", Read: log.read}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			log.set([]byte("abcd"), nil)
+			w.Check()
+			log.set(tc.violated, nil)
+			if alerts := w.Check(); len(alerts) != 1 {
+				t.Fatalf("Check(violated): got alerts %v, want one", alerts)
+			}
+			log.set(tc.next, nil)
+			alerts := w.Check()
+			if len(alerts) != 1 || alerts[0].Kind != tc.wantKind || alerts[0].SeenLength != 4 {
+				t.Errorf("Check(%s): got alerts %v, want a %v alert against the 4 trusted bytes", tc.name, alerts, tc.wantKind)
+			}
+			// Restoring and appending to the trusted prefix is append-only.
+			log.set([]byte("abcdef"), nil)
+			if alerts := w.Check(); len(alerts) != 0 {
+				t.Errorf("Check(restored): got alerts %v, want none", alerts)
+			}
+		})
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cel.bin")
+	if err := os.WriteFile(path, []byte("abcd"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(Opts{Sources: []Source{FileSource("CEL", path, Raw)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerts := w.Check(); len(alerts) != 0 {
+		t.Fatalf("Check(): got alerts %v, want none", alerts)
+	}
+	if err := os.WriteFile(path, []byte("abXd"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	alerts := w.Check()
+	if len(alerts) != 1 || alerts[0].Kind != Rewritten || alerts[0].Source != "CEL" {
+		t.Errorf("Check(): got alerts %v, want a CEL rewrite", alerts)
+	}
+}
+
+func TestRun(t *testing.T) {
+	log := &fakeLog{}
+	log.set([]byte("abcd"), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	alerts := make(chan Alert, 1)
+	w, err := New(Opts{
+		Sources:  []Source{{Name: "log", Read: log.read}},
+		Interval: time.Millisecond,
+		Alert: func(alert Alert) {
+			select {
+			case alerts <- alert:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	// Wait for the first check before rewriting the log.
+	for {
+		w.mu.Lock()
+		seen := w.seen[0] != nil
+		w.mu.Unlock()
+		if seen {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	log.set([]byte("Xbcd"), nil)
+	select {
+	case alert := <-alerts:
+		if alert.Kind != Rewritten || alert.Offset != 0 {
+			t.Errorf("Run(): got alert %v, want a rewrite at offset 0", alert)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Run(): got no alert")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestNewFails(t *testing.T) {
+	read := func() ([]byte, error) { return nil, nil }
+	for _, tc := range []struct {
+		name string
+		opts Opts
+	}{
+		{"no sources", Opts{}},
+		{"no name", Opts{Sources: []Source{{Read: read}}}},
+		{"no read", Opts{Sources: []Source{{Name: "log"}}}},
+		{"unknown format", Opts{Sources: []Source{{Name: "log", Read: read, Format: 2}}}},
+		{"negative interval", Opts{Sources: []Source{{Name: "log", Read: read}}, Interval: -time.Second}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New(tc.opts); err == nil {
+				t.Errorf("New(): got nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package wellknown
+
+import (
+	"crypto"
+
+	"github.com/google/go-eventlog/tcg"
+)
+
+// Valid event data of EV_SEPARATOR events. From the PC Client Firmware Profile
+// spec, on the separator event: the event field MUST contain the hex value
+// 00000000h or FFFFFFFFh.
+var (
+	// SeparatorData marks the end of the pre-OS measurements of a register.
+	SeparatorData = []byte{0, 0, 0, 0}
+	// ErrorSeparatorData is measured instead of SeparatorData if a pre-OS
+	// error occurred.
+	ErrorSeparatorData = []byte{0xff, 0xff, 0xff, 0xff}
+)
+
+// SeparatorDigests returns the digests measured for the valid separator event
+// data, SeparatorData and ErrorSeparatorData, in that order. Verifiers can
+// compare them against the replayed digests of separator events.
+func SeparatorDigests(hash crypto.Hash) [][]byte {
+	digests := make([][]byte, 0, 2)
+	for _, data := range [][]byte{SeparatorData, ErrorSeparatorData} {
+		hasher := hash.New()
+		hasher.Write(data)
+		digests = append(digests, hasher.Sum(nil))
+	}
+	return digests
+}
+
+// ActionDigest returns the digest measured for an EV_EFI_ACTION event with
+// the action string, e.g., tcg.ExitBootServicesInvocation. See
+// tcg.EFIActionDigest.
+func ActionDigest(hash crypto.Hash, action string) []byte {
+	return tcg.EFIActionDigest(hash, action)
+}
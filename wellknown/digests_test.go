@@ -0,0 +1,47 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package wellknown
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/google/go-eventlog/tcg"
+)
+
+func TestSeparatorDigests(t *testing.T) {
+	zero := sha256.Sum256([]byte{0, 0, 0, 0})
+	ff := sha256.Sum256([]byte{0xff, 0xff, 0xff, 0xff})
+	digests := SeparatorDigests(crypto.SHA256)
+	if len(digests) != 2 || !bytes.Equal(digests[0], zero[:]) || !bytes.Equal(digests[1], ff[:]) {
+		t.Errorf("SeparatorDigests(SHA256) = %x, want [%x %x]", digests, zero, ff)
+	}
+	if got := SeparatorDigests(crypto.SHA384); len(got[0]) != crypto.SHA384.Size() {
+		t.Errorf("SeparatorDigests(SHA384) = %x, want SHA-384 digests", got)
+	}
+}
+
+func TestActionDigest(t *testing.T) {
+	want := sha256.Sum256([]byte(tcg.ExitBootServicesInvocation))
+	if got := ActionDigest(crypto.SHA256, tcg.ExitBootServicesInvocation); !bytes.Equal(got, want[:]) {
+		t.Errorf("ActionDigest(%q) = %x, want %x", tcg.ExitBootServicesInvocation, got, want)
+	}
+	custom := sha256.Sum256([]byte("Custom Action"))
+	if got := ActionDigest(crypto.SHA256, "Custom Action"); !bytes.Equal(got, custom[:]) {
+		t.Errorf("ActionDigest(custom action) = %x, want %x", got, custom)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package wellknown
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxAIADepth bounds the number of issuers FetchGceIntermediates
+// follows.
+const defaultMaxAIADepth = 4
+
+// maxIssuerSize bounds the size of a fetched issuer certificate.
+const maxIssuerSize = 64 << 10
+
+// defaultFetchTimeout bounds each request of an HTTPIssuerFetcher without a
+// Client.
+const defaultFetchTimeout = 10 * time.Second
+
+// GceIssuerHosts are the hosts, with their subdomains, that an
+// HTTPIssuerFetcher without AllowedHosts fetches issuer certificates from.
+var GceIssuerHosts = []string{"pki.goog"}
+
+// An IssuerFetcher fetches the DER-encoded certificate at an Authority
+// Information Access (AIA) CA issuers URL.
+type IssuerFetcher interface {
+	FetchIssuer(ctx context.Context, url string) ([]byte, error)
+}
+
+// HTTPIssuerFetcher fetches issuer certificates over HTTPS from allowed hosts.
+// The issuer URLs come from certificates that are not verified yet, so they
+// must not be fetched from arbitrary hosts.
+type HTTPIssuerFetcher struct {
+	// Client sends the requests. If nil, a client with a 10 second timeout is
+	// used. Redirects are only followed to allowed hosts.
+	Client *http.Client
+	// AllowedHosts are the hosts, with their subdomains, that issuer
+	// certificates may be fetched from. If nil, GceIssuerHosts is used.
+	AllowedHosts []string
+}
+
+// FetchIssuer implements IssuerFetcher.
+func (f HTTPIssuerFetcher) FetchIssuer(ctx context.Context, issuerURL string) ([]byte, error) {
+	u, err := url.Parse(issuerURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkURL(u); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuerURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := http.Client{Timeout: defaultFetchTimeout}
+	if f.Client != nil {
+		client = *f.Client
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return f.checkURL(req.URL)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got HTTP status %s", resp.Status)
+	}
+	der, err := io.ReadAll(io.LimitReader(resp.Body, maxIssuerSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(der) > maxIssuerSize {
+		return nil, fmt.Errorf("issuer certificate is larger than %d bytes", maxIssuerSize)
+	}
+	return der, nil
+}
+
+// checkURL returns an error if u is not an HTTPS URL of an allowed host.
+func (f HTTPIssuerFetcher) checkURL(u *url.URL) error {
+	if u.Scheme != "https" {
+		return fmt.Errorf("unsupported issuer URL scheme %q, want https", u.Scheme)
+	}
+	allowed := f.AllowedHosts
+	if allowed == nil {
+		allowed = GceIssuerHosts
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range allowed {
+		h = strings.ToLower(h)
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return nil
+		}
+	}
+	return fmt.Errorf("issuer URL host %q is not allowed", host)
+}
+
+// An IssuerCache caches fetched issuer certificates by URL. Implementations
+// must be safe for concurrent use.
+type IssuerCache interface {
+	Get(url string) (*x509.Certificate, bool)
+	Put(url string, cert *x509.Certificate)
+}
+
+// MemoryIssuerCache is an in-memory IssuerCache. The zero value is ready to
+// use.
+type MemoryIssuerCache struct {
+	certs sync.Map
+}
+
+// Get implements IssuerCache.
+func (c *MemoryIssuerCache) Get(url string) (*x509.Certificate, bool) {
+	cert, ok := c.certs.Load(url)
+	if !ok {
+		return nil, false
+	}
+	return cert.(*x509.Certificate), true
+}
+
+// Put implements IssuerCache.
+func (c *MemoryIssuerCache) Put(url string, cert *x509.Certificate) {
+	c.certs.Store(url, cert)
+}
+
+// AIAOpts configures how missing intermediates are fetched.
+type AIAOpts struct {
+	// Fetcher fetches issuer certificates, e.g., an HTTPIssuerFetcher. It
+	// must be set for intermediates to be fetched: if nil, only certificates
+	// issued by GceEKRoots or GceEKIntermediates are accepted.
+	Fetcher IssuerFetcher
+	// Cache, if set, is checked before fetching and stores fetched issuers.
+	Cache IssuerCache
+	// MaxDepth is the maximum number of issuers to fetch. If 0, 4 is used.
+	MaxDepth int
+	// Now returns the time at which the certificate, the fetched
+	// intermediates, and the GCE EK root must all be valid. It only affects
+	// verification, not which issuers are fetched or cached. If nil,
+	// time.Now is used.
+	Now func() time.Time
+}
+
+// FetchGceIntermediates follows the AIA CA issuers URLs of cert, and of each
+// fetched issuer, until reaching a certificate issued by one of GceEKRoots or
+// GceEKIntermediates. It returns the fetched intermediates, leaf side first.
+// Each fetched certificate must have signed the certificate before it, but
+// the chain is not otherwise verified; use VerifyGceKeyCertificate or
+// VerifyGceKeyCertificateWithAIA for that.
+func FetchGceIntermediates(ctx context.Context, cert *x509.Certificate, opts AIAOpts) ([]*x509.Certificate, error) {
+	maxDepth := opts.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = defaultMaxAIADepth
+	}
+	known := append(append([]*x509.Certificate{}, GceEKRoots...), GceEKIntermediates...)
+
+	var fetched []*x509.Certificate
+	for current := cert; !issuedByAny(current, known); {
+		if len(fetched) == maxDepth {
+			return nil, fmt.Errorf("no known GCE EK issuer within %d fetched issuers", maxDepth)
+		}
+		if opts.Fetcher == nil {
+			return nil, fmt.Errorf("certificate %q is not issued by a known GCE EK issuer, and no issuer fetcher is set", current.Subject)
+		}
+		if len(current.IssuingCertificateURL) == 0 {
+			return nil, fmt.Errorf("certificate %q has no issuer URL", current.Subject)
+		}
+		issuer, err := fetchIssuer(ctx, current, opts.Fetcher, opts.Cache)
+		if err != nil {
+			return nil, err
+		}
+		fetched = append(fetched, issuer)
+		current = issuer
+	}
+	return fetched, nil
+}
+
+// VerifyGceKeyCertificateWithAIA verifies a GCE EK or AK certificate like
+// VerifyGceKeyCertificate, after fetching the intermediates missing from
+// GceEKIntermediates with FetchGceIntermediates.
+func VerifyGceKeyCertificateWithAIA(ctx context.Context, cert *x509.Certificate, opts AIAOpts) (*GceKeyIdentity, error) {
+	if len(GceEKRoots) == 0 {
+		return nil, errors.New("no GCE EK roots configured")
+	}
+	intermediates, err := FetchGceIntermediates(ctx, cert, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GCE EK intermediates: %v", err)
+	}
+	var now time.Time
+	if opts.Now != nil {
+		now = opts.Now()
+	}
+	return verifyGceKeyCertificate(cert, now, intermediates)
+}
+
+func issuedByAny(cert *x509.Certificate, issuers []*x509.Certificate) bool {
+	for _, issuer := range issuers {
+		if cert.CheckSignatureFrom(issuer) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchIssuer returns the first issuer at the AIA URLs of cert that signed it.
+func fetchIssuer(ctx context.Context, cert *x509.Certificate, fetcher IssuerFetcher, cache IssuerCache) (*x509.Certificate, error) {
+	var errs []error
+	for _, issuerURL := range cert.IssuingCertificateURL {
+		if cache != nil {
+			if issuer, ok := cache.Get(issuerURL); ok && cert.CheckSignatureFrom(issuer) == nil {
+				return issuer, nil
+			}
+		}
+		der, err := fetcher.FetchIssuer(ctx, issuerURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fetching %s: %v", issuerURL, err))
+			continue
+		}
+		issuer, err := x509.ParseCertificate(der)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parsing %s: %v", issuerURL, err))
+			continue
+		}
+		if err := cert.CheckSignatureFrom(issuer); err != nil {
+			errs = append(errs, fmt.Errorf("%s did not issue %q: %v", issuerURL, cert.Subject, err))
+			continue
+		}
+		if cache != nil {
+			cache.Put(issuerURL, issuer)
+		}
+		return issuer, nil
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package wellknown
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-eventlog/eventlogtest"
+)
+
+// withIssuerURL returns a copy of cert, reissued by parent with an AIA CA
+// issuers URL.
+func withIssuerURL(t *testing.T, cert *x509.Certificate, parent *testCA, issuerURL string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := *cert
+	template.ExtraExtensions = cert.Extensions
+	template.IssuingCertificateURL = []string{issuerURL}
+	return signCert(t, &template, key, parent)
+}
+
+func TestVerifyGceKeyCertificateWithAIA(t *testing.T) {
+	var requests atomic.Int32
+	certs := make(map[string][]byte)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		der, ok := certs[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(der)
+	}))
+	defer server.Close()
+
+	root := newCert(t, "EK root", nil)
+	intermediate := withIssuerURL(t, newCert(t, "EK intermediate", root).cert, root, server.URL+"/root.crt")
+	leaf := newCert(t, "EK", intermediate, tpmSAN(t, GceTPMManufacturer), instanceExtension(t))
+	leafWithAIA := withIssuerURL(t, leaf.cert, intermediate, server.URL+"/intermediate.crt").cert
+	certs["/intermediate.crt"] = intermediate.cert.Raw
+	missing := withIssuerURL(t, leaf.cert, intermediate, server.URL+"/missing.crt").cert
+
+	defer func(roots []*x509.Certificate) { GceEKRoots = roots }(GceEKRoots)
+	GceEKRoots = []*x509.Certificate{root.cert}
+
+	ctx := context.Background()
+	cache := &MemoryIssuerCache{}
+	fetcher := HTTPIssuerFetcher{Client: server.Client(), AllowedHosts: []string{"127.0.0.1"}}
+	opts := AIAOpts{Fetcher: fetcher, Cache: cache}
+	for i := 0; i < 2; i++ {
+		identity, err := VerifyGceKeyCertificateWithAIA(ctx, leafWithAIA, opts)
+		if err != nil {
+			t.Fatalf("VerifyGceKeyCertificateWithAIA(): %v", err)
+		}
+		if len(identity.Chain) != 3 || !identity.Chain[1].Equal(intermediate.cert) {
+			t.Errorf("VerifyGceKeyCertificateWithAIA() returned a chain of %d certificates, want leaf, fetched intermediate, and root", len(identity.Chain))
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("VerifyGceKeyCertificateWithAIA() twice with a cache sent %d requests, want 1", got)
+	}
+
+	// The certificates are valid for an hour.
+	clock := eventlogtest.NewFakeClock(time.Now())
+	expiredOpts := opts
+	expiredOpts.Now = clock.Now
+	if _, err := VerifyGceKeyCertificateWithAIA(ctx, leafWithAIA, expiredOpts); err != nil {
+		t.Errorf("VerifyGceKeyCertificateWithAIA(now): %v", err)
+	}
+	clock.Advance(2 * time.Hour)
+	if _, err := VerifyGceKeyCertificateWithAIA(ctx, leafWithAIA, expiredOpts); err == nil {
+		t.Errorf("VerifyGceKeyCertificateWithAIA(expired): got nil, want error")
+	}
+
+	if _, err := VerifyGceKeyCertificateWithAIA(ctx, missing, opts); err == nil {
+		t.Errorf("VerifyGceKeyCertificateWithAIA(missing issuer): got nil, want error")
+	}
+	if _, err := VerifyGceKeyCertificateWithAIA(ctx, leaf.cert, opts); err == nil {
+		t.Errorf("VerifyGceKeyCertificateWithAIA(no issuer URL): got nil, want error")
+	}
+
+	// An issuer URL serving a certificate that did not sign the leaf is
+	// rejected.
+	certs["/intermediate.crt"] = newCert(t, "EK intermediate", root).cert.Raw
+	if _, err := VerifyGceKeyCertificateWithAIA(ctx, leafWithAIA, AIAOpts{Fetcher: fetcher}); err == nil {
+		t.Errorf("VerifyGceKeyCertificateWithAIA(wrong issuer): got nil, want error")
+	}
+	certs["/intermediate.crt"] = intermediate.cert.Raw
+
+	// Fetching is opt-in.
+	if _, err := VerifyGceKeyCertificateWithAIA(ctx, leafWithAIA, AIAOpts{}); err == nil {
+		t.Errorf("VerifyGceKeyCertificateWithAIA(no fetcher): got nil, want error")
+	}
+	// Without AllowedHosts, only GCE issuer hosts are fetched from.
+	requests.Store(0)
+	if _, err := VerifyGceKeyCertificateWithAIA(ctx, leafWithAIA, AIAOpts{Fetcher: HTTPIssuerFetcher{Client: server.Client()}}); err == nil {
+		t.Errorf("VerifyGceKeyCertificateWithAIA(host not allowed): got nil, want error")
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("VerifyGceKeyCertificateWithAIA(host not allowed) sent %d requests, want 0", got)
+	}
+}
+
+func TestHTTPIssuerFetcherRejectsURL(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Redirect(w, r, "https://169.254.169.254/computeMetadata/v1/", http.StatusFound)
+	}))
+	defer server.Close()
+	fetcher := HTTPIssuerFetcher{Client: server.Client(), AllowedHosts: []string{"127.0.0.1"}}
+
+	for _, tc := range []struct {
+		name string
+		url  string
+	}{
+		{"HTTP", "http://pki.goog/cloud_integrity/tpm_ek_root_1.crt"},
+		{"File", "file:///etc/passwd"},
+		{"HostNotAllowed", "https://metadata.google.internal/computeMetadata/v1/"},
+		{"SuffixNotSubdomain", "https://evil127.0.0.1.example/"},
+		{"RedirectToHostNotAllowed", server.URL + "/redirect.crt"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := fetcher.FetchIssuer(context.Background(), tc.url); err == nil {
+				t.Errorf("FetchIssuer(%q): got nil, want error", tc.url)
+			}
+		})
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("FetchIssuer() sent %d requests, want only the redirecting one", got)
+	}
+}
+
+func TestHTTPIssuerFetcherAllowedHosts(t *testing.T) {
+	for _, tc := range []struct {
+		host string
+		want bool
+	}{
+		{"pki.goog", true},
+		{"certs.pki.goog", true},
+		{"PKI.GOOG", true},
+		{"notpki.goog", false},
+		{"pki.goog.example", false},
+	} {
+		u := &url.URL{Scheme: "https", Host: tc.host, Path: "/ek.crt"}
+		var fetcher HTTPIssuerFetcher
+		if got := fetcher.checkURL(u) == nil; got != tc.want {
+			t.Errorf("checkURL(%v) allowed = %v, want %v", u, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package wellknown
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/google/go-eventlog/proto/state"
+)
+
+// GCE vTPM certificate extensions and attributes.
+var (
+	// cloudComputeInstanceIdentifierOID is the extension identifying the GCE
+	// instance of an EK or AK certificate.
+	cloudComputeInstanceIdentifierOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 1, 21}
+	subjectAltNameOID                 = asn1.ObjectIdentifier{2, 5, 29, 17}
+	// TPM attributes of the subject alternative name directoryName, from the
+	// TCG EK Credential Profile, Section 3.1.2.
+	tpmManufacturerOID = asn1.ObjectIdentifier{2, 23, 133, 2, 1}
+	tpmModelOID        = asn1.ObjectIdentifier{2, 23, 133, 2, 2}
+	tpmVersionOID      = asn1.ObjectIdentifier{2, 23, 133, 2, 3}
+)
+
+// GceTPMManufacturer is the TPM manufacturer attribute of GCE vTPM
+// certificates, "GOOG" as a hex vendor ID.
+const GceTPMManufacturer = "id:474F4F47"
+
+// gceSecurityProperties and gceInstanceInfo are the ASN.1 structure of the
+// GCE instance identifier extension.
+type gceSecurityProperties struct {
+	SecurityVersion int64 `asn1:"explicit,tag:0,optional"`
+	IsProduction    bool  `asn1:"explicit,tag:1,optional"`
+}
+
+type gceInstanceInfo struct {
+	Zone               string `asn1:"utf8"`
+	ProjectNumber      int64
+	ProjectID          string `asn1:"utf8"`
+	InstanceID         int64
+	InstanceName       string                `asn1:"utf8"`
+	SecurityProperties gceSecurityProperties `asn1:"explicit,optional"`
+}
+
+// GceKeyIdentity is the identity certified by a verified GCE EK or AK
+// certificate.
+type GceKeyIdentity struct {
+	// Instance identifies the GCE instance holding the key.
+	Instance *pb.GCEInstanceInfo
+	// SecurityVersion is the security version of the instance's vTPM.
+	SecurityVersion int64
+	// IsProduction is false for certificates of non-production instances.
+	IsProduction bool
+	// TPMManufacturer, TPMModel, and TPMVersion are the TPM attributes of the
+	// subject alternative name, if present.
+	TPMManufacturer string
+	TPMModel        string
+	TPMVersion      string
+	// Chain is the verified certificate chain, leaf first.
+	Chain []*x509.Certificate
+}
+
+// VerifyGceKeyCertificate verifies a GCE EK or AK certificate against
+// GceEKRoots, using GceEKIntermediates and the given intermediates to build
+// the chain. The certificate must identify a GCE instance, and a TPM
+// manufacturer in its subject alternative name must be GceTPMManufacturer.
+//
+// GceEKRoots and GceEKIntermediates are empty by default. Callers must
+// populate them with the GCE EK CA certificates before verifying.
+func VerifyGceKeyCertificate(cert *x509.Certificate, intermediates ...*x509.Certificate) (*GceKeyIdentity, error) {
+	return verifyGceKeyCertificate(cert, time.Time{}, intermediates)
+}
+
+// verifyGceKeyCertificate verifies the certificate at the given time, or at
+// the current time if now is zero.
+func verifyGceKeyCertificate(cert *x509.Certificate, now time.Time, intermediates []*x509.Certificate) (*GceKeyIdentity, error) {
+	if len(GceEKRoots) == 0 {
+		return nil, errors.New("no GCE EK roots configured")
+	}
+	identity := &GceKeyIdentity{}
+	if err := parseTPMAttributes(cert, identity); err != nil {
+		return nil, err
+	}
+	if identity.TPMManufacturer != "" && identity.TPMManufacturer != GceTPMManufacturer {
+		return nil, fmt.Errorf("got TPM manufacturer %q, want %q", identity.TPMManufacturer, GceTPMManufacturer)
+	}
+	if err := parseInstanceInfo(cert, identity); err != nil {
+		return nil, err
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   now,
+		// EK and AK certificates often have no extended key usage.
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, root := range GceEKRoots {
+		opts.Roots.AddCert(root)
+	}
+	// Appending intermediates to GceEKIntermediates could write into its
+	// spare capacity, which concurrent callers share.
+	for _, intermediate := range GceEKIntermediates {
+		opts.Intermediates.AddCert(intermediate)
+	}
+	for _, intermediate := range intermediates {
+		opts.Intermediates.AddCert(intermediate)
+	}
+	// The subject alternative name only holds a directoryName, which the x509
+	// package does not handle, so it is checked above instead.
+	leaf := *cert
+	leaf.UnhandledCriticalExtensions = nil
+	for _, oid := range cert.UnhandledCriticalExtensions {
+		if !oid.Equal(subjectAltNameOID) {
+			leaf.UnhandledCriticalExtensions = append(leaf.UnhandledCriticalExtensions, oid)
+		}
+	}
+	chains, err := leaf.Verify(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify GCE key certificate: %v", err)
+	}
+	identity.Chain = append([]*x509.Certificate{cert}, chains[0][1:]...)
+	return identity, nil
+}
+
+// parseTPMAttributes sets the TPM attributes of the identity from the
+// directoryName of the certificate's subject alternative name.
+func parseTPMAttributes(cert *x509.Certificate, identity *GceKeyIdentity) error {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(subjectAltNameOID) {
+			continue
+		}
+		var names []asn1.RawValue
+		if _, err := asn1.Unmarshal(ext.Value, &names); err != nil {
+			return fmt.Errorf("invalid subject alternative name: %v", err)
+		}
+		for _, name := range names {
+			if name.Class != asn1.ClassContextSpecific || name.Tag != 4 {
+				continue
+			}
+			var rdns pkix.RDNSequence
+			if _, err := asn1.Unmarshal(name.Bytes, &rdns); err != nil {
+				return fmt.Errorf("invalid subject alternative name directoryName: %v", err)
+			}
+			for _, rdn := range rdns {
+				for _, attr := range rdn {
+					value, ok := attr.Value.(string)
+					if !ok {
+						continue
+					}
+					switch {
+					case attr.Type.Equal(tpmManufacturerOID):
+						identity.TPMManufacturer = value
+					case attr.Type.Equal(tpmModelOID):
+						identity.TPMModel = value
+					case attr.Type.Equal(tpmVersionOID):
+						identity.TPMVersion = value
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseInstanceInfo sets the instance of the identity from the certificate's
+// GCE instance identifier extension.
+func parseInstanceInfo(cert *x509.Certificate, identity *GceKeyIdentity) error {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(cloudComputeInstanceIdentifierOID) {
+			continue
+		}
+		var info gceInstanceInfo
+		if _, err := asn1.Unmarshal(ext.Value, &info); err != nil {
+			return fmt.Errorf("invalid GCE instance identifier: %v", err)
+		}
+		if info.ProjectNumber < 0 || info.InstanceID < 0 {
+			return errors.New("invalid GCE instance identifier: negative project number or instance ID")
+		}
+		identity.Instance = &pb.GCEInstanceInfo{
+			Zone:          info.Zone,
+			ProjectId:     info.ProjectID,
+			ProjectNumber: uint64(info.ProjectNumber),
+			InstanceName:  info.InstanceName,
+			InstanceId:    uint64(info.InstanceID),
+		}
+		identity.SecurityVersion = info.SecurityProperties.SecurityVersion
+		identity.IsProduction = info.SecurityProperties.IsProduction
+		return nil
+	}
+	return errors.New("certificate has no GCE instance identifier")
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package wellknown
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/google/go-eventlog/proto/state"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func tpmSAN(t *testing.T, manufacturer string) pkix.Extension {
+	t.Helper()
+	name, err := asn1.Marshal(pkix.RDNSequence{
+		{{Type: tpmManufacturerOID, Value: manufacturer}},
+		{{Type: tpmModelOID, Value: "vTPM"}},
+		{{Type: tpmVersionOID, Value: "id:20160511"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	san, err := asn1.Marshal([]asn1.RawValue{{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: name}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkix.Extension{Id: subjectAltNameOID, Critical: true, Value: san}
+}
+
+func instanceExtension(t *testing.T) pkix.Extension {
+	t.Helper()
+	value, err := asn1.Marshal(gceInstanceInfo{
+		Zone:               "us-central1-a",
+		ProjectNumber:      123456789,
+		ProjectID:          "my-project",
+		InstanceID:         987654321,
+		InstanceName:       "my-instance",
+		SecurityProperties: gceSecurityProperties{SecurityVersion: 2, IsProduction: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkix.Extension{Id: cloudComputeInstanceIdentifierOID, Value: value}
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newCert(t *testing.T, name string, parent *testCA, extensions ...pkix.Extension) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  len(extensions) == 0,
+		KeyUsage:              x509.KeyUsageCertSign,
+		ExtraExtensions:       extensions,
+	}
+	return signCert(t, template, key, parent)
+}
+
+// signCert signs the template with the parent, or self-signs it if parent is
+// nil.
+func signCert(t *testing.T, template *x509.Certificate, key *ecdsa.PrivateKey, parent *testCA) *testCA {
+	t.Helper()
+	signer := &testCA{cert: template, key: key}
+	if parent != nil {
+		signer = parent
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer.cert, key.Public(), signer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+func TestVerifyGceKeyCertificate(t *testing.T) {
+	root := newCert(t, "EK root", nil)
+	intermediate := newCert(t, "EK intermediate", root)
+	leaf := newCert(t, "EK", intermediate, tpmSAN(t, GceTPMManufacturer), instanceExtension(t)).cert
+	otherVendor := newCert(t, "EK", intermediate, tpmSAN(t, "id:414D4400"), instanceExtension(t)).cert
+	noInstance := newCert(t, "EK", intermediate, tpmSAN(t, GceTPMManufacturer)).cert
+	untrusted := newCert(t, "EK", newCert(t, "other root", nil), instanceExtension(t)).cert
+
+	if _, err := VerifyGceKeyCertificate(leaf, intermediate.cert); err == nil {
+		t.Errorf("VerifyGceKeyCertificate() without roots: got nil, want error")
+	}
+	defer func(roots []*x509.Certificate) { GceEKRoots = roots }(GceEKRoots)
+	GceEKRoots = []*x509.Certificate{root.cert}
+
+	identity, err := VerifyGceKeyCertificate(leaf, intermediate.cert)
+	if err != nil {
+		t.Fatalf("VerifyGceKeyCertificate(): %v", err)
+	}
+	want := &pb.GCEInstanceInfo{
+		Zone:          "us-central1-a",
+		ProjectId:     "my-project",
+		ProjectNumber: 123456789,
+		InstanceName:  "my-instance",
+		InstanceId:    987654321,
+	}
+	if diff := cmp.Diff(want, identity.Instance, protocmp.Transform()); diff != "" {
+		t.Errorf("VerifyGceKeyCertificate() instance returned unexpected diff (-want +got):\n%s", diff)
+	}
+	if !identity.IsProduction || identity.SecurityVersion != 2 {
+		t.Errorf("VerifyGceKeyCertificate() = production %v, security version %d, want true, 2", identity.IsProduction, identity.SecurityVersion)
+	}
+	if identity.TPMManufacturer != GceTPMManufacturer || identity.TPMModel != "vTPM" || identity.TPMVersion != "id:20160511" {
+		t.Errorf("VerifyGceKeyCertificate() = TPM %q %q %q, want GCE vTPM attributes", identity.TPMManufacturer, identity.TPMModel, identity.TPMVersion)
+	}
+	if len(identity.Chain) != 3 || identity.Chain[0] != leaf {
+		t.Errorf("VerifyGceKeyCertificate() returned a chain of %d certificates, want leaf, intermediate, and root", len(identity.Chain))
+	}
+
+	for name, cert := range map[string]*x509.Certificate{
+		"other manufacturer": otherVendor,
+		"no instance":        noInstance,
+		"untrusted":          untrusted,
+	} {
+		if _, err := VerifyGceKeyCertificate(cert, intermediate.cert); err == nil {
+			t.Errorf("VerifyGceKeyCertificate(%s): got nil, want error", name)
+		}
+	}
+	if _, err := VerifyGceKeyCertificate(leaf); err == nil {
+		t.Errorf("VerifyGceKeyCertificate() without intermediates: got nil, want error")
+	}
+
+	// Intermediates given by the caller must not be added to the spare
+	// capacity of GceEKIntermediates.
+	defer func(intermediates []*x509.Certificate) { GceEKIntermediates = intermediates }(GceEKIntermediates)
+	GceEKIntermediates = make([]*x509.Certificate, 0, 1)
+	if _, err := VerifyGceKeyCertificate(leaf, intermediate.cert); err != nil {
+		t.Fatalf("VerifyGceKeyCertificate(): %v", err)
+	}
+	if spare := GceEKIntermediates[:1]; spare[0] != nil {
+		t.Errorf("VerifyGceKeyCertificate() wrote %q into the spare capacity of GceEKIntermediates", spare[0].Subject)
+	}
+}
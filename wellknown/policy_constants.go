@@ -43,6 +43,8 @@ const (
 
 // EventTagLoadedImageHex used with type "EV_EVENT_TAG".
 // This corresponds to a TLV struct of type LOAD_OPTIONS_EVENT_TAG_ID (0x8F3B22ED, reversed endian), length 0x1a (26), value `LOADED_IMAGE::LoadOptions\n`.
+// To recognize the Linux EFI stub's tagged events in either form they are
+// measured in, use tcg.ParseLinuxEventTag.
 const EventTagLoadedImageHex = "ed223b8f1a0000004c4f414445445f494d4147453a3a4c6f61644f7074696f6e7300"
 
 var (
@@ -84,7 +86,8 @@ var (
 	RevokedCiscoCert []byte
 )
 
-// Certificates corresponding to the known CA certs for GCE.
+// Certificates corresponding to the known CA certs for GCE, used by
+// VerifyGceKeyCertificate.
 var (
 	GceEKRoots         []*x509.Certificate
 	GceEKIntermediates []*x509.Certificate